@@ -0,0 +1,140 @@
+package achievements
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+
+	"gorm.io/gorm"
+)
+
+// notifyEarned je post-award helper; testovi mogu override-ovati.
+var notifyEarned = func(db *gorm.DB, korisnikID uint, rule Rule) {
+	notifications.NotifyAchievementEarned(db, korisnikID, rule.Kod, rule.Naziv, rule.Opis)
+}
+
+// Evaluate proverava pravila koja slušaju ev i dodeljuje nove bedževe (sa obaveštenjem).
+// Vraća kodove novo osvojenih bedževa.
+func Evaluate(db *gorm.DB, korisnikID uint, ev Event) ([]string, error) {
+	if korisnikID == 0 {
+		return nil, nil
+	}
+	owned, err := ownedKodovi(db, korisnikID)
+	if err != nil {
+		return nil, err
+	}
+	var earned []string
+	for _, rule := range rules {
+		if !rule.listensTo(ev) {
+			continue
+		}
+		if _, ok := owned[rule.Kod]; ok {
+			continue
+		}
+		awarded, err := evaluateRule(db, korisnikID, rule, false)
+		if err != nil {
+			return earned, err
+		}
+		if awarded {
+			earned = append(earned, rule.Kod)
+			notifyEarned(db, korisnikID, rule)
+		}
+	}
+	return earned, nil
+}
+
+// EvaluateBestEffort je za post-commit hook-ove: greška/panic ne sme da obori glavni tok.
+func EvaluateBestEffort(db *gorm.DB, korisnikIDs []uint, ev Event) {
+	seen := make(map[uint]struct{}, len(korisnikIDs))
+	for _, id := range korisnikIDs {
+		if id == 0 {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		func(korisnikID uint) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("achievements panic event=%s korisnikId=%d: %v", ev, korisnikID, r)
+				}
+			}()
+			if _, err := Evaluate(db, korisnikID, ev); err != nil {
+				log.Printf("achievements event=%s korisnikId=%d: %v", ev, korisnikID, err)
+			}
+		}(id)
+	}
+}
+
+// Backfill proverava sva pravila za sve aktivne korisnike i dodeljuje bedževe iz istorije
+// bez obaveštenja (novo pravilo ne sme da pošalje push celoj bazi). Idempotentno.
+func Backfill(db *gorm.DB) (int, error) {
+	var korisnikIDs []uint
+	if err := db.Model(&models.Korisnik{}).Where("role <> ?", "deleted").Order("id").Pluck("id", &korisnikIDs).Error; err != nil {
+		return 0, err
+	}
+	awardedTotal := 0
+	for _, korisnikID := range korisnikIDs {
+		owned, err := ownedKodovi(db, korisnikID)
+		if err != nil {
+			return awardedTotal, err
+		}
+		for _, rule := range rules {
+			if _, ok := owned[rule.Kod]; ok {
+				continue
+			}
+			awarded, err := evaluateRule(db, korisnikID, rule, true)
+			if err != nil {
+				return awardedTotal, fmt.Errorf("pravilo %s korisnik %d: %w", rule.Kod, korisnikID, err)
+			}
+			if awarded {
+				awardedTotal++
+			}
+		}
+	}
+	return awardedTotal, nil
+}
+
+func evaluateRule(db *gorm.DB, korisnikID uint, rule Rule, backfilled bool) (bool, error) {
+	ok, err := rule.Earned(db, korisnikID)
+	if err != nil || !ok {
+		return false, err
+	}
+	return award(db, korisnikID, rule.Kod, backfilled)
+}
+
+// award upisuje bedž; paralelni događaji za istog korisnika ne prave duplikat (unique indeks).
+func award(db *gorm.DB, korisnikID uint, kod string, backfilled bool) (bool, error) {
+	row := models.UserAchievement{
+		KorisnikID: korisnikID,
+		Kod:        kod,
+		EarnedAt:   time.Now().UTC(),
+		Backfilled: backfilled,
+	}
+	if err := db.Create(&row).Error; err != nil {
+		var count int64
+		if cErr := db.Model(&models.UserAchievement{}).
+			Where("korisnik_id = ? AND kod = ?", korisnikID, kod).
+			Count(&count).Error; cErr == nil && count > 0 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func ownedKodovi(db *gorm.DB, korisnikID uint) (map[string]struct{}, error) {
+	var kodovi []string
+	if err := db.Model(&models.UserAchievement{}).Where("korisnik_id = ?", korisnikID).Pluck("kod", &kodovi).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string]struct{}, len(kodovi))
+	for _, k := range kodovi {
+		out[k] = struct{}{}
+	}
+	return out, nil
+}
@@ -0,0 +1,149 @@
+package achievements
+
+import (
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func testAchievementsDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "achievements")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.Korisnik{},
+		&models.Akcija{},
+		&models.Prijava{},
+		&models.PeakAscent{},
+		&models.TrackedActivity{},
+		&models.UserDailySteps{},
+		&models.UserActivitySettings{},
+		&models.Post{},
+		&models.UserAchievement{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func captureNotifications(t *testing.T) *[]string {
+	t.Helper()
+	var got []string
+	prev := notifyEarned
+	notifyEarned = func(_ *gorm.DB, _ uint, rule Rule) { got = append(got, rule.Kod) }
+	t.Cleanup(func() { notifyEarned = prev })
+	return &got
+}
+
+func TestEvaluate_FirstViaFerrataAwardedOnceWithNotification(t *testing.T) {
+	db := testAchievementsDB(t)
+	notified := captureNotifications(t)
+	k := models.Korisnik{Username: "penjac", Role: "clan"}
+	db.Create(&k)
+	ak := models.Akcija{Naziv: "Ferata", TipAkcije: "via_ferrata", IsCompleted: true, Datum: time.Now()}
+	db.Create(&ak)
+	db.Create(&models.Prijava{AkcijaID: ak.ID, KorisnikID: k.ID, Status: "popeo se"})
+
+	earned, err := Evaluate(db, k.ID, EventActionFinished)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsKod(earned, "prva_via_ferrata") || !containsKod(earned, "prvi_vrh") {
+		t.Fatalf("expected ferrata + first summit badges, got %v", earned)
+	}
+	again, err := Evaluate(db, k.ID, EventActionFinished)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("expected no duplicate awards, got %v", again)
+	}
+	if len(*notified) != len(earned) {
+		t.Fatalf("expected one notification per badge, got %v", *notified)
+	}
+}
+
+func TestEvaluate_OnlyRulesListeningToEvent(t *testing.T) {
+	db := testAchievementsDB(t)
+	captureNotifications(t)
+	k := models.Korisnik{Username: "pisac", Role: "clan"}
+	db.Create(&k)
+	db.Create(&models.Post{UserID: k.ID, AuthorID: k.ID, Content: "Zdravo"})
+
+	earned, _ := Evaluate(db, k.ID, EventStepsSynced)
+	if len(earned) != 0 {
+		t.Fatalf("steps event must not award post badge, got %v", earned)
+	}
+	earned, _ = Evaluate(db, k.ID, EventPostCreated)
+	if !containsKod(earned, "prva_objava") {
+		t.Fatalf("expected prva_objava, got %v", earned)
+	}
+}
+
+func TestLongestStepGoalStreak_UsesPersonalGoal(t *testing.T) {
+	db := testAchievementsDB(t)
+	k := models.Korisnik{Username: "setac", Role: "clan"}
+	db.Create(&k)
+	db.Create(&models.UserActivitySettings{UserID: k.ID, DailyStepGoal: 5000})
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 31; i++ {
+		steps := 6000
+		if i == 10 {
+			steps = 4000 // prekid niza
+		}
+		db.Create(&models.UserDailySteps{UserID: k.ID, Date: start.AddDate(0, 0, i), Steps: steps})
+	}
+	streak, err := longestStepGoalStreak(db, k.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streak != 20 {
+		t.Fatalf("expected streak 20, got %d", streak)
+	}
+}
+
+func TestBackfill_AwardsFromHistoryWithoutNotifications(t *testing.T) {
+	db := testAchievementsDB(t)
+	notified := captureNotifications(t)
+	k := models.Korisnik{Username: "veteran", Role: "clan", UkupnoMetaraUsponaKorisnik: 9000}
+	db.Create(&k)
+	db.Create(&models.TrackedActivity{UserID: k.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: time.Now(), ElevationGainM: 1200})
+	db.Create(&models.TrackedActivity{UserID: k.ID, Status: models.TrackedActivityStatusDiscarded, StartedAt: time.Now(), ElevationGainM: 50000})
+
+	n, err := Backfill(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected exactly one backfilled badge, got %d", n)
+	}
+	var row models.UserAchievement
+	if err := db.Where("korisnik_id = ? AND kod = ?", k.ID, "uspon_10000m").First(&row).Error; err != nil {
+		t.Fatalf("expected uspon_10000m: %v", err)
+	}
+	if !row.Backfilled {
+		t.Fatal("expected backfilled flag")
+	}
+	if len(*notified) != 0 {
+		t.Fatalf("backfill must not notify, got %v", *notified)
+	}
+	if n, _ := Backfill(db); n != 0 {
+		t.Fatalf("expected idempotent backfill, got %d", n)
+	}
+}
+
+func containsKod(list []string, kod string) bool {
+	for _, k := range list {
+		if k == kod {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+// Package achievements sadrži pravila za bedževe i njihovo dodeljivanje.
+//
+// Pravilo računa uslov iz istorije (prijave, dnevnik uspona, GPS sesije, koraci, objave),
+// pa je isto pravilo upotrebljivo i za događaje i za backfill kada se doda novo pravilo.
+package achievements
+
+import (
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Event je domen događaj posle kojeg se proveravaju pravila.
+type Event string
+
+const (
+	EventActionFinished   Event = "action_finished"
+	EventActivityFinished Event = "activity_finished"
+	EventStepsSynced      Event = "steps_synced"
+	EventPostCreated      Event = "post_created"
+)
+
+// Rule opisuje jedan bedž. Earned mora biti deterministički iz baze (bez side-effecta).
+type Rule struct {
+	Kod    string
+	Naziv  string
+	Opis   string
+	Events []Event
+	Earned func(db *gorm.DB, korisnikID uint) (bool, error)
+}
+
+const stepStreakDays = 30
+
+var rules = []Rule{
+	{
+		Kod:    "prvi_vrh",
+		Naziv:  "Prvi vrh",
+		Opis:   "Prvi uspešan uspon na akciji ili vrh iz kataloga.",
+		Events: []Event{EventActionFinished, EventActivityFinished},
+		Earned: func(db *gorm.DB, korisnikID uint) (bool, error) {
+			n, err := countSummitedPrijave(db, korisnikID, "")
+			if err != nil || n > 0 {
+				return n > 0, err
+			}
+			var ascents int64
+			err = db.Model(&models.PeakAscent{}).Where("korisnik_id = ?", korisnikID).Count(&ascents).Error
+			return ascents > 0, err
+		},
+	},
+	{
+		Kod:    "prva_via_ferrata",
+		Naziv:  "Prva via ferrata",
+		Opis:   "Uspešno završena prva via ferrata akcija.",
+		Events: []Event{EventActionFinished},
+		Earned: func(db *gorm.DB, korisnikID uint) (bool, error) {
+			n, err := countSummitedPrijave(db, korisnikID, "akcije.tip_akcije = 'via_ferrata'")
+			return n >= 1, err
+		},
+	},
+	{
+		Kod:    "zimski_usponi_10",
+		Naziv:  "Zimski planinar",
+		Opis:   "10 zimskih uspona.",
+		Events: []Event{EventActionFinished, EventActivityFinished},
+		Earned: func(db *gorm.DB, korisnikID uint) (bool, error) {
+			n, err := countSummitedPrijave(db, korisnikID, "akcije.zimski_uspon = ?", true)
+			if err != nil {
+				return false, err
+			}
+			// Usponi sa akcija su već u prijavama; ovde samo GPS i ručni unosi.
+			var other int64
+			if err := db.Model(&models.PeakAscent{}).
+				Where("korisnik_id = ? AND zimski_uspon = ? AND izvor <> ?", korisnikID, true, models.PeakAscentIzvorAkcija).
+				Count(&other).Error; err != nil {
+				return false, err
+			}
+			return n+other >= 10, nil
+		},
+	},
+	{
+		Kod:    "vrhovi_10",
+		Naziv:  "Deset vrhova",
+		Opis:   "10 različitih vrhova u dnevniku uspona.",
+		Events: []Event{EventActionFinished, EventActivityFinished},
+		Earned: func(db *gorm.DB, korisnikID uint) (bool, error) {
+			var n int64
+			err := db.Model(&models.PeakAscent{}).
+				Where("korisnik_id = ?", korisnikID).
+				Distinct("peak_id").
+				Count(&n).Error
+			return n >= 10, err
+		},
+	},
+	{
+		Kod:    "uspon_10000m",
+		Naziv:  "10.000 metara uspona",
+		Opis:   "Ukupno 10.000 m visinske razlike sa akcija i GPS aktivnosti.",
+		Events: []Event{EventActionFinished, EventActivityFinished},
+		Earned: func(db *gorm.DB, korisnikID uint) (bool, error) {
+			total, err := cumulativeElevationM(db, korisnikID)
+			return total >= 10000, err
+		},
+	},
+	{
+		Kod:    "koraci_niz_30",
+		Naziv:  "30 dana u nizu",
+		Opis:   "Dnevni cilj koraka ostvaren 30 dana zaredom.",
+		Events: []Event{EventStepsSynced},
+		Earned: func(db *gorm.DB, korisnikID uint) (bool, error) {
+			streak, err := longestStepGoalStreak(db, korisnikID)
+			return streak >= stepStreakDays, err
+		},
+	},
+	{
+		Kod:    "prva_objava",
+		Naziv:  "Prva objava",
+		Opis:   "Prva objava na feed-u.",
+		Events: []Event{EventPostCreated},
+		Earned: func(db *gorm.DB, korisnikID uint) (bool, error) {
+			var n int64
+			err := db.Model(&models.Post{}).Where("user_id = ?", korisnikID).Count(&n).Error
+			return n > 0, err
+		},
+	},
+}
+
+// Rules vraća sva pravila (redosled = redosled prikaza).
+func Rules() []Rule {
+	return rules
+}
+
+// RuleByKod vraća pravilo po kodu.
+func RuleByKod(kod string) (Rule, bool) {
+	for _, r := range rules {
+		if r.Kod == kod {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (r Rule) listensTo(ev Event) bool {
+	for _, e := range r.Events {
+		if e == ev {
+			return true
+		}
+	}
+	return false
+}
+
+// countSummitedPrijave broji „popeo se“ prijave na završenim akcijama; extra je dodatni uslov nad akcije.
+func countSummitedPrijave(db *gorm.DB, korisnikID uint, extra string, args ...interface{}) (int64, error) {
+	q := db.Model(&models.Prijava{}).
+		Joins("JOIN akcije ON akcije.id = prijave.akcija_id").
+		Where("prijave.korisnik_id = ? AND prijave.status = ? AND akcije.is_completed = ?", korisnikID, "popeo se", true)
+	if extra != "" {
+		q = q.Where(extra, args...)
+	}
+	var n int64
+	err := q.Count(&n).Error
+	return n, err
+}
+
+func cumulativeElevationM(db *gorm.DB, korisnikID uint) (float64, error) {
+	var korisnik models.Korisnik
+	if err := db.Select("id", "ukupno_metara_uspona_korisnik").First(&korisnik, korisnikID).Error; err != nil {
+		return 0, err
+	}
+	var tracked float64
+	if err := db.Model(&models.TrackedActivity{}).
+		Where("user_id = ? AND status = ? AND flagged = ?", korisnikID, models.TrackedActivityStatusCompleted, false).
+		Select("COALESCE(SUM(elevation_gain_m), 0)").
+		Scan(&tracked).Error; err != nil {
+		return 0, err
+	}
+	return float64(korisnik.UkupnoMetaraUsponaKorisnik) + tracked, nil
+}
+
+// longestStepGoalStreak vraća najduži niz uzastopnih dana sa ostvarenim dnevnim ciljem koraka.
+func longestStepGoalStreak(db *gorm.DB, korisnikID uint) (int, error) {
+	goal := 10000
+	var settings models.UserActivitySettings
+	if err := db.Where("user_id = ?", korisnikID).First(&settings).Error; err == nil && settings.DailyStepGoal > 0 {
+		goal = settings.DailyStepGoal
+	}
+	var days []models.UserDailySteps
+	if err := db.Select("date").
+		Where("user_id = ? AND steps >= ?", korisnikID, goal).
+		Order("date ASC").
+		Find(&days).Error; err != nil {
+		return 0, err
+	}
+	best, cur := 0, 0
+	var prev time.Time
+	for i, d := range days {
+		day := time.Date(d.Date.Year(), d.Date.Month(), d.Date.Day(), 0, 0, 0, 0, time.UTC)
+		if i > 0 && day.Equal(prev.AddDate(0, 0, 1)) {
+			cur++
+		} else {
+			cur = 1
+		}
+		if cur > best {
+			best = cur
+		}
+		prev = day
+	}
+	return best, nil
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"beleg-app/backend/internal/config"
 	"beleg-app/backend/internal/database"
+	"beleg-app/backend/internal/deltasync"
 	"beleg-app/backend/internal/handlers"
 	"beleg-app/backend/internal/jobs"
 	"beleg-app/backend/internal/models"
@@ -49,6 +50,14 @@ func Run(registerRoutes RouteRegistrar) {
 	router.Static("/uploads", "./uploads")
 	go jobs.RunCloudinaryPendingDeletesJob(db)
 	go jobs.RunSubscriptionHoldJob(db)
+	go jobs.RunAchievementBackfillJob(db)
+	go jobs.RunChallengeFinalizeJob(db)
+	go jobs.RunSyncTombstonePruneJob(db)
+	go jobs.RunIdempotencyKeyPruneJob(db)
+	go jobs.RunActionReminderJob(db)
+	go jobs.RunWeatherAlertJob(db)
+	go jobs.RunRescheduleConfirmationJob(db)
+	go jobs.RunScheduledPublishJob(db)
 	mustRunServer(router)
 }
 
@@ -72,8 +81,8 @@ func configureHTTPMiddleware(r *gin.Engine) {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     origins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Club-Id"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Club-Id", "Idempotency-Key"},
+		ExposeHeaders:    []string{"Content-Length", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -94,6 +103,9 @@ func mustOpenDatabase() *gorm.DB {
 		log.Fatal("Ne mogu da se povežem sa bazom:", err)
 	}
 	log.Println("Uspješno povezan sa bazom!")
+	if err := deltasync.RegisterTombstoneCallbacks(db); err != nil {
+		log.Fatal("Ne mogu da registrujem sync tombstone callback:", err)
+	}
 	return db
 }
 
@@ -146,8 +158,36 @@ func migrateAndSeed(db *gorm.DB) {
 		&models.UserDailySteps{},
 		&models.TrackedActivity{},
 		&models.TrackedActivityPoint{},
+		&models.TrackedActivityPointBatch{},
 		&models.PushToken{},
 		&models.AuthIdentity{},
+		&models.PeakAscent{},
+		&models.PeakList{},
+		&models.PeakListItem{},
+		&models.UserAchievement{},
+		&models.ClubChallenge{},
+		&models.ClubChallengeTeam{},
+		&models.ClubChallengeParticipant{},
+		&models.ClubChallengeResult{},
+		&models.GeocodeCache{},
+		&models.OfflineBundleVersion{},
+		&models.SyncTombstone{},
+		&models.IdempotencyKey{},
+		&models.AkcijaRuta{},
+		&models.WeatherCache{},
+		&models.AkcijaPomeranje{},
+		&models.AkcijaVerzija{},
+		&models.AkcijaVodic{},
+		&models.AkcijaForma{},
+		&models.AkcijaUslovi{},
+		&models.AkcijaUslovIzuzetak{},
+		&models.KorisnikSertifikat{},
+		&models.AkcijaCenovnik{},
+		&models.AkcijaPromoKod{},
+		&models.Placanje{},
+		&models.PlacanjeDogadjaj{},
+		&models.AkcijaPolitikaPovracaja{},
+		&models.ObavezaPovracaja{},
 	)
 	if err != nil {
 		log.Fatal("Greška pri automigraciji tabela:", err)
@@ -164,6 +204,14 @@ func migrateAndSeed(db *gorm.DB) {
 		}
 	}
 
+	if err := database.PostAutoMigrateBackfillSyncTimestamps(db); err != nil {
+		log.Fatal("Greška pri popunjavanju updated_at za sync:", err)
+	}
+
+	if err := database.PostAutoMigrateCreateSearchIndexes(db); err != nil {
+		log.Printf("UPOZORENJE: indeksi pretrage nisu kreirani: %v", err)
+	}
+
 	log.Println("Tabele su migrirane (akcije, prijave, korisnici, transakcije, zadaci, zadatak_korisnici, obavestenja, klubovi, auth_identities)")
 	seed.RunIfEmpty(db)
 }
@@ -0,0 +1,166 @@
+package challenges
+
+import (
+	"log"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+
+	"gorm.io/gorm"
+)
+
+// notifyFinished je post-commit helper; testovi mogu override-ovati.
+var notifyFinished = func(db *gorm.DB, ch *models.ClubChallenge, individual []Standing, teams []TeamStanding) {
+	notifications.NotifyChallengeFinished(db, ch, toResultRows(individual, teams))
+}
+
+// Finalize zamrzava rezultate završenog izazova i obaveštava učesnike.
+// Vraća false ako je izazov već zamrznut (paralelni job ili ponovljeni poziv).
+func Finalize(db *gorm.DB, ch *models.ClubChallenge) (bool, error) {
+	var individual []Standing
+	var teams []TeamStanding
+	finalized := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		res := tx.Model(&models.ClubChallenge{}).
+			Where("id = ? AND zavrseno_at IS NULL", ch.ID).
+			Update("zavrseno_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		var err error
+		individual, teams, err = Standings(tx, ch)
+		if err != nil {
+			return err
+		}
+		for _, s := range individual {
+			korisnikID := s.KorisnikID
+			if err := tx.Create(&models.ClubChallengeResult{
+				ChallengeID: ch.ID,
+				KorisnikID:  &korisnikID,
+				TeamID:      s.TeamID,
+				Vrednost:    s.Vrednost,
+				Rang:        s.Rang,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		for _, t := range teams {
+			teamID := t.TeamID
+			if err := tx.Create(&models.ClubChallengeResult{
+				ChallengeID: ch.ID,
+				TeamID:      &teamID,
+				Vrednost:    t.Vrednost,
+				Rang:        t.Rang,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		ch.ZavrsenoAt = &now
+		finalized = true
+		return nil
+	})
+	if err != nil || !finalized {
+		return false, err
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("challenge notify panic challengeId=%d: %v", ch.ID, r)
+			}
+		}()
+		notifyFinished(db, ch, individual, teams)
+	}()
+	return true, nil
+}
+
+// FinalizeDue zamrzava sve izazove kojima je prošao poslednji dan.
+func FinalizeDue(db *gorm.DB, now time.Time) (int, error) {
+	var due []models.ClubChallenge
+	if err := db.Where("zavrseno_at IS NULL AND kraj_datum < ?", dateUTC(now.UTC())).
+		Order("id ASC").
+		Find(&due).Error; err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := range due {
+		ok, err := Finalize(db, &due[i])
+		if err != nil {
+			return count, err
+		}
+		if ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// FrozenStandings čita zamrznute rezultate u istom obliku kao Standings.
+func FrozenStandings(db *gorm.DB, ch *models.ClubChallenge) ([]Standing, []TeamStanding, error) {
+	var rows []models.ClubChallengeResult
+	if err := db.Where("challenge_id = ?", ch.ID).Order("rang ASC, id ASC").Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	var teams []models.ClubChallengeTeam
+	if err := db.Where("challenge_id = ?", ch.ID).Find(&teams).Error; err != nil {
+		return nil, nil, err
+	}
+	teamByID := make(map[uint]models.ClubChallengeTeam, len(teams))
+	for _, t := range teams {
+		teamByID[t.ID] = t
+	}
+	individual := make([]Standing, 0, len(rows))
+	teamStandings := make([]TeamStanding, 0)
+	teamMembers := make(map[uint]int)
+	for _, r := range rows {
+		if r.KorisnikID != nil {
+			individual = append(individual, Standing{KorisnikID: *r.KorisnikID, TeamID: r.TeamID, Vrednost: r.Vrednost, Rang: r.Rang})
+			if r.TeamID != nil {
+				teamMembers[*r.TeamID]++
+			}
+		}
+	}
+	for _, r := range rows {
+		if r.KorisnikID == nil && r.TeamID != nil {
+			t := teamByID[*r.TeamID]
+			teamStandings = append(teamStandings, TeamStanding{
+				TeamID:       *r.TeamID,
+				Naziv:        t.Naziv,
+				KlubID:       t.KlubID,
+				BrojUcesnika: teamMembers[*r.TeamID],
+				Vrednost:     r.Vrednost,
+				Rang:         r.Rang,
+			})
+		}
+	}
+	return individual, teamStandings, nil
+}
+
+func toResultRows(individual []Standing, teams []TeamStanding) []notifications.ChallengeResultRow {
+	teamByID := make(map[uint]TeamStanding, len(teams))
+	for _, t := range teams {
+		teamByID[t.TeamID] = t
+	}
+	out := make([]notifications.ChallengeResultRow, 0, len(individual))
+	for _, s := range individual {
+		row := notifications.ChallengeResultRow{
+			KorisnikID:   s.KorisnikID,
+			Rang:         s.Rang,
+			BrojUcesnika: len(individual),
+			Vrednost:     s.Vrednost,
+		}
+		if s.TeamID != nil {
+			if t, ok := teamByID[*s.TeamID]; ok {
+				row.TimNaziv = t.Naziv
+				row.TimRang = t.Rang
+				row.BrojTimova = len(teams)
+			}
+		}
+		out = append(out, row)
+	}
+	return out
+}
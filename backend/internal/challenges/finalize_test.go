@@ -0,0 +1,157 @@
+package challenges
+
+import (
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func testChallengesDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "challenges")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.Korisnik{},
+		&models.Akcija{},
+		&models.Prijava{},
+		&models.PeakAscent{},
+		&models.TrackedActivity{},
+		&models.UserDailySteps{},
+		&models.ClubChallenge{},
+		&models.ClubChallengeTeam{},
+		&models.ClubChallengeParticipant{},
+		&models.ClubChallengeResult{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestStandings_StepsOnlyInsideWindowAndTiesShareRank(t *testing.T) {
+	db := testChallengesDB(t)
+	ch := models.ClubChallenge{Naziv: "Mart", Metrika: models.ChallengeMetrikaKoraci, Tip: models.ChallengeTipIndividualni,
+		Obuhvat: models.ChallengeObuhvatKlub, PocetakDatum: day(2025, 3, 1), KrajDatum: day(2025, 3, 31), CreatedByID: 1}
+	db.Create(&ch)
+	for _, id := range []uint{1, 2, 3} {
+		db.Create(&models.ClubChallengeParticipant{ChallengeID: ch.ID, KorisnikID: id})
+	}
+	db.Create(&models.UserDailySteps{UserID: 1, Date: day(2025, 3, 1), Steps: 5000})
+	db.Create(&models.UserDailySteps{UserID: 1, Date: day(2025, 3, 31), Steps: 5000})
+	db.Create(&models.UserDailySteps{UserID: 1, Date: day(2025, 4, 1), Steps: 90000})
+	db.Create(&models.UserDailySteps{UserID: 2, Date: day(2025, 3, 15), Steps: 10000})
+	db.Create(&models.UserDailySteps{UserID: 3, Date: day(2025, 2, 28), Steps: 20000})
+	// Korisnik koji nije prijavljen se ne rangira.
+	db.Create(&models.UserDailySteps{UserID: 4, Date: day(2025, 3, 10), Steps: 50000})
+
+	individual, teams, err := Standings(db, &ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(teams) != 0 || len(individual) != 3 {
+		t.Fatalf("expected 3 individual rows and no teams, got %d / %d", len(individual), len(teams))
+	}
+	if individual[0].Vrednost != 10000 || individual[0].Rang != 1 || individual[1].Rang != 1 {
+		t.Fatalf("expected tie at rank 1 with 10000 steps, got %+v", individual)
+	}
+	if individual[2].KorisnikID != 3 || individual[2].Vrednost != 0 || individual[2].Rang != 3 {
+		t.Fatalf("expected user 3 ranked 3rd with 0, got %+v", individual[2])
+	}
+}
+
+func TestFinalize_TeamKmFrozenOnceAndNotified(t *testing.T) {
+	db := testChallengesDB(t)
+	var notified []Standing
+	prev := notifyFinished
+	notifyFinished = func(_ *gorm.DB, _ *models.ClubChallenge, individual []Standing, _ []TeamStanding) {
+		notified = append(notified, individual...)
+	}
+	t.Cleanup(func() { notifyFinished = prev })
+
+	ch := models.ClubChallenge{Naziv: "Timski km", Metrika: models.ChallengeMetrikaKm, Tip: models.ChallengeTipTimski,
+		Obuhvat: models.ChallengeObuhvatKlub, PocetakDatum: day(2025, 5, 1), KrajDatum: day(2025, 5, 7), CreatedByID: 1}
+	db.Create(&ch)
+	plavi := models.ClubChallengeTeam{ChallengeID: ch.ID, Naziv: "Plavi"}
+	crveni := models.ClubChallengeTeam{ChallengeID: ch.ID, Naziv: "Crveni"}
+	db.Create(&plavi)
+	db.Create(&crveni)
+	db.Create(&models.ClubChallengeParticipant{ChallengeID: ch.ID, KorisnikID: 1, TeamID: &plavi.ID})
+	db.Create(&models.ClubChallengeParticipant{ChallengeID: ch.ID, KorisnikID: 2, TeamID: &plavi.ID})
+	db.Create(&models.ClubChallengeParticipant{ChallengeID: ch.ID, KorisnikID: 3, TeamID: &crveni.ID})
+
+	db.Create(&models.TrackedActivity{UserID: 1, Status: models.TrackedActivityStatusCompleted, StartedAt: day(2025, 5, 7).Add(20 * time.Hour), DistanceM: 8000})
+	db.Create(&models.TrackedActivity{UserID: 2, Status: models.TrackedActivityStatusCompleted, StartedAt: day(2025, 5, 2), DistanceM: 4000})
+	db.Create(&models.TrackedActivity{UserID: 2, Status: models.TrackedActivityStatusDiscarded, StartedAt: day(2025, 5, 3), DistanceM: 40000})
+	db.Create(&models.TrackedActivity{UserID: 3, Status: models.TrackedActivityStatusCompleted, StartedAt: day(2025, 5, 4), DistanceM: 10000})
+	db.Create(&models.TrackedActivity{UserID: 3, Status: models.TrackedActivityStatusCompleted, StartedAt: day(2025, 5, 8), DistanceM: 50000})
+
+	n, err := FinalizeDue(db, day(2025, 5, 7).Add(23*time.Hour))
+	if err != nil || n != 0 {
+		t.Fatalf("challenge must not finalize on its last day, got n=%d err=%v", n, err)
+	}
+	n, err = FinalizeDue(db, day(2025, 5, 8).Add(time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 finalized, got n=%d err=%v", n, err)
+	}
+	if len(notified) != 3 {
+		t.Fatalf("expected notification for 3 participants, got %d", len(notified))
+	}
+
+	// Aktivnost posle zamrzavanja ne menja rezultat.
+	db.Create(&models.TrackedActivity{UserID: 3, Status: models.TrackedActivityStatusCompleted, StartedAt: day(2025, 5, 5), DistanceM: 99000})
+	var stored models.ClubChallenge
+	db.First(&stored, ch.ID)
+	if stored.ZavrsenoAt == nil {
+		t.Fatal("expected zavrseno_at set")
+	}
+	individual, teams, err := FrozenStandings(db, &stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if individual[0].KorisnikID != 3 || individual[0].Vrednost != 10 {
+		t.Fatalf("expected frozen leader user 3 with 10 km, got %+v", individual[0])
+	}
+	if len(teams) != 2 || teams[0].TeamID != plavi.ID || teams[0].Vrednost != 12 || teams[0].BrojUcesnika != 2 {
+		t.Fatalf("expected Plavi first with 12 km, got %+v", teams)
+	}
+
+	if ok, err := Finalize(db, &stored); err != nil || ok {
+		t.Fatalf("second finalize must be a noop, got ok=%v err=%v", ok, err)
+	}
+	var results int64
+	db.Model(&models.ClubChallengeResult{}).Where("challenge_id = ?", ch.ID).Count(&results)
+	if results != 5 {
+		t.Fatalf("expected 3 individual + 2 team results, got %d", results)
+	}
+}
+
+func TestStandings_SummitsCountLogAndActionsWithoutPeak(t *testing.T) {
+	db := testChallengesDB(t)
+	ch := models.ClubChallenge{Naziv: "Vrhovi", Metrika: models.ChallengeMetrikaVrhovi, Tip: models.ChallengeTipIndividualni,
+		Obuhvat: models.ChallengeObuhvatKlubovi, PocetakDatum: day(2025, 6, 1), KrajDatum: day(2025, 6, 30), CreatedByID: 1}
+	db.Create(&ch)
+	db.Create(&models.ClubChallengeParticipant{ChallengeID: ch.ID, KorisnikID: 1})
+	db.Create(&models.PeakAscent{KorisnikID: 1, PeakID: 1, Datum: day(2025, 6, 10), Izvor: models.PeakAscentIzvorRucno})
+	db.Create(&models.PeakAscent{KorisnikID: 1, PeakID: 2, Datum: day(2025, 7, 1), Izvor: models.PeakAscentIzvorRucno})
+	bez := models.Akcija{Naziv: "Bez vrha", IsCompleted: true, Datum: day(2025, 6, 12)}
+	db.Create(&bez)
+	db.Create(&models.Prijava{AkcijaID: bez.ID, KorisnikID: 1, Status: "popeo se"})
+
+	individual, _, err := Standings(db, &ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if individual[0].Vrednost != 2 {
+		t.Fatalf("expected 2 summits (log + action without catalog peak), got %v", individual[0].Vrednost)
+	}
+}
@@ -0,0 +1,183 @@
+// Package challenges računa plasman vremenski ograničenih izazova i zamrzava rezultate po završetku.
+//
+// Vrednost se uvek računa iz istorije (koraci, GPS aktivnosti, dnevnik uspona, prijave) unutar
+// [PocetakDatum, KrajDatum], pa je „live“ plasman i konačni rezultat ista funkcija.
+package challenges
+
+import (
+	"sort"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Standing je plasman jednog učesnika.
+type Standing struct {
+	KorisnikID uint    `json:"korisnikId"`
+	TeamID     *uint   `json:"teamId,omitempty"`
+	Vrednost   float64 `json:"vrednost"`
+	Rang       int     `json:"rang"`
+}
+
+// TeamStanding je plasman tima; vrednost tima je zbir vrednosti članova.
+type TeamStanding struct {
+	TeamID       uint    `json:"teamId"`
+	Naziv        string  `json:"naziv"`
+	KlubID       *uint   `json:"klubId,omitempty"`
+	BrojUcesnika int     `json:"brojUcesnika"`
+	Vrednost     float64 `json:"vrednost"`
+	Rang         int     `json:"rang"`
+}
+
+// ValidMetrika proverava da li je metrika podržana.
+func ValidMetrika(m string) bool {
+	switch m {
+	case models.ChallengeMetrikaKoraci, models.ChallengeMetrikaKm, models.ChallengeMetrikaUspon, models.ChallengeMetrikaVrhovi:
+		return true
+	}
+	return false
+}
+
+// Window vraća [od, do) u UTC za vremenske žigove (KrajDatum je uključiv ceo dan).
+func Window(ch *models.ClubChallenge) (time.Time, time.Time) {
+	from := dateUTC(ch.PocetakDatum)
+	return from, dateUTC(ch.KrajDatum).AddDate(0, 0, 1)
+}
+
+// HasEnded — izazov je završen kada je prošao ceo dan KrajDatum (UTC).
+func HasEnded(ch *models.ClubChallenge, now time.Time) bool {
+	_, end := Window(ch)
+	return !now.Before(end)
+}
+
+// Standings računa trenutni plasman učesnika i timova (timovi su prazni za individualne izazove).
+// Jednake vrednosti dele rang (1, 1, 3).
+func Standings(db *gorm.DB, ch *models.ClubChallenge) ([]Standing, []TeamStanding, error) {
+	var participants []models.ClubChallengeParticipant
+	if err := db.Where("challenge_id = ?", ch.ID).Order("id ASC").Find(&participants).Error; err != nil {
+		return nil, nil, err
+	}
+	ids := make([]uint, 0, len(participants))
+	for _, p := range participants {
+		ids = append(ids, p.KorisnikID)
+	}
+	values, err := participantValues(db, ch, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	individual := make([]Standing, 0, len(participants))
+	for _, p := range participants {
+		individual = append(individual, Standing{KorisnikID: p.KorisnikID, TeamID: p.TeamID, Vrednost: values[p.KorisnikID]})
+	}
+	sort.SliceStable(individual, func(i, j int) bool {
+		return individual[i].Vrednost > individual[j].Vrednost
+	})
+	for i := range individual {
+		individual[i].Rang = i + 1
+		if i > 0 && individual[i].Vrednost == individual[i-1].Vrednost {
+			individual[i].Rang = individual[i-1].Rang
+		}
+	}
+
+	if ch.Tip != models.ChallengeTipTimski {
+		return individual, []TeamStanding{}, nil
+	}
+	var teams []models.ClubChallengeTeam
+	if err := db.Where("challenge_id = ?", ch.ID).Order("id ASC").Find(&teams).Error; err != nil {
+		return nil, nil, err
+	}
+	byTeam := make(map[uint]*TeamStanding, len(teams))
+	teamStandings := make([]TeamStanding, 0, len(teams))
+	for _, t := range teams {
+		teamStandings = append(teamStandings, TeamStanding{TeamID: t.ID, Naziv: t.Naziv, KlubID: t.KlubID})
+	}
+	for i := range teamStandings {
+		byTeam[teamStandings[i].TeamID] = &teamStandings[i]
+	}
+	for _, s := range individual {
+		if s.TeamID == nil {
+			continue
+		}
+		if ts, ok := byTeam[*s.TeamID]; ok {
+			ts.BrojUcesnika++
+			ts.Vrednost += s.Vrednost
+		}
+	}
+	sort.SliceStable(teamStandings, func(i, j int) bool {
+		return teamStandings[i].Vrednost > teamStandings[j].Vrednost
+	})
+	for i := range teamStandings {
+		teamStandings[i].Rang = i + 1
+		if i > 0 && teamStandings[i].Vrednost == teamStandings[i-1].Vrednost {
+			teamStandings[i].Rang = teamStandings[i-1].Rang
+		}
+	}
+	return individual, teamStandings, nil
+}
+
+// participantValues vraća vrednost metrike po korisniku unutar perioda izazova.
+func participantValues(db *gorm.DB, ch *models.ClubChallenge, korisnikIDs []uint) (map[uint]float64, error) {
+	out := make(map[uint]float64, len(korisnikIDs))
+	if len(korisnikIDs) == 0 {
+		return out, nil
+	}
+	type aggRow struct {
+		KorisnikID uint
+		Vrednost   float64
+	}
+	from, to := Window(ch)
+	var rows []aggRow
+	switch ch.Metrika {
+	case models.ChallengeMetrikaKoraci:
+		if err := db.Model(&models.UserDailySteps{}).
+			Select("user_id as korisnik_id, SUM(steps) as vrednost").
+			Where("user_id IN ? AND date >= ? AND date <= ?", korisnikIDs, dateUTC(ch.PocetakDatum), dateUTC(ch.KrajDatum)).
+			Group("user_id").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+	case models.ChallengeMetrikaKm, models.ChallengeMetrikaUspon:
+		col := "SUM(distance_m) / 1000.0"
+		if ch.Metrika == models.ChallengeMetrikaUspon {
+			col = "SUM(elevation_gain_m)"
+		}
+		if err := db.Model(&models.TrackedActivity{}).
+			Select("user_id as korisnik_id, "+col+" as vrednost").
+			Where("user_id IN ? AND status = ? AND flagged = ? AND started_at >= ? AND started_at < ?", korisnikIDs, models.TrackedActivityStatusCompleted, false, from, to).
+			Group("user_id").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+	case models.ChallengeMetrikaVrhovi:
+		if err := db.Model(&models.PeakAscent{}).
+			Select("korisnik_id, COUNT(*) as vrednost").
+			Where("korisnik_id IN ? AND datum >= ? AND datum < ?", korisnikIDs, from, to).
+			Group("korisnik_id").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		// Akcije vezane za vrh iz kataloga su već u dnevniku uspona.
+		var prijave []aggRow
+		if err := db.Model(&models.Prijava{}).
+			Select("prijave.korisnik_id as korisnik_id, COUNT(*) as vrednost").
+			Joins("JOIN akcije ON akcije.id = prijave.akcija_id").
+			Where("prijave.korisnik_id IN ? AND prijave.status = ? AND akcije.is_completed = ? AND akcije.peak_id IS NULL", korisnikIDs, "popeo se", true).
+			Where("akcije.datum >= ? AND akcije.datum < ?", from, to).
+			Group("prijave.korisnik_id").
+			Scan(&prijave).Error; err != nil {
+			return nil, err
+		}
+		rows = append(rows, prijave...)
+	}
+	for _, r := range rows {
+		out[r.KorisnikID] += r.Vrednost
+	}
+	return out, nil
+}
+
+func dateUTC(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
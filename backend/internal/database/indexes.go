@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"beleg-app/backend/internal/deltasync"
 	"beleg-app/backend/internal/models"
 
 	"gorm.io/gorm"
@@ -13,6 +14,9 @@ import (
 const prijavaUniqueIndexName = "idx_prijave_akcija_korisnik"
 const signupPendingUniqueIndexName = "idx_signup_pending_unique"
 
+// dedupeBatchSize — koliko duplih prijava se briše u jednoj transakciji.
+const dedupeBatchSize = 500
+
 // DuplicatePrijaveError vraća se kada postoje duplikati prije kreiranja unique indeksa.
 // Ne sadrži korisničke podatke — samo broj duplicate grupa.
 type DuplicatePrijaveError struct {
@@ -157,74 +161,42 @@ func MaintenanceCleanupDuplicatePrijave(db *gorm.DB) (removed int64, err error)
 	return maintenanceDedupeDuplicatePrijave(db)
 }
 
+// maintenanceDedupeDuplicatePrijave briše sve osim najstarije prijave po (akcija, korisnik).
+// Brisanje ide raw SQL-om, pa se tombstone-ovi za /api/sync upisuju eksplicitno, u istoj transakciji.
 func maintenanceDedupeDuplicatePrijave(db *gorm.DB) (int64, error) {
-	dialect := strings.ToLower(db.Dialector.Name())
-	var totalRemoved int64
-
-	switch dialect {
-	case "postgres":
-		if err := db.Exec(`
-			DELETE FROM prijava_izbori
-			WHERE prijava_id IN (
-				SELECT p.id FROM prijave p
-				INNER JOIN (
-					SELECT akcija_id, korisnik_id, MIN(id) AS keep_id
-					FROM prijave
-					GROUP BY akcija_id, korisnik_id
-					HAVING COUNT(*) > 1
-				) d ON p.akcija_id = d.akcija_id AND p.korisnik_id = d.korisnik_id AND p.id <> d.keep_id
-			)
-		`).Error; err != nil {
-			return 0, fmt.Errorf("maintenance dedupe prijava_izbori: %w", err)
-		}
-		res := db.Exec(`
-			DELETE FROM prijave p
-			USING (
-				SELECT akcija_id, korisnik_id, MIN(id) AS keep_id
-				FROM prijave
-				GROUP BY akcija_id, korisnik_id
-				HAVING COUNT(*) > 1
-			) d
-			WHERE p.akcija_id = d.akcija_id AND p.korisnik_id = d.korisnik_id AND p.id <> d.keep_id
-		`)
-		if res.Error != nil {
-			return 0, fmt.Errorf("maintenance dedupe prijave: %w", res.Error)
-		}
-		totalRemoved = res.RowsAffected
-	default:
-		type dupKey struct {
-			AkcijaID   uint
-			KorisnikID uint
-			KeepID     uint
-		}
-		var dups []dupKey
-		if err := db.Raw(`
+	var removeIDs []uint
+	if err := db.Raw(`
+		SELECT p.id FROM prijave p
+		INNER JOIN (
 			SELECT akcija_id, korisnik_id, MIN(id) AS keep_id
 			FROM prijave
 			GROUP BY akcija_id, korisnik_id
 			HAVING COUNT(*) > 1
-		`).Scan(&dups).Error; err != nil {
-			return 0, fmt.Errorf("maintenance dedupe prijave scan: %w", err)
-		}
-		for _, d := range dups {
-			var removeIDs []uint
-			if err := db.Raw(`
-				SELECT id FROM prijave
-				WHERE akcija_id = ? AND korisnik_id = ? AND id <> ?
-			`, d.AkcijaID, d.KorisnikID, d.KeepID).Scan(&removeIDs).Error; err != nil {
-				return totalRemoved, fmt.Errorf("maintenance dedupe prijave ids: %w", err)
-			}
-			if len(removeIDs) == 0 {
-				continue
+		) d ON p.akcija_id = d.akcija_id AND p.korisnik_id = d.korisnik_id AND p.id <> d.keep_id
+		ORDER BY p.id
+	`).Scan(&removeIDs).Error; err != nil {
+		return 0, fmt.Errorf("maintenance dedupe prijave ids: %w", err)
+	}
+
+	var totalRemoved int64
+	for start := 0; start < len(removeIDs); start += dedupeBatchSize {
+		batch := removeIDs[start:min(start+dedupeBatchSize, len(removeIDs))]
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := deltasync.RecordDeleted(tx, "prijave", batch); err != nil {
+				return err
 			}
-			if err := db.Exec("DELETE FROM prijava_izbori WHERE prijava_id IN ?", removeIDs).Error; err != nil {
-				return totalRemoved, err
+			if err := tx.Exec("DELETE FROM prijava_izbori WHERE prijava_id IN ?", batch).Error; err != nil {
+				return fmt.Errorf("maintenance dedupe prijava_izbori: %w", err)
 			}
-			res := db.Exec("DELETE FROM prijave WHERE id IN ?", removeIDs)
+			res := tx.Exec("DELETE FROM prijave WHERE id IN ?", batch)
 			if res.Error != nil {
-				return totalRemoved, res.Error
+				return fmt.Errorf("maintenance dedupe prijave: %w", res.Error)
 			}
 			totalRemoved += res.RowsAffected
+			return nil
+		})
+		if err != nil {
+			return totalRemoved, err
 		}
 	}
 	return totalRemoved, nil
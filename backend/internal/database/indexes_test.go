@@ -26,6 +26,7 @@ func testIndexesDB(t *testing.T) *gorm.DB {
 		&models.Prijava{},
 		&models.PrijavaIzbori{},
 		&models.ActionSignupRequest{},
+		&models.SyncTombstone{},
 	); err != nil {
 		t.Fatalf("migrate: %v", err)
 	}
@@ -136,6 +137,18 @@ func TestMaintenanceCleanupDuplicatePrijave_RemovesDuplicatesExplicitly(t *testi
 	if groups != 0 {
 		t.Fatalf("expected 0 duplicate groups after maintenance, got %d", groups)
 	}
+
+	var kept models.Prijava
+	if err := db.Where("akcija_id = ?", akcija.ID).First(&kept).Error; err != nil {
+		t.Fatal(err)
+	}
+	var tombstones []models.SyncTombstone
+	if err := db.Where("entitet = ?", "prijave").Find(&tombstones).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(tombstones) != 1 || tombstones[0].EntitetID == kept.ID || tombstones[0].KorisnikID == nil || *tombstones[0].KorisnikID != 7 {
+		t.Fatalf("raw delete must leave a sync tombstone for the removed duplicate, got %+v", tombstones)
+	}
 }
 
 func TestCheckDuplicatePendingSignupReadOnly_DetectsDuplicates(t *testing.T) {
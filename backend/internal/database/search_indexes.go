@@ -0,0 +1,52 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"beleg-app/backend/internal/search"
+
+	"gorm.io/gorm"
+)
+
+// searchFoldFunctionSQL mora davati isti tekst kao slug.FoldLatin (ćirilica → latinica, bez dijakritika);
+// velika slova se preslikavaju pre lower(), koji pod C lokalom ne menja ćirilicu.
+const searchFoldFunctionSQL = `
+CREATE OR REPLACE FUNCTION beleg_fold(input text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+	SELECT translate(
+		replace(replace(replace(replace(replace(lower(translate(coalesce(input, ''),
+			'ČĆŠŽĐАБВГДЂЕЖЗИЈКЛЉМНЊОПРСТЋУФХЦЧЏШ',
+			'čćšžđабвгдђежзијклљмнњопрстћуфхцчџш')), 'đ', 'dj'), 'ђ', 'dj'), 'љ', 'lj'), 'њ', 'nj'), 'џ', 'dz'),
+		'čćšžабвгдежзијклмнопрстћуфхцчш',
+		'ccszabvgdezzijklmnoprstcufhccs'
+	)
+$$`
+
+// PostAutoMigrateCreateSearchIndexes kreira beleg_fold i GIN full-text indekse (isto kao migracija 000008).
+// Samo Postgres; na ostalim dialektima pretraga ide kroz LIKE.
+func PostAutoMigrateCreateSearchIndexes(db *gorm.DB) error {
+	if db == nil || !strings.EqualFold(db.Dialector.Name(), "postgres") {
+		return nil
+	}
+	if err := db.Exec(searchFoldFunctionSQL).Error; err != nil {
+		return fmt.Errorf("database: create beleg_fold failed: %w", err)
+	}
+	tipovi := make([]string, 0, len(search.Documents))
+	for tip := range search.Documents {
+		tipovi = append(tipovi, tip)
+	}
+	sort.Strings(tipovi)
+	for _, tip := range tipovi {
+		doc := search.Documents[tip]
+		sql := fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (to_tsvector('simple', beleg_fold(%s)))",
+			doc.IndexName, doc.Table, doc.Expr,
+		)
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("database: create %s failed: %w", doc.IndexName, err)
+		}
+	}
+	return nil
+}
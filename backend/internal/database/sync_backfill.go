@@ -0,0 +1,22 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// PostAutoMigrateBackfillSyncTimestamps popunjava updated_at koji je AutoMigrate dodao postojećim
+// prijavama i obaveštenjima (isto kao migracija 000012); redovi sa NULL ne bi stigli u /api/sync.
+func PostAutoMigrateBackfillSyncTimestamps(db *gorm.DB) error {
+	if db == nil {
+		return nil
+	}
+	if err := db.Exec("UPDATE prijave SET updated_at = prijavljen_at WHERE updated_at IS NULL").Error; err != nil {
+		return fmt.Errorf("database: backfill prijave.updated_at failed: %w", err)
+	}
+	if err := db.Exec("UPDATE obavestenja SET updated_at = COALESCE(read_at, created_at) WHERE updated_at IS NULL").Error; err != nil {
+		return fmt.Errorf("database: backfill obavestenja.updated_at failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package deltasync
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const cursorVersion = 1
+
+var (
+	ErrInvalidCursor = errors.New("nevažeći kursor sinhronizacije")
+	ErrCursorExpired = errors.New("kursor sinhronizacije je istekao")
+)
+
+// Position je keyset pozicija u jednom entitetu: poslednji poslat (updated_at, id).
+type Position struct {
+	T  time.Time `json:"t"`
+	ID uint      `json:"id"`
+}
+
+// After je true ako je zapis (t, id) posle pozicije.
+func (p Position) After(t time.Time, id uint) bool {
+	return t.After(p.T) || (t.Equal(p.T) && id > p.ID)
+}
+
+// Cursor je neproziran za klijenta (base64 JSON). Svaki entitet ima svoju poziciju, pa se
+// sinhronizacija prekinuta na lošoj vezi nastavlja tačno od poslednje primljene stranice.
+// Kursor važi za korisnika i klub za koje je izdat; kada se klub promeni, vidljivi skup je drugi,
+// pa se entiteti šalju ispočetka, a tombstone-ovi nastavljaju od iste pozicije.
+type Cursor struct {
+	Verzija    int                 `json:"v"`
+	KorisnikID uint                `json:"k"`
+	KlubID     uint                `json:"c"`
+	Izdat      time.Time           `json:"i"`
+	Inicijalna bool                `json:"f,omitempty"` // prva sinhronizacija još traje (hasMore)
+	Tombstone  uint                `json:"x"`
+	Pozicije   map[string]Position `json:"p"`
+}
+
+// NewCursor vraća prazan kursor (prva, puna sinhronizacija).
+func NewCursor(korisnikID, klubID uint) Cursor {
+	return Cursor{Verzija: cursorVersion, KorisnikID: korisnikID, KlubID: klubID, Inicijalna: true, Pozicije: map[string]Position{}}
+}
+
+// Encode vraća kursor za ?since=.
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor čita ?since= i proverava da li kursor pripada korisniku i da tombstone-ovi od njegovog
+// izdavanja još nisu obrisani (ErrCursorExpired → klijent radi punu sinhronizaciju). Kursor izdat za
+// drugi klub počinje punu sinhronizaciju, ali zadržava poziciju tombstone-ova: tako klijent dobija
+// brisanja zapisa starog kluba koje više ne vidi (upisana pri promeni kluba).
+func DecodeCursor(s string, korisnikID, klubID uint, now time.Time) (Cursor, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return NewCursor(korisnikID, klubID), nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Verzija != cursorVersion || c.KorisnikID != korisnikID {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.Izdat.Before(now.Add(-TombstoneRetention)) {
+		return Cursor{}, ErrCursorExpired
+	}
+	if c.KlubID != klubID {
+		rebased := NewCursor(korisnikID, klubID)
+		rebased.Tombstone = c.Tombstone
+		return rebased, nil
+	}
+	if c.Pozicije == nil {
+		c.Pozicije = map[string]Position{}
+	}
+	return c, nil
+}
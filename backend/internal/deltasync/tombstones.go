@@ -0,0 +1,295 @@
+// Package deltasync podržava /api/sync: mobilni klijent posle prve sinhronizacije dobija samo
+// zapise izmenjene od svog kursora (po updated_at) i tombstone-ove obrisanih zapisa.
+//
+// Tombstone-ovi se pišu GORM delete callback-om, u istoj transakciji kao i brisanje, pa nijedno
+// mesto u kodu koje briše akcije, prijave, zahteve, obaveštenja, praćenja ili zadatke ne mora
+// da zna za sync. Raw SQL DELETE (db.Exec) callback ne vidi — takvo mesto pre brisanja zove
+// RecordDeleted. Tombstone akcije nosi klub i vidljivost, da ID nejavne akcije ne stigne van kluba;
+// učesnicima van kluba ga zapisuje brisanje akcije preko RecordHidden. Zapis koji ostaje u bazi, ali ga
+// korisnik više ne vidi (akcija vraćena u nacrt, promena kluba), klijentu se šalje kao brisanje preko RecordHidden.
+package deltasync
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Nazivi entiteta u odgovoru /api/sync i u sync_tombstones.entitet.
+const (
+	EntitetAkcija       = "akcije"
+	EntitetPrijava      = "prijave"
+	EntitetSignupZahtev = "zahteviZaPrijavu"
+	EntitetObavestenje  = "obavestenja"
+	EntitetPracenje     = "pracenja"
+	EntitetZadatak      = "zadaci"
+)
+
+const (
+	// TombstoneRetention — koliko dugo se čuvaju tombstone-ovi; stariji kursor traži punu sinhronizaciju.
+	TombstoneRetention = 90 * 24 * time.Hour
+
+	pendingTombstonesKey = "deltasync:pending"
+)
+
+// Entiteti — redosled entiteta u odgovoru.
+func Entiteti() []string {
+	return []string{EntitetAkcija, EntitetPrijava, EntitetSignupZahtev, EntitetObavestenje, EntitetPracenje, EntitetZadatak}
+}
+
+// trackedTable opisuje kome pripada tombstone obrisanog reda.
+type trackedTable struct {
+	entitet   string
+	userCols  []string // tombstone po korisniku (praćenje: i requester i target)
+	klubCol   string   // tombstone za članove kluba
+	klubCond  string   // SQL uslov pod kojim red pripada klubu (prazno = uvek)
+	javnaCond string   // SQL uslov vidljivosti svima; tombstone nosi rezultat u Javna (prazno = ne beleži se)
+}
+
+// Uslovi akcija su isti kao sqlClubOrganizedOnly i helpers.SQLAkcijaObjavljena (helpers se ovde ne uvozi:
+// database → deltasync bi zatvorio ciklus u testovima helpers-a).
+const (
+	sqlAkcijaKlupska    = "(organizator_tip IS NULL OR TRIM(organizator_tip) = '' OR LOWER(TRIM(organizator_tip)) <> 'vodic')"
+	sqlAkcijaObjavljena = "(status_objave IS NULL OR status_objave = '' OR status_objave = 'objavljena')"
+)
+
+// Akcije nemaju vlasnika: tombstone objavljene javne akcije je za sve (klijent briše samo ako ima taj ID),
+// ostale samo za klub koji ih organizuje — isto pravilo kao za žive redove u /api/sync.
+var trackedTables = map[string]trackedTable{
+	"akcije": {entitet: EntitetAkcija, klubCol: "klub_id", klubCond: sqlAkcijaKlupska,
+		javnaCond: "javna = TRUE AND " + sqlAkcijaObjavljena},
+	"prijave":                {entitet: EntitetPrijava, userCols: []string{"korisnik_id"}},
+	"action_signup_requests": {entitet: EntitetSignupZahtev, userCols: []string{"requester_id"}},
+	"obavestenja":            {entitet: EntitetObavestenje, userCols: []string{"user_id"}},
+	"follows":                {entitet: EntitetPracenje, userCols: []string{"requester_id", "target_id"}},
+	"zadaci":                 {entitet: EntitetZadatak, klubCol: "klub_id"},
+}
+
+// RegisterTombstoneCallbacks uključuje beleženje tombstone-ova za praćene tabele.
+func RegisterTombstoneCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Delete().Before("gorm:delete").Register("deltasync:collect", collectDeleted); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("deltasync:record", recordTombstones)
+}
+
+// collectDeleted pre brisanja čita ID i vlasnike redova koje će DELETE obuhvatiti (isti WHERE + primarni ključevi modela).
+func collectDeleted(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return
+	}
+	spec, ok := trackedTables[tx.Statement.Table]
+	if !ok {
+		return
+	}
+	// Model (a ne samo Table) je potreban da bi se rešio clause.PrimaryKey iz Delete(&Model{}, id).
+	q := tx.Session(&gorm.Session{NewDB: true}).Model(reflect.New(tx.Statement.Schema.ModelType).Interface()).Table(tx.Statement.Table)
+	hasConds := false
+	if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			q = q.Clauses(clause.Where{Exprs: where.Exprs})
+			hasConds = true
+		}
+	}
+	if ids := primaryKeyValues(tx); len(ids) > 0 {
+		q = q.Where("id IN ?", ids)
+		hasConds = true
+	}
+	if !hasConds {
+		// GORM odbija DELETE bez uslova (ErrMissingWhereClause), pa nema šta da se beleži.
+		return
+	}
+	tombstones, err := ownerTombstones(q, spec, time.Now().UTC())
+	if err != nil {
+		_ = tx.AddError(fmt.Errorf("deltasync: čitanje redova za brisanje iz %s: %w", tx.Statement.Table, err))
+		return
+	}
+	if len(tombstones) == 0 {
+		return
+	}
+	tx.InstanceSet(pendingTombstonesKey, tombstones)
+}
+
+// ownerTombstones čita ID i vlasnike redova iz q i pravi po jedan tombstone za svakog vlasnika.
+func ownerTombstones(q *gorm.DB, spec trackedTable, now time.Time) ([]models.SyncTombstone, error) {
+	cols := append([]string{"id"}, spec.userCols...)
+	switch {
+	case spec.klubCol != "" && spec.klubCond != "":
+		cols = append(cols, "CASE WHEN "+spec.klubCond+" THEN "+spec.klubCol+" END AS "+spec.klubCol)
+	case spec.klubCol != "":
+		cols = append(cols, spec.klubCol)
+	}
+	if spec.javnaCond != "" {
+		cols = append(cols, "CASE WHEN "+spec.javnaCond+" THEN 1 ELSE 0 END AS javna")
+	}
+	var rows []map[string]any
+	if err := q.Select(strings.Join(cols, ", ")).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	tombstones := make([]models.SyncTombstone, 0, len(rows))
+	for _, row := range rows {
+		id := toUint(row["id"])
+		if id == 0 {
+			continue
+		}
+		if len(spec.userCols) == 0 {
+			t := models.SyncTombstone{Entitet: spec.entitet, EntitetID: id, ObrisanoAt: now}
+			if spec.klubCol != "" {
+				if klubID := toUint(row[spec.klubCol]); klubID != 0 {
+					t.KlubID = &klubID
+				}
+			}
+			if spec.javnaCond != "" {
+				javna := toBool(row["javna"])
+				t.Javna = &javna
+			}
+			tombstones = append(tombstones, t)
+			continue
+		}
+		for _, col := range spec.userCols {
+			if userID := toUint(row[col]); userID != 0 {
+				tombstones = append(tombstones, models.SyncTombstone{Entitet: spec.entitet, EntitetID: id, KorisnikID: &userID, ObrisanoAt: now})
+			}
+		}
+	}
+	return tombstones, nil
+}
+
+// RecordDeleted upisuje tombstone-ove za redove koje briše raw SQL (db.Exec), koji callback ne vidi.
+// Poziva se pre DELETE-a, u istoj transakciji; za tabelu koja se ne prati ne radi ništa.
+func RecordDeleted(tx *gorm.DB, table string, ids []uint) error {
+	spec, ok := trackedTables[table]
+	if !ok || len(ids) == 0 {
+		return nil
+	}
+	q := tx.Session(&gorm.Session{NewDB: true}).Table(table).Where("id IN ?", ids)
+	tombstones, err := ownerTombstones(q, spec, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("deltasync: čitanje redova za brisanje iz %s: %w", table, err)
+	}
+	if len(tombstones) == 0 {
+		return nil
+	}
+	if err := tx.Session(&gorm.Session{NewDB: true}).Create(&tombstones).Error; err != nil {
+		return fmt.Errorf("deltasync: upis tombstone-ova: %w", err)
+	}
+	return nil
+}
+
+// RecordHidden upisuje tombstone-ove za zapise koji ostaju u bazi, ali ih navedeni korisnici više ne vide:
+// klijent ih briše isto kao obrisane, a ako zapis ponovo postane vidljiv, stiže kao izmenjen.
+func RecordHidden(tx *gorm.DB, entitet string, ids []uint, korisnikIDs []uint) error {
+	if len(ids) == 0 || len(korisnikIDs) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	tombstones := make([]models.SyncTombstone, 0, len(ids)*len(korisnikIDs))
+	for _, userID := range korisnikIDs {
+		if userID == 0 {
+			continue
+		}
+		for _, id := range ids {
+			tombstones = append(tombstones, models.SyncTombstone{Entitet: entitet, EntitetID: id, KorisnikID: &userID, ObrisanoAt: now})
+		}
+	}
+	if len(tombstones) == 0 {
+		return nil
+	}
+	if err := tx.Session(&gorm.Session{NewDB: true}).CreateInBatches(&tombstones, 500).Error; err != nil {
+		return fmt.Errorf("deltasync: upis tombstone-ova: %w", err)
+	}
+	return nil
+}
+
+// recordTombstones upisuje tombstone-ove samo ako je brisanje uspelo (u istoj transakciji).
+func recordTombstones(tx *gorm.DB) {
+	if tx.Error != nil || tx.RowsAffected == 0 {
+		return
+	}
+	v, ok := tx.InstanceGet(pendingTombstonesKey)
+	if !ok {
+		return
+	}
+	tombstones, _ := v.([]models.SyncTombstone)
+	if len(tombstones) == 0 {
+		return
+	}
+	if err := tx.Session(&gorm.Session{NewDB: true}).Create(&tombstones).Error; err != nil {
+		_ = tx.AddError(fmt.Errorf("deltasync: upis tombstone-ova: %w", err))
+	}
+}
+
+// primaryKeyValues vraća popunjene primarne ključeve modela iz Delete(&row) / Delete(&rows).
+func primaryKeyValues(tx *gorm.DB) []any {
+	field := tx.Statement.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+	rv := reflect.Indirect(tx.Statement.ReflectValue)
+	ids := make([]any, 0, 1)
+	add := func(v reflect.Value) {
+		if value, zero := field.ValueOf(tx.Statement.Context, v); !zero {
+			ids = append(ids, value)
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		add(rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			add(reflect.Indirect(rv.Index(i)))
+		}
+	}
+	return ids
+}
+
+// PruneTombstones briše tombstone-ove starije od TombstoneRetention (kursor stariji od toga traži punu sinhronizaciju).
+func PruneTombstones(db *gorm.DB, now time.Time) (int64, error) {
+	res := db.Where("obrisano_at < ?", now.UTC().Add(-TombstoneRetention)).Delete(&models.SyncTombstone{})
+	return res.RowsAffected, res.Error
+}
+
+// toBool: kolona modela stiže kao bool, izraz bez modela kao broj (CASE ... THEN 1).
+func toBool(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case *bool:
+		return b != nil && *b
+	}
+	return toUint(v) == 1
+}
+
+func toUint(v any) uint {
+	switch n := v.(type) {
+	case int64:
+		if n > 0 {
+			return uint(n)
+		}
+	case int32:
+		if n > 0 {
+			return uint(n)
+		}
+	case int:
+		if n > 0 {
+			return uint(n)
+		}
+	case uint:
+		return n
+	case uint64:
+		return uint(n)
+	case uint32:
+		return uint(n)
+	case *uint:
+		if n != nil {
+			return *n
+		}
+	}
+	return 0
+}
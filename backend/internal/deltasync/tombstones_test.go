@@ -0,0 +1,131 @@
+package deltasync
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func testSyncDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "deltasync")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Prijava{}, &models.Follow{}, &models.Zadatak{}, &models.PeakAscent{}, &models.SyncTombstone{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := RegisterTombstoneCallbacks(db); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	return db
+}
+
+func tombstonesFor(t *testing.T, db *gorm.DB, entitet string) []models.SyncTombstone {
+	t.Helper()
+	var rows []models.SyncTombstone
+	if err := db.Where("entitet = ?", entitet).Order("id ASC").Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestTombstones_WhereAndPrimaryKeyDeletes(t *testing.T) {
+	db := testSyncDB(t)
+	p1 := models.Prijava{AkcijaID: 1, KorisnikID: 7}
+	p2 := models.Prijava{AkcijaID: 2, KorisnikID: 7}
+	p3 := models.Prijava{AkcijaID: 2, KorisnikID: 8}
+	db.Create(&p1)
+	db.Create(&p2)
+	db.Create(&p3)
+
+	if err := db.Where("akcija_id = ?", 2).Delete(&models.Prijava{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(&p1).Error; err != nil {
+		t.Fatal(err)
+	}
+	rows := tombstonesFor(t, db, EntitetPrijava)
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 prijava tombstones, got %+v", rows)
+	}
+	if rows[0].EntitetID != p2.ID || *rows[0].KorisnikID != 7 || rows[1].EntitetID != p3.ID || *rows[1].KorisnikID != 8 || rows[2].EntitetID != p1.ID {
+		t.Fatalf("tombstones must carry id and owner of each deleted row, got %+v", rows)
+	}
+
+	f := models.Follow{RequesterID: 3, TargetID: 4, Status: models.FollowStatusAccepted}
+	db.Create(&f)
+	db.Where("requester_id = ? AND target_id = ?", 3, 4).Delete(&models.Follow{})
+	if rows := tombstonesFor(t, db, EntitetPracenje); len(rows) != 2 || *rows[0].KorisnikID != 3 || *rows[1].KorisnikID != 4 {
+		t.Fatalf("follow delete must notify both sides, got %+v", rows)
+	}
+
+	klubID := uint(5)
+	z := models.Zadatak{Naziv: "Markacija staze", KlubID: &klubID}
+	db.Create(&z)
+	db.Delete(&models.Zadatak{}, z.ID)
+	if rows := tombstonesFor(t, db, EntitetZadatak); len(rows) != 1 || rows[0].KorisnikID != nil || rows[0].KlubID == nil || *rows[0].KlubID != 5 {
+		t.Fatalf("task tombstone must be scoped to the club, got %+v", rows)
+	}
+
+	db.Create(&models.PeakAscent{KorisnikID: 1, PeakID: 1, Datum: time.Now(), Izvor: "rucno"})
+	db.Where("korisnik_id = ?", 1).Delete(&models.PeakAscent{})
+	var total int64
+	db.Model(&models.SyncTombstone{}).Count(&total)
+	if total != 6 {
+		t.Fatalf("untracked tables must not produce tombstones, total %d", total)
+	}
+}
+
+func TestTombstones_RolledBackWithTransaction(t *testing.T) {
+	db := testSyncDB(t)
+	p := models.Prijava{AkcijaID: 1, KorisnikID: 7}
+	db.Create(&p)
+	errAbort := errors.New("abort")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&p).Error; err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("unexpected %v", err)
+	}
+	if rows := tombstonesFor(t, db, EntitetPrijava); len(rows) != 0 {
+		t.Fatalf("rolled back delete must not leave tombstones, got %+v", rows)
+	}
+}
+
+func TestCursor_RoundTripAndValidation(t *testing.T) {
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	c := NewCursor(7, 5)
+	c.Izdat = now
+	c.Tombstone = 12
+	c.Pozicije[EntitetPrijava] = Position{T: now.Add(-time.Minute), ID: 3}
+
+	got, err := DecodeCursor(c.Encode(), 7, 5, now)
+	if err != nil || got.Tombstone != 12 || got.Pozicije[EntitetPrijava].ID != 3 || !got.Pozicije[EntitetPrijava].T.Equal(now.Add(-time.Minute)) {
+		t.Fatalf("round trip failed: %+v err=%v", got, err)
+	}
+	if _, err := DecodeCursor(c.Encode(), 8, 5, now); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("cursor of another user must be rejected, got %v", err)
+	}
+	if moved, err := DecodeCursor(c.Encode(), 7, 6, now); err != nil || !moved.Inicijalna || moved.KlubID != 6 || len(moved.Pozicije) != 0 || moved.Tombstone != 12 {
+		t.Fatalf("club change must restart entities but keep the tombstone position, got %+v err=%v", moved, err)
+	}
+	if _, err := DecodeCursor(c.Encode(), 7, 5, now.Add(TombstoneRetention+time.Hour)); !errors.Is(err, ErrCursorExpired) {
+		t.Fatalf("cursor older than tombstone retention must expire, got %v", err)
+	}
+	if _, err := DecodeCursor("nije-kursor", 7, 5, now); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("garbage must be rejected, got %v", err)
+	}
+	if fresh, err := DecodeCursor("", 7, 5, now); err != nil || !fresh.Inicijalna {
+		t.Fatalf("empty since must start a full sync, got %+v err=%v", fresh, err)
+	}
+}
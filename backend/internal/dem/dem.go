@@ -0,0 +1,192 @@
+// Package dem čita visine terena iz lokalnih HGT pločica (SRTM1/SRTM3 ili Copernicus DEM konvertovan u HGT).
+//
+// Pločica pokriva 1°×1°, ime je po jugozapadnom uglu (N43E021.hgt), sadržaj je kvadratna mreža
+// big-endian int16 visina u metrima, red po red od severa ka jugu; -32768 je „praznina“ (void).
+// Podržane su i gzip pločice (N43E021.hgt.gz). Bez DEM_DIR servis nije dostupan i pozivaoci zadržavaju GPS visine.
+package dem
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	voidValue = -32768
+	// maxCachedTiles — SRTM1 pločica je ~25 MB u memoriji; planinarske ture retko prelaze nekoliko pločica.
+	maxCachedTiles = 12
+)
+
+var ErrNoData = errors.New("dem: nema podataka o visini za tačku")
+
+type tile struct {
+	size    int // broj uzoraka po stranici (1201 za SRTM3, 3601 za SRTM1)
+	samples []int16
+}
+
+// Service učitava pločice po potrebi i drži poslednje korišćene u memoriji.
+type Service struct {
+	dir string
+
+	mu      sync.Mutex
+	tiles   map[string]*tile // nil vrednost = pločica ne postoji (ne traži se ponovo)
+	lruKeys []string
+}
+
+// NewService vraća servis nad direktorijumom sa .hgt pločicama.
+func NewService(dir string) *Service {
+	return &Service{dir: dir, tiles: make(map[string]*tile)}
+}
+
+// FromEnv vraća servis nad DEM_DIR, ili nil kada DEM nije podešen.
+func FromEnv() *Service {
+	dir := strings.TrimSpace(os.Getenv("DEM_DIR"))
+	if dir == "" {
+		return nil
+	}
+	if st, err := os.Stat(dir); err != nil || !st.IsDir() {
+		return nil
+	}
+	return NewService(dir)
+}
+
+// TileName vraća ime pločice koja sadrži tačku (npr. N43E021).
+func TileName(lat, lng float64) string {
+	latBase := int(math.Floor(lat))
+	lngBase := int(math.Floor(lng))
+	ns, ew := 'N', 'E'
+	if latBase < 0 {
+		ns = 'S'
+	}
+	if lngBase < 0 {
+		ew = 'W'
+	}
+	return fmt.Sprintf("%c%02d%c%03d", ns, abs(latBase), ew, abs(lngBase))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Elevation vraća visinu terena u metrima (bilinearna interpolacija četiri susedna uzorka).
+func (s *Service) Elevation(lat, lng float64) (float64, error) {
+	if s == nil || lat < -60 || lat > 61 || lng < -180 || lng > 180 {
+		return 0, ErrNoData
+	}
+	t, err := s.tile(TileName(lat, lng))
+	if err != nil {
+		return 0, err
+	}
+	if t == nil {
+		return 0, ErrNoData
+	}
+	n := float64(t.size - 1)
+	// Red 0 je severna ivica pločice (floor(lat)+1), poslednji red južna.
+	y := (math.Floor(lat) + 1 - lat) * n
+	x := (lng - math.Floor(lng)) * n
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	x1, y1 := min(x0+1, t.size-1), min(y0+1, t.size-1)
+	fx, fy := x-float64(x0), y-float64(y0)
+
+	var sum, weight float64
+	for _, c := range [4]struct {
+		col, row int
+		w        float64
+	}{
+		{x0, y0, (1 - fx) * (1 - fy)},
+		{x1, y0, fx * (1 - fy)},
+		{x0, y1, (1 - fx) * fy},
+		{x1, y1, fx * fy},
+	} {
+		v := t.samples[c.row*t.size+c.col]
+		if v == voidValue || c.w == 0 {
+			continue
+		}
+		sum += float64(v) * c.w
+		weight += c.w
+	}
+	if weight == 0 {
+		return 0, ErrNoData
+	}
+	return sum / weight, nil
+}
+
+func (s *Service) tile(name string) (*tile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.tiles[name]; ok {
+		s.touch(name)
+		return t, nil
+	}
+	t, err := loadTile(s.dir, name)
+	if err != nil {
+		return nil, err
+	}
+	s.tiles[name] = t
+	s.touch(name)
+	for len(s.lruKeys) > maxCachedTiles {
+		delete(s.tiles, s.lruKeys[0])
+		s.lruKeys = s.lruKeys[1:]
+	}
+	return t, nil
+}
+
+func (s *Service) touch(name string) {
+	for i, k := range s.lruKeys {
+		if k == name {
+			s.lruKeys = append(s.lruKeys[:i], s.lruKeys[i+1:]...)
+			break
+		}
+	}
+	s.lruKeys = append(s.lruKeys, name)
+}
+
+// loadTile čita pločicu; nepostojeća pločica (more, van pokrivenosti) vraća nil bez greške.
+func loadTile(dir, name string) (*tile, error) {
+	var r io.Reader
+	f, err := os.Open(filepath.Join(dir, name+".hgt"))
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.Open(filepath.Join(dir, name+".hgt.gz"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("dem: %s: %w", name, err)
+		}
+		defer gz.Close()
+		r = gz
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer f.Close()
+		r = f
+	}
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("dem: %s: %w", name, err)
+	}
+	size := int(math.Round(math.Sqrt(float64(len(raw) / 2))))
+	if size < 2 || size*size*2 != len(raw) {
+		return nil, fmt.Errorf("dem: %s: neispravna veličina pločice (%d bajtova)", name, len(raw))
+	}
+	samples := make([]int16, size*size)
+	for i := range samples {
+		samples[i] = int16(binary.BigEndian.Uint16(raw[i*2:]))
+	}
+	return &tile{size: size, samples: samples}, nil
+}
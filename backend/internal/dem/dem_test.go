@@ -0,0 +1,121 @@
+package dem
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTile upisuje pločicu 11×11 (korak 0,1°) u kojoj visina raste 100 m po redu ka jugu i 10 m po koloni ka istoku.
+func writeTile(t *testing.T, dir, name string, gz bool) {
+	t.Helper()
+	const size = 11
+	raw := make([]byte, size*size*2)
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			v := int16(1000 + row*100 + col*10)
+			if row == 5 && col == 5 {
+				v = voidValue
+			}
+			binary.BigEndian.PutUint16(raw[(row*size+col)*2:], uint16(v))
+		}
+	}
+	path := filepath.Join(dir, name+".hgt")
+	if !gz {
+		if err := os.WriteFile(path, raw, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	f, err := os.Create(path + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := gzip.NewWriter(f)
+	w.Write(raw)
+	w.Close()
+	f.Close()
+}
+
+func TestElevation_InterpolatesAndSkipsVoids(t *testing.T) {
+	dir := t.TempDir()
+	writeTile(t, dir, "N43E021", false)
+	writeTile(t, dir, "N44E021", true)
+	s := NewService(dir)
+
+	cases := []struct {
+		lat, lng, want float64
+	}{
+		{44.0, 21.0, 2000},   // 44° pripada pločici N44 (njena južna ivica)
+		{43.0, 21.0, 2000},   // jugozapadni ugao = poslednji red
+		{43.95, 21.05, 1055}, // sredina između četiri uzorka
+		{44.5, 21.2, 1520},   // gzip pločica
+	}
+	for _, tc := range cases {
+		got, err := s.Elevation(tc.lat, tc.lng)
+		if err != nil || math.Abs(got-tc.want) > 0.01 {
+			t.Fatalf("Elevation(%v, %v) = %v, %v; want %v", tc.lat, tc.lng, got, err, tc.want)
+		}
+	}
+	// Uzorak (5,5) je void: interpolacija koristi samo ispravne susede.
+	if got, err := s.Elevation(43.45, 21.55); err != nil || math.Abs(got-(1560+1650+1660)/3.0) > 0.01 {
+		t.Fatalf("void neighbour must be skipped, got %v %v", got, err)
+	}
+	if _, err := s.Elevation(42.5, 21.5); !errors.Is(err, ErrNoData) {
+		t.Fatalf("missing tile must report ErrNoData, got %v", err)
+	}
+	if TileName(-0.5, -0.5) != "S01W001" || TileName(43.2, 21.9) != "N43E021" {
+		t.Fatal("unexpected tile names")
+	}
+}
+
+func TestCorrectAndBuildProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeTile(t, dir, "N43E021", false)
+	s := NewService(dir)
+	bad, good := 5000.0, 1995.0
+	pts := []ProfilePoint{
+		{Lat: 43.0, Lng: 21.0, Elevation: &good}, // 5 m od terena (2000) — zadržava se
+		{Lat: 43.05, Lng: 21.0, Elevation: &bad},
+		{Lat: 43.1, Lng: 21.0},
+		{Lat: 43.1, Lng: 21.1},
+	}
+	changed := s.Correct(pts)
+	if len(changed) != 3 || *pts[0].Elevation != 1995 || *pts[1].Elevation != 1950 || *pts[2].Elevation != 1900 || *pts[3].Elevation != 1910 {
+		t.Fatalf("unexpected correction %v", changed)
+	}
+
+	prof, ok := BuildProfile(pts)
+	if !ok {
+		t.Fatal("profile expected")
+	}
+	if prof.AscentM != 10 || prof.DescentM != 95 || prof.MinM != 1900 || prof.MaxM != 1995 || len(prof.DistanceM) != 4 {
+		t.Fatalf("unexpected profile %+v", prof)
+	}
+	if prof.MaxGradePct <= 0 || prof.MaxGradePct > 1 {
+		t.Fatalf("gentle descent must have a small grade, got %v", prof.MaxGradePct)
+	}
+	if _, ok := BuildProfile([]ProfilePoint{{Lat: 43, Lng: 21}}); ok {
+		t.Fatal("profile without elevations must be rejected")
+	}
+	var nilService *Service
+	if nilService.Correct(pts) != nil {
+		t.Fatal("nil service must not change points")
+	}
+}
+
+func TestBuildProfile_Downsamples(t *testing.T) {
+	pts := make([]ProfilePoint, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		e := 500 + float64(i%2)*20
+		pts = append(pts, ProfilePoint{Lat: 43 + float64(i)*0.0001, Lng: 21, Elevation: &e})
+	}
+	prof, _ := BuildProfile(pts)
+	if len(prof.DistanceM) > MaxProfileSamples || len(prof.DistanceM) != len(prof.ElevationM) || math.Round(prof.DistanceM[len(prof.DistanceM)-1]) != prof.LengthM {
+		t.Fatalf("unexpected downsampling: %d samples, last %v of %v", len(prof.DistanceM), prof.DistanceM[len(prof.DistanceM)-1], prof.LengthM)
+	}
+}
@@ -0,0 +1,142 @@
+package dem
+
+import (
+	"math"
+
+	"beleg-app/backend/internal/geo"
+)
+
+const (
+	// ascentHysteresisM — promene visine manje od ovoga (šum GPS-a i DEM-a) ne ulaze u uspon/spust.
+	ascentHysteresisM = 3.0
+	// gradeWindowM — nagib se meri na deonicama od bar ovoliko metara, da jedna loša tačka ne da „100 %“.
+	gradeWindowM = 100.0
+	// MaxProfileSamples — najviše tačaka u nizovima profila koji se šalju klijentu.
+	MaxProfileSamples = 500
+	// CorrectionThresholdM — GPS visina koja od terena odstupa više od ovoga smatra se pogrešnom.
+	CorrectionThresholdM = 40.0
+)
+
+// ProfilePoint je tačka rute sa opcionom visinom.
+type ProfilePoint struct {
+	Lat       float64
+	Lng       float64
+	Elevation *float64
+}
+
+// Profile je visinski profil rute; nizovi DistanceM i ElevationM su iste dužine (najviše MaxProfileSamples).
+type Profile struct {
+	DistanceM   []float64 `json:"distanceM"`
+	ElevationM  []float64 `json:"elevationM"`
+	LengthM     float64   `json:"duzinaM"`
+	AscentM     float64   `json:"ukupnoUsponM"`
+	DescentM    float64   `json:"ukupnoSpustM"`
+	MaxGradePct float64   `json:"maxNagibPct"`
+	MinM        float64   `json:"minVisinaM"`
+	MaxM        float64   `json:"maxVisinaM"`
+}
+
+// Correct popunjava nedostajuće visine sa terena i zamenjuje GPS visine koje odstupaju više od CorrectionThresholdM.
+// Vraća indekse izmenjenih tačaka. Bez DEM-a (s == nil) ne menja ništa.
+func (s *Service) Correct(points []ProfilePoint) []int {
+	if s == nil {
+		return nil
+	}
+	var changed []int
+	for i := range points {
+		ground, err := s.Elevation(points[i].Lat, points[i].Lng)
+		if err != nil {
+			continue
+		}
+		if points[i].Elevation == nil || math.Abs(*points[i].Elevation-ground) > CorrectionThresholdM {
+			v := math.Round(ground*10) / 10
+			points[i].Elevation = &v
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
+
+// BuildProfile računa profil iz tačaka koje imaju visinu; tačke bez visine ulaze samo u pređenu distancu.
+// Vraća ok=false kada manje od dve tačke imaju visinu.
+func BuildProfile(points []ProfilePoint) (Profile, bool) {
+	var dist, ele []float64
+	total := 0.0
+	for i, p := range points {
+		if i > 0 {
+			total += geo.DistanceKmHaversine(points[i-1].Lat, points[i-1].Lng, p.Lat, p.Lng) * 1000
+		}
+		if p.Elevation != nil {
+			dist = append(dist, total)
+			ele = append(ele, *p.Elevation)
+		}
+	}
+	if len(ele) < 2 {
+		return Profile{}, false
+	}
+	prof := Profile{LengthM: math.Round(total), MinM: ele[0], MaxM: ele[0]}
+
+	// Smer se menja tek kada visina ode ascentHysteresisM suprotno; u istom smeru se broji svaki metar (vrh se ne gubi).
+	anchor, dir := ele[0], 0
+	for _, e := range ele[1:] {
+		prof.MinM, prof.MaxM = math.Min(prof.MinM, e), math.Max(prof.MaxM, e)
+		d := e - anchor
+		switch {
+		case (dir > 0 && d > 0) || d >= ascentHysteresisM:
+			prof.AscentM += d
+			anchor, dir = e, 1
+		case (dir < 0 && d < 0) || d <= -ascentHysteresisM:
+			prof.DescentM -= d
+			anchor, dir = e, -1
+		}
+	}
+
+	from := 0
+	for to := 1; to < len(ele); to++ {
+		for from < to-1 && dist[to]-dist[from+1] >= gradeWindowM {
+			from++
+		}
+		if span := dist[to] - dist[from]; span >= gradeWindowM {
+			if g := math.Abs(ele[to]-ele[from]) / span * 100; g > prof.MaxGradePct {
+				prof.MaxGradePct = g
+			}
+		}
+	}
+	if prof.MaxGradePct == 0 && total > 0 {
+		// Kratka ruta (kraća od prozora): nagib od početka do kraja.
+		prof.MaxGradePct = math.Abs(ele[len(ele)-1]-ele[0]) / math.Max(dist[len(dist)-1], 1) * 100
+	}
+
+	prof.DistanceM, prof.ElevationM = downsample(dist, ele, MaxProfileSamples)
+	prof.AscentM = math.Round(prof.AscentM)
+	prof.DescentM = math.Round(prof.DescentM)
+	prof.MaxGradePct = math.Round(prof.MaxGradePct*10) / 10
+	return prof, true
+}
+
+// downsample zadržava najviše max uzoraka ravnomerno po distanci (prvi i poslednji uvek ostaju).
+func downsample(dist, ele []float64, max int) ([]float64, []float64) {
+	if len(dist) <= max {
+		return roundSeries(dist), roundSeries(ele)
+	}
+	outD := make([]float64, 0, max)
+	outE := make([]float64, 0, max)
+	step := dist[len(dist)-1] / float64(max-1)
+	next := 0.0
+	for i := range dist {
+		if i == len(dist)-1 || (dist[i] >= next && len(outD) < max-1) {
+			outD = append(outD, dist[i])
+			outE = append(outE, ele[i])
+			next = dist[i] + step
+		}
+	}
+	return roundSeries(outD), roundSeries(outE)
+}
+
+func roundSeries(v []float64) []float64 {
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = math.Round(x*10) / 10
+	}
+	return out
+}
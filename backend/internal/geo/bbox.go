@@ -0,0 +1,89 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const kmPerDegreeLat = 111.32
+
+// BBox je pravougaonik na mapi u stepenima. MinLng > MaxLng znači da prelazi 180. meridijan.
+type BBox struct {
+	MinLat float64 `json:"minLat"`
+	MinLng float64 `json:"minLng"`
+	MaxLat float64 `json:"maxLat"`
+	MaxLng float64 `json:"maxLng"`
+}
+
+var ErrInvalidBBox = errors.New("bbox mora biti minLng,minLat,maxLng,maxLat u geografskom opsegu")
+
+// ParseBBox čita „minLng,minLat,maxLng,maxLat“ (isti redosled kao Leaflet toBBoxString / GeoJSON).
+func ParseBBox(raw string) (BBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return BBox{}, ErrInvalidBBox
+	}
+	vals := make([]float64, 4)
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return BBox{}, ErrInvalidBBox
+		}
+		vals[i] = v
+	}
+	b := BBox{MinLng: vals[0], MinLat: vals[1], MaxLng: vals[2], MaxLat: vals[3]}
+	if !ValidLatLng(b.MinLat, b.MinLng) || !ValidLatLng(b.MaxLat, b.MaxLng) || b.MinLat > b.MaxLat {
+		return BBox{}, ErrInvalidBBox
+	}
+	return b, nil
+}
+
+// BBoxAround vraća pravougaonik koji sigurno sadrži krug poluprečnika radiusKm (prefilter pre Haversine).
+func BBoxAround(lat, lng, radiusKm float64) BBox {
+	dLat := radiusKm / kmPerDegreeLat
+	b := BBox{MinLat: math.Max(lat-dLat, -90), MaxLat: math.Min(lat+dLat, 90), MinLng: -180, MaxLng: 180}
+	if b.MinLat <= -90 || b.MaxLat >= 90 {
+		return b
+	}
+	// Najšira geografska dužina je na geografskoj širini bližoj polu.
+	cos := math.Cos(math.Max(math.Abs(b.MinLat), math.Abs(b.MaxLat)) * math.Pi / 180)
+	dLng := radiusKm / (kmPerDegreeLat * cos)
+	if dLng >= 180 {
+		return b
+	}
+	b.MinLng, b.MaxLng = lng-dLng, lng+dLng
+	if b.MinLng < -180 {
+		b.MinLng += 360
+	}
+	if b.MaxLng > 180 {
+		b.MaxLng -= 360
+	}
+	return b
+}
+
+// CrossesAntimeridian je true kada bbox ide preko 180. meridijana (dva opsega po lng).
+func (b BBox) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// Contains proverava da li je tačka unutar bbox-a (ivice uključene).
+func (b BBox) Contains(lat, lng float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return lng >= b.MinLng || lng <= b.MaxLng
+	}
+	return lng >= b.MinLng && lng <= b.MaxLng
+}
+
+// SQLWhere vraća uslov za btree (lat, lng) indeks: opseg po lat, pa lng (OR kod 180. meridijana).
+func (b BBox) SQLWhere(latCol, lngCol string) (string, []any) {
+	cond := latCol + " BETWEEN ? AND ? AND "
+	if b.CrossesAntimeridian() {
+		return cond + "(" + lngCol + " >= ? OR " + lngCol + " <= ?)", []any{b.MinLat, b.MaxLat, b.MinLng, b.MaxLng}
+	}
+	return cond + lngCol + " BETWEEN ? AND ?", []any{b.MinLat, b.MaxLat, b.MinLng, b.MaxLng}
+}
@@ -0,0 +1,66 @@
+package geo
+
+import "testing"
+
+func TestParseBBox(t *testing.T) {
+	b, err := ParseBBox("19.5,42.8,22.9,46.2")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if b.MinLng != 19.5 || b.MinLat != 42.8 || b.MaxLng != 22.9 || b.MaxLat != 46.2 {
+		t.Fatalf("unexpected bbox %+v", b)
+	}
+	for _, raw := range []string{"", "1,2,3", "a,1,2,3", "0,50,10,40", "0,0,200,10"} {
+		if _, err := ParseBBox(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestBBoxAround_ContainsRadius(t *testing.T) {
+	lat, lng := 43.3209, 21.8958 // Niš
+	b := BBoxAround(lat, lng, 100)
+	if b.Contains(44.7866, 20.4489) {
+		t.Fatal("Belgrade is ~200 km away and must be outside the prefilter box")
+	}
+	if !b.Contains(43.5809, 21.3339) {
+		t.Fatal("Kruševac (~50 km) must be inside the prefilter box")
+	}
+}
+
+func TestBBox_Antimeridian(t *testing.T) {
+	b := BBoxAround(-17.7, 179.5, 200)
+	if !b.CrossesAntimeridian() {
+		t.Fatalf("expected antimeridian crossing, got %+v", b)
+	}
+	if !b.Contains(-17.7, -179.5) || b.Contains(-17.7, 170) {
+		t.Fatalf("wrong containment for %+v", b)
+	}
+	where, args := b.SQLWhere("lat", "lng")
+	if where != "lat BETWEEN ? AND ? AND (lng >= ? OR lng <= ?)" || len(args) != 4 {
+		t.Fatalf("unexpected sql %q %v", where, args)
+	}
+}
+
+func TestClusterPoints(t *testing.T) {
+	points := []Point{
+		{Tip: "vrh", ID: 1, Lat: 43.30, Lng: 20.80},
+		{Tip: "vrh", ID: 2, Lat: 43.31, Lng: 20.81},
+		{Tip: "hotel", ID: 3, Lat: 43.32, Lng: 20.82},
+		{Tip: "ferata", ID: 4, Lat: 42.10, Lng: 19.10},
+	}
+	singles, clusters := ClusterPoints(points, 8)
+	if len(clusters) != 1 || clusters[0].Count != 3 || clusters[0].PoTipu["vrh"] != 2 || clusters[0].PoTipu["hotel"] != 1 {
+		t.Fatalf("unexpected clusters %+v", clusters)
+	}
+	if len(singles) != 1 || singles[0].ID != 4 {
+		t.Fatalf("unexpected singles %+v", singles)
+	}
+	if clusters[0].Bounds.MinLat != 43.30 || clusters[0].Bounds.MaxLng != 20.82 {
+		t.Fatalf("unexpected bounds %+v", clusters[0].Bounds)
+	}
+	singles, clusters = ClusterPoints(points, 18)
+	if len(clusters) != 0 || len(singles) != 4 {
+		t.Fatalf("high zoom must not cluster, got %d clusters", len(clusters))
+	}
+}
@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// Point je jedna tačka na mapi (tip entiteta + ID).
+type Point struct {
+	Tip string
+	ID  uint
+	Lat float64
+	Lng float64
+}
+
+// Cluster je grupa tačaka iz iste ćelije mreže; Lat/Lng je težište, Bounds obuhvata sve tačke.
+type Cluster struct {
+	Lat    float64        `json:"lat"`
+	Lng    float64        `json:"lng"`
+	Count  int            `json:"count"`
+	PoTipu map[string]int `json:"poTipu"`
+	Bounds BBox           `json:"bounds"`
+}
+
+// clusterCellsPerTile — ćelija je četvrtina 256px pločice (~64px), kao kod Leaflet.markercluster.
+const clusterCellsPerTile = 4
+
+// ClusterCellDeg vraća veličinu ćelije mreže u stepenima za dati Web Mercator zoom.
+func ClusterCellDeg(zoom int) float64 {
+	if zoom < 0 {
+		zoom = 0
+	}
+	return 360 / math.Exp2(float64(zoom)) / clusterCellsPerTile
+}
+
+// ClusterPoints grupiše tačke po ćelijama mreže; ćelije sa jednom tačkom ostaju pojedinačne tačke.
+// Rezultat je determinisan (klasteri po broju tačaka, tačke po ulaznom redosledu).
+func ClusterPoints(points []Point, zoom int) ([]Point, []Cluster) {
+	cell := ClusterCellDeg(zoom)
+	type cellKey struct{ y, x int64 }
+	groups := make(map[cellKey][]int)
+	order := make([]cellKey, 0)
+	for i, p := range points {
+		k := cellKey{int64(math.Floor((p.Lat + 90) / cell)), int64(math.Floor((p.Lng + 180) / cell))}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], i)
+	}
+
+	singles := make([]Point, 0)
+	clusters := make([]Cluster, 0)
+	for _, k := range order {
+		idx := groups[k]
+		if len(idx) == 1 {
+			singles = append(singles, points[idx[0]])
+			continue
+		}
+		first := points[idx[0]]
+		cl := Cluster{PoTipu: make(map[string]int), Bounds: BBox{MinLat: first.Lat, MaxLat: first.Lat, MinLng: first.Lng, MaxLng: first.Lng}}
+		for _, i := range idx {
+			p := points[i]
+			cl.Lat += p.Lat
+			cl.Lng += p.Lng
+			cl.Count++
+			cl.PoTipu[p.Tip]++
+			cl.Bounds.MinLat = math.Min(cl.Bounds.MinLat, p.Lat)
+			cl.Bounds.MaxLat = math.Max(cl.Bounds.MaxLat, p.Lat)
+			cl.Bounds.MinLng = math.Min(cl.Bounds.MinLng, p.Lng)
+			cl.Bounds.MaxLng = math.Max(cl.Bounds.MaxLng, p.Lng)
+		}
+		cl.Lat /= float64(cl.Count)
+		cl.Lng /= float64(cl.Count)
+		clusters = append(clusters, cl)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].Count > clusters[j].Count })
+	return singles, clusters
+}
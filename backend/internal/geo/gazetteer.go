@@ -0,0 +1,117 @@
+package geo
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"beleg-app/backend/internal/search"
+
+	"gorm.io/gorm"
+)
+
+// gazetteerReverseRadiusKm — koliko daleko od kliknute tačke tražimo vrh/feratu/hotel iz kataloga.
+const gazetteerReverseRadiusKm = 5.0
+
+// Gazetteer je lokalni geokoder nad našim katalogom (aktivni vrhovi, ferate, hoteli sa koordinatama).
+type Gazetteer struct {
+	DB *gorm.DB
+}
+
+type gazetteerSource struct {
+	tip, table, name, sub string
+}
+
+var gazetteerSources = []gazetteerSource{
+	{tip: search.TipVrh, table: "peaks", name: "naziv_vrha", sub: "planina"},
+	{tip: search.TipFerata, table: "ferratas", name: "naziv", sub: "grad_opstina"},
+	{tip: search.TipHotel, table: "hotels", name: "naziv", sub: "adresa"},
+}
+
+type gazetteerRow struct {
+	ID        uint
+	Naziv     string
+	Podnaslov string
+	Lat       float64
+	Lng       float64
+	Rank      float64
+}
+
+func (g Gazetteer) Name() string { return IzvorLocal }
+
+func (g Gazetteer) base(src gazetteerSource) *gorm.DB {
+	return g.DB.Table(src.table).Where("status = ? AND lat IS NOT NULL AND lng IS NOT NULL", "active")
+}
+
+func (g Gazetteer) Search(ctx context.Context, q string, limit int) ([]GeocodeResult, error) {
+	tokens := search.Tokens(q)
+	if len(tokens) == 0 {
+		return []GeocodeResult{}, nil
+	}
+	type ranked struct {
+		r    GeocodeResult
+		rank float64
+	}
+	all := make([]ranked, 0)
+	for _, src := range gazetteerSources {
+		q, rank := search.Match(g.DB, g.base(src).WithContext(ctx), search.Documents[src.tip], tokens)
+		var rows []gazetteerRow
+		if err := q.Select("id, " + src.name + " AS naziv, COALESCE(" + src.sub + ", '') AS podnaslov, lat, lng, " + rank + " AS rank").
+			Order("rank DESC, id ASC").Limit(limit).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			all = append(all, ranked{r: gazetteerResult(src.tip, row), rank: row.Rank + search.TitleBoost(row.Naziv, tokens)})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].rank > all[j].rank })
+	out := make([]GeocodeResult, 0, len(all))
+	for _, x := range all {
+		out = append(out, x.r)
+	}
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// Reverse vraća najbliže stavke kataloga u krugu od 5 km, od najbliže.
+func (g Gazetteer) Reverse(ctx context.Context, lat, lng float64, limit int) ([]GeocodeResult, error) {
+	b := BBoxAround(lat, lng, gazetteerReverseRadiusKm)
+	cond, args := b.SQLWhere("lat", "lng")
+	type near struct {
+		r  GeocodeResult
+		km float64
+	}
+	all := make([]near, 0)
+	for _, src := range gazetteerSources {
+		var rows []gazetteerRow
+		if err := g.base(src).WithContext(ctx).Where(cond, args...).
+			Select("id, " + src.name + " AS naziv, COALESCE(" + src.sub + ", '') AS podnaslov, lat, lng").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if d := DistanceKmHaversine(lat, lng, row.Lat, row.Lng); d <= gazetteerReverseRadiusKm {
+				all = append(all, near{r: gazetteerResult(src.tip, row), km: d})
+			}
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].km < all[j].km })
+	out := make([]GeocodeResult, 0, len(all))
+	for _, x := range all {
+		out = append(out, x.r)
+	}
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func gazetteerResult(tip string, row gazetteerRow) GeocodeResult {
+	name := strings.TrimSpace(row.Naziv)
+	if sub := strings.TrimSpace(row.Podnaslov); sub != "" {
+		name += ", " + sub
+	}
+	return GeocodeResult{Lat: row.Lat, Lng: row.Lng, DisplayName: name, Tip: tip, ID: row.ID, Izvor: IzvorLocal}
+}
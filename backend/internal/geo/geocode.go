@@ -0,0 +1,214 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/search"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	IzvorLocal     = "local"
+	IzvorNominatim = "nominatim"
+	IzvorPhoton    = "photon"
+
+	geocodeCacheTTL = 30 * 24 * time.Hour
+)
+
+// GeocodeResult je jedna pronađena lokacija; ID je popunjen samo za stavke iz našeg kataloga.
+type GeocodeResult struct {
+	Lat         float64 `json:"lat"`
+	Lng         float64 `json:"lng"`
+	DisplayName string  `json:"displayName"`
+	Tip         string  `json:"tip,omitempty"` // vrh/ferata/hotel za katalog, OSM tip za spoljne provajdere
+	ID          uint    `json:"id,omitempty"`
+	Izvor       string  `json:"izvor"`
+}
+
+// Geocoder je provajder pretrage mesta po tekstu i obrnutog geokodiranja (koordinate → mesto).
+type Geocoder interface {
+	Name() string
+	Search(ctx context.Context, q string, limit int) ([]GeocodeResult, error)
+	Reverse(ctx context.Context, lat, lng float64, limit int) ([]GeocodeResult, error)
+}
+
+var (
+	ErrGeocoderUnavailable = errors.New("servis lokacije trenutno nije dostupan")
+	ErrGeocoderThrottled   = errors.New("previše zahteva ka servisu lokacije")
+)
+
+// throttle obezbeđuje minimalni razmak između zahteva ka provajderu (Nominatim: najviše 1 u sekundi).
+type throttle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	maxWait  time.Duration
+}
+
+func (t *throttle) wait(ctx context.Context) error {
+	if t == nil || t.interval <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	now := time.Now()
+	slot := t.next
+	if slot.Before(now) {
+		slot = now
+	}
+	delay := slot.Sub(now)
+	if delay > t.maxWait {
+		t.mu.Unlock()
+		return ErrGeocoderThrottled
+	}
+	t.next = slot.Add(t.interval)
+	t.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// CachedGeocoder čuva odgovore spoljnog provajdera u tabeli geocode_cache (i prazne; greške ne).
+type CachedGeocoder struct {
+	Inner Geocoder
+	DB    *gorm.DB
+	TTL   time.Duration
+}
+
+func (g CachedGeocoder) Name() string { return g.Inner.Name() }
+
+func (g CachedGeocoder) Search(ctx context.Context, q string, limit int) ([]GeocodeResult, error) {
+	key := fmt.Sprintf("%s|s|%d|%s", g.Inner.Name(), limit, search.Fold(q))
+	return g.cached(key, func() ([]GeocodeResult, error) { return g.Inner.Search(ctx, q, limit) })
+}
+
+func (g CachedGeocoder) Reverse(ctx context.Context, lat, lng float64, limit int) ([]GeocodeResult, error) {
+	// 4 decimale ≈ 11 m — dovoljno za klik na mapi, a susedni klikovi dele keš.
+	key := fmt.Sprintf("%s|r|%d|%.4f,%.4f", g.Inner.Name(), limit, lat, lng)
+	return g.cached(key, func() ([]GeocodeResult, error) { return g.Inner.Reverse(ctx, lat, lng, limit) })
+}
+
+func (g CachedGeocoder) cached(key string, load func() ([]GeocodeResult, error)) ([]GeocodeResult, error) {
+	if len(key) > 300 {
+		key = key[:300]
+	}
+	now := time.Now().UTC()
+	var rows []models.GeocodeCache
+	if err := g.DB.Where("kljuc = ? AND expires_at > ?", key, now).Limit(1).Find(&rows).Error; err == nil && len(rows) == 1 {
+		var out []GeocodeResult
+		if json.Unmarshal([]byte(rows[0].ResultsJSON), &out) == nil {
+			return out, nil
+		}
+	}
+	out, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		out = []GeocodeResult{}
+	}
+	ttl := g.TTL
+	if ttl <= 0 {
+		ttl = geocodeCacheTTL
+	}
+	raw, _ := json.Marshal(out)
+	entry := models.GeocodeCache{Kljuc: key, ResultsJSON: string(raw), ExpiresAt: now.Add(ttl), CreatedAt: now}
+	if err := g.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kljuc"}},
+		DoUpdates: clause.AssignmentColumns([]string{"results_json", "expires_at", "created_at"}),
+	}).Create(&entry).Error; err != nil {
+		log.Printf("geocode: cache write %q: %v", key, err)
+	}
+	return out, nil
+}
+
+// MultiGeocoder spaja rezultate više provajdera redom (katalog pre spoljnog).
+// Greška se vraća samo kada nema nijednog rezultata, a bar jedan provajder je pao.
+type MultiGeocoder []Geocoder
+
+func (m MultiGeocoder) Name() string { return "multi" }
+
+func (m MultiGeocoder) Search(ctx context.Context, q string, limit int) ([]GeocodeResult, error) {
+	return m.collect(limit, func(g Geocoder) ([]GeocodeResult, error) { return g.Search(ctx, q, limit) })
+}
+
+func (m MultiGeocoder) Reverse(ctx context.Context, lat, lng float64, limit int) ([]GeocodeResult, error) {
+	return m.collect(limit, func(g Geocoder) ([]GeocodeResult, error) { return g.Reverse(ctx, lat, lng, limit) })
+}
+
+func (m MultiGeocoder) collect(limit int, call func(Geocoder) ([]GeocodeResult, error)) ([]GeocodeResult, error) {
+	out := make([]GeocodeResult, 0)
+	seen := make(map[string]struct{})
+	var firstErr error
+	for _, g := range m {
+		rows, err := call(g)
+		if err != nil {
+			log.Printf("geocode: %s: %v", g.Name(), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, r := range rows {
+			// Isto ime na (skoro) istom mestu iz dva izvora prikazuje se jednom.
+			key := fmt.Sprintf("%.3f,%.3f|%s", r.Lat, r.Lng, search.Fold(strings.SplitN(r.DisplayName, ",", 2)[0]))
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			out = append(out, r)
+		}
+	}
+	if len(out) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// ExternalGeocoderFromEnv bira spoljni provajder (deli se između zahteva zbog throttle-a).
+//
+//   - GEOCODER_PROVIDER=nominatim (podrazumevano) | photon | local (samo katalog, bez spoljnih poziva)
+//   - NOMINATIM_URL (podrazumevano javni OSM server), NOMINATIM_EMAIL — kontakt po Nominatim usage policy
+//   - PHOTON_URL — self-hosted Photon (npr. http://photon:2322); bez njega photon pada nazad na Nominatim
+func ExternalGeocoderFromEnv() Geocoder {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("GEOCODER_PROVIDER"))) {
+	case IzvorLocal:
+		return nil
+	case IzvorPhoton:
+		if u := strings.TrimSpace(os.Getenv("PHOTON_URL")); u != "" {
+			return NewPhoton(u)
+		}
+		log.Printf("geocode: GEOCODER_PROVIDER=photon bez PHOTON_URL — koristi se Nominatim")
+	}
+	return NewNominatim(strings.TrimSpace(os.Getenv("NOMINATIM_URL")), strings.TrimSpace(os.Getenv("NOMINATIM_EMAIL")))
+}
+
+// NewGeocoder vraća lanac: lokalni katalog, pa keširani spoljni provajder (ako postoji).
+func NewGeocoder(db *gorm.DB, external Geocoder) Geocoder {
+	chain := MultiGeocoder{Gazetteer{DB: db}}
+	if external != nil {
+		chain = append(chain, CachedGeocoder{Inner: external, DB: db})
+	}
+	return chain
+}
@@ -0,0 +1,155 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"beleg-app/backend/internal/models"
+	_ "beleg-app/backend/internal/search/searchtest"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type fakeGeocoder struct {
+	calls   int
+	results []GeocodeResult
+	err     error
+}
+
+func (f *fakeGeocoder) Name() string { return "fake" }
+
+func (f *fakeGeocoder) Search(ctx context.Context, q string, limit int) ([]GeocodeResult, error) {
+	f.calls++
+	return f.results, f.err
+}
+
+func (f *fakeGeocoder) Reverse(ctx context.Context, lat, lng float64, limit int) ([]GeocodeResult, error) {
+	f.calls++
+	return f.results, f.err
+}
+
+func testGeocodeDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "geocode")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.GeocodeCache{}, &models.Peak{}, &models.Ferrata{}, &models.Hotel{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestNominatim_SearchAndReverse(t *testing.T) {
+	var gotUA, gotEmail string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA = r.Header.Get("User-Agent")
+		gotEmail = r.URL.Query().Get("email")
+		switch r.URL.Path {
+		case "/search":
+			_, _ = w.Write([]byte(`[{"lat":"43.2736","lon":"20.8126","display_name":"Pančićev vrh, Kopaonik","type":"peak"},{"lat":"x","lon":"1","display_name":"bad"}]`))
+		case "/reverse":
+			_, _ = w.Write([]byte(`{"error":"Unable to geocode"}`))
+		}
+	}))
+	defer srv.Close()
+	n := NewNominatim(srv.URL, "ops@beleg.rs")
+	n.throttle = nil
+
+	res, err := n.Search(context.Background(), "Pančićev vrh", 5)
+	if err != nil || len(res) != 1 || res[0].Lat != 43.2736 || res[0].Izvor != IzvorNominatim {
+		t.Fatalf("unexpected search result %+v err=%v", res, err)
+	}
+	if gotUA == "" || gotEmail != "ops@beleg.rs" {
+		t.Fatalf("usage policy: user agent %q email %q", gotUA, gotEmail)
+	}
+	res, err = n.Reverse(context.Background(), 0, 0, 5)
+	if err != nil || len(res) != 0 {
+		t.Fatalf("reverse without a place must be empty, got %+v err=%v", res, err)
+	}
+}
+
+func TestPhoton_ParsesGeoJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"features":[{"geometry":{"coordinates":[20.8126,43.2736]},"properties":{"name":"Kopaonik","state":"Central Serbia","country":"Serbia","osm_value":"mountain_range"}}]}`))
+	}))
+	defer srv.Close()
+	res, err := NewPhoton(srv.URL).Search(context.Background(), "kopaonik", 5)
+	if err != nil || len(res) != 1 {
+		t.Fatalf("unexpected %+v err=%v", res, err)
+	}
+	if res[0].Lat != 43.2736 || res[0].Lng != 20.8126 || res[0].DisplayName != "Kopaonik, Central Serbia, Serbia" {
+		t.Fatalf("unexpected photon result %+v", res[0])
+	}
+}
+
+func TestThrottle_RejectsWhenQueueTooLong(t *testing.T) {
+	th := &throttle{interval: 10e9, maxWait: 1e9}
+	if err := th.wait(context.Background()); err != nil {
+		t.Fatalf("first request must pass: %v", err)
+	}
+	if err := th.wait(context.Background()); !errors.Is(err, ErrGeocoderThrottled) {
+		t.Fatalf("second request within interval must be throttled, got %v", err)
+	}
+}
+
+func TestCachedGeocoder_PersistsResultsNotErrors(t *testing.T) {
+	db := testGeocodeDB(t)
+	inner := &fakeGeocoder{results: []GeocodeResult{{Lat: 44.8, Lng: 20.4, DisplayName: "Beograd", Izvor: "fake"}}}
+	g := CachedGeocoder{Inner: inner, DB: db}
+	for i := 0; i < 2; i++ {
+		res, err := g.Search(context.Background(), "Београд", 5)
+		if err != nil || len(res) != 1 || res[0].DisplayName != "Beograd" {
+			t.Fatalf("unexpected %+v err=%v", res, err)
+		}
+	}
+	if _, err := g.Search(context.Background(), "beograd", 5); err != nil {
+		t.Fatal(err)
+	}
+	if inner.calls != 1 {
+		t.Fatalf("Cyrillic and Latin queries must share one cache entry, provider called %d times", inner.calls)
+	}
+
+	failing := &fakeGeocoder{err: ErrGeocoderUnavailable}
+	g = CachedGeocoder{Inner: failing, DB: db}
+	_, _ = g.Reverse(context.Background(), 43.3, 21.9, 5)
+	_, _ = g.Reverse(context.Background(), 43.3, 21.9, 5)
+	if failing.calls != 2 {
+		t.Fatalf("errors must not be cached, provider called %d times", failing.calls)
+	}
+}
+
+func TestNewGeocoder_CatalogFirstAndReverse(t *testing.T) {
+	db := testGeocodeDB(t)
+	lat, lng := 43.2736, 20.8126
+	db.Create(&models.Peak{NazivVrha: "Pančićev vrh", Planina: "Kopaonik", Slug: "pancicev-vrh", Status: "active", Lat: &lat, Lng: &lng})
+	db.Create(&models.Hotel{Naziv: "Hotel Grand", Slug: "hotel-grand", Status: "active", Lat: 43.2850, Lng: 20.8000})
+	db.Create(&models.Hotel{Naziv: "Hotel Daleko", Slug: "hotel-daleko", Status: "active", Lat: 44.80, Lng: 20.40})
+	ext := &fakeGeocoder{results: []GeocodeResult{
+		{Lat: 43.27361, Lng: 20.81259, DisplayName: "Pančićev vrh, Raška, Srbija", Izvor: "fake"},
+		{Lat: 43.30, Lng: 20.85, DisplayName: "Brzeće, Srbija", Izvor: "fake"},
+	}}
+	g := NewGeocoder(db, ext)
+
+	res, err := g.Search(context.Background(), "копаоник", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].Izvor != IzvorLocal || res[0].Tip != "vrh" || res[1].DisplayName != "Brzeće, Srbija" {
+		t.Fatalf("expected catalog peak first and the duplicate OSM hit dropped, got %+v", res)
+	}
+
+	ext.results = nil
+	res, err = g.Reverse(context.Background(), 43.2740, 20.8120, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].Tip != "vrh" || res[1].Tip != "hotel" {
+		t.Fatalf("expected nearest catalog items within 5 km, got %+v", res)
+	}
+}
@@ -0,0 +1,118 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultNominatimURL = "https://nominatim.openstreetmap.org"
+	geocodeUserAgent    = "BelegMountaineeringApp/1.0"
+)
+
+// Nominatim — OSM geokoder; javni server dozvoljava najviše 1 zahtev u sekundi sa identifikovanim User-Agent-om.
+type Nominatim struct {
+	BaseURL  string
+	Email    string
+	Client   *http.Client
+	throttle *throttle
+}
+
+// NewNominatim pravi provajdera sa throttle-om od 1 s (prazan baseURL = javni server).
+func NewNominatim(baseURL, email string) *Nominatim {
+	if baseURL == "" {
+		baseURL = defaultNominatimURL
+	}
+	return &Nominatim{
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+		Email:    email,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		throttle: &throttle{interval: time.Second, maxWait: 3 * time.Second},
+	}
+}
+
+type nominatimHit struct {
+	PlaceID     int64  `json:"place_id"`
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+	Error       string `json:"error"`
+}
+
+func (n *Nominatim) Name() string { return IzvorNominatim }
+
+func (n *Nominatim) Search(ctx context.Context, q string, limit int) ([]GeocodeResult, error) {
+	params := url.Values{"format": {"jsonv2"}, "q": {q}, "limit": {strconv.Itoa(limit)}, "accept-language": {"sr-Latn,sr,en"}}
+	var hits []nominatimHit
+	if err := n.get(ctx, "/search", params, &hits); err != nil {
+		return nil, err
+	}
+	return nominatimResults(hits), nil
+}
+
+// Reverse — Nominatim vraća najviše jedno mesto za tačku.
+func (n *Nominatim) Reverse(ctx context.Context, lat, lng float64, limit int) ([]GeocodeResult, error) {
+	params := url.Values{
+		"format":          {"jsonv2"},
+		"lat":             {strconv.FormatFloat(lat, 'f', 6, 64)},
+		"lon":             {strconv.FormatFloat(lng, 'f', 6, 64)},
+		"accept-language": {"sr-Latn,sr,en"},
+	}
+	var hit nominatimHit
+	if err := n.get(ctx, "/reverse", params, &hit); err != nil {
+		return nil, err
+	}
+	if hit.Error != "" {
+		return []GeocodeResult{}, nil
+	}
+	return nominatimResults([]nominatimHit{hit}), nil
+}
+
+func (n *Nominatim) get(ctx context.Context, path string, params url.Values, out any) error {
+	if err := n.throttle.wait(ctx); err != nil {
+		return err
+	}
+	if n.Email != "" {
+		params.Set("email", n.Email)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.BaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", geocodeUserAgent)
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrGeocoderUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ErrGeocoderThrottled
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: nominatim status %d", ErrGeocoderUnavailable, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: nominatim odgovor: %v", ErrGeocoderUnavailable, err)
+	}
+	return nil
+}
+
+func nominatimResults(hits []nominatimHit) []GeocodeResult {
+	out := make([]GeocodeResult, 0, len(hits))
+	for _, h := range hits {
+		lat, e1 := strconv.ParseFloat(h.Lat, 64)
+		lng, e2 := strconv.ParseFloat(h.Lon, 64)
+		if e1 != nil || e2 != nil || !ValidLatLng(lat, lng) {
+			continue
+		}
+		out = append(out, GeocodeResult{Lat: lat, Lng: lng, DisplayName: h.DisplayName, Tip: h.Type, Izvor: IzvorNominatim})
+	}
+	return out
+}
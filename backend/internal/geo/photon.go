@@ -0,0 +1,90 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Photon — self-hosted geokoder nad OSM podacima (GeoJSON odgovor); bez ograničenja javnog servera.
+type Photon struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewPhoton(baseURL string) *Photon {
+	return &Photon{BaseURL: strings.TrimRight(baseURL, "/"), Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type photonResponse struct {
+	Features []struct {
+		Geometry struct {
+			Coordinates []float64 `json:"coordinates"` // [lng, lat]
+		} `json:"geometry"`
+		Properties struct {
+			Name     string `json:"name"`
+			Street   string `json:"street"`
+			City     string `json:"city"`
+			State    string `json:"state"`
+			Country  string `json:"country"`
+			OsmValue string `json:"osm_value"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+func (p *Photon) Name() string { return IzvorPhoton }
+
+func (p *Photon) Search(ctx context.Context, q string, limit int) ([]GeocodeResult, error) {
+	return p.get(ctx, "/api", url.Values{"q": {q}, "limit": {strconv.Itoa(limit)}})
+}
+
+func (p *Photon) Reverse(ctx context.Context, lat, lng float64, limit int) ([]GeocodeResult, error) {
+	return p.get(ctx, "/reverse", url.Values{
+		"lat":   {strconv.FormatFloat(lat, 'f', 6, 64)},
+		"lon":   {strconv.FormatFloat(lng, 'f', 6, 64)},
+		"limit": {strconv.Itoa(limit)},
+	})
+}
+
+func (p *Photon) get(ctx context.Context, path string, params url.Values) ([]GeocodeResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", geocodeUserAgent)
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGeocoderUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: photon status %d", ErrGeocoderUnavailable, resp.StatusCode)
+	}
+	var body photonResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: photon odgovor: %v", ErrGeocoderUnavailable, err)
+	}
+	out := make([]GeocodeResult, 0, len(body.Features))
+	for _, f := range body.Features {
+		if len(f.Geometry.Coordinates) < 2 {
+			continue
+		}
+		lng, lat := f.Geometry.Coordinates[0], f.Geometry.Coordinates[1]
+		if !ValidLatLng(lat, lng) {
+			continue
+		}
+		parts := make([]string, 0, 5)
+		for _, s := range []string{f.Properties.Name, f.Properties.Street, f.Properties.City, f.Properties.State, f.Properties.Country} {
+			if s = strings.TrimSpace(s); s != "" && (len(parts) == 0 || parts[len(parts)-1] != s) {
+				parts = append(parts, s)
+			}
+		}
+		out = append(out, GeocodeResult{Lat: lat, Lng: lng, DisplayName: strings.Join(parts, ", "), Tip: f.Properties.OsmValue, Izvor: IzvorPhoton})
+	}
+	return out, nil
+}
@@ -0,0 +1,165 @@
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+// LatLng je tačka rute u stepenima.
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// PolylineZoomLevels su nivoi zumiranja za koje se čuva pojednostavljena ruta:
+// pregled (kartica u feed-u), region i detalj staze.
+var PolylineZoomLevels = []int{10, 13, 16}
+
+// metersPerPixelZoom0 je rezolucija Web Mercator pločice (256 px) na ekvatoru pri zoom 0.
+const metersPerPixelZoom0 = 156543.03392
+
+// ToleranceMetersForZoom vraća dozvoljeno odstupanje (≈ jedan piksel) pri datom zumu na datoj geografskoj širini.
+func ToleranceMetersForZoom(zoom int, lat float64) float64 {
+	return metersPerPixelZoom0 * math.Cos(lat*math.Pi/180) / math.Pow(2, float64(zoom))
+}
+
+// SimplifyDouglasPeucker uklanja tačke koje od pojednostavljene linije odstupaju manje od toleranceM metara.
+// Prva i poslednja tačka se uvek zadržavaju.
+func SimplifyDouglasPeucker(points []LatLng, toleranceM float64) []LatLng {
+	idx := SimplifyDouglasPeuckerIndices(points, toleranceM)
+	out := make([]LatLng, 0, len(idx))
+	for _, i := range idx {
+		out = append(out, points[i])
+	}
+	return out
+}
+
+// SimplifyDouglasPeuckerIndices vraća indekse zadržanih tačaka (rastuće), da pozivalac zadrži i pridružene podatke (visinu).
+// Radi iterativno (bez rekurzije) zbog ruta od nekoliko desetina hiljada tačaka.
+func SimplifyDouglasPeuckerIndices(points []LatLng, toleranceM float64) []int {
+	if len(points) < 3 || toleranceM <= 0 {
+		idx := make([]int, len(points))
+		for i := range idx {
+			idx[i] = i
+		}
+		return idx
+	}
+	// Lokalna ekvidistantna projekcija u metrima — dovoljno tačna za jednu planinarsku rutu.
+	refLat := points[0].Lat * math.Pi / 180
+	mPerDegLat := kmPerDegreeLat * 1000
+	mPerDegLng := mPerDegLat * math.Cos(refLat)
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = p.Lng * mPerDegLng
+		ys[i] = p.Lat * mPerDegLat
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	type span struct{ from, to int }
+	stack := []span{{0, len(points) - 1}}
+	tol2 := toleranceM * toleranceM
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		maxDist, maxIdx := -1.0, -1
+		for i := s.from + 1; i < s.to; i++ {
+			if d := segmentDistanceSq(xs[i], ys[i], xs[s.from], ys[s.from], xs[s.to], ys[s.to]); d > maxDist {
+				maxDist, maxIdx = d, i
+			}
+		}
+		if maxIdx >= 0 && maxDist > tol2 {
+			keep[maxIdx] = true
+			stack = append(stack, span{s.from, maxIdx}, span{maxIdx, s.to})
+		}
+	}
+	out := make([]int, 0, len(points)/4+2)
+	for i, k := range keep {
+		if k {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func segmentDistanceSq(px, py, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	if dx == 0 && dy == 0 {
+		return (px-ax)*(px-ax) + (py-ay)*(py-ay)
+	}
+	t := ((px-ax)*dx + (py-ay)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	cx, cy := ax+t*dx, ay+t*dy
+	return (px-cx)*(px-cx) + (py-cy)*(py-cy)
+}
+
+// EncodePolyline kodira tačke u Google „encoded polyline“ format (preciznost 1e-5), isti koji čitaju Leaflet i mobilni klijent.
+func EncodePolyline(points []LatLng) string {
+	var b strings.Builder
+	var prevLat, prevLng int64
+	for _, p := range points {
+		lat := int64(math.Round(p.Lat * 1e5))
+		lng := int64(math.Round(p.Lng * 1e5))
+		encodePolylineValue(&b, lat-prevLat)
+		encodePolylineValue(&b, lng-prevLng)
+		prevLat, prevLng = lat, lng
+	}
+	return b.String()
+}
+
+func encodePolylineValue(b *strings.Builder, v int64) {
+	u := uint64(v) << 1
+	if v < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		b.WriteByte(byte((0x20 | (u & 0x1f)) + 63))
+		u >>= 5
+	}
+	b.WriteByte(byte(u + 63))
+}
+
+// DecodePolyline je inverz EncodePolyline; neispravan niz vraća ok=false.
+func DecodePolyline(s string) ([]LatLng, bool) {
+	var out []LatLng
+	var lat, lng int64
+	i := 0
+	next := func() (int64, bool) {
+		var result uint64
+		var shift uint
+		for {
+			if i >= len(s) || shift > 60 {
+				return 0, false
+			}
+			c := uint64(s[i]) - 63
+			i++
+			if c > 0x3f {
+				return 0, false
+			}
+			result |= (c & 0x1f) << shift
+			shift += 5
+			if c < 0x20 {
+				break
+			}
+		}
+		if result&1 != 0 {
+			return ^int64(result >> 1), true
+		}
+		return int64(result >> 1), true
+	}
+	for i < len(s) {
+		dLat, ok := next()
+		if !ok {
+			return nil, false
+		}
+		dLng, ok := next()
+		if !ok {
+			return nil, false
+		}
+		lat += dLat
+		lng += dLng
+		out = append(out, LatLng{Lat: float64(lat) / 1e5, Lng: float64(lng) / 1e5})
+	}
+	return out, true
+}
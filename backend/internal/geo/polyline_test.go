@@ -0,0 +1,42 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncodePolyline_KnownVector(t *testing.T) {
+	// Primer iz Google dokumentacije formata.
+	pts := []LatLng{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	if got := EncodePolyline(pts); got != "_p~iF~ps|U_ulLnnqC_mqNvxq`@" {
+		t.Fatalf("unexpected encoding %q", got)
+	}
+	back, ok := DecodePolyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	if !ok || len(back) != 3 || math.Abs(back[2].Lat-43.252) > 1e-9 || math.Abs(back[2].Lng+126.453) > 1e-9 {
+		t.Fatalf("decode failed: %v %v", back, ok)
+	}
+	if _, ok := DecodePolyline("_p~iF~ps|U_"); ok {
+		t.Fatal("truncated polyline must be rejected")
+	}
+}
+
+func TestSimplifyDouglasPeucker_ZoomLevels(t *testing.T) {
+	// Dve prave deonice (uspon pa spust, vrh ~500 m od osnovice) sa šumom od ~1 m.
+	var pts []LatLng
+	for i := 0; i <= 200; i++ {
+		noise := 0.00001 * float64(i%2)
+		lat := 43.3 + noise + 0.0045*(1-math.Abs(float64(i-100))/100)
+		pts = append(pts, LatLng{Lat: lat, Lng: 21.9 + float64(i)*0.0001})
+	}
+	coarse := SimplifyDouglasPeucker(pts, ToleranceMetersForZoom(PolylineZoomLevels[0], 43.3))
+	if len(coarse) != 3 || coarse[1] != pts[100] {
+		t.Fatalf("overview must keep only ends and the turn, got %d points", len(coarse))
+	}
+	fine := SimplifyDouglasPeucker(pts, 0.5)
+	if len(fine) <= len(coarse) || fine[0] != pts[0] || fine[len(fine)-1] != pts[len(pts)-1] {
+		t.Fatalf("detail level must keep more points and both ends, got %d", len(fine))
+	}
+	if ToleranceMetersForZoom(16, 43.3) >= ToleranceMetersForZoom(10, 43.3) {
+		t.Fatal("higher zoom must use a smaller tolerance")
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"beleg-app/backend/internal/achievements"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func achievementRuleToMap(r achievements.Rule) gin.H {
+	return gin.H{
+		"kod":   r.Kod,
+		"naziv": r.Naziv,
+		"opis":  r.Opis,
+	}
+}
+
+// ListAchievementRules GET /api/bedzevi — katalog svih bedževa.
+func ListAchievementRules(c *gin.Context) {
+	rules := achievements.Rules()
+	out := make([]gin.H, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, achievementRuleToMap(r))
+	}
+	c.JSON(http.StatusOK, gin.H{"bedzevi": out})
+}
+
+// GetPublicKorisnikBedzevi GET /api/korisnici/:id/bedzevi — osvojeni bedževi (ista vidljivost kao javni profil).
+func GetPublicKorisnikBedzevi(c *gin.Context) {
+	db := DB(c)
+	korisnik, err := getVisiblePublicKorisnik(c, db, c.Param("id"))
+	if err != nil {
+		respondPublicKorisnikNotFound(c)
+		return
+	}
+	var rows []models.UserAchievement
+	if err := db.Where("korisnik_id = ?", korisnik.ID).Order("earned_at DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju bedževa"})
+		return
+	}
+	out := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		rule, ok := achievements.RuleByKod(row.Kod)
+		if !ok {
+			// Pravilo uklonjeno iz koda — ne prikazujemo bedž bez opisa.
+			continue
+		}
+		m := achievementRuleToMap(rule)
+		m["earnedAt"] = row.EarnedAt
+		out = append(out, m)
+	}
+	c.JSON(http.StatusOK, gin.H{"bedzevi": out, "ukupno": len(out), "ukupnoMogucih": len(achievements.Rules())})
+}
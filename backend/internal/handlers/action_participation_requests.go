@@ -795,6 +795,9 @@ func RespondToActionParticipationRequest(c *gin.Context) {
 			if err := tx.Save(&targetUser).Error; err != nil {
 				return err
 			}
+			if err := helpers.RecordAkcijaPeakAscentTx(tx, lockedAkcija, lockedReq.TargetUserID); err != nil {
+				return err
+			}
 		}
 
 		lockedReq.Status = models.ActionParticipationRequestAccepted
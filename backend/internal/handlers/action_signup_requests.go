@@ -23,6 +23,8 @@ type actionSignupRequestDTO struct {
 	SelectedSmestajIDs []uint            `json:"selectedSmestajIds"`
 	SelectedPrevozIDs  []uint            `json:"selectedPrevozIds"`
 	SelectedRentItems  []prijavaRentItem `json:"selectedRentItems"`
+	Odgovori           map[string]string `json:"odgovori"`
+	FormaVerzija       int               `json:"formaVerzija"`
 	Requester          gin.H             `json:"requester"`
 	Action             gin.H             `json:"action,omitempty"`
 }
@@ -53,6 +55,8 @@ func buildActionSignupRequestDTO(db *gorm.DB, req models.ActionSignupRequest, in
 		SelectedSmestajIDs: smestaj,
 		SelectedPrevozIDs:  prevoz,
 		SelectedRentItems:  rent,
+		Odgovori:           helpers.ParseFormaOdgovori(req.Odgovori),
+		FormaVerzija:       req.FormaVerzija,
 		Requester: gin.H{
 			"id":           req.Requester.ID,
 			"username":     req.Requester.Username,
@@ -79,7 +83,7 @@ func resolveSignupApprovers(db *gorm.DB, akcija *models.Akcija) []uint {
 		return nil
 	}
 	if akcija.VodicID > 0 {
-		return []uint{akcija.VodicID}
+		return helpers.AkcijaGuideIDs(db, akcija)
 	}
 	if akcija.KlubID == nil || *akcija.KlubID == 0 {
 		return nil
@@ -92,8 +96,8 @@ func resolveSignupApprovers(db *gorm.DB, akcija *models.Akcija) []uint {
 }
 
 func canApproveSignupRequest(c *gin.Context, db *gorm.DB, akcija *models.Akcija) bool {
-	// Canonical action-management policy (isti kao edit/finish/cancel status).
-	return helpers.CanManageAkcijaEx(c, db, akcija)
+	// Canonical action-guiding policy (isti kao finish/status učesnika): upravljanje akcijom ili ko-vodič.
+	return helpers.CanGuideAkcijaEx(c, db, akcija)
 }
 
 func createSignupRequestNotification(db *gorm.DB, req models.ActionSignupRequest) {
@@ -214,6 +218,9 @@ func createPrijavaFromChoicesWithLockedAkcija(tx *gorm.DB, locked *models.Akcija
 	if err := helpers.ValidateAkcijaSignupOpen(locked, time.Now()); err != nil {
 		return models.Prijava{}, err
 	}
+	if err := helpers.CheckAkcijaEligibilityTx(tx, locked, &korisnik); err != nil {
+		return models.Prijava{}, err
+	}
 
 	if err := validatePrijavaChoicesTx(tx, akcijaID, &choices, nil); err != nil {
 		return models.Prijava{}, err
@@ -222,6 +229,12 @@ func createPrijavaFromChoicesWithLockedAkcija(tx *gorm.DB, locked *models.Akcija
 	if err := helpers.EnsureCapacityAvailable(tx, akcijaID, locked.MaxLjudi); err != nil {
 		return models.Prijava{}, err
 	}
+	// Zahtevi pre cenovnika nemaju obračun: cena se računa pri prihvatanju.
+	if choices.Cena == nil {
+		if err := obracunajCenuPrijaveTx(tx, locked, &korisnik, &choices); err != nil {
+			return models.Prijava{}, err
+		}
+	}
 
 	smestajJSON, _ := json.Marshal(choices.SelectedSmestajIDs)
 	prevozJSON, _ := json.Marshal(choices.SelectedPrevozIDs)
@@ -230,6 +243,11 @@ func createPrijavaFromChoicesWithLockedAkcija(tx *gorm.DB, locked *models.Akcija
 		SelectedSmestajIDs:   string(smestajJSON),
 		SelectedPrevozIDs:    string(prevozJSON),
 		SelectedRentItemsRaw: string(rentJSON),
+		Odgovori:             helpers.EncodeFormaOdgovori(choices.Odgovori),
+		FormaVerzija:         choices.FormaVerzija,
+		CenaObracun:          helpers.EncodeCenaObracun(choices.Cena),
+		PromoKodID:           choices.PromoKodID,
+		Grupa:                choices.Grupa,
 	}
 
 	var existing models.Prijava
@@ -265,6 +283,11 @@ func createPrijavaFromChoicesWithLockedAkcija(tx *gorm.DB, locked *models.Akcija
 		SelectedSmestajIDs:   izboriPayload.SelectedSmestajIDs,
 		SelectedPrevozIDs:    izboriPayload.SelectedPrevozIDs,
 		SelectedRentItemsRaw: izboriPayload.SelectedRentItemsRaw,
+		Odgovori:             izboriPayload.Odgovori,
+		FormaVerzija:         izboriPayload.FormaVerzija,
+		CenaObracun:          izboriPayload.CenaObracun,
+		PromoKodID:           izboriPayload.PromoKodID,
+		Grupa:                izboriPayload.Grupa,
 	}
 	if err := tx.Create(&izbor).Error; err != nil {
 		return models.Prijava{}, err
@@ -290,6 +313,11 @@ func parseChoicesFromRequest(c *gin.Context) prijavaChoicesPayload {
 		if raw := strings.TrimSpace(c.PostForm("selectedRentItems")); raw != "" {
 			_ = json.Unmarshal([]byte(raw), &choices.SelectedRentItems)
 		}
+		if raw := strings.TrimSpace(c.PostForm("odgovori")); raw != "" {
+			_ = json.Unmarshal([]byte(raw), &choices.Odgovori)
+		}
+		choices.PromoKod = c.PostForm("promoKod")
+		choices.Grupa = c.PostForm("grupa")
 	}
 	choices.SelectedRentItems = normalizeRentItems(choices.SelectedRentItems)
 	return choices
@@ -459,6 +487,11 @@ func RespondToActionSignupRequest(c *gin.Context) {
 			SelectedSmestajIDs: smestaj,
 			SelectedPrevozIDs:  prevoz,
 			SelectedRentItems:  rent,
+			Odgovori:           helpers.ParseFormaOdgovori(req.Odgovori),
+			FormaVerzija:       req.FormaVerzija,
+			Grupa:              req.Grupa,
+			Cena:               helpers.ParseCenaObracun(req.CenaObracun),
+			PromoKodID:         req.PromoKodID,
 		}
 		var requester models.Korisnik
 		if err := tx.First(&requester, req.RequesterID).Error; err != nil {
@@ -479,6 +512,14 @@ func RespondToActionSignupRequest(c *gin.Context) {
 	})
 	if err != nil {
 		errMsg := err.Error()
+		var uslovi *helpers.UsloviNisuIspunjeniError
+		if errors.As(err, &uslovi) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Član ne ispunjava uslove akcije; dodajte izuzetak za njega pa prihvatite zahtev",
+				"razlozi": uslovi.Razlozi,
+			})
+			return
+		}
 		if errors.Is(err, helpers.ErrAkcijaCapacityFull) ||
 			strings.Contains(errMsg, "popunjen") ||
 			strings.Contains(errMsg, "popunjena") ||
@@ -490,7 +531,7 @@ func RespondToActionSignupRequest(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		if errors.Is(err, helpers.ErrSignupClosed) || errors.Is(err, helpers.ErrAkcijaAlreadyComplete) {
+		if errors.Is(err, helpers.ErrSignupClosed) || errors.Is(err, helpers.ErrAkcijaAlreadyComplete) || errors.Is(err, helpers.ErrAkcijaNotPublished) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
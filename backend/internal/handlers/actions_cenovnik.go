@@ -0,0 +1,370 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var errCenovnikForbidden = errors.New("Samo organizator akcije može da menja cene akcije")
+
+// errPreviewRollback poništava transakciju pregleda cene u GetAkcijaCenovnik.
+var errPreviewRollback = errors.New("preview rollback")
+
+// akcijaCenovnikDTO: cenovnik akcije za klijenta; nil kada ga nema.
+func akcijaCenovnikDTO(c *models.AkcijaCenovnik) gin.H {
+	if helpers.AkcijaCenovnikPrazan(c) {
+		return nil
+	}
+	return gin.H{
+		"cenaDete":            c.CenaDete,
+		"deteDoGodina":        c.DeteDoGodina,
+		"cenaStudent":         c.CenaStudent,
+		"studentDoGodina":     c.StudentDoGodina,
+		"cenaSenior":          c.CenaSenior,
+		"seniorOdGodina":      c.SeniorOdGodina,
+		"earlyBirdDo":         c.EarlyBirdDo,
+		"earlyBirdCenaClan":   c.EarlyBirdCenaClan,
+		"earlyBirdCenaOstali": c.EarlyBirdCenaOstali,
+		"grupaMinOsoba":       c.GrupaMinOsoba,
+		"grupaPopustProcenat": c.GrupaPopustProcenat,
+		"updatedAt":           c.UpdatedAt,
+	}
+}
+
+// GetAkcijaCenovnik GET /akcije/:id/cenovnik?promoKod=LETO&grupa=Porodica — cenovnik i cena koju bi ulogovani član
+// platio da se sada prijavi (konačna cena se računa pri slanju prijave). Organizator dobija
+// i promo kodove sa brojem iskorišćenja.
+func GetAkcijaCenovnik(c *gin.Context) {
+	db := DB(c)
+	viewer, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	if !viewerCanSeeAkcijaDetails(c, db, akcija, viewer) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pristup ovoj akciji"})
+		return
+	}
+	cenovnik := helpers.LoadAkcijaCenovnik(db, akcija.ID)
+	resp := gin.H{
+		"cenaClan":   akcija.CenaClan,
+		"cenaOstali": akcija.CenaOstali,
+		"cenovnik":   akcijaCenovnikDTO(cenovnik),
+	}
+	// ObracunajCenuTx zaključava red promo koda; pregled ga odmah otpušta poništavanjem transakcije.
+	var mojaCena helpers.CenaObracun
+	previewErr := db.Transaction(func(tx *gorm.DB) error {
+		obracun, _, err := helpers.ObracunajCenuTx(tx, *akcija, *viewer, helpers.CenaOpcije{
+			PromoKod: c.Query("promoKod"),
+			Grupa:    c.Query("grupa"),
+			Vreme:    time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		mojaCena = obracun
+		return errPreviewRollback
+	})
+	switch {
+	case errors.Is(previewErr, errPreviewRollback):
+		resp["mojaCena"] = mojaCena
+	case errors.Is(previewErr, helpers.ErrPromoKodNevazeci), errors.Is(previewErr, helpers.ErrPromoKodIskoriscen):
+		bez := helpers.ComputeCenaObracun(*akcija, cenovnik, *viewer, time.Now())
+		resp["mojaCena"] = bez
+		resp["promoKodGreska"] = previewErr.Error()
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri obračunu cene"})
+		return
+	}
+	if helpers.CanManageAkcijaEx(c, db, akcija) {
+		var kodovi []models.AkcijaPromoKod
+		db.Where("akcija_id = ?", akcija.ID).Order("id").Find(&kodovi)
+		out := make([]gin.H, 0, len(kodovi))
+		for _, k := range kodovi {
+			out = append(out, gin.H{
+				"id":             k.ID,
+				"kod":            k.Kod,
+				"popustProcenat": k.PopustProcenat,
+				"popustIznos":    k.PopustIznos,
+				"maxKoriscenja":  k.MaxKoriscenja,
+				"iskoriscenja":   helpers.PromoKodIskoriscenja(db, k.ID, 0),
+				"vaziDo":         k.VaziDo,
+				"aktivan":        k.Aktivan,
+			})
+		}
+		resp["promoKodovi"] = out
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseCenovnikDatum čita "YYYY-MM-DD" kao kraj dana po beogradskom vremenu; prazno je nil.
+func parseCenovnikDatum(raw string) (*time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, true
+	}
+	d, err := time.ParseInLocation("2006-01-02", raw, belgradeLoc())
+	if err != nil {
+		return nil, false
+	}
+	kraj := d.Add(24*time.Hour - time.Second)
+	return &kraj, true
+}
+
+func cenaValidna(v *float64) bool {
+	return v == nil || (*v >= 0 && *v <= 1e7)
+}
+
+// SacuvajAkcijaCenovnik PUT /akcije/:id/cenovnik — body {"cenaDete": 0, "deteDoGodina": 14, "cenaStudent": 1500,
+// "studentDoGodina": 26, "cenaSenior": 1500, "seniorOdGodina": 65, "earlyBirdDo": "2026-05-01", "earlyBirdCenaClan": 1800,
+// "earlyBirdCenaOstali": 2500, "grupaMinOsoba": 4, "grupaPopustProcenat": 10}. Izostavljena cena kategorije = nema kategorije.
+// Važi za nove prijave; već prijavljeni zadržavaju cenu iz CenaObracun.
+func SacuvajAkcijaCenovnik(c *gin.Context) {
+	db := DB(c)
+	actor, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	var req struct {
+		CenaDete            *float64 `json:"cenaDete"`
+		DeteDoGodina        *int     `json:"deteDoGodina"`
+		CenaStudent         *float64 `json:"cenaStudent"`
+		StudentDoGodina     *int     `json:"studentDoGodina"`
+		CenaSenior          *float64 `json:"cenaSenior"`
+		SeniorOdGodina      *int     `json:"seniorOdGodina"`
+		EarlyBirdDo         string   `json:"earlyBirdDo"`
+		EarlyBirdCenaClan   *float64 `json:"earlyBirdCenaClan"`
+		EarlyBirdCenaOstali *float64 `json:"earlyBirdCenaOstali"`
+		GrupaMinOsoba       int      `json:"grupaMinOsoba"`
+		GrupaPopustProcenat float64  `json:"grupaPopustProcenat"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON cenovnika"})
+		return
+	}
+	next := models.AkcijaCenovnik{
+		AkcijaID:            akcija.ID,
+		CenaDete:            req.CenaDete,
+		DeteDoGodina:        14,
+		CenaStudent:         req.CenaStudent,
+		StudentDoGodina:     26,
+		CenaSenior:          req.CenaSenior,
+		SeniorOdGodina:      65,
+		EarlyBirdCenaClan:   req.EarlyBirdCenaClan,
+		EarlyBirdCenaOstali: req.EarlyBirdCenaOstali,
+		GrupaMinOsoba:       req.GrupaMinOsoba,
+		GrupaPopustProcenat: req.GrupaPopustProcenat,
+		IzmenioID:           actor.ID,
+	}
+	if req.DeteDoGodina != nil {
+		next.DeteDoGodina = *req.DeteDoGodina
+	}
+	if req.StudentDoGodina != nil {
+		next.StudentDoGodina = *req.StudentDoGodina
+	}
+	if req.SeniorOdGodina != nil {
+		next.SeniorOdGodina = *req.SeniorOdGodina
+	}
+	for _, v := range []*float64{next.CenaDete, next.CenaStudent, next.CenaSenior, next.EarlyBirdCenaClan, next.EarlyBirdCenaOstali} {
+		if !cenaValidna(v) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cena ne može biti negativna"})
+			return
+		}
+	}
+	if next.DeteDoGodina < 0 || next.StudentDoGodina < next.DeteDoGodina || next.SeniorOdGodina <= next.StudentDoGodina || next.SeniorOdGodina > 120 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Uzrasne granice moraju biti rastuće: dete ≤ student < senior"})
+		return
+	}
+	if next.GrupaMinOsoba < 0 || next.GrupaMinOsoba == 1 || next.GrupaPopustProcenat < 0 || next.GrupaPopustProcenat > 100 ||
+		(next.GrupaMinOsoba > 0) != (next.GrupaPopustProcenat > 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Grupni popust zahteva najmanje 2 osobe i procenat između 0 i 100"})
+		return
+	}
+	earlyBirdDo, ok := parseCenovnikDatum(req.EarlyBirdDo)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći format datuma (YYYY-MM-DD)"})
+		return
+	}
+	if (earlyBirdDo != nil) != (next.EarlyBirdCenaClan != nil || next.EarlyBirdCenaOstali != nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Early bird zahteva datum i bar jednu cenu"})
+		return
+	}
+	next.EarlyBirdDo = earlyBirdDo
+
+	var saved *models.AkcijaCenovnik
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := helpers.LockAkcijaForUpdate(tx, akcija.ID)
+		if err != nil {
+			return err
+		}
+		if !helpers.CanManageAkcijaEx(c, tx, locked) {
+			return errCenovnikForbidden
+		}
+		if locked.IsCancelled {
+			return helpers.ErrAkcijaAlreadyCancelled
+		}
+		if locked.IsCompleted {
+			return helpers.ErrCompletedActionFinancialsImmutable
+		}
+		existing := helpers.LoadAkcijaCenovnik(tx, locked.ID)
+		if helpers.AkcijaCenovnikPrazan(&next) {
+			if existing != nil {
+				return tx.Delete(existing).Error
+			}
+			return nil
+		}
+		if existing != nil {
+			next.ID = existing.ID
+			next.CreatedAt = existing.CreatedAt
+		}
+		if err := tx.Save(&next).Error; err != nil {
+			return err
+		}
+		saved = &next
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errCenovnikForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, helpers.ErrAkcijaAlreadyCancelled), errors.Is(err, helpers.ErrCompletedActionFinancialsImmutable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju cenovnika"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Cenovnik je sačuvan", "cenovnik": akcijaCenovnikDTO(saved)})
+}
+
+// DodajPromoKod POST /akcije/:id/promo-kodovi — body {"kod": "LETO10", "popustProcenat": 10, "popustIznos": 0,
+// "maxKoriscenja": 20, "vaziDo": "2026-06-01"}; procenat ili iznos, maxKoriscenja 0 = neograničeno.
+func DodajPromoKod(c *gin.Context) {
+	db := DB(c)
+	actor, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	if !helpers.CanManageAkcijaEx(c, db, akcija) {
+		c.JSON(http.StatusForbidden, gin.H{"error": errCenovnikForbidden.Error()})
+		return
+	}
+	if akcija.IsCancelled || akcija.IsCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Akcija je otkazana ili završena"})
+		return
+	}
+	var req struct {
+		Kod            string  `json:"kod"`
+		PopustProcenat float64 `json:"popustProcenat"`
+		PopustIznos    float64 `json:"popustIznos"`
+		MaxKoriscenja  int     `json:"maxKoriscenja"`
+		VaziDo         string  `json:"vaziDo"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON promo koda"})
+		return
+	}
+	kod := helpers.NormalizePromoKod(req.Kod)
+	if kod == "" || len(kod) > 40 || strings.ContainsAny(kod, " \t") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kod je obavezan, bez razmaka, do 40 znakova"})
+		return
+	}
+	if req.PopustProcenat < 0 || req.PopustProcenat > 100 || req.PopustIznos < 0 || (req.PopustProcenat == 0 && req.PopustIznos == 0) ||
+		(req.PopustProcenat > 0 && req.PopustIznos > 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unesite popust u procentima (0–100) ili iznos"})
+		return
+	}
+	if req.MaxKoriscenja < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Broj iskorišćenja ne može biti negativan"})
+		return
+	}
+	vaziDo, ok := parseCenovnikDatum(req.VaziDo)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći format datuma (YYYY-MM-DD)"})
+		return
+	}
+	var n int64
+	db.Model(&models.AkcijaPromoKod{}).Where("akcija_id = ? AND kod = ?", akcija.ID, kod).Count(&n)
+	if n > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Kod već postoji za ovu akciju"})
+		return
+	}
+	row := models.AkcijaPromoKod{
+		AkcijaID:       akcija.ID,
+		Kod:            kod,
+		PopustProcenat: req.PopustProcenat,
+		PopustIznos:    req.PopustIznos,
+		MaxKoriscenja:  req.MaxKoriscenja,
+		VaziDo:         vaziDo,
+		Aktivan:        true,
+		KreiraoID:      actor.ID,
+	}
+	if err := db.Create(&row).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju promo koda"})
+		return
+	}
+	c.JSON(http.StatusCreated, row)
+}
+
+// ObrisiPromoKod DELETE /akcije/:id/promo-kodovi/:kodId — iskorišćen kod se samo isključuje (prijave zadržavaju popust).
+func ObrisiPromoKod(c *gin.Context) {
+	db := DB(c)
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	if !helpers.CanManageAkcijaEx(c, db, akcija) {
+		c.JSON(http.StatusForbidden, gin.H{"error": errCenovnikForbidden.Error()})
+		return
+	}
+	kodID, err := strconv.ParseUint(c.Param("kodId"), 10, 32)
+	if err != nil || kodID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID promo koda"})
+		return
+	}
+	var row models.AkcijaPromoKod
+	if err := db.Where("id = ? AND akcija_id = ?", kodID, akcija.ID).First(&row).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Promo kod nije pronađen"})
+		return
+	}
+	var koriscen int64
+	db.Model(&models.PrijavaIzbori{}).Where("promo_kod_id = ?", row.ID).Count(&koriscen)
+	if koriscen == 0 {
+		db.Model(&models.ActionSignupRequest{}).Where("promo_kod_id = ?", row.ID).Count(&koriscen)
+	}
+	if koriscen > 0 {
+		if err := db.Model(&row).Update("aktivan", false).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri isključivanju promo koda"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Promo kod je isključen"})
+		return
+	}
+	if err := db.Delete(&row).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri brisanju promo koda"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Promo kod je obrisan"})
+}
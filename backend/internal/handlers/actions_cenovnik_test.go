@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAkcijaCenovnik_AgreedPriceStoredOnSignup(t *testing.T) {
+	db := testRespondSignupDB(t)
+	vodic := seedRespondApprover(t, db, "cena_vodic")
+	dete := seedRespondRequester(t, db, "cena_dete")
+	rodjen := time.Now().AddDate(-10, 0, 0)
+	db.Model(&dete).Update("datum_rodjenja", rodjen)
+	dete.DatumRodjenja = &rodjen
+	gost := seedRespondRequester(t, db, "cena_gost")
+	akcija := seedRespondAkcija(t, db, vodic, func(a *models.Akcija) { a.CenaClan = 2000; a.CenaOstali = 2000 })
+	id := strconv.FormatUint(uint64(akcija.ID), 10)
+
+	raw, _ := json.Marshal(map[string]any{"cenaDete": 1000, "grupaMinOsoba": 1, "grupaPopustProcenat": 10})
+	w, c := m4ManageCtx(t, db, vodic, http.MethodPut, "/api/akcije/"+id+"/cenovnik", raw)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	SacuvajAkcijaCenovnik(c)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("group of one: %d %s", w.Code, w.Body.String())
+	}
+	raw, _ = json.Marshal(map[string]any{"cenaDete": 1000})
+	w, c = m4ManageCtx(t, db, vodic, http.MethodPut, "/api/akcije/"+id+"/cenovnik", raw)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	SacuvajAkcijaCenovnik(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("save price list: %d %s", w.Code, w.Body.String())
+	}
+	raw, _ = json.Marshal(map[string]any{"kod": "prvi", "popustProcenat": 50, "maxKoriscenja": 1})
+	w, c = m4ManageCtx(t, db, vodic, http.MethodPost, "/api/akcije/"+id+"/promo-kodovi", raw)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	DodajPromoKod(c)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add promo: %d %s", w.Code, w.Body.String())
+	}
+
+	w, c = m4ManageCtx(t, db, dete, http.MethodGet, "/api/akcije/"+id+"/cenovnik?promoKod=PRVI", nil)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	GetAkcijaCenovnik(c)
+	var preview struct {
+		MojaCena    helpers.CenaObracun `json:"mojaCena"`
+		PromoKodovi []any               `json:"promoKodovi"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &preview)
+	if w.Code != http.StatusOK || preview.MojaCena.Ukupno != 500 || preview.PromoKodovi != nil {
+		t.Fatalf("child preview: %d %s", w.Code, w.Body.String())
+	}
+
+	code, body := callPrijaviNaAkcijuJSON(t, db, akcija.ID, dete.Username, map[string]any{"promoKod": "prvi"})
+	if code != http.StatusOK || body["saldo"] != float64(500) {
+		t.Fatalf("child signup: %d %v", code, body)
+	}
+	code, body = callPrijaviNaAkcijuJSON(t, db, akcija.ID, gost.Username, map[string]any{"promoKod": "PRVI"})
+	if code != http.StatusBadRequest {
+		t.Fatalf("exhausted promo: %d %v", code, body)
+	}
+
+	var req models.ActionSignupRequest
+	db.Where("akcija_id = ? AND requester_id = ?", akcija.ID, dete.ID).First(&req)
+	if code, body := callRespondSignup(t, db, akcija.ID, req.ID, vodic, "accept"); code != http.StatusOK {
+		t.Fatalf("accept: %d %v", code, body)
+	}
+	db.Model(&akcija).Update("cena_ostali", 9000)
+	db.Where("akcija_id = ?", akcija.ID).Delete(&models.AkcijaCenovnik{})
+	akcija.CenaOstali = 9000
+
+	var prijava models.Prijava
+	db.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, dete.ID).First(&prijava)
+	var izbor models.PrijavaIzbori
+	db.Where("prijava_id = ?", prijava.ID).First(&izbor)
+	choices, err := helpers.ParticipantChoicesFromIzbori(&izbor)
+	if err != nil || choices.Cena == nil || choices.Cena.Kategorija != helpers.CenaKategorijaDete || izbor.PromoKodID == nil {
+		t.Fatalf("stored breakdown: %+v %v", izbor, err)
+	}
+	if saldo := helpers.ComputeSaldoForParticipant(db, akcija, dete, choices); saldo != 500 {
+		t.Fatalf("later edits must not change agreed price, saldo=%v", saldo)
+	}
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"beleg-app/backend/internal/helpers"
 	"beleg-app/backend/internal/models"
-	"beleg-app/backend/internal/notifications"
 	"beleg-app/backend/middleware"
 	"context"
 	"encoding/json"
@@ -40,29 +39,17 @@ func GetPublicAkcijaByID(jwtSecret []byte) gin.HandlerFunc {
 
 		canSeePrivateDetails := akcija.Javna
 		var viewer *models.Korisnik
+		roleClaim := ""
+		objavljena := helpers.IsAkcijaObjavljena(&akcija)
+		if !akcija.Javna || !objavljena {
+			viewer, roleClaim = publicAkcijaViewerFromToken(c, db, jwtSecret)
+		}
+		// Nacrt, akcija na pregledu i zakazana akcija ne postoje za one koji njima ne upravljaju.
+		if !objavljena && !tokenViewerCanManageAkcija(c, db, &akcija, viewer, roleClaim) {
+			c.JSON(404, gin.H{"error": "Akcija nije pronađena"})
+			return
+		}
 		if !akcija.Javna {
-			tokenStr := middleware.GetTokenFromRequest(c)
-			roleClaim := ""
-			if tokenStr != "" {
-				claims := jwt.MapClaims{}
-				if token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-					if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-						return nil, jwt.ErrSignatureInvalid
-					}
-					return jwtSecret, nil
-				}); err == nil && token.Valid {
-					usernameClaim, _ := claims["username"].(string)
-					roleClaim, _ = claims["role"].(string)
-					usernameClaim = strings.TrimSpace(usernameClaim)
-					if usernameClaim != "" {
-						var viewerUser models.Korisnik
-						if err := helpers.DBWhereUsername(db, usernameClaim).First(&viewerUser).Error; err == nil {
-							viewer = &viewerUser
-						}
-					}
-				}
-			}
-
 			hasInvite := hasValidActionInviteLink(db, akcija.ID, c.Query("inviteToken"))
 			if akcija.KlubID != nil {
 				if hasInvite {
@@ -131,6 +118,12 @@ func GetPublicAkcijaByID(jwtSecret []byte) gin.HandlerFunc {
 			"cenaOstali":               akcija.CenaOstali,
 			"prikaziListuPrijavljenih": akcija.PrikaziListuPrijavljenih,
 			"omoguciGrupniChat":        akcija.OmoguciGrupniChat,
+			"statusObjave":             akcija.StatusObjave,
+			"objaviAt":                 akcija.ObjaviAt,
+			"objavljenaAt":             akcija.ObjavljenaAt,
+		}
+		if !objavljena && akcija.PregledNapomena != "" {
+			resp["pregledNapomena"] = akcija.PregledNapomena
 		}
 		if akcija.PlaninaLat != nil {
 			resp["planinaLat"] = *akcija.PlaninaLat
@@ -168,6 +161,12 @@ func GetPublicAkcijaByID(jwtSecret []byte) gin.HandlerFunc {
 				}
 			}
 		}
+		resp["vodici"] = akcijaVodiciDTO(db, &akcija)
+		forma, formaPolja := helpers.LoadAkcijaForma(db, akcija.ID)
+		resp["forma"] = akcijaFormaDTO(forma, formaPolja, false)
+		resp["uslovi"] = akcijaUsloviDTO(helpers.LoadAkcijaUslovi(db, akcija.ID))
+		resp["cenovnik"] = akcijaCenovnikDTO(helpers.LoadAkcijaCenovnik(db, akcija.ID))
+		resp["politikaPovracaja"] = politikaPovracajaDTO(db, akcija.ID, helpers.EfektivnaPolitikaPovracaja(db, akcija))
 		if akcija.AddedByID > 0 {
 			var a models.Korisnik
 			if db.First(&a, akcija.AddedByID).Error == nil {
@@ -184,6 +183,15 @@ func GetPublicAkcijaByID(jwtSecret []byte) gin.HandlerFunc {
 			return
 		}
 		resp["capacityUsedCount"] = capacityUsedCount
+		if est := estimateAkcijaHikingTime(db, &akcija); est != nil {
+			resp["procenaVremena"] = est
+		}
+		if daylight := helpers.AkcijaDaylightInfo(&akcija); daylight != nil {
+			resp["dnevnoSvetlo"] = daylight
+		}
+		if prognoza := akcijaWeatherForDetail(c.Request.Context(), db, &akcija); prognoza != nil {
+			resp["prognoza"] = prognoza
+		}
 
 		var smestaj []models.AkcijaSmestaj
 		_ = db.Where("akcija_id = ?", akcija.ID).Find(&smestaj).Error
@@ -222,6 +230,7 @@ func GetPublicAkcijaByID(jwtSecret []byte) gin.HandlerFunc {
 					var rentItems []prijavaRentItem
 					_ = json.Unmarshal([]byte(izbor.SelectedRentItemsRaw), &rentItems)
 					choices.SelectedRentItems = rentItemsToHelpers(rentItems)
+					choices.Cena = helpers.ParseCenaObracun(izbor.CenaObracun)
 				}
 			}
 			resp["mojSaldo"] = helpers.ComputeSaldoForParticipant(db, akcija, *viewer, choices)
@@ -230,6 +239,57 @@ func GetPublicAkcijaByID(jwtSecret []byte) gin.HandlerFunc {
 	}
 }
 
+// publicAkcijaViewerFromToken čita opcioni JWT na javnoj ruti detalja akcije (korisnik i uloga iz claim-a).
+func publicAkcijaViewerFromToken(c *gin.Context, db *gorm.DB, jwtSecret []byte) (*models.Korisnik, string) {
+	tokenStr := middleware.GetTokenFromRequest(c)
+	if tokenStr == "" {
+		return nil, ""
+	}
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ""
+	}
+	usernameClaim, _ := claims["username"].(string)
+	roleClaim, _ := claims["role"].(string)
+	usernameClaim = strings.TrimSpace(usernameClaim)
+	if usernameClaim == "" {
+		return nil, roleClaim
+	}
+	var viewerUser models.Korisnik
+	if err := helpers.DBWhereUsername(db, usernameClaim).First(&viewerUser).Error; err != nil {
+		return nil, roleClaim
+	}
+	return &viewerUser, roleClaim
+}
+
+// tokenViewerCanManageAkcija je CanManageAkcijaEx za javnu rutu bez auth middleware-a:
+// autor/vodič/ko-vodič akcije ili admin/vodič kluba domaćina (superadmin preko X-Club-Id).
+func tokenViewerCanManageAkcija(c *gin.Context, db *gorm.DB, akcija *models.Akcija, viewer *models.Korisnik, role string) bool {
+	if viewer == nil {
+		return false
+	}
+	if helpers.IsAkcijaLeader(akcija, viewer.ID) || helpers.IsAkcijaCoGuide(db, akcija.ID, viewer.ID) {
+		return true
+	}
+	if akcija.KlubID == nil {
+		return false
+	}
+	switch role {
+	case "admin", "vodic":
+		return viewer.KlubID != nil && *viewer.KlubID == *akcija.KlubID
+	case "superadmin":
+		selectedClubID, err := strconv.ParseUint(strings.TrimSpace(c.GetHeader("X-Club-Id")), 10, 64)
+		return err == nil && uint(selectedClubID) == *akcija.KlubID
+	}
+	return false
+}
+
 func GetAkcije(c *gin.Context) {
 	dbAny, exists := c.Get("db")
 	if !exists {
@@ -245,7 +305,7 @@ func GetAkcije(c *gin.Context) {
 	}
 	if clubID == 0 {
 		var aktivne []models.Akcija
-		where := "is_completed = ? AND is_cancelled = ? AND (u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND javna = ?"
+		where := "is_completed = ? AND is_cancelled = ? AND (u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND javna = ? AND " + helpers.SQLAkcijaObjavljena
 		if err := gormDb.Preload("Klub").Where(where, false, false, true, true).Find(&aktivne).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju aktivnih akcija"})
 			return
@@ -259,13 +319,14 @@ func GetAkcije(c *gin.Context) {
 		resp := gin.H{"aktivne": aktivne, "zavrsene": []models.Akcija{}}
 		appendGuideOwnedAkcije(gormDb, c, resp)
 		appendMyPrivateAkcije(gormDb, c, resp)
+		appendNeobjavljeneAkcije(gormDb, c, clubID, resp)
 		c.JSON(http.StatusOK, resp)
 		return
 	}
 
 	if strings.EqualFold(strings.TrimSpace(c.Query("scope")), "global") {
 		var aktivne []models.Akcija
-		where := "is_completed = ? AND is_cancelled = ? AND (u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND javna = ?"
+		where := "is_completed = ? AND is_cancelled = ? AND (u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND javna = ? AND " + helpers.SQLAkcijaObjavljena
 		if err := gormDb.Preload("Klub").Where(where, false, false, true, true).Find(&aktivne).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju aktivnih akcija"})
 			return
@@ -279,18 +340,19 @@ func GetAkcije(c *gin.Context) {
 		resp := gin.H{"aktivne": aktivne, "zavrsene": []models.Akcija{}}
 		appendGuideOwnedAkcije(gormDb, c, resp)
 		appendMyPrivateAkcije(gormDb, c, resp)
+		appendNeobjavljeneAkcije(gormDb, c, clubID, resp)
 		c.JSON(http.StatusOK, resp)
 		return
 	}
 
 	var aktivne []models.Akcija
 	var zavrsene []models.Akcija
-	aktivneWhere := "is_completed = ? AND is_cancelled = ? AND (u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND ((klub_id = ? AND " + sqlClubOrganizedOnly + ") OR javna = ?)"
+	aktivneWhere := "is_completed = ? AND is_cancelled = ? AND (u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND ((klub_id = ? AND " + sqlClubOrganizedOnly + ") OR javna = ?) AND " + helpers.SQLAkcijaObjavljena
 	if err := gormDb.Preload("Klub").Where(aktivneWhere, false, false, true, clubID, true).Find(&aktivne).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju aktivnih akcija"})
 		return
 	}
-	zavrseneWhere := "is_completed = ? AND is_cancelled = ? AND (u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND klub_id = ? AND " + sqlClubOrganizedOnly
+	zavrseneWhere := "is_completed = ? AND is_cancelled = ? AND (u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND klub_id = ? AND " + sqlClubOrganizedOnly + " AND " + helpers.SQLAkcijaObjavljena
 	if err := gormDb.Preload("Klub").Where(zavrseneWhere, true, false, true, clubID).Find(&zavrsene).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju završenih akcija"})
 		return
@@ -314,9 +376,31 @@ func GetAkcije(c *gin.Context) {
 	}
 	appendGuideOwnedAkcije(gormDb, c, resp)
 	appendMyPrivateAkcije(gormDb, c, resp)
+	appendNeobjavljeneAkcije(gormDb, c, clubID, resp)
 	c.JSON(http.StatusOK, resp)
 }
 
+// appendNeobjavljeneAkcije dodaje nacrte, akcije na pregledu i zakazane akcije koje gledalac sme da vidi:
+// sopstvene (autor/vodič) i, za admina izabranog kluba, sve neobjavljene akcije tog kluba.
+func appendNeobjavljeneAkcije(db *gorm.DB, c *gin.Context, clubID uint, resp gin.H) {
+	rows := []models.Akcija{}
+	resp["neobjavljene"] = rows
+	viewer, ok := AuthUser(c)
+	if !ok {
+		return
+	}
+	roleVal, _ := c.Get("role")
+	role, _ := roleVal.(string)
+	q := db.Where("is_cancelled = ? AND NOT "+helpers.SQLAkcijaObjavljena, false)
+	if clubID > 0 && (role == "admin" || role == "superadmin") {
+		q = q.Where("(klub_id = ? OR added_by_id = ? OR vodic_id = ?)", clubID, viewer.ID, viewer.ID)
+	} else {
+		q = q.Where("(added_by_id = ? OR vodic_id = ?)", viewer.ID, viewer.ID)
+	}
+	_ = q.Order("datum ASC").Find(&rows).Error
+	resp["neobjavljene"] = rows
+}
+
 func appendGuideOwnedAkcije(db *gorm.DB, c *gin.Context, resp gin.H) {
 	roleVal, _ := c.Get("role")
 	role, _ := roleVal.(string)
@@ -332,10 +416,10 @@ func appendGuideOwnedAkcije(db *gorm.DB, c *gin.Context, resp gin.H) {
 		resp["vodeneZavrsene"] = []models.Akcija{}
 		return
 	}
-	guideBase := "organizator_tip = ? AND vodic_id = ?"
+	guideBase := "organizator_tip = ? AND (vodic_id = ? OR id IN (" + helpers.SQLAkcijaCoGuideIDs + ")) AND " + helpers.SQLAkcijaObjavljena
 	var vodeneAktivne, vodeneZavrsene []models.Akcija
-	_ = db.Where(guideBase+" AND is_completed = ? AND is_cancelled = ?", "vodic", viewer.ID, false, false).Order("datum DESC").Find(&vodeneAktivne).Error
-	_ = db.Where(guideBase+" AND is_completed = ? AND is_cancelled = ?", "vodic", viewer.ID, true, false).Order("datum DESC").Find(&vodeneZavrsene).Error
+	_ = db.Where(guideBase+" AND is_completed = ? AND is_cancelled = ?", "vodic", viewer.ID, viewer.ID, false, false).Order("datum DESC").Find(&vodeneAktivne).Error
+	_ = db.Where(guideBase+" AND is_completed = ? AND is_cancelled = ?", "vodic", viewer.ID, viewer.ID, true, false).Order("datum DESC").Find(&vodeneZavrsene).Error
 	resp["vodeneAktivne"] = vodeneAktivne
 	resp["vodeneZavrsene"] = vodeneZavrsene
 }
@@ -360,11 +444,11 @@ func appendMyPrivateAkcije(db *gorm.DB, c *gin.Context, resp gin.H) {
 		Pluck("akcija_id", &prijavaAkcijaIDs)
 
 	loadPrivate := func(completed bool) []models.Akcija {
-		q := db.Where("javna = ? AND is_completed = ? AND is_cancelled = ?", false, completed, false)
+		q := db.Where("javna = ? AND is_completed = ? AND is_cancelled = ? AND "+helpers.SQLAkcijaObjavljena, false, completed, false)
 		if len(prijavaAkcijaIDs) > 0 {
-			q = q.Where("vodic_id = ? OR id IN ?", viewer.ID, prijavaAkcijaIDs)
+			q = q.Where("vodic_id = ? OR id IN ? OR id IN ("+helpers.SQLAkcijaCoGuideIDs+")", viewer.ID, prijavaAkcijaIDs, viewer.ID)
 		} else {
-			q = q.Where("vodic_id = ?", viewer.ID)
+			q = q.Where("vodic_id = ? OR id IN ("+helpers.SQLAkcijaCoGuideIDs+")", viewer.ID, viewer.ID)
 		}
 		var rows []models.Akcija
 		_ = q.Order("datum DESC").Find(&rows).Error
@@ -659,6 +743,10 @@ func CreateAkcija(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
 		return
 	}
+	if ok, errMsg := applyActionPeakID(c, db, &akcija); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
 
 	if tipAkcije == "via_ferrata" && ferrataIDPtr != nil {
 		var ftVia models.Ferrata
@@ -666,6 +754,10 @@ func CreateAkcija(c *gin.Context) {
 			applyViaFerrataAkcijaDefaults(&akcija, &ftVia)
 		}
 	}
+	if ok, errMsg := applyInitialPublishState(c, db, &akcija, roleStr, time.Now()); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
 
 	var createErr error
 	if err := db.Transaction(func(tx *gorm.DB) error {
@@ -676,7 +768,11 @@ func CreateAkcija(c *gin.Context) {
 		if err := syncActionNestedData(tx, akcija.ID, c); err != nil {
 			return err
 		}
-		return EnsureGuidePrijava(tx, akcija.ID, akcija.VodicID)
+		if err := EnsureGuidePrijava(tx, akcija.ID, akcija.VodicID); err != nil {
+			return err
+		}
+		_, err := helpers.RecordAkcijaVerzijaTx(tx, akcija.ID, nil, models.AkcijaVerzijaIzvorKreiranje, currentUser.ID)
+		return err
 	}); err != nil {
 		if createErr != nil {
 			c.JSON(500, gin.H{"error": "Greška pri čuvanju akcije"})
@@ -694,23 +790,7 @@ func CreateAkcija(c *gin.Context) {
 		return
 	}
 
-	if organizatorTip == "klub" && clubID > 0 {
-		var notifyUserIDs []uint
-		if javna {
-			db.Model(&models.Korisnik{}).Where("klub_id IS NOT NULL").Pluck("id", &notifyUserIDs)
-		} else {
-			db.Model(&models.Korisnik{}).Where("klub_id = ?", clubID).Pluck("id", &notifyUserIDs)
-		}
-		notifications.NotifyUsers(
-			db,
-			notifyUserIDs,
-			models.ObavestenjeTipAkcija,
-			"Nova akcija u kalendaru",
-			akcija.Naziv,
-			notifications.BuildActionNotificationLink(akcija.ID, false),
-			notifications.MarshalMetadata(notifications.ActionNotificationMetadata(akcija.ID, nil)),
-		)
-	}
+	notifyAkcijaPublishState(db, &akcija, currentUser.ID, nil)
 
 	files := form.File["slika"]
 	if len(files) > 0 {
@@ -979,6 +1059,10 @@ func UpdateAkcija(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
 		return
 	}
+	if ok, errMsg := applyActionPeakID(c, db, &akcija); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
 
 	if err := helpers.ValidateMaxLjudiNotBelowActive(db, akcija.ID, akcija.MaxLjudi); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -993,8 +1077,12 @@ func UpdateAkcija(c *gin.Context) {
 	}
 
 	nestedInput := actionNestedSyncInputFromContext(c)
+	actor, _ := AuthUser(c)
+	var izmena *helpers.AkcijaIzmena
 	if err := db.Transaction(func(tx *gorm.DB) error {
-		return executeUpdateAkcijaTx(tx, akcija, nestedInput)
+		var err error
+		izmena, err = executeUpdateAkcijaTx(tx, akcija, nestedInput, actor.ID)
+		return err
 	}); err != nil {
 		if errors.Is(err, ErrNestedOptionInUse) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -1015,6 +1103,7 @@ func UpdateAkcija(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju akcije"})
 		return
 	}
+	notifyMaterialAkcijaChange(db, &akcija, izmena, actor.ID)
 
 	files := form.File["slika"]
 	if len(files) > 0 {
@@ -41,8 +41,8 @@ func DodajClanaPopeoSe(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
 		return
 	}
-	if !helpers.CanManageAkcijaEx(c, db, &akcija) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Samo organizator kluba domaćina može da dodaje članove na ovu akciju"})
+	if !helpers.CanGuideAkcijaEx(c, db, &akcija) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo organizator kluba domaćina ili vodič akcije može da dodaje članove na ovu akciju"})
 		return
 	}
 	if akcija.IsCancelled {
@@ -117,7 +117,7 @@ func BulkAddClubMembersCompleted(c *gin.Context) {
 		return
 	}
 	if !helpers.CanManageAkcijaEx(c, db, &akcija) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Samo organizator kluba domaćina može da dodaje članove na ovu akciju"})
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo organizator kluba domaćina ili vodič akcije može da dodaje članove na ovu akciju"})
 		return
 	}
 	if akcija.IsCancelled {
@@ -208,6 +208,15 @@ func UpdatePrijavaPlatioStatus(c *gin.Context) {
 		if lockedAkcija.IsCompleted && isUnpayTransition {
 			return helpers.ErrCompletedActionPaymentCannotBeUnset
 		}
+		if isUnpayTransition {
+			online, err := helpers.PrijavaImaOnlineUplatu(tx, lockedPrijava.ID)
+			if err != nil {
+				return err
+			}
+			if online {
+				return helpers.ErrPrijavaPlacenaOnline
+			}
+		}
 
 		resultPlatio = currentPaid
 		if currentPaid == requestedPaid {
@@ -216,6 +225,15 @@ func UpdatePrijavaPlatioStatus(c *gin.Context) {
 		}
 
 		lockedPrijava.Platio = requestedPaid
+		// Iznos primljen gotovinom se pamti u trenutku uplate: povraćaj pri otkazu se računa od njega.
+		lockedPrijava.UplacenoGotovinom = nil
+		if requestedPaid {
+			saldo, err := helpers.SaldoPrijaveTx(tx, *lockedAkcija, *lockedPrijava)
+			if err != nil {
+				return err
+			}
+			lockedPrijava.UplacenoGotovinom = &saldo
+		}
 		if err := tx.Save(lockedPrijava).Error; err != nil {
 			return err
 		}
@@ -251,7 +269,7 @@ func UpdatePrijavaPlatioStatus(c *gin.Context) {
 				_ = json.Unmarshal([]byte(izbor.SelectedRentItemsRaw), &selRent)
 			}
 
-			saldo := computeSaldoForParticipant(tx, *lockedAkcija, prijavaWithUser.Korisnik, selSmestaj, selPrevoz, selRent)
+			saldo := computeSaldoForParticipant(tx, *lockedAkcija, prijavaWithUser.Korisnik, selSmestaj, selPrevoz, selRent, helpers.ParseCenaObracun(izbor.CenaObracun))
 
 			if saldo > 0 {
 				recorderID := resolveFinanceRecorderID(tx, lockedAkcija.KlubID, actor.ID)
@@ -282,7 +300,7 @@ func UpdatePrijavaPlatioStatus(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
-		if errors.Is(err, helpers.ErrCompletedActionPaymentCannotBeUnset) {
+		if errors.Is(err, helpers.ErrCompletedActionPaymentCannotBeUnset) || errors.Is(err, helpers.ErrPrijavaPlacenaOnline) {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
@@ -328,7 +346,7 @@ func ZavrsiAkciju(c *gin.Context) {
 	roleVal, _ := c.Get("role")
 	role, _ := roleVal.(string)
 	roleAllowed := role == "admin" || role == "vodic" || role == "superadmin"
-	canManage := helpers.CanManageAkcijaEx(c, db, &akcija)
+	canManage := helpers.CanGuideAkcijaEx(c, db, &akcija)
 
 	// #region agent log
 	helpers.AgentDebugLog("actions_finish.go:ZavrsiAkciju", "finish attempt", "A", "post-fix", map[string]any{
@@ -553,6 +571,8 @@ func OtkaziPrijavuNaAkciju(c *gin.Context) {
 		return
 	}
 
+	potvrdaBezPovracaja := c.Query("potvrdaBezPovracaja") == "true"
+
 	// Preliminary read: samo id/akcija_id radi lock redoslijeda (nije autoritativan).
 	var probe models.Prijava
 	if err := db.Select("id", "akcija_id").
@@ -562,6 +582,8 @@ func OtkaziPrijavuNaAkciju(c *gin.Context) {
 		return
 	}
 
+	bezPenala := false
+	var obaveza *models.ObavezaPovracaja
 	if err := db.Transaction(func(tx *gorm.DB) error {
 		// Autoritativni redoslijed: Akcija → Prijava.
 		lockedAkcija, err := helpers.LockAkcijaForUpdate(tx, uint(akcijaID))
@@ -599,8 +621,32 @@ func OtkaziPrijavuNaAkciju(c *gin.Context) {
 		}
 
 		// Paid guard: samo locked Platio (ne preliminary). Prioritet: lifecycle → status → paid.
+		// Plaćena prijava se otkazuje po politici povraćaja, a bez nje samo posle poskupljenja (BezPenalaDo);
+		// red ostaje radi evidencije uplate, a povraćaj se upisuje kao ObavezaPovracaja. Kada po politici
+		// nema povraćaja (0%), otkaz prolazi samo uz izričitu potvrdu (?potvrdaBezPovracaja=true).
 		if lockedPrijava.Platio {
-			return helpers.ErrPaidPrijavaCannotBeSelfCancelled
+			now := time.Now()
+			bezPenala = helpers.HasPenaltyFreeWithdrawal(lockedPrijava, now)
+			politika := helpers.EfektivnaPolitikaPovracaja(tx, *lockedAkcija)
+			if !bezPenala && politika == nil {
+				return helpers.ErrPaidPrijavaCannotBeSelfCancelled
+			}
+			if !bezPenala && !potvrdaBezPovracaja {
+				pocetak, _ := helpers.AkcijaTimeWindow(lockedAkcija)
+				if politika.ProcenatPovracaja(pocetak, now) <= 0 {
+					return errOtkazBezPovracajaPotvrda
+				}
+			}
+			razlog := models.ObavezaPovracajaOtkazUcesnika
+			if bezPenala {
+				razlog = models.ObavezaPovracajaBezPenala
+			}
+			o, err := helpers.EvidentirajObavezuPovracajaTx(tx, *lockedAkcija, *lockedPrijava, politika, razlog, now)
+			if err != nil {
+				return err
+			}
+			obaveza = o
+			return tx.Model(lockedPrijava).Updates(map[string]any{"status": "otkazano", "bez_penala_do": nil}).Error
 		}
 
 		if err := tx.Delete(lockedPrijava).Error; err != nil {
@@ -627,6 +673,10 @@ func OtkaziPrijavuNaAkciju(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, errOtkazBezPovracajaPotvrda) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "potrebnaPotvrda": true})
+			return
+		}
 		if errors.Is(err, errSelfCancelStatusForbidden) {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
@@ -639,12 +689,19 @@ func OtkaziPrijavuNaAkciju(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Uspešno ste otkazali prijavu"})
+	resp := gin.H{"message": "Uspešno ste otkazali prijavu", "bezPenala": bezPenala}
+	if obaveza != nil {
+		resp["povracaj"] = obavezaPovracajaDTO(obaveza)
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 // errSelfCancelStatusForbidden — postojeća Forbidden poruka za nedozvoljen status self-cancela.
 var errSelfCancelStatusForbidden = errors.New("Ne možete otkazati prijavu nakon što vam je admin potvrdio uspeh ili neuspeh")
 
+// errOtkazBezPovracajaPotvrda — plaćena prijava bi se po politici otkazala bez povraćaja; klijent traži potvrdu.
+var errOtkazBezPovracajaPotvrda = errors.New("Za otkaz u ovom roku nema povraćaja uplate. Potvrdite otkaz bez povraćaja.")
+
 func GetMojePopeoSe(c *gin.Context) {
 	username, exists := c.Get("username")
 	if !exists {
@@ -721,8 +778,8 @@ func UpdatePrijavaStatus(c *gin.Context) {
 		c.JSON(404, gin.H{"error": "Prijava nije pronađena"})
 		return
 	}
-	if !helpers.CanManageAkcijaEx(c, db, &existing.Akcija) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Samo organizator kluba domaćina može da menja status prijava"})
+	if !helpers.CanGuideAkcijaEx(c, db, &existing.Akcija) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo organizator kluba domaćina ili vodič akcije može da menja status prijava"})
 		return
 	}
 
@@ -776,6 +833,13 @@ func UpdatePrijavaStatus(c *gin.Context) {
 			if err := tx.Save(&korisnik).Error; err != nil {
 				return err
 			}
+			if willBePopeoSe {
+				if err := helpers.RecordAkcijaPeakAscentTx(tx, lockedAkcija, korisnik.ID); err != nil {
+					return err
+				}
+			} else if err := helpers.RemoveAkcijaPeakAscentTx(tx, lockedAkcija, korisnik.ID); err != nil {
+				return err
+			}
 			if willBePopeoSe {
 				// Snapshot za post-commit notify — nikad push/network unutar TX.
 				shouldNotifySummit = true
@@ -784,6 +848,13 @@ func UpdatePrijavaStatus(c *gin.Context) {
 			}
 		}
 
+		// Otkaz od strane organizatora: plaćena prijava dobija obavezu povraćaja kao pri otkazivanju akcije.
+		if req.Status == "otkazano" && lockedPrijava.Status != "otkazano" {
+			if _, err := helpers.EvidentirajObavezuPovracajaTx(tx, *lockedAkcija, *lockedPrijava,
+				helpers.EfektivnaPolitikaPovracaja(tx, *lockedAkcija), models.ObavezaPovracajaOtkazAkcije, time.Now()); err != nil {
+				return err
+			}
+		}
 		lockedPrijava.Status = req.Status
 		if err := tx.Save(lockedPrijava).Error; err != nil {
 			return err
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+	"beleg-app/backend/internal/services/actions"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// akcijaFormaDTO: forma prijave za klijenta; arhivirana pitanja vide samo vodiči (export i stari odgovori).
+func akcijaFormaDTO(forma *models.AkcijaForma, polja []helpers.FormaPolje, includeArchived bool) gin.H {
+	if forma == nil {
+		return nil
+	}
+	if !includeArchived {
+		polja = helpers.AktivnaFormaPolja(polja)
+	}
+	return gin.H{"verzija": forma.Verzija, "polja": polja, "updatedAt": forma.UpdatedAt}
+}
+
+// formaNepotpuna: prijavi nedostaje odgovor na obavezno pitanje (npr. dodato posle prijave) — dopunjuje se PATCH moja-prijava.
+func formaNepotpuna(db *gorm.DB, akcijaID uint, odgovori map[string]string) bool {
+	forma, polja := helpers.LoadAkcijaForma(db, akcijaID)
+	return forma != nil && helpers.FormaOdgovoriNepotpuni(polja, odgovori)
+}
+
+// GetAkcijaForma GET /akcije/:id/forma — pitanja pri prijavi; {"forma": null} kada ih akcija nema.
+func GetAkcijaForma(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		return
+	}
+	if !viewerCanSeeAkcijaDetails(c, db, &akcija, user) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pristup ovoj akciji"})
+		return
+	}
+	forma, polja := helpers.LoadAkcijaForma(db, akcija.ID)
+	c.JSON(http.StatusOK, gin.H{"forma": akcijaFormaDTO(forma, polja, helpers.CanGuideAkcijaEx(c, db, &akcija))})
+}
+
+// SacuvajAkcijaFormu PUT /akcije/:id/forma — body {"polja": [{"id": "p1", "label": "...", "tip": "choice", "obavezno": true, "opcije": [...]}]}.
+// Nova pitanja šalju se bez id; prazna lista uklanja formu (pitanja sa odgovorima ostaju arhivirana).
+func SacuvajAkcijaFormu(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	db := DB(c)
+	actor, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	var req struct {
+		Polja []helpers.FormaPolje `json:"polja"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON (očekuje se {\"polja\": [...]})"})
+		return
+	}
+	result, err := actions.SaveSignupForm(db, uint(id), req.Polja, actor.ID, func(tx *gorm.DB, locked *models.Akcija) error {
+		if !helpers.CanManageAkcijaEx(c, tx, locked) {
+			return actions.ErrSignupFormUnauthorized
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, helpers.ErrFormaNevazeca), errors.Is(err, helpers.ErrFormaTipPromenjen):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, actions.ErrSignupFormUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		case errors.Is(err, helpers.ErrAkcijaAlreadyCancelled), errors.Is(err, helpers.ErrAkcijaAlreadyComplete):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju forme prijave"})
+		}
+		return
+	}
+	if len(result.NoviNepotpuni) > 0 {
+		var akcija models.Akcija
+		if db.First(&akcija, id).Error == nil {
+			notifications.NotifySignupFormIncomplete(db, &akcija, result.NoviNepotpuni, result.Forma.Verzija)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Forma prijave je sačuvana",
+		"forma":            akcijaFormaDTO(result.Forma, result.Polja, true),
+		"nepotpunePrijave": len(result.NepotpuniKorisnici),
+	})
+}
+
+// csvCell sprečava da Excel tumači unos korisnika kao formulu.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// ExportPrijaveCSV GET /akcije/:id/prijave.csv — spisak prijavljenih sa odgovorima na formu (vodiči akcije).
+// Kontakt podaci se ne izvoze, kao ni u spisku prijavljenih.
+func ExportPrijaveCSV(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	db := DB(c)
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		return
+	}
+	if !helpers.CanGuideAkcijaEx(c, db, &akcija) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Izvoz prijava je dostupan samo vodičima akcije"})
+		return
+	}
+	var prijave []models.Prijava
+	if err := db.Preload("Korisnik").Where("akcija_id = ?", akcija.ID).Order("prijavljen_at, id").Find(&prijave).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju prijava"})
+		return
+	}
+	_, polja := helpers.LoadAkcijaForma(db, akcija.ID)
+
+	header := []string{"ID prijave", "Korisnik", "Ime i prezime", "Status", "Platio", "Prijavljen"}
+	for _, p := range polja {
+		label := p.Label
+		if p.Arhivirano {
+			label += " (uklonjeno pitanje)"
+		}
+		header = append(header, csvCell(label))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff") // BOM da Excel prepozna UTF-8 (č, ć, š, ž)
+	w := csv.NewWriter(&buf)
+	_ = w.Write(header)
+	for _, pr := range prijave {
+		var izbor models.PrijavaIzbori
+		odgovori := map[string]string{}
+		if db.Where("prijava_id = ?", pr.ID).First(&izbor).Error == nil {
+			odgovori = helpers.ParseFormaOdgovori(izbor.Odgovori)
+		}
+		platio := "ne"
+		if pr.Platio {
+			platio = "da"
+		}
+		row := []string{
+			strconv.FormatUint(uint64(pr.ID), 10),
+			csvCell(pr.Korisnik.Username),
+			csvCell(pr.Korisnik.FullName),
+			pr.Status,
+			platio,
+			pr.PrijavljenAt.In(belgradeLoc()).Format("2006-01-02 15:04"),
+		}
+		for _, p := range polja {
+			v := odgovori[p.ID]
+			if p.Tip != models.FormaPoljeTipNumber {
+				v = csvCell(v)
+			}
+			row = append(row, v)
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri izvozu prijava"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="akcija-%d-prijave.csv"`, akcija.ID))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func callSacuvajAkcijaFormu(t *testing.T, db *gorm.DB, akcijaID uint, actor models.Korisnik, polja []map[string]any) (int, map[string]any) {
+	t.Helper()
+	id := strconv.FormatUint(uint64(akcijaID), 10)
+	body, _ := json.Marshal(map[string]any{"polja": polja})
+	w, c := m4ManageCtx(t, db, actor, http.MethodPut, "/api/akcije/"+id+"/forma", body)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	SacuvajAkcijaFormu(c)
+	var out map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &out)
+	return w.Code, out
+}
+
+func TestSignupForm_AnswersFlowIntoPrijavaAndCSV(t *testing.T) {
+	db := testRespondSignupDB(t)
+	vodic := seedRespondApprover(t, db, "forma_vodic")
+	user := seedRespondRequester(t, db, "forma_clan")
+	akcija := seedRespondAkcija(t, db, vodic)
+
+	code, body := callSacuvajAkcijaFormu(t, db, akcija.ID, vodic, []map[string]any{
+		{"label": "Veličina majice", "tip": "choice", "opcije": []string{"S", "M", "L"}, "obavezno": true},
+		{"label": "Napomena", "tip": "text"},
+	})
+	if code != http.StatusOK {
+		t.Fatalf("save form: %d %v", code, body)
+	}
+	if code, _ := callSacuvajAkcijaFormu(t, db, akcija.ID, user, nil); code != http.StatusForbidden {
+		t.Fatalf("participant must not edit form, got %d", code)
+	}
+
+	code, body = callPrijaviNaAkcijuJSON(t, db, akcija.ID, user.Username, map[string]any{"odgovori": map[string]string{"p2": "vegan"}})
+	if code != http.StatusBadRequest || !strings.Contains(body["error"].(string), "Veličina majice") {
+		t.Fatalf("missing required answer: %d %v", code, body)
+	}
+	code, body = callPrijaviNaAkcijuJSON(t, db, akcija.ID, user.Username, map[string]any{"odgovori": map[string]string{"p1": "M", "p2": "vegan"}})
+	if code != http.StatusOK {
+		t.Fatalf("signup: %d %v", code, body)
+	}
+	var req models.ActionSignupRequest
+	db.Where("akcija_id = ? AND requester_id = ?", akcija.ID, user.ID).First(&req)
+	if req.FormaVerzija != 1 || helpers.ParseFormaOdgovori(req.Odgovori)["p1"] != "M" {
+		t.Fatalf("answers on signup request: %+v", req)
+	}
+	if code, body := callRespondSignup(t, db, akcija.ID, req.ID, vodic, "accept"); code != http.StatusOK {
+		t.Fatalf("accept: %d %v", code, body)
+	}
+	var prijava models.Prijava
+	db.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, user.ID).First(&prijava)
+	var izbor models.PrijavaIzbori
+	db.Where("prijava_id = ?", prijava.ID).First(&izbor)
+	if helpers.ParseFormaOdgovori(izbor.Odgovori)["p2"] != "vegan" {
+		t.Fatalf("answers must move to prijava on accept: %+v", izbor)
+	}
+
+	// Uklonjeno pitanje sa odgovorima se arhivira; novo obavezno pitanje čini prijavu nepotpunom.
+	code, body = callSacuvajAkcijaFormu(t, db, akcija.ID, vodic, []map[string]any{
+		{"id": "p1", "label": "Majica", "tip": "choice", "opcije": []string{"S", "M", "L"}, "obavezno": true},
+		{"label": "Broj cipela", "tip": "number", "obavezno": true},
+	})
+	if code != http.StatusOK || body["nepotpunePrijave"] != float64(1) {
+		t.Fatalf("form change: %d %v", code, body)
+	}
+	var notifs int64
+	db.Model(&models.Obavestenje{}).Where("user_id = ? AND type = ?", user.ID, models.ObavestenjeTipActionFormUpdated).Count(&notifs)
+	if notifs != 1 {
+		t.Fatalf("participant must be asked to complete the form, got %d", notifs)
+	}
+	if code, body := callSacuvajAkcijaFormu(t, db, akcija.ID, vodic, []map[string]any{
+		{"id": "p1", "label": "Majica", "tip": "text"},
+	}); code != http.StatusBadRequest {
+		t.Fatalf("type change of answered question: %d %v", code, body)
+	}
+
+	id := strconv.FormatUint(uint64(akcija.ID), 10)
+	export := func(u models.Korisnik) (int, string) {
+		w, c := m4ManageCtx(t, db, u, http.MethodGet, "/api/akcije/"+id+"/prijave.csv", nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		ExportPrijaveCSV(c)
+		return w.Code, w.Body.String()
+	}
+	if code, _ := export(user); code != http.StatusForbidden {
+		t.Fatalf("participant export: %d", code)
+	}
+	code, csvBody := export(vodic)
+	if code != http.StatusOK {
+		t.Fatalf("export: %d %s", code, csvBody)
+	}
+	lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(csvBody, "\ufeff")), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "Majica,Broj cipela,Napomena (uklonjeno pitanje)") {
+		t.Fatalf("csv header: %q", csvBody)
+	}
+	if !strings.Contains(lines[1], "forma_clan") || !strings.HasSuffix(strings.TrimSpace(lines[1]), "M,,vegan") {
+		t.Fatalf("csv row: %q", lines[1])
+	}
+}
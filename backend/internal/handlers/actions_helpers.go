@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"beleg-app/backend/internal/deltasync"
 	"beleg-app/backend/internal/helpers"
 	"beleg-app/backend/internal/models"
 	"encoding/json"
@@ -47,9 +48,15 @@ type prijavaRentItem struct {
 }
 
 type prijavaChoicesPayload struct {
-	SelectedSmestajIDs []uint            `json:"selectedSmestajIds"`
-	SelectedPrevozIDs  []uint            `json:"selectedPrevozIds"`
-	SelectedRentItems  []prijavaRentItem `json:"selectedRentItems"`
+	SelectedSmestajIDs []uint               `json:"selectedSmestajIds"`
+	SelectedPrevozIDs  []uint               `json:"selectedPrevozIds"`
+	SelectedRentItems  []prijavaRentItem    `json:"selectedRentItems"`
+	Odgovori           map[string]string    `json:"odgovori,omitempty"` // odgovori na formu akcije (id polja → odgovor)
+	FormaVerzija       int                  `json:"-"`
+	PromoKod           string               `json:"promoKod,omitempty"`
+	Grupa              string               `json:"grupa,omitempty"` // naziv grupe za grupni popust
+	Cena               *helpers.CenaObracun `json:"-"`               // obračunata pri slanju prijave (obracunajCenuPrijaveTx)
+	PromoKodID         *uint                `json:"-"`
 }
 
 func normalizeRentItems(items []prijavaRentItem) []prijavaRentItem {
@@ -174,6 +181,45 @@ func validatePrijavaChoicesTx(tx *gorm.DB, akcijaID uint, choices *prijavaChoice
 	return validatePrevozCapacity(tx, akcijaID, choices.SelectedPrevozIDs, excludePrijavaID)
 }
 
+// validatePrijavaFormaTx proverava odgovore na formu akcije (ako je ima) i upisuje očišćene odgovore i verziju forme.
+// Poziva se pri slanju prijave i izmeni izbora, ne i pri prihvatanju zahteva (forma se u međuvremenu mogla promeniti).
+func validatePrijavaFormaTx(tx *gorm.DB, akcijaID uint, choices *prijavaChoicesPayload) error {
+	forma, polja := helpers.LoadAkcijaForma(tx, akcijaID)
+	if forma == nil {
+		choices.Odgovori = nil
+		choices.FormaVerzija = 0
+		return nil
+	}
+	odgovori, err := helpers.ValidateFormaOdgovori(polja, choices.Odgovori)
+	if err != nil {
+		return err
+	}
+	choices.Odgovori = odgovori
+	choices.FormaVerzija = forma.Verzija
+	return nil
+}
+
+// obracunajCenuPrijaveTx računa cenu akcije za prijavu (cenovnik, promo kod, grupa) i upisuje je u choices.
+// Poziva se pri slanju prijave; prihvatanje zahteva koristi već obračunatu cenu.
+func obracunajCenuPrijaveTx(tx *gorm.DB, akcija *models.Akcija, korisnik *models.Korisnik, choices *prijavaChoicesPayload) error {
+	obracun, promo, err := helpers.ObracunajCenuTx(tx, *akcija, *korisnik, helpers.CenaOpcije{
+		PromoKod: choices.PromoKod,
+		Grupa:    choices.Grupa,
+		Vreme:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	choices.Cena = &obracun
+	choices.Grupa = obracun.Grupa
+	choices.PromoKodID = nil
+	if promo != nil {
+		id := promo.ID
+		choices.PromoKodID = &id
+	}
+	return nil
+}
+
 func validatePrevozCapacity(db *gorm.DB, akcijaID uint, prevozIDs []uint, excludePrijavaID *uint) error {
 	if len(prevozIDs) == 0 {
 		return nil
@@ -340,6 +386,31 @@ func parseActionExtras(c *gin.Context, akcija *models.Akcija) (bool, string) {
 	return true, ""
 }
 
+// applyActionPeakID čita opciono peakId iz forme (vrh iz kataloga za dnevnik uspona).
+// Polje koje nije poslato ne menja postojeću vezu; prazna vrednost je uklanja.
+func applyActionPeakID(c *gin.Context, db *gorm.DB, akcija *models.Akcija) (bool, string) {
+	raw, present := c.GetPostForm("peakId")
+	if !present {
+		return true, ""
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		akcija.PeakID = nil
+		return true, ""
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil || id == 0 {
+		return false, "Nevažeći ID vrha"
+	}
+	var count int64
+	if err := db.Model(&models.Peak{}).Where("id = ?", id).Count(&count).Error; err != nil || count == 0 {
+		return false, "Vrh nije pronađen u katalogu"
+	}
+	peakID := uint(id)
+	akcija.PeakID = &peakID
+	return true, ""
+}
+
 func syncActionNestedData(db *gorm.DB, akcijaID uint, c *gin.Context) error {
 	smestajRaw := strings.TrimSpace(c.PostForm("smestajJson"))
 	opremaRaw := strings.TrimSpace(c.PostForm("opremaJson"))
@@ -463,14 +534,16 @@ func choicesPayloadToHelpers(choices prijavaChoicesPayload) helpers.ParticipantC
 		SelectedSmestajIDs: choices.SelectedSmestajIDs,
 		SelectedPrevozIDs:  choices.SelectedPrevozIDs,
 		SelectedRentItems:  rentItemsToHelpers(choices.SelectedRentItems),
+		Cena:               choices.Cena,
 	}
 }
 
-func computeSaldoForParticipant(db *gorm.DB, akcija models.Akcija, korisnik models.Korisnik, smestaj []uint, prevoz []uint, rent []prijavaRentItem) float64 {
+func computeSaldoForParticipant(db *gorm.DB, akcija models.Akcija, korisnik models.Korisnik, smestaj []uint, prevoz []uint, rent []prijavaRentItem, cena *helpers.CenaObracun) float64 {
 	return helpers.ComputeSaldoForParticipant(db, akcija, korisnik, helpers.ParticipantChoices{
 		SelectedSmestajIDs: smestaj,
 		SelectedPrevozIDs:  prevoz,
 		SelectedRentItems:  rentItemsToHelpers(rent),
+		Cena:               cena,
 	})
 }
 
@@ -504,12 +577,12 @@ func viewerIsRegisteredOnAkcija(db *gorm.DB, akcijaID uint, viewerID uint) bool
 	return n > 0
 }
 
-// viewerCanAccessPrivateAkcija: vodič akcije ili prijavljeni učesnik vide pun detalj privatne ture.
+// viewerCanAccessPrivateAkcija: vodič, ko-vodič ili prijavljeni učesnik vide pun detalj privatne ture.
 func viewerCanAccessPrivateAkcija(db *gorm.DB, akcija *models.Akcija, viewer *models.Korisnik) bool {
 	if viewer == nil || akcija == nil {
 		return false
 	}
-	if akcija.VodicID > 0 && akcija.VodicID == viewer.ID {
+	if helpers.IsAkcijaGuide(db, akcija, viewer.ID) {
 		return true
 	}
 	return viewerIsRegisteredOnAkcija(db, akcija.ID, viewer.ID)
@@ -525,6 +598,9 @@ func deleteAkcijaDataTx(tx *gorm.DB, akcijaID uint) error {
 	if err := helpers.ValidateAkcijaCanBeHardDeletedTx(tx, &akcija); err != nil {
 		return err
 	}
+	if err := recordAkcijaDeletedForParticipantsTx(tx, &akcija); err != nil {
+		return err
+	}
 
 	var prijavaIDs []uint
 	if err := tx.Model(&models.Prijava{}).Where("akcija_id = ?", akcijaID).Pluck("id", &prijavaIDs).Error; err != nil {
@@ -563,6 +639,33 @@ func deleteAkcijaDataTx(tx *gorm.DB, akcijaID uint) error {
 		return err
 	}
 
+	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaRuta{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaPomeranje{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaVerzija{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaVodic{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaForma{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaUslovi{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaUslovIzuzetak{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaCenovnik{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaPromoKod{}).Error; err != nil {
+		return err
+	}
 	if err := tx.Where("akcija_id = ?", akcijaID).Delete(&models.AkcijaOpremaRent{}).Error; err != nil {
 		return err
 	}
@@ -581,16 +684,39 @@ func deleteAkcijaDataTx(tx *gorm.DB, akcijaID uint) error {
 	return nil
 }
 
+// recordAkcijaDeletedForParticipantsTx: tombstone nejavne (ili neobjavljene) akcije ide samo klubu, pa ga
+// autor, vodič, ko-vodiči i prijavljeni dobijaju lično — pre nego što se obrišu prijave i ko-vodiči.
+func recordAkcijaDeletedForParticipantsTx(tx *gorm.DB, akcija *models.Akcija) error {
+	if akcija.Javna && helpers.IsAkcijaObjavljena(akcija) {
+		return nil
+	}
+	var userIDs []uint
+	if err := tx.Model(&models.Prijava{}).Where("akcija_id = ?", akcija.ID).Pluck("korisnik_id", &userIDs).Error; err != nil {
+		return err
+	}
+	guideIDs := helpers.AkcijaGuideIDs(tx, akcija)
+	seen := map[uint]bool{0: true}
+	ucesnici := make([]uint, 0, len(userIDs)+len(guideIDs)+1)
+	for _, id := range append(append([]uint{akcija.AddedByID}, guideIDs...), userIDs...) {
+		if !seen[id] {
+			seen[id] = true
+			ucesnici = append(ucesnici, id)
+		}
+	}
+	return deltasync.RecordHidden(tx, deltasync.EntitetAkcija, []uint{akcija.ID}, ucesnici)
+}
+
 // executeUpdateAkcijaTx atomski ažurira akciju, nested podatke i guide prijavu.
 // Za završenu akciju finansijska konfiguracija mora ostati nepromijenjena.
 // Za aktivnu akciju promjena finansijskog snapshot-a resetuje Platio=true prijave.
-func executeUpdateAkcijaTx(tx *gorm.DB, akcija models.Akcija, nestedInput ActionNestedSyncInput) error {
+// Svaka izmena se upisuje u istoriju verzija; poskupljenje daje prijavljenima pravo na odustajanje bez penala.
+func executeUpdateAkcijaTx(tx *gorm.DB, akcija models.Akcija, nestedInput ActionNestedSyncInput, actorID uint) (*helpers.AkcijaIzmena, error) {
 	locked, err := helpers.LockAkcijaForUpdate(tx, akcija.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if locked.IsCancelled {
-		return helpers.ErrAkcijaCancelled
+		return nil, helpers.ErrAkcijaCancelled
 	}
 	wasCompleted := locked.IsCompleted
 	// Preserve lifecycle flags from the locked row — clients cannot set them via update form.
@@ -598,43 +724,82 @@ func executeUpdateAkcijaTx(tx *gorm.DB, akcija models.Akcija, nestedInput Action
 	akcija.IsCancelled = locked.IsCancelled
 	akcija.CancelledAt = locked.CancelledAt
 	akcija.CancellationReason = locked.CancellationReason
+	// Objavljivanje menjaju samo /objavi, /odobri, /odbij i /nacrt.
+	akcija.StatusObjave = locked.StatusObjave
+	akcija.ObjaviAt = locked.ObjaviAt
+	akcija.ObjavljenaAt = locked.ObjavljenaAt
+	akcija.PregledaoID = locked.PregledaoID
+	akcija.PregledNapomena = locked.PregledNapomena
 
 	oldSnapshot, err := helpers.LoadActionFinancialSnapshotTx(tx, akcija.ID, *locked)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	before, err := helpers.LoadAkcijaSnapshotTx(tx, akcija.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := tx.Save(&akcija).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := syncActionNestedDataOnUpdate(tx, akcija.ID, nestedInput); err != nil {
-		return err
+		return nil, err
 	}
 
 	var saved models.Akcija
 	if err := tx.First(&saved, akcija.ID).Error; err != nil {
-		return err
+		return nil, err
 	}
 	newSnapshot, err := helpers.LoadActionFinancialSnapshotTx(tx, akcija.ID, saved)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	financialChanged := !helpers.ActionFinancialSnapshotsEqual(oldSnapshot, newSnapshot)
 
+	if !uintPtrEqual(locked.PeakID, saved.PeakID) {
+		if err := helpers.SyncAkcijaPeakAscentsTx(tx, &saved); err != nil {
+			return nil, err
+		}
+	}
+
 	if wasCompleted {
 		if financialChanged {
-			return helpers.ErrCompletedActionFinancialsImmutable
+			return nil, helpers.ErrCompletedActionFinancialsImmutable
 		}
 	} else if financialChanged {
 		if _, err := helpers.ResetPaidPrijaveForFinancialChangeTx(tx, akcija.ID); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
+	izmena, err := helpers.RecordAkcijaVerzijaTx(tx, akcija.ID, before, models.AkcijaVerzijaIzvorIzmena, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if izmena != nil && izmena.Verzija.PoskupljenjeCene && !wasCompleted {
+		if _, err := helpers.GrantPenaltyFreeWithdrawalTx(tx, &saved, helpers.PenaltyFreeWithdrawalUntil(&saved, time.Now())); err != nil {
+			return nil, err
+		}
+	}
+
+	// Ko-vodič postavljen za glavnog vodiča više nije i ko-vodič.
+	if akcija.VodicID > 0 {
+		if err := tx.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, akcija.VodicID).Delete(&models.AkcijaVodic{}).Error; err != nil {
+			return nil, err
+		}
+	}
 	if err := EnsureGuidePrijava(tx, akcija.ID, akcija.VodicID); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return izmena, nil
+}
+
+func uintPtrEqual(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
 }
 
 // sqlClubOrganizedOnly: klupske akcije (isključuje privatne ture vodiča iz kalendara/istorije kluba).
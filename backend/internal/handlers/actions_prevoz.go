@@ -109,6 +109,10 @@ func DodajPrevozZaAkciju(c *gin.Context) {
 			return errPrevozAddForbidden
 		}
 
+		before, err := helpers.LoadAkcijaSnapshotTx(tx, lockedAkcija.ID)
+		if err != nil {
+			return err
+		}
 		row = models.AkcijaPrevoz{
 			AkcijaID:    lockedAkcija.ID,
 			TipPrevoza:  tip,
@@ -119,6 +123,9 @@ func DodajPrevozZaAkciju(c *gin.Context) {
 		if err := tx.Create(&row).Error; err != nil {
 			return err
 		}
+		if _, err := helpers.RecordAkcijaVerzijaTx(tx, lockedAkcija.ID, before, models.AkcijaVerzijaIzvorPrevoz, korisnik.ID); err != nil {
+			return err
+		}
 
 		if !req.Join {
 			// Čisti insert bez postojećih izbora — nema Platio resetovanja.
@@ -217,6 +224,7 @@ func ObrisiPrevozZaAkciju(c *gin.Context) {
 	prevozID := uint(prevozID64)
 
 	db := DB(c)
+	actor, _ := AuthUser(c)
 
 	if err := db.Transaction(func(tx *gorm.DB) error {
 		lockedAkcija, err := helpers.LockAkcijaForUpdate(tx, uint(akcijaID))
@@ -240,6 +248,11 @@ func ObrisiPrevozZaAkciju(c *gin.Context) {
 			return helpers.ErrPrijavaAkcijaMismatch
 		}
 
+		beforeSnapshot, err := helpers.LoadAkcijaSnapshotTx(tx, lockedAkcija.ID)
+		if err != nil {
+			return err
+		}
+
 		affected, err := findPrijaveSelectingPrevozTx(tx, lockedAkcija.ID, prevozID)
 		if err != nil {
 			return err
@@ -306,6 +319,9 @@ func ObrisiPrevozZaAkciju(c *gin.Context) {
 		if err := tx.Delete(&models.AkcijaPrevoz{}, prev.ID).Error; err != nil {
 			return err
 		}
+		if _, err := helpers.RecordAkcijaVerzijaTx(tx, lockedAkcija.ID, beforeSnapshot, models.AkcijaVerzijaIzvorPrevoz, actor.ID); err != nil {
+			return err
+		}
 
 		for _, st := range states {
 			after, err := helpers.ParticipantChoicesFromIzbori(st.izbor)
@@ -411,7 +427,7 @@ func GetPrevozPrijave(c *gin.Context) {
 		}
 	}
 	if !canSee {
-		canSee = helpers.CanManageAkcijaEx(c, db, &akcija)
+		canSee = helpers.CanGuideAkcijaEx(c, db, &akcija)
 	}
 	if !canSee {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Spisak prevoza nije dostupan"})
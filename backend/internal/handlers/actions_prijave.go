@@ -77,6 +77,11 @@ func PrijaviNaAkciju(c *gin.Context) {
 		return nil
 	}); err != nil {
 		errMsg := err.Error()
+		var uslovi *helpers.UsloviNisuIspunjeniError
+		if errors.As(err, &uslovi) {
+			c.JSON(http.StatusForbidden, gin.H{"error": helpers.ErrUsloviNisuIspunjeni.Error(), "razlozi": uslovi.Razlozi})
+			return
+		}
 		if errors.Is(err, errActionSignupBlocked) {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
@@ -85,7 +90,8 @@ func PrijaviNaAkciju(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		if errors.Is(err, helpers.ErrPendingSignupExists) {
+		if errors.Is(err, helpers.ErrPendingSignupExists) || errors.Is(err, helpers.ErrFormaOdgovor) ||
+			errors.Is(err, helpers.ErrPromoKodNevazeci) || errors.Is(err, helpers.ErrPromoKodIskoriscen) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -98,7 +104,7 @@ func PrijaviNaAkciju(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
 			return
 		}
-		if errors.Is(err, helpers.ErrSignupClosed) || errors.Is(err, helpers.ErrAkcijaAlreadyComplete) {
+		if errors.Is(err, helpers.ErrSignupClosed) || errors.Is(err, helpers.ErrAkcijaAlreadyComplete) || errors.Is(err, helpers.ErrAkcijaNotPublished) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -130,6 +136,7 @@ func PrijaviNaAkciju(c *gin.Context) {
 	signupReq.Requester = korisnik
 	createSignupRequestNotification(db, signupReq)
 
+	choices.Cena = helpers.ParseCenaObracun(signupReq.CenaObracun)
 	saldo := computeSaldoForChoices(db, akcijaForResp, korisnik, choices)
 
 	c.JSON(http.StatusOK, gin.H{
@@ -137,6 +144,7 @@ func PrijaviNaAkciju(c *gin.Context) {
 		"akcijaId":      akcijaID,
 		"signupRequest": gin.H{"id": signupReq.ID, "status": signupReq.Status},
 		"saldo":         saldo,
+		"cena":          choices.Cena,
 	})
 }
 
@@ -167,6 +175,9 @@ func createPendingActionSignupRequestTx(
 	if hasPending {
 		return nil, helpers.ErrPendingSignupExists
 	}
+	if err := helpers.CheckAkcijaEligibilityTx(tx, lockedAkcija, requester); err != nil {
+		return nil, err
+	}
 
 	// Rani capacity guard; pending ne rezerviše mjesto — konačni guard je u acceptu.
 	if err := helpers.EnsureCapacityAvailable(tx, akcijaID, lockedAkcija.MaxLjudi); err != nil {
@@ -176,6 +187,12 @@ func createPendingActionSignupRequestTx(
 	if err := validatePrijavaChoicesTx(tx, akcijaID, &choices, nil); err != nil {
 		return nil, err
 	}
+	if err := validatePrijavaFormaTx(tx, akcijaID, &choices); err != nil {
+		return nil, err
+	}
+	if err := obracunajCenuPrijaveTx(tx, lockedAkcija, requester, &choices); err != nil {
+		return nil, err
+	}
 
 	smestajJSON, _ := json.Marshal(choices.SelectedSmestajIDs)
 	prevozJSON, _ := json.Marshal(choices.SelectedPrevozIDs)
@@ -187,10 +204,21 @@ func createPendingActionSignupRequestTx(
 		SelectedSmestajIDs:   string(smestajJSON),
 		SelectedPrevozIDs:    string(prevozJSON),
 		SelectedRentItemsRaw: string(rentJSON),
+		Odgovori:             helpers.EncodeFormaOdgovori(choices.Odgovori),
+		FormaVerzija:         choices.FormaVerzija,
+		CenaObracun:          helpers.EncodeCenaObracun(choices.Cena),
+		PromoKodID:           choices.PromoKodID,
+		Grupa:                choices.Grupa,
 	}
 	if err := tx.Create(&signupReq).Error; err != nil {
 		return nil, helpers.MapCreateSignupRequestError(err)
 	}
+	if err := helpers.PrimeniGrupniPopustTx(tx, akcijaID, choices.Grupa); err != nil {
+		return nil, err
+	}
+	if err := tx.Select("cena_obracun").First(&signupReq, signupReq.ID).Error; err != nil {
+		return nil, err
+	}
 	return &signupReq, nil
 }
 
@@ -235,13 +263,19 @@ func GetMojaPrijavaZaAkciju(c *gin.Context) {
 			_ = json.Unmarshal([]byte(izbor.SelectedPrevozIDs), &selectedPrevoz)
 			_ = json.Unmarshal([]byte(izbor.SelectedRentItemsRaw), &selectedRent)
 		}
+		odgovori := helpers.ParseFormaOdgovori(izbor.Odgovori)
 		resp["prijava"] = gin.H{
 			"id":                 prijava.ID,
 			"status":             prijava.Status,
 			"prijavljenAt":       prijava.PrijavljenAt,
+			"potvrdaDo":          prijava.PotvrdaDo,
+			"bezPenalaDo":        prijava.BezPenalaDo,
 			"selectedSmestajIds": selectedSmestaj,
 			"selectedPrevozIds":  selectedPrevoz,
 			"selectedRentItems":  selectedRent,
+			"odgovori":           odgovori,
+			"formaNepotpuna":     formaNepotpuna(db, uint(akcijaID), odgovori),
+			"cena":               helpers.ParseCenaObracun(izbor.CenaObracun),
 		}
 	} else {
 		resp["prijava"] = nil
@@ -256,6 +290,8 @@ func GetMojaPrijavaZaAkciju(c *gin.Context) {
 			"selectedSmestajIds": smestaj,
 			"selectedPrevozIds":  prevoz,
 			"selectedRentItems":  rent,
+			"odgovori":           helpers.ParseFormaOdgovori(signupReq.Odgovori),
+			"cena":               helpers.ParseCenaObracun(signupReq.CenaObracun),
 		}
 	} else {
 		resp["signupRequest"] = nil
@@ -282,7 +318,7 @@ func GetPrijaveZaAkciju(c *gin.Context) {
 	}
 	canSeePrijave := false
 	if !akcijaZaPravo.PrikaziListuPrijavljenih {
-		canSeePrijave = helpers.CanManageAkcijaEx(c, db, &akcijaZaPravo)
+		canSeePrijave = helpers.CanGuideAkcijaEx(c, db, &akcijaZaPravo)
 	} else if akcijaZaPravo.Javna {
 		canSeePrijave = true
 	} else if akcijaZaPravo.KlubID != nil {
@@ -359,7 +395,7 @@ func GetPrijaveZaAkciju(c *gin.Context) {
 		if p.Korisnik.ID != 0 && akcijaZaPravo.KlubID != nil && p.Korisnik.KlubID != nil && *p.Korisnik.KlubID == *akcijaZaPravo.KlubID {
 			isClan = true
 		}
-		saldo := computeSaldoForParticipant(db, akcijaZaPravo, p.Korisnik, selSmestaj, selPrevoz, selRent)
+		saldo := computeSaldoForParticipant(db, akcijaZaPravo, p.Korisnik, selSmestaj, selPrevoz, selRent, helpers.ParseCenaObracun(izbor.CenaObracun))
 		out = append(out, PrijavaDTO{
 			ID:                 p.ID,
 			Korisnik:           p.Korisnik.Username,
@@ -453,6 +489,7 @@ func UpdateMojaPrijavaIzbori(c *gin.Context) {
 	}
 
 	var resultPlatio bool
+	var agreedCena *helpers.CenaObracun
 	if err := db.Transaction(func(tx *gorm.DB) error {
 		lockedAkcija, err := helpers.LockAkcijaForUpdate(tx, akcija.ID)
 		if err != nil {
@@ -492,6 +529,25 @@ func UpdateMojaPrijavaIzbori(c *gin.Context) {
 			return fetchErr
 		}
 
+		// Bez "odgovori" u zahtevu ostaju postojeći odgovori (stari klijenti menjaju samo izbore).
+		if payload.Odgovori != nil {
+			if err := validatePrijavaFormaTx(tx, akcija.ID, &payload); err != nil {
+				return err
+			}
+			newChoicesPayload.Odgovori = helpers.EncodeFormaOdgovori(payload.Odgovori)
+			newChoicesPayload.FormaVerzija = payload.FormaVerzija
+		} else if oldIzbor != nil {
+			newChoicesPayload.Odgovori = oldIzbor.Odgovori
+			newChoicesPayload.FormaVerzija = oldIzbor.FormaVerzija
+		}
+		// Cena akcije ostaje ona dogovorena pri prijavi; izmena izbora menja samo logistiku.
+		if oldIzbor != nil {
+			newChoicesPayload.CenaObracun = oldIzbor.CenaObracun
+			newChoicesPayload.PromoKodID = oldIzbor.PromoKodID
+			newChoicesPayload.Grupa = oldIzbor.Grupa
+			agreedCena = helpers.ParseCenaObracun(oldIzbor.CenaObracun)
+		}
+
 		resetPlatio, err := helpers.HasFinancialObligationChangedTx(tx, prijava, oldIzbor, newChoicesPayload)
 		if err != nil {
 			return err
@@ -510,12 +566,16 @@ func UpdateMojaPrijavaIzbori(c *gin.Context) {
 				SelectedSmestajIDs:   newChoicesPayload.SelectedSmestajIDs,
 				SelectedPrevozIDs:    newChoicesPayload.SelectedPrevozIDs,
 				SelectedRentItemsRaw: newChoicesPayload.SelectedRentItemsRaw,
+				Odgovori:             newChoicesPayload.Odgovori,
+				FormaVerzija:         newChoicesPayload.FormaVerzija,
 			}
 			return tx.Create(&izbor).Error
 		}
 		oldIzbor.SelectedSmestajIDs = newChoicesPayload.SelectedSmestajIDs
 		oldIzbor.SelectedPrevozIDs = newChoicesPayload.SelectedPrevozIDs
 		oldIzbor.SelectedRentItemsRaw = newChoicesPayload.SelectedRentItemsRaw
+		oldIzbor.Odgovori = newChoicesPayload.Odgovori
+		oldIzbor.FormaVerzija = newChoicesPayload.FormaVerzija
 		return tx.Save(oldIzbor).Error
 	}); err != nil {
 		errMsg := err.Error()
@@ -523,7 +583,7 @@ func UpdateMojaPrijavaIzbori(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
-		if errors.Is(err, helpers.ErrAkcijaAlreadyComplete) {
+		if errors.Is(err, helpers.ErrAkcijaAlreadyComplete) || errors.Is(err, helpers.ErrFormaOdgovor) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -538,7 +598,7 @@ func UpdateMojaPrijavaIzbori(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju izbora"})
 		return
 	}
-	saldo := computeSaldoForParticipant(db, akcija, korisnik, payload.SelectedSmestajIDs, payload.SelectedPrevozIDs, payload.SelectedRentItems)
+	saldo := computeSaldoForParticipant(db, akcija, korisnik, payload.SelectedSmestajIDs, payload.SelectedPrevozIDs, payload.SelectedRentItems, agreedCena)
 	c.JSON(200, gin.H{
 		"message":            "Izbori sačuvani",
 		"saldo":              saldo,
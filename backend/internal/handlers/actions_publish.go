@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+	"beleg-app/backend/internal/services/actions"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// parseObjaviAt čita opciono vreme objavljivanja (beogradsko, kao startAt); prazno = odmah.
+func parseObjaviAt(raw string) (*time.Time, bool) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, true
+	}
+	t, err := parseViaFerrataStartAt(raw)
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
+}
+
+// applyInitialPublishState postavlja status objave nove akcije iz forme: nacrt=true čuva nacrt,
+// inače važe ista pravila kao POST /akcije/:id/objavi (pregled admina, zakazano vreme, odmah).
+func applyInitialPublishState(c *gin.Context, db *gorm.DB, akcija *models.Akcija, role string, now time.Time) (bool, string) {
+	if strings.ToLower(strings.TrimSpace(c.PostForm("nacrt"))) == "true" {
+		akcija.StatusObjave = models.AkcijaStatusNacrt
+		return true, ""
+	}
+	objaviAt, ok := parseObjaviAt(c.PostForm("objaviAt"))
+	if !ok {
+		return false, "Polje objaviAt nije u ispravnom formatu"
+	}
+	start := akcija.Datum
+	if akcija.StartAt != nil {
+		start = *akcija.StartAt
+	}
+	if objaviAt != nil && !objaviAt.Before(start) {
+		return false, actions.ErrPublishAtInvalid.Error()
+	}
+	akcija.StatusObjave = helpers.ResolvePublishStatus(helpers.AkcijaRequiresClubReview(db, akcija, role), objaviAt, now)
+	if akcija.StatusObjave == models.AkcijaStatusObjavljena {
+		akcija.ObjavljenaAt = &now
+	} else {
+		akcija.ObjaviAt = objaviAt
+	}
+	return true, ""
+}
+
+// notifyAkcijaPublishState posle commita: objavljena → „nova akcija”, na pregledu → admini kluba.
+// reviewerIDs nil znači da se admini čitaju iz baze (kreiranje akcije).
+func notifyAkcijaPublishState(db *gorm.DB, akcija *models.Akcija, actorID uint, reviewerIDs []uint) {
+	switch akcija.StatusObjave {
+	case models.AkcijaStatusObjavljena:
+		notifications.NotifyActionPublished(db, akcija)
+	case models.AkcijaStatusNaPregledu:
+		if reviewerIDs == nil && akcija.KlubID != nil {
+			ids, err := helpers.ClubActionReviewerIDs(db, *akcija.KlubID)
+			if err != nil {
+				log.Printf("akcija %d: admini za pregled: %v", akcija.ID, err)
+			}
+			for _, id := range ids {
+				if id != actorID {
+					reviewerIDs = append(reviewerIDs, id)
+				}
+			}
+		}
+		notifications.NotifyActionReviewRequested(db, akcija, reviewerIDs)
+	}
+}
+
+// authorizeManageAkcija: objavljivanje i povlačenje u nacrt — ko upravlja akcijom (CanManageAkcijaEx).
+func authorizeManageAkcija(c *gin.Context) func(tx *gorm.DB, locked *models.Akcija) error {
+	return func(tx *gorm.DB, locked *models.Akcija) error {
+		if !helpers.CanManageAkcijaEx(c, tx, locked) {
+			return actions.ErrPublishUnauthorized
+		}
+		return nil
+	}
+}
+
+// authorizeReviewAkcija: pregled radi admin kluba domaćina (ili superadmin koji je izabrao taj klub).
+func authorizeReviewAkcija(c *gin.Context) func(tx *gorm.DB, locked *models.Akcija) error {
+	return func(tx *gorm.DB, locked *models.Akcija) error {
+		roleVal, _ := c.Get("role")
+		role, _ := roleVal.(string)
+		if (role != "admin" && role != "superadmin") || !helpers.CanManageAkcija(c, tx, locked.KlubID) {
+			return actions.ErrReviewUnauthorized
+		}
+		return nil
+	}
+}
+
+// respondPublishError mapira greške objavljivanja na HTTP status.
+func respondPublishError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, actions.ErrPublishAtInvalid), errors.Is(err, actions.ErrReviewNoteInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, actions.ErrPublishUnauthorized), errors.Is(err, actions.ErrReviewUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+	case errors.Is(err, actions.ErrPublishAlreadyPublished),
+		errors.Is(err, actions.ErrPublishNotInReview),
+		errors.Is(err, helpers.ErrAkcijaAlreadyCancelled),
+		errors.Is(err, helpers.ErrAkcijaAlreadyComplete):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri objavljivanju akcije"})
+	}
+}
+
+// publishStateMessage je poruka odgovora za novo stanje objave.
+func publishStateMessage(a *models.Akcija) string {
+	switch a.StatusObjave {
+	case models.AkcijaStatusNaPregledu:
+		return "Akcija je poslata adminu kluba na odobrenje."
+	case models.AkcijaStatusZakazana:
+		return "Objavljivanje akcije je zakazano."
+	case models.AkcijaStatusNacrt:
+		return "Akcija je vraćena u nacrt."
+	}
+	return "Akcija je objavljena."
+}
+
+// ObjaviAkciju POST /akcije/:id/objavi — objavljuje nacrt odmah ili u objaviAt (beogradsko vreme);
+// akcija vodiča ide na pregled ako ga klub traži.
+func ObjaviAkciju(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	db := DB(c)
+	actor, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	var req struct {
+		ObjaviAt string `json:"objaviAt"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON (očekuje se npr. {\"objaviAt\": \"2026-11-01T08:00\"})"})
+			return
+		}
+	}
+	objaviAt, ok := parseObjaviAt(req.ObjaviAt)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Polje objaviAt nije u ispravnom formatu"})
+		return
+	}
+	roleVal, _ := c.Get("role")
+	role, _ := roleVal.(string)
+
+	result, err := actions.PublishAction(db, uint(id), actions.PublishActionInput{ObjaviAt: objaviAt, PublisherRole: role},
+		actor.ID, time.Now(), authorizeManageAkcija(c))
+	if err != nil {
+		respondPublishError(c, err)
+		return
+	}
+	notifyAkcijaPublishState(db, result.Akcija, actor.ID, result.ReviewerIDs)
+	c.JSON(http.StatusOK, gin.H{"message": publishStateMessage(result.Akcija), "akcija": result.Akcija})
+}
+
+// OdobriAkciju POST /akcije/:id/odobri — admin kluba odobrava akciju vodiča.
+func OdobriAkciju(c *gin.Context) {
+	reviewAkcija(c, true)
+}
+
+// OdbijAkciju POST /akcije/:id/odbij — admin kluba vraća akciju u nacrt; body {"napomena": "..."}.
+func OdbijAkciju(c *gin.Context) {
+	reviewAkcija(c, false)
+}
+
+func reviewAkcija(c *gin.Context, approve bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	db := DB(c)
+	reviewer, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	var req struct {
+		Napomena string `json:"napomena"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON (očekuje se npr. {\"napomena\": \"...\"})"})
+			return
+		}
+	}
+	result, err := actions.ReviewAction(db, uint(id), approve, req.Napomena, reviewer.ID, time.Now(), authorizeReviewAkcija(c))
+	if err != nil {
+		respondPublishError(c, err)
+		return
+	}
+	notifications.NotifyActionReviewed(db, result.Akcija, result.AuthorID, result.Approved)
+	if result.Akcija.StatusObjave == models.AkcijaStatusObjavljena {
+		notifications.NotifyActionPublished(db, result.Akcija)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": publishStateMessage(result.Akcija), "akcija": result.Akcija})
+}
+
+// VratiAkcijuUNacrt POST /akcije/:id/nacrt — povlači akciju sa pregleda ili otkazuje zakazano objavljivanje.
+func VratiAkcijuUNacrt(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	db := DB(c)
+	akcija, err := actions.ReturnActionToDraft(db, uint(id), authorizeManageAkcija(c))
+	if err != nil {
+		respondPublishError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": publishStateMessage(akcija), "akcija": akcija})
+}
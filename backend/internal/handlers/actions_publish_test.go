@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"beleg-app/backend/internal/models"
+)
+
+func TestGetPublicAkcijaByID_DraftHiddenFromPublic(t *testing.T) {
+	db := testPublicAkcijaDetailDB(t)
+	akcija := createPublicAkcija(t, db, 10)
+	if err := db.Model(&akcija).Update("status_objave", models.AkcijaStatusNacrt).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := callGetPublicAkcijaByID(t, db, akcija.ID); code != http.StatusNotFound {
+		t.Fatalf("draft must not be visible to anonymous viewer, got %d", code)
+	}
+	if err := db.Model(&akcija).Update("status_objave", models.AkcijaStatusObjavljena).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := callGetPublicAkcijaByID(t, db, akcija.ID); code != http.StatusOK {
+		t.Fatalf("published action must be visible, got %d", code)
+	}
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+	"beleg-app/backend/internal/services/actions"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// defaultReconfirmWindow — podrazumevani rok za potvrdu, ali najkasnije 12 h pre novog polaska.
+const defaultReconfirmWindow = 72 * time.Hour
+
+// pomeriAkcijuRequest: startAt/endAt/potvrdaDo su beogradsko vreme (kao startAt ferate), datum/rokPrijava YYYY-MM-DD.
+// Izostavljeni endAt/rokPrijava se pomeraju za isti pomak kao polazak; prazan string ih briše.
+type pomeriAkcijuRequest struct {
+	Datum      string  `json:"datum"`
+	StartAt    string  `json:"startAt"`
+	EndAt      *string `json:"endAt"`
+	RokPrijava *string `json:"rokPrijava"`
+	PotvrdaDo  string  `json:"potvrdaDo"`
+	Reason     string  `json:"reason"`
+}
+
+func shiftTimePtr(t *time.Time, delta time.Duration) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.Add(delta)
+	return &v
+}
+
+// buildRescheduleInput prevodi zahtev u novi termin; vraća poruku greške za 400.
+func buildRescheduleInput(req pomeriAkcijuRequest, akcija *models.Akcija, now time.Time) (actions.RescheduleActionInput, string) {
+	in := actions.RescheduleActionInput{Reason: req.Reason}
+	if raw := strings.TrimSpace(req.StartAt); raw != "" {
+		st, err := parseViaFerrataStartAt(raw)
+		if err != nil {
+			return in, "Polje startAt (datum i vreme polaska) nije u ispravnom formatu"
+		}
+		in.StartAt = &st
+		in.Datum = calendarDatumUTCFromBelgradeClock(st)
+	} else {
+		if akcija.TipAkcije == "via_ferrata" {
+			return in, "Polje startAt (datum i vreme polaska) je obavezno za via ferrata akciju"
+		}
+		datum, err := time.Parse("2006-01-02", strings.TrimSpace(req.Datum))
+		if err != nil {
+			return in, "Datum mora biti YYYY-MM-DD"
+		}
+		in.Datum = datum
+	}
+
+	delta := in.Datum.Sub(akcija.Datum)
+	if in.StartAt != nil && akcija.StartAt != nil {
+		delta = in.StartAt.Sub(*akcija.StartAt)
+	}
+	switch {
+	case req.EndAt == nil:
+		if in.StartAt != nil {
+			in.EndAt = shiftTimePtr(akcija.EndAt, delta)
+		}
+	case strings.TrimSpace(*req.EndAt) != "":
+		end, err := parseViaFerrataStartAt(*req.EndAt)
+		if err != nil {
+			return in, "Polje endAt nije u ispravnom formatu"
+		}
+		in.EndAt = &end
+	}
+	switch {
+	case req.RokPrijava == nil:
+		in.RokPrijava = shiftTimePtr(akcija.RokPrijava, in.Datum.Sub(akcija.Datum))
+	case strings.TrimSpace(*req.RokPrijava) != "":
+		rok, err := time.Parse("2006-01-02", strings.TrimSpace(*req.RokPrijava))
+		if err != nil {
+			return in, "Rok prijava mora biti YYYY-MM-DD"
+		}
+		in.RokPrijava = &rok
+	}
+
+	if raw := strings.TrimSpace(req.PotvrdaDo); raw != "" {
+		deadline, err := parseViaFerrataStartAt(raw)
+		if err != nil {
+			return in, "Polje potvrdaDo nije u ispravnom formatu"
+		}
+		in.PotvrdaDo = deadline
+	} else {
+		start := in.Datum
+		if in.StartAt != nil {
+			start = *in.StartAt
+		}
+		in.PotvrdaDo = now.Add(defaultReconfirmWindow)
+		if latest := start.Add(-12 * time.Hour); latest.Before(in.PotvrdaDo) {
+			in.PotvrdaDo = latest
+		}
+	}
+	return in, ""
+}
+
+// PomeriAkciju POST /akcije/:id/pomeri — pomera termin umesto otkazivanja; prijave ostaju uz obaveznu potvrdu.
+func PomeriAkciju(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	db := DB(c)
+	actor, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	var req pomeriAkcijuRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON (očekuje se npr. {\"datum\": \"2026-11-07\", \"reason\": \"...\"})"})
+		return
+	}
+
+	// Rani auth check (optimization); konačna odluka je nad zaključanom akcijom u service TX.
+	var early models.Akcija
+	if err := db.First(&early, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju akcije"})
+		return
+	}
+	if !helpers.CanManageAkcijaEx(c, db, &early) {
+		c.JSON(http.StatusForbidden, gin.H{"error": actions.ErrRescheduleUnauthorized.Error()})
+		return
+	}
+
+	now := time.Now()
+	in, msg := buildRescheduleInput(req, &early, now)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	result, svcErr := actions.RescheduleAction(db, uint(id), in, actor.ID, now, func(tx *gorm.DB, locked *models.Akcija) error {
+		if !helpers.CanManageAkcijaEx(c, tx, locked) {
+			return actions.ErrRescheduleUnauthorized
+		}
+		return nil
+	})
+	if svcErr != nil {
+		switch {
+		case errors.Is(svcErr, actions.ErrRescheduleReasonInvalid),
+			errors.Is(svcErr, actions.ErrRescheduleInPast),
+			errors.Is(svcErr, actions.ErrRescheduleEndBeforeStart),
+			errors.Is(svcErr, actions.ErrRescheduleRokAfterDatum),
+			errors.Is(svcErr, actions.ErrRescheduleDeadline):
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Error()})
+		case errors.Is(svcErr, actions.ErrRescheduleUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": svcErr.Error()})
+		case errors.Is(svcErr, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		case errors.Is(svcErr, actions.ErrRescheduleUnchanged),
+			errors.Is(svcErr, helpers.ErrAkcijaAlreadyCancelled),
+			errors.Is(svcErr, helpers.ErrAkcijaAlreadyComplete):
+			c.JSON(http.StatusConflict, gin.H{"error": svcErr.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri pomeranju akcije"})
+		}
+		return
+	}
+
+	// Best-effort fan-out nakon uspješnog commita; isti primaoci kao kod otkazivanja.
+	notifications.NotifyActionRescheduled(db, result.Akcija, result.Pomeranje, result.RecipientUserIDs, belgradeLoc())
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Akcija je pomerena. Prijavljeni učesnici su pozvani da potvrde novi termin.",
+		"akcija":    result.Akcija,
+		"pomeranje": result.Pomeranje,
+	})
+}
+
+// PotvrdiNoviTermin POST /akcije/:id/potvrdi-termin — učesnik potvrđuje dolazak na pomereni termin.
+func PotvrdiNoviTermin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	prijava, err := actions.ReconfirmPrijava(db, uint(id), user.ID, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		case errors.Is(err, actions.ErrNoReconfirmPending):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, actions.ErrReconfirmExpired),
+			errors.Is(err, helpers.ErrAkcijaCancelled),
+			errors.Is(err, helpers.ErrAkcijaAlreadyComplete):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri potvrdi termina"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dolazak na novi termin je potvrđen.", "prijava": prijava})
+}
+
+// GetAkcijaPomeranja GET /akcije/:id/pomeranja — istorija pomeranja termina (najnovije prvo).
+func GetAkcijaPomeranja(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		return
+	}
+	if !viewerCanSeeAkcijaDetails(c, db, &akcija, user) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pristup ovoj akciji"})
+		return
+	}
+	var pomeranja []models.AkcijaPomeranje
+	if err := db.Where("akcija_id = ?", akcija.ID).Order("created_at DESC, id DESC").Find(&pomeranja).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju istorije pomeranja"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"akcijaId": akcija.ID, "pomeranja": pomeranja})
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxUsloviSertifikata = 10
+
+var errUsloviForbidden = errors.New("Samo organizator akcije može da menja uslove za prijavu")
+
+// akcijaUsloviDTO: uslovi akcije za klijenta; nil kada ih nema.
+func akcijaUsloviDTO(u *models.AkcijaUslovi) gin.H {
+	if helpers.AkcijaUsloviPrazni(u) {
+		return nil
+	}
+	return gin.H{
+		"minBrojAkcija":    u.MinBrojAkcija,
+		"tipAkcije":        u.TipAkcije,
+		"minTezina":        u.MinTezina,
+		"minVisinaM":       u.MinVisinaM,
+		"clanarinaPlacena": u.ClanarinaPlacena,
+		"sertifikati":      helpers.ParseUsloviSertifikati(u.Sertifikati),
+		"minGodine":        u.MinGodine,
+		"updatedAt":        u.UpdatedAt,
+	}
+}
+
+// loadAkcijaForUslovi učitava akciju iz :id; false kada je odgovor već poslat.
+func loadAkcijaForUslovi(c *gin.Context, db *gorm.DB) (*models.Akcija, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return nil, false
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		return nil, false
+	}
+	return &akcija, true
+}
+
+// GetAkcijaUslovi GET /akcije/:id/uslovi — uslovi za prijavu i da li ih ulogovani član ispunjava (sa razlozima).
+// Organizator dobija i listu izuzetaka.
+func GetAkcijaUslovi(c *gin.Context) {
+	db := DB(c)
+	viewer, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	if !viewerCanSeeAkcijaDetails(c, db, akcija, viewer) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pristup ovoj akciji"})
+		return
+	}
+	u := helpers.LoadAkcijaUslovi(db, akcija.ID)
+	razlozi := helpers.ProveriUsloveAkcije(db, akcija, u, viewer)
+	izuzetak := helpers.HasAkcijaUslovIzuzetak(db, akcija.ID, viewer.ID)
+	resp := gin.H{
+		"uslovi": akcijaUsloviDTO(u),
+		"mojStatus": gin.H{
+			"ispunjeni": len(razlozi) == 0 || izuzetak,
+			"izuzetak":  izuzetak,
+			"razlozi":   razlozi,
+		},
+	}
+	if helpers.CanManageAkcijaEx(c, db, akcija) {
+		var rows []models.AkcijaUslovIzuzetak
+		db.Preload("Korisnik").Where("akcija_id = ?", akcija.ID).Order("id").Find(&rows)
+		izuzeci := make([]gin.H, 0, len(rows))
+		for _, r := range rows {
+			izuzeci = append(izuzeci, gin.H{
+				"korisnikId": r.KorisnikID,
+				"username":   r.Korisnik.Username,
+				"fullName":   r.Korisnik.FullName,
+				"napomena":   r.Napomena,
+				"odobrioId":  r.OdobrioID,
+				"createdAt":  r.CreatedAt,
+			})
+		}
+		resp["izuzeci"] = izuzeci
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SacuvajAkcijaUslove PUT /akcije/:id/uslovi — body {"minBrojAkcija": 3, "tipAkcije": "planina", "minTezina": "tesko",
+// "minVisinaM": 2500, "clanarinaPlacena": true, "sertifikati": ["Alpinistički tečaj"], "minGodine": 18}; sve nule uklanjaju uslove.
+// Važi za nove prijave i prihvatanje zahteva; postojeći učesnici ostaju prijavljeni.
+func SacuvajAkcijaUslove(c *gin.Context) {
+	db := DB(c)
+	actor, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	var req struct {
+		MinBrojAkcija    int      `json:"minBrojAkcija"`
+		TipAkcije        string   `json:"tipAkcije"`
+		MinTezina        string   `json:"minTezina"`
+		MinVisinaM       int      `json:"minVisinaM"`
+		ClanarinaPlacena bool     `json:"clanarinaPlacena"`
+		Sertifikati      []string `json:"sertifikati"`
+		MinGodine        int      `json:"minGodine"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON uslova"})
+		return
+	}
+	req.TipAkcije = strings.ToLower(strings.TrimSpace(req.TipAkcije))
+	req.MinTezina = strings.ToLower(strings.TrimSpace(req.MinTezina))
+	if req.MinBrojAkcija < 0 || req.MinVisinaM < 0 || req.MinVisinaM > 9000 || req.MinGodine < 0 || req.MinGodine > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeće vrednosti uslova"})
+		return
+	}
+	if req.TipAkcije != "" && req.TipAkcije != "planina" && req.TipAkcije != "via_ferrata" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tip akcije mora biti planina ili via_ferrata"})
+		return
+	}
+	if req.MinTezina != "" && !allowedTezine[req.MinTezina] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Težina mora biti lako, srednje, tesko ili alpinizam"})
+		return
+	}
+	sertifikati := make([]string, 0, len(req.Sertifikati))
+	seen := map[string]bool{}
+	for _, s := range req.Sertifikati {
+		s = strings.TrimSpace(s)
+		if s == "" || seen[strings.ToLower(s)] {
+			continue
+		}
+		if len([]rune(s)) > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Naziv sertifikata je predugačak (do 100 znakova)"})
+			return
+		}
+		seen[strings.ToLower(s)] = true
+		sertifikati = append(sertifikati, s)
+	}
+	if len(sertifikati) > maxUsloviSertifikata {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Najviše 10 potrebnih sertifikata"})
+		return
+	}
+	sertJSON, _ := json.Marshal(sertifikati)
+	next := models.AkcijaUslovi{
+		AkcijaID:         akcija.ID,
+		MinBrojAkcija:    req.MinBrojAkcija,
+		TipAkcije:        req.TipAkcije,
+		MinTezina:        req.MinTezina,
+		MinVisinaM:       req.MinVisinaM,
+		ClanarinaPlacena: req.ClanarinaPlacena,
+		Sertifikati:      string(sertJSON),
+		MinGodine:        req.MinGodine,
+		IzmenioID:        actor.ID,
+	}
+
+	var saved *models.AkcijaUslovi
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := helpers.LockAkcijaForUpdate(tx, akcija.ID)
+		if err != nil {
+			return err
+		}
+		if !helpers.CanManageAkcijaEx(c, tx, locked) {
+			return errUsloviForbidden
+		}
+		if locked.IsCancelled {
+			return helpers.ErrAkcijaAlreadyCancelled
+		}
+		if locked.IsCompleted {
+			return helpers.ErrAkcijaAlreadyComplete
+		}
+		existing := helpers.LoadAkcijaUslovi(tx, locked.ID)
+		if helpers.AkcijaUsloviPrazni(&next) {
+			if existing != nil {
+				return tx.Delete(existing).Error
+			}
+			return nil
+		}
+		if existing != nil {
+			next.ID = existing.ID
+			next.CreatedAt = existing.CreatedAt
+		}
+		if err := tx.Save(&next).Error; err != nil {
+			return err
+		}
+		saved = &next
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errUsloviForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, helpers.ErrAkcijaAlreadyCancelled), errors.Is(err, helpers.ErrAkcijaAlreadyComplete):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju uslova akcije"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Uslovi za prijavu su sačuvani", "uslovi": akcijaUsloviDTO(saved)})
+}
+
+// DodajIzuzetakUslova POST /akcije/:id/uslovi/izuzeci — body {"korisnikId": 5, "napomena": "..."}: član se prijavljuje
+// i prihvata bez provere uslova.
+func DodajIzuzetakUslova(c *gin.Context) {
+	db := DB(c)
+	actor, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	if !helpers.CanManageAkcijaEx(c, db, akcija) {
+		c.JSON(http.StatusForbidden, gin.H{"error": errUsloviForbidden.Error()})
+		return
+	}
+	var req struct {
+		KorisnikID uint   `json:"korisnikId"`
+		Napomena   string `json:"napomena"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.KorisnikID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON (očekuje se {\"korisnikId\": 5})"})
+		return
+	}
+	var korisnik models.Korisnik
+	if err := db.Select("id").First(&korisnik, req.KorisnikID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Korisnik nije pronađen"})
+		return
+	}
+	izuzetak := models.AkcijaUslovIzuzetak{AkcijaID: akcija.ID, KorisnikID: korisnik.ID}
+	napomena := strings.TrimSpace(req.Napomena)
+	if err := db.Where(izuzetak).
+		Assign(map[string]any{"odobrio_id": actor.ID, "napomena": napomena}).
+		FirstOrCreate(&izuzetak).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju izuzetka"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Član može da se prijavi bez obzira na uslove", "izuzetak": izuzetak})
+}
+
+// UkloniIzuzetakUslova DELETE /akcije/:id/uslovi/izuzeci/:korisnikId — već prihvaćena prijava ostaje.
+func UkloniIzuzetakUslova(c *gin.Context) {
+	db := DB(c)
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	korisnikID, err := strconv.ParseUint(c.Param("korisnikId"), 10, 32)
+	if err != nil || korisnikID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID korisnika"})
+		return
+	}
+	if !helpers.CanManageAkcijaEx(c, db, akcija) {
+		c.JSON(http.StatusForbidden, gin.H{"error": errUsloviForbidden.Error()})
+		return
+	}
+	res := db.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, korisnikID).Delete(&models.AkcijaUslovIzuzetak{})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri uklanjanju izuzetka"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Član nema izuzetak za ovu akciju"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Izuzetak je uklonjen"})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func callAkcijaUslovi(t *testing.T, db *gorm.DB, akcijaID uint, actor models.Korisnik, method string, payload any, handler gin.HandlerFunc) (int, map[string]any) {
+	t.Helper()
+	id := strconv.FormatUint(uint64(akcijaID), 10)
+	var body []byte
+	if payload != nil {
+		body, _ = json.Marshal(payload)
+	}
+	w, c := m4ManageCtx(t, db, actor, method, "/api/akcije/"+id+"/uslovi", body)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	handler(c)
+	var out map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &out)
+	return w.Code, out
+}
+
+func TestAkcijaUslovi_SignupBlockedWithReasonsUntilOverride(t *testing.T) {
+	db := testRespondSignupDB(t)
+	if err := db.AutoMigrate(&models.KorisnikSertifikat{}); err != nil {
+		t.Fatal(err)
+	}
+	vodic := seedRespondApprover(t, db, "uslovi_vodic")
+	pocetnik := seedRespondRequester(t, db, "uslovi_pocetnik")
+	ranije := seedRespondRequester(t, db, "uslovi_ranije")
+	akcija := seedRespondAkcija(t, db, vodic)
+	pending := seedPendingSignup(t, db, akcija.ID, ranije.ID, "", "", "")
+
+	if code, body := callAkcijaUslovi(t, db, akcija.ID, pocetnik, http.MethodPut, map[string]any{"minBrojAkcija": 1}, SacuvajAkcijaUslove); code != http.StatusForbidden {
+		t.Fatalf("member must not edit rules: %d %v", code, body)
+	}
+	if code, body := callAkcijaUslovi(t, db, akcija.ID, vodic, http.MethodPut, map[string]any{"minTezina": "ekstremno"}, SacuvajAkcijaUslove); code != http.StatusBadRequest {
+		t.Fatalf("unknown difficulty: %d %v", code, body)
+	}
+	code, body := callAkcijaUslovi(t, db, akcija.ID, vodic, http.MethodPut, map[string]any{
+		"minBrojAkcija": 3, "tipAkcije": "planina", "minTezina": "tesko", "sertifikati": []string{"Alpinistički tečaj"},
+	}, SacuvajAkcijaUslove)
+	if code != http.StatusOK {
+		t.Fatalf("save rules: %d %v", code, body)
+	}
+
+	code, body = callPrijaviNaAkcijuJSON(t, db, akcija.ID, pocetnik.Username, nil)
+	if code != http.StatusForbidden {
+		t.Fatalf("beginner signup: %d %v", code, body)
+	}
+	if razlozi, _ := body["razlozi"].([]any); len(razlozi) != 2 {
+		t.Fatalf("expected two reasons, got %v", body)
+	}
+	code, body = callAkcijaUslovi(t, db, akcija.ID, pocetnik, http.MethodGet, nil, GetAkcijaUslovi)
+	status, _ := body["mojStatus"].(map[string]any)
+	if code != http.StatusOK || status["ispunjeni"] != false || body["izuzeci"] != nil {
+		t.Fatalf("member view: %d %v", code, body)
+	}
+
+	code, body = callRespondSignup(t, db, akcija.ID, pending.ID, vodic, "accept")
+	if code != http.StatusConflict || reloadSignupRequest(t, db, pending.ID).Status != models.ActionSignupRequestPending {
+		t.Fatalf("accept of ineligible request: %d %v", code, body)
+	}
+
+	for _, k := range []models.Korisnik{pocetnik, ranije} {
+		id := strconv.FormatUint(uint64(akcija.ID), 10)
+		raw, _ := json.Marshal(map[string]any{"korisnikId": k.ID, "napomena": "poznat vodiču"})
+		w, c := m4ManageCtx(t, db, vodic, http.MethodPost, "/api/akcije/"+id+"/uslovi/izuzeci", raw)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		DodajIzuzetakUslova(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("add override: %d %s", w.Code, w.Body.String())
+		}
+	}
+	if code, body := callPrijaviNaAkcijuJSON(t, db, akcija.ID, pocetnik.Username, nil); code != http.StatusOK {
+		t.Fatalf("signup with override: %d %v", code, body)
+	}
+	if code, body := callRespondSignup(t, db, akcija.ID, pending.ID, vodic, "accept"); code != http.StatusOK {
+		t.Fatalf("accept with override: %d %v", code, body)
+	}
+	code, body = callAkcijaUslovi(t, db, akcija.ID, vodic, http.MethodGet, nil, GetAkcijaUslovi)
+	if izuzeci, _ := body["izuzeci"].([]any); code != http.StatusOK || len(izuzeci) != 2 {
+		t.Fatalf("manager view: %d %v", code, body)
+	}
+}
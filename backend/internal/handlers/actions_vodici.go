@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+	"beleg-app/backend/internal/services/actions"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// akcijaVodiciDTO: glavni vodič (uloga „glavni”) pa ko-vodiči redom dodavanja.
+func akcijaVodiciDTO(db *gorm.DB, akcija *models.Akcija) []gin.H {
+	out := make([]gin.H, 0)
+	co, _ := helpers.AkcijaCoGuides(db, akcija.ID)
+	ids := make([]uint, 0, len(co)+1)
+	if akcija.VodicID > 0 {
+		ids = append(ids, akcija.VodicID)
+	}
+	for _, v := range co {
+		ids = append(ids, v.KorisnikID)
+	}
+	profiSet := helpers.ApprovedProfiGuideKorisnikIDs(db, ids)
+	if akcija.VodicID > 0 {
+		var v models.Korisnik
+		if db.First(&v, akcija.VodicID).Error == nil {
+			out = append(out, gin.H{
+				"korisnikId":   v.ID,
+				"fullName":     v.FullName,
+				"username":     v.Username,
+				"avatarUrl":    v.AvatarURL,
+				"isProfiGuide": profiSet[v.ID],
+				"uloga":        models.AkcijaVodicUlogaGlavni,
+			})
+		}
+	}
+	for _, v := range co {
+		item := gin.H{
+			"korisnikId":   v.KorisnikID,
+			"fullName":     v.Korisnik.FullName,
+			"username":     v.Korisnik.Username,
+			"avatarUrl":    v.Korisnik.AvatarURL,
+			"isProfiGuide": profiSet[v.KorisnikID],
+			"uloga":        v.Uloga,
+		}
+		if v.GuideProfileID != nil {
+			item["guideProfileId"] = *v.GuideProfileID
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// authorizeManageCoGuides: vodiče akcije menja ko upravlja akcijom (CanManageAkcijaEx), ne i ko-vodiči.
+func authorizeManageCoGuides(c *gin.Context) func(tx *gorm.DB, locked *models.Akcija) error {
+	return func(tx *gorm.DB, locked *models.Akcija) error {
+		if !helpers.CanManageAkcijaEx(c, tx, locked) {
+			return actions.ErrCoGuideUnauthorized
+		}
+		return nil
+	}
+}
+
+// respondCoGuideError mapira greške dodele vodiča na HTTP status.
+func respondCoGuideError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, actions.ErrCoGuideRoleInvalid), errors.Is(err, actions.ErrCoGuideIsLead):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, actions.ErrCoGuideUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, actions.ErrCoGuideNotFound), errors.Is(err, actions.ErrCoGuideNotAssigned):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+	case errors.Is(err, helpers.ErrAkcijaAlreadyCancelled), errors.Is(err, helpers.ErrAkcijaAlreadyComplete):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri izmeni vodiča akcije"})
+	}
+}
+
+// DodajVodicaAkciji POST /akcije/:id/vodici — body {"korisnikId": 5, "uloga": "zatvarac"} ili {"guideProfileId": 3};
+// isti korisnik ponovo menja ulogu.
+func DodajVodicaAkciji(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	db := DB(c)
+	actor, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	var req struct {
+		KorisnikID     uint   `json:"korisnikId"`
+		GuideProfileID uint   `json:"guideProfileId"`
+		Uloga          string `json:"uloga"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON (očekuje se npr. {\"korisnikId\": 5, \"uloga\": \"pomocni\"})"})
+		return
+	}
+	result, err := actions.AssignCoGuide(db, uint(id), actions.CoGuideInput{
+		KorisnikID: req.KorisnikID, GuideProfileID: req.GuideProfileID, Uloga: req.Uloga,
+	}, actor.ID, authorizeManageCoGuides(c))
+	if err != nil {
+		respondCoGuideError(c, err)
+		return
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju akcije"})
+		return
+	}
+	status := http.StatusOK
+	if result.Added {
+		status = http.StatusCreated
+		if result.Vodic.KorisnikID != actor.ID {
+			notifications.NotifyCoGuideAssigned(db, &akcija, result.Vodic)
+		}
+	}
+	c.JSON(status, gin.H{"message": "Vodič akcije je sačuvan", "vodici": akcijaVodiciDTO(db, &akcija)})
+}
+
+// UkloniVodicaSaAkcije DELETE /akcije/:id/vodici/:korisnikId — uklanja ko-vodiča (glavni se menja izmenom akcije).
+func UkloniVodicaSaAkcije(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	korisnikID, err := strconv.ParseUint(c.Param("korisnikId"), 10, 32)
+	if err != nil || korisnikID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID korisnika"})
+		return
+	}
+	db := DB(c)
+	if err := actions.RemoveCoGuide(db, uint(id), uint(korisnikID), authorizeManageCoGuides(c)); err != nil {
+		respondCoGuideError(c, err)
+		return
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju akcije"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Vodič je uklonjen sa akcije", "vodici": akcijaVodiciDTO(db, &akcija)})
+}
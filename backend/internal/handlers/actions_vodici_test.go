@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func testCoGuideHandlersDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := testModule4DB(t)
+	if err := db.AutoMigrate(&models.AkcijaVodic{}, &models.GuideProfile{}, &models.GuideActionRating{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestCoGuide_SeesHiddenParticipantList(t *testing.T) {
+	db := testCoGuideHandlersDB(t)
+	club := m4Club(t, db, "co_list")
+	other := m4Club(t, db, "co_list_other")
+	lead := m4User(t, db, "co_list_lead", "vodic", &club.ID)
+	coGuide := m4User(t, db, "co_list_guide", "clan", &other.ID)
+	akcija := m4Akcija(t, db, club.ID, lead.ID, true)
+	db.Model(&akcija).Update("prikazi_listu_prijavljenih", false)
+	id := strconv.FormatUint(uint64(akcija.ID), 10)
+
+	call := func() int {
+		w, c := m4ManageCtx(t, db, coGuide, http.MethodGet, "/api/akcije/"+id+"/prijave", nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		GetPrijaveZaAkciju(c)
+		return w.Code
+	}
+	if code := call(); code != http.StatusForbidden {
+		t.Fatalf("outsider must not see hidden list, got %d", code)
+	}
+	db.Create(&models.AkcijaVodic{AkcijaID: akcija.ID, KorisnikID: coGuide.ID, Uloga: models.AkcijaVodicUlogaZatvarac})
+	if code := call(); code != http.StatusOK {
+		t.Fatalf("co-guide must see participant list, got %d", code)
+	}
+}
+
+func TestGuideRating_EachGuideRatedSeparately(t *testing.T) {
+	db := testCoGuideHandlersDB(t)
+	club := m4Club(t, db, "co_rate")
+	lead := m4User(t, db, "co_rate_lead", "vodic", &club.ID)
+	coGuide := m4User(t, db, "co_rate_guide", "clan", nil)
+	member := m4User(t, db, "co_rate_member", "clan", &club.ID)
+	akcija := m4Akcija(t, db, club.ID, lead.ID, true)
+	db.Model(&akcija).Update("is_completed", true)
+	db.Create(&models.AkcijaVodic{AkcijaID: akcija.ID, KorisnikID: coGuide.ID, Uloga: models.AkcijaVodicUlogaPomocni})
+	db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: member.ID, Status: "popeo se"})
+	for _, u := range []models.Korisnik{lead, coGuide} {
+		db.Create(&models.GuideProfile{KorisnikID: u.ID, Status: models.GuideStatusApproved, Naslov: "V", Opis: "O"})
+	}
+	id := strconv.FormatUint(uint64(akcija.ID), 10)
+
+	rate := func(rater models.Korisnik, vodicID uint) int {
+		body, _ := json.Marshal(map[string]any{"vodicId": vodicID, "ocena": 5})
+		w, c := m4ManageCtx(t, db, rater, http.MethodPost, "/api/akcije/"+id+"/guide-rating", body)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		SubmitGuideRatingForAkcija(c)
+		return w.Code
+	}
+	if code := rate(member, lead.ID); code != http.StatusCreated {
+		t.Fatalf("rating lead: %d", code)
+	}
+	if code := rate(member, coGuide.ID); code != http.StatusCreated {
+		t.Fatalf("rating co-guide: %d", code)
+	}
+	if code := rate(member, coGuide.ID); code != http.StatusConflict {
+		t.Fatalf("rating co-guide twice: %d", code)
+	}
+	if code := rate(coGuide, lead.ID); code != http.StatusForbidden {
+		t.Fatalf("guides must not rate each other: %d", code)
+	}
+	if code := rate(member, member.ID); code != http.StatusBadRequest {
+		t.Fatalf("non-guide vodicId: %d", code)
+	}
+	var gp models.GuideProfile
+	db.Where("korisnik_id = ?", coGuide.ID).First(&gp)
+	if gp.BrojOcena != 1 || gp.ProsecnaOcena != 5 {
+		t.Fatalf("co-guide profile rating: %+v", gp)
+	}
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	activityMetricDistance  = "distance"  // metri: GPS sesije van akcija + dužina staze akcija
+	activityMetricElevation = "elevation" // metri uspona: GPS sesije van akcija + kumulativni uspon akcija
+	activityMetricActions   = "actions"   // broj „popeo se“ prijava na završenim akcijama
+)
+
+type activityLeaderboardEntry struct {
+	UserID    uint    `json:"userId"`
+	Username  string  `json:"username"`
+	FullName  string  `json:"fullName,omitempty"`
+	AvatarURL string  `json:"avatarUrl,omitempty"`
+	Value     float64 `json:"value"`
+	Rank      int     `json:"rank"`
+}
+
+// activityPeriodRange vraća [from, to) za week/month/year/all (all = bez donje granice).
+func activityPeriodRange(period string) (from, to time.Time, normalized string) {
+	today := todayDateUTC()
+	to = today.AddDate(0, 0, 1)
+	switch period {
+	case "month":
+		return time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC), to, "month"
+	case "year":
+		return time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC), to, "year"
+	case "all":
+		return time.Time{}, to, "all"
+	default:
+		return today.AddDate(0, 0, -6), to, "week"
+	}
+}
+
+// activityLeaderboardScope vraća subquery korisnika u opsegu (nil = global) i normalizovan scope.
+// club = effective klub (helpers.GetEffectiveClubID, kao kod koraka); ok=false kad klub nije određen.
+// followers = korisnici koje pratim (prihvaćeno) + ja.
+func activityLeaderboardScope(c *gin.Context, db *gorm.DB, user *models.Korisnik, scope string) (*gorm.DB, string, bool) {
+	switch scope {
+	case "club":
+		klubID, ok := helpers.GetEffectiveClubID(c, db)
+		if !ok || klubID == 0 {
+			return nil, "club", false
+		}
+		return db.Model(&models.Korisnik{}).Select("id").Where("klub_id = ?", klubID), "club", true
+	case "followers":
+		return db.Model(&models.Korisnik{}).Select("id").
+			Where("id = ? OR id IN (?)", user.ID,
+				db.Model(&models.Follow{}).Select("target_id").
+					Where("requester_id = ? AND status = ?", user.ID, models.FollowStatusAccepted)), "followers", true
+	default:
+		return nil, "global", true
+	}
+}
+
+// activitySessionEnd je SQL izraz za kraj GPS sesije (ended_at, a za starije sesije početak + trajanje).
+func activitySessionEnd(db *gorm.DB) string {
+	if strings.EqualFold(db.Dialector.Name(), "postgres") {
+		return "COALESCE(tracked_activities.ended_at, tracked_activities.started_at + tracked_activities.duration_sec * INTERVAL '1 second')"
+	}
+	return "COALESCE(tracked_activities.ended_at, datetime(tracked_activities.started_at, '+' || tracked_activities.duration_sec || ' seconds'))"
+}
+
+// activityLeaderboardRows vraća subquery (user_id, value) čiji zbir po korisniku daje metriku; flagovane i odbačene
+// GPS sesije se ne računaju. Za km i uspon jedan izlazak se broji jednom: GPS sesija koja se preklapa sa završenom
+// akcijom na kojoj je korisnik „popeo se“ se preskače, jer akcija već nosi zvanične km/uspon. Prozor akcije je ceo
+// dan polaska (do kraja akcije ako je višednevna): sesija snimljena tog dana je isti izlazak.
+func activityLeaderboardRows(db *gorm.DB, metric string, from, to time.Time, scopeIDs *gorm.DB) *gorm.DB {
+	completed := func() *gorm.DB {
+		return db.Model(&models.Prijava{}).
+			Joins("JOIN akcije ON akcije.id = prijave.akcija_id").
+			Where("prijave.status = ? AND akcije.is_completed = ? AND akcije.datum >= ? AND akcije.datum < ?", "popeo se", true, from, to)
+	}
+	col := "1"
+	switch metric {
+	case activityMetricDistance:
+		col = "akcije.ukupno_km_akcija * 1000"
+	case activityMetricElevation:
+		col = "akcije.ukupno_metara_uspona_akcija"
+	}
+	prijave := completed().Select("prijave.korisnik_id AS user_id, " + col + " AS value")
+	if scopeIDs != nil {
+		prijave = prijave.Where("prijave.korisnik_id IN (?)", scopeIDs)
+	}
+	if metric == activityMetricActions {
+		return prijave
+	}
+
+	col = "tracked_activities.distance_m"
+	if metric == activityMetricElevation {
+		col = "tracked_activities.elevation_gain_m"
+	}
+	polazak := "DATE(COALESCE(akcije.start_at, akcije.datum))"
+	istiIzlazak := completed().Select("1").
+		Where("prijave.korisnik_id = tracked_activities.user_id").
+		Where("DATE(" + activitySessionEnd(db) + ") >= " + polazak).
+		Where("(DATE(tracked_activities.started_at) <= " + polazak + " OR tracked_activities.started_at < akcije.end_at)")
+	sesije := db.Model(&models.TrackedActivity{}).
+		Select("tracked_activities.user_id AS user_id, "+col+" AS value").
+		Where("tracked_activities.status = ? AND tracked_activities.flagged = ? AND tracked_activities.started_at >= ? AND tracked_activities.started_at < ?",
+			models.TrackedActivityStatusCompleted, false, from, to).
+		Where("NOT EXISTS (?)", istiIzlazak)
+	if scopeIDs != nil {
+		sesije = sesije.Where("tracked_activities.user_id IN (?)", scopeIDs)
+	}
+	return db.Raw("SELECT * FROM (?) AS s UNION ALL SELECT * FROM (?) AS p", sesije, prijave)
+}
+
+func getActivityLeaderboard(c *gin.Context, metric string) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	limit := 50
+	if l := c.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+	fromDate, toDate, period := activityPeriodRange(c.DefaultQuery("period", "week"))
+	scopeIDs, scope, scopeOk := activityLeaderboardScope(c, db, user, c.DefaultQuery("scope", "global"))
+
+	me := activityLeaderboardEntry{
+		UserID:    user.ID,
+		Username:  user.Username,
+		FullName:  user.FullName,
+		AvatarURL: user.AvatarURL,
+	}
+	if !scopeOk {
+		me.Value, me.Rank = 0, 1
+		c.JSON(http.StatusOK, gin.H{"entries": []activityLeaderboardEntry{}, "me": me, "scope": scope, "period": period, "metric": metric})
+		return
+	}
+
+	// Zbir po korisniku, rang i top N računa baza; u Go stižu samo prikazani redovi i „ja“.
+	totals := func() *gorm.DB {
+		return db.Table("(?) AS t", activityLeaderboardRows(db, metric, fromDate, toDate, scopeIDs)).
+			Select("user_id, SUM(value) AS value").Group("user_id")
+	}
+	type valueRow struct {
+		UserID uint
+		Value  float64
+	}
+	var rows []valueRow
+	if err := totals().Having("SUM(value) > 0").Order("value DESC, user_id").Limit(limit).Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju rang liste"})
+		return
+	}
+	var mine []valueRow
+	if err := totals().Where("user_id = ?", user.ID).Scan(&mine).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju rang liste"})
+		return
+	}
+	if len(mine) > 0 {
+		me.Value = mine[0].Value
+	}
+	// Rang „mene“ kao kod koraka: broj korisnika sa strogo većom vrednošću + 1.
+	var better int64
+	if err := db.Table("(?) AS r", totals().Having("SUM(value) > ?", me.Value)).Count(&better).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju rang liste"})
+		return
+	}
+	me.Rank = int(better) + 1
+
+	ids := make([]uint, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.UserID)
+	}
+	usersByID := make(map[uint]models.Korisnik, len(ids))
+	if len(ids) > 0 {
+		var users []models.Korisnik
+		_ = db.Where("id IN ?", ids).Find(&users).Error
+		for _, u := range users {
+			usersByID[u.ID] = u
+		}
+	}
+	entries := make([]activityLeaderboardEntry, 0, len(rows))
+	for i, r := range rows {
+		k := usersByID[r.UserID]
+		entries = append(entries, activityLeaderboardEntry{
+			UserID:    r.UserID,
+			Username:  k.Username,
+			FullName:  k.FullName,
+			AvatarURL: k.AvatarURL,
+			Value:     r.Value,
+			Rank:      i + 1,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "me": me, "scope": scope, "period": period, "metric": metric})
+}
+
+// GetDistanceLeaderboard returns distance (m) rankings for global, club or followers scope.
+func GetDistanceLeaderboard(c *gin.Context) {
+	getActivityLeaderboard(c, activityMetricDistance)
+}
+
+// GetElevationLeaderboard returns elevation gain (m) rankings for global, club or followers scope.
+func GetElevationLeaderboard(c *gin.Context) {
+	getActivityLeaderboard(c, activityMetricElevation)
+}
+
+// GetActionsLeaderboard returns completed action counts for global, club or followers scope.
+func GetActionsLeaderboard(c *gin.Context) {
+	getActivityLeaderboard(c, activityMetricActions)
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func testActivityLeaderboardDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "activity_leaderboards")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.Korisnik{},
+		&models.Follow{},
+		&models.Akcija{},
+		&models.Prijava{},
+		&models.TrackedActivity{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+type activityLeaderboardResponse struct {
+	Entries []activityLeaderboardEntry `json:"entries"`
+	Me      activityLeaderboardEntry   `json:"me"`
+	Scope   string                     `json:"scope"`
+	Period  string                     `json:"period"`
+	Metric  string                     `json:"metric"`
+}
+
+func callActivityLeaderboard(t *testing.T, db *gorm.DB, h gin.HandlerFunc, user models.Korisnik, query string) activityLeaderboardResponse {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/leaderboards/x?"+query, nil)
+	c.Set("db", db)
+	c.Set("username", user.Username)
+	h(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d body=%s", w.Code, w.Body.String())
+	}
+	var resp activityLeaderboardResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestActivityLeaderboards_ExcludeFlaggedAndScopeFollowers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testActivityLeaderboardDB(t)
+	me := models.Korisnik{Username: "ja", Role: "clan"}
+	friend := models.Korisnik{Username: "drug", Role: "clan"}
+	stranger := models.Korisnik{Username: "neko", Role: "clan"}
+	for _, u := range []*models.Korisnik{&me, &friend, &stranger} {
+		db.Create(u)
+	}
+	db.Create(&models.Follow{RequesterID: me.ID, TargetID: friend.ID, Status: models.FollowStatusAccepted})
+
+	now := time.Now().UTC()
+	db.Create(&models.TrackedActivity{UserID: me.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: now, DistanceM: 5000, ElevationGainM: 400})
+	db.Create(&models.TrackedActivity{UserID: friend.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: now, DistanceM: 3000})
+	db.Create(&models.TrackedActivity{UserID: friend.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: now, DistanceM: 90000, Flagged: true})
+	db.Create(&models.TrackedActivity{UserID: friend.ID, Status: models.TrackedActivityStatusDiscarded, StartedAt: now, DistanceM: 90000})
+	db.Create(&models.TrackedActivity{UserID: stranger.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: now, DistanceM: 20000})
+	db.Create(&models.TrackedActivity{UserID: me.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: now.AddDate(-2, 0, 0), DistanceM: 100000})
+
+	ak := models.Akcija{Naziv: "Rtanj", IsCompleted: true, Datum: now.AddDate(0, 0, -2), UkupnoKmAkcija: 4, UkupnoMetaraUsponaAkcija: 800}
+	db.Create(&ak)
+	db.Create(&models.Prijava{AkcijaID: ak.ID, KorisnikID: friend.ID, Status: "popeo se"})
+
+	global := callActivityLeaderboard(t, db, GetDistanceLeaderboard, me, "")
+	if global.Period != "week" || global.Scope != "global" || global.Metric != "distance" {
+		t.Fatalf("unexpected defaults %+v", global)
+	}
+	if len(global.Entries) != 3 || global.Entries[0].UserID != stranger.ID || global.Entries[1].UserID != friend.ID || global.Entries[1].Value != 7000 {
+		t.Fatalf("expected stranger, friend (7 km incl. action), me; got %+v", global.Entries)
+	}
+	if global.Me.Rank != 3 || global.Me.Value != 5000 {
+		t.Fatalf("expected me ranked 3rd with 5000 m, got %+v", global.Me)
+	}
+
+	top := callActivityLeaderboard(t, db, GetDistanceLeaderboard, me, "limit=1")
+	if len(top.Entries) != 1 || top.Entries[0].UserID != stranger.ID || top.Me.Rank != 3 || top.Me.Value != 5000 {
+		t.Fatalf("limit must cut entries but keep my rank, got %+v me=%+v", top.Entries, top.Me)
+	}
+
+	followers := callActivityLeaderboard(t, db, GetDistanceLeaderboard, me, "scope=followers")
+	if len(followers.Entries) != 2 || followers.Entries[0].UserID != friend.ID || followers.Me.Rank != 2 {
+		t.Fatalf("followers scope should hold friend and me only, got %+v me=%+v", followers.Entries, followers.Me)
+	}
+
+	all := callActivityLeaderboard(t, db, GetDistanceLeaderboard, me, "period=all")
+	if all.Me.Value != 105000 || all.Me.Rank != 1 {
+		t.Fatalf("all-time should include older session, got %+v", all.Me)
+	}
+
+	elevation := callActivityLeaderboard(t, db, GetElevationLeaderboard, me, "")
+	if elevation.Entries[0].UserID != friend.ID || elevation.Entries[0].Value != 800 {
+		t.Fatalf("expected friend leading elevation with action stats, got %+v", elevation.Entries)
+	}
+
+	actions := callActivityLeaderboard(t, db, GetActionsLeaderboard, me, "period=year")
+	if len(actions.Entries) != 1 || actions.Entries[0].Value != 1 || actions.Me.Rank != 2 {
+		t.Fatalf("expected one completed action for friend, got %+v me=%+v", actions.Entries, actions.Me)
+	}
+}
+
+func TestActivityLeaderboards_SessionDuringActionCountedOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testActivityLeaderboardDB(t)
+	me := models.Korisnik{Username: "ja", Role: "clan"}
+	db.Create(&me)
+
+	now := time.Now().UTC()
+	dan := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := dan.Add(7 * time.Hour)
+	kraj := dan.Add(15 * time.Hour)
+	ak := models.Akcija{Naziv: "Rtanj", IsCompleted: true, Datum: dan, StartAt: &start, UkupnoKmAkcija: 12, UkupnoMetaraUsponaAkcija: 900}
+	db.Create(&ak)
+	db.Create(&models.Prijava{AkcijaID: ak.ID, KorisnikID: me.ID, Status: "popeo se"})
+	// Isti izlazak snimljen GPS-om: ne sme da se sabere sa km/usponom akcije.
+	db.Create(&models.TrackedActivity{UserID: me.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: start.Add(-30 * time.Minute), EndedAt: &kraj, DistanceM: 12500, ElevationGainM: 950})
+	// Zasebna šetnja dva dana ranije se računa.
+	ranije := dan.AddDate(0, 0, -2).Add(17 * time.Hour)
+	db.Create(&models.TrackedActivity{UserID: me.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: ranije, DurationSec: 3600, DistanceM: 3000, ElevationGainM: 100})
+
+	distance := callActivityLeaderboard(t, db, GetDistanceLeaderboard, me, "")
+	if distance.Me.Value != 15000 {
+		t.Fatalf("expected 12 km from action + 3 km walk, got %+v", distance.Me)
+	}
+	elevation := callActivityLeaderboard(t, db, GetElevationLeaderboard, me, "")
+	if elevation.Me.Value != 1000 {
+		t.Fatalf("expected 900 m from action + 100 m walk, got %+v", elevation.Me)
+	}
+}
+
+func TestActivityLeaderboards_ClubScopeUsesEffectiveClub(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testActivityLeaderboardDB(t)
+	klubA, klubB := uint(1), uint(2)
+	super := models.Korisnik{Username: "super", Role: "superadmin"}
+	clanA := models.Korisnik{Username: "clan_a", Role: "clan", KlubID: &klubA}
+	clanB := models.Korisnik{Username: "clan_b", Role: "clan", KlubID: &klubB}
+	for _, u := range []*models.Korisnik{&super, &clanA, &clanB} {
+		db.Create(u)
+	}
+	now := time.Now().UTC()
+	db.Create(&models.TrackedActivity{UserID: clanA.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: now, DistanceM: 4000})
+	db.Create(&models.TrackedActivity{UserID: clanB.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: now, DistanceM: 6000})
+
+	call := func(header string) activityLeaderboardResponse {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/leaderboards/distance?scope=club", nil)
+		if header != "" {
+			c.Request.Header.Set(helpers.XClubIDHeader, header)
+		}
+		c.Set("db", db)
+		c.Set("username", super.Username)
+		c.Set("role", super.Role)
+		GetDistanceLeaderboard(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d body=%s", w.Code, w.Body.String())
+		}
+		var resp activityLeaderboardResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+	if resp := call(""); len(resp.Entries) != 0 {
+		t.Fatalf("superadmin without X-Club-Id has no club, got %+v", resp.Entries)
+	}
+	resp := call("2")
+	if len(resp.Entries) != 1 || resp.Entries[0].UserID != clanB.ID {
+		t.Fatalf("expected club 2 ranking from X-Club-Id, got %+v", resp.Entries)
+	}
+}
@@ -40,6 +40,7 @@ func testCancellationReadDB(t *testing.T) *gorm.DB {
 		&models.AkcijaPrevoz{},
 		&models.AkcijaOprema{},
 		&models.AkcijaOpremaRent{},
+		&models.Placanje{},
 	); err != nil {
 		t.Fatalf("migrate: %v", err)
 	}
@@ -265,7 +266,8 @@ func TestExecuteUpdateAkcijaTx_CancelledBlocked(t *testing.T) {
 	mutated.CancellationReason = "hacked"
 
 	err := db.Transaction(func(tx *gorm.DB) error {
-		return executeUpdateAkcijaTx(tx, mutated, ActionNestedSyncInput{})
+		_, err := executeUpdateAkcijaTx(tx, mutated, ActionNestedSyncInput{}, 0)
+		return err
 	})
 	if !errors.Is(err, helpers.ErrAkcijaCancelled) {
 		t.Fatalf("expected ErrAkcijaCancelled, got %v", err)
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// notifyMaterialAkcijaChange posle commita javlja potvrđenim učesnicima materijalnu izmenu (best-effort).
+func notifyMaterialAkcijaChange(db *gorm.DB, akcija *models.Akcija, izmena *helpers.AkcijaIzmena, actorID uint) {
+	if izmena == nil || izmena.Verzija == nil || !izmena.Verzija.Materijalna || akcija.IsCompleted {
+		return
+	}
+	recipients, err := helpers.ConfirmedParticipantIDs(db, akcija.ID, actorID)
+	if err != nil {
+		log.Printf("akcija %d: učesnici za obaveštenje o izmeni: %v", akcija.ID, err)
+		return
+	}
+	var saved models.Akcija
+	if err := db.First(&saved, akcija.ID).Error; err != nil {
+		return
+	}
+	summary := helpers.SummarizeMaterialPromene(izmena.Promene, izmena.Posle)
+	if !izmena.Verzija.PoskupljenjeCene {
+		notifications.NotifyActionChanged(db, &saved, izmena.Verzija, recipients, summary, nil, belgradeLoc())
+		return
+	}
+	until := helpers.PenaltyFreeWithdrawalUntil(&saved, izmena.Verzija.CreatedAt)
+	notifications.NotifyActionChanged(db, &saved, izmena.Verzija, recipients, summary, &until, belgradeLoc())
+}
+
+type akcijaVerzijaDTO struct {
+	models.AkcijaVerzija
+	Promene  []helpers.AkcijaPromena `json:"promene"`
+	Snapshot json.RawMessage         `json:"snapshot,omitempty"`
+}
+
+// loadAkcijaForHistory proverava pristup akciji (kao detalj) i vraća je; false znači da je odgovor već poslat.
+func loadAkcijaForHistory(c *gin.Context, db *gorm.DB) (*models.Akcija, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return nil, false
+	}
+	user, ok := currentUser(c, db)
+	if !ok {
+		return nil, false
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		return nil, false
+	}
+	if !viewerCanSeeAkcijaDetails(c, db, &akcija, user) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pristup ovoj akciji"})
+		return nil, false
+	}
+	return &akcija, true
+}
+
+// GetAkcijaIstorija GET /akcije/:id/istorija — verzije akcije sa razlikama (najnovije prvo).
+func GetAkcijaIstorija(c *gin.Context) {
+	db := DB(c)
+	akcija, ok := loadAkcijaForHistory(c, db)
+	if !ok {
+		return
+	}
+	var verzije []models.AkcijaVerzija
+	if err := db.Where("akcija_id = ?", akcija.ID).Order("verzija DESC").Find(&verzije).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju istorije akcije"})
+		return
+	}
+	out := make([]akcijaVerzijaDTO, 0, len(verzije))
+	for i := range verzije {
+		out = append(out, akcijaVerzijaDTO{AkcijaVerzija: verzije[i], Promene: helpers.ParseAkcijaPromene(&verzije[i])})
+	}
+	c.JSON(http.StatusOK, gin.H{"akcijaId": akcija.ID, "verzije": out})
+}
+
+// GetAkcijaVerzija GET /akcije/:id/istorija/:verzija — jedna verzija sa punim snimkom stanja.
+func GetAkcijaVerzija(c *gin.Context) {
+	db := DB(c)
+	akcija, ok := loadAkcijaForHistory(c, db)
+	if !ok {
+		return
+	}
+	broj, err := strconv.Atoi(c.Param("verzija"))
+	if err != nil || broj < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeća verzija"})
+		return
+	}
+	var v models.AkcijaVerzija
+	if err := db.Where("akcija_id = ? AND verzija = ?", akcija.ID, broj).First(&v).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Verzija nije pronađena"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju istorije akcije"})
+		return
+	}
+	c.JSON(http.StatusOK, akcijaVerzijaDTO{AkcijaVerzija: v, Promene: helpers.ParseAkcijaPromene(&v), Snapshot: json.RawMessage(v.Snapshot)})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func TestUpdateAkcija_PriceIncrease_RecordsVersionNotifiesAndAllowsPenaltyFreeWithdrawal(t *testing.T) {
+	db := testUpdateAkcijaDB(t)
+	if err := db.AutoMigrate(&models.Obavestenje{}); err != nil {
+		t.Fatal(err)
+	}
+	guide := seedSelfCancelHost(t, db, "hist_guide")
+	akcija := seedSelfCancelAkcija(t, db, guide, func(a *models.Akcija) {
+		a.CenaClan = 30
+		a.MestoPolaska = "Slavija"
+	})
+	member, p := seedSelfCancelMemberPrijava(t, db, akcija.ID, "hist_member", "prijavljen", true)
+
+	updated := akcija
+	updated.CenaClan = 45
+	updated.Opis = "Novi opis"
+	var izmena *helpers.AkcijaIzmena
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		izmena, err = executeUpdateAkcijaTx(tx, updated, ActionNestedSyncInput{}, guide.ID)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if izmena == nil || !izmena.Verzija.Materijalna || !izmena.Verzija.PoskupljenjeCene || izmena.Verzija.Izvor != models.AkcijaVerzijaIzvorIzmena {
+		t.Fatalf("version: %+v", izmena)
+	}
+	promene := helpers.ParseAkcijaPromene(izmena.Verzija)
+	if len(promene) != 2 || promene[0].Polje != "cenaClan" || promene[0].Staro != "30" || promene[0].Novo != "45" || promene[1].Polje != "opis" {
+		t.Fatalf("diff: %+v", promene)
+	}
+
+	notifyMaterialAkcijaChange(db, &updated, izmena, guide.ID)
+	var notes []models.Obavestenje
+	db.Where("type = ?", models.ObavestenjeTipActionChanged).Find(&notes)
+	if len(notes) != 1 || notes[0].UserID != member.ID {
+		t.Fatalf("notifications: %+v", notes)
+	}
+
+	// Promena cene resetuje Platio; vodič ponovo evidentira uplatu, a učesnik i dalje može da odustane bez penala.
+	reloaded := models.Prijava{}
+	db.First(&reloaded, p.ID)
+	if reloaded.BezPenalaDo == nil {
+		t.Fatal("price increase must grant penalty-free withdrawal")
+	}
+	db.Model(&reloaded).Update("platio", true)
+	code, body := callOtkaziPrijavuWithBody(t, db, akcija.ID, member.Username)
+	if code != http.StatusOK || body["bezPenala"] != true {
+		t.Fatalf("status %d body=%v", code, body)
+	}
+	var cancelled models.Prijava
+	db.First(&cancelled, p.ID)
+	if cancelled.Status != "otkazano" || !cancelled.Platio || cancelled.BezPenalaDo != nil {
+		t.Fatalf("paid prijava must be kept as otkazano: status=%s platio=%v bezPenalaDo=%v", cancelled.Status, cancelled.Platio, cancelled.BezPenalaDo)
+	}
+}
+
+func TestUpdateAkcija_NoChange_NoVersion(t *testing.T) {
+	db := testUpdateAkcijaDB(t)
+	guide := seedSelfCancelHost(t, db, "hist_same")
+	akcija := seedSelfCancelAkcija(t, db, guide)
+	var izmena *helpers.AkcijaIzmena
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		izmena, err = executeUpdateAkcijaTx(tx, akcija, ActionNestedSyncInput{}, guide.ID)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var n int64
+	db.Model(&models.AkcijaVerzija{}).Where("akcija_id = ?", akcija.ID).Count(&n)
+	if izmena != nil || n != 0 {
+		t.Fatalf("unchanged save must not create a version: %+v n=%d", izmena, n)
+	}
+}
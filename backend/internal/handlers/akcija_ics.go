@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const icsTimeLayout = "20060102T150405Z"
+
+// icsEscape escape-uje TEXT vrednost po RFC 5545 (\, ;, zarez i novi red).
+func icsEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// icsFold lomi liniju na 75 okteta (nastavak počinje razmakom), ne sečući UTF-8 znak.
+func icsFold(line string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range line {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// buildAkcijaICS gradi VCALENDAR sa jednim događajem; opis sadrži podatke o dnevnom svetlu.
+func buildAkcijaICS(akcija *models.Akcija, now time.Time) string {
+	var lines []string
+	add := func(l string) { lines = append(lines, l) }
+	add("BEGIN:VCALENDAR")
+	add("VERSION:2.0")
+	add("PRODID:-//Beleg//Akcije//SR")
+	add("CALSCALE:GREGORIAN")
+	add("METHOD:PUBLISH")
+	add("BEGIN:VEVENT")
+	add(fmt.Sprintf("UID:akcija-%d@beleg", akcija.ID))
+	add("DTSTAMP:" + now.UTC().Format(icsTimeLayout))
+	if akcija.StartAt != nil {
+		end := akcija.StartAt.Add(time.Duration(akcija.TrajanjeSati * float64(time.Hour)))
+		if akcija.EndAt != nil && akcija.EndAt.After(*akcija.StartAt) {
+			end = *akcija.EndAt
+		} else if akcija.TrajanjeSati <= 0 {
+			end = akcija.StartAt.Add(time.Hour)
+		}
+		add("DTSTART:" + akcija.StartAt.UTC().Format(icsTimeLayout))
+		add("DTEND:" + end.UTC().Format(icsTimeLayout))
+	} else {
+		days := max(akcija.BrojDana, 1)
+		day := akcija.Datum.UTC()
+		add("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+		add("DTEND;VALUE=DATE:" + day.AddDate(0, 0, days).Format("20060102"))
+	}
+	add("SUMMARY:" + icsEscape(akcija.Naziv))
+	var location []string
+	for _, s := range []string{akcija.Vrh, akcija.Planina} {
+		if s = strings.TrimSpace(s); s != "" {
+			location = append(location, s)
+		}
+	}
+	if len(location) > 0 {
+		add("LOCATION:" + icsEscape(strings.Join(location, ", ")))
+	}
+	if akcija.PlaninaLat != nil && akcija.PlaninaLng != nil {
+		add(fmt.Sprintf("GEO:%.6f;%.6f", *akcija.PlaninaLat, *akcija.PlaninaLng))
+	}
+	var desc []string
+	if s := strings.TrimSpace(akcija.Opis); s != "" {
+		desc = append(desc, s)
+	}
+	if s := strings.TrimSpace(akcija.MestoPolaska); s != "" {
+		desc = append(desc, "Mesto polaska: "+s)
+	}
+	if s := helpers.AkcijaDaylightInfo(akcija).Summary(); s != "" {
+		desc = append(desc, s)
+	}
+	if len(desc) > 0 {
+		add("DESCRIPTION:" + icsEscape(strings.Join(desc, "\n\n")))
+	}
+	if akcija.IsCancelled {
+		add("STATUS:CANCELLED")
+	}
+	add("END:VEVENT")
+	add("END:VCALENDAR")
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(icsFold(l))
+	}
+	return b.String()
+}
+
+// GetAkcijaICS GET /akcije/:id/kalendar.ics — akcija kao kalendarski događaj (iCalendar).
+func GetAkcijaICS(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		return
+	}
+	if !viewerCanSeeAkcijaDetails(c, db, &akcija, user) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pristup ovoj akciji"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="akcija-%d.ics"`, akcija.ID))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(buildAkcijaICS(&akcija, time.Now())))
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+)
+
+func TestBuildAkcijaICS_DaylightInDescription(t *testing.T) {
+	start := time.Date(2026, 12, 12, 10, 0, 0, 0, belgradeLoc())
+	lat, lng := 43.3, 22.1
+	akcija := models.Akcija{ID: 7, Naziv: "Trem, zimski uspon", Planina: "Suva planina", Vrh: "Trem",
+		Opis: "Okupljanje; obavezni dereze", MestoPolaska: "Niš, Trg kralja Milana",
+		Datum: time.Date(2026, 12, 12, 0, 0, 0, 0, time.UTC), StartAt: &start, TrajanjeSati: 8, PlaninaLat: &lat, PlaninaLng: &lng}
+
+	ics := buildAkcijaICS(&akcija, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC))
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	for _, want := range []string{
+		"UID:akcija-7@beleg",
+		"DTSTART:20261212T090000Z",
+		"DTEND:20261212T170000Z",
+		`SUMMARY:Trem\, zimski uspon`,
+		"GEO:43.300000;22.100000",
+		`Okupljanje\; obavezni dereze`,
+		"Izlazak Sunca",
+		"građanski sumrak",
+		"posle građanskog sumraka",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Fatalf("ICS must contain %q:\n%s", want, ics)
+		}
+	}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line longer than 75 octets: %q", line)
+		}
+	}
+
+	akcija.StartAt, akcija.BrojDana = nil, 2
+	if ics := buildAkcijaICS(&akcija, time.Now()); !strings.Contains(ics, "DTSTART;VALUE=DATE:20261212") || !strings.Contains(ics, "DTEND;VALUE=DATE:20261214") {
+		t.Fatalf("all-day multi-day event expected:\n%s", ics)
+	}
+}
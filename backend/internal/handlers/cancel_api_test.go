@@ -284,7 +284,8 @@ func TestOtkaziAkciju_CancelThenUpdateBlocked(t *testing.T) {
 
 	err := db.Transaction(func(tx *gorm.DB) error {
 		akcija.Naziv = "Hacked"
-		return executeUpdateAkcijaTx(tx, akcija, ActionNestedSyncInput{})
+		_, err := executeUpdateAkcijaTx(tx, akcija, ActionNestedSyncInput{}, 0)
+		return err
 	})
 	if !errors.Is(err, helpers.ErrAkcijaCancelled) {
 		t.Fatalf("err=%v", err)
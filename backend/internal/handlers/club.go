@@ -127,6 +127,10 @@ type updateMojKlubRequest struct {
 	Sediste        *string `json:"sediste"`
 	WebSajt        *string `json:"web_sajt"`
 	DatumOsnivanja *string `json:"datum_osnivanja"` // YYYY-MM-DD
+	// PregledAkcijaVodica: akcije koje objavljuje vodič čekaju odobrenje admina kluba
+	PregledAkcijaVodica *bool `json:"pregled_akcija_vodica"`
+	// ClanarinaIznos: godišnja članarina za online plaćanje; 0 isključuje online članarinu
+	ClanarinaIznos *float64 `json:"clanarina_iznos"`
 }
 
 func parseOptionalDate(s string) *time.Time {
@@ -242,6 +246,21 @@ func UpdateMojKlub(c *gin.Context) {
 			klub.DatumOsnivanja = *t
 		}
 	}
+	if req.PregledAkcijaVodica != nil {
+		klub.PregledAkcijaVodica = *req.PregledAkcijaVodica
+	}
+	if req.ClanarinaIznos != nil {
+		if *req.ClanarinaIznos < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Iznos članarine ne može biti negativan"})
+			return
+		}
+		if *req.ClanarinaIznos == 0 {
+			klub.ClanarinaIznos = nil
+		} else {
+			iznos := *req.ClanarinaIznos
+			klub.ClanarinaIznos = &iznos
+		}
+	}
 
 	if err := db.Save(&klub).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju"})
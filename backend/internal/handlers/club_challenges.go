@@ -0,0 +1,504 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/challenges"
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type clubChallengeBody struct {
+	Naziv        string   `json:"naziv"`
+	Opis         string   `json:"opis"`
+	Metrika      string   `json:"metrika"`
+	Tip          string   `json:"tip"`
+	Obuhvat      string   `json:"obuhvat"`
+	PocetakDatum string   `json:"pocetakDatum"`
+	KrajDatum    string   `json:"krajDatum"`
+	Timovi       []string `json:"timovi"`
+}
+
+type challengeStandingEntry struct {
+	challenges.Standing
+	Username  string `json:"username"`
+	FullName  string `json:"fullName,omitempty"`
+	AvatarURL string `json:"avatarUrl,omitempty"`
+}
+
+const maxChallengeDays = 366
+
+func clubChallengeToMap(ch *models.ClubChallenge, brojUcesnika int64, pridruzen bool) gin.H {
+	status := "aktivan"
+	if ch.ZavrsenoAt != nil {
+		status = "zavrsen"
+	} else if todayDateUTC().Before(ch.PocetakDatum) {
+		status = "predstoji"
+	}
+	return gin.H{
+		"id":           ch.ID,
+		"naziv":        ch.Naziv,
+		"opis":         ch.Opis,
+		"metrika":      ch.Metrika,
+		"tip":          ch.Tip,
+		"obuhvat":      ch.Obuhvat,
+		"klubId":       ch.KlubID,
+		"pocetakDatum": ch.PocetakDatum.Format("2006-01-02"),
+		"krajDatum":    ch.KrajDatum.Format("2006-01-02"),
+		"zavrsenoAt":   ch.ZavrsenoAt,
+		"status":       status,
+		"brojUcesnika": brojUcesnika,
+		"pridruzen":    pridruzen,
+	}
+}
+
+// canViewClubChallenge: klupski izazov vide članovi kluba organizatora, međuklubski svi; superadmin sve.
+func canViewClubChallenge(c *gin.Context, user *models.Korisnik, ch *models.ClubChallenge) bool {
+	roleVal, _ := c.Get("role")
+	if role, _ := roleVal.(string); role == "superadmin" {
+		return true
+	}
+	if ch.Obuhvat == models.ChallengeObuhvatKlubovi {
+		return true
+	}
+	return ch.KlubID != nil && user.KlubID != nil && *user.KlubID == *ch.KlubID
+}
+
+// canManageClubChallenge: superadmin ili admin kluba organizatora.
+func canManageClubChallenge(c *gin.Context, db *gorm.DB, ch *models.ClubChallenge) bool {
+	roleVal, _ := c.Get("role")
+	role, _ := roleVal.(string)
+	if role == "superadmin" {
+		return true
+	}
+	if role != "admin" || ch.KlubID == nil {
+		return false
+	}
+	clubID, ok := helpers.GetEffectiveClubID(c, db)
+	return ok && clubID != 0 && clubID == *ch.KlubID
+}
+
+func loadClubChallenge(c *gin.Context, db *gorm.DB) (*models.ClubChallenge, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID izazova"})
+		return nil, false
+	}
+	var ch models.ClubChallenge
+	if err := db.First(&ch, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Izazov nije pronađen"})
+		return nil, false
+	}
+	return &ch, true
+}
+
+// ListClubChallenges GET /api/izazovi?status=aktivni|zavrseni — izazovi vidljivi korisniku.
+func ListClubChallenges(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	q := db.Model(&models.ClubChallenge{})
+	roleVal, _ := c.Get("role")
+	if role, _ := roleVal.(string); role != "superadmin" {
+		if user.KlubID != nil {
+			q = q.Where("obuhvat = ? OR klub_id = ?", models.ChallengeObuhvatKlubovi, *user.KlubID)
+		} else {
+			q = q.Where("obuhvat = ?", models.ChallengeObuhvatKlubovi)
+		}
+	}
+	switch c.Query("status") {
+	case "aktivni":
+		q = q.Where("zavrseno_at IS NULL")
+	case "zavrseni":
+		q = q.Where("zavrseno_at IS NOT NULL")
+	}
+	var list []models.ClubChallenge
+	if err := q.Order("kraj_datum DESC, id DESC").Limit(100).Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju izazova"})
+		return
+	}
+
+	ids := make([]uint, 0, len(list))
+	for _, ch := range list {
+		ids = append(ids, ch.ID)
+	}
+	counts := make(map[uint]int64, len(list))
+	joined := make(map[uint]bool)
+	if len(ids) > 0 {
+		type countRow struct {
+			ChallengeID uint
+			N           int64
+		}
+		var rows []countRow
+		_ = db.Model(&models.ClubChallengeParticipant{}).
+			Select("challenge_id, COUNT(*) as n").
+			Where("challenge_id IN ?", ids).
+			Group("challenge_id").
+			Scan(&rows).Error
+		for _, r := range rows {
+			counts[r.ChallengeID] = r.N
+		}
+		var mine []uint
+		_ = db.Model(&models.ClubChallengeParticipant{}).
+			Where("challenge_id IN ? AND korisnik_id = ?", ids, user.ID).
+			Pluck("challenge_id", &mine).Error
+		for _, id := range mine {
+			joined[id] = true
+		}
+	}
+	out := make([]gin.H, 0, len(list))
+	for i := range list {
+		out = append(out, clubChallengeToMap(&list[i], counts[list[i].ID], joined[list[i].ID]))
+	}
+	c.JSON(http.StatusOK, gin.H{"izazovi": out})
+}
+
+// GetClubChallenge GET /api/izazovi/:id — detalj sa plasmanom (live dok traje, zamrznut posle kraja).
+func GetClubChallenge(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	ch, ok := loadClubChallenge(c, db)
+	if !ok {
+		return
+	}
+	if !canViewClubChallenge(c, user, ch) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Izazov nije pronađen"})
+		return
+	}
+
+	var (
+		individual []challenges.Standing
+		teams      []challenges.TeamStanding
+		err        error
+	)
+	if ch.ZavrsenoAt != nil {
+		individual, teams, err = challenges.FrozenStandings(db, ch)
+	} else {
+		individual, teams, err = challenges.Standings(db, ch)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri računanju plasmana"})
+		return
+	}
+
+	korisnikIDs := make([]uint, 0, len(individual))
+	for _, s := range individual {
+		korisnikIDs = append(korisnikIDs, s.KorisnikID)
+	}
+	usersByID := make(map[uint]models.Korisnik, len(korisnikIDs))
+	if len(korisnikIDs) > 0 {
+		var users []models.Korisnik
+		_ = db.Select("id", "username", "full_name", "avatar_url").Where("id IN ?", korisnikIDs).Find(&users).Error
+		for _, u := range users {
+			usersByID[u.ID] = u
+		}
+	}
+	entries := make([]challengeStandingEntry, 0, len(individual))
+	var me *challengeStandingEntry
+	for _, s := range individual {
+		u := usersByID[s.KorisnikID]
+		entry := challengeStandingEntry{Standing: s, Username: u.Username, FullName: u.FullName, AvatarURL: u.AvatarURL}
+		entries = append(entries, entry)
+		if s.KorisnikID == user.ID {
+			e := entry
+			me = &e
+		}
+	}
+
+	var teamList []models.ClubChallengeTeam
+	_ = db.Where("challenge_id = ?", ch.ID).Order("id ASC").Find(&teamList).Error
+
+	c.JSON(http.StatusOK, gin.H{
+		"izazov":     clubChallengeToMap(ch, int64(len(individual)), me != nil),
+		"timovi":     teamList,
+		"plasman":    entries,
+		"plasmanTim": teams,
+		"me":         me,
+		"zamrznuto":  ch.ZavrsenoAt != nil,
+	})
+}
+
+// CreateClubChallenge POST /api/izazovi — admin kluba (klupski ili međuklubski) ili superadmin.
+func CreateClubChallenge(c *gin.Context) {
+	if !RequireAnyRole(c, "Samo admin kluba ili superadmin mogu da prave izazove", "admin", "superadmin") {
+		return
+	}
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	var body clubChallengeBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON"})
+		return
+	}
+	naziv := strings.TrimSpace(body.Naziv)
+	if naziv == "" || len(naziv) > 150 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Naziv izazova je obavezan (najviše 150 karaktera)"})
+		return
+	}
+	if !challenges.ValidMetrika(body.Metrika) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Metrika mora biti koraci, km, uspon ili vrhovi"})
+		return
+	}
+	tip := strings.TrimSpace(body.Tip)
+	if tip == "" {
+		tip = models.ChallengeTipIndividualni
+	}
+	if tip != models.ChallengeTipIndividualni && tip != models.ChallengeTipTimski {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tip mora biti individualni ili timski"})
+		return
+	}
+	obuhvat := strings.TrimSpace(body.Obuhvat)
+	if obuhvat == "" {
+		obuhvat = models.ChallengeObuhvatKlub
+	}
+	if obuhvat != models.ChallengeObuhvatKlub && obuhvat != models.ChallengeObuhvatKlubovi {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Obuhvat mora biti klub ili klubovi"})
+		return
+	}
+	pocetak, okFrom := parseDateYMD(strings.TrimSpace(body.PocetakDatum))
+	kraj, okTo := parseDateYMD(strings.TrimSpace(body.KrajDatum))
+	if !okFrom || !okTo || kraj.Before(pocetak) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravan raspon datuma"})
+		return
+	}
+	if kraj.Before(todayDateUTC()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Izazov ne može da se završi u prošlosti"})
+		return
+	}
+	if kraj.Sub(pocetak) > maxChallengeDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Izazov može trajati najviše godinu dana"})
+		return
+	}
+
+	ch := models.ClubChallenge{
+		Naziv:        naziv,
+		Opis:         strings.TrimSpace(body.Opis),
+		Metrika:      body.Metrika,
+		Tip:          tip,
+		Obuhvat:      obuhvat,
+		PocetakDatum: pocetak,
+		KrajDatum:    kraj,
+		CreatedByID:  user.ID,
+	}
+	roleVal, _ := c.Get("role")
+	if role, _ := roleVal.(string); role != "superadmin" || obuhvat == models.ChallengeObuhvatKlub {
+		clubID, ok := helpers.GetEffectiveClubID(c, db)
+		if !ok || clubID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Izaberite klub (superadmin) ili niste u klubu."})
+			return
+		}
+		ch.KlubID = &clubID
+	}
+
+	// Klupski timski izazov ima unapred zadate timove; međuklubski pravi tim po klubu pri prijavi.
+	timovi := make([]string, 0, len(body.Timovi))
+	if tip == models.ChallengeTipTimski && obuhvat == models.ChallengeObuhvatKlub {
+		seen := make(map[string]struct{})
+		for _, t := range body.Timovi {
+			t = strings.TrimSpace(t)
+			key := strings.ToLower(t)
+			if t == "" || len(t) > 150 {
+				continue
+			}
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+			timovi = append(timovi, t)
+		}
+		if len(timovi) < 2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Timski izazov u klubu mora imati bar dva tima"})
+			return
+		}
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&ch).Error; err != nil {
+			return err
+		}
+		for _, t := range timovi {
+			if err := tx.Create(&models.ClubChallengeTeam{ChallengeID: ch.ID, Naziv: t}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju izazova"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"izazov": clubChallengeToMap(&ch, 0, false)})
+}
+
+// DeleteClubChallenge DELETE /api/izazovi/:id — briše izazov sa timovima, učesnicima i rezultatima.
+func DeleteClubChallenge(c *gin.Context) {
+	db := DB(c)
+	ch, ok := loadClubChallenge(c, db)
+	if !ok {
+		return
+	}
+	if !canManageClubChallenge(c, db, ch) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate dozvolu da obrišete ovaj izazov"})
+		return
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&models.ClubChallengeResult{}, &models.ClubChallengeParticipant{}, &models.ClubChallengeTeam{}} {
+			if err := tx.Where("challenge_id = ?", ch.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(ch).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Brisanje nije uspelo"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// JoinClubChallenge POST /api/izazovi/:id/prijava — opt-in prijava (body {teamId} za klupski timski izazov).
+func JoinClubChallenge(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	ch, ok := loadClubChallenge(c, db)
+	if !ok {
+		return
+	}
+	if !canViewClubChallenge(c, user, ch) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Izazov nije pronađen"})
+		return
+	}
+	if ch.ZavrsenoAt != nil || challenges.HasEnded(ch, time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Izazov je završen"})
+		return
+	}
+	if user.KlubID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo članovi klubova mogu učestvovati u izazovima"})
+		return
+	}
+	if ch.Obuhvat == models.ChallengeObuhvatKlub && (ch.KlubID == nil || *ch.KlubID != *user.KlubID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Izazov je samo za članove kluba organizatora"})
+		return
+	}
+	var body struct {
+		TeamID uint `json:"teamId"`
+	}
+	_ = c.ShouldBindJSON(&body)
+
+	participant := models.ClubChallengeParticipant{ChallengeID: ch.ID, KorisnikID: user.ID}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.ClubChallengeParticipant{}).
+			Where("challenge_id = ? AND korisnik_id = ?", ch.ID, user.ID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return errChallengeAlreadyJoined
+		}
+		if ch.Tip == models.ChallengeTipTimski {
+			teamID, err := resolveChallengeTeamTx(tx, ch, user, body.TeamID)
+			if err != nil {
+				return err
+			}
+			participant.TeamID = &teamID
+		}
+		return tx.Create(&participant).Error
+	})
+	switch {
+	case errors.Is(err, errChallengeAlreadyJoined):
+		c.JSON(http.StatusConflict, gin.H{"error": "Već ste prijavljeni na izazov"})
+		return
+	case errors.Is(err, errChallengeTeamRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Izaberite tim"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Prijava na izazov nije uspela"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"ucesnik": participant})
+}
+
+// LeaveClubChallenge DELETE /api/izazovi/:id/prijava — odjava dok izazov traje.
+func LeaveClubChallenge(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	ch, ok := loadClubChallenge(c, db)
+	if !ok {
+		return
+	}
+	if ch.ZavrsenoAt != nil || challenges.HasEnded(ch, time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Izazov je završen; rezultati su zamrznuti"})
+		return
+	}
+	res := db.Where("challenge_id = ? AND korisnik_id = ?", ch.ID, user.ID).Delete(&models.ClubChallengeParticipant{})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Odjava nije uspela"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Niste prijavljeni na izazov"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+var (
+	errChallengeAlreadyJoined = errors.New("already joined")
+	errChallengeTeamRequired  = errors.New("team required")
+)
+
+// resolveChallengeTeamTx: međuklubski timski izazov — tim = klub korisnika (pravi se pri prvoj prijavi);
+// klupski — tim koji je korisnik izabrao.
+func resolveChallengeTeamTx(tx *gorm.DB, ch *models.ClubChallenge, user *models.Korisnik, teamID uint) (uint, error) {
+	if ch.Obuhvat == models.ChallengeObuhvatKlubovi {
+		var team models.ClubChallengeTeam
+		err := tx.Where("challenge_id = ? AND klub_id = ?", ch.ID, *user.KlubID).First(&team).Error
+		if err == nil {
+			return team.ID, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+		var klub models.Klubovi
+		if err := tx.Select("id", "naziv").First(&klub, *user.KlubID).Error; err != nil {
+			return 0, err
+		}
+		klubID := klub.ID
+		team = models.ClubChallengeTeam{ChallengeID: ch.ID, Naziv: klub.Naziv, KlubID: &klubID}
+		if err := tx.Create(&team).Error; err != nil {
+			return 0, err
+		}
+		return team.ID, nil
+	}
+	if teamID == 0 {
+		return 0, errChallengeTeamRequired
+	}
+	var count int64
+	if err := tx.Model(&models.ClubChallengeTeam{}).Where("id = ? AND challenge_id = ?", teamID, ch.ID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, errChallengeTeamRequired
+	}
+	return teamID, nil
+}
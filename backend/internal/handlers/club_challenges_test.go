@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func testClubChallengeDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "club_challenges")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.Korisnik{},
+		&models.Klubovi{},
+		&models.UserDailySteps{},
+		&models.ClubChallenge{},
+		&models.ClubChallengeTeam{},
+		&models.ClubChallengeParticipant{},
+		&models.ClubChallengeResult{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func callClubChallenge(t *testing.T, db *gorm.DB, h gin.HandlerFunc, method, id string, user models.Korisnik, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/api/izazovi", bytes.NewReader(payload))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if id != "" {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+	c.Set("db", db)
+	c.Set("role", user.Role)
+	c.Set("username", user.Username)
+	if user.KlubID != nil {
+		c.Set("klubId", *user.KlubID)
+	}
+	h(c)
+	return w
+}
+
+func TestClubChallenge_InterClubTeamsPerClubAndClubScopeGuard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testClubChallengeDB(t)
+	k1 := models.Klubovi{Naziv: "PSK Jastrebac"}
+	k2 := models.Klubovi{Naziv: "PK Kopaonik"}
+	db.Create(&k1)
+	db.Create(&k2)
+	admin := models.Korisnik{Username: "admin1", Role: "admin", KlubID: &k1.ID}
+	clan1 := models.Korisnik{Username: "clan1", Role: "clan", KlubID: &k1.ID}
+	clan2 := models.Korisnik{Username: "clan2", Role: "clan", KlubID: &k2.ID}
+	for _, u := range []*models.Korisnik{&admin, &clan1, &clan2} {
+		db.Create(u)
+	}
+	today := todayDateUTC().Format("2006-01-02")
+
+	w := callClubChallenge(t, db, CreateClubChallenge, http.MethodPost, "", admin, gin.H{
+		"naziv": "Klubovi u koracima", "metrika": "koraci", "tip": "timski", "obuhvat": "klubovi",
+		"pocetakDatum": today, "krajDatum": today,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status %d body=%s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Izazov struct {
+			ID uint `json:"id"`
+		} `json:"izazov"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	id := strconv.FormatUint(uint64(created.Izazov.ID), 10)
+
+	for _, u := range []models.Korisnik{clan1, clan2} {
+		if w := callClubChallenge(t, db, JoinClubChallenge, http.MethodPost, id, u, nil); w.Code != http.StatusCreated {
+			t.Fatalf("join %s status %d body=%s", u.Username, w.Code, w.Body.String())
+		}
+	}
+	if w := callClubChallenge(t, db, JoinClubChallenge, http.MethodPost, id, clan1, nil); w.Code != http.StatusConflict {
+		t.Fatalf("second join should conflict, got %d", w.Code)
+	}
+	var teams []models.ClubChallengeTeam
+	db.Where("challenge_id = ?", created.Izazov.ID).Order("id").Find(&teams)
+	if len(teams) != 2 || teams[0].Naziv != k1.Naziv || teams[1].Naziv != k2.Naziv {
+		t.Fatalf("expected one team per club, got %+v", teams)
+	}
+
+	db.Create(&models.UserDailySteps{UserID: clan2.ID, Date: todayDateUTC(), Steps: 7000})
+	w = callClubChallenge(t, db, GetClubChallenge, http.MethodGet, id, clan1, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("detail status %d body=%s", w.Code, w.Body.String())
+	}
+	var detail struct {
+		PlasmanTim []struct {
+			Naziv    string  `json:"naziv"`
+			Vrednost float64 `json:"vrednost"`
+			Rang     int     `json:"rang"`
+		} `json:"plasmanTim"`
+		Me *struct {
+			Rang int `json:"rang"`
+		} `json:"me"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &detail)
+	if len(detail.PlasmanTim) != 2 || detail.PlasmanTim[0].Naziv != k2.Naziv || detail.PlasmanTim[0].Vrednost != 7000 {
+		t.Fatalf("expected live team standings led by %s, got %+v", k2.Naziv, detail.PlasmanTim)
+	}
+	if detail.Me == nil || detail.Me.Rang != 2 {
+		t.Fatalf("expected viewer ranked 2nd, got %+v", detail.Me)
+	}
+
+	w = callClubChallenge(t, db, CreateClubChallenge, http.MethodPost, "", admin, gin.H{
+		"naziv": "Samo naš klub", "metrika": "km", "pocetakDatum": today, "krajDatum": today,
+	})
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if w := callClubChallenge(t, db, JoinClubChallenge, http.MethodPost, strconv.FormatUint(uint64(created.Izazov.ID), 10), clan2, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("member of another club must not see club challenge, got %d", w.Code)
+	}
+}
@@ -424,10 +424,16 @@ func LeaveCurrentClub(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Niste član nijednog kluba."})
 		return
 	}
-	if err := db.Model(user).Updates(map[string]any{
-		"klub_id": nil,
-		"role":    "",
-	}).Error; err != nil {
+	klubID := *user.KlubID
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]any{
+			"klub_id": nil,
+			"role":    "",
+		}).Error; err != nil {
+			return err
+		}
+		return recordClubLeftTx(tx, user.ID, klubID)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri napuštanju kluba"})
 		return
 	}
@@ -456,10 +462,15 @@ func RemoveMemberFromClub(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Korisnik nije član vašeg kluba"})
 		return
 	}
-	if err := db.Model(&user).Updates(map[string]any{
-		"klub_id": nil,
-		"role":    "",
-	}).Error; err != nil {
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]any{
+			"klub_id": nil,
+			"role":    "",
+		}).Error; err != nil {
+			return err
+		}
+		return recordClubLeftTx(tx, user.ID, clubID)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri izbacivanju člana"})
 		return
 	}
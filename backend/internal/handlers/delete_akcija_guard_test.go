@@ -170,7 +170,7 @@ func TestDeleteAkcijaGuard_BlocksCompletedWithPrijave(t *testing.T) {
 
 func TestDeleteAkcijaGuard_BlocksCompletedWithTransakcija(t *testing.T) {
 	db := testDeleteAkcijaDB(t)
-	if err := db.AutoMigrate(&models.Transakcija{}); err != nil {
+	if err := db.AutoMigrate(&models.Transakcija{}, &models.Placanje{}); err != nil {
 		t.Fatal(err)
 	}
 	owner := seedDeleteGuardOwner(t, db, "own_tx")
@@ -263,7 +263,7 @@ func TestDeleteAkcijaGuard_ValidateDirectly(t *testing.T) {
 
 func TestDeleteAkcijaGuard_FinishThenDeleteConflict(t *testing.T) {
 	db := testDeleteAkcijaDB(t)
-	if err := db.AutoMigrate(&models.Transakcija{}); err != nil {
+	if err := db.AutoMigrate(&models.Transakcija{}, &models.Placanje{}); err != nil {
 		t.Fatal(err)
 	}
 	owner := seedDeleteGuardOwner(t, db, "own_fin_del")
@@ -288,7 +288,7 @@ func TestDeleteAkcijaGuard_FinishThenDeleteConflict(t *testing.T) {
 
 func TestDeleteAkcijaGuard_DeleteThenFinishNotFound(t *testing.T) {
 	db := testDeleteAkcijaDB(t)
-	if err := db.AutoMigrate(&models.Transakcija{}); err != nil {
+	if err := db.AutoMigrate(&models.Transakcija{}, &models.Placanje{}); err != nil {
 		t.Fatal(err)
 	}
 	owner := seedDeleteGuardOwner(t, db, "own_del_fin")
@@ -39,10 +39,20 @@ func testDeleteAkcijaDB(t *testing.T) *gorm.DB {
 		&models.AkcijaPrevoz{},
 		&models.AkcijaOprema{},
 		&models.AkcijaOpremaRent{},
+		&models.AkcijaRuta{},
+		&models.AkcijaPomeranje{},
+		&models.AkcijaVerzija{},
+		&models.AkcijaVodic{},
+		&models.AkcijaForma{},
+		&models.AkcijaUslovi{},
+		&models.AkcijaUslovIzuzetak{},
+		&models.AkcijaCenovnik{},
+		&models.AkcijaPromoKod{},
 		&models.FerrataGuideBookingRequest{},
 		&models.FerrataGuideBookingTarget{},
 		&models.PeakGuideBookingRequest{},
 		&models.PeakGuideBookingTarget{},
+		&models.SyncTombstone{},
 	); err != nil {
 		t.Fatalf("migrate: %v", err)
 	}
@@ -153,11 +163,39 @@ func TestDeleteAkcija_RemovesAllDirectChildren(t *testing.T) {
 	}).Error; err != nil {
 		t.Fatal(err)
 	}
+	if err := db.Create(&models.AkcijaRuta{
+		AkcijaID: akcija.ID, Polyline: "_p~iF~ps|U_ulLnnqC", Izvor: "polyline",
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.AkcijaPomeranje{
+		AkcijaID: akcija.ID, StariDatum: akcija.Datum, NoviDatum: akcija.Datum.AddDate(0, 0, 7), PotvrdaDo: akcija.Datum,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
 	if err := db.Create(&models.GuideActionRating{
 		AkcijaID: akcija.ID, RaterKorisnikID: member.ID, GuideProfileID: 1, GuideKorisnikID: owner.ID,
 	}).Error; err != nil {
 		t.Fatal(err)
 	}
+	if err := db.Create(&models.AkcijaVodic{AkcijaID: akcija.ID, KorisnikID: member.ID, Uloga: models.AkcijaVodicUlogaZatvarac}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.AkcijaForma{AkcijaID: akcija.ID, Polja: `[{"id":"p1","label":"Majica","tip":"text"}]`}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.AkcijaUslovi{AkcijaID: akcija.ID, MinGodine: 18}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.AkcijaUslovIzuzetak{AkcijaID: akcija.ID, KorisnikID: member.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.AkcijaCenovnik{AkcijaID: akcija.ID, GrupaMinOsoba: 4, GrupaPopustProcenat: 10}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.AkcijaPromoKod{AkcijaID: akcija.ID, Kod: "LETO", PopustProcenat: 10, Aktivan: true}).Error; err != nil {
+		t.Fatal(err)
+	}
 
 	code, _ := callDeleteAkcija(t, db, akcija.ID, owner.Username, "vodic")
 	if code != http.StatusOK {
@@ -176,6 +214,15 @@ func TestDeleteAkcija_RemovesAllDirectChildren(t *testing.T) {
 		{"oprema", &models.AkcijaOprema{}},
 		{"rent", &models.AkcijaOpremaRent{}},
 		{"rating", &models.GuideActionRating{}},
+		{"ruta", &models.AkcijaRuta{}},
+		{"pomeranja", &models.AkcijaPomeranje{}},
+		{"verzije", &models.AkcijaVerzija{}},
+		{"vodici", &models.AkcijaVodic{}},
+		{"forma", &models.AkcijaForma{}},
+		{"uslovi", &models.AkcijaUslovi{}},
+		{"izuzeci", &models.AkcijaUslovIzuzetak{}},
+		{"cenovnik", &models.AkcijaCenovnik{}},
+		{"promo", &models.AkcijaPromoKod{}},
 	}
 	for _, c := range checks {
 		var n int64
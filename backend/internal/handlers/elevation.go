@@ -0,0 +1,403 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"beleg-app/backend/internal/dem"
+	"beleg-app/backend/internal/geo"
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	akcijaRutaMaxPoints  = 50000
+	akcijaRutaMaxGPXSize = 10 << 20
+)
+
+var (
+	demOnce    sync.Once
+	demService *dem.Service
+)
+
+// demFor vraća DEM servis (DEM_DIR) ili nil kada nije podešen; testovi ga zamenjuju.
+var demFor = func() *dem.Service {
+	demOnce.Do(func() { demService = dem.FromEnv() })
+	return demService
+}
+
+// correctActivityAltitudes popunjava/ispravlja visine tačaka aktivnosti sa terena i, kada klijent nije poslao uspon,
+// upisuje ukupan uspon iz profila. Bez DEM-a ostaju GPS visine.
+func correctActivityAltitudes(db *gorm.DB, activityID uint) {
+	svc := demFor()
+	if svc == nil {
+		return
+	}
+	var points []models.TrackedActivityPoint
+	if err := db.Select("id", "seq", "lat", "lng", "altitude").
+		Where("activity_id = ?", activityID).Order("seq ASC").Find(&points).Error; err != nil {
+		log.Printf("tracked activity %d: DEM visine: %v", activityID, err)
+		return
+	}
+	profilePoints := activityProfilePoints(points)
+	changed := svc.Correct(profilePoints)
+	if len(changed) > 0 {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, i := range changed {
+				if err := tx.Model(&models.TrackedActivityPoint{}).Where("id = ?", points[i].ID).
+					Updates(map[string]any{"altitude": *profilePoints[i].Elevation, "altitude_dem": true}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("tracked activity %d: čuvanje DEM visina: %v", activityID, err)
+			return
+		}
+	}
+	if prof, ok := dem.BuildProfile(profilePoints); ok {
+		if err := db.Model(&models.TrackedActivity{}).
+			Where("id = ? AND (elevation_gain_m IS NULL OR elevation_gain_m = 0)", activityID).
+			Update("elevation_gain_m", prof.AscentM).Error; err != nil {
+			log.Printf("tracked activity %d: uspon iz profila: %v", activityID, err)
+		}
+	}
+}
+
+func activityProfilePoints(points []models.TrackedActivityPoint) []dem.ProfilePoint {
+	out := make([]dem.ProfilePoint, len(points))
+	for i, p := range points {
+		out[i] = dem.ProfilePoint{Lat: p.Lat, Lng: p.Lng, Elevation: p.Altitude}
+	}
+	return out
+}
+
+// GetTrackedActivityElevationProfile GET /activities/:id/elevation-profile — distanca/visina, uspon, spust i najveći nagib.
+func GetTrackedActivityElevationProfile(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravan ID"})
+		return
+	}
+	activity, okAct := findOwnedActivity(c, db, user.ID, uint(id))
+	if !okAct {
+		return
+	}
+	var points []models.TrackedActivityPoint
+	if err := db.Select("seq", "lat", "lng", "altitude", "altitude_dem").
+		Where("activity_id = ?", activity.ID).Order("seq ASC").Find(&points).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju tačaka"})
+		return
+	}
+	profilePoints := activityProfilePoints(points)
+	izvor := "gps"
+	for _, p := range points {
+		if p.AltitudeDEM {
+			izvor = "dem"
+			break
+		}
+	}
+	// Aktivnost još nije obrađena (ili je starija od DEM-a): nedostajuće visine se dopunjuju samo za prikaz.
+	if len(demFor().Correct(profilePoints)) > 0 {
+		izvor = "dem"
+	}
+	prof, ok := dem.BuildProfile(profilePoints)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aktivnost nema podatke o visini"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"activityId": activity.ID, "izvor": izvor, "profil": prof})
+}
+
+type akcijaRutaPointInput struct {
+	Lat float64  `json:"lat"`
+	Lng float64  `json:"lng"`
+	Ele *float64 `json:"ele,omitempty"`
+}
+
+type akcijaRutaBody struct {
+	GPX      string                 `json:"gpx,omitempty"`
+	Polyline string                 `json:"polyline,omitempty"`
+	Points   []akcijaRutaPointInput `json:"points,omitempty"`
+}
+
+type gpxPoint struct {
+	Lat float64  `xml:"lat,attr"`
+	Lon float64  `xml:"lon,attr"`
+	Ele *float64 `xml:"ele"`
+}
+
+type gpxFile struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+var errAkcijaRutaInvalid = errors.New("neispravna ruta")
+
+// parseAkcijaRuta čita trasu iz GPX-a (track ili route), encoded polyline-a ili niza tačaka.
+func parseAkcijaRuta(body akcijaRutaBody) ([]dem.ProfilePoint, string, error) {
+	var pts []dem.ProfilePoint
+	izvor := ""
+	switch {
+	case strings.TrimSpace(body.GPX) != "":
+		if len(body.GPX) > akcijaRutaMaxGPXSize {
+			return nil, "", errAkcijaRutaInvalid
+		}
+		var f gpxFile
+		if err := xml.Unmarshal([]byte(body.GPX), &f); err != nil {
+			return nil, "", errAkcijaRutaInvalid
+		}
+		for _, t := range f.Tracks {
+			for _, s := range t.Segments {
+				for _, p := range s.Points {
+					pts = append(pts, dem.ProfilePoint{Lat: p.Lat, Lng: p.Lon, Elevation: p.Ele})
+				}
+			}
+		}
+		if len(pts) == 0 {
+			for _, r := range f.Routes {
+				for _, p := range r.Points {
+					pts = append(pts, dem.ProfilePoint{Lat: p.Lat, Lng: p.Lon, Elevation: p.Ele})
+				}
+			}
+		}
+		izvor = "gpx"
+	case strings.TrimSpace(body.Polyline) != "":
+		decoded, ok := geo.DecodePolyline(strings.TrimSpace(body.Polyline))
+		if !ok {
+			return nil, "", errAkcijaRutaInvalid
+		}
+		for _, p := range decoded {
+			pts = append(pts, dem.ProfilePoint{Lat: p.Lat, Lng: p.Lng})
+		}
+		izvor = "polyline"
+	default:
+		for _, p := range body.Points {
+			pts = append(pts, dem.ProfilePoint{Lat: p.Lat, Lng: p.Lng, Elevation: p.Ele})
+		}
+		izvor = "tacke"
+	}
+	if len(pts) < 2 || len(pts) > akcijaRutaMaxPoints {
+		return nil, "", errAkcijaRutaInvalid
+	}
+	for _, p := range pts {
+		if !geo.ValidLatLng(p.Lat, p.Lng) {
+			return nil, "", errAkcijaRutaInvalid
+		}
+	}
+	return pts, izvor, nil
+}
+
+func akcijaRutaPoints(ruta models.AkcijaRuta) []dem.ProfilePoint {
+	decoded, _ := geo.DecodePolyline(ruta.Polyline)
+	var visine []*float64
+	if ruta.Visine != "" {
+		_ = json.Unmarshal([]byte(ruta.Visine), &visine)
+	}
+	out := make([]dem.ProfilePoint, len(decoded))
+	for i, p := range decoded {
+		out[i] = dem.ProfilePoint{Lat: p.Lat, Lng: p.Lng}
+		if i < len(visine) {
+			out[i].Elevation = visine[i]
+		}
+	}
+	return out
+}
+
+// PutAkcijaRuta PUT /akcije/:id/ruta — uvoz trase akcije; visine se popunjavaju/ispravljaju sa terena (DEM).
+// Kumulativni uspon i dužina staze akcije se popunjavaju iz profila samo ako ih organizator nije uneo.
+func PutAkcijaRuta(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		return
+	}
+	if !helpers.CanManageAkcijaEx(c, db, &akcija) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo admin ili vodič kluba koji je objavio akciju može da menja trasu"})
+		return
+	}
+	var body akcijaRutaBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravan zahtev"})
+		return
+	}
+	pts, izvor, err := parseAkcijaRuta(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Trasa mora imati 2–50000 ispravnih tačaka (GPX, polyline ili points)"})
+		return
+	}
+	demFor().Correct(pts)
+
+	// Čuva se trasa pojednostavljena na detaljnom nivou mape, sa visinom svake zadržane tačke.
+	latlngs := make([]geo.LatLng, len(pts))
+	for i, p := range pts {
+		latlngs[i] = geo.LatLng{Lat: p.Lat, Lng: p.Lng}
+	}
+	zoom := geo.PolylineZoomLevels[len(geo.PolylineZoomLevels)-1]
+	idx := geo.SimplifyDouglasPeuckerIndices(latlngs, geo.ToleranceMetersForZoom(zoom, latlngs[0].Lat))
+	kept := make([]geo.LatLng, 0, len(idx))
+	visine := make([]*float64, 0, len(idx))
+	for _, i := range idx {
+		kept = append(kept, latlngs[i])
+		visine = append(visine, pts[i].Elevation)
+	}
+	// Profil (uspon, nagib) se računa iz svih tačaka, ne iz pojednostavljene trase.
+	prof, hasProfile := dem.BuildProfile(pts)
+	rawVisine, _ := json.Marshal(visine)
+
+	ruta := models.AkcijaRuta{
+		AkcijaID: akcija.ID,
+		Polyline: geo.EncodePolyline(kept),
+		Visine:   string(rawVisine),
+		Izvor:    izvor,
+		UvezaoID: user.ID,
+	}
+	if hasProfile {
+		ruta.DuzinaM, ruta.UsponM, ruta.SpustM, ruta.MaxNagibPct = prof.LengthM, prof.AscentM, prof.DescentM, prof.MaxGradePct
+	} else {
+		for i := 1; i < len(pts); i++ {
+			ruta.DuzinaM += geo.DistanceKmHaversine(pts[i-1].Lat, pts[i-1].Lng, pts[i].Lat, pts[i].Lng) * 1000
+		}
+		ruta.DuzinaM = math.Round(ruta.DuzinaM)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("akcija_id = ?", akcija.ID).Delete(&models.AkcijaRuta{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&ruta).Error; err != nil {
+			return err
+		}
+		updates := map[string]any{}
+		if akcija.UkupnoMetaraUsponaAkcija == 0 && hasProfile {
+			updates["ukupno_metara_uspona_akcija"] = int(ruta.UsponM)
+		}
+		if akcija.UkupnoKmAkcija == 0 {
+			updates["ukupno_km_akcija"] = math.Round(ruta.DuzinaM/100) / 10
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&models.Akcija{}).Where("id = ?", akcija.ID).Updates(updates).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju trase"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ruta": ruta})
+}
+
+// DeleteAkcijaRuta DELETE /akcije/:id/ruta — uklanja uvezenu trasu (uneti uspon i dužina akcije ostaju).
+func DeleteAkcijaRuta(c *gin.Context) {
+	db := DB(c)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		return
+	}
+	if !helpers.CanManageAkcijaEx(c, db, &akcija) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo admin ili vodič kluba koji je objavio akciju može da menja trasu"})
+		return
+	}
+	if err := db.Where("akcija_id = ?", akcija.ID).Delete(&models.AkcijaRuta{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri brisanju trase"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Trasa obrisana"})
+}
+
+// viewerCanSeeAkcijaDetails: javna akcija, akcija sopstvenog (izabranog) kluba, ili vodič/učesnik privatne ture.
+// Neobjavljenu akciju vidi samo ko njome upravlja (autor/vodič, vodič ili admin kluba domaćina) i ko-vodiči.
+func viewerCanSeeAkcijaDetails(c *gin.Context, db *gorm.DB, akcija *models.Akcija, viewer *models.Korisnik) bool {
+	if !helpers.IsAkcijaObjavljena(akcija) {
+		return helpers.CanGuideAkcijaEx(c, db, akcija)
+	}
+	if akcija.Javna {
+		return true
+	}
+	if akcija.KlubID != nil {
+		if clubID, ok := helpers.GetEffectiveClubID(c, db); ok && clubID == *akcija.KlubID {
+			return true
+		}
+	}
+	return viewerCanAccessPrivateAkcija(db, akcija, viewer)
+}
+
+// GetAkcijaElevationProfile GET /akcije/:id/elevation-profile — profil uvezene trase akcije.
+func GetAkcijaElevationProfile(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID akcije"})
+		return
+	}
+	var akcija models.Akcija
+	if err := db.First(&akcija, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nije pronađena"})
+		return
+	}
+	if !viewerCanSeeAkcijaDetails(c, db, &akcija, user) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pristup ovoj akciji"})
+		return
+	}
+	var rute []models.AkcijaRuta
+	if err := db.Where("akcija_id = ?", akcija.ID).Limit(1).Find(&rute).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju trase"})
+		return
+	}
+	if len(rute) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akcija nema uvezenu trasu"})
+		return
+	}
+	pts := akcijaRutaPoints(rute[0])
+	demFor().Correct(pts)
+	prof, ok := dem.BuildProfile(pts)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trasa nema podatke o visini"})
+		return
+	}
+	// Sažetak je računat iz svih uvezenih tačaka; nizovi su iz sačuvane (pojednostavljene) trase.
+	prof.LengthM, prof.AscentM, prof.DescentM, prof.MaxGradePct = rute[0].DuzinaM, rute[0].UsponM, rute[0].SpustM, rute[0].MaxNagibPct
+	c.JSON(http.StatusOK, gin.H{"akcijaId": akcija.ID, "polyline": rute[0].Polyline, "izvor": rute[0].Izvor, "profil": prof})
+}
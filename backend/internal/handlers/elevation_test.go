@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/dem"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// useTestDEM postavlja DEM sa pločicom N43E021 u kojoj teren raste 1000 m po stepenu ka severu (43° = 1000 m).
+func useTestDEM(t *testing.T) {
+	t.Helper()
+	const size = 101
+	raw := make([]byte, size*size*2)
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			binary.BigEndian.PutUint16(raw[(row*size+col)*2:], uint16(1000+(size-1-row)*10))
+		}
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "N43E021.hgt"), raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	svc := dem.NewService(dir)
+	prev := demFor
+	demFor = func() *dem.Service { return svc }
+	t.Cleanup(func() { demFor = prev })
+}
+
+func TestCorrectActivityAltitudes_FillsMissingAndFixesOutliers(t *testing.T) {
+	db, user := testActivityPointsDB(t)
+	useTestDEM(t)
+	activity := models.TrackedActivity{UserID: user.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: time.Now().UTC()}
+	db.Create(&activity)
+	good, wrong := 1302.0, 150.0
+	points := []models.TrackedActivityPoint{
+		{ActivityID: activity.ID, Seq: 0, Lat: 43.300, Lng: 21.9, RecordedAt: time.Now()},
+		{ActivityID: activity.ID, Seq: 1, Lat: 43.302, Lng: 21.9, Altitude: &good, RecordedAt: time.Now()},
+		{ActivityID: activity.ID, Seq: 2, Lat: 43.310, Lng: 21.9, Altitude: &wrong, RecordedAt: time.Now()},
+	}
+	db.Create(&points)
+
+	correctActivityAltitudes(db, activity.ID)
+	var stored []models.TrackedActivityPoint
+	db.Where("activity_id = ?", activity.ID).Order("seq").Find(&stored)
+	if stored[0].Altitude == nil || *stored[0].Altitude != 1300 || !stored[0].AltitudeDEM {
+		t.Fatalf("missing altitude must come from DEM: %+v", stored[0])
+	}
+	if *stored[1].Altitude != good || stored[1].AltitudeDEM {
+		t.Fatalf("GPS altitude close to terrain must stay: %+v", stored[1])
+	}
+	if *stored[2].Altitude != 1310 || !stored[2].AltitudeDEM {
+		t.Fatalf("outlier must be replaced with terrain: %+v", stored[2])
+	}
+	var reloaded models.TrackedActivity
+	db.First(&reloaded, activity.ID)
+	if reloaded.ElevationGainM != 10 {
+		t.Fatalf("elevation gain from profile = %v, want 10", reloaded.ElevationGainM)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/activities/1/elevation-profile", nil)
+	c.Params = gin.Params{{Key: "id", Value: fmt.Sprint(activity.ID)}}
+	c.Set("db", db)
+	c.Set("username", user.Username)
+	GetTrackedActivityElevationProfile(c)
+	var resp struct {
+		Izvor  string      `json:"izvor"`
+		Profil dem.Profile `json:"profil"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || resp.Izvor != "dem" || len(resp.Profil.DistanceM) != 3 || resp.Profil.AscentM != 10 {
+		t.Fatalf("profile: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func callAkcijaRuta(t *testing.T, db *gorm.DB, user models.Korisnik, h gin.HandlerFunc, method string, akcijaID uint, body any) (int, map[string]any) {
+	t.Helper()
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/api/akcije/ruta", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: fmt.Sprint(akcijaID)}}
+	c.Set("db", db)
+	c.Set("username", user.Username)
+	c.Set("role", user.Role)
+	h(c)
+	var resp map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+func TestPutAkcijaRuta_GPXImportFillsProfileAndAction(t *testing.T) {
+	db, user := testActivityPointsDB(t)
+	if err := db.AutoMigrate(&models.Akcija{}, &models.Prijava{}, &models.AkcijaRuta{}); err != nil {
+		t.Fatal(err)
+	}
+	useTestDEM(t)
+	vodic := models.Korisnik{Username: "vodic_rute", Role: "vodic"}
+	db.Create(&vodic)
+	akcija := models.Akcija{Naziv: "Suva planina", Datum: time.Now().Add(72 * time.Hour), VodicID: vodic.ID, AddedByID: vodic.ID, OrganizatorTip: "vodic"}
+	db.Create(&akcija)
+
+	// Uspon 43.30 → 43.32 pa silazak do 43.31; GPX bez visina za deo tačaka.
+	var gpx strings.Builder
+	gpx.WriteString(`<?xml version="1.0"?><gpx><trk><trkseg>`)
+	for i := 0; i <= 30; i++ {
+		lat := 43.30 + float64(min(i, 20))*0.001 - float64(max(i-20, 0))*0.001
+		if i%2 == 0 {
+			fmt.Fprintf(&gpx, `<trkpt lat="%.5f" lon="21.9"><ele>%.0f</ele></trkpt>`, lat, 1000+(lat-43)*1000)
+		} else {
+			fmt.Fprintf(&gpx, `<trkpt lat="%.5f" lon="21.9"></trkpt>`, lat)
+		}
+	}
+	gpx.WriteString(`</trkseg></trk></gpx>`)
+
+	if code, _ := callAkcijaRuta(t, db, user, PutAkcijaRuta, http.MethodPut, akcija.ID, gin.H{"gpx": gpx.String()}); code != http.StatusForbidden {
+		t.Fatalf("non-organizer must not import the route, got %d", code)
+	}
+	code, resp := callAkcijaRuta(t, db, vodic, PutAkcijaRuta, http.MethodPut, akcija.ID, gin.H{"gpx": gpx.String()})
+	if code != http.StatusOK {
+		t.Fatalf("import: %d %v", code, resp)
+	}
+	ruta, _ := resp["ruta"].(map[string]any)
+	if ruta["izvor"] != "gpx" || ruta["ukupnoUsponM"] != float64(20) || ruta["ukupnoSpustM"] != float64(10) {
+		t.Fatalf("unexpected route summary: %v", ruta)
+	}
+	var reloaded models.Akcija
+	db.First(&reloaded, akcija.ID)
+	if reloaded.UkupnoMetaraUsponaAkcija != 20 || reloaded.UkupnoKmAkcija != 3.3 {
+		t.Fatalf("empty action totals must be filled from the route: %d m, %v km", reloaded.UkupnoMetaraUsponaAkcija, reloaded.UkupnoKmAkcija)
+	}
+
+	code, resp = callAkcijaRuta(t, db, vodic, GetAkcijaElevationProfile, http.MethodGet, akcija.ID, nil)
+	profil, _ := resp["profil"].(map[string]any)
+	if code != http.StatusOK || profil["ukupnoUsponM"] != float64(20) || profil["maxVisinaM"] != float64(1320) {
+		t.Fatalf("profile: %d %v", code, resp)
+	}
+	if code, _ := callAkcijaRuta(t, db, user, GetAkcijaElevationProfile, http.MethodGet, akcija.ID, nil); code != http.StatusForbidden {
+		t.Fatalf("private action profile must be hidden from outsiders, got %d", code)
+	}
+	if code, _ := callAkcijaRuta(t, db, vodic, PutAkcijaRuta, http.MethodPut, akcija.ID, gin.H{"gpx": "<gpx>"}); code != http.StatusBadRequest {
+		t.Fatalf("broken GPX must be rejected, got %d", code)
+	}
+}
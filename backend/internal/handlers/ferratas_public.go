@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"beleg-app/backend/internal/helpers"
 	"beleg-app/backend/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -34,7 +36,7 @@ func ListFerratas(c *gin.Context) {
 	for i := range rows {
 		var cnt int64
 		db.Model(&models.Akcija{}).
-			Where("ferrata_id = ? AND tip_akcije = ? AND javna = ? AND is_completed = ? AND is_cancelled = ? AND start_at IS NOT NULL AND start_at > NOW()",
+			Where("ferrata_id = ? AND tip_akcije = ? AND javna = ? AND is_completed = ? AND is_cancelled = ? AND start_at IS NOT NULL AND start_at > NOW() AND "+helpers.SQLAkcijaObjavljena,
 				rows[i].ID, "via_ferrata", true, false, false).
 			Count(&cnt)
 		out = append(out, ferrataToMap(&rows[i], cnt))
@@ -57,10 +59,19 @@ func GetFerrataBySlug(c *gin.Context) {
 	}
 	var cnt int64
 	db.Model(&models.Akcija{}).
-		Where("ferrata_id = ? AND tip_akcije = ? AND javna = ? AND is_completed = ? AND is_cancelled = ? AND start_at IS NOT NULL AND start_at > NOW()",
+		Where("ferrata_id = ? AND tip_akcije = ? AND javna = ? AND is_completed = ? AND is_cancelled = ? AND start_at IS NOT NULL AND start_at > NOW() AND "+helpers.SQLAkcijaObjavljena,
 			f.ID, "via_ferrata", true, false, false).
 		Count(&cnt)
-	c.JSON(http.StatusOK, gin.H{"ferrata": ferrataToMap(&f, cnt)})
+	out := ferrataToMap(&f, cnt)
+	if m := ferrataHikingMinutes(&f); m > 0 {
+		// ?datum=YYYY-MM-DD — najkasniji bezbedan polazak za taj dan (zalazak na glavnoj tački ferate).
+		var day time.Time
+		if d, err := time.Parse("2006-01-02", strings.TrimSpace(c.Query("datum"))); err == nil {
+			day = d
+		}
+		out["procenaVremena"] = newHikingEstimate(m, "ferrata", 1, 0, day, f.Lat, f.Lng)
+	}
+	c.JSON(http.StatusOK, gin.H{"ferrata": out})
 }
 
 // GetFerrataContactsByFerrataID GET /api/ferratas/:id/contacts
@@ -114,7 +125,7 @@ func GetFerrataUpcomingActions(c *gin.Context) {
 
 	var akcije []models.Akcija
 	if err := db.Preload("Klub").
-		Where("ferrata_id = ? AND tip_akcije = ? AND javna = ? AND is_completed = ? AND is_cancelled = ? AND start_at IS NOT NULL AND start_at > NOW()",
+		Where("ferrata_id = ? AND tip_akcije = ? AND javna = ? AND is_completed = ? AND is_cancelled = ? AND start_at IS NOT NULL AND start_at > NOW() AND "+helpers.SQLAkcijaObjavljena,
 			id, "via_ferrata", true, false, false).
 		Order("start_at ASC").
 		Find(&akcije).Error; err != nil {
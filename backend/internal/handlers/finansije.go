@@ -348,9 +348,6 @@ func GetClanarine(c *gin.Context) {
 		return
 	}
 
-	from := time.Date(godina, 1, 1, 0, 0, 0, 0, time.UTC)
-	to := time.Date(godina, 12, 31, 23, 59, 59, 0, time.UTC)
-
 	// Za svakog korisnika proveri da li ima uplatu sa ClanarinaKorisnikID u toj godini
 	type ClanarinaStatus struct {
 		ID       uint   `json:"id"`
@@ -360,13 +357,12 @@ func GetClanarine(c *gin.Context) {
 	}
 	var result []ClanarinaStatus
 	for _, k := range korisnici {
-		var count int64
-		db.Model(&models.Transakcija{}).Where("tip = ? AND clanarina_korisnik_id = ? AND datum >= ? AND datum <= ?", "uplata", k.ID, from, to).Count(&count)
+		platio, _ := helpers.ClanarinaPlacenaZaGodinu(db, k.ID, godina)
 		result = append(result, ClanarinaStatus{
 			ID:       k.ID,
 			FullName: k.FullName,
 			Username: k.Username,
-			Platio:   count > 0,
+			Platio:   platio,
 		})
 	}
 
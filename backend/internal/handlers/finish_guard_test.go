@@ -36,6 +36,7 @@ func testFinishHandlerDB(t *testing.T) *gorm.DB {
 		&models.ActionSignupRequest{},
 		&models.ActionInviteLink{},
 		&models.Transakcija{},
+		&models.Placanje{},
 		&models.Obavestenje{},
 	); err != nil {
 		t.Fatalf("migrate: %v", err)
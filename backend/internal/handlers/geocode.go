@@ -1,58 +1,94 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"beleg-app/backend/internal/geo"
+
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var (
+	geocodeExternalOnce sync.Once
+	geocodeExternal     geo.Geocoder
 )
 
-type nominatimHit struct {
-	Lat         string `json:"lat"`
-	Lon         string `json:"lon"`
-	DisplayName string `json:"display_name"`
+// geocoderFor vraća lanac provajdera za zahtev (katalog + keširani spoljni); testovi ga zamenjuju.
+var geocoderFor = func(db *gorm.DB) geo.Geocoder {
+	geocodeExternalOnce.Do(func() { geocodeExternal = geo.ExternalGeocoderFromEnv() })
+	return geo.NewGeocoder(db, geocodeExternal)
+}
+
+func geocodeLimit(c *gin.Context) int {
+	limit := 5
+	if n, err := strconv.Atoi(c.Query("limit")); err == nil && n > 0 {
+		if n > 10 {
+			limit = 10
+		} else {
+			limit = n
+		}
+	}
+	return limit
+}
+
+func respondGeocodeError(c *gin.Context, err error) {
+	if errors.Is(err, geo.ErrGeocoderThrottled) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Servis lokacije je zauzet, pokušajte ponovo za nekoliko sekundi."})
+		return
+	}
+	c.JSON(http.StatusBadGateway, gin.H{"error": "Servis lokacije trenutno nije dostupan."})
 }
 
-// GetGeocodeSearch GET /api/geocode?q= — proxy ka Nominatim-u (OpenStreetMap) radi pretrage mjesta/planina u formi.
+// GetGeocodeSearch GET /api/geocode?q=&limit= — mesta/planine iz kataloga i spoljnog geokodera (keširano).
+// lat/lng/displayName na vrhu odgovora su prvi rezultat (kompatibilnost sa starim klijentima).
 func GetGeocodeSearch(c *gin.Context) {
 	q := strings.TrimSpace(c.Query("q"))
 	if len(q) < 3 || len(q) > 200 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Upit za lokaciju: 3–200 karaktera."})
 		return
 	}
-	endpoint := "https://nominatim.openstreetmap.org/search?format=json&limit=1&q=" + url.QueryEscape(q)
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 12*time.Second)
+	defer cancel()
+	results, err := geocoderFor(DB(c)).Search(ctx, q, geocodeLimit(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri pripremi zahtjeva."})
+		respondGeocodeError(c, err)
 		return
 	}
-	req.Header.Set("User-Agent", "BelegMountaineeringApp/1.0")
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{"error": "Servis lokacije trenutno nije dostupan."})
+	if len(results) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nema rezultata za ovaj upit."})
 		return
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		c.JSON(http.StatusBadGateway, gin.H{"error": "Geokodiranje nije uspjelo."})
+	first := results[0]
+	c.JSON(http.StatusOK, gin.H{"lat": first.Lat, "lng": first.Lng, "displayName": first.DisplayName, "rezultati": results})
+}
+
+// GetGeocodeReverse GET /api/geocode/reverse?lat=&lng=&limit= — šta je na tački izabranoj na mapi.
+func GetGeocodeReverse(c *gin.Context) {
+	latStr := c.Query("lat")
+	lngStr := c.Query("lng")
+	lat, err1 := strconv.ParseFloat(latStr, 64)
+	lng, err2 := strconv.ParseFloat(lngStr, 64)
+	if err1 != nil || err2 != nil || strings.TrimSpace(latStr) == "" || strings.TrimSpace(lngStr) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parametri lat i lng su obavezni i moraju biti brojevi."})
 		return
 	}
-	var hits []nominatimHit
-	if err := json.NewDecoder(resp.Body).Decode(&hits); err != nil || len(hits) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Nema rezultata za ovaj upit."})
+	if !geo.ValidLatLng(lat, lng) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Koordinate nisu u dozvoljenom opsegu."})
 		return
 	}
-	h := hits[0]
-	lat, e1 := strconv.ParseFloat(h.Lat, 64)
-	lng, e2 := strconv.ParseFloat(h.Lon, 64)
-	if e1 != nil || e2 != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Nepoznat format odgovora servisa."})
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 12*time.Second)
+	defer cancel()
+	results, err := geocoderFor(DB(c)).Reverse(ctx, lat, lng, geocodeLimit(c))
+	if err != nil {
+		respondGeocodeError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"lat": lat, "lng": lng, "displayName": h.DisplayName})
+	c.JSON(http.StatusOK, gin.H{"rezultati": results})
 }
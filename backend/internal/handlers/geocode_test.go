@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"beleg-app/backend/internal/geo"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type stubGeocoder struct {
+	results []geo.GeocodeResult
+	err     error
+}
+
+func (s stubGeocoder) Name() string { return "stub" }
+
+func (s stubGeocoder) Search(ctx context.Context, q string, limit int) ([]geo.GeocodeResult, error) {
+	return s.results, s.err
+}
+
+func (s stubGeocoder) Reverse(ctx context.Context, lat, lng float64, limit int) ([]geo.GeocodeResult, error) {
+	return s.results, s.err
+}
+
+func callGeocode(t *testing.T, h gin.HandlerFunc, g geo.Geocoder, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	prev := geocoderFor
+	geocoderFor = func(*gorm.DB) geo.Geocoder { return g }
+	t.Cleanup(func() { geocoderFor = prev })
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/geocode?"+query, nil)
+	c.Set("db", (*gorm.DB)(nil))
+	h(c)
+	return w
+}
+
+func TestGeocode_MultipleResultsReverseAndProviderErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hits := []geo.GeocodeResult{
+		{Lat: 43.2736, Lng: 20.8126, DisplayName: "Pančićev vrh, Kopaonik", Tip: "vrh", ID: 7, Izvor: geo.IzvorLocal},
+		{Lat: 43.30, Lng: 20.85, DisplayName: "Brzeće, Srbija", Izvor: geo.IzvorNominatim},
+	}
+	w := callGeocode(t, GetGeocodeSearch, stubGeocoder{results: hits}, "q=kopaonik")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Lat       float64             `json:"lat"`
+		Rezultati []geo.GeocodeResult `json:"rezultati"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Lat != 43.2736 || len(resp.Rezultati) != 2 {
+		t.Fatalf("expected legacy lat plus all results, got %s", w.Body.String())
+	}
+
+	if w := callGeocode(t, GetGeocodeSearch, stubGeocoder{}, "q=nigde"); w.Code != http.StatusNotFound {
+		t.Fatalf("empty result should be 404, got %d", w.Code)
+	}
+	if w := callGeocode(t, GetGeocodeSearch, stubGeocoder{err: geo.ErrGeocoderThrottled}, "q=kopaonik"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("throttled provider should be 429, got %d", w.Code)
+	}
+	if w := callGeocode(t, GetGeocodeReverse, stubGeocoder{results: hits}, "lat=95&lng=20"); w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid coordinates should be 400, got %d", w.Code)
+	}
+	if w := callGeocode(t, GetGeocodeReverse, stubGeocoder{}, "lat=43.27&lng=20.81"); w.Code != http.StatusOK {
+		t.Fatalf("reverse with no place should be 200, got %d", w.Code)
+	}
+}
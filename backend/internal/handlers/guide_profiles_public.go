@@ -97,7 +97,8 @@ func ListGuidesNearby(c *gin.Context) {
 
 	db := DB(c)
 	var rows []models.GuideProfile
-	if err := db.Where("status = ? AND base_lat IS NOT NULL AND base_lng IS NOT NULL", models.GuideStatusApproved).
+	q := db.Where("status = ? AND base_lat IS NOT NULL AND base_lng IS NOT NULL", models.GuideStatusApproved)
+	if err := whereInBBox(q, "base_lat", "base_lng", geo.BBoxAround(lat, lng, radiusKm)).
 		Preload("Korisnik").
 		Find(&rows).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju vodiča"})
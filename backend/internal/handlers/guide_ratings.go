@@ -15,10 +15,23 @@ import (
 )
 
 type guideRatingBody struct {
+	VodicID  uint   `json:"vodicId"` // glavni vodič ako je 0
 	Ocena    *int   `json:"ocena"`
 	Komentar string `json:"komentar"`
 }
 
+// ratedGuideID: vodič koga učesnik ocenjuje — traženi (glavni ili ko-vodič) ili glavni vodič akcije.
+// Vraća 0 ako traženi korisnik nije vodič akcije.
+func ratedGuideID(db *gorm.DB, akcija *models.Akcija, requested uint) uint {
+	if requested == 0 {
+		return akcija.VodicID
+	}
+	if !helpers.IsAkcijaGuide(db, akcija, requested) {
+		return 0
+	}
+	return requested
+}
+
 func recalcGuideProfileRatings(db *gorm.DB, guideProfileID uint) error {
 	var rows []models.GuideActionRating
 	if err := db.Where("guide_profile_id = ? AND ocena IS NOT NULL", guideProfileID).Find(&rows).Error; err != nil {
@@ -62,19 +75,23 @@ func loadAkcijaForGuideRating(c *gin.Context, db *gorm.DB) (*models.Akcija, bool
 	return &akcija, true
 }
 
-func participantCanRateGuide(c *gin.Context, db *gorm.DB, akcija *models.Akcija, rater *models.Korisnik) bool {
+func participantCanRateGuide(c *gin.Context, db *gorm.DB, akcija *models.Akcija, rater *models.Korisnik, guideID uint) bool {
 	if !akcija.IsCompleted {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Ocenjivanje je moguće tek nakon završetka akcije"})
 		return false
 	}
-	if akcija.VodicID == 0 {
+	if guideID == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Akcija nema dodeljenog vodiča"})
 		return false
 	}
-	if rater.ID == akcija.VodicID {
+	if rater.ID == guideID {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Vodič ne može oceniti sam sebe"})
 		return false
 	}
+	if helpers.IsAkcijaGuide(db, akcija, rater.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Vodiči akcije ne ocenjuju jedni druge"})
+		return false
+	}
 	var prijava models.Prijava
 	err := db.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, rater.ID).First(&prijava).Error
 	if err != nil {
@@ -108,7 +125,7 @@ func guideRatingNotApplicable() gin.H {
 	return gin.H{"submitted": false, "applicable": false, "rating": nil}
 }
 
-// GetMyGuideRatingForAkcija GET /api/akcije/:id/guide-rating/mine
+// GetMyGuideRatingForAkcija GET /api/akcije/:id/guide-rating/mine?vodicId= (bez vodicId: glavni vodič)
 func GetMyGuideRatingForAkcija(c *gin.Context) {
 	db := DB(c)
 	k, ok := currentKorisnik(c, db)
@@ -119,7 +136,9 @@ func GetMyGuideRatingForAkcija(c *gin.Context) {
 	if !ok {
 		return
 	}
-	if !akcija.IsCompleted || akcija.VodicID == 0 || k.ID == akcija.VodicID {
+	requested, _ := strconv.ParseUint(c.Query("vodicId"), 10, 32)
+	guideID := ratedGuideID(db, akcija, uint(requested))
+	if !akcija.IsCompleted || guideID == 0 || helpers.IsAkcijaGuide(db, akcija, k.ID) {
 		c.JSON(http.StatusOK, guideRatingNotApplicable())
 		return
 	}
@@ -137,15 +156,15 @@ func GetMyGuideRatingForAkcija(c *gin.Context) {
 		c.JSON(http.StatusOK, guideRatingNotApplicable())
 		return
 	}
-	if !helpers.VodicCanReceiveGuideRatings(db, akcija.VodicID) {
+	if !helpers.VodicCanReceiveGuideRatings(db, guideID) {
 		c.JSON(http.StatusOK, guideRatingNotApplicable())
 		return
 	}
 	var row models.GuideActionRating
-	err = db.Where("akcija_id = ? AND rater_korisnik_id = ?", akcija.ID, k.ID).First(&row).Error
+	err = db.Where("akcija_id = ? AND rater_korisnik_id = ? AND guide_korisnik_id = ?", akcija.ID, k.ID, guideID).First(&row).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusOK, gin.H{"submitted": false, "applicable": true, "rating": nil})
+			c.JSON(http.StatusOK, gin.H{"submitted": false, "applicable": true, "vodicId": guideID, "rating": nil})
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju ocene"})
@@ -154,6 +173,7 @@ func GetMyGuideRatingForAkcija(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"submitted":  true,
 		"applicable": true,
+		"vodicId":    guideID,
 		"rating": gin.H{
 			"id":       row.ID,
 			"ocena":    row.Ocena,
@@ -162,7 +182,7 @@ func GetMyGuideRatingForAkcija(c *gin.Context) {
 	})
 }
 
-// SubmitGuideRatingForAkcija POST /api/akcije/:id/guide-rating
+// SubmitGuideRatingForAkcija POST /api/akcije/:id/guide-rating — svaki vodič akcije (vodicId) se ocenjuje posebno.
 func SubmitGuideRatingForAkcija(c *gin.Context) {
 	db := DB(c)
 	k, ok := currentKorisnik(c, db)
@@ -173,26 +193,31 @@ func SubmitGuideRatingForAkcija(c *gin.Context) {
 	if !ok {
 		return
 	}
-	if !participantCanRateGuide(c, db, akcija, k) {
+	var body guideRatingBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravan JSON"})
+		return
+	}
+	guideID := ratedGuideID(db, akcija, body.VodicID)
+	if body.VodicID != 0 && guideID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Korisnik nije vodič ove akcije"})
+		return
+	}
+	if !participantCanRateGuide(c, db, akcija, k, guideID) {
 		return
 	}
 
-	if !helpers.VodicCanReceiveGuideRatings(db, akcija.VodicID) {
+	if !helpers.VodicCanReceiveGuideRatings(db, guideID) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Vodič nema odobren profi vodički profil za ocenjivanje"})
 		return
 	}
 
-	gp, hasProfile := approvedGuideProfileForVodic(db, akcija.VodicID)
+	gp, hasProfile := approvedGuideProfileForVodic(db, guideID)
 	if !hasProfile {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Vodič nema odobren profi vodički profil za ocenjivanje"})
 		return
 	}
 
-	var body guideRatingBody
-	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravan JSON"})
-		return
-	}
 	komentar := strings.TrimSpace(body.Komentar)
 
 	if body.Ocena == nil && komentar == "" {
@@ -211,7 +236,7 @@ func SubmitGuideRatingForAkcija(c *gin.Context) {
 	}
 
 	var existing models.GuideActionRating
-	if err := db.Where("akcija_id = ? AND rater_korisnik_id = ?", akcija.ID, k.ID).First(&existing).Error; err == nil {
+	if err := db.Where("akcija_id = ? AND rater_korisnik_id = ? AND guide_korisnik_id = ?", akcija.ID, k.ID, guideID).First(&existing).Error; err == nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "Već ste ocenili vodiča za ovu akciju"})
 		return
 	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -223,7 +248,7 @@ func SubmitGuideRatingForAkcija(c *gin.Context) {
 		AkcijaID:        akcija.ID,
 		RaterKorisnikID: k.ID,
 		GuideProfileID:  gp.ID,
-		GuideKorisnikID: akcija.VodicID,
+		GuideKorisnikID: guideID,
 		Ocena:           body.Ocena,
 		Komentar:        komentar,
 	}
@@ -244,6 +269,7 @@ func SubmitGuideRatingForAkcija(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Ocena je sačuvana",
+		"vodicId": guideID,
 		"rating": gin.H{
 			"id":       row.ID,
 			"ocena":    row.Ocena,
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"math"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/hiketime"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/sun"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// safeStartReserve — rezerva pre zalaska za pauze, fotografisanje i nepredviđeno.
+	safeStartReserve = 30 * time.Minute
+	// paceActivitiesPerUser — tempo učesnika se računa iz njegovih poslednjih N završenih aktivnosti.
+	paceActivitiesPerUser = 10
+)
+
+// hikingEstimate je procena vremena hoda na detalju akcije/ferate.
+type hikingEstimate struct {
+	Izvor               string     `json:"izvor"`       // ruta | akcija | ferrata
+	StandardMin         int        `json:"standardMin"` // DIN 33466, bez pauza
+	TempoFaktor         float64    `json:"tempoFaktor"` // >1 = grupa sporija od standarda
+	TempoUcesnika       int        `json:"tempoUcesnika"`
+	ProcenaMin          int        `json:"procenaMin"`
+	PredlogTrajanjaSati float64    `json:"predlogTrajanjaSati"`
+	Zalazak             *time.Time `json:"zalazak,omitempty"`
+	NajkasnijiPolazak   *time.Time `json:"najkasnijiPolazak,omitempty"`
+	KasniPolazak        bool       `json:"kasniPolazak,omitempty"` // startAt je posle najkasnijeg bezbednog polaska
+}
+
+// ferrataHikingMinutes: prilaz (unet u minutima) + deonica na sajli + silazak pešačkom stazom.
+func ferrataHikingMinutes(f *models.Ferrata) int {
+	if f.DuzinaM <= 0 && f.VisinskaRazlikaM <= 0 {
+		return 0
+	}
+	return f.PrilazMin + hiketime.StandardMinutes(
+		hiketime.Leg{DistanceM: float64(f.DuzinaM), AscentM: float64(f.VisinskaRazlikaM), Rates: hiketime.ViaFerrata},
+		hiketime.Leg{DescentM: float64(f.VisinskaRazlikaM), Rates: hiketime.Hiking},
+	)
+}
+
+// akcijaStandardMinutes bira najbolji izvor podataka: uvezena trasa, pa uneti km/uspon akcije
+// (pretpostavka povratka na polaznu tačku), pa katalog ferate.
+func akcijaStandardMinutes(db *gorm.DB, akcija *models.Akcija) (int, string) {
+	var rute []models.AkcijaRuta
+	if db.Where("akcija_id = ?", akcija.ID).Limit(1).Find(&rute).Error == nil && len(rute) == 1 && rute[0].DuzinaM > 0 {
+		r := rute[0]
+		return hiketime.StandardMinutes(hiketime.Leg{DistanceM: r.DuzinaM, AscentM: r.UsponM, DescentM: r.SpustM, Rates: hiketime.Hiking}), "ruta"
+	}
+	if akcija.UkupnoKmAkcija > 0 {
+		up := float64(akcija.UkupnoMetaraUsponaAkcija)
+		return hiketime.StandardMinutes(hiketime.Leg{DistanceM: akcija.UkupnoKmAkcija * 1000, AscentM: up, DescentM: up, Rates: hiketime.Hiking}), "akcija"
+	}
+	if akcija.FerrataID != nil {
+		var f models.Ferrata
+		if db.Limit(1).Find(&f, *akcija.FerrataID).Error == nil && f.ID != 0 {
+			if m := ferrataHikingMinutes(&f); m > 0 {
+				return m, "ferrata"
+			}
+		}
+	}
+	return 0, ""
+}
+
+// akcijaGroupPace računa tempo grupe iz završenih (neoznačenih) aktivnosti prijavljenih učesnika.
+func akcijaGroupPace(db *gorm.DB, akcijaID uint) (float64, int) {
+	var userIDs []uint
+	if err := db.Model(&models.Prijava{}).Where("akcija_id = ? AND status IN ?", akcijaID, helpers.PrijavaActiveStatuses).
+		Distinct().Pluck("korisnik_id", &userIDs).Error; err != nil || len(userIDs) == 0 {
+		return 1, 0
+	}
+	var rows []models.TrackedActivity
+	if err := db.Select("user_id", "duration_sec", "distance_m", "elevation_gain_m").
+		Where("user_id IN ? AND status = ? AND flagged = ? AND distance_m >= ? AND duration_sec > 0",
+			userIDs, models.TrackedActivityStatusCompleted, false, hiketime.MinSampleDistanceM).
+		Order("user_id, started_at DESC").Find(&rows).Error; err != nil {
+		return 1, 0
+	}
+	ratios := make(map[uint][]float64, len(userIDs))
+	for _, a := range rows {
+		if len(ratios[a.UserID]) >= paceActivitiesPerUser {
+			continue
+		}
+		if r, ok := hiketime.PaceRatio(a.DurationSec, a.DistanceM, a.ElevationGainM); ok {
+			ratios[a.UserID] = append(ratios[a.UserID], r)
+		}
+	}
+	pace, n, _ := hiketime.GroupPace(ratios)
+	return pace, n
+}
+
+// newHikingEstimate primenjuje tempo i, kada su poznati tačka i dan, računa najkasniji bezbedan polazak.
+func newHikingEstimate(standardMin int, izvor string, pace float64, paceUsers int, day time.Time, lat, lng *float64) *hikingEstimate {
+	est := &hikingEstimate{
+		Izvor:         izvor,
+		StandardMin:   standardMin,
+		TempoFaktor:   math.Round(pace*100) / 100,
+		TempoUcesnika: paceUsers,
+		ProcenaMin:    int(math.Round(float64(standardMin) * pace)),
+	}
+	est.PredlogTrajanjaSati = math.Ceil(float64(est.ProcenaMin)/30) / 2
+	if lat != nil && lng != nil && !day.IsZero() {
+		if set := sun.Times(day, *lat, *lng).Sunset; set != nil {
+			latest := set.Add(-time.Duration(est.ProcenaMin)*time.Minute - safeStartReserve)
+			est.Zalazak, est.NajkasnijiPolazak = set, &latest
+		}
+	}
+	return est
+}
+
+// estimateAkcijaHikingTime vraća nil kada akcija nema ni trasu ni dužinu/uspon.
+func estimateAkcijaHikingTime(db *gorm.DB, akcija *models.Akcija) *hikingEstimate {
+	standardMin, izvor := akcijaStandardMinutes(db, akcija)
+	if standardMin <= 0 {
+		return nil
+	}
+	pace, paceUsers := akcijaGroupPace(db, akcija.ID)
+	lat, lng := akcija.PlaninaLat, akcija.PlaninaLng
+	day := helpers.AkcijaDay(akcija)
+	// Višednevna tura: procena je za celu trasu, pa zalazak prvog dana nije merodavan.
+	if akcija.BrojDana > 1 {
+		day = time.Time{}
+	}
+	est := newHikingEstimate(standardMin, izvor, pace, paceUsers, day, lat, lng)
+	if est.NajkasnijiPolazak != nil && akcija.StartAt != nil && akcija.StartAt.After(*est.NajkasnijiPolazak) {
+		est.KasniPolazak = true
+	}
+	return est
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+)
+
+func TestEstimateAkcijaHikingTime_RoutePaceAndSunset(t *testing.T) {
+	db, _ := testActivityPointsDB(t)
+	if err := db.AutoMigrate(&models.Akcija{}, &models.Prijava{}, &models.AkcijaRuta{}, &models.Ferrata{}); err != nil {
+		t.Fatal(err)
+	}
+	lat, lng := 43.3, 22.1
+	start := time.Date(2026, 12, 12, 11, 0, 0, 0, belgradeLoc())
+	akcija := models.Akcija{Naziv: "Trem zimi", Datum: time.Date(2026, 12, 12, 0, 0, 0, 0, time.UTC), StartAt: &start,
+		PlaninaLat: &lat, PlaninaLng: &lng, UkupnoKmAkcija: 20, UkupnoMetaraUsponaAkcija: 1500, BrojDana: 1}
+	db.Create(&akcija)
+
+	// Trasa ima prednost nad unetim km/usponom: 12 km (3 h), 900 m gore i dole (4,8 h) → 4,8 + 1,5 = 6,3 h.
+	db.Create(&models.AkcijaRuta{AkcijaID: akcija.ID, Polyline: "x", Izvor: "gpx", DuzinaM: 12000, UsponM: 900, SpustM: 900})
+	est := estimateAkcijaHikingTime(db, &akcija)
+	if est == nil || est.Izvor != "ruta" || est.StandardMin != 378 || est.TempoFaktor != 1 {
+		t.Fatalf("unexpected standard estimate: %+v", est)
+	}
+
+	// Brži učesnik (0,8) i sporiji (1,2 standardnog vremena na turama od 12 km / 450 m = 4,2 h): grupa ide tempom sporijeg.
+	for i, pace := range []float64{0.8, 1.2} {
+		u := models.Korisnik{Username: []string{"brzi", "spori"}[i], Role: "clan"}
+		db.Create(&u)
+		db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: u.ID, Status: "prijavljen"})
+		for j := 0; j < 2; j++ {
+			db.Create(&models.TrackedActivity{UserID: u.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: time.Now().AddDate(0, 0, -j-1),
+				DurationSec: int(pace * 4.2 * 3600), DistanceM: 12000, ElevationGainM: 450})
+		}
+		// Označena (sumnjiva) aktivnost se ne računa.
+		db.Create(&models.TrackedActivity{UserID: u.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: time.Now(),
+			DurationSec: 600, DistanceM: 12000, ElevationGainM: 450, Flagged: true})
+	}
+	est = estimateAkcijaHikingTime(db, &akcija)
+	if est.TempoFaktor != 1.2 || est.TempoUcesnika != 2 || est.ProcenaMin != 454 || est.PredlogTrajanjaSati != 8 {
+		t.Fatalf("group pace not applied: %+v", est)
+	}
+	// Zalazak u Nišu sredinom decembra je oko 16 h; 7,6 h hoda i 30 min rezerve → polazak najkasnije oko 8 h.
+	if est.Zalazak == nil || est.NajkasnijiPolazak == nil {
+		t.Fatalf("sunset-based latest start missing: %+v", est)
+	}
+	latest := est.NajkasnijiPolazak.In(belgradeLoc())
+	if latest.Hour() < 7 || latest.Hour() > 8 || !est.KasniPolazak {
+		t.Fatalf("latest safe start %v (late=%v), want ~08:00 and 11:00 flagged as late", latest, est.KasniPolazak)
+	}
+
+	akcija.BrojDana = 2
+	if est := estimateAkcijaHikingTime(db, &akcija); est.NajkasnijiPolazak != nil {
+		t.Fatal("multi-day action must not get a same-day latest start")
+	}
+}
+
+func TestEstimateAkcijaHikingTime_FerrataCatalogFallback(t *testing.T) {
+	db, _ := testActivityPointsDB(t)
+	if err := db.AutoMigrate(&models.Akcija{}, &models.Prijava{}, &models.AkcijaRuta{}, &models.Ferrata{}); err != nil {
+		t.Fatal(err)
+	}
+	f := models.Ferrata{Naziv: "Ferata Gabrovnica", Slug: "gabrovnica", DuzinaM: 400, VisinskaRazlikaM: 200, PrilazMin: 30}
+	db.Create(&f)
+	akcija := models.Akcija{Naziv: "Ferata", Datum: time.Now().UTC(), FerrataID: &f.ID, TipAkcije: "via_ferrata"}
+	db.Create(&akcija)
+
+	// Prilaz 30 min + sajla (0,2 h / 1 h → 1,1 h = 66 min) + silazak 200 m (0,4 h = 24 min).
+	est := estimateAkcijaHikingTime(db, &akcija)
+	if est == nil || est.Izvor != "ferrata" || est.StandardMin != 120 || est.Zalazak != nil {
+		t.Fatalf("unexpected ferrata estimate: %+v", est)
+	}
+
+	empty := models.Akcija{Naziv: "Bez podataka", Datum: time.Now().UTC()}
+	db.Create(&empty)
+	if est := estimateAkcijaHikingTime(db, &empty); est != nil {
+		t.Fatalf("action without route data must not get an estimate: %+v", est)
+	}
+}
@@ -63,7 +63,9 @@ func ListHotelsNearby(c *gin.Context) {
 
 	db := DB(c)
 	var rows []models.Hotel
-	if err := db.Where("status = ?", "active").Find(&rows).Error; err != nil {
+	// bbox prefilter ide preko (lat, lng) indeksa; Haversine samo za tačan radijus.
+	q := whereInBBox(db.Where("status = ?", "active"), "lat", "lng", geo.BBoxAround(lat, lng, radiusKm))
+	if err := q.Find(&rows).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju hotela"})
 		return
 	}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// canAdminKorisnikSertifikate: superadmin za svakog korisnika, admin samo za članove izabranog kluba (kao GetKorisnikInfo).
+func canAdminKorisnikSertifikate(c *gin.Context, db *gorm.DB, korisnik *models.Korisnik) bool {
+	roleVal, _ := c.Get("role")
+	role, _ := roleVal.(string)
+	if role == "superadmin" {
+		return true
+	}
+	if role != "admin" {
+		return false
+	}
+	clubID, ok := helpers.GetEffectiveClubID(c, db)
+	return ok && clubID != 0 && korisnik.KlubID != nil && *korisnik.KlubID == clubID
+}
+
+// loadKorisnikForSertifikati učitava člana iz :id; false kada je odgovor već poslat.
+func loadKorisnikForSertifikati(c *gin.Context, db *gorm.DB) (*models.Korisnik, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID korisnika"})
+		return nil, false
+	}
+	var korisnik models.Korisnik
+	if err := db.First(&korisnik, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Korisnik nije pronađen"})
+		return nil, false
+	}
+	return &korisnik, true
+}
+
+// GetKorisnikSertifikati GET /korisnici/:id/sertifikati — sertifikati člana (sam član ili admin kluba).
+func GetKorisnikSertifikati(c *gin.Context) {
+	db := DB(c)
+	viewer, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	korisnik, ok := loadKorisnikForSertifikati(c, db)
+	if !ok {
+		return
+	}
+	if viewer.ID != korisnik.ID && !canAdminKorisnikSertifikate(c, db, korisnik) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pristup ovim podacima"})
+		return
+	}
+	var rows []models.KorisnikSertifikat
+	if err := db.Where("korisnik_id = ?", korisnik.ID).Order("naziv, id").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju sertifikata"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sertifikati": rows})
+}
+
+// DodajKorisnikSertifikat POST /korisnici/:id/sertifikati — body {"naziv": "Alpinistički tečaj", "vaziDo": "2027-12-31"};
+// vaziDo je opciono (trajno važi). Evidentira admin kluba.
+func DodajKorisnikSertifikat(c *gin.Context) {
+	db := DB(c)
+	actor, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	korisnik, ok := loadKorisnikForSertifikati(c, db)
+	if !ok {
+		return
+	}
+	if !canAdminKorisnikSertifikate(c, db, korisnik) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo admin kluba može da evidentira sertifikate člana"})
+		return
+	}
+	var body struct {
+		Naziv  string `json:"naziv"`
+		VaziDo string `json:"vaziDo"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Obavezno: naziv"})
+		return
+	}
+	naziv := strings.TrimSpace(body.Naziv)
+	if naziv == "" || len([]rune(naziv)) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Naziv sertifikata je obavezan (do 100 znakova)"})
+		return
+	}
+	sert := models.KorisnikSertifikat{KorisnikID: korisnik.ID, Naziv: naziv, EvidentiraoID: actor.ID}
+	if raw := strings.TrimSpace(body.VaziDo); raw != "" {
+		d, err := time.ParseInLocation("2006-01-02", raw, belgradeLoc())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći format datuma (YYYY-MM-DD)"})
+			return
+		}
+		kraj := d.Add(24*time.Hour - time.Second)
+		sert.VaziDo = &kraj
+	}
+	if err := db.Create(&sert).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju sertifikata"})
+		return
+	}
+	c.JSON(http.StatusCreated, sert)
+}
+
+// ObrisiKorisnikSertifikat DELETE /korisnici/:id/sertifikati/:sertifikatId
+func ObrisiKorisnikSertifikat(c *gin.Context) {
+	db := DB(c)
+	korisnik, ok := loadKorisnikForSertifikati(c, db)
+	if !ok {
+		return
+	}
+	if !canAdminKorisnikSertifikate(c, db, korisnik) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo admin kluba može da briše sertifikate člana"})
+		return
+	}
+	sertID, err := strconv.ParseUint(c.Param("sertifikatId"), 10, 32)
+	if err != nil || sertID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID sertifikata"})
+		return
+	}
+	res := db.Where("id = ? AND korisnik_id = ?", sertID, korisnik.ID).Delete(&models.KorisnikSertifikat{})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri brisanju sertifikata"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sertifikat nije pronađen"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sertifikat je obrisan"})
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/geo"
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	mapDefaultZoom = 10
+	mapMaxZoom     = 20
+	// Ispod ovog zoom-a tačke se uvek grupišu u klastere (ceo region/država u prozoru).
+	mapClusterBelowZoom = 12
+	// Više tačaka od ovoga se grupiše i na većem zoom-u; po tipu se nikad ne čita više od mapMaxRowsPerTip.
+	mapMaxPoints     = 500
+	mapMaxRowsPerTip = 5000
+)
+
+type mapPoint struct {
+	Tip     string     `json:"tip"`
+	ID      uint       `json:"id"`
+	Naziv   string     `json:"naziv"`
+	Slug    string     `json:"slug,omitempty"` // slug kataloga ili username vodiča
+	Lat     float64    `json:"lat"`
+	Lng     float64    `json:"lng"`
+	VisinaM int        `json:"visinaM,omitempty"`
+	Datum   *time.Time `json:"datum,omitempty"`
+}
+
+// mapTipovi — redosled prikaza slojeva na mapi.
+func mapTipovi() []string {
+	return []string{search.TipVrh, search.TipFerata, search.TipHotel, search.TipVodic, search.TipAkcija}
+}
+
+func parseMapTipovi(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return mapTipovi()
+	}
+	valid := make(map[string]struct{})
+	for _, t := range mapTipovi() {
+		valid[t] = struct{}{}
+	}
+	out := make([]string, 0)
+	for _, t := range strings.Split(raw, ",") {
+		t = strings.TrimSpace(strings.ToLower(t))
+		if _, ok := valid[t]; ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// whereInBBox ograničava upit na bbox preko btree (lat, lng) indeksa.
+func whereInBBox(q *gorm.DB, latCol, lngCol string, b geo.BBox) *gorm.DB {
+	cond, args := b.SQLWhere(latCol, lngCol)
+	return q.Where(cond, args...)
+}
+
+// publicUpcomingAkcijeQuery — predstojeće javne akcije; klupske, završene i otkazane ne idu na javnu mapu.
+func publicUpcomingAkcijeQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Akcija{}).
+		Where("javna = ? AND is_completed = ? AND is_cancelled = ? AND datum >= ?", true, false, false, todayDateUTC()).
+		Where("(u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND "+helpers.SQLAkcijaObjavljena, true)
+}
+
+// loadMapPoints čita tačke jednog sloja unutar bbox-a (samo javno vidljive stavke).
+func loadMapPoints(db *gorm.DB, tip string, b geo.BBox, viewer searchViewer) ([]mapPoint, error) {
+	out := make([]mapPoint, 0)
+	switch tip {
+	case search.TipVrh:
+		var rows []models.Peak
+		q := whereInBBox(db.Select("id, naziv_vrha, slug, lat, lng, visina_m").Where("status = ?", "active"), "lat", "lng", b)
+		if err := q.Order("visina_m DESC, id ASC").Limit(mapMaxRowsPerTip).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			if r.Lat != nil && r.Lng != nil {
+				out = append(out, mapPoint{Tip: tip, ID: r.ID, Naziv: r.NazivVrha, Slug: r.Slug, Lat: *r.Lat, Lng: *r.Lng, VisinaM: r.VisinaM})
+			}
+		}
+	case search.TipFerata:
+		var rows []models.Ferrata
+		q := whereInBBox(db.Select("id, naziv, slug, lat, lng").Where("status = ?", "active"), "lat", "lng", b)
+		if err := q.Order("id ASC").Limit(mapMaxRowsPerTip).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			if r.Lat != nil && r.Lng != nil {
+				out = append(out, mapPoint{Tip: tip, ID: r.ID, Naziv: r.Naziv, Slug: r.Slug, Lat: *r.Lat, Lng: *r.Lng})
+			}
+		}
+	case search.TipHotel:
+		var rows []models.Hotel
+		q := whereInBBox(db.Select("id, naziv, slug, lat, lng").Where("status = ?", "active"), "lat", "lng", b)
+		if err := q.Order("id ASC").Limit(mapMaxRowsPerTip).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			out = append(out, mapPoint{Tip: tip, ID: r.ID, Naziv: r.Naziv, Slug: r.Slug, Lat: r.Lat, Lng: r.Lng})
+		}
+	case search.TipVodic:
+		type guideRow struct {
+			ID       uint
+			BaseLat  float64
+			BaseLng  float64
+			Username string
+			FullName string
+		}
+		var rows []guideRow
+		q := db.Table("guide_profiles").
+			Joins("JOIN korisnici ON korisnici.id = guide_profiles.korisnik_id").
+			Where("guide_profiles.status = ? AND korisnici.role <> ?", models.GuideStatusApproved, "deleted")
+		q = whereInBBox(excludeBlockedUsers(q, "korisnici.id", viewer), "guide_profiles.base_lat", "guide_profiles.base_lng", b)
+		if err := q.Select("guide_profiles.id AS id, guide_profiles.base_lat AS base_lat, guide_profiles.base_lng AS base_lng, korisnici.username AS username, COALESCE(korisnici.full_name, '') AS full_name").
+			Order("guide_profiles.id ASC").Limit(mapMaxRowsPerTip).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			naziv := r.FullName
+			if strings.TrimSpace(naziv) == "" {
+				naziv = r.Username
+			}
+			out = append(out, mapPoint{Tip: tip, ID: r.ID, Naziv: naziv, Slug: r.Username, Lat: r.BaseLat, Lng: r.BaseLng})
+		}
+	case search.TipAkcija:
+		var rows []models.Akcija
+		q := whereInBBox(publicUpcomingAkcijeQuery(db).Select("id, naziv, planina_lat, planina_lng, datum"), "planina_lat", "planina_lng", b)
+		if err := q.Order("datum ASC, id ASC").Limit(mapMaxRowsPerTip).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			r := &rows[i]
+			if r.PlaninaLat != nil && r.PlaninaLng != nil {
+				datum := r.Datum
+				out = append(out, mapPoint{Tip: tip, ID: r.ID, Naziv: r.Naziv, Lat: *r.PlaninaLat, Lng: *r.PlaninaLng, Datum: &datum})
+			}
+		}
+	}
+	return out, nil
+}
+
+// GetMap GET /api/map?bbox=minLng,minLat,maxLng,maxLat&zoom=&tip=vrh,ferata,hotel,vodic,akcija
+// Vraća tačke u prozoru mape; na malom zoom-u (ili kad ih je previše) grupiše ih u klastere.
+func GetMap(c *gin.Context) {
+	b, err := geo.ParseBBox(c.Query("bbox"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parametar bbox mora biti minLng,minLat,maxLng,maxLat."})
+		return
+	}
+	zoom := mapDefaultZoom
+	if z, err := strconv.Atoi(c.Query("zoom")); err == nil {
+		zoom = z
+	}
+	if zoom < 0 {
+		zoom = 0
+	}
+	if zoom > mapMaxZoom {
+		zoom = mapMaxZoom
+	}
+	tipovi := parseMapTipovi(c.Query("tip"))
+	if len(tipovi) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nepoznat tip sloja mape"})
+		return
+	}
+
+	db := DB(c)
+	viewer := searchViewerFromContext(c)
+	points := make([]mapPoint, 0)
+	for _, tip := range tipovi {
+		rows, err := loadMapPoints(db, tip, b, viewer)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju mape"})
+			return
+		}
+		points = append(points, rows...)
+	}
+
+	poTipu := make(map[string]int)
+	for _, p := range points {
+		poTipu[p.Tip]++
+	}
+	if zoom >= mapClusterBelowZoom && len(points) <= mapMaxPoints {
+		c.JSON(http.StatusOK, gin.H{"tacke": points, "klasteri": []geo.Cluster{}, "klasterovano": false, "zoom": zoom, "poTipu": poTipu})
+		return
+	}
+
+	geoPoints := make([]geo.Point, len(points))
+	for i, p := range points {
+		geoPoints[i] = geo.Point{Tip: p.Tip, ID: p.ID, Lat: p.Lat, Lng: p.Lng}
+	}
+	singles, clusters := geo.ClusterPoints(geoPoints, zoom)
+	type pointKey struct {
+		tip string
+		id  uint
+	}
+	byKey := make(map[pointKey]mapPoint, len(points))
+	for _, p := range points {
+		byKey[pointKey{p.Tip, p.ID}] = p
+	}
+	tacke := make([]mapPoint, 0, len(singles))
+	for _, s := range singles {
+		tacke = append(tacke, byKey[pointKey{s.Tip, s.ID}])
+	}
+	c.JSON(http.StatusOK, gin.H{"tacke": tacke, "klasteri": clusters, "klasterovano": true, "zoom": zoom, "poTipu": poTipu})
+}
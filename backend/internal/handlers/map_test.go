@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/geo"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type mapResponse struct {
+	Tacke        []mapPoint    `json:"tacke"`
+	Klasteri     []geo.Cluster `json:"klasteri"`
+	Klasterovano bool          `json:"klasterovano"`
+}
+
+func callMap(t *testing.T, db *gorm.DB, query string) mapResponse {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/map?"+query, nil)
+	c.Set("db", db)
+	GetMap(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d body=%s", w.Code, w.Body.String())
+	}
+	var resp mapResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func mapHas(points []mapPoint, tip string, id uint) bool {
+	for _, p := range points {
+		if p.Tip == tip && p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGetMap_BBoxFilterVisibilityAndClustering(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "map")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Korisnik{}, &models.Block{}, &models.Akcija{}, &models.Peak{}, &models.Ferrata{}, &models.Hotel{}, &models.GuideProfile{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	f := func(v float64) *float64 { return &v }
+
+	pancicev := models.Peak{NazivVrha: "Pančićev vrh", Slug: "pancicev-vrh", Status: "active", Lat: f(43.2736), Lng: f(20.8126)}
+	suvoRudiste := models.Peak{NazivVrha: "Suvo rudište", Slug: "suvo-rudiste", Status: "active", Lat: f(43.2770), Lng: f(20.8170)}
+	triglav := models.Peak{NazivVrha: "Triglav", Slug: "triglav", Status: "active", Lat: f(46.3783), Lng: f(13.8367)}
+	db.Create(&pancicev)
+	db.Create(&suvoRudiste)
+	db.Create(&triglav)
+	hotel := models.Hotel{Naziv: "Hotel Grand", Slug: "hotel-grand", Status: "active", Lat: 43.2850, Lng: 20.8000}
+	db.Create(&hotel)
+	vodicUser := models.Korisnik{Username: "vodic_kop", Role: "clan"}
+	db.Create(&vodicUser)
+	vodic := models.GuideProfile{KorisnikID: vodicUser.ID, Status: models.GuideStatusApproved, Naslov: "Vodič", Opis: "Kopaonik", BaseLat: f(43.30), BaseLng: f(20.85)}
+	db.Create(&vodic)
+
+	datum := time.Now().UTC().AddDate(0, 0, 7)
+	javna := models.Akcija{Naziv: "Kopaonik zimi", Javna: true, Datum: datum, PlaninaLat: f(43.28), PlaninaLng: f(20.81), UIstorijiKluba: true}
+	klupska := models.Akcija{Naziv: "Klupski izlet", Javna: false, Datum: datum, PlaninaLat: f(43.28), PlaninaLng: f(20.82), UIstorijiKluba: true}
+	prosla := models.Akcija{Naziv: "Prošla", Javna: true, Datum: datum.AddDate(0, 0, -30), PlaninaLat: f(43.28), PlaninaLng: f(20.83), UIstorijiKluba: true}
+	for _, a := range []*models.Akcija{&javna, &klupska, &prosla} {
+		db.Create(a)
+	}
+
+	resp := callMap(t, db, "bbox=20.7,43.2,20.9,43.4&zoom=15")
+	if resp.Klasterovano || len(resp.Klasteri) != 0 {
+		t.Fatalf("high zoom should return raw points, got %+v", resp)
+	}
+	for _, want := range []struct {
+		tip string
+		id  uint
+	}{{"vrh", pancicev.ID}, {"vrh", suvoRudiste.ID}, {"hotel", hotel.ID}, {"vodic", vodic.ID}, {"akcija", javna.ID}} {
+		if !mapHas(resp.Tacke, want.tip, want.id) {
+			t.Fatalf("expected %s %d in bbox, got %+v", want.tip, want.id, resp.Tacke)
+		}
+	}
+	if mapHas(resp.Tacke, "vrh", triglav.ID) {
+		t.Fatal("Triglav is outside the bbox")
+	}
+	if mapHas(resp.Tacke, "akcija", klupska.ID) || mapHas(resp.Tacke, "akcija", prosla.ID) {
+		t.Fatal("club-only and past actions must not be on the public map")
+	}
+
+	resp = callMap(t, db, "bbox=10,40,25,48&zoom=6&tip=vrh")
+	if !resp.Klasterovano || len(resp.Klasteri) != 1 || resp.Klasteri[0].Count != 2 {
+		t.Fatalf("expected the two Kopaonik peaks clustered at zoom 6, got %+v", resp)
+	}
+	if len(resp.Tacke) != 1 || resp.Tacke[0].ID != triglav.ID {
+		t.Fatalf("expected Triglav as a lone point, got %+v", resp.Tacke)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/map?bbox=20,44,19,43", nil)
+	c.Set("db", db)
+	GetMap(c)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("inverted latitude bbox should be rejected, got %d", w.Code)
+	}
+}
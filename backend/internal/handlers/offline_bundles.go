@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/geo"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/offline"
+	"beleg-app/backend/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// offlineTipovi — slojevi offline paketa (isti nazivi tipova kao na mapi i u pretrazi).
+func offlineTipovi() []string {
+	return []string{search.TipVrh, search.TipFerata, search.TipHotel, search.TipAkcija}
+}
+
+// offlineRegionFromQuery čita ?country=RS ili ?bbox=minLng,minLat,maxLng,maxLat.
+func offlineRegionFromQuery(c *gin.Context) (offline.Region, bool) {
+	if code := strings.TrimSpace(c.Query("country")); code != "" {
+		region, err := offline.CountryRegion(code)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nepoznata država za offline paket."})
+			return offline.Region{}, false
+		}
+		return region, true
+	}
+	b, err := geo.ParseBBox(c.Query("bbox"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Potreban je parametar country ili bbox (minLng,minLat,maxLng,maxLat)."})
+		return offline.Region{}, false
+	}
+	region, err := offline.BBoxRegion(b)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Region offline paketa je prevelik (najviše 12° po širini i dužini)."})
+		return offline.Region{}, false
+	}
+	return region, true
+}
+
+func offlinePeakItem(p *models.Peak) gin.H {
+	m := peakToMap(p)
+	delete(m, "status")
+	delete(m, "createdAt")
+	delete(m, "updatedAt")
+	return m
+}
+
+// offlineFerrataItem — sve što treba na terenu: oprema, parking, napomena za mapu; bez galerije.
+func offlineFerrataItem(f *models.Ferrata) gin.H {
+	m := ferrataToMap(f, -1)
+	delete(m, "galerija")
+	delete(m, "status")
+	delete(m, "createdAt")
+	delete(m, "updatedAt")
+	m["parkingLat"] = ferrataCoordJSON(f.ParkingLat)
+	m["parkingLng"] = ferrataCoordJSON(f.ParkingLng)
+	m["parkingInfo"] = strings.TrimSpace(f.ParkingInfo)
+	m["povratakInfo"] = strings.TrimSpace(f.PovratakInfo)
+	m["najboljeVremeInfo"] = strings.TrimSpace(f.NajboljeVremeInfo)
+	m["prilazMin"] = f.PrilazMin
+	return m
+}
+
+func offlineAkcijaItem(a *models.Akcija) gin.H {
+	return gin.H{
+		"id":                a.ID,
+		"naziv":             a.Naziv,
+		"planina":           a.Planina,
+		"vrh":               a.Vrh,
+		"peakId":            a.PeakID,
+		"ferrataId":         a.FerrataID,
+		"tipAkcije":         a.TipAkcije,
+		"datum":             a.Datum,
+		"opis":              a.Opis,
+		"tezina":            a.Tezina,
+		"mestoPolaska":      a.MestoPolaska,
+		"kontaktTelefon":    a.KontaktTelefon,
+		"planinaLat":        a.PlaninaLat,
+		"planinaLng":        a.PlaninaLng,
+		"visinaVrhM":        a.VisinaVrhM,
+		"duzinaStazeKm":     a.UkupnoKmAkcija,
+		"kumulativniUsponM": a.UkupnoMetaraUsponaAkcija,
+		"klubId":            a.KlubID,
+	}
+}
+
+// loadOfflineItems čita aktivne stavke kataloga i predstojeće javne akcije unutar regiona (po ID-ju).
+func loadOfflineItems(db *gorm.DB, region offline.Region) (map[string][]offline.Item, error) {
+	items := make(map[string][]offline.Item, 4)
+	b := region.BBox
+
+	var peaks []models.Peak
+	if err := whereInBBox(db.Where("status = ?", "active"), "lat", "lng", b).Order("id ASC").Find(&peaks).Error; err != nil {
+		return nil, err
+	}
+	items[search.TipVrh] = make([]offline.Item, 0, len(peaks))
+	for i := range peaks {
+		items[search.TipVrh] = append(items[search.TipVrh], offline.Item{ID: peaks[i].ID, Data: offlinePeakItem(&peaks[i])})
+	}
+
+	var ferratas []models.Ferrata
+	if err := whereInBBox(db.Where("status = ?", "active"), "lat", "lng", b).Order("id ASC").Find(&ferratas).Error; err != nil {
+		return nil, err
+	}
+	items[search.TipFerata] = make([]offline.Item, 0, len(ferratas))
+	for i := range ferratas {
+		items[search.TipFerata] = append(items[search.TipFerata], offline.Item{ID: ferratas[i].ID, Data: offlineFerrataItem(&ferratas[i])})
+	}
+
+	var hotels []models.Hotel
+	if err := whereInBBox(db.Where("status = ?", "active"), "lat", "lng", b).Order("id ASC").Find(&hotels).Error; err != nil {
+		return nil, err
+	}
+	items[search.TipHotel] = make([]offline.Item, 0, len(hotels))
+	for i := range hotels {
+		items[search.TipHotel] = append(items[search.TipHotel], offline.Item{ID: hotels[i].ID, Data: hotelToPublicMap(&hotels[i])})
+	}
+
+	var akcije []models.Akcija
+	if err := whereInBBox(publicUpcomingAkcijeQuery(db), "planina_lat", "planina_lng", b).Order("id ASC").Find(&akcije).Error; err != nil {
+		return nil, err
+	}
+	items[search.TipAkcija] = make([]offline.Item, 0, len(akcije))
+	for i := range akcije {
+		items[search.TipAkcija] = append(items[search.TipAkcija], offline.Item{ID: akcije[i].ID, Data: offlineAkcijaItem(&akcije[i])})
+	}
+	return items, nil
+}
+
+// buildOfflineBundle čita region, računa verziju i pamti manifest za kasniju deltu.
+func buildOfflineBundle(c *gin.Context, db *gorm.DB, region offline.Region) (map[string][]offline.Item, offline.Manifest, string, bool) {
+	items, err := loadOfflineItems(db, region)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri pripremi offline paketa"})
+		return nil, nil, "", false
+	}
+	manifest, version := offline.BuildManifest(items)
+	if err := offline.SaveVersion(db, region, version, manifest); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri pripremi offline paketa"})
+		return nil, nil, "", false
+	}
+	return items, manifest, version, true
+}
+
+// writeOfflineJSON šalje paket gzip-ovan kada klijent to podržava (paketi za celu državu su veliki).
+func writeOfflineJSON(c *gin.Context, payload gin.H) {
+	c.Header("Vary", "Accept-Encoding")
+	if !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
+		c.JSON(http.StatusOK, payload)
+		return
+	}
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Header("Content-Encoding", "gzip")
+	c.Status(http.StatusOK)
+	zw := gzip.NewWriter(c.Writer)
+	defer zw.Close()
+	_ = json.NewEncoder(zw).Encode(payload)
+}
+
+// GetOfflineBundle GET /api/offline/bundle?country=RS | ?bbox=minLng,minLat,maxLng,maxLat
+// Ceo paket regiona; ETag je verzija (If-None-Match → 304 kada se katalog nije promenio).
+func GetOfflineBundle(c *gin.Context) {
+	region, ok := offlineRegionFromQuery(c)
+	if !ok {
+		return
+	}
+	db := DB(c)
+	items, _, version, ok := buildOfflineBundle(c, db, region)
+	if !ok {
+		return
+	}
+	etag := `"` + version + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if strings.Contains(c.GetHeader("If-None-Match"), etag) || c.Query("version") == version {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	stavke := make(gin.H, len(items))
+	broj := make(gin.H, len(items))
+	for _, tip := range offlineTipovi() {
+		list := make([]any, 0, len(items[tip]))
+		for _, it := range items[tip] {
+			list = append(list, it.Data)
+		}
+		stavke[tip] = list
+		broj[tip] = len(list)
+	}
+	writeOfflineJSON(c, gin.H{
+		"verzija":    version,
+		"region":     region,
+		"format":     "json",
+		"generisano": time.Now().UTC(),
+		"broj":       broj,
+		"stavke":     stavke,
+	})
+}
+
+// GetOfflineBundleDelta GET /api/offline/bundle/delta?since=<verzija>&country=RS|bbox=...
+// Izmenjene/nove stavke (ceo zapis) i obrisani ID-jevi od verzije koju klijent ima;
+// 410 ako ta verzija više nije poznata (klijent preuzima ceo paket).
+func GetOfflineBundleDelta(c *gin.Context) {
+	since := strings.TrimSpace(c.Query("since"))
+	if since == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parametar since (verzija paketa) je obavezan."})
+		return
+	}
+	region, ok := offlineRegionFromQuery(c)
+	if !ok {
+		return
+	}
+	db := DB(c)
+	items, manifest, version, ok := buildOfflineBundle(c, db, region)
+	if !ok {
+		return
+	}
+	c.Header("ETag", `"`+version+`"`)
+
+	izmenjeno := make(gin.H, len(items))
+	obrisano := make(gin.H, len(items))
+	if since == version {
+		for _, tip := range offlineTipovi() {
+			izmenjeno[tip] = []any{}
+			obrisano[tip] = []uint{}
+		}
+		c.JSON(http.StatusOK, gin.H{"verzija": version, "od": since, "izmenjeno": izmenjeno, "obrisano": obrisano})
+		return
+	}
+	old, err := offline.LoadManifest(db, region, since)
+	if errors.Is(err, offline.ErrUnknownVersion) {
+		c.JSON(http.StatusGone, gin.H{"error": "Verzija paketa više nije dostupna, preuzmite ceo paket.", "verzija": version})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri pripremi offline paketa"})
+		return
+	}
+	changed, deleted := offline.Diff(old, manifest)
+	for _, tip := range offlineTipovi() {
+		want := make(map[uint]struct{}, len(changed[tip]))
+		for _, id := range changed[tip] {
+			want[id] = struct{}{}
+		}
+		list := make([]any, 0, len(want))
+		for _, it := range items[tip] {
+			if _, ok := want[it.ID]; ok {
+				list = append(list, it.Data)
+			}
+		}
+		izmenjeno[tip] = list
+		if deleted[tip] == nil {
+			deleted[tip] = []uint{}
+		}
+		obrisano[tip] = deleted[tip]
+	}
+	writeOfflineJSON(c, gin.H{"verzija": version, "od": since, "izmenjeno": izmenjeno, "obrisano": obrisano})
+}
+
+// ListOfflineCountries GET /api/offline/countries — države za koje postoji gotov region paketa.
+func ListOfflineCountries(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"drzave": offline.Countries})
+}
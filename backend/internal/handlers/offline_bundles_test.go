@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func callOffline(t *testing.T, db *gorm.DB, h gin.HandlerFunc, query, ifNoneMatch string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/offline/bundle?"+query, nil)
+	if ifNoneMatch != "" {
+		c.Request.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	c.Set("db", db)
+	h(c)
+	return w
+}
+
+func TestOfflineBundle_VersionETagAndDelta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "offline_bundles")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Akcija{}, &models.Peak{}, &models.Ferrata{}, &models.Hotel{}, &models.OfflineBundleVersion{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	f := func(v float64) *float64 { return &v }
+	peak := models.Peak{NazivVrha: "Pančićev vrh", Slug: "pancicev-vrh", Status: "active", Lat: f(43.2736), Lng: f(20.8126)}
+	db.Create(&peak)
+	ferrata := models.Ferrata{Naziv: "Via ferrata Gabrovnica", Slug: "gabrovnica", Status: "active", Lat: f(43.30), Lng: f(20.75),
+		ParkingLat: f(43.29), ParkingLng: f(20.74), MapNote: "Parking kod crkve", ObaveznaOpremaJSON: []byte(`["kaciga","set za feratu"]`)}
+	db.Create(&ferrata)
+	hotel := models.Hotel{Naziv: "Hotel Grand", Slug: "hotel-grand", Status: "active", Lat: 43.2850, Lng: 20.8000}
+	db.Create(&hotel)
+
+	query := "bbox=20.7,43.2,20.9,43.4"
+	w := callOffline(t, db, GetOfflineBundle, query, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d body=%s", w.Code, w.Body.String())
+	}
+	var bundle struct {
+		Verzija string `json:"verzija"`
+		Stavke  struct {
+			Ferata []map[string]any `json:"ferata"`
+			Vrh    []map[string]any `json:"vrh"`
+		} `json:"stavke"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &bundle)
+	if bundle.Verzija == "" || len(bundle.Stavke.Vrh) != 1 || len(bundle.Stavke.Ferata) != 1 {
+		t.Fatalf("unexpected bundle %s", w.Body.String())
+	}
+	if fe := bundle.Stavke.Ferata[0]; fe["parkingLat"] != 43.29 || fe["mapNote"] != "Parking kod crkve" || fe["obaveznaOprema"] == nil {
+		t.Fatalf("ferrata item must carry parking, map note and gear: %+v", fe)
+	}
+	if w.Header().Get("ETag") != `"`+bundle.Verzija+`"` {
+		t.Fatalf("ETag must be the bundle version, got %q", w.Header().Get("ETag"))
+	}
+	if w := callOffline(t, db, GetOfflineBundle, query, `"`+bundle.Verzija+`"`); w.Code != http.StatusNotModified {
+		t.Fatalf("unchanged catalog should be 304, got %d", w.Code)
+	}
+
+	db.Model(&models.Hotel{}).Where("id = ?", hotel.ID).Update("opis", "Renoviran 2026.")
+	db.Model(&models.Peak{}).Where("id = ?", peak.ID).Update("status", "inactive")
+	w = callOffline(t, db, GetOfflineBundleDelta, query+"&since="+bundle.Verzija, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("delta status %d body=%s", w.Code, w.Body.String())
+	}
+	var delta struct {
+		Verzija   string `json:"verzija"`
+		Izmenjeno struct {
+			Hotel  []map[string]any `json:"hotel"`
+			Ferata []map[string]any `json:"ferata"`
+		} `json:"izmenjeno"`
+		Obrisano map[string][]uint `json:"obrisano"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &delta)
+	if delta.Verzija == bundle.Verzija || len(delta.Izmenjeno.Hotel) != 1 || len(delta.Izmenjeno.Ferata) != 0 {
+		t.Fatalf("expected only the hotel changed, got %s", w.Body.String())
+	}
+	if len(delta.Obrisano["vrh"]) != 1 || delta.Obrisano["vrh"][0] != peak.ID {
+		t.Fatalf("deactivated peak must be listed as deleted, got %+v", delta.Obrisano)
+	}
+
+	if w := callOffline(t, db, GetOfflineBundleDelta, query+"&since=nepoznata", ""); w.Code != http.StatusGone {
+		t.Fatalf("unknown version should be 410, got %d", w.Code)
+	}
+}
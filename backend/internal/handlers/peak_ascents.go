@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type peakAscentBody struct {
+	PeakID      uint   `json:"peakId"`
+	Datum       string `json:"datum"`
+	Napomena    string `json:"napomena"`
+	ZimskiUspon *bool  `json:"zimskiUspon"`
+}
+
+func peakAscentToMap(a *models.PeakAscent) gin.H {
+	return gin.H{
+		"id":                a.ID,
+		"peakId":            a.PeakID,
+		"datum":             a.Datum,
+		"izvor":             a.Izvor,
+		"akcijaId":          a.AkcijaID,
+		"trackedActivityId": a.TrackedActivityID,
+		"zimskiUspon":       a.ZimskiUspon,
+		"napomena":          a.Napomena,
+	}
+}
+
+// GetPublicKorisnikVrhovi GET /api/korisnici/:id/vrhovi — dnevnik uspona grupisan po vrhu iz kataloga.
+func GetPublicKorisnikVrhovi(c *gin.Context) {
+	db := DB(c)
+	korisnik, err := getVisiblePublicKorisnik(c, db, c.Param("id"))
+	if err != nil {
+		respondPublicKorisnikNotFound(c)
+		return
+	}
+
+	var ascents []models.PeakAscent
+	if err := db.Preload("Peak").
+		Where("korisnik_id = ?", korisnik.ID).
+		Order("datum ASC, id ASC").
+		Find(&ascents).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju uspona"})
+		return
+	}
+
+	type peakGroup struct {
+		peak   *models.Peak
+		prvi   time.Time
+		zadnji time.Time
+		usponi []gin.H
+	}
+	groups := make(map[uint]*peakGroup)
+	order := make([]uint, 0)
+	for i := range ascents {
+		a := &ascents[i]
+		if a.Peak == nil {
+			continue
+		}
+		g, ok := groups[a.PeakID]
+		if !ok {
+			g = &peakGroup{peak: a.Peak, prvi: a.Datum}
+			groups[a.PeakID] = g
+			order = append(order, a.PeakID)
+		}
+		g.zadnji = a.Datum
+		g.usponi = append(g.usponi, peakAscentToMap(a))
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return groups[order[i]].prvi.After(groups[order[j]].prvi)
+	})
+
+	vrhovi := make([]gin.H, 0, len(order))
+	ukupnoUspona := 0
+	for _, peakID := range order {
+		g := groups[peakID]
+		ukupnoUspona += len(g.usponi)
+		vrhovi = append(vrhovi, gin.H{
+			"peak":           peakToMap(g.peak),
+			"prviUspon":      g.prvi,
+			"poslednjiUspon": g.zadnji,
+			"brojUspona":     len(g.usponi),
+			"usponi":         g.usponi,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"vrhovi":       vrhovi,
+		"ukupnoVrhova": len(vrhovi),
+		"ukupnoUspona": ukupnoUspona,
+	})
+}
+
+// AddMyPeakAscent POST /api/me/vrhovi — ručni unos uspona (npr. pre korišćenja aplikacije).
+func AddMyPeakAscent(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	var body peakAscentBody
+	if err := c.ShouldBindJSON(&body); err != nil || body.PeakID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Izaberite vrh iz kataloga"})
+		return
+	}
+	datum, err := time.Parse("2006-01-02", strings.TrimSpace(body.Datum))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datum mora biti YYYY-MM-DD"})
+		return
+	}
+	if datum.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datum uspona ne može biti u budućnosti"})
+		return
+	}
+	napomena := strings.TrimSpace(body.Napomena)
+	if len(napomena) > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Napomena može imati najviše 500 karaktera"})
+		return
+	}
+	var peak models.Peak
+	if err := db.Where("id = ? AND status = ?", body.PeakID, "active").First(&peak).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vrh nije pronađen"})
+		return
+	}
+	zimski := helpers.IsWinterAscentDate(datum)
+	if body.ZimskiUspon != nil {
+		zimski = *body.ZimskiUspon
+	}
+	ascent := models.PeakAscent{
+		KorisnikID:  user.ID,
+		PeakID:      peak.ID,
+		Datum:       datum,
+		Izvor:       models.PeakAscentIzvorRucno,
+		ZimskiUspon: zimski,
+		Napomena:    napomena,
+	}
+	if err := db.Create(&ascent).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju uspona"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"uspon": peakAscentToMap(&ascent), "peak": peakToMap(&peak)})
+}
+
+// DeleteMyPeakAscent DELETE /api/me/vrhovi/:id — briše samo ručno unete uspone;
+// usponi sa akcija i GPS sesija prate status prijave/aktivnosti.
+func DeleteMyPeakAscent(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID"})
+		return
+	}
+	var ascent models.PeakAscent
+	if err := db.Where("id = ? AND korisnik_id = ?", id, user.ID).First(&ascent).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Uspon nije pronađen"})
+		return
+	}
+	if ascent.Izvor != models.PeakAscentIzvorRucno {
+		c.JSON(http.StatusConflict, gin.H{"error": "Uspon sa akcije ili GPS aktivnosti se ne briše ručno"})
+		return
+	}
+	if err := db.Delete(&ascent).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Brisanje nije uspelo"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/slug"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxPeakListItems = 500
+
+// peakListKriterijum popunjava listu iz kataloga (npr. svi vrhovi Srbije preko 2000 m).
+type peakListKriterijum struct {
+	MinVisinaM int    `json:"minVisinaM"`
+	Drzava     string `json:"drzava"`
+	Planina    string `json:"planina"`
+	Limit      int    `json:"limit"`
+}
+
+type peakListBody struct {
+	Naziv      string              `json:"naziv"`
+	Opis       string              `json:"opis"`
+	Klubska    bool                `json:"klubska"`
+	PeakIDs    []uint              `json:"peakIds"`
+	Kriterijum *peakListKriterijum `json:"kriterijum"`
+}
+
+func peakListToMap(l *models.PeakList, ukupno int, popeto int) gin.H {
+	return gin.H{
+		"id":        l.ID,
+		"naziv":     l.Naziv,
+		"slug":      l.Slug,
+		"opis":      l.Opis,
+		"klubId":    l.KlubID,
+		"ukupno":    ukupno,
+		"popeto":    popeto,
+		"procenat":  peakListPercent(popeto, ukupno),
+		"createdAt": l.CreatedAt,
+		"updatedAt": l.UpdatedAt,
+	}
+}
+
+func peakListPercent(popeto, ukupno int) float64 {
+	if ukupno <= 0 {
+		return 0
+	}
+	return math.Round(float64(popeto)*1000/float64(ukupno)) / 10
+}
+
+// peakListProgressUser: ?korisnik=<id|username> (vidljiv viewer-u) ili ulogovani viewer.
+func peakListProgressUser(c *gin.Context, db *gorm.DB) (*models.Korisnik, bool) {
+	if param := strings.TrimSpace(c.Query("korisnik")); param != "" {
+		k, err := getVisiblePublicKorisnik(c, db, param)
+		if err != nil {
+			respondPublicKorisnikNotFound(c)
+			return nil, false
+		}
+		return k, true
+	}
+	if viewer, ok := AuthUser(c); ok {
+		return &viewer, true
+	}
+	return nil, true
+}
+
+// ListPeakLists GET /api/peak-lists — globalne liste i (?klubId=) liste kluba, sa napretkom korisnika.
+func ListPeakLists(c *gin.Context) {
+	db := DB(c)
+	q := db.Model(&models.PeakList{})
+	if raw := strings.TrimSpace(c.Query("klubId")); raw != "" {
+		klubID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || klubID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći klubId"})
+			return
+		}
+		q = q.Where("klub_id = ?", klubID)
+	} else {
+		q = q.Where("klub_id IS NULL")
+	}
+	var lists []models.PeakList
+	if err := q.Order("naziv ASC").Find(&lists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju lista vrhova"})
+		return
+	}
+	target, ok := peakListProgressUser(c, db)
+	if !ok {
+		return
+	}
+
+	listIDs := make([]uint, 0, len(lists))
+	for _, l := range lists {
+		listIDs = append(listIDs, l.ID)
+	}
+	type countRow struct {
+		PeakListID uint
+		N          int
+	}
+	totals := make(map[uint]int)
+	climbed := make(map[uint]int)
+	if len(listIDs) > 0 {
+		var rows []countRow
+		if err := db.Model(&models.PeakListItem{}).
+			Select("peak_list_id, COUNT(*) AS n").
+			Where("peak_list_id IN ?", listIDs).
+			Group("peak_list_id").
+			Scan(&rows).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju lista vrhova"})
+			return
+		}
+		for _, r := range rows {
+			totals[r.PeakListID] = r.N
+		}
+		if target != nil {
+			rows = nil
+			if err := db.Model(&models.PeakListItem{}).
+				Select("peak_list_id, COUNT(*) AS n").
+				Where("peak_list_id IN ?", listIDs).
+				Where("peak_id IN (?)", db.Model(&models.PeakAscent{}).Select("peak_id").Where("korisnik_id = ?", target.ID)).
+				Group("peak_list_id").
+				Scan(&rows).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju lista vrhova"})
+				return
+			}
+			for _, r := range rows {
+				climbed[r.PeakListID] = r.N
+			}
+		}
+	}
+
+	out := make([]gin.H, 0, len(lists))
+	for i := range lists {
+		out = append(out, peakListToMap(&lists[i], totals[lists[i].ID], climbed[lists[i].ID]))
+	}
+	c.JSON(http.StatusOK, gin.H{"lists": out})
+}
+
+// GetPeakList GET /api/peak-lists/:slug — vrhovi liste sa oznakom popeo/nije i datumom prvog uspona (za mapu).
+func GetPeakList(c *gin.Context) {
+	db := DB(c)
+	var list models.PeakList
+	if err := db.Where("slug = ?", c.Param("slug")).First(&list).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lista vrhova nije pronađena"})
+		return
+	}
+	target, ok := peakListProgressUser(c, db)
+	if !ok {
+		return
+	}
+
+	var items []models.PeakListItem
+	if err := db.Where("peak_list_id = ?", list.ID).Order("pozicija ASC, id ASC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju liste vrhova"})
+		return
+	}
+	peakIDs := make([]uint, 0, len(items))
+	for _, it := range items {
+		peakIDs = append(peakIDs, it.PeakID)
+	}
+	peaksByID := make(map[uint]*models.Peak, len(peakIDs))
+	if len(peakIDs) > 0 {
+		var peaks []models.Peak
+		if err := db.Where("id IN ?", peakIDs).Find(&peaks).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju vrhova"})
+			return
+		}
+		for i := range peaks {
+			peaksByID[peaks[i].ID] = &peaks[i]
+		}
+	}
+	var firstAscents map[uint]interface{}
+	if target != nil {
+		dates, err := helpers.FirstPeakAscentDates(db, target.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju uspona"})
+			return
+		}
+		firstAscents = make(map[uint]interface{}, len(dates))
+		for id, d := range dates {
+			firstAscents[id] = d
+		}
+	}
+
+	vrhovi := make([]gin.H, 0, len(items))
+	popeto := 0
+	for _, it := range items {
+		p, ok := peaksByID[it.PeakID]
+		if !ok {
+			continue
+		}
+		m := peakToMap(p)
+		m["pozicija"] = it.Pozicija
+		prvi, climbed := firstAscents[p.ID]
+		m["popeo"] = climbed
+		m["prviUspon"] = prvi
+		if climbed {
+			popeto++
+		}
+		vrhovi = append(vrhovi, m)
+	}
+
+	resp := gin.H{
+		"list":   peakListToMap(&list, len(vrhovi), popeto),
+		"vrhovi": vrhovi,
+	}
+	if target != nil {
+		resp["korisnikId"] = target.ID
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// canManagePeakList: superadmin za sve liste; admin kluba samo za liste svog kluba.
+func canManagePeakList(c *gin.Context, db *gorm.DB, list *models.PeakList) bool {
+	roleVal, _ := c.Get("role")
+	role, _ := roleVal.(string)
+	if role == "superadmin" {
+		return true
+	}
+	if role != "admin" || list.KlubID == nil {
+		return false
+	}
+	clubID, ok := helpers.GetEffectiveClubID(c, db)
+	return ok && clubID != 0 && clubID == *list.KlubID
+}
+
+// resolvePeakListPeakIDs spaja eksplicitne peakIds i kriterijum u jedinstven, uređen spisak aktivnih vrhova.
+func resolvePeakListPeakIDs(db *gorm.DB, body *peakListBody) ([]uint, string) {
+	seen := make(map[uint]struct{})
+	ordered := make([]uint, 0, len(body.PeakIDs))
+	for _, id := range body.PeakIDs {
+		if id == 0 {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ordered = append(ordered, id)
+	}
+	if len(ordered) > 0 {
+		var count int64
+		if err := db.Model(&models.Peak{}).Where("id IN ?", ordered).Count(&count).Error; err != nil {
+			return nil, "Greška pri čitanju vrhova"
+		}
+		if int(count) != len(ordered) {
+			return nil, "Jedan ili više vrhova nisu pronađeni u katalogu"
+		}
+	}
+	if k := body.Kriterijum; k != nil {
+		q := db.Model(&models.Peak{}).Where("status = ?", "active")
+		if k.MinVisinaM > 0 {
+			q = q.Where("visina_m >= ?", k.MinVisinaM)
+		}
+		if d := strings.TrimSpace(k.Drzava); d != "" {
+			q = q.Where("LOWER(drzava) = LOWER(?)", d)
+		}
+		if p := strings.TrimSpace(k.Planina); p != "" {
+			q = q.Where("LOWER(planina) = LOWER(?)", p)
+		}
+		limit := k.Limit
+		if limit <= 0 || limit > maxPeakListItems {
+			limit = maxPeakListItems
+		}
+		var ids []uint
+		if err := q.Order("visina_m DESC, naziv_vrha ASC").Limit(limit).Pluck("id", &ids).Error; err != nil {
+			return nil, "Greška pri čitanju vrhova"
+		}
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ordered = append(ordered, id)
+		}
+	}
+	if len(ordered) == 0 {
+		return nil, "Lista mora imati bar jedan vrh"
+	}
+	if len(ordered) > maxPeakListItems {
+		return nil, "Lista može imati najviše 500 vrhova"
+	}
+	return ordered, ""
+}
+
+func replacePeakListItemsTx(tx *gorm.DB, listID uint, peakIDs []uint) error {
+	if err := tx.Where("peak_list_id = ?", listID).Delete(&models.PeakListItem{}).Error; err != nil {
+		return err
+	}
+	items := make([]models.PeakListItem, 0, len(peakIDs))
+	for i, id := range peakIDs {
+		items = append(items, models.PeakListItem{PeakListID: listID, PeakID: id, Pozicija: i + 1})
+	}
+	return tx.Create(&items).Error
+}
+
+// CreatePeakList POST /api/peak-lists — superadmin pravi globalne liste, admin liste svog kluba.
+func CreatePeakList(c *gin.Context) {
+	if !RequireAnyRole(c, "Samo admin kluba ili superadmin mogu da prave liste vrhova", "admin", "superadmin") {
+		return
+	}
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	var body peakListBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON"})
+		return
+	}
+	naziv := strings.TrimSpace(body.Naziv)
+	if naziv == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Naziv liste je obavezan"})
+		return
+	}
+
+	list := models.PeakList{
+		Naziv:       naziv,
+		Opis:        strings.TrimSpace(body.Opis),
+		CreatedByID: user.ID,
+	}
+	roleVal, _ := c.Get("role")
+	if role, _ := roleVal.(string); role != "superadmin" || body.Klubska {
+		clubID, ok := helpers.GetEffectiveClubID(c, db)
+		if !ok || clubID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Izaberite klub (superadmin) ili niste u klubu."})
+			return
+		}
+		list.KlubID = &clubID
+	}
+
+	peakIDs, msg := resolvePeakListPeakIDs(db, &body)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	slugStr, err := slug.UniquePeakListSlug(db, naziv, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri generisanju slug-a"})
+		return
+	}
+	list.Slug = slugStr
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&list).Error; err != nil {
+			return err
+		}
+		return replacePeakListItemsTx(tx, list.ID, peakIDs)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju liste vrhova"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"list": peakListToMap(&list, len(peakIDs), 0)})
+}
+
+// UpdatePeakList PUT /api/peak-lists/:id — menja naziv/opis i zamenjuje vrhove liste.
+func UpdatePeakList(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID"})
+		return
+	}
+	db := DB(c)
+	var list models.PeakList
+	if err := db.First(&list, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lista vrhova nije pronađena"})
+		return
+	}
+	if !canManagePeakList(c, db, &list) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate dozvolu da menjate ovu listu"})
+		return
+	}
+	var body peakListBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON"})
+		return
+	}
+	naziv := strings.TrimSpace(body.Naziv)
+	if naziv == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Naziv liste je obavezan"})
+		return
+	}
+	peakIDs, msg := resolvePeakListPeakIDs(db, &body)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if strings.TrimSpace(list.Naziv) != naziv {
+		slugStr, err := slug.UniquePeakListSlug(db, naziv, list.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri generisanju slug-a"})
+			return
+		}
+		list.Slug = slugStr
+	}
+	list.Naziv = naziv
+	list.Opis = strings.TrimSpace(body.Opis)
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&list).Error; err != nil {
+			return err
+		}
+		return replacePeakListItemsTx(tx, list.ID, peakIDs)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju liste vrhova"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"list": peakListToMap(&list, len(peakIDs), 0)})
+}
+
+// DeletePeakList DELETE /api/peak-lists/:id
+func DeletePeakList(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID"})
+		return
+	}
+	db := DB(c)
+	var list models.PeakList
+	if err := db.First(&list, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lista vrhova nije pronađena"})
+		return
+	}
+	if !canManagePeakList(c, db, &list) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate dozvolu da obrišete ovu listu"})
+		return
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("peak_list_id = ?", list.ID).Delete(&models.PeakListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&list).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Brisanje nije uspelo"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
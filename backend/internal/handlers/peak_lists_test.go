@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func testPeakListDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "peak_lists")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.Korisnik{},
+		&models.Block{},
+		&models.Peak{},
+		&models.PeakAscent{},
+		&models.PeakList{},
+		&models.PeakListItem{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func seedPeakListPeak(t *testing.T, db *gorm.DB, naziv, drzava string, visina int) models.Peak {
+	t.Helper()
+	lat, lng := 43.0, 21.0
+	p := models.Peak{NazivVrha: naziv, Slug: naziv, Status: "active", VisinaM: visina, Drzava: drzava, Lat: &lat, Lng: &lng}
+	if err := db.Create(&p).Error; err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestCreatePeakList_SuperadminCriteriaAndProgress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testPeakListDB(t)
+	admin := models.Korisnik{Username: "super", Role: "superadmin"}
+	climber := models.Korisnik{Username: "penjac", Role: "clan"}
+	db.Create(&admin)
+	db.Create(&climber)
+	midzor := seedPeakListPeak(t, db, "midzor", "Srbija", 2169)
+	seedPeakListPeak(t, db, "pancicev-vrh", "Srbija", 2017)
+	seedPeakListPeak(t, db, "rtanj", "Srbija", 1565)
+	seedPeakListPeak(t, db, "durmitor", "Crna Gora", 2523)
+
+	first := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	db.Create(&models.PeakAscent{KorisnikID: climber.ID, PeakID: midzor.ID, Datum: first.AddDate(1, 0, 0), Izvor: models.PeakAscentIzvorRucno})
+	db.Create(&models.PeakAscent{KorisnikID: climber.ID, PeakID: midzor.ID, Datum: first, Izvor: models.PeakAscentIzvorRucno})
+
+	payload, _ := json.Marshal(gin.H{
+		"naziv":      "Srbija 2000+",
+		"kriterijum": gin.H{"minVisinaM": 2000, "drzava": "srbija"},
+	})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/peak-lists", bytes.NewReader(payload))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("db", db)
+	c.Set("role", "superadmin")
+	c.Set("username", admin.Username)
+	CreatePeakList(c)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status %d body=%s", w.Code, w.Body.String())
+	}
+	var created struct {
+		List struct {
+			Slug   string `json:"slug"`
+			Ukupno int    `json:"ukupno"`
+			KlubID *uint  `json:"klubId"`
+		} `json:"list"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if created.List.Ukupno != 2 || created.List.KlubID != nil || created.List.Slug != "srbija-2000" {
+		t.Fatalf("unexpected list %+v", created.List)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/peak-lists/srbija-2000?korisnik=penjac", nil)
+	c.Params = gin.Params{{Key: "slug", Value: "srbija-2000"}}
+	c.Set("db", db)
+	GetPeakList(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get status %d body=%s", w.Code, w.Body.String())
+	}
+	var detail struct {
+		List struct {
+			Popeto   int     `json:"popeto"`
+			Procenat float64 `json:"procenat"`
+		} `json:"list"`
+		Vrhovi []struct {
+			ID        uint       `json:"id"`
+			Popeo     bool       `json:"popeo"`
+			PrviUspon *time.Time `json:"prviUspon"`
+		} `json:"vrhovi"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &detail)
+	if detail.List.Popeto != 1 || detail.List.Procenat != 50 {
+		t.Fatalf("unexpected progress %+v", detail.List)
+	}
+	if len(detail.Vrhovi) != 2 || detail.Vrhovi[0].ID != midzor.ID || !detail.Vrhovi[0].Popeo {
+		t.Fatalf("expected midzor first and climbed, got %+v", detail.Vrhovi)
+	}
+	if detail.Vrhovi[0].PrviUspon == nil || !detail.Vrhovi[0].PrviUspon.Equal(first) {
+		t.Fatalf("expected first ascent %v, got %v", first, detail.Vrhovi[0].PrviUspon)
+	}
+	if detail.Vrhovi[1].Popeo || detail.Vrhovi[1].PrviUspon != nil {
+		t.Fatalf("expected second peak unclimbed, got %+v", detail.Vrhovi[1])
+	}
+}
+
+func TestCreatePeakList_ClanForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testPeakListDB(t)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/peak-lists", bytes.NewReader([]byte(`{"naziv":"x","peakIds":[1]}`)))
+	c.Set("db", db)
+	c.Set("role", "clan")
+	CreatePeakList(c)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestGetPublicKorisnikVrhovi_BlockedViewerGets404(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testPeakListDB(t)
+	owner := models.Korisnik{Username: "vlasnik", Role: "clan"}
+	viewer := models.Korisnik{Username: "gledalac", Role: "clan"}
+	db.Create(&owner)
+	db.Create(&viewer)
+	peak := seedPeakListPeak(t, db, "suva-planina", "Srbija", 1810)
+	db.Create(&models.PeakAscent{KorisnikID: owner.ID, PeakID: peak.ID, Datum: time.Now().AddDate(0, -1, 0), Izvor: models.PeakAscentIzvorRucno})
+
+	call := func(setViewer bool) (int, map[string]any) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/korisnici/vlasnik/vrhovi", nil)
+		c.Params = gin.Params{{Key: "id", Value: "vlasnik"}}
+		c.Set("db", db)
+		if setViewer {
+			c.Set("korisnik", viewer)
+		}
+		GetPublicKorisnikVrhovi(c)
+		var body map[string]any
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+
+	code, body := call(false)
+	if code != http.StatusOK || body["ukupnoVrhova"] != float64(1) {
+		t.Fatalf("expected 1 peak, got %d %v", code, body)
+	}
+	db.Create(&models.Block{BlockerID: owner.ID, BlockedID: viewer.ID})
+	if code, _ := call(true); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for blocked viewer, got %d", code)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"beleg-app/backend/internal/slug"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type superadminPeakBody struct {
@@ -204,12 +205,36 @@ func SuperadminDeletePeak(c *gin.Context) {
 		return
 	}
 	db := DB(c)
-	res := db.Delete(&models.Peak{}, id)
-	if res.Error != nil {
+	// Dnevnik uspona i akcije čuvaju istoriju — takav vrh se sklanja statusom draft, ne brisanjem.
+	var refs int64
+	if err := db.Model(&models.PeakAscent{}).Where("peak_id = ?", id).Count(&refs).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Brisanje nije uspelo"})
 		return
 	}
-	if res.RowsAffected == 0 {
+	if refs == 0 {
+		if err := db.Model(&models.Akcija{}).Where("peak_id = ?", id).Count(&refs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Brisanje nije uspelo"})
+			return
+		}
+	}
+	if refs > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Vrh je vezan za uspone ili akcije; postavite status draft umesto brisanja."})
+		return
+	}
+	var rowsAffected int64
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("peak_id = ?", id).Delete(&models.PeakListItem{}).Error; err != nil {
+			return err
+		}
+		res := tx.Delete(&models.Peak{}, id)
+		rowsAffected = res.RowsAffected
+		return res.Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Brisanje nije uspelo"})
+		return
+	}
+	if rowsAffected == 0 {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Vrh nije pronađen"})
 		return
 	}
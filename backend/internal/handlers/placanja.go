@@ -0,0 +1,540 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+	"beleg-app/backend/internal/payments"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxWebhookBody — webhook događaji provajdera su mali JSON-ovi.
+const maxWebhookBody = 1 << 20
+
+var (
+	paymentsProviderOnce sync.Once
+	paymentsProvider     payments.Provider
+)
+
+var errPovracajProvajder = errors.New("Povraćaj trenutno nije moguć, pokušajte kasnije")
+
+// paymentsFor vraća provajdera online plaćanja (PAYMENTS_PROVIDER) ili nil kada je isključen; testovi ga zamenjuju.
+var paymentsFor = func() payments.Provider {
+	paymentsProviderOnce.Do(func() { paymentsProvider = payments.ProviderFromEnv() })
+	return paymentsProvider
+}
+
+func paymentsOrUnavailable(c *gin.Context) payments.Provider {
+	p := paymentsFor()
+	if p == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Online plaćanje nije uključeno"})
+	}
+	return p
+}
+
+func klubValuta(db *gorm.DB, klubID *uint) string {
+	if klubID != nil {
+		var klub models.Klubovi
+		if err := db.Select("id", "valuta").First(&klub, *klubID).Error; err == nil && klub.Valuta != "" {
+			return klub.Valuta
+		}
+	}
+	return "RSD"
+}
+
+// otvoriPlacanje vraća postojeće plaćanje na čekanju za isti predmet i iznos, ili otvara novo kod provajdera.
+// Idempotency ključ je predmet + redni broj pokušaja, pa ponovljen poziv provajderu ne otvara drugi checkout.
+func otvoriPlacanje(c *gin.Context, db *gorm.DB, provider payments.Provider, p models.Placanje, opis, povratakURL string) {
+	predmet := func(q *gorm.DB) *gorm.DB {
+		q = q.Where("svrha = ? AND korisnik_id = ?", p.Svrha, p.KorisnikID)
+		if p.PrijavaID != nil {
+			return q.Where("prijava_id = ?", *p.PrijavaID)
+		}
+		return q.Where("godina = ?", p.Godina)
+	}
+	var postojeca []models.Placanje
+	if err := predmet(db.Where("provider = ? AND status = ?", provider.Name(), models.PlacanjeNaCekanju)).
+		Order("id DESC").Find(&postojeca).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju plaćanja"})
+		return
+	}
+	for _, e := range postojeca {
+		if math.Abs(e.Iznos-p.Iznos) < 0.005 && e.CheckoutURL != "" {
+			c.JSON(http.StatusOK, gin.H{"placanje": e})
+			return
+		}
+	}
+
+	var pokusaj int64
+	if err := predmet(db.Model(&models.Placanje{})).Count(&pokusaj).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju plaćanja"})
+		return
+	}
+	if p.PrijavaID != nil {
+		p.IdempotencyKey = fmt.Sprintf("prijava-%d-%d", *p.PrijavaID, pokusaj+1)
+	} else {
+		p.IdempotencyKey = fmt.Sprintf("clanarina-%d-%d-%d", p.KorisnikID, p.Godina, pokusaj+1)
+	}
+	p.Provider = provider.Name()
+	p.Status = models.PlacanjeNaCekanju
+	if err := db.Create(&p).Error; err != nil {
+		// Jedinstven idempotency ključ: paralelan zahtev je upravo otvorio isto plaćanje.
+		c.JSON(http.StatusConflict, gin.H{"error": "Plaćanje je upravo pokrenuto, osvežite stranicu"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+	povratak := fmt.Sprintf("%s?placanje=%d", povratakURL, p.ID)
+	klub := ""
+	if p.KlubID != nil {
+		klub = strconv.FormatUint(uint64(*p.KlubID), 10)
+	}
+	co, err := provider.CreateCheckout(ctx, payments.CheckoutRequest{
+		IdempotencyKey: p.IdempotencyKey,
+		Referenca:      strconv.FormatUint(uint64(p.ID), 10),
+		Klub:           klub,
+		Iznos:          p.Iznos,
+		Valuta:         p.Valuta,
+		Opis:           opis,
+		SuccessURL:     povratak,
+		CancelURL:      povratak + "&otkazano=1",
+	})
+	if err != nil {
+		log.Printf("payments: checkout za plaćanje #%d: %v", p.ID, err)
+		db.Model(&p).Updates(map[string]interface{}{"status": models.PlacanjeNeuspelo, "greska": err.Error()})
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Online plaćanje trenutno nije dostupno, pokušajte kasnije"})
+		return
+	}
+	p.CheckoutID, p.CheckoutURL, p.PaymentID = co.ID, co.URL, co.PaymentID
+	if err := db.Model(&p).Updates(map[string]interface{}{
+		"checkout_id":  p.CheckoutID,
+		"checkout_url": p.CheckoutURL,
+		"payment_id":   p.PaymentID,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju plaćanja"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"placanje": p})
+}
+
+// PokreniPlacanjeAkcije POST /akcije/:id/placanje — otvara online plaćanje salda prijave ulogovanog učesnika.
+// Odgovor sadrži checkoutUrl na koji se učesnik preusmerava; uplata se potvrđuje webhook-om provajdera.
+func PokreniPlacanjeAkcije(c *gin.Context) {
+	db := DB(c)
+	provider := paymentsOrUnavailable(c)
+	if provider == nil {
+		return
+	}
+	viewer, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	if akcija.IsCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": helpers.ErrAkcijaCancelled.Error()})
+		return
+	}
+	if akcijaSkipsClubFinances(*akcija) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Privatne ture vodiča se ne plaćaju online preko kluba"})
+		return
+	}
+	var prijava models.Prijava
+	if err := db.Where("akcija_id = ? AND korisnik_id = ? AND status IN ?", akcija.ID, viewer.ID, helpers.PrijavaActiveStatuses).
+		First(&prijava).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Niste prijavljeni na ovu akciju"})
+		return
+	}
+	if prijava.Platio {
+		c.JSON(http.StatusConflict, gin.H{"error": "Prijava je već plaćena"})
+		return
+	}
+	saldo, err := helpers.SaldoPrijaveTx(db, *akcija, prijava)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri obračunu iznosa"})
+		return
+	}
+	saldo = math.Round(saldo*100) / 100
+	if saldo <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Za ovu prijavu nema iznosa za plaćanje"})
+		return
+	}
+	akcijaID, prijavaID := akcija.ID, prijava.ID
+	otvoriPlacanje(c, db, provider, models.Placanje{
+		Svrha:      models.PlacanjeSvrhaAkcija,
+		KlubID:     akcija.KlubID,
+		KorisnikID: viewer.ID,
+		AkcijaID:   &akcijaID,
+		PrijavaID:  &prijavaID,
+		Iznos:      saldo,
+		Valuta:     klubValuta(db, akcija.KlubID),
+	}, "Kotizacija: "+akcija.Naziv, fmt.Sprintf("%s/akcije/%d", actionInvitePublicBaseURL(), akcija.ID))
+}
+
+// PokreniPlacanjeClanarine POST /klub/clanarina/placanje — online plaćanje godišnje članarine (iznos iz podešavanja kluba).
+func PokreniPlacanjeClanarine(c *gin.Context) {
+	db := DB(c)
+	provider := paymentsOrUnavailable(c)
+	if provider == nil {
+		return
+	}
+	viewer, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	if viewer.KlubID == nil || *viewer.KlubID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Niste član kluba"})
+		return
+	}
+	var klub models.Klubovi
+	if err := db.First(&klub, *viewer.KlubID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Klub nije pronađen"})
+		return
+	}
+	if klub.ClanarinaIznos == nil || *klub.ClanarinaIznos <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Klub nije podesio iznos članarine za online plaćanje"})
+		return
+	}
+	godina := time.Now().In(belgradeLoc()).Year()
+	placena, err := helpers.ClanarinaPlacenaZaGodinu(db, viewer.ID, godina)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri proveri članarine"})
+		return
+	}
+	if placena {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Članarina za %d. godinu je već plaćena", godina)})
+		return
+	}
+	otvoriPlacanje(c, db, provider, models.Placanje{
+		Svrha:      models.PlacanjeSvrhaClanarina,
+		KlubID:     viewer.KlubID,
+		KorisnikID: viewer.ID,
+		Godina:     godina,
+		Iznos:      *klub.ClanarinaIznos,
+		Valuta:     klub.Valuta,
+	}, fmt.Sprintf("Članarina %d – %s", godina, klub.Naziv), actionInvitePublicBaseURL()+"/klub")
+}
+
+// obradiPlacanjeDogadjaj knjiži verifikovan događaj; posle uspešne online članarine obaveštava admin/blagajnika kao ručni unos.
+func obradiPlacanjeDogadjaj(db *gorm.DB, provider string, ev payments.Event, payload []byte) error {
+	var p *models.Placanje
+	var promenjeno bool
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		p, promenjeno, err = helpers.ObradiPlacanjeDogadjajTx(tx, provider, ev, string(payload), time.Now())
+		return err
+	}); err != nil {
+		return err
+	}
+	if p == nil || !promenjeno || ev.Tip != payments.DogadjajPlaceno || p.Svrha != models.PlacanjeSvrhaClanarina || p.KlubID == nil {
+		return nil
+	}
+	var clan models.Korisnik
+	_ = db.Select("id", "full_name").First(&clan, p.KorisnikID).Error
+	var adminBlagajnikIDs []uint
+	db.Model(&models.Korisnik{}).Where("klub_id = ? AND role IN ?", *p.KlubID, []string{"admin", "blagajnik"}).Pluck("id", &adminBlagajnikIDs)
+	transakcijaID := uint(0)
+	if p.TransakcijaID != nil {
+		transakcijaID = *p.TransakcijaID
+	}
+	notifications.NotifyUsers(
+		db,
+		adminBlagajnikIDs,
+		models.ObavestenjeTipUplata,
+		"Online uplata članarine",
+		"Članarina – "+clan.FullName,
+		notifications.BuildFinancesNotificationLink(),
+		fmt.Sprintf(`{"transakcijaId":%d,"placanjeId":%d}`, transakcijaID, p.ID),
+	)
+	return nil
+}
+
+// PlacanjeWebhook POST /api/placanja/webhook/:provider — javna ruta za potpisane događaje provajdera.
+// Nevažeći potpis → 400; greška baze → 500 (provajder ponavlja događaj); ponovljen događaj → 200 bez efekta.
+func PlacanjeWebhook(c *gin.Context) {
+	provider := paymentsFor()
+	if provider == nil || provider.Name() != c.Param("provider") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nepoznat provajder plaćanja"})
+		return
+	}
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći zahtev"})
+		return
+	}
+	ev, err := provider.VerifyWebhook(payload, c.GetHeader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := obradiPlacanjeDogadjaj(DB(c), provider.Name(), *ev, payload); err != nil {
+		log.Printf("payments: webhook %s/%s: %v", provider.Name(), ev.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri obradi događaja"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"primljeno": true})
+}
+
+// PotvrdiFakePlacanje POST /placanja/fake/:checkoutId {"ishod":"placeno"|"neuspelo"} — samo uz PAYMENTS_PROVIDER=fake:
+// platilac „završava” checkout, a potpisan događaj prolazi isti put kao webhook pravog provajdera.
+func PotvrdiFakePlacanje(c *gin.Context) {
+	fake, ok := paymentsFor().(*payments.Fake)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lokalni provajder plaćanja nije uključen"})
+		return
+	}
+	db := DB(c)
+	viewer, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	var req struct {
+		Ishod string `json:"ishod"`
+	}
+	_ = c.ShouldBindJSON(&req)
+	var p models.Placanje
+	if err := db.Where("provider = ? AND checkout_id = ?", fake.Name(), c.Param("checkoutId")).First(&p).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plaćanje nije pronađeno"})
+		return
+	}
+	if p.KorisnikID != viewer.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Ovo plaćanje nije vaše"})
+		return
+	}
+	ev := payments.Event{Tip: payments.DogadjajPlaceno, CheckoutID: p.CheckoutID, PaymentID: p.PaymentID, Iznos: p.Iznos}
+	if req.Ishod == payments.DogadjajNeuspelo {
+		ev = payments.Event{Tip: payments.DogadjajNeuspelo, CheckoutID: p.CheckoutID}
+	}
+	payload, sig := fake.SignedEvent(ev)
+	verified, err := fake.VerifyWebhook(payload, func(string) string { return sig })
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := obradiPlacanjeDogadjaj(db, fake.Name(), *verified, payload); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri obradi plaćanja"})
+		return
+	}
+	_ = db.First(&p, p.ID).Error
+	c.JSON(http.StatusOK, gin.H{"placanje": p})
+}
+
+// canRefundPlacanje: kotizaciju vraća organizator akcije ili admin/blagajnik njenog kluba, članarinu admin/blagajnik kluba.
+func canRefundPlacanje(c *gin.Context, db *gorm.DB, p *models.Placanje) bool {
+	if p.AkcijaID != nil {
+		var akcija models.Akcija
+		if err := db.First(&akcija, *p.AkcijaID).Error; err == nil && helpers.CanManageAkcijaEx(c, db, &akcija) {
+			return true
+		}
+	}
+	if !checkFinanceRole(c) {
+		return false
+	}
+	clubID, ok := helpers.GetEffectiveClubID(c, db)
+	return ok && p.KlubID != nil && *p.KlubID == clubID
+}
+
+// VratiPlacanje POST /placanja/:id/povracaj {"iznos": 1500} — povraćaj kod provajdera (bez iznosa: ceo preostali iznos).
+// Tri koraka, bez mreže unutar transakcije: (1) pod lock-om plaćanja provera preostalog iznosa i rezervacija povraćaja,
+// (2) poziv provajdera sa ključem po ciljnom ukupnom povraćaju, (3) knjiženje u novoj kratkoj transakciji. Ako korak 3
+// ne uspe, isplatu knjiži webhook provajdera ili ponovljen zahtev (isti ključ, provajder ne vraća novac dvaput).
+func VratiPlacanje(c *gin.Context) {
+	db := DB(c)
+	provider := paymentsOrUnavailable(c)
+	if provider == nil {
+		return
+	}
+	if _, ok := currentUser(c, db); !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID plaćanja"})
+		return
+	}
+	var p models.Placanje
+	if err := db.First(&p, uint(id)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plaćanje nije pronađeno"})
+		return
+	}
+	if !canRefundPlacanje(c, db, &p) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pravo da vratite ovu uplatu"})
+		return
+	}
+	if p.Provider != provider.Name() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Plaćanje je obavljeno preko drugog provajdera"})
+		return
+	}
+	var req struct {
+		Iznos *float64 `json:"iznos"`
+	}
+	_ = c.ShouldBindJSON(&req)
+	if req.Iznos != nil && math.Round(*req.Iznos*100)/100 <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Iznos povraćaja mora biti pozitivan"})
+		return
+	}
+
+	var (
+		cilj   float64
+		locked models.Placanje
+	)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		lp, _, err := helpers.LockPlacanjeForUpdate(tx, p.ID)
+		if err != nil {
+			return err
+		}
+		cilj, err = helpers.RezervisiPovracajTx(tx, lp, req.Iznos)
+		locked = *lp
+		return err
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, helpers.ErrPovracajPrevelik):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, helpers.ErrPlacanjeNijeUplaceno), errors.Is(err, helpers.ErrPovracajUToku):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri povraćaju"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+	refund, err := provider.Refund(ctx, locked.PaymentID, math.Round((cilj-locked.Vraceno)*100)/100, helpers.PovracajKljuc(&locked, cilj))
+	if err != nil {
+		log.Printf("payments: povraćaj plaćanja #%d: %v", locked.ID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": errPovracajProvajder.Error()})
+		return
+	}
+
+	var knjizeno float64
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		lp, akcija, err := helpers.LockPlacanjeForUpdate(tx, p.ID)
+		if err != nil {
+			return err
+		}
+		knjizeno, err = helpers.PrimeniPovracajTx(tx, lp, akcija, cilj, time.Now())
+		locked = *lp
+		return err
+	}); err != nil {
+		log.Printf("payments: knjiženje povraćaja plaćanja #%d: %v", p.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Povraćaj je izvršen, ali nije knjižen — proverite usklađivanje plaćanja"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"placanje": locked, "knjizeno": knjizeno, "povracajId": refund.ID})
+}
+
+// GetMojaPlacanja GET /placanja/moja — online plaćanja ulogovanog korisnika.
+func GetMojaPlacanja(c *gin.Context) {
+	db := DB(c)
+	viewer, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	var list []models.Placanje
+	if err := db.Where("korisnik_id = ?", viewer.ID).Order("id DESC").Limit(100).Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju plaćanja"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"placanja": list})
+}
+
+// GetPlacanjaUskladjivanje GET /finansije/placanja?status=&od=YYYY-MM-DD&do=YYYY-MM-DD — online plaćanja kluba za
+// usklađivanje sa izveštajem provajdera: zbirovi po statusu, plaćanja za proveru (greška ili uplata bez transakcije)
+// i webhook događaji koji nisu mogli da se primene (nepovezani: događaji koji se ne odnose ni na jedno plaćanje).
+func GetPlacanjaUskladjivanje(c *gin.Context) {
+	if !checkFinanceRole(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo admin ili blagajnik mogu da vide plaćanja"})
+		return
+	}
+	db := DB(c)
+	clubID, ok := helpers.GetEffectiveClubID(c, db)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Izaberite klub (header X-Club-Id)"})
+		return
+	}
+	q := db.Model(&models.Placanje{}).Where("klub_id = ?", clubID)
+	if s := c.Query("status"); s != "" {
+		q = q.Where("status = ?", s)
+	}
+	loc := belgradeLoc()
+	if od, err := time.ParseInLocation("2006-01-02", c.Query("od"), loc); err == nil {
+		q = q.Where("created_at >= ?", od)
+	}
+	if do, err := time.ParseInLocation("2006-01-02", c.Query("do"), loc); err == nil {
+		q = q.Where("created_at < ?", do.AddDate(0, 0, 1))
+	}
+	var list []models.Placanje
+	if err := q.Order("id DESC").Limit(500).Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju plaćanja"})
+		return
+	}
+
+	ukupno := map[string]float64{"uplaceno": 0, "vraceno": 0, "naCekanju": 0}
+	zaProveru := []models.Placanje{}
+	ids := make([]uint, 0, len(list))
+	for _, p := range list {
+		ids = append(ids, p.ID)
+		switch p.Status {
+		case models.PlacanjeNaCekanju:
+			ukupno["naCekanju"] += p.Iznos
+		case models.PlacanjePlaceno, models.PlacanjeDelimicnoVraceno, models.PlacanjeVraceno:
+			ukupno["uplaceno"] += p.Iznos
+			ukupno["vraceno"] += p.Vraceno
+			if p.TransakcijaID == nil {
+				zaProveru = append(zaProveru, p)
+				continue
+			}
+		}
+		if p.Greska != "" {
+			zaProveru = append(zaProveru, p)
+		}
+	}
+	dogadjaji := []models.PlacanjeDogadjaj{}
+	if len(ids) > 0 {
+		if err := db.Where("placanje_id IN ? AND greska <> ''", ids).Order("id DESC").Find(&dogadjaji).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju događaja"})
+			return
+		}
+	}
+	// Događaji koje obrada nije povezala ni sa jednim plaćanjem (nepoznat checkout/payment ID), za isti period, da bi se
+	// uplata koju provajder prijavljuje, a aplikacija ne prepoznaje, videla pri usklađivanju. Klub je iz metapodataka
+	// provajdera; ignorisani tipovi događaja nemaju grešku i ne ulaze.
+	nq := db.Where("placanje_id IS NULL AND greska <> '' AND klub_id = ?", clubID)
+	if od, err := time.ParseInLocation("2006-01-02", c.Query("od"), loc); err == nil {
+		nq = nq.Where("created_at >= ?", od)
+	}
+	if do, err := time.ParseInLocation("2006-01-02", c.Query("do"), loc); err == nil {
+		nq = nq.Where("created_at < ?", do.AddDate(0, 0, 1))
+	}
+	nepovezani := []models.PlacanjeDogadjaj{}
+	if err := nq.Order("id DESC").Limit(500).Find(&nepovezani).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju događaja"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"placanja":           list,
+		"ukupno":             ukupno,
+		"zaProveru":          zaProveru,
+		"dogadjajiSaGreskom": dogadjaji,
+		"nepovezani":         nepovezani,
+	})
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/payments"
+	"beleg-app/backend/internal/services/actions"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func callPlacanjeWebhook(t *testing.T, db *gorm.DB, payload []byte, sig string) int {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/placanja/webhook/fake", bytes.NewReader(payload))
+	c.Request.Header.Set("X-Placanje-Signature", sig)
+	c.Params = gin.Params{{Key: "provider", Value: payments.ProviderFake}}
+	c.Set("db", db)
+	PlacanjeWebhook(c)
+	return w.Code
+}
+
+func countTransakcije(t *testing.T, db *gorm.DB, tip string) (int64, float64) {
+	t.Helper()
+	var rows []models.Transakcija
+	db.Where("tip = ?", tip).Find(&rows)
+	sum := 0.0
+	for _, r := range rows {
+		sum += r.Iznos
+	}
+	return int64(len(rows)), sum
+}
+
+// countingRefundProvider broji pozive Refund-a lokalnog provajdera; fail simulira jedan neuspeo poziv,
+// a onRefund proverava stanje baze dok provajder radi.
+type countingRefundProvider struct {
+	*payments.Fake
+	calls    int
+	fail     bool
+	onRefund func()
+}
+
+func (p *countingRefundProvider) Refund(ctx context.Context, paymentID string, iznos float64, key string) (*payments.Refund, error) {
+	p.calls++
+	if p.onRefund != nil {
+		p.onRefund()
+	}
+	if p.fail {
+		p.fail = false
+		return nil, payments.ErrUnavailable
+	}
+	return p.Fake.Refund(ctx, paymentID, iznos, key)
+}
+
+func TestPlacanja_OnlineFeeWebhookRefundAndFinish(t *testing.T) {
+	db := testRespondSignupDB(t)
+	if err := db.AutoMigrate(&models.Klubovi{}, &models.PlacanjeDogadjaj{}); err != nil {
+		t.Fatal(err)
+	}
+	fake := payments.NewFake("test-secret")
+	prev := paymentsFor
+	paymentsFor = func() payments.Provider { return fake }
+	t.Cleanup(func() { paymentsFor = prev })
+
+	klub := models.Klubovi{Naziv: "PD Placanja", Valuta: "RSD"}
+	db.Create(&klub)
+	vodic := seedRespondApprover(t, db, "pl_vodic")
+	clan := seedRespondRequester(t, db, "pl_clan")
+	db.Model(&models.Korisnik{}).Where("id IN ?", []uint{vodic.ID, clan.ID}).Update("klub_id", klub.ID)
+	vodic.KlubID, clan.KlubID = &klub.ID, &klub.ID
+	akcija := seedRespondAkcija(t, db, vodic, func(a *models.Akcija) {
+		a.OrganizatorTip = "klub"
+		a.KlubID = &klub.ID
+		a.CenaClan = 3000
+		a.CenaOstali = 3500
+	})
+	prijava := models.Prijava{AkcijaID: akcija.ID, KorisnikID: clan.ID, Status: "prijavljen"}
+	db.Create(&prijava)
+	id := strconv.FormatUint(uint64(akcija.ID), 10)
+
+	start := func() (int, models.Placanje) {
+		w, c := m4ManageCtx(t, db, clan, http.MethodPost, "/api/akcije/"+id+"/placanje", nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		PokreniPlacanjeAkcije(c)
+		var body struct {
+			Placanje models.Placanje `json:"placanje"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body.Placanje
+	}
+	code, p := start()
+	if code != http.StatusCreated || p.Iznos != 3000 || p.CheckoutURL == "" || p.Status != models.PlacanjeNaCekanju {
+		t.Fatalf("start payment: %d %+v", code, p)
+	}
+	if code, again := start(); code != http.StatusOK || again.ID != p.ID {
+		t.Fatalf("repeated start must reuse pending checkout: %d %+v", code, again)
+	}
+
+	payload, sig := fake.SignedEvent(payments.Event{Tip: payments.DogadjajPlaceno, CheckoutID: p.CheckoutID, PaymentID: p.PaymentID, Iznos: 3000})
+	if code := callPlacanjeWebhook(t, db, payload, "t=1,v1=00"); code != http.StatusBadRequest {
+		t.Fatalf("bad signature: %d", code)
+	}
+	for i := 0; i < 2; i++ {
+		if code := callPlacanjeWebhook(t, db, payload, sig); code != http.StatusOK {
+			t.Fatalf("webhook #%d: %d", i, code)
+		}
+	}
+	db.First(&prijava, prijava.ID)
+	if n, sum := countTransakcije(t, db, "uplata"); !prijava.Platio || n != 1 || sum != 3000 {
+		t.Fatalf("paid once: platio=%v uplate=%d sum=%v", prijava.Platio, n, sum)
+	}
+
+	raw, _ := json.Marshal(map[string]any{"platio": false})
+	w, c := m4ManageCtx(t, db, vodic, http.MethodPatch, "/api/prijave/"+strconv.Itoa(int(prijava.ID))+"/platio", raw)
+	c.Params = gin.Params{{Key: "id", Value: strconv.Itoa(int(prijava.ID))}}
+	UpdatePrijavaPlatioStatus(c)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("online paid must not be unset by hand: %d %s", w.Code, w.Body.String())
+	}
+
+	placanjeID := strconv.Itoa(int(p.ID))
+	raw, _ = json.Marshal(map[string]any{"iznos": 1000})
+	w, c = m4ManageCtx(t, db, vodic, http.MethodPost, "/api/placanja/"+placanjeID+"/povracaj", raw)
+	c.Params = gin.Params{{Key: "id", Value: placanjeID}}
+	VratiPlacanje(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("refund: %d %s", w.Code, w.Body.String())
+	}
+	// Provajder kasnije javlja isti ukupni povraćaj — bez druge isplate.
+	payload, sig = fake.SignedEvent(payments.Event{Tip: payments.DogadjajVraceno, PaymentID: p.PaymentID, Vraceno: 1000})
+	if code := callPlacanjeWebhook(t, db, payload, sig); code != http.StatusOK {
+		t.Fatalf("refund webhook: %d", code)
+	}
+	db.First(&p, p.ID)
+	if n, sum := countTransakcije(t, db, "isplata"); n != 1 || sum != -1000 || p.Status != models.PlacanjeDelimicnoVraceno {
+		t.Fatalf("partial refund: isplate=%d sum=%v status=%s", n, sum, p.Status)
+	}
+
+	// Preostali iznos se proverava na zaključanom plaćanju pre poziva provajdera.
+	counting := &countingRefundProvider{Fake: fake}
+	paymentsFor = func() payments.Provider { return counting }
+	refundReq := func(iznos float64) int {
+		raw, _ := json.Marshal(map[string]any{"iznos": iznos})
+		w, c := m4ManageCtx(t, db, vodic, http.MethodPost, "/api/placanja/"+placanjeID+"/povracaj", raw)
+		c.Params = gin.Params{{Key: "id", Value: placanjeID}}
+		VratiPlacanje(c)
+		return w.Code
+	}
+	if code := refundReq(2500); code != http.StatusBadRequest || counting.calls != 0 {
+		t.Fatalf("refund over remaining: %d, provider calls %d", code, counting.calls)
+	}
+	// Provajder se zove posle commit-a rezervacije (van transakcije); neuspeo poziv ostavlja rezervaciju
+	// koju nastavlja samo isti iznos (isti ključ).
+	counting.fail = true
+	counting.onRefund = func() {
+		var reserved models.Placanje
+		db.First(&reserved, p.ID)
+		if reserved.PovracajUToku != 3000 {
+			t.Errorf("refund must be reserved and committed before the provider call, got %+v", reserved)
+		}
+	}
+	if code := refundReq(2000); code != http.StatusBadGateway || counting.calls != 1 {
+		t.Fatalf("failed provider call: %d, provider calls %d", code, counting.calls)
+	}
+	if code := refundReq(1500); code != http.StatusConflict || counting.calls != 1 {
+		t.Fatalf("other amount while refund is pending: %d, provider calls %d", code, counting.calls)
+	}
+	if code := refundReq(2000); code != http.StatusOK || counting.calls != 2 {
+		t.Fatalf("retry of pending refund: %d, provider calls %d", code, counting.calls)
+	}
+	counting.onRefund = nil
+	db.First(&p, p.ID)
+	if n, sum := countTransakcije(t, db, "isplata"); n != 2 || sum != -3000 || p.Status != models.PlacanjeVraceno || p.Vraceno != 3000 || p.PovracajUToku != 0 {
+		t.Fatalf("full refund: isplate=%d sum=%v placanje=%+v", n, sum, p)
+	}
+	if code := refundReq(1); code != http.StatusConflict || counting.calls != 2 {
+		t.Fatalf("refund of refunded payment: %d, provider calls %d", code, counting.calls)
+	}
+	paymentsFor = func() payments.Provider { return fake }
+
+	db.Model(&prijava).Update("status", "popeo se")
+	res, err := actions.FinishAction(db, &akcija, vodic, actions.FinishActionInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.PrihodUkupan != 0 {
+		t.Fatalf("online fee already booked, finish must not count it again: %+v", res)
+	}
+	if n, _ := countTransakcije(t, db, "uplata"); n != 1 {
+		t.Fatalf("uplate after finish: %d", n)
+	}
+}
+
+func TestPlacanja_OnlineMembershipWithFakeProvider(t *testing.T) {
+	db := testRespondSignupDB(t)
+	if err := db.AutoMigrate(&models.Klubovi{}, &models.PlacanjeDogadjaj{}); err != nil {
+		t.Fatal(err)
+	}
+	fake := payments.NewFake("")
+	prev := paymentsFor
+	paymentsFor = func() payments.Provider { return fake }
+	t.Cleanup(func() { paymentsFor = prev })
+
+	iznos := 2500.0
+	klub := models.Klubovi{Naziv: "PD Clanarina", Valuta: "RSD", ClanarinaIznos: &iznos}
+	db.Create(&klub)
+	blagajnik := models.Korisnik{Username: "pl_blagajnik", Password: "x", Role: "blagajnik", KlubID: &klub.ID}
+	db.Create(&blagajnik)
+	clan := seedRespondRequester(t, db, "pl_clanarina")
+	db.Model(&clan).Update("klub_id", klub.ID)
+	clan.KlubID = &klub.ID
+
+	start := func() (*httptest.ResponseRecorder, models.Placanje) {
+		w, c := m4ManageCtx(t, db, clan, http.MethodPost, "/api/klub/clanarina/placanje", nil)
+		PokreniPlacanjeClanarine(c)
+		var body struct {
+			Placanje models.Placanje `json:"placanje"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w, body.Placanje
+	}
+	w, p := start()
+	if w.Code != http.StatusCreated || p.Iznos != 2500 || p.Godina != time.Now().In(belgradeLoc()).Year() {
+		t.Fatalf("start membership: %d %s", w.Code, w.Body.String())
+	}
+
+	raw, _ := json.Marshal(map[string]any{"ishod": "placeno"})
+	w, c := m4ManageCtx(t, db, clan, http.MethodPost, "/api/placanja/fake/"+p.CheckoutID, raw)
+	c.Params = gin.Params{{Key: "checkoutId", Value: p.CheckoutID}}
+	PotvrdiFakePlacanje(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("fake confirm: %d %s", w.Code, w.Body.String())
+	}
+	if placena, _ := helpers.ClanarinaPlacenaZaGodinu(db, clan.ID, p.Godina); !placena {
+		t.Fatal("online membership must count as paid")
+	}
+	var obavestenja int64
+	db.Model(&models.Obavestenje{}).Where("user_id = ? AND type = ?", blagajnik.ID, models.ObavestenjeTipUplata).Count(&obavestenja)
+	if obavestenja != 1 {
+		t.Fatalf("treasurer notifications: %d", obavestenja)
+	}
+	if w, _ := start(); w.Code != http.StatusConflict {
+		t.Fatalf("second membership payment in same year: %d %s", w.Code, w.Body.String())
+	}
+
+	// Uplata koju provajder javlja za nepoznat checkout ostaje vidljiva kao nepovezan događaj svog kluba;
+	// ignorisan tip događaja i nepovezan događaj drugog kluba se ne prikazuju.
+	klubRef := strconv.Itoa(int(klub.ID))
+	for _, ev := range []payments.Event{
+		{Tip: payments.DogadjajPlaceno, CheckoutID: "fake_cs_nepoznat", Iznos: 2500, Klub: klubRef},
+		{ID: "evt_ignorisan", Klub: klubRef},
+		{Tip: payments.DogadjajPlaceno, CheckoutID: "fake_cs_tudji", Iznos: 1800, Klub: strconv.Itoa(int(klub.ID) + 1)},
+	} {
+		payload, sig := fake.SignedEvent(ev)
+		if code := callPlacanjeWebhook(t, db, payload, sig); code != http.StatusOK {
+			t.Fatalf("webhook %+v: %d", ev, code)
+		}
+	}
+
+	w, c = m4ManageCtx(t, db, blagajnik, http.MethodGet, "/api/finansije/placanja", nil)
+	c.Request.Header.Set("X-Club-Id", strconv.Itoa(int(klub.ID)))
+	GetPlacanjaUskladjivanje(c)
+	var rec struct {
+		Placanja   []models.Placanje         `json:"placanja"`
+		Ukupno     map[string]float64        `json:"ukupno"`
+		ZaProveru  []models.Placanje         `json:"zaProveru"`
+		Nepovezani []models.PlacanjeDogadjaj `json:"nepovezani"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &rec)
+	if w.Code != http.StatusOK || len(rec.Placanja) != 1 || rec.Ukupno["uplaceno"] != 2500 || len(rec.ZaProveru) != 0 ||
+		len(rec.Nepovezani) != 1 || rec.Nepovezani[0].EventID == "evt_ignorisan" || rec.Nepovezani[0].Greska == "" {
+		t.Fatalf("reconciliation: %d %s", w.Code, w.Body.String())
+	}
+
+	// Pun povraćaj članarine: isplata nosi člana i godinu, pa članarina više nije plaćena.
+	placanjeID := strconv.Itoa(int(p.ID))
+	w, c = m4ManageCtx(t, db, blagajnik, http.MethodPost, "/api/placanja/"+placanjeID+"/povracaj", nil)
+	c.Request.Header.Set("X-Club-Id", klubRef)
+	c.Params = gin.Params{{Key: "id", Value: placanjeID}}
+	VratiPlacanje(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("membership refund: %d %s", w.Code, w.Body.String())
+	}
+	var isplata models.Transakcija
+	if err := db.Where("tip = ?", "isplata").First(&isplata).Error; err != nil || isplata.ClanarinaKorisnikID == nil ||
+		*isplata.ClanarinaKorisnikID != clan.ID || isplata.ClanarinaGodina == nil || *isplata.ClanarinaGodina != p.Godina {
+		t.Fatalf("membership refund row: %+v %v", isplata, err)
+	}
+	if placena, _ := helpers.ClanarinaPlacenaZaGodinu(db, clan.ID, p.Godina); placena {
+		t.Fatal("fully refunded membership must not count as paid")
+	}
+}
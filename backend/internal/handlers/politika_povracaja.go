@@ -0,0 +1,521 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errPolitikaPovracajaForbidden = errors.New("Samo organizator akcije može da menja politiku povraćaja")
+	errPolitikaPovracajaVodic     = errors.New("Politika povraćaja važi samo za akcije kluba")
+	errPovracajBezOnlineUplate    = errors.New("Nema online uplate iz koje se može vratiti ovaj iznos — izaberite ručnu isplatu")
+)
+
+// politikaPovracajaRequest je telo PUT zahteva politike kluba i akcije. Bez otkazKlubaProcenat klub vraća 100%,
+// a akcija preuzima procenat kluba; nepovratne stavke su dozvoljene samo na akciji.
+type politikaPovracajaRequest struct {
+	Pravila            []helpers.PraviloPovracaja `json:"pravila"`
+	OtkazKlubaProcenat *float64                   `json:"otkazKlubaProcenat"`
+	NepovratniPrevoz   []uint                     `json:"nepovratniPrevoz"`
+	NepovratniSmestaj  []uint                     `json:"nepovratniSmestaj"`
+	NepovratnaOprema   []uint                     `json:"nepovratnaOprema"`
+	Napomena           string                     `json:"napomena"`
+}
+
+func (r *politikaPovracajaRequest) normalize() error {
+	pravila, err := helpers.NormalizujPravilaPovracaja(r.Pravila)
+	if err != nil {
+		return err
+	}
+	r.Pravila = pravila
+	if r.OtkazKlubaProcenat != nil && (*r.OtkazKlubaProcenat < 0 || *r.OtkazKlubaProcenat > 100) {
+		return errors.New("Procenat povraćaja pri otkazu kluba mora biti između 0 i 100")
+	}
+	r.Napomena = strings.TrimSpace(r.Napomena)
+	if len([]rune(r.Napomena)) > 1000 {
+		return errors.New("Napomena može imati najviše 1000 znakova")
+	}
+	return nil
+}
+
+// politikaPovracajaDTO: politika sa redovima za prikaz pre prijave; nil kada je nema.
+func politikaPovracajaDTO(db *gorm.DB, akcijaID uint, p *helpers.PolitikaPovracaja) gin.H {
+	if p == nil {
+		return nil
+	}
+	pravila := p.Pravila
+	if pravila == nil {
+		pravila = []helpers.PraviloPovracaja{}
+	}
+	return gin.H{
+		"pravila":            pravila,
+		"otkazKlubaProcenat": p.OtkazKlubaProcenat,
+		"nepovratniPrevoz":   p.NepovratniPrevoz,
+		"nepovratniSmestaj":  p.NepovratniSmestaj,
+		"nepovratnaOprema":   p.NepovratnaOprema,
+		"napomena":           p.Napomena,
+		"izvor":              p.Izvor,
+		"opis":               helpers.OpisPolitikePovracaja(db, akcijaID, p),
+	}
+}
+
+func obavezaPovracajaDTO(o *models.ObavezaPovracaja) gin.H {
+	out := gin.H{
+		"id":           o.ID,
+		"akcijaId":     o.AkcijaID,
+		"prijavaId":    o.PrijavaID,
+		"korisnikId":   o.KorisnikID,
+		"razlog":       o.Razlog,
+		"uplaceno":     o.Uplaceno,
+		"nepovratno":   o.Nepovratno,
+		"procenat":     o.Procenat,
+		"iznos":        o.Iznos,
+		"uplataOnline": o.UplataOnline,
+		"status":       o.Status,
+		"otkazanoAt":   o.OtkazanoAt,
+		"isplacenoAt":  o.IsplacenoAt,
+	}
+	var obracun helpers.ObracunPovracaja
+	if json.Unmarshal([]byte(o.Obracun), &obracun) == nil {
+		out["nepovratneStavke"] = obracun.NepovratneStavke
+	}
+	return out
+}
+
+// GetAkcijaPolitikaPovracaja GET /akcije/:id/politika-povracaja — efektivna politika (klub + akcija) koju učesnik vidi
+// pre prijave. Plaćena aktivna prijava dobija i obračun povraćaja da sada otkaže, a otkazana upisanu obavezu.
+// Organizator dobija i sopstvena podešavanja akcije (akcijaPolitika).
+func GetAkcijaPolitikaPovracaja(c *gin.Context) {
+	db := DB(c)
+	viewer, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	if !viewerCanSeeAkcijaDetails(c, db, akcija, viewer) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Nemate pristup ovoj akciji"})
+		return
+	}
+	politika := helpers.EfektivnaPolitikaPovracaja(db, *akcija)
+	resp := gin.H{"politika": politikaPovracajaDTO(db, akcija.ID, politika)}
+
+	var prijava models.Prijava
+	if db.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, viewer.ID).First(&prijava).Error == nil {
+		var obaveza models.ObavezaPovracaja
+		if db.Where("prijava_id = ?", prijava.ID).First(&obaveza).Error == nil {
+			resp["mojaObaveza"] = obavezaPovracajaDTO(&obaveza)
+		} else if prijava.Platio && slices.Contains(helpers.PrijavaActiveStatuses, prijava.Status) && !akcija.IsCancelled && !akcija.IsCompleted {
+			now := time.Now()
+			razlog := models.ObavezaPovracajaOtkazUcesnika
+			if helpers.HasPenaltyFreeWithdrawal(&prijava, now) {
+				razlog = models.ObavezaPovracajaBezPenala
+			}
+			if !helpers.AkcijaSkipsClubFinances(*akcija) && (politika != nil || razlog == models.ObavezaPovracajaBezPenala) {
+				if obracun, err := helpers.ObracunajPovracajTx(db, *akcija, prijava, politika, razlog, now); err == nil {
+					resp["mojPovracaj"] = obracun
+				}
+			}
+		}
+	}
+	if helpers.CanManageAkcijaEx(c, db, akcija) {
+		resp["akcijaPolitika"] = politikaPovracajaDTO(db, akcija.ID, helpers.PolitikaIzAkcije(helpers.LoadAkcijaPolitikaPovracaja(db, akcija.ID)))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// stavkeAkcijePostoje proverava da nepovratne stavke pripadaju akciji.
+func stavkeAkcijePostoje(tx *gorm.DB, model any, akcijaID uint, ids []uint) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	var n int64
+	tx.Model(model).Where("akcija_id = ? AND id IN ?", akcijaID, ids).Count(&n)
+	return int(n) == len(ids)
+}
+
+// SacuvajAkcijaPolitikuPovracaja PUT /akcije/:id/politika-povracaja — body {"pravila": [{"satiPre": 168, "procenat": 100},
+// {"satiPre": 48, "procenat": 50}], "otkazKlubaProcenat": 100, "nepovratniPrevoz": [3], "napomena": "Autobus je plaćen unapred"}.
+// Prazna pravila preuzimaju pravila kluba; prazno telo briše politiku akcije. Važi za otkaze od trenutka čuvanja.
+func SacuvajAkcijaPolitikuPovracaja(c *gin.Context) {
+	db := DB(c)
+	actor, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	akcija, ok := loadAkcijaForUslovi(c, db)
+	if !ok {
+		return
+	}
+	var req politikaPovracajaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON politike povraćaja"})
+		return
+	}
+	if err := req.normalize(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	next := models.AkcijaPolitikaPovracaja{
+		AkcijaID:           akcija.ID,
+		Pravila:            helpers.EncodePravilaPovracaja(req.Pravila),
+		OtkazKlubaProcenat: req.OtkazKlubaProcenat,
+		NepovratniPrevoz:   helpers.EncodeIDsPovracaja(req.NepovratniPrevoz),
+		NepovratniSmestaj:  helpers.EncodeIDsPovracaja(req.NepovratniSmestaj),
+		NepovratnaOprema:   helpers.EncodeIDsPovracaja(req.NepovratnaOprema),
+		Napomena:           req.Napomena,
+		IzmenioID:          actor.ID,
+	}
+	prazna := next.Pravila == "" && next.OtkazKlubaProcenat == nil && next.NepovratniPrevoz == "" &&
+		next.NepovratniSmestaj == "" && next.NepovratnaOprema == "" && next.Napomena == ""
+
+	var politika *helpers.PolitikaPovracaja
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := helpers.LockAkcijaForUpdate(tx, akcija.ID)
+		if err != nil {
+			return err
+		}
+		if !helpers.CanManageAkcijaEx(c, tx, locked) {
+			return errPolitikaPovracajaForbidden
+		}
+		if helpers.AkcijaSkipsClubFinances(*locked) {
+			return errPolitikaPovracajaVodic
+		}
+		if locked.IsCancelled {
+			return helpers.ErrAkcijaAlreadyCancelled
+		}
+		if locked.IsCompleted {
+			return helpers.ErrCompletedActionFinancialsImmutable
+		}
+		if !stavkeAkcijePostoje(tx, &models.AkcijaPrevoz{}, locked.ID, req.NepovratniPrevoz) ||
+			!stavkeAkcijePostoje(tx, &models.AkcijaSmestaj{}, locked.ID, req.NepovratniSmestaj) ||
+			!stavkeAkcijePostoje(tx, &models.AkcijaOpremaRent{}, locked.ID, req.NepovratnaOprema) {
+			return helpers.ErrPolitikaPovracaja
+		}
+		existing := helpers.LoadAkcijaPolitikaPovracaja(tx, locked.ID)
+		if prazna {
+			if existing != nil {
+				if err := tx.Delete(existing).Error; err != nil {
+					return err
+				}
+			}
+		} else {
+			if existing != nil {
+				next.ID = existing.ID
+				next.CreatedAt = existing.CreatedAt
+			}
+			if err := tx.Save(&next).Error; err != nil {
+				return err
+			}
+		}
+		politika = helpers.EfektivnaPolitikaPovracaja(tx, *locked)
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errPolitikaPovracajaForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, helpers.ErrPolitikaPovracaja):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nepovratne stavke moraju biti prevoz, smeštaj ili oprema ove akcije"})
+		case errors.Is(err, errPolitikaPovracajaVodic), errors.Is(err, helpers.ErrAkcijaAlreadyCancelled),
+			errors.Is(err, helpers.ErrCompletedActionFinancialsImmutable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju politike povraćaja"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Politika povraćaja je sačuvana", "politika": politikaPovracajaDTO(db, akcija.ID, politika)})
+}
+
+// GetKlubPolitikaPovracaja GET /klub/politika-povracaja — politika povraćaja effective kluba (nil kada je nema).
+func GetKlubPolitikaPovracaja(c *gin.Context) {
+	db := DB(c)
+	clubID, ok := helpers.GetEffectiveClubID(c, db)
+	if !ok || clubID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Izaberite klub (X-Club-Id) ili niste u klubu"})
+		return
+	}
+	var klub models.Klubovi
+	if err := db.First(&klub, clubID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Klub nije pronađen"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"politika": politikaPovracajaDTO(db, 0, helpers.ParsePolitikaPovracaja(klub.PolitikaPovracaja))})
+}
+
+// SacuvajKlubPolitikuPovracaja PUT /klub/politika-povracaja — body {"pravila": [...], "otkazKlubaProcenat": 100, "napomena": ""};
+// podrazumevana politika za sve akcije kluba (admin ili sekretar). Prazna pravila i napomena brišu politiku kluba.
+func SacuvajKlubPolitikuPovracaja(c *gin.Context) {
+	db := DB(c)
+	clubID, ok := helpers.GetEffectiveClubID(c, db)
+	if !ok || clubID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Izaberite klub (X-Club-Id) ili niste u klubu"})
+		return
+	}
+	if !canEditClub(c, db, clubID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo admin ili sekretar kluba mogu da menjaju politiku povraćaja"})
+		return
+	}
+	var req politikaPovracajaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći JSON politike povraćaja"})
+		return
+	}
+	if err := req.normalize(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.NepovratniPrevoz)+len(req.NepovratniSmestaj)+len(req.NepovratnaOprema) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nepovratne stavke se podešavaju na akciji"})
+		return
+	}
+	var politika *helpers.PolitikaPovracaja
+	if len(req.Pravila) > 0 || req.OtkazKlubaProcenat != nil || req.Napomena != "" {
+		politika = &helpers.PolitikaPovracaja{Pravila: req.Pravila, OtkazKlubaProcenat: 100, Napomena: req.Napomena}
+		if req.OtkazKlubaProcenat != nil {
+			politika.OtkazKlubaProcenat = *req.OtkazKlubaProcenat
+		}
+	}
+	if err := db.Model(&models.Klubovi{}).Where("id = ?", clubID).
+		Update("politika_povracaja", helpers.EncodePolitikaPovracaja(politika)).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju politike povraćaja"})
+		return
+	}
+	if politika != nil {
+		politika.Izvor = helpers.PolitikaPovracajaIzvorKlub
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Politika povraćaja je sačuvana", "politika": politikaPovracajaDTO(db, 0, politika)})
+}
+
+// GetObavezePovracaja GET /finansije/obaveze-povracaja?status=otvorena — obaveze povraćaja kluba posle otkaza
+// prijava i akcija, sa zbirom otvorenih iznosa.
+func GetObavezePovracaja(c *gin.Context) {
+	if !checkFinanceRole(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo admin ili blagajnik mogu da vide povraćaje"})
+		return
+	}
+	db := DB(c)
+	clubID, ok := helpers.GetEffectiveClubID(c, db)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Izaberite klub (header X-Club-Id)"})
+		return
+	}
+	q := db.Where("klub_id = ?", clubID)
+	if status := strings.TrimSpace(c.Query("status")); status != "" {
+		q = q.Where("status = ?", status)
+	}
+	var rows []models.ObavezaPovracaja
+	if err := q.Order("id DESC").Limit(500).Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju povraćaja"})
+		return
+	}
+	akcijaIDs := make([]uint, 0, len(rows))
+	korisnikIDs := make([]uint, 0, len(rows))
+	for _, o := range rows {
+		akcijaIDs = append(akcijaIDs, o.AkcijaID)
+		korisnikIDs = append(korisnikIDs, o.KorisnikID)
+	}
+	nazivi := map[uint]string{}
+	var akcije []models.Akcija
+	db.Select("id", "naziv").Where("id IN ?", akcijaIDs).Find(&akcije)
+	for _, a := range akcije {
+		nazivi[a.ID] = a.Naziv
+	}
+	imena := map[uint]string{}
+	var korisnici []models.Korisnik
+	db.Select("id", "full_name").Where("id IN ?", korisnikIDs).Find(&korisnici)
+	for _, k := range korisnici {
+		imena[k.ID] = k.FullName
+	}
+
+	out := make([]gin.H, 0, len(rows))
+	ukupnoOtvoreno := 0.0
+	for i := range rows {
+		o := &rows[i]
+		dto := obavezaPovracajaDTO(o)
+		dto["akcijaNaziv"] = nazivi[o.AkcijaID]
+		dto["korisnik"] = imena[o.KorisnikID]
+		out = append(out, dto)
+		if o.Status != models.ObavezaPovracajaIsplacena {
+			ukupnoOtvoreno += o.Iznos
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"obaveze": out, "ukupnoOtvoreno": math.Round(ukupnoOtvoreno*100) / 100})
+}
+
+// IsplatiObavezuPovracaja POST /finansije/obaveze-povracaja/:id/isplata {"nacin": "online"|"rucno"} — zatvara obavezu.
+// Online uplata se podrazumevano vraća preko provajdera kao u VratiPlacanje: obaveza prelazi u "u toku", provajder
+// se zove van transakcije, a isplatu knjiži PrimeniPovracajTx; ponovljen zahtev koristi isti ključ. Ručna isplata
+// knjiži isplatu, a za gotovinsku uplatu i zadržanu uplatu otkazane prijave.
+func IsplatiObavezuPovracaja(c *gin.Context) {
+	if !checkFinanceRole(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Samo admin ili blagajnik mogu da isplate povraćaj"})
+		return
+	}
+	db := DB(c)
+	actor, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	clubID, ok := helpers.GetEffectiveClubID(c, db)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Izaberite klub (header X-Club-Id)"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID povraćaja"})
+		return
+	}
+	var o models.ObavezaPovracaja
+	if err := db.First(&o, uint(id)).Error; err != nil || o.KlubID == nil || *o.KlubID != clubID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Povraćaj nije pronađen"})
+		return
+	}
+	if o.Status == models.ObavezaPovracajaIsplacena {
+		c.JSON(http.StatusConflict, gin.H{"error": helpers.ErrObavezaPovracajaZatvorena.Error()})
+		return
+	}
+	var req struct {
+		Nacin string `json:"nacin"`
+	}
+	_ = c.ShouldBindJSON(&req)
+	online := (o.UplataOnline && o.Iznos > 0) || o.Status == models.ObavezaPovracajaUToku
+	switch req.Nacin {
+	case "":
+	case "online":
+		online = true
+	case "rucno":
+		online = false
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Način isplate je online ili rucno"})
+		return
+	}
+
+	now := time.Now()
+	if !online {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			locked, akcija, err := helpers.LockObavezaPovracajaForUpdate(tx, o.ID)
+			if err != nil {
+				return err
+			}
+			o = *locked
+			return helpers.ZatvoriObavezuPovracajaTx(tx, &o, akcija, nil, actor.ID, now)
+		}); err != nil {
+			if errors.Is(err, helpers.ErrObavezaPovracajaZatvorena) || errors.Is(err, helpers.ErrPovracajUToku) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri knjiženju povraćaja"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Povraćaj je knjižen", "obaveza": obavezaPovracajaDTO(&o)})
+		return
+	}
+
+	provider := paymentsOrUnavailable(c)
+	if provider == nil {
+		return
+	}
+	var p models.Placanje
+	q := db.Where("prijava_id = ? AND provider = ? AND status IN ? AND iznos - vraceno >= ?",
+		o.PrijavaID, provider.Name(), helpers.PlacanjeUspesniStatusi, o.Iznos-0.005)
+	if o.Status == models.ObavezaPovracajaUToku && o.PlacanjeID != nil {
+		q = db.Where("id = ?", *o.PlacanjeID)
+	}
+	if err := q.Order("id DESC").First(&p).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": errPovracajBezOnlineUplate.Error()})
+		return
+	}
+
+	// Korak 1: pod lock-om plaćanja i obaveze rezerviše se povraćaj i obaveza prelazi u "u toku" (commit pre mreže).
+	var (
+		cilj     float64
+		knjizeno bool
+		locked   models.Placanje
+	)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		lp, _, err := helpers.LockPlacanjeForUpdate(tx, p.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&o, o.ID).Error; err != nil {
+			return err
+		}
+		if o.Status == models.ObavezaPovracajaOtvorena &&
+			(!slices.Contains(helpers.PlacanjeUspesniStatusi, lp.Status) || lp.Iznos-lp.Vraceno < o.Iznos-0.005) {
+			return errPovracajBezOnlineUplate
+		}
+		cilj, err = helpers.RezervisiIsplatuObavezeTx(tx, &o, lp)
+		knjizeno = lp.Vraceno >= cilj-0.005
+		locked = *lp
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, helpers.ErrObavezaPovracajaZatvorena), errors.Is(err, errPovracajBezOnlineUplate),
+			errors.Is(err, helpers.ErrPovracajUToku), errors.Is(err, helpers.ErrPlacanjeNijeUplaceno):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, helpers.ErrPovracajPrevelik):
+			c.JSON(http.StatusConflict, gin.H{"error": errPovracajBezOnlineUplate.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri isplati povraćaja"})
+		}
+		return
+	}
+
+	// Korak 2: provajder van transakcije; isti ključ kao VratiPlacanje, pa ponovljen zahtev ne vraća novac dvaput.
+	// Ako je webhook već knjižio ovaj povraćaj, provajder se ne zove ponovo.
+	if !knjizeno {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+		defer cancel()
+		if _, err := provider.Refund(ctx, locked.PaymentID, o.Iznos, helpers.PovracajKljuc(&locked, cilj)); err != nil {
+			log.Printf("payments: povraćaj obaveze #%d (plaćanje #%d): %v", o.ID, locked.ID, err)
+			c.JSON(http.StatusBadGateway, gin.H{"error": errPovracajProvajder.Error()})
+			return
+		}
+	}
+
+	// Korak 3: knjiženje i zatvaranje u novoj kratkoj transakciji (ponovna provera pod lock-om).
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		lp, akcija, err := helpers.LockPlacanjeForUpdate(tx, p.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&o, o.ID).Error; err != nil {
+			return err
+		}
+		if _, err := helpers.PrimeniPovracajTx(tx, lp, akcija, cilj, now); err != nil {
+			return err
+		}
+		return helpers.ZatvoriObavezuPovracajaTx(tx, &o, akcija, &lp.ID, actor.ID, now)
+	}); err != nil {
+		if errors.Is(err, helpers.ErrObavezaPovracajaZatvorena) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("payments: knjiženje povraćaja obaveze #%d: %v", o.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Povraćaj je izvršen, ali nije knjižen — ponovite isplatu ili proverite usklađivanje plaćanja"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Povraćaj je izvršen", "obaveza": obavezaPovracajaDTO(&o)})
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/payments"
+	"beleg-app/backend/internal/services/actions"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func seedPovracajKlub(t *testing.T, db *gorm.DB, prefix string) (models.Klubovi, models.Korisnik, models.Korisnik, models.Korisnik) {
+	t.Helper()
+	if err := db.AutoMigrate(&models.Klubovi{}, &models.PlacanjeDogadjaj{}); err != nil {
+		t.Fatal(err)
+	}
+	klub := models.Klubovi{Naziv: "PD " + prefix, Valuta: "RSD"}
+	db.Create(&klub)
+	admin := models.Korisnik{Username: prefix + "_admin", Password: "x", Role: "admin", KlubID: &klub.ID}
+	db.Create(&admin)
+	blagajnik := models.Korisnik{Username: prefix + "_blagajnik", Password: "x", Role: "blagajnik", KlubID: &klub.ID}
+	db.Create(&blagajnik)
+	vodic := seedRespondApprover(t, db, prefix+"_vodic")
+	db.Model(&vodic).Update("klub_id", klub.ID)
+	vodic.KlubID = &klub.ID
+	return klub, admin, blagajnik, vodic
+}
+
+func callIsplatiObavezu(t *testing.T, db *gorm.DB, user models.Korisnik, id uint, body []byte) (int, string) {
+	t.Helper()
+	sid := strconv.Itoa(int(id))
+	w, c := m4ManageCtx(t, db, user, http.MethodPost, "/api/finansije/obaveze-povracaja/"+sid+"/isplata", body)
+	c.Params = gin.Params{{Key: "id", Value: sid}}
+	IsplatiObavezuPovracaja(c)
+	return w.Code, w.Body.String()
+}
+
+func TestPolitikaPovracaja_SelfCancelTiersAndCashPayout(t *testing.T) {
+	db := testRespondSignupDB(t)
+	klub, admin, blagajnik, vodic := seedPovracajKlub(t, db, "pp")
+	clan := seedRespondRequester(t, db, "pp_clan")
+	db.Model(&clan).Update("klub_id", klub.ID)
+	clan.KlubID = &klub.ID
+	start := time.Now().Add(72 * time.Hour)
+	akcija := seedRespondAkcija(t, db, vodic, func(a *models.Akcija) {
+		a.OrganizatorTip = "klub"
+		a.KlubID = &klub.ID
+		a.CenaClan = 3000
+		a.StartAt = &start
+		a.Datum = start
+	})
+	bus := models.AkcijaPrevoz{AkcijaID: akcija.ID, TipPrevoza: "Autobus", NazivGrupe: "Polazak Beograd", CenaPoOsobi: 1000}
+	db.Create(&bus)
+	id := strconv.FormatUint(uint64(akcija.ID), 10)
+
+	raw, _ := json.Marshal(map[string]any{"pravila": []map[string]any{{"satiPre": 48, "procenat": 50}, {"satiPre": 168, "procenat": 100}}})
+	w, c := m4ManageCtx(t, db, admin, http.MethodPut, "/api/klub/politika-povracaja", raw)
+	SacuvajKlubPolitikuPovracaja(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("club policy: %d %s", w.Code, w.Body.String())
+	}
+	putAkcija := func(body map[string]any) int {
+		raw, _ := json.Marshal(body)
+		w, c := m4ManageCtx(t, db, vodic, http.MethodPut, "/api/akcije/"+id+"/politika-povracaja", raw)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		SacuvajAkcijaPolitikuPovracaja(c)
+		return w.Code
+	}
+	if code := putAkcija(map[string]any{"nepovratniPrevoz": []uint{bus.ID + 100}}); code != http.StatusBadRequest {
+		t.Fatalf("foreign transport item: %d", code)
+	}
+	if code := putAkcija(map[string]any{"nepovratniPrevoz": []uint{bus.ID}, "napomena": "Autobus je plaćen unapred"}); code != http.StatusOK {
+		t.Fatalf("action policy: %d", code)
+	}
+
+	prijava := models.Prijava{AkcijaID: akcija.ID, KorisnikID: clan.ID, Status: "prijavljen", Platio: true}
+	db.Create(&prijava)
+	db.Create(&models.PrijavaIzbori{PrijavaID: prijava.ID, SelectedPrevozIDs: "[" + strconv.Itoa(int(bus.ID)) + "]"})
+
+	w, c = m4ManageCtx(t, db, clan, http.MethodGet, "/api/akcije/"+id+"/politika-povracaja", nil)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	GetAkcijaPolitikaPovracaja(c)
+	var pregled struct {
+		Politika struct {
+			Pravila []any    `json:"pravila"`
+			Izvor   string   `json:"izvor"`
+			Opis    []string `json:"opis"`
+		} `json:"politika"`
+		MojPovracaj struct {
+			Procenat   float64 `json:"procenat"`
+			Nepovratno float64 `json:"nepovratno"`
+			Iznos      float64 `json:"iznos"`
+		} `json:"mojPovracaj"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &pregled)
+	if w.Code != http.StatusOK || len(pregled.Politika.Pravila) != 2 || pregled.Politika.Izvor != "akcija" || len(pregled.Politika.Opis) == 0 ||
+		pregled.MojPovracaj.Procenat != 50 || pregled.MojPovracaj.Nepovratno != 1000 || pregled.MojPovracaj.Iznos != 1500 {
+		t.Fatalf("preview: %d %s", w.Code, w.Body.String())
+	}
+
+	code, out := callOtkaziPrijavuWithBody(t, db, akcija.ID, clan.Username)
+	povracaj, _ := out["povracaj"].(map[string]any)
+	if code != http.StatusOK || povracaj == nil || povracaj["iznos"] != 1500.0 || povracaj["razlog"] != models.ObavezaPovracajaOtkazUcesnika {
+		t.Fatalf("paid self-cancel under policy: %d %v", code, out)
+	}
+	db.First(&prijava, prijava.ID)
+	if prijava.Status != "otkazano" || !prijava.Platio {
+		t.Fatalf("prijava after cancel: %+v", prijava)
+	}
+
+	w, c = m4ManageCtx(t, db, blagajnik, http.MethodGet, "/api/finansije/obaveze-povracaja?status=otvorena", nil)
+	GetObavezePovracaja(c)
+	var lista struct {
+		Obaveze        []map[string]any `json:"obaveze"`
+		UkupnoOtvoreno float64          `json:"ukupnoOtvoreno"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &lista)
+	if w.Code != http.StatusOK || len(lista.Obaveze) != 1 || lista.UkupnoOtvoreno != 1500 {
+		t.Fatalf("open obligations: %d %s", w.Code, w.Body.String())
+	}
+	obavezaID := uint(lista.Obaveze[0]["id"].(float64))
+	if code, body := callIsplatiObavezu(t, db, blagajnik, obavezaID, nil); code != http.StatusOK {
+		t.Fatalf("cash payout: %d %s", code, body)
+	}
+	// Gotovinska uplata otkazane prijave nije bila knjižena: knjiže se uplata i povraćaj.
+	if n, sum := countTransakcije(t, db, "uplata"); n != 1 || sum != 4000 {
+		t.Fatalf("booked cash payment: %d %v", n, sum)
+	}
+	if n, sum := countTransakcije(t, db, "isplata"); n != 1 || sum != -1500 {
+		t.Fatalf("booked refund: %d %v", n, sum)
+	}
+	if code, _ := callIsplatiObavezu(t, db, blagajnik, obavezaID, nil); code != http.StatusConflict {
+		t.Fatalf("second payout: %d", code)
+	}
+}
+
+func TestPolitikaPovracaja_ClubCancelRefundsOnlinePayment(t *testing.T) {
+	db := testRespondSignupDB(t)
+	fake := payments.NewFake("test-secret")
+	prev := paymentsFor
+	paymentsFor = func() payments.Provider { return fake }
+	t.Cleanup(func() { paymentsFor = prev })
+
+	klub, admin, blagajnik, vodic := seedPovracajKlub(t, db, "ppo")
+	clan := seedRespondRequester(t, db, "ppo_clan")
+	db.Model(&clan).Update("klub_id", klub.ID)
+	clan.KlubID = &klub.ID
+	akcija := seedRespondAkcija(t, db, vodic, func(a *models.Akcija) {
+		a.OrganizatorTip = "klub"
+		a.KlubID = &klub.ID
+		a.CenaClan = 2500
+	})
+	prijava := models.Prijava{AkcijaID: akcija.ID, KorisnikID: clan.ID, Status: "prijavljen"}
+	db.Create(&prijava)
+	id := strconv.FormatUint(uint64(akcija.ID), 10)
+
+	w, c := m4ManageCtx(t, db, clan, http.MethodPost, "/api/akcije/"+id+"/placanje", nil)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	PokreniPlacanjeAkcije(c)
+	var started struct {
+		Placanje models.Placanje `json:"placanje"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &started)
+	payload, sig := fake.SignedEvent(payments.Event{Tip: payments.DogadjajPlaceno, CheckoutID: started.Placanje.CheckoutID, Iznos: 2500})
+	if code := callPlacanjeWebhook(t, db, payload, sig); code != http.StatusOK {
+		t.Fatalf("payment webhook: %d", code)
+	}
+
+	if _, err := actions.CancelAction(db, akcija.ID, "Nevreme u planini", admin.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	var o models.ObavezaPovracaja
+	if err := db.Where("prijava_id = ?", prijava.ID).First(&o).Error; err != nil {
+		t.Fatal(err)
+	}
+	if o.Razlog != models.ObavezaPovracajaOtkazAkcije || o.Procenat != 100 || o.Iznos != 2500 || !o.UplataOnline {
+		t.Fatalf("club-cancel obligation: %+v", o)
+	}
+
+	// Provajder pada: obaveza ostaje u toku sa rezervisanim povraćajem, ručna isplata je odbijena,
+	// a ponovljen zahtev šalje isti ključ i zatvara obavezu.
+	counting := &countingRefundProvider{Fake: fake, fail: true}
+	paymentsFor = func() payments.Provider { return counting }
+	if code, body := callIsplatiObavezu(t, db, blagajnik, o.ID, nil); code != http.StatusBadGateway {
+		t.Fatalf("failed provider payout: %d %s", code, body)
+	}
+	db.First(&o, o.ID)
+	if o.Status != models.ObavezaPovracajaUToku || o.PlacanjeID == nil || *o.PlacanjeID != started.Placanje.ID {
+		t.Fatalf("after failed payout: %+v", o)
+	}
+	if code, body := callIsplatiObavezu(t, db, blagajnik, o.ID, []byte(`{"nacin":"rucno"}`)); code != http.StatusConflict {
+		t.Fatalf("manual payout while in progress: %d %s", code, body)
+	}
+	if code, body := callIsplatiObavezu(t, db, blagajnik, o.ID, nil); code != http.StatusOK {
+		t.Fatalf("online payout: %d %s", code, body)
+	}
+	if code, _ := callIsplatiObavezu(t, db, blagajnik, o.ID, nil); code != http.StatusConflict || counting.calls != 2 {
+		t.Fatalf("repeated payout: %d calls=%d", code, counting.calls)
+	}
+	var p models.Placanje
+	db.First(&p, started.Placanje.ID)
+	db.First(&o, o.ID)
+	if p.Status != models.PlacanjeVraceno || p.PovracajUToku != 0 || o.Status != models.ObavezaPovracajaIsplacena || o.PlacanjeID == nil || *o.PlacanjeID != p.ID {
+		t.Fatalf("after online payout: placanje=%s obaveza=%+v", p.Status, o)
+	}
+	// Online uplata je knjižena pri plaćanju; povraćaj je jedna isplata, bez nove uplate.
+	if n, _ := countTransakcije(t, db, "uplata"); n != 1 {
+		t.Fatalf("uplate: %d", n)
+	}
+	if n, sum := countTransakcije(t, db, "isplata"); n != 1 || sum != -2500 {
+		t.Fatalf("isplate: %d %v", n, sum)
+	}
+}
+
+func seedPovracajAkcija(t *testing.T, db *gorm.DB, prefix string, start time.Time, pravila []map[string]any) (models.Akcija, models.Korisnik, models.Korisnik) {
+	t.Helper()
+	klub, admin, _, vodic := seedPovracajKlub(t, db, prefix)
+	clan := seedRespondRequester(t, db, prefix+"_clan")
+	db.Model(&clan).Update("klub_id", klub.ID)
+	clan.KlubID = &klub.ID
+	akcija := seedRespondAkcija(t, db, vodic, func(a *models.Akcija) {
+		a.OrganizatorTip = "klub"
+		a.KlubID = &klub.ID
+		a.CenaClan = 3000
+		a.StartAt = &start
+		a.Datum = start
+	})
+	raw, _ := json.Marshal(map[string]any{"pravila": pravila})
+	w, c := m4ManageCtx(t, db, admin, http.MethodPut, "/api/klub/politika-povracaja", raw)
+	SacuvajKlubPolitikuPovracaja(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("club policy: %d %s", w.Code, w.Body.String())
+	}
+	prijava := models.Prijava{AkcijaID: akcija.ID, KorisnikID: clan.ID, Status: "prijavljen"}
+	db.Create(&prijava)
+	raw, _ = json.Marshal(map[string]any{"platio": true})
+	sid := strconv.Itoa(int(prijava.ID))
+	w, c = m4ManageCtx(t, db, vodic, http.MethodPatch, "/api/prijave/"+sid+"/platio", raw)
+	c.Params = gin.Params{{Key: "id", Value: sid}}
+	UpdatePrijavaPlatioStatus(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("mark paid: %d %s", w.Code, w.Body.String())
+	}
+	return akcija, clan, vodic
+}
+
+func callOtkaziPrijavuPotvrda(t *testing.T, db *gorm.DB, akcija models.Akcija, clan models.Korisnik, query string) (int, map[string]any) {
+	t.Helper()
+	id := strconv.FormatUint(uint64(akcija.ID), 10)
+	w, c := m4ManageCtx(t, db, clan, http.MethodDelete, "/api/akcije/"+id+"/prijavi"+query, nil)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	OtkaziPrijavuNaAkciju(c)
+	var out map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &out)
+	return w.Code, out
+}
+
+func TestPolitikaPovracaja_CashRefundUsesAmountRecordedAtPayment(t *testing.T) {
+	db := testRespondSignupDB(t)
+	akcija, clan, _ := seedPovracajAkcija(t, db, "ppg", time.Now().Add(72*time.Hour), []map[string]any{{"satiPre": 48, "procenat": 50}})
+	// Poskupljenje posle uplate ne menja ono što je blagajnik primio.
+	db.Model(&akcija).Update("cena_clan", 5000)
+
+	code, out := callOtkaziPrijavuPotvrda(t, db, akcija, clan, "")
+	povracaj, _ := out["povracaj"].(map[string]any)
+	if code != http.StatusOK || povracaj == nil || povracaj["uplaceno"] != 3000.0 || povracaj["iznos"] != 1500.0 {
+		t.Fatalf("cash refund must be based on the recorded payment: %d %v", code, out)
+	}
+}
+
+func TestPolitikaPovracaja_ZeroRefundNeedsConfirmation(t *testing.T) {
+	db := testRespondSignupDB(t)
+	akcija, clan, _ := seedPovracajAkcija(t, db, "ppz", time.Now().Add(24*time.Hour), []map[string]any{{"satiPre": 48, "procenat": 50}})
+
+	code, out := callOtkaziPrijavuPotvrda(t, db, akcija, clan, "")
+	if code != http.StatusConflict || out["potrebnaPotvrda"] != true {
+		t.Fatalf("paid cancel without refund must ask for confirmation: %d %v", code, out)
+	}
+	var prijava models.Prijava
+	db.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, clan.ID).First(&prijava)
+	if prijava.Status != "prijavljen" {
+		t.Fatalf("unconfirmed cancel must not change the signup: %+v", prijava)
+	}
+
+	code, out = callOtkaziPrijavuPotvrda(t, db, akcija, clan, "?potvrdaBezPovracaja=true")
+	povracaj, _ := out["povracaj"].(map[string]any)
+	if code != http.StatusOK || povracaj == nil || povracaj["procenat"] != 0.0 || povracaj["iznos"] != 0.0 {
+		t.Fatalf("confirmed cancel without refund: %d %v", code, out)
+	}
+}
+
+func TestPolitikaPovracaja_OrganizerCancelIsFullRefund(t *testing.T) {
+	db := testRespondSignupDB(t)
+	// Politika bi učesniku 24h pre početka vratila 0%, ali otkaz organizatora je otkaz kluba.
+	akcija, clan, vodic := seedPovracajAkcija(t, db, "ppv", time.Now().Add(24*time.Hour), []map[string]any{{"satiPre": 48, "procenat": 50}})
+	var prijava models.Prijava
+	db.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, clan.ID).First(&prijava)
+
+	sid := strconv.Itoa(int(prijava.ID))
+	raw, _ := json.Marshal(map[string]string{"status": "otkazano"})
+	w, c := m4ManageCtx(t, db, vodic, http.MethodPut, "/api/prijave/"+sid+"/status", raw)
+	c.Params = gin.Params{{Key: "id", Value: sid}}
+	UpdatePrijavaStatus(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("organizer cancel: %d %s", w.Code, w.Body.String())
+	}
+	var obaveze []models.ObavezaPovracaja
+	db.Where("prijava_id = ?", prijava.ID).Find(&obaveze)
+	if len(obaveze) != 1 || obaveze[0].Razlog != models.ObavezaPovracajaOtkazAkcije || obaveze[0].Procenat != 100 || obaveze[0].Iznos != 3000 {
+		t.Fatalf("organizer cancel must record a full refund obligation: %+v", obaveze)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"beleg-app/backend/internal/achievements"
 	"beleg-app/backend/internal/helpers"
 	"beleg-app/backend/internal/models"
 	"beleg-app/backend/internal/notifications"
@@ -105,8 +106,9 @@ func notifyMentionsFromContent(db *gorm.DB, mentionUsernames []string, sender mo
 }
 
 type CreatePostRequest struct {
-	Content  string `json:"content"`
-	ImageURL string `json:"imageUrl"`
+	Content    string `json:"content"`
+	ImageURL   string `json:"imageUrl"`
+	ActivityID *uint  `json:"activityId"` // deljenje završene aktivnosti (sličica rute)
 }
 
 type UpdatePostRequest struct {
@@ -196,6 +198,7 @@ func GetPosts(c *gin.Context) {
 		ID           uint    `json:"id"`
 		Content      string  `json:"content"`
 		ImageURL     string  `json:"imageUrl,omitempty"`
+		ActivityID   *uint   `json:"activityId,omitempty"`
 		CreatedAt    string  `json:"createdAt"`
 		User         UserDTO `json:"user"`
 		LikeCount    int64   `json:"likeCount"`
@@ -229,6 +232,7 @@ func GetPosts(c *gin.Context) {
 			ID:           p.ID,
 			Content:      p.Content,
 			ImageURL:     p.ImageURL,
+			ActivityID:   p.ActivityID,
 			CreatedAt:    p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			User:         u,
 			LikeCount:    likeCountMap[p.ID],
@@ -315,6 +319,7 @@ func GetPost(c *gin.Context) {
 			"id":           post.ID,
 			"content":      post.Content,
 			"imageUrl":     post.ImageURL,
+			"activityId":   post.ActivityID,
 			"createdAt":    post.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			"user":         u,
 			"likeCount":    likeCount,
@@ -357,6 +362,7 @@ func CreatePost(c *gin.Context) {
 
 	var content string
 	var imageURL string
+	var activityID *uint
 
 	if isMultipart {
 		// multipart: content (text) + image (file)
@@ -370,6 +376,15 @@ func CreatePost(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Tekst objave je predugačak (maks. 3000 karaktera)"})
 			return
 		}
+		if raw := strings.TrimSpace(c.PostForm("activityId")); raw != "" {
+			n, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći ID aktivnosti"})
+				return
+			}
+			id := uint(n)
+			activityID = &id
+		}
 
 		files := c.Request.MultipartForm.File["image"]
 		if len(files) > 0 {
@@ -428,7 +443,7 @@ func CreatePost(c *gin.Context) {
 			imageURL = uploadResult.SecureURL
 		}
 
-		if content == "" && imageURL == "" {
+		if content == "" && imageURL == "" && activityID == nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Unesite tekst ili dodajte sliku"})
 			return
 		}
@@ -442,8 +457,9 @@ func CreatePost(c *gin.Context) {
 
 		content = strings.TrimSpace(req.Content)
 		imageURL = strings.TrimSpace(req.ImageURL)
+		activityID = req.ActivityID
 
-		if content == "" && imageURL == "" {
+		if content == "" && imageURL == "" && activityID == nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Unesite tekst ili imageUrl sa slikom"})
 			return
 		}
@@ -453,12 +469,30 @@ func CreatePost(c *gin.Context) {
 		}
 	}
 
+	if activityID != nil {
+		// Deli se samo sopstvena završena aktivnost; bez sopstvene slike objava dobija sličicu rute.
+		var shared []models.TrackedActivity
+		if err := db.Where("id = ? AND user_id = ? AND status = ?", *activityID, korisnik.ID, models.TrackedActivityStatusCompleted).
+			Limit(1).Find(&shared).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju aktivnosti"})
+			return
+		}
+		if len(shared) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Možete podeliti samo svoju završenu aktivnost"})
+			return
+		}
+		if imageURL == "" {
+			imageURL = shared[0].RouteThumbnailURL
+		}
+	}
+
 	post := models.Post{
-		ClubID:   clubID,
-		UserID:   korisnik.ID,
-		AuthorID: korisnik.ID,
-		Content:  content,
-		ImageURL: imageURL,
+		ClubID:     clubID,
+		UserID:     korisnik.ID,
+		AuthorID:   korisnik.ID,
+		Content:    content,
+		ImageURL:   imageURL,
+		ActivityID: activityID,
 	}
 
 	if err := db.Create(&post).Error; err != nil {
@@ -469,6 +503,7 @@ func CreatePost(c *gin.Context) {
 	// Notifikuj označene (@username) u tekstu objave — samo članove istog kluba kao objava.
 	mentions := extractMentionUsernames(content)
 	notifyMentionsFromContent(db, mentions, korisnik, content, korisnik.ID, post.ID, clubID)
+	achievements.EvaluateBestEffort(db, []uint{korisnik.ID}, achievements.EventPostCreated)
 
 	db.Preload("User", func(tx *gorm.DB) *gorm.DB {
 		return tx.Select("id, username, full_name, avatar_url, role, klub_id")
@@ -485,6 +520,7 @@ func CreatePost(c *gin.Context) {
 		"id":           post.ID,
 		"content":      post.Content,
 		"imageUrl":     post.ImageURL,
+		"activityId":   post.ActivityID,
 		"createdAt":    post.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		"likeCount":    int64(0),
 		"commentCount": int64(0),
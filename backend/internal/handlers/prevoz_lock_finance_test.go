@@ -38,9 +38,12 @@ func testPrevozDB(t *testing.T) *gorm.DB {
 		&models.ActionInviteLink{},
 		&models.AkcijaSmestaj{},
 		&models.AkcijaPrevoz{},
+		&models.AkcijaOprema{},
 		&models.AkcijaOpremaRent{},
 		&models.Transakcija{},
+		&models.Placanje{},
 		&models.Obavestenje{},
+		&models.AkcijaVerzija{},
 	); err != nil {
 		t.Fatalf("migrate: %v", err)
 	}
@@ -39,8 +39,19 @@ func testRespondSignupDB(t *testing.T) *gorm.DB {
 		&models.AkcijaSmestaj{},
 		&models.AkcijaPrevoz{},
 		&models.AkcijaOpremaRent{},
+		&models.AkcijaRuta{},
+		&models.AkcijaPomeranje{},
+		&models.AkcijaVerzija{},
+		&models.AkcijaVodic{},
+		&models.AkcijaForma{},
+		&models.AkcijaUslovi{},
+		&models.AkcijaUslovIzuzetak{},
+		&models.AkcijaCenovnik{},
+		&models.AkcijaPromoKod{},
 		&models.Obavestenje{},
 		&models.Transakcija{},
+		&models.Placanje{},
+		&models.AkcijaPolitikaPovracaja{}, &models.ObavezaPovracaja{},
 		&models.ActionInviteLink{},
 		&models.ActionParticipationRequest{},
 		&models.GuideActionRating{},
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type searchResult struct {
+	Tip       string  `json:"tip"`
+	ID        uint    `json:"id"`
+	Naslov    string  `json:"naslov"`
+	Podnaslov string  `json:"podnaslov,omitempty"`
+	Slug      string  `json:"slug,omitempty"` // slug kataloga ili username za korisnike/vodiče
+	SlikaURL  string  `json:"slikaUrl,omitempty"`
+	Rank      float64 `json:"rank"`
+}
+
+type searchRow struct {
+	ID        uint
+	Naslov    string
+	Podnaslov string
+	Slug      string
+	SlikaURL  string
+	Rank      float64
+}
+
+// searchViewer — opcioni prijavljeni korisnik (privatnost akcija, blokade).
+type searchViewer struct {
+	ID     uint
+	KlubID *uint
+	Role   string
+}
+
+func searchViewerFromContext(c *gin.Context) searchViewer {
+	viewer, ok := AuthUser(c)
+	if !ok {
+		return searchViewer{}
+	}
+	return searchViewer{ID: viewer.ID, KlubID: viewer.KlubID, Role: viewer.Role}
+}
+
+// excludeBlockedUsers izbacuje korisnike u blokadi sa viewer-om (bilo koji smer).
+func excludeBlockedUsers(q *gorm.DB, column string, viewer searchViewer) *gorm.DB {
+	if viewer.ID == 0 {
+		return q
+	}
+	return q.Where(column+" NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = ?) AND "+column+" NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = ?)", viewer.ID, viewer.ID)
+}
+
+func runSearchSource(db *gorm.DB, tip string, tokens []string, limit int, viewer searchViewer) ([]searchRow, error) {
+	doc := search.Documents[tip]
+	var rows []searchRow
+	switch tip {
+	case search.TipAkcija:
+		// Ista pravila kao lista akcija: aktivne javne svima, klupske (i završene) samo članovima kluba.
+		q := db.Table("akcije").
+			Where("is_cancelled = ? AND (u_istoriji_kluba IS NULL OR u_istoriji_kluba = ?) AND "+helpers.SQLAkcijaObjavljena, false, true)
+		if viewer.Role != "superadmin" {
+			if viewer.KlubID != nil {
+				q = q.Where("((is_completed = ? AND javna = ?) OR (klub_id = ? AND "+sqlClubOrganizedOnly+"))", false, true, *viewer.KlubID)
+			} else {
+				q = q.Where("is_completed = ? AND javna = ?", false, true)
+			}
+		}
+		q, rank := search.Match(db, q, doc, tokens)
+		err := q.Select("id, naziv AS naslov, TRIM(COALESCE(planina, '') || ' ' || COALESCE(vrh, '')) AS podnaslov, '' AS slug, COALESCE(slika_url, '') AS slika_url, " + rank + " AS rank").
+			Order("rank DESC, datum DESC").Limit(limit).Scan(&rows).Error
+		return rows, err
+	case search.TipVrh:
+		q, rank := search.Match(db, db.Table("peaks").Where("status = ?", "active"), doc, tokens)
+		err := q.Select("id, naziv_vrha AS naslov, COALESCE(planina, '') AS podnaslov, slug, '' AS slika_url, " + rank + " AS rank").
+			Order("rank DESC, visina_m DESC").Limit(limit).Scan(&rows).Error
+		return rows, err
+	case search.TipFerata:
+		q, rank := search.Match(db, db.Table("ferratas").Where("status = ?", "active"), doc, tokens)
+		err := q.Select("id, naziv AS naslov, COALESCE(grad_opstina, '') AS podnaslov, slug, '' AS slika_url, " + rank + " AS rank").
+			Order("rank DESC, naziv ASC").Limit(limit).Scan(&rows).Error
+		return rows, err
+	case search.TipHotel:
+		q, rank := search.Match(db, db.Table("hotels").Where("status = ?", "active"), doc, tokens)
+		err := q.Select("id, naziv AS naslov, COALESCE(adresa, '') AS podnaslov, slug, '' AS slika_url, " + rank + " AS rank").
+			Order("rank DESC, naziv ASC").Limit(limit).Scan(&rows).Error
+		return rows, err
+	case search.TipKlub:
+		q, rank := search.Match(db, publicClubQuery(db), doc, tokens)
+		err := q.Select("id, naziv AS naslov, COALESCE(sediste, '') AS podnaslov, '' AS slug, COALESCE(logo_url, '') AS slika_url, " + rank + " AS rank").
+			Order("rank DESC, naziv ASC").Limit(limit).Scan(&rows).Error
+		return rows, err
+	case search.TipKorisnik:
+		q := excludeBlockedUsers(db.Table("korisnici").Where("role <> ?", "deleted"), "id", viewer)
+		q, rank := search.Match(db, q, doc, tokens)
+		err := q.Select("id, CASE WHEN COALESCE(full_name, '') <> '' THEN full_name ELSE username END AS naslov, username AS podnaslov, username AS slug, COALESCE(avatar_url, '') AS slika_url, " + rank + " AS rank").
+			Order("rank DESC, username ASC").Limit(limit).Scan(&rows).Error
+		return rows, err
+	case search.TipVodic:
+		return searchGuides(db, tokens, limit, viewer)
+	}
+	return rows, nil
+}
+
+// searchGuides: odobreni vodiči po tekstu profila ili po imenu korisnika (dva indeksirana upita).
+func searchGuides(db *gorm.DB, tokens []string, limit int, viewer searchViewer) ([]searchRow, error) {
+	base := func() *gorm.DB {
+		q := db.Table("guide_profiles").
+			Joins("JOIN korisnici ON korisnici.id = guide_profiles.korisnik_id").
+			Where("guide_profiles.status = ? AND korisnici.role <> ?", models.GuideStatusApproved, "deleted")
+		return excludeBlockedUsers(q, "korisnici.id", viewer)
+	}
+	sel := "guide_profiles.id AS id, CASE WHEN COALESCE(korisnici.full_name, '') <> '' THEN korisnici.full_name ELSE korisnici.username END AS naslov, guide_profiles.naslov AS podnaslov, korisnici.username AS slug, COALESCE(korisnici.avatar_url, '') AS slika_url, "
+
+	profileDoc := search.Documents[search.TipVodic]
+	profileDoc.Expr = qualifyDocExpr(profileDoc.Expr, "guide_profiles")
+	q, rank := search.Match(db, base(), profileDoc, tokens)
+	var byProfile []searchRow
+	if err := q.Select(sel + rank + " AS rank").Order("rank DESC").Limit(limit).Scan(&byProfile).Error; err != nil {
+		return nil, err
+	}
+	userDoc := search.Documents[search.TipKorisnik]
+	userDoc.Expr = qualifyDocExpr(userDoc.Expr, "korisnici")
+	q, rank = search.Match(db, base(), userDoc, tokens)
+	var byName []searchRow
+	if err := q.Select(sel + rank + " AS rank").Order("rank DESC").Limit(limit).Scan(&byName).Error; err != nil {
+		return nil, err
+	}
+	seen := make(map[uint]int, len(byProfile)+len(byName))
+	out := make([]searchRow, 0, len(byProfile)+len(byName))
+	for _, r := range append(byName, byProfile...) {
+		if i, ok := seen[r.ID]; ok {
+			if r.Rank > out[i].Rank {
+				out[i].Rank = r.Rank
+			}
+			continue
+		}
+		seen[r.ID] = len(out)
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// qualifyDocExpr dodaje ime tabele kolonama u coalesce(...) izrazu (join sa korisnici).
+func qualifyDocExpr(expr, table string) string {
+	return strings.ReplaceAll(expr, "coalesce(", "coalesce("+table+".")
+}
+
+func parseSearchTipovi(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return search.Tipovi()
+	}
+	valid := make(map[string]struct{})
+	for _, t := range search.Tipovi() {
+		valid[t] = struct{}{}
+	}
+	out := make([]string, 0)
+	for _, t := range strings.Split(raw, ",") {
+		t = strings.TrimSpace(strings.ToLower(t))
+		if _, ok := valid[t]; ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func runSearch(c *gin.Context, defaultLimit, maxLimit int) ([]searchResult, bool) {
+	db := DB(c)
+	q := strings.TrimSpace(c.Query("q"))
+	tokens := search.Tokens(q)
+	if len([]rune(search.Fold(q))) < search.MinQueryLen || len(tokens) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upit mora imati bar 2 karaktera"})
+		return nil, false
+	}
+	tipovi := parseSearchTipovi(c.Query("tip"))
+	if len(tipovi) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nepoznat tip pretrage"})
+		return nil, false
+	}
+	limit := defaultLimit
+	if l := c.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 && n <= maxLimit {
+			limit = n
+		}
+	}
+	viewer := searchViewerFromContext(c)
+
+	results := make([]searchResult, 0)
+	for _, tip := range tipovi {
+		rows, err := runSearchSource(db, tip, tokens, limit, viewer)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri pretrazi"})
+			return nil, false
+		}
+		for _, r := range rows {
+			results = append(results, searchResult{
+				Tip:       tip,
+				ID:        r.ID,
+				Naslov:    r.Naslov,
+				Podnaslov: strings.TrimSpace(r.Podnaslov),
+				Slug:      r.Slug,
+				SlikaURL:  r.SlikaURL,
+				Rank:      r.Rank + search.TitleBoost(r.Naslov, tokens),
+			})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, true
+}
+
+// Search GET /api/search?q=&tip=akcija,vrh&limit= — rangirani rezultati svih tipova
+// (ćirilica = latinica, bez dijakritika; privatnost akcija i blokade poštovane).
+func Search(c *gin.Context) {
+	results, ok := runSearch(c, 20, 50)
+	if !ok {
+		return
+	}
+	poTipu := make(map[string]int)
+	for _, r := range results {
+		poTipu[r.Tip]++
+	}
+	c.JSON(http.StatusOK, gin.H{"rezultati": results, "poTipu": poTipu})
+}
+
+// SearchAutocomplete GET /api/search/autocomplete?q=&tip=vrh — kratki prefiks predlozi za forme.
+func SearchAutocomplete(c *gin.Context) {
+	results, ok := runSearch(c, 8, 20)
+	if !ok {
+		return
+	}
+	predlozi := make([]gin.H, 0, len(results))
+	for _, r := range results {
+		predlozi = append(predlozi, gin.H{"tip": r.Tip, "id": r.ID, "naslov": r.Naslov, "podnaslov": r.Podnaslov, "slug": r.Slug})
+	}
+	c.JSON(http.StatusOK, gin.H{"predlozi": predlozi})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	_ "beleg-app/backend/internal/search/searchtest"
+	"beleg-app/backend/internal/testdb"
+	"beleg-app/backend/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func testSearchDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "search")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.Korisnik{},
+		&models.Klubovi{},
+		&models.Block{},
+		&models.Akcija{},
+		&models.Peak{},
+		&models.Ferrata{},
+		&models.Hotel{},
+		&models.GuideProfile{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func callSearch(t *testing.T, db *gorm.DB, viewer *models.Korisnik, query string) []searchResult {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/search?"+query, nil)
+	c.Set("db", db)
+	if viewer != nil {
+		c.Set(middleware.ContextKeyKorisnik, *viewer)
+	}
+	Search(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Rezultati []searchResult `json:"rezultati"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Rezultati
+}
+
+func searchHas(results []searchResult, tip string, id uint) bool {
+	for _, r := range results {
+		if r.Tip == tip && r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSearch_CyrillicQueryAndPrivacyRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testSearchDB(t)
+	mojKlub := models.Klubovi{Naziv: "PSK Kopaonik"}
+	drugiKlub := models.Klubovi{Naziv: "PK Rtanj"}
+	db.Create(&mojKlub)
+	db.Create(&drugiKlub)
+	viewer := models.Korisnik{Username: "viewer", Role: "clan", KlubID: &mojKlub.ID}
+	blokirao := models.Korisnik{Username: "kopaonik_fan", Role: "clan"}
+	vidljiv := models.Korisnik{Username: "kopaonik_vodic", FullName: "Kopaonik Vodic", Role: "clan"}
+	obrisan := models.Korisnik{Username: "kopaonik_old", Role: "deleted"}
+	for _, u := range []*models.Korisnik{&viewer, &blokirao, &vidljiv, &obrisan} {
+		db.Create(u)
+	}
+	db.Create(&models.Block{BlockerID: blokirao.ID, BlockedID: viewer.ID})
+
+	peak := models.Peak{NazivVrha: "Pancicev vrh", Planina: "Kopaonik", Slug: "pancicev-vrh", Status: "active"}
+	db.Create(&peak)
+	javna := models.Akcija{Naziv: "Kopaonik zimi", Javna: true, KlubID: &drugiKlub.ID, Datum: time.Now(), UIstorijiKluba: true}
+	klupska := models.Akcija{Naziv: "Kopaonik klupska", KlubID: &mojKlub.ID, Datum: time.Now(), UIstorijiKluba: true}
+	tudja := models.Akcija{Naziv: "Kopaonik tudja", KlubID: &drugiKlub.ID, Datum: time.Now(), UIstorijiKluba: true}
+	cirilica := models.Akcija{Naziv: "КОПАОНИК Ђурђевдан", KlubID: &mojKlub.ID, Datum: time.Now(), UIstorijiKluba: true}
+	for _, a := range []*models.Akcija{&javna, &klupska, &tudja, &cirilica} {
+		db.Create(a)
+	}
+	db.Create(&models.GuideProfile{KorisnikID: vidljiv.ID, Status: models.GuideStatusApproved, Naslov: "Ture po Kopaoniku", Opis: "x"})
+
+	results := callSearch(t, db, &viewer, "q="+url.QueryEscape("Копаоник"))
+	if !searchHas(results, "vrh", peak.ID) || !searchHas(results, "klub", mojKlub.ID) {
+		t.Fatalf("cyrillic query should find peak and club, got %+v", results)
+	}
+	if !searchHas(results, "akcija", javna.ID) || !searchHas(results, "akcija", klupska.ID) || searchHas(results, "akcija", tudja.ID) ||
+		!searchHas(results, "akcija", cirilica.ID) {
+		t.Fatalf("expected public + own club actions only, got %+v", results)
+	}
+	if searchHas(results, "korisnik", blokirao.ID) || searchHas(results, "korisnik", obrisan.ID) || !searchHas(results, "korisnik", vidljiv.ID) {
+		t.Fatalf("blocked/deleted users must be hidden, got %+v", results)
+	}
+	if !searchHas(results, "vodic", 1) {
+		t.Fatalf("approved guide should be found, got %+v", results)
+	}
+
+	anon := callSearch(t, db, nil, "q=kopaonik&tip=akcija")
+	if len(anon) != 1 || anon[0].ID != javna.ID {
+		t.Fatalf("anonymous viewer sees only public actions, got %+v", anon)
+	}
+	if peaks := callSearch(t, db, nil, "q=panc&tip=vrh"); len(peaks) != 1 || peaks[0].Slug != "pancicev-vrh" {
+		t.Fatalf("prefix autocomplete on peaks failed, got %+v", peaks)
+	}
+}
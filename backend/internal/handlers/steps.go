@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"beleg-app/backend/internal/achievements"
 	"beleg-app/backend/internal/helpers"
 	"beleg-app/backend/internal/models"
 	"net/http"
@@ -230,6 +231,7 @@ func SyncDailySteps(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri sinhronizaciji"})
 		return
 	}
+	achievements.EvaluateBestEffort(db, []uint{user.ID}, achievements.EventStepsSynced)
 	c.JSON(http.StatusOK, gin.H{"steps": stored, "date": date.Format("2006-01-02")})
 }
 
@@ -275,6 +277,9 @@ func SyncDailyStepsBatch(c *gin.Context) {
 		}
 		synced++
 	}
+	if synced > 0 {
+		achievements.EvaluateBestEffort(db, []uint{user.ID}, achievements.EventStepsSynced)
+	}
 	c.JSON(http.StatusOK, gin.H{"synced": synced})
 }
 
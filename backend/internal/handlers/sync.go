@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/deltasync"
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	syncDefaultLimit = 200
+	syncMaxLimit     = 500
+)
+
+// syncSafetyLag — zapisi mlađi od ovoga čekaju sledeći poziv, da transakcija koja je upravo
+// upisala stariji updated_at ne bi ostala iza kursora.
+var syncSafetyLag = 2 * time.Second
+
+// syncRow je jedan zapis entiteta za delta sync (kreirano/izmenjeno se određuje po CreatedAt).
+type syncRow struct {
+	ID        uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Data      any
+}
+
+// syncScope — šta korisnik vidi (isto kao /akcije, /moje-prijave, /obavestenja, /follows, /zadaci).
+type syncScope struct {
+	KorisnikID uint
+	KlubID     uint
+	Full       bool
+}
+
+// syncKeyset vraća stranicu posle pozicije kursora, po (updated_at, id), do granice upTo.
+func syncKeyset(q *gorm.DB, pos deltasync.Position, upTo time.Time, limit int) *gorm.DB {
+	if !pos.T.IsZero() {
+		q = q.Where("(updated_at > ? OR (updated_at = ? AND id > ?))", pos.T, pos.T, pos.ID)
+	}
+	return q.Where("updated_at <= ?", upTo).Order("updated_at ASC, id ASC").Limit(limit + 1)
+}
+
+func syncAkcijeRows(db *gorm.DB, s syncScope, pos deltasync.Position, upTo time.Time, limit int) ([]syncRow, error) {
+	own := "((klub_id = ? AND " + sqlClubOrganizedOnly + ") OR vodic_id = ? OR id IN (" + helpers.SQLAkcijaCoGuideIDs + ") OR id IN (SELECT akcija_id FROM prijave WHERE korisnik_id = ?))"
+	// Neobjavljene akcije sinhronizuje samo autor/vodič; ostali ih dobijaju tek posle objave (updated_at se menja).
+	q := db.Preload("Klub").Where("("+helpers.SQLAkcijaObjavljena+" OR added_by_id = ? OR vodic_id = ?)", s.KorisnikID, s.KorisnikID)
+	if s.Full {
+		// Prva sinhronizacija ne vuče celu istoriju javnih akcija drugih klubova.
+		q = q.Where("((javna = ? AND is_completed = ?) OR "+own+")", true, false, s.KlubID, s.KorisnikID, s.KorisnikID, s.KorisnikID)
+	} else {
+		q = q.Where("(javna = ? OR "+own+")", true, s.KlubID, s.KorisnikID, s.KorisnikID, s.KorisnikID)
+	}
+	var list []models.Akcija
+	if err := syncKeyset(q, pos, upTo, limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]syncRow, 0, len(list))
+	for i := range list {
+		a := list[i]
+		if a.Klub != nil {
+			a.KlubNaziv = a.Klub.Naziv
+			a.KlubLogoURL = a.Klub.LogoURL
+		}
+		out = append(out, syncRow{ID: a.ID, CreatedAt: a.CreatedAt, UpdatedAt: a.UpdatedAt, Data: a})
+	}
+	return out, nil
+}
+
+func syncPrijaveRows(db *gorm.DB, s syncScope, pos deltasync.Position, upTo time.Time, limit int) ([]syncRow, error) {
+	var list []models.Prijava
+	if err := syncKeyset(db.Where("korisnik_id = ?", s.KorisnikID), pos, upTo, limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]syncRow, 0, len(list))
+	for _, p := range list {
+		out = append(out, syncRow{ID: p.ID, CreatedAt: p.PrijavljenAt, UpdatedAt: p.UpdatedAt, Data: gin.H{
+			"id":           p.ID,
+			"akcijaId":     p.AkcijaID,
+			"korisnikId":   p.KorisnikID,
+			"status":       p.Status,
+			"platio":       p.Platio,
+			"prijavljenAt": p.PrijavljenAt,
+			"updatedAt":    p.UpdatedAt,
+		}})
+	}
+	return out, nil
+}
+
+func syncSignupZahteviRows(db *gorm.DB, s syncScope, pos deltasync.Position, upTo time.Time, limit int) ([]syncRow, error) {
+	var list []models.ActionSignupRequest
+	if err := syncKeyset(db.Where("requester_id = ?", s.KorisnikID), pos, upTo, limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]syncRow, 0, len(list))
+	for _, r := range list {
+		out = append(out, syncRow{ID: r.ID, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt, Data: r})
+	}
+	return out, nil
+}
+
+func syncObavestenjaRows(db *gorm.DB, s syncScope, pos deltasync.Position, upTo time.Time, limit int) ([]syncRow, error) {
+	var list []models.Obavestenje
+	if err := syncKeyset(db.Where("user_id = ?", s.KorisnikID), pos, upTo, limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]syncRow, 0, len(list))
+	for _, n := range list {
+		out = append(out, syncRow{ID: n.ID, CreatedAt: n.CreatedAt, UpdatedAt: n.UpdatedAt, Data: n})
+	}
+	return out, nil
+}
+
+func syncPracenjaRows(db *gorm.DB, s syncScope, pos deltasync.Position, upTo time.Time, limit int) ([]syncRow, error) {
+	var list []models.Follow
+	if err := syncKeyset(db.Where("(requester_id = ? OR target_id = ?)", s.KorisnikID, s.KorisnikID), pos, upTo, limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]syncRow, 0, len(list))
+	for _, f := range list {
+		out = append(out, syncRow{ID: f.ID, CreatedAt: f.CreatedAt, UpdatedAt: f.UpdatedAt, Data: f})
+	}
+	return out, nil
+}
+
+func syncZadaciRows(db *gorm.DB, s syncScope, pos deltasync.Position, upTo time.Time, limit int) ([]syncRow, error) {
+	if s.KlubID == 0 {
+		return nil, nil
+	}
+	var list []models.Zadatak
+	if err := syncKeyset(db.Where("klub_id = ?", s.KlubID).Preload("ZadatakKorisnici.Korisnik"), pos, upTo, limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]syncRow, 0, len(list))
+	for _, z := range list {
+		out = append(out, syncRow{ID: z.ID, CreatedAt: z.CreatedAt, UpdatedAt: z.UpdatedAt, Data: buildZadatakResponse(z)})
+	}
+	return out, nil
+}
+
+var syncLoaders = map[string]func(*gorm.DB, syncScope, deltasync.Position, time.Time, int) ([]syncRow, error){
+	deltasync.EntitetAkcija:       syncAkcijeRows,
+	deltasync.EntitetPrijava:      syncPrijaveRows,
+	deltasync.EntitetSignupZahtev: syncSignupZahteviRows,
+	deltasync.EntitetObavestenje:  syncObavestenjaRows,
+	deltasync.EntitetPracenje:     syncPracenjaRows,
+	deltasync.EntitetZadatak:      syncZadaciRows,
+}
+
+// syncTombstonesQuery — tombstone-ovi namenjeni korisniku, njegovom klubu ili svima. Za akcije važi isto
+// pravilo kao u syncAkcijeRows: objavljenu javnu vide svi, ostale samo klub (učesnici imaju svoj tombstone).
+func syncTombstonesQuery(db *gorm.DB, s syncScope) *gorm.DB {
+	return db.Model(&models.SyncTombstone{}).
+		Where("(korisnik_id = ? OR (korisnik_id IS NULL AND (javna = ? OR klub_id = ? OR (javna IS NULL AND klub_id IS NULL))))",
+			s.KorisnikID, true, s.KlubID)
+}
+
+// recordClubLeftTx: posle izlaska iz kluba korisnik više ne vidi zadatke kluba ni njegove nejavne akcije
+// na kojima nije vodič, ko-vodič ili prijavljen — /api/sync mu ih šalje kao obrisane.
+func recordClubLeftTx(tx *gorm.DB, korisnikID, klubID uint) error {
+	var zadatakIDs []uint
+	if err := tx.Model(&models.Zadatak{}).Where("klub_id = ?", klubID).Pluck("id", &zadatakIDs).Error; err != nil {
+		return err
+	}
+	if err := deltasync.RecordHidden(tx, deltasync.EntitetZadatak, zadatakIDs, []uint{korisnikID}); err != nil {
+		return err
+	}
+	var akcijaIDs []uint
+	if err := tx.Model(&models.Akcija{}).
+		Where("klub_id = ? AND javna = ? AND vodic_id <> ?", klubID, false, korisnikID).
+		Where("id NOT IN ("+helpers.SQLAkcijaCoGuideIDs+") AND id NOT IN (SELECT akcija_id FROM prijave WHERE korisnik_id = ?)", korisnikID, korisnikID).
+		Pluck("id", &akcijaIDs).Error; err != nil {
+		return err
+	}
+	return deltasync.RecordHidden(tx, deltasync.EntitetAkcija, akcijaIDs, []uint{korisnikID})
+}
+
+// GetSync GET /api/sync?since=<kursor>&limit=200
+// Bez since: puna sinhronizacija (sve u kreirano). Sa since: kreirano/izmenjeno posle kursora i obrisani ID-jevi.
+// Kada je hasMore=true, klijent odmah zove ponovo sa novim kursorom; prekinut niz se nastavlja od poslednjeg
+// primljenog kursora. 410 kada je kursor istekao — klijent radi punu sinhronizaciju. Posle promene kluba
+// odgovor je pun, ali nosi i tombstone-ove od starog kursora (ono što korisnik više ne vidi).
+func GetSync(c *gin.Context) {
+	korisnik, ok := AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Niste ulogovani"})
+		return
+	}
+	db := DB(c)
+	klubID, ok := helpers.GetEffectiveClubID(c, db)
+	if !ok {
+		klubID = 0
+	}
+	limit := syncDefaultLimit
+	if l := c.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = min(n, syncMaxLimit)
+		}
+	}
+
+	now := time.Now().UTC()
+	since := strings.TrimSpace(c.Query("since"))
+	cursor, err := deltasync.DecodeCursor(since, korisnik.ID, klubID, now)
+	if errors.Is(err, deltasync.ErrCursorExpired) {
+		c.JSON(http.StatusGone, gin.H{"error": "Kursor sinhronizacije više ne važi, potrebna je puna sinhronizacija."})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nevažeći kursor sinhronizacije"})
+		return
+	}
+	scope := syncScope{KorisnikID: korisnik.ID, KlubID: klubID, Full: cursor.Inicijalna}
+	upTo := now.Add(-syncSafetyLag)
+
+	kreirano := make(gin.H, len(syncLoaders))
+	izmenjeno := make(gin.H, len(syncLoaders))
+	obrisano := make(gin.H, len(syncLoaders))
+	hasMore := false
+
+	// Tombstone-ovi se čitaju pre podataka: brisanje između dva čitanja stiže u sledećem pozivu, a ne gubi se.
+	// Nov klijent nema šta da briše: kursor samo preskače postojeće tombstone-ove.
+	var tombstones []models.SyncTombstone
+	if since == "" {
+		var maxID *uint
+		if err := syncTombstonesQuery(db, scope).Select("MAX(id)").Scan(&maxID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri sinhronizaciji"})
+			return
+		}
+		if maxID != nil {
+			cursor.Tombstone = *maxID
+		}
+	} else {
+		if err := syncTombstonesQuery(db, scope).
+			Where("id > ? AND obrisano_at <= ?", cursor.Tombstone, upTo).
+			Order("id ASC").Limit(limit + 1).Find(&tombstones).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri sinhronizaciji"})
+			return
+		}
+		if len(tombstones) > limit {
+			tombstones = tombstones[:limit]
+			hasMore = true
+		}
+	}
+	for _, e := range deltasync.Entiteti() {
+		obrisano[e] = []uint{}
+	}
+	for _, t := range tombstones {
+		obrisano[t.Entitet] = append(obrisano[t.Entitet].([]uint), t.EntitetID)
+		cursor.Tombstone = t.ID
+	}
+
+	for _, e := range deltasync.Entiteti() {
+		pos := cursor.Pozicije[e]
+		rows, err := syncLoaders[e](db, scope, pos, upTo, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri sinhronizaciji"})
+			return
+		}
+		if len(rows) > limit {
+			rows = rows[:limit]
+			hasMore = true
+		}
+		nova := make([]any, 0)
+		izmenjena := make([]any, 0)
+		for _, r := range rows {
+			if scope.Full || r.CreatedAt.After(pos.T) {
+				nova = append(nova, r.Data)
+			} else {
+				izmenjena = append(izmenjena, r.Data)
+			}
+		}
+		if len(rows) > 0 {
+			last := rows[len(rows)-1]
+			cursor.Pozicije[e] = deltasync.Position{T: last.UpdatedAt, ID: last.ID}
+		} else if _, ok := cursor.Pozicije[e]; !ok {
+			cursor.Pozicije[e] = deltasync.Position{T: upTo}
+		}
+		kreirano[e] = nova
+		izmenjeno[e] = izmenjena
+	}
+	cursor.Izdat = now
+	cursor.Inicijalna = cursor.Inicijalna && hasMore
+
+	c.JSON(http.StatusOK, gin.H{
+		"kursor":    cursor.Encode(),
+		"puna":      scope.Full,
+		"hasMore":   hasMore,
+		"kreirano":  kreirano,
+		"izmenjeno": izmenjeno,
+		"obrisano":  obrisano,
+	})
+}
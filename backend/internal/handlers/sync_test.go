@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/deltasync"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+	"beleg-app/backend/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type syncResponse struct {
+	Kursor    string                      `json:"kursor"`
+	Puna      bool                        `json:"puna"`
+	HasMore   bool                        `json:"hasMore"`
+	Kreirano  map[string][]map[string]any `json:"kreirano"`
+	Izmenjeno map[string][]map[string]any `json:"izmenjeno"`
+	Obrisano  map[string][]uint           `json:"obrisano"`
+}
+
+func callSync(t *testing.T, db *gorm.DB, user models.Korisnik, since string, limit string) (int, syncResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	q := url.Values{}
+	if since != "" {
+		q.Set("since", since)
+	}
+	if limit != "" {
+		q.Set("limit", limit)
+	}
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/sync?"+q.Encode(), nil)
+	c.Set("db", db)
+	c.Set(middleware.ContextKeyKorisnik, user)
+	c.Set("username", user.Username)
+	c.Set("role", user.Role)
+	if user.KlubID != nil {
+		c.Set("klubId", *user.KlubID)
+	}
+	GetSync(c)
+	var resp syncResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+func syncIDs(rows []map[string]any) []uint {
+	out := make([]uint, 0, len(rows))
+	for _, r := range rows {
+		if id, ok := r["id"].(float64); ok {
+			out = append(out, uint(id))
+		}
+	}
+	return out
+}
+
+func TestGetSync_FullThenDelta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prevLag := syncSafetyLag
+	syncSafetyLag = 0
+	t.Cleanup(func() { syncSafetyLag = prevLag })
+
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "sync")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Klubovi{}, &models.Korisnik{}, &models.Akcija{}, &models.Prijava{}, &models.ActionSignupRequest{},
+		&models.Obavestenje{}, &models.Follow{}, &models.Zadatak{}, &models.ZadatakKorisnik{}, &models.SyncTombstone{}, &models.AkcijaVodic{}, &models.AkcijaForma{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := deltasync.RegisterTombstoneCallbacks(db); err != nil {
+		t.Fatal(err)
+	}
+	klubID, drugiKlub := uint(1), uint(2)
+	me := models.Korisnik{Username: "marko", Role: "clan", KlubID: &klubID}
+	other := models.Korisnik{Username: "ana", Role: "clan", KlubID: &drugiKlub}
+	db.Create(&me)
+	db.Create(&other)
+
+	javna := models.Akcija{Naziv: "Rtanj", Javna: true, KlubID: &drugiKlub}
+	klupska := models.Akcija{Naziv: "Suva planina", KlubID: &klubID}
+	tudja := models.Akcija{Naziv: "Interna akcija drugog kluba", KlubID: &drugiKlub}
+	db.Create(&javna)
+	db.Create(&klupska)
+	db.Create(&tudja)
+	prijava := models.Prijava{AkcijaID: klupska.ID, KorisnikID: me.ID, Status: "prijavljen"}
+	db.Create(&prijava)
+	db.Create(&models.Prijava{AkcijaID: javna.ID, KorisnikID: other.ID, Status: "prijavljen"})
+	n1 := models.Obavestenje{UserID: me.ID, Type: models.ObavestenjeTipAkcija, Title: "Nova akcija"}
+	n2 := models.Obavestenje{UserID: me.ID, Type: models.ObavestenjeTipAkcija, Title: "Još jedna akcija"}
+	db.Create(&n1)
+	db.Create(&n2)
+	db.Create(&models.Follow{RequesterID: other.ID, TargetID: me.ID, Status: models.FollowStatusPending})
+	db.Create(&models.Zadatak{Naziv: "Markacija", KlubID: &klubID, Status: models.ZadatakStatusAktivni})
+	db.Create(&models.Zadatak{Naziv: "Tuđi zadatak", KlubID: &drugiKlub, Status: models.ZadatakStatusAktivni})
+
+	code, full := callSync(t, db, me, "", "")
+	if code != http.StatusOK || !full.Puna || full.HasMore {
+		t.Fatalf("full sync: status %d %+v", code, full)
+	}
+	if got := syncIDs(full.Kreirano[deltasync.EntitetAkcija]); len(got) != 2 || got[0] != javna.ID || got[1] != klupska.ID {
+		t.Fatalf("expected public and own-club actions only, got %v", got)
+	}
+	if len(full.Kreirano[deltasync.EntitetPrijava]) != 1 || len(full.Kreirano[deltasync.EntitetObavestenje]) != 2 ||
+		len(full.Kreirano[deltasync.EntitetPracenje]) != 1 || len(full.Kreirano[deltasync.EntitetZadatak]) != 1 {
+		t.Fatalf("unexpected full sync %+v", full.Kreirano)
+	}
+
+	code, empty := callSync(t, db, me, full.Kursor, "")
+	if code != http.StatusOK || empty.Puna || len(empty.Kreirano[deltasync.EntitetAkcija]) != 0 || len(empty.Izmenjeno[deltasync.EntitetObavestenje]) != 0 {
+		t.Fatalf("nothing changed, expected empty delta: %d %+v", code, empty)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	now := time.Now()
+	db.Model(&n1).Update("read_at", &now)
+	db.Delete(&models.Obavestenje{}, n2.ID)
+	db.Model(&prijava).Update("platio", true)
+	db.Where("requester_id = ? AND target_id = ?", other.ID, me.ID).Delete(&models.Follow{})
+	n3 := models.Obavestenje{UserID: me.ID, Type: models.ObavestenjeTipZadatak, Title: "Novi zadatak"}
+	db.Create(&n3)
+	db.Create(&models.Obavestenje{UserID: other.ID, Type: models.ObavestenjeTipZadatak, Title: "Nije za mene"})
+
+	code, delta := callSync(t, db, me, empty.Kursor, "")
+	if code != http.StatusOK {
+		t.Fatalf("delta status %d", code)
+	}
+	if got := syncIDs(delta.Izmenjeno[deltasync.EntitetObavestenje]); len(got) != 1 || got[0] != n1.ID {
+		t.Fatalf("expected read notification as updated, got %v", got)
+	}
+	if got := syncIDs(delta.Kreirano[deltasync.EntitetObavestenje]); len(got) != 1 || got[0] != n3.ID {
+		t.Fatalf("expected only my new notification as created, got %v", got)
+	}
+	if got := syncIDs(delta.Izmenjeno[deltasync.EntitetPrijava]); len(got) != 1 || got[0] != prijava.ID {
+		t.Fatalf("expected paid prijava as updated, got %v", got)
+	}
+	if got := delta.Obrisano[deltasync.EntitetObavestenje]; len(got) != 1 || got[0] != n2.ID {
+		t.Fatalf("expected deleted notification tombstone, got %v", got)
+	}
+	if got := delta.Obrisano[deltasync.EntitetPracenje]; len(got) != 1 {
+		t.Fatalf("expected deleted follow tombstone, got %v", got)
+	}
+
+	code, again := callSync(t, db, me, delta.Kursor, "")
+	if code != http.StatusOK || len(again.Obrisano[deltasync.EntitetObavestenje]) != 0 || len(again.Izmenjeno[deltasync.EntitetObavestenje]) != 0 {
+		t.Fatalf("resuming from the new cursor must not repeat changes: %+v", again)
+	}
+	if code, _ := callSync(t, db, other, delta.Kursor, ""); code != http.StatusBadRequest {
+		t.Fatalf("cursor of another user must be rejected, got %d", code)
+	}
+}
+
+func TestGetSync_PagesResumeFromCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prevLag := syncSafetyLag
+	syncSafetyLag = 0
+	t.Cleanup(func() { syncSafetyLag = prevLag })
+
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "sync")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Korisnik{}, &models.Akcija{}, &models.Prijava{}, &models.ActionSignupRequest{},
+		&models.Obavestenje{}, &models.Follow{}, &models.Zadatak{}, &models.ZadatakKorisnik{}, &models.SyncTombstone{}, &models.AkcijaVodic{}, &models.AkcijaForma{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	me := models.Korisnik{Username: "marko", Role: "clan"}
+	db.Create(&me)
+	for i := 0; i < 5; i++ {
+		db.Create(&models.Obavestenje{UserID: me.ID, Type: models.ObavestenjeTipBroadcast, Title: "Obaveštenje"})
+	}
+
+	seen := map[uint]bool{}
+	since := ""
+	for page := 0; page < 5; page++ {
+		code, resp := callSync(t, db, me, since, "2")
+		if code != http.StatusOK {
+			t.Fatalf("page %d status %d", page, code)
+		}
+		for _, id := range syncIDs(resp.Kreirano[deltasync.EntitetObavestenje]) {
+			if seen[id] {
+				t.Fatalf("notification %d delivered twice", id)
+			}
+			seen[id] = true
+		}
+		since = resp.Kursor
+		if !resp.HasMore {
+			break
+		}
+	}
+	if len(seen) != 5 {
+		t.Fatalf("expected all 5 notifications across pages, got %d", len(seen))
+	}
+}
+
+func TestGetSync_LeavingClubSendsClubDataAsDeleted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prevLag := syncSafetyLag
+	syncSafetyLag = 0
+	t.Cleanup(func() { syncSafetyLag = prevLag })
+
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "sync")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Klubovi{}, &models.Korisnik{}, &models.Akcija{}, &models.Prijava{}, &models.ActionSignupRequest{},
+		&models.Obavestenje{}, &models.Follow{}, &models.Zadatak{}, &models.ZadatakKorisnik{}, &models.SyncTombstone{}, &models.AkcijaVodic{}, &models.AkcijaForma{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	klubID := uint(1)
+	me := models.Korisnik{Username: "marko", Role: "clan", KlubID: &klubID}
+	db.Create(&me)
+	klupska := models.Akcija{Naziv: "Suva planina", KlubID: &klubID}
+	saPrijavom := models.Akcija{Naziv: "Stara planina", KlubID: &klubID}
+	db.Create(&klupska)
+	db.Create(&saPrijavom)
+	db.Create(&models.Prijava{AkcijaID: saPrijavom.ID, KorisnikID: me.ID, Status: "prijavljen"})
+	zadatak := models.Zadatak{Naziv: "Markacija", KlubID: &klubID, Status: models.ZadatakStatusAktivni}
+	db.Create(&zadatak)
+
+	code, full := callSync(t, db, me, "", "")
+	if code != http.StatusOK || len(full.Kreirano[deltasync.EntitetAkcija]) != 2 || len(full.Kreirano[deltasync.EntitetZadatak]) != 1 {
+		t.Fatalf("full sync: status %d %+v", code, full.Kreirano)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/club-membership/leave", nil)
+	c.Set("db", db)
+	c.Set("username", me.Username)
+	LeaveCurrentClub(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("leave club: %d %s", w.Code, w.Body.String())
+	}
+	me.KlubID = nil
+
+	code, delta := callSync(t, db, me, full.Kursor, "")
+	if code != http.StatusOK {
+		t.Fatalf("club change must not drop pending deletions with 410, got %d", code)
+	}
+	if got := delta.Obrisano[deltasync.EntitetZadatak]; len(got) != 1 || got[0] != zadatak.ID {
+		t.Fatalf("club task must arrive as deleted, got %v", got)
+	}
+	if got := delta.Obrisano[deltasync.EntitetAkcija]; len(got) != 1 || got[0] != klupska.ID {
+		t.Fatalf("club-only action must arrive as deleted (action with own signup stays), got %v", got)
+	}
+	if got := syncIDs(delta.Kreirano[deltasync.EntitetAkcija]); len(got) != 1 || got[0] != saPrijavom.ID {
+		t.Fatalf("action with own signup must stay visible, got %v", got)
+	}
+}
+
+func TestGetSync_ActionTombstonesFollowVisibility(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prevLag := syncSafetyLag
+	syncSafetyLag = 0
+	t.Cleanup(func() { syncSafetyLag = prevLag })
+
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "sync")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Klubovi{}, &models.Korisnik{}, &models.Akcija{}, &models.Prijava{}, &models.ActionSignupRequest{},
+		&models.Obavestenje{}, &models.Follow{}, &models.Zadatak{}, &models.ZadatakKorisnik{}, &models.SyncTombstone{}, &models.AkcijaVodic{}, &models.AkcijaForma{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := deltasync.RegisterTombstoneCallbacks(db); err != nil {
+		t.Fatal(err)
+	}
+	klubID, drugiKlub := uint(1), uint(2)
+	me := models.Korisnik{Username: "marko", Role: "clan", KlubID: &klubID}
+	other := models.Korisnik{Username: "ana", Role: "clan", KlubID: &drugiKlub}
+	db.Create(&me)
+	db.Create(&other)
+
+	javna := models.Akcija{Naziv: "Rtanj", Javna: true, KlubID: &drugiKlub}
+	klupska := models.Akcija{Naziv: "Suva planina", KlubID: &klubID}
+	tudja := models.Akcija{Naziv: "Interna akcija drugog kluba", KlubID: &drugiKlub}
+	gost := models.Akcija{Naziv: "Interna akcija sa gostom", KlubID: &drugiKlub}
+	for _, a := range []*models.Akcija{&javna, &klupska, &tudja, &gost} {
+		db.Create(a)
+	}
+	db.Create(&models.Prijava{AkcijaID: gost.ID, KorisnikID: me.ID, Status: "prijavljen"})
+
+	_, mine := callSync(t, db, me, "", "")
+	_, theirs := callSync(t, db, other, "", "")
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := recordAkcijaDeletedForParticipantsTx(tx, &gost); err != nil {
+			return err
+		}
+		if err := tx.Where("akcija_id = ?", gost.ID).Delete(&models.Prijava{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Akcija{}, []uint{javna.ID, klupska.ID, tudja.ID, gost.ID}).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, delta := callSync(t, db, me, mine.Kursor, "")
+	if got := delta.Obrisano[deltasync.EntitetAkcija]; len(got) != 3 || slices.Contains(got, tudja.ID) ||
+		!slices.Contains(got, javna.ID) || !slices.Contains(got, klupska.ID) || !slices.Contains(got, gost.ID) {
+		t.Fatalf("deleted private action of another club must not reach me (guest signup excepted), got %v", got)
+	}
+	_, delta = callSync(t, db, other, theirs.Kursor, "")
+	if got := delta.Obrisano[deltasync.EntitetAkcija]; slices.Contains(got, klupska.ID) || !slices.Contains(got, tudja.ID) {
+		t.Fatalf("club sees its own deleted actions only, got %v", got)
+	}
+}
@@ -1,9 +1,15 @@
 package handlers
 
 import (
+	"beleg-app/backend/internal/achievements"
+	"beleg-app/backend/internal/helpers"
 	"beleg-app/backend/internal/models"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,10 +22,12 @@ type activityPointInput struct {
 	Altitude   *float64 `json:"altitude,omitempty"`
 	Accuracy   *float64 `json:"accuracy,omitempty"`
 	RecordedAt string   `json:"recordedAt"`
+	Seq        *int     `json:"seq,omitempty"` // redni broj tačke na uređaju (0, 1, 2…); ponovljena tačka se preskače
 }
 
 type appendPointsBody struct {
-	Points []activityPointInput `json:"points"`
+	BatchID string               `json:"batchId,omitempty"` // ID zahteva na uređaju; ponovljen batch se ne upisuje ponovo
+	Points  []activityPointInput `json:"points"`
 }
 
 type finishActivityBody struct {
@@ -30,6 +38,9 @@ type finishActivityBody struct {
 	RoutePolyline  string  `json:"routePolyline"`
 	EndLat         float64 `json:"endLat"`
 	EndLng         float64 `json:"endLng"`
+	// Samo za offline upload: stvarni početak i kraj snimanja na uređaju (RFC3339).
+	StartedAt string `json:"startedAt"`
+	EndedAt   string `json:"endedAt"`
 }
 
 func findOwnedActivity(c *gin.Context, db *gorm.DB, userID uint, activityID uint) (*models.TrackedActivity, bool) {
@@ -78,7 +89,8 @@ func StartTrackedActivity(c *gin.Context) {
 	})
 }
 
-// AppendTrackedActivityPoints adds GPS points to an active activity.
+// AppendTrackedActivityPoints adds GPS points to an active or uploading activity.
+// Idempotentno: tačke sa seq se dedupliciraju po (activity_id, seq), a ponovljen batchId se ne upisuje ponovo.
 func AppendTrackedActivityPoints(c *gin.Context) {
 	db := DB(c)
 	user, ok := currentUser(c, db)
@@ -94,7 +106,7 @@ func AppendTrackedActivityPoints(c *gin.Context) {
 	if !okAct {
 		return
 	}
-	if activity.Status != models.TrackedActivityStatusActive {
+	if !activityAcceptsPoints(activity.Status) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Aktivnost nije aktivna"})
 		return
 	}
@@ -103,45 +115,35 @@ func AppendTrackedActivityPoints(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Nema tačaka za dodavanje"})
 		return
 	}
-	if len(body.Points) > 200 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Previše tačaka u jednom zahtevu (max 200)"})
+	if len(body.Points) > activityPointsMaxPerRequest {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Previše tačaka u jednom zahtevu (max %d)", activityPointsMaxPerRequest)})
 		return
 	}
-
-	var maxSeq int
-	_ = db.Model(&models.TrackedActivityPoint{}).
-		Where("activity_id = ?", activity.ID).
-		Select("COALESCE(MAX(seq), -1)").Scan(&maxSeq)
-
-	points := make([]models.TrackedActivityPoint, 0, len(body.Points))
-	for i, p := range body.Points {
-		recordedAt := time.Now().UTC()
-		if p.RecordedAt != "" {
-			if t, err := time.Parse(time.RFC3339, p.RecordedAt); err == nil {
-				recordedAt = t
-			}
-		}
-		points = append(points, models.TrackedActivityPoint{
-			ActivityID: activity.ID,
-			Seq:        maxSeq + 1 + i,
-			Lat:        p.Lat,
-			Lng:        p.Lng,
-			Altitude:   p.Altitude,
-			Accuracy:   p.Accuracy,
-			RecordedAt: recordedAt,
-		})
+	body.BatchID = strings.TrimSpace(body.BatchID)
+	if len(body.BatchID) > activityBatchIDMaxLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "batchId može imati najviše 64 znaka"})
+		return
 	}
-	if err := db.Create(&points).Error; err != nil {
+
+	res, err := ingestActivityPoints(db, activity.ID, body)
+	switch {
+	case errors.Is(err, errActivityPointsMixedSeq):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sve tačke u zahtevu moraju imati seq, ili nijedna"})
+		return
+	case errors.Is(err, errActivityPointsSeqMode):
+		c.JSON(http.StatusConflict, gin.H{"error": "Aktivnost je započeta drugim načinom numerisanja tačaka (seq) — nastavite istim"})
+		return
+	case errors.Is(err, errActivityPointsBadSeq):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravan seq tačke"})
+		return
+	case errors.Is(err, errActivityPointsTooMany):
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Aktivnost može imati najviše %d tačaka", activityPointsMaxPerActivity)})
+		return
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čuvanju tačaka"})
 		return
 	}
-	first := body.Points[0]
-	if activity.StartLat == nil {
-		activity.StartLat = &first.Lat
-		activity.StartLng = &first.Lng
-		_ = db.Save(activity).Error
-	}
-	c.JSON(http.StatusOK, gin.H{"added": len(points)})
+	c.JSON(http.StatusOK, res)
 }
 
 // FinishTrackedActivity completes an active session.
@@ -160,7 +162,7 @@ func FinishTrackedActivity(c *gin.Context) {
 	if !okAct {
 		return
 	}
-	if activity.Status != models.TrackedActivityStatusActive {
+	if !activityAcceptsPoints(activity.Status) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Aktivnost nije aktivna"})
 		return
 	}
@@ -170,22 +172,55 @@ func FinishTrackedActivity(c *gin.Context) {
 		return
 	}
 	now := time.Now().UTC()
+	endedAt := now
+	if activity.Status == models.TrackedActivityStatusUploading {
+		if t, err := time.Parse(time.RFC3339, body.StartedAt); err == nil {
+			activity.StartedAt = t.UTC()
+		}
+		if t, err := time.Parse(time.RFC3339, body.EndedAt); err == nil && !t.Before(activity.StartedAt) && !t.After(now) {
+			endedAt = t.UTC()
+		}
+	}
 	activity.Status = models.TrackedActivityStatusCompleted
-	activity.EndedAt = &now
+	activity.EndedAt = &endedAt
 	activity.DurationSec = body.DurationSec
 	activity.DistanceM = body.DistanceM
 	activity.ElevationGainM = body.ElevationGainM
 	activity.Steps = body.Steps
 	activity.RoutePolyline = body.RoutePolyline
+	points, err := loadActivityRoutePoints(db, activity.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju tačaka"})
+		return
+	}
+	hasRoute := applyActivityRoute(activity, points)
 	if body.EndLat != 0 || body.EndLng != 0 {
 		activity.EndLat = &body.EndLat
 		activity.EndLng = &body.EndLng
 	}
+	activity.FlagReason = helpers.TrackedActivityFlagReason(activity)
+	activity.Flagged = activity.FlagReason != ""
 	if err := db.Save(activity).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri završetku aktivnosti"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"activity": activity})
+	// Best-effort: neuspeh prepoznavanja vrhova ne poništava završenu sesiju.
+	ascents, err := helpers.RecordTrackedActivityPeakAscentsTx(db, activity)
+	if err != nil {
+		log.Printf("tracked activity %d: prepoznavanje vrhova: %v", activity.ID, err)
+	}
+	if ascents == nil {
+		ascents = []models.PeakAscent{}
+	}
+	achievements.EvaluateBestEffort(db, []uint{user.ID}, achievements.EventActivityFinished)
+	if hasRoute {
+		activityID := activity.ID
+		runActivityRouteBackground(func() {
+			correctActivityAltitudes(db, activityID)
+			generateActivityThumbnail(db, activityID)
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"activity": activity, "peakAscents": ascents})
 }
 
 // DiscardTrackedActivity cancels an active session.
@@ -204,7 +239,7 @@ func DiscardTrackedActivity(c *gin.Context) {
 	if !okAct {
 		return
 	}
-	if activity.Status != models.TrackedActivityStatusActive {
+	if !activityAcceptsPoints(activity.Status) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Aktivnost nije aktivna"})
 		return
 	}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// activityPointsMaxPerRequest — jedan deo uploada; offline aktivnost od nekoliko hiljada tačaka ide u više delova.
+	activityPointsMaxPerRequest = 1000
+	activityBatchIDMaxLen       = 64
+)
+
+// activityPointsMaxPerActivity — gornja granica tačaka jedne aktivnosti (≈14 h na 1 tačku u sekundi); testovi je smanjuju.
+var activityPointsMaxPerActivity = 50000
+
+var (
+	errActivityPointsMixedSeq = errors.New("tracked activity: mixed client and server seq")
+	errActivityPointsBadSeq   = errors.New("tracked activity: invalid seq")
+	errActivityPointsTooMany  = errors.New("tracked activity: too many points")
+	errActivityPointsSeqMode  = errors.New("tracked activity: seq mode differs from earlier batches")
+)
+
+// activityAcceptsPoints — tačke se primaju tokom praćenja i tokom offline uploada.
+func activityAcceptsPoints(status string) bool {
+	return status == models.TrackedActivityStatusActive || status == models.TrackedActivityStatusUploading
+}
+
+// pointsIngestResult je odgovor na slanje tačaka; maxSeq/total klijent koristi da nastavi prekinut upload.
+type pointsIngestResult struct {
+	Added      int   `json:"added"`
+	Duplicates int   `json:"duplicates"`
+	Replayed   bool  `json:"replayed,omitempty"`
+	MaxSeq     int   `json:"maxSeq"`
+	Total      int64 `json:"total"`
+}
+
+func parsePointRecordedAt(raw string) time.Time {
+	if raw != "" {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t
+		}
+	}
+	return time.Now().UTC()
+}
+
+// ingestActivityPoints upisuje tačke idempotentno:
+//   - sa seq od klijenta: (activity_id, seq) je jedinstven, već primljene tačke se preskaču, redosled batch-eva nije bitan;
+//   - bez seq (stariji klijenti): server dodeljuje MAX(seq)+1 pod lock-om aktivnosti, a batchId sprečava dupli upis pri ponovljenom zahtevu.
+//
+// Način se utvrđuje prvim batch-om (PointSeqMode); batch drugog načina bi pomešao seq-ove i vraća errActivityPointsSeqMode.
+// U limit tačaka aktivnosti ulaze samo nove tačke, pa ponovljen batch pri punoj aktivnosti ostaje bez efekta.
+func ingestActivityPoints(db *gorm.DB, activityID uint, body appendPointsBody) (pointsIngestResult, error) {
+	var res pointsIngestResult
+	withSeq := 0
+	for _, p := range body.Points {
+		if p.Seq != nil {
+			if *p.Seq < 0 || *p.Seq >= activityPointsMaxPerActivity*10 {
+				return res, errActivityPointsBadSeq
+			}
+			withSeq++
+		}
+	}
+	if withSeq != 0 && withSeq != len(body.Points) {
+		return res, errActivityPointsMixedSeq
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var locked models.TrackedActivity
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, activityID).Error; err != nil {
+			return err
+		}
+		if body.BatchID != "" {
+			var batches []models.TrackedActivityPointBatch
+			if err := tx.Where("activity_id = ? AND batch_id = ?", activityID, body.BatchID).Limit(1).Find(&batches).Error; err != nil {
+				return err
+			}
+			if len(batches) == 1 {
+				res.Replayed = true
+				res.Duplicates = len(body.Points)
+				return nil
+			}
+		}
+
+		mode := models.TrackedActivitySeqServer
+		if withSeq > 0 {
+			mode = models.TrackedActivitySeqClient
+		}
+		if len(body.Points) > 0 {
+			if locked.PointSeqMode != "" && locked.PointSeqMode != mode {
+				return errActivityPointsSeqMode
+			}
+			if locked.PointSeqMode == "" {
+				if err := tx.Model(&locked).Update("point_seq_mode", mode).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		var existing int64
+		if err := tx.Model(&models.TrackedActivityPoint{}).Where("activity_id = ?", activityID).Count(&existing).Error; err != nil {
+			return err
+		}
+
+		points := make([]models.TrackedActivityPoint, 0, len(body.Points))
+		if withSeq > 0 {
+			seen := make(map[int]struct{}, len(body.Points))
+			seqs := make([]int, 0, len(body.Points))
+			for _, p := range body.Points {
+				if _, dup := seen[*p.Seq]; dup {
+					continue
+				}
+				seen[*p.Seq] = struct{}{}
+				seqs = append(seqs, *p.Seq)
+				points = append(points, models.TrackedActivityPoint{
+					ActivityID: activityID, Seq: *p.Seq, Lat: p.Lat, Lng: p.Lng,
+					Altitude: p.Altitude, Accuracy: p.Accuracy, RecordedAt: parsePointRecordedAt(p.RecordedAt),
+				})
+			}
+			var received int64
+			if err := tx.Model(&models.TrackedActivityPoint{}).
+				Where("activity_id = ? AND seq IN ?", activityID, seqs).Count(&received).Error; err != nil {
+				return err
+			}
+			if existing+int64(len(points))-received > int64(activityPointsMaxPerActivity) {
+				return errActivityPointsTooMany
+			}
+			ins := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "activity_id"}, {Name: "seq"}},
+				DoNothing: true,
+			}).Create(&points)
+			if ins.Error != nil {
+				return ins.Error
+			}
+			res.Added = int(ins.RowsAffected)
+		} else {
+			if existing+int64(len(body.Points)) > int64(activityPointsMaxPerActivity) {
+				return errActivityPointsTooMany
+			}
+			var maxSeq int
+			if err := tx.Model(&models.TrackedActivityPoint{}).
+				Where("activity_id = ?", activityID).
+				Select("COALESCE(MAX(seq), -1)").Scan(&maxSeq).Error; err != nil {
+				return err
+			}
+			for i, p := range body.Points {
+				points = append(points, models.TrackedActivityPoint{
+					ActivityID: activityID, Seq: maxSeq + 1 + i, Lat: p.Lat, Lng: p.Lng,
+					Altitude: p.Altitude, Accuracy: p.Accuracy, RecordedAt: parsePointRecordedAt(p.RecordedAt),
+				})
+			}
+			if err := tx.Create(&points).Error; err != nil {
+				return err
+			}
+			res.Added = len(points)
+		}
+		res.Duplicates = len(body.Points) - res.Added
+
+		if body.BatchID != "" {
+			batch := models.TrackedActivityPointBatch{ActivityID: activityID, BatchID: body.BatchID, Added: res.Added}
+			if err := tx.Create(&batch).Error; err != nil {
+				return err
+			}
+		}
+
+		// Batch-evi stižu bilo kojim redom: početak je uvek tačka sa najmanjim seq.
+		var first []models.TrackedActivityPoint
+		if err := tx.Where("activity_id = ?", activityID).Order("seq ASC").Limit(1).Find(&first).Error; err != nil {
+			return err
+		}
+		if len(first) == 1 && (locked.StartLat == nil || locked.StartLng == nil || *locked.StartLat != first[0].Lat || *locked.StartLng != first[0].Lng) {
+			if err := tx.Model(&locked).Updates(map[string]any{"start_lat": first[0].Lat, "start_lng": first[0].Lng}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	res.MaxSeq, res.Total = activityPointStats(db, activityID)
+	return res, nil
+}
+
+// activityPointStats vraća najveći primljeni seq (-1 kada nema tačaka) i broj tačaka aktivnosti.
+func activityPointStats(db *gorm.DB, activityID uint) (int, int64) {
+	var stats struct {
+		MaxSeq int
+		Total  int64
+	}
+	_ = db.Model(&models.TrackedActivityPoint{}).
+		Where("activity_id = ?", activityID).
+		Select("COALESCE(MAX(seq), -1) AS max_seq, COUNT(*) AS total").
+		Scan(&stats).Error
+	return stats.MaxSeq, stats.Total
+}
+
+type uploadActivityBody struct {
+	ClientID  string `json:"clientId"`
+	StartedAt string `json:"startedAt"`
+}
+
+// StartTrackedActivityUpload POST /activities/upload — otvara (ili vraća postojeći) upload aktivnosti snimljene bez signala.
+// Isti clientId vraća istu aktivnost, pa klijent posle prekida samo nastavlja slanje tačaka od maxSeq,
+// a na kraju zove /activities/:id/finish.
+func StartTrackedActivityUpload(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	var body uploadActivityBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravan zahtev"})
+		return
+	}
+	clientID := strings.TrimSpace(body.ClientID)
+	if clientID == "" || len(clientID) > activityBatchIDMaxLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "clientId je obavezan (najviše 64 znaka)"})
+		return
+	}
+	startedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(body.StartedAt))
+	if err != nil || startedAt.After(time.Now().Add(time.Hour)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravno vreme početka (RFC3339)"})
+		return
+	}
+
+	var existing []models.TrackedActivity
+	if err := db.Where("user_id = ? AND client_id = ?", user.ID, clientID).Limit(1).Find(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri učitavanju aktivnosti"})
+		return
+	}
+	status := http.StatusOK
+	var activity models.TrackedActivity
+	if len(existing) == 1 {
+		activity = existing[0]
+	} else {
+		activity = models.TrackedActivity{
+			UserID:    user.ID,
+			ClientID:  &clientID,
+			Status:    models.TrackedActivityStatusUploading,
+			StartedAt: startedAt.UTC(),
+			KlubID:    user.KlubID,
+		}
+		if err := db.Create(&activity).Error; err != nil {
+			// Dva paralelna zahteva sa istim clientId: drugi čita red koji je prvi upisao.
+			if db.Where("user_id = ? AND client_id = ?", user.ID, clientID).Limit(1).Find(&existing).Error != nil || len(existing) == 0 {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri pokretanju uploada"})
+				return
+			}
+			activity = existing[0]
+		} else {
+			status = http.StatusCreated
+		}
+	}
+
+	maxSeq, total := activityPointStats(db, activity.ID)
+	c.JSON(status, gin.H{
+		"id":        activity.ID,
+		"clientId":  clientID,
+		"status":    activity.Status,
+		"startedAt": activity.StartedAt,
+		"maxSeq":    maxSeq,
+		"total":     total,
+		"chunkMax":  activityPointsMaxPerRequest,
+	})
+}
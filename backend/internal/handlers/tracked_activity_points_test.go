@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func testActivityPointsDB(t *testing.T) (*gorm.DB, models.Korisnik) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	// Sličica rute se ne crta u pozadini dok traje test (testovi koji je proveravaju je pokreću sinhrono).
+	prevRun := runActivityRouteBackground
+	runActivityRouteBackground = func(func()) {}
+	t.Cleanup(func() { runActivityRouteBackground = prevRun })
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "activity_points")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Korisnik{}, &models.TrackedActivity{}, &models.TrackedActivityPoint{}, &models.TrackedActivityPointBatch{},
+		&models.Peak{}, &models.PeakAscent{}, &models.UserAchievement{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user := models.Korisnik{Username: "planinar", Role: "clan"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db, user
+}
+
+func callActivity(t *testing.T, db *gorm.DB, user models.Korisnik, h gin.HandlerFunc, id uint, body any) (int, map[string]any) {
+	t.Helper()
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/activities", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: fmt.Sprint(id)}}
+	c.Set("db", db)
+	c.Set("username", user.Username)
+	h(c)
+	var resp map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+func seqPoints(seqs ...int) []gin.H {
+	out := make([]gin.H, 0, len(seqs))
+	for _, s := range seqs {
+		out = append(out, gin.H{"seq": s, "lat": 43.0 + float64(s)/10000, "lng": 21.0, "recordedAt": "2026-10-18T08:00:00Z"})
+	}
+	return out
+}
+
+func TestAppendTrackedActivityPoints_DedupesClientSeqAndBatches(t *testing.T) {
+	db, user := testActivityPointsDB(t)
+	activity := models.TrackedActivity{UserID: user.ID, Status: models.TrackedActivityStatusActive, StartedAt: time.Now().UTC()}
+	db.Create(&activity)
+
+	code, resp := callActivity(t, db, user, AppendTrackedActivityPoints, activity.ID, gin.H{"points": seqPoints(2, 3, 4)})
+	if code != http.StatusOK || resp["added"] != float64(3) {
+		t.Fatalf("first batch: %d %v", code, resp)
+	}
+	// Retry posle timeout-a + raniji batch koji stiže kasnije.
+	code, resp = callActivity(t, db, user, AppendTrackedActivityPoints, activity.ID, gin.H{"points": seqPoints(2, 3, 4)})
+	if code != http.StatusOK || resp["added"] != float64(0) || resp["duplicates"] != float64(3) {
+		t.Fatalf("retry must not duplicate points: %d %v", code, resp)
+	}
+	code, resp = callActivity(t, db, user, AppendTrackedActivityPoints, activity.ID, gin.H{"points": seqPoints(1, 0, 1)})
+	if code != http.StatusOK || resp["added"] != float64(2) || resp["maxSeq"] != float64(4) || resp["total"] != float64(5) {
+		t.Fatalf("out-of-order batch: %d %v", code, resp)
+	}
+	var reloaded models.TrackedActivity
+	db.First(&reloaded, activity.ID)
+	if reloaded.StartLat == nil || *reloaded.StartLat != 43.0 {
+		t.Fatalf("start must follow the lowest seq, got %v", reloaded.StartLat)
+	}
+
+	code, _ = callActivity(t, db, user, AppendTrackedActivityPoints, activity.ID, gin.H{"points": []gin.H{{"seq": 9, "lat": 43, "lng": 21}, {"lat": 43, "lng": 21}}})
+	if code != http.StatusBadRequest {
+		t.Fatalf("mixed seq must be rejected, got %d", code)
+	}
+
+	// Stariji klijent bez seq: server dodeljuje seq, batchId štiti od dupliranja.
+	legacy := gin.H{"batchId": "b-1", "points": []gin.H{{"lat": 43.1, "lng": 21.1}, {"lat": 43.2, "lng": 21.2}}}
+	code, _ = callActivity(t, db, user, AppendTrackedActivityPoints, activity.ID, legacy)
+	if code != http.StatusConflict {
+		t.Fatalf("server seq on a client-seq activity must be rejected, got %d", code)
+	}
+	older := models.TrackedActivity{UserID: user.ID, Status: models.TrackedActivityStatusActive, StartedAt: time.Now().UTC()}
+	db.Create(&older)
+	code, resp = callActivity(t, db, user, AppendTrackedActivityPoints, older.ID, legacy)
+	if code != http.StatusOK || resp["added"] != float64(2) || resp["maxSeq"] != float64(1) {
+		t.Fatalf("legacy batch: %d %v", code, resp)
+	}
+	code, resp = callActivity(t, db, user, AppendTrackedActivityPoints, older.ID, legacy)
+	if code != http.StatusOK || resp["replayed"] != true || resp["total"] != float64(2) {
+		t.Fatalf("replayed legacy batch must be ignored: %d %v", code, resp)
+	}
+	code, _ = callActivity(t, db, user, AppendTrackedActivityPoints, older.ID, gin.H{"points": seqPoints(5)})
+	if code != http.StatusConflict {
+		t.Fatalf("client seq on a server-seq activity must be rejected, got %d", code)
+	}
+}
+
+func TestAppendTrackedActivityPoints_CapCountsOnlyNewPoints(t *testing.T) {
+	db, user := testActivityPointsDB(t)
+	prev := activityPointsMaxPerActivity
+	activityPointsMaxPerActivity = 4
+	t.Cleanup(func() { activityPointsMaxPerActivity = prev })
+	activity := models.TrackedActivity{UserID: user.ID, Status: models.TrackedActivityStatusActive, StartedAt: time.Now().UTC()}
+	db.Create(&activity)
+
+	if code, resp := callActivity(t, db, user, AppendTrackedActivityPoints, activity.ID, gin.H{"points": seqPoints(0, 1, 2, 3)}); code != http.StatusOK || resp["added"] != float64(4) {
+		t.Fatalf("fill to the cap: %d %v", code, resp)
+	}
+	// Ponovljen batch pri punoj aktivnosti nije prekoračenje: sve tačke su već primljene.
+	if code, resp := callActivity(t, db, user, AppendTrackedActivityPoints, activity.ID, gin.H{"points": seqPoints(2, 3)}); code != http.StatusOK || resp["duplicates"] != float64(2) {
+		t.Fatalf("retry at the cap must stay idempotent: %d %v", code, resp)
+	}
+	if code, _ := callActivity(t, db, user, AppendTrackedActivityPoints, activity.ID, gin.H{"points": seqPoints(3, 4)}); code != http.StatusBadRequest {
+		t.Fatalf("a new point over the cap must be rejected, got %d", code)
+	}
+}
+
+func TestTrackedActivityUpload_ChunksAndFinish(t *testing.T) {
+	db, user := testActivityPointsDB(t)
+	start := gin.H{"clientId": "ios-7f3a", "startedAt": "2026-10-18T06:00:00Z"}
+	code, resp := callActivity(t, db, user, StartTrackedActivityUpload, 0, start)
+	if code != http.StatusCreated || resp["status"] != models.TrackedActivityStatusUploading {
+		t.Fatalf("upload start: %d %v", code, resp)
+	}
+	id := uint(resp["id"].(float64))
+	code, resp = callActivity(t, db, user, StartTrackedActivityUpload, 0, start)
+	if code != http.StatusOK || uint(resp["id"].(float64)) != id {
+		t.Fatalf("same clientId must resume the same upload: %d %v", code, resp)
+	}
+
+	const total = 2500
+	for from := 0; from < total; from += activityPointsMaxPerRequest {
+		seqs := make([]int, 0, activityPointsMaxPerRequest)
+		for s := from; s < total && s < from+activityPointsMaxPerRequest; s++ {
+			seqs = append(seqs, s)
+		}
+		code, resp = callActivity(t, db, user, AppendTrackedActivityPoints, id, gin.H{"points": seqPoints(seqs...)})
+		if code != http.StatusOK {
+			t.Fatalf("chunk from %d: %d %v", from, code, resp)
+		}
+	}
+	if resp["total"] != float64(total) || resp["maxSeq"] != float64(total-1) {
+		t.Fatalf("expected all points stored, got %v", resp)
+	}
+	code, resp = callActivity(t, db, user, StartTrackedActivityUpload, 0, start)
+	if code != http.StatusOK || resp["total"] != float64(total) {
+		t.Fatalf("resume info must report stored points: %d %v", code, resp)
+	}
+
+	code, resp = callActivity(t, db, user, FinishTrackedActivity, id, gin.H{
+		"durationSec": 9000, "distanceM": 12000, "startedAt": "2026-10-18T06:00:00Z", "endedAt": "2026-10-18T08:30:00Z",
+	})
+	if code != http.StatusOK {
+		t.Fatalf("finish: %d %v", code, resp)
+	}
+	var done models.TrackedActivity
+	db.First(&done, id)
+	if done.Status != models.TrackedActivityStatusCompleted || done.EndedAt == nil || !done.EndedAt.Equal(time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC)) {
+		t.Fatalf("upload must finish with the device end time, got %+v", done)
+	}
+}
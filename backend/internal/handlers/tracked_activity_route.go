@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"beleg-app/backend/internal/geo"
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/routethumb"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// activityThumbnailUpload je upload sličice rute (u testovima se zamenjuje).
+var activityThumbnailUpload = helpers.UploadImage
+
+// runActivityRouteBackground pokreće DEM korekciju visina i sličicu rute van zahteva za završetak aktivnosti (u testovima sinhrono).
+var runActivityRouteBackground = func(fn func()) { go fn() }
+
+// loadActivityRoutePoints vraća tačke aktivnosti po seq (samo kolone potrebne za rutu).
+func loadActivityRoutePoints(db *gorm.DB, activityID uint) ([]models.TrackedActivityPoint, error) {
+	var points []models.TrackedActivityPoint
+	err := db.Select("seq", "lat", "lng", "altitude").
+		Where("activity_id = ?", activityID).
+		Order("seq ASC").
+		Find(&points).Error
+	return points, err
+}
+
+func activityRouteLatLngs(points []models.TrackedActivityPoint) []geo.LatLng {
+	out := make([]geo.LatLng, len(points))
+	for i, p := range points {
+		out[i] = geo.LatLng{Lat: p.Lat, Lng: p.Lng}
+	}
+	return out
+}
+
+// applyActivityRoute gradi pojednostavljene polyline-e za geo.PolylineZoomLevels iz primljenih tačaka.
+// Vraća false kada aktivnost nema bar dve tačke (tada ostaje polyline koji je poslao klijent).
+func applyActivityRoute(activity *models.TrackedActivity, points []models.TrackedActivityPoint) bool {
+	if len(points) < 2 {
+		return false
+	}
+	latlngs := activityRouteLatLngs(points)
+	levels := make(map[string]string, len(geo.PolylineZoomLevels))
+	var detail string
+	for _, z := range geo.PolylineZoomLevels {
+		detail = geo.EncodePolyline(geo.SimplifyDouglasPeucker(latlngs, geo.ToleranceMetersForZoom(z, latlngs[0].Lat)))
+		levels[strconv.Itoa(z)] = detail
+	}
+	raw, _ := json.Marshal(levels)
+	activity.RoutePolylines = string(raw)
+	activity.RoutePolyline = detail
+	return true
+}
+
+// generateActivityThumbnail crta PNG sličicu rute (trasa + visinski profil) i čuva je kroz Cloudinary upload.
+// Best-effort: greška se samo loguje, aktivnost ostaje bez sličice.
+func generateActivityThumbnail(db *gorm.DB, activityID uint) {
+	var activity models.TrackedActivity
+	if err := db.First(&activity, activityID).Error; err != nil {
+		log.Printf("tracked activity %d: sličica rute: %v", activityID, err)
+		return
+	}
+	points, err := loadActivityRoutePoints(db, activityID)
+	if err != nil || len(points) < 2 {
+		return
+	}
+	// Sličica je 600 px široka: nivo detalja rute je dovoljan, a crtanje ostaje brzo i za duge ture.
+	latlngs := activityRouteLatLngs(points)
+	zoom := geo.PolylineZoomLevels[len(geo.PolylineZoomLevels)-1]
+	idx := geo.SimplifyDouglasPeuckerIndices(latlngs, geo.ToleranceMetersForZoom(zoom, latlngs[0].Lat))
+	thumbPoints := make([]routethumb.Point, 0, len(idx))
+	for _, i := range idx {
+		thumbPoints = append(thumbPoints, routethumb.Point{Lat: points[i].Lat, Lng: points[i].Lng, Altitude: points[i].Altitude})
+	}
+	png, err := routethumb.RenderPNG(thumbPoints)
+	if err != nil {
+		log.Printf("tracked activity %d: crtanje sličice: %v", activityID, err)
+		return
+	}
+
+	clubID := uint(0)
+	folder := helpers.CloudinaryFolderSetup()
+	if activity.KlubID != nil {
+		clubID = *activity.KlubID
+		folder = helpers.CloudinaryFolderForClub(clubID)
+	}
+	size := int64(len(png))
+	if err := helpers.CheckStorageLimit(db, clubID, size); err != nil {
+		log.Printf("tracked activity %d: sličica rute preskočena: %v", activityID, err)
+		return
+	}
+	publicID := fmt.Sprintf("activities/route-%d-%d", activity.ID, time.Now().Unix())
+	url, err := activityThumbnailUpload(folder, publicID, bytes.NewReader(png))
+	if err != nil {
+		log.Printf("tracked activity %d: upload sličice: %v", activityID, err)
+		return
+	}
+	if err := helpers.AddStorageUsage(db, clubID, size); err != nil {
+		log.Printf("tracked activity %d: storage zauzeće: %v", activityID, err)
+	}
+	if err := db.Model(&models.TrackedActivity{}).Where("id = ?", activity.ID).Update("route_thumbnail_url", url).Error; err != nil {
+		log.Printf("tracked activity %d: čuvanje sličice: %v", activityID, err)
+		return
+	}
+	// Objava podeljena odmah po završetku (pre nego što je sličica bila gotova) dobija je naknadno.
+	if err := db.Model(&models.Post{}).Where("activity_id = ? AND (image_url = '' OR image_url IS NULL)", activity.ID).
+		Update("image_url", url).Error; err != nil {
+		log.Printf("tracked activity %d: sličica u objavama: %v", activityID, err)
+	}
+	helpers.ScheduleCloudinaryDeletion(db, os.Getenv("CLOUDINARY_CLOUD_NAME"), activity.RouteThumbnailURL)
+}
+
+// GetTrackedActivityRoute GET /activities/:id/route?zoom= — encoded polyline pojednostavljen za dati zum mape.
+// Vraća najmanji sačuvani nivo koji je bar toliko detaljan (ili najdetaljniji kada je zum veći od svih nivoa).
+func GetTrackedActivityRoute(c *gin.Context) {
+	db := DB(c)
+	user, ok := currentUser(c, db)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravan ID"})
+		return
+	}
+	activity, okAct := findOwnedActivity(c, db, user.ID, uint(id))
+	if !okAct {
+		return
+	}
+	levels := geo.PolylineZoomLevels
+	zoom := levels[len(levels)-1]
+	if z := c.Query("zoom"); z != "" {
+		n, err := strconv.Atoi(z)
+		if err != nil || n < 0 || n > 22 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Neispravan zoom (0–22)"})
+			return
+		}
+		for i := len(levels) - 1; i >= 0 && levels[i] >= n; i-- {
+			zoom = levels[i]
+		}
+	}
+	var stored map[string]string
+	if activity.RoutePolylines != "" {
+		_ = json.Unmarshal([]byte(activity.RoutePolylines), &stored)
+	}
+	polyline, ok := stored[strconv.Itoa(zoom)]
+	if !ok {
+		// Starije aktivnosti (ili bez tačaka): samo polyline koji je poslao klijent.
+		polyline = activity.RoutePolyline
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"activityId":        activity.ID,
+		"zoom":              zoom,
+		"zoomLevels":        levels,
+		"polyline":          polyline,
+		"routeThumbnailUrl": activity.RouteThumbnailURL,
+	})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/geo"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFinishTrackedActivity_BuildsRouteAndThumbnail(t *testing.T) {
+	db, user := testActivityPointsDB(t)
+	if err := db.AutoMigrate(&models.Post{}, &models.PostLike{}, &models.PostComment{}, &models.Obavestenje{}); err != nil {
+		t.Fatal(err)
+	}
+	prevUpload := activityThumbnailUpload
+	t.Cleanup(func() { activityThumbnailUpload = prevUpload })
+	runActivityRouteBackground = func(fn func()) { fn() }
+	var uploaded []byte
+	activityThumbnailUpload = func(folder, publicID string, r io.Reader) (string, error) {
+		uploaded, _ = io.ReadAll(r)
+		return "https://res.cloudinary.com/demo/image/upload/v1/" + publicID + ".png", nil
+	}
+
+	activity := models.TrackedActivity{UserID: user.ID, Status: models.TrackedActivityStatusActive, StartedAt: time.Now().UTC()}
+	db.Create(&activity)
+	// Uspon na ~1 km pa silazak, 1 tačka na ~8 m; svaka deseta tačka odstupa ~5 m (vidljivo samo na detaljnom nivou).
+	points := make([]gin.H, 0, 241)
+	for i := 0; i <= 240; i++ {
+		climb := 120 - math.Abs(float64(i-120))
+		bump := 0.0
+		if i%10 == 5 {
+			bump = 0.00005
+		}
+		points = append(points, gin.H{
+			"seq": i, "lat": 43.3 + climb*0.00007 + bump, "lng": 21.9 + float64(i)*0.0001,
+			"altitude": 600 + climb*3, "recordedAt": "2026-10-18T08:00:00Z",
+		})
+	}
+	if code, resp := callActivity(t, db, user, AppendTrackedActivityPoints, activity.ID, gin.H{"points": points}); code != http.StatusOK {
+		t.Fatalf("append: %d %v", code, resp)
+	}
+
+	code, resp := callActivity(t, db, user, FinishTrackedActivity, activity.ID, gin.H{"durationSec": 3600, "distanceM": 2000, "routePolyline": "klijent"})
+	if code != http.StatusOK {
+		t.Fatalf("finish: %d %v", code, resp)
+	}
+	var done models.TrackedActivity
+	db.First(&done, activity.ID)
+	detail, ok := geo.DecodePolyline(done.RoutePolyline)
+	if !ok || len(detail) < 3 || len(detail) >= 241 {
+		t.Fatalf("server polyline must be a simplified route, got %d points (%q)", len(detail), done.RoutePolyline)
+	}
+	if done.RouteThumbnailURL == "" {
+		t.Fatal("thumbnail URL must be stored")
+	}
+	img, err := png.Decode(bytes.NewReader(uploaded))
+	if err != nil || img.Bounds().Dx() == 0 {
+		t.Fatalf("uploaded thumbnail must be a PNG: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/activities/1/route?zoom=9", nil)
+	c.Params = gin.Params{{Key: "id", Value: fmt.Sprint(activity.ID)}}
+	c.Set("db", db)
+	c.Set("username", user.Username)
+	GetTrackedActivityRoute(c)
+	var route struct {
+		Zoom     int    `json:"zoom"`
+		Polyline string `json:"polyline"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &route)
+	overview, _ := geo.DecodePolyline(route.Polyline)
+	if w.Code != http.StatusOK || route.Zoom != geo.PolylineZoomLevels[0] || len(overview) == 0 || len(overview) >= len(detail) {
+		t.Fatalf("overview level must have fewer points than detail: %d %+v (%d vs %d)", w.Code, route, len(overview), len(detail))
+	}
+
+	code, created := callActivity(t, db, user, CreatePost, 0, gin.H{"activityId": activity.ID})
+	post, _ := created["post"].(map[string]any)
+	if code != http.StatusCreated || post["imageUrl"] != done.RouteThumbnailURL || post["activityId"] != float64(activity.ID) {
+		t.Fatalf("shared activity post must use the route thumbnail: %d %v", code, created)
+	}
+	other := models.TrackedActivity{UserID: user.ID + 1, Status: models.TrackedActivityStatusCompleted, StartedAt: time.Now().UTC()}
+	db.Create(&other)
+	if code, _ := callActivity(t, db, user, CreatePost, 0, gin.H{"activityId": other.ID}); code != http.StatusBadRequest {
+		t.Fatalf("sharing someone else's activity must be rejected, got %d", code)
+	}
+}
+
+func TestGenerateActivityThumbnail_FillsEarlierSharedPost(t *testing.T) {
+	db, user := testActivityPointsDB(t)
+	if err := db.AutoMigrate(&models.Post{}); err != nil {
+		t.Fatal(err)
+	}
+	prevUpload := activityThumbnailUpload
+	t.Cleanup(func() { activityThumbnailUpload = prevUpload })
+	activityThumbnailUpload = func(folder, publicID string, r io.Reader) (string, error) {
+		return "https://example.com/" + publicID + ".png", nil
+	}
+	activity := models.TrackedActivity{UserID: user.ID, Status: models.TrackedActivityStatusCompleted, StartedAt: time.Now().UTC()}
+	db.Create(&activity)
+	db.Create(&[]models.TrackedActivityPoint{
+		{ActivityID: activity.ID, Seq: 0, Lat: 43.3, Lng: 21.9, RecordedAt: time.Now()},
+		{ActivityID: activity.ID, Seq: 1, Lat: 43.31, Lng: 21.91, RecordedAt: time.Now()},
+	})
+	post := models.Post{UserID: user.ID, AuthorID: user.ID, Content: "Danas na Suvoj planini", ActivityID: &activity.ID}
+	db.Create(&post)
+
+	generateActivityThumbnail(db, activity.ID)
+	var reloaded models.Post
+	db.First(&reloaded, post.ID)
+	if reloaded.ImageURL == "" {
+		t.Fatal("post shared before the thumbnail was ready must get it afterwards")
+	}
+}
@@ -280,7 +280,7 @@ func TestConcurrency_MarkPaidThenUpdateAkcija(t *testing.T) {
 
 func TestConcurrency_UpdateAkcijaThenFinish(t *testing.T) {
 	db := testUpdateAkcijaDB(t)
-	if err := db.AutoMigrate(&models.Transakcija{}); err != nil {
+	if err := db.AutoMigrate(&models.Transakcija{}, &models.Placanje{}); err != nil {
 		t.Fatal(err)
 	}
 	actor := seedUser(t, db, "admin_finish")
@@ -319,7 +319,7 @@ func TestConcurrency_UpdateAkcijaThenFinish(t *testing.T) {
 
 func TestConcurrency_FinishThenFinancialUpdateRejected(t *testing.T) {
 	db := testUpdateAkcijaDB(t)
-	if err := db.AutoMigrate(&models.Transakcija{}); err != nil {
+	if err := db.AutoMigrate(&models.Transakcija{}, &models.Placanje{}); err != nil {
 		t.Fatal(err)
 	}
 	actor := seedUser(t, db, "admin_finish2")
@@ -368,7 +368,7 @@ func TestUpdateMojaPrijavaIzbori_LocksAkcijaBeforePrijava(t *testing.T) {
 
 func TestFinishAction_RejectsAlreadyCompleted(t *testing.T) {
 	db := testUpdateAkcijaDB(t)
-	if err := db.AutoMigrate(&models.Transakcija{}); err != nil {
+	if err := db.AutoMigrate(&models.Transakcija{}, &models.Placanje{}); err != nil {
 		t.Fatal(err)
 	}
 	actor := seedUser(t, db, "finish_twice")
@@ -34,6 +34,13 @@ func testUpdateAkcijaDB(t *testing.T) *gorm.DB {
 		&models.AkcijaPrevoz{},
 		&models.AkcijaOprema{},
 		&models.AkcijaOpremaRent{},
+		&models.AkcijaVerzija{},
+		&models.AkcijaVodic{},
+		&models.AkcijaForma{},
+		&models.AkcijaUslovi{},
+		&models.AkcijaUslovIzuzetak{},
+		&models.AkcijaCenovnik{},
+		&models.AkcijaPromoKod{},
 	); err != nil {
 		t.Fatalf("migrate: %v", err)
 	}
@@ -92,7 +99,8 @@ func seedNestedFinancialOptions(t *testing.T, db *gorm.DB, akcijaID uint) (model
 func runExecuteUpdateAkcijaTx(t *testing.T, db *gorm.DB, akcija models.Akcija, nested ActionNestedSyncInput) error {
 	t.Helper()
 	return db.Transaction(func(tx *gorm.DB) error {
-		return executeUpdateAkcijaTx(tx, akcija, nested)
+		_, err := executeUpdateAkcijaTx(tx, akcija, nested, 0)
+		return err
 	})
 }
 
@@ -188,6 +188,9 @@ func DeleteKorisnikByAdmin(c *gin.Context) {
 	db.Where("korisnik_id = ?", id).Delete(&models.ZadatakKorisnik{})
 	db.Model(&models.Transakcija{}).Where("clanarina_korisnik_id = ?", id).Update("clanarina_korisnik_id", nil)
 	db.Model(&models.Akcija{}).Where("vodic_id = ?", id).Update("vodic_id", 0)
+	db.Where("korisnik_id = ?", id).Delete(&models.AkcijaVodic{})
+	db.Where("korisnik_id = ?", id).Delete(&models.AkcijaUslovIzuzetak{})
+	db.Where("korisnik_id = ?", id).Delete(&models.KorisnikSertifikat{})
 	db.Model(&models.Akcija{}).Where("added_by_id = ?", id).Update("added_by_id", 0)
 
 	if err := db.Delete(&korisnik).Error; err != nil {
@@ -498,6 +501,10 @@ func AddProslaAkcija(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
 		return
 	}
+	if ok, errMsg := applyActionPeakID(c, db, &akcija); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
 	// Prošla akcija ne nosi finansijske stavke: bez troškova po članu/gostu.
 	akcija.CenaClan = 0
 	akcija.CenaOstali = 0
@@ -577,6 +584,10 @@ func AddProslaAkcija(c *gin.Context) {
 			c.JSON(500, gin.H{"error": "Greška pri ažuriranju statistike korisnika"})
 			return
 		}
+		if err := helpers.RecordAkcijaPeakAscentTx(db, &akcija, korisnici[i].ID); err != nil {
+			c.JSON(500, gin.H{"error": "Greška pri upisu uspona u dnevnik vrhova"})
+			return
+		}
 	}
 
 	c.JSON(200, gin.H{"message": "Prošla akcija dodata", "korisnikIds": korisnikIDs, "brojKorisnika": len(korisnikIDs)})
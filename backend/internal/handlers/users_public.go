@@ -181,7 +181,7 @@ func GetPublicKorisnikVodio(c *gin.Context) {
 
 	var vodeneAkcije []models.Akcija
 	var allLed []models.Akcija
-	if err := db.Where("vodic_id = ? AND is_completed = ? AND is_cancelled = ?", korisnik.ID, true, false).
+	if err := db.Where("(vodic_id = ? OR id IN ("+helpers.SQLAkcijaCoGuideIDs+")) AND is_completed = ? AND is_cancelled = ?", korisnik.ID, korisnik.ID, true, false).
 		Order("datum DESC").
 		Find(&allLed).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri čitanju vođenih akcija"})
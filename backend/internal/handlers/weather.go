@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/weather"
+
+	"gorm.io/gorm"
+)
+
+var (
+	weatherProviderOnce sync.Once
+	weatherProvider     weather.Provider
+)
+
+// weatherFor vraća keširani provajder prognoze (WEATHER_PROVIDER/WEATHER_URL) ili nil; testovi ga zamenjuju.
+var weatherFor = func(db *gorm.DB) weather.Provider {
+	weatherProviderOnce.Do(func() { weatherProvider = weather.ProviderFromEnv() })
+	if weatherProvider == nil {
+		return nil
+	}
+	return weather.CachedProvider{Inner: weatherProvider, DB: db}
+}
+
+// akcijaWeatherForDetail vraća prognozu za predstojeću akciju; greška provajdera samo izostavlja prognozu sa detalja.
+func akcijaWeatherForDetail(ctx context.Context, db *gorm.DB, akcija *models.Akcija) *helpers.AkcijaPrognoza {
+	if akcija.IsCompleted || akcija.IsCancelled {
+		return nil
+	}
+	if _, to := helpers.AkcijaTimeWindow(akcija); to.Before(time.Now()) {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	prognoza, err := helpers.AkcijaWeather(ctx, weatherFor(db), akcija)
+	if err != nil {
+		log.Printf("akcija %d: prognoza: %v", akcija.ID, err)
+		return nil
+	}
+	return prognoza
+}
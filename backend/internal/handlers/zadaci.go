@@ -287,6 +287,7 @@ func PreuzmiZadatak(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška pri pridruživanju zadatku"})
 		return
 	}
+	touchZadatak(db, zadatakID)
 
 	var updated models.Zadatak
 	if err := db.Preload("ZadatakKorisnici.Korisnik").First(&updated, zadatakID).Error; err != nil {
@@ -296,6 +297,11 @@ func PreuzmiZadatak(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"zadatak": buildZadatakResponse(updated)})
 }
 
+// touchZadatak pomera updated_at kada se promene izvršioci, da delta sync (/api/sync) pošalje novi spisak.
+func touchZadatak(db *gorm.DB, zadatakID uint) {
+	_ = db.Model(&models.Zadatak{}).Where("id = ?", zadatakID).Update("updated_at", time.Now()).Error
+}
+
 // NapustiZadatak — POST /zadaci/:id/napusti. Uklanja trenutnog korisnika sa zadatka; ako niko više ne radi, status se vraća na "aktivni".
 func NapustiZadatak(c *gin.Context) {
 	zadatakID, ok := parseZadatakID(c)
@@ -342,6 +348,7 @@ func NapustiZadatak(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Niste prijavljeni na ovaj zadatak"})
 		return
 	}
+	touchZadatak(db, zadatakID)
 
 	var remaining int64
 	if err := db.Model(&models.ZadatakKorisnik{}).Where("zadatak_id = ?", zadatakID).Count(&remaining).Error; err != nil {
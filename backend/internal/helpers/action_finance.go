@@ -6,6 +6,7 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"time"
 
 	"beleg-app/backend/internal/models"
 
@@ -53,13 +54,16 @@ type PrijavaRentItem struct {
 	Kolicina int  `json:"kolicina"`
 }
 
-// ParticipantChoices opisuje izbore logistike u prijavi.
+// ParticipantChoices opisuje izbore logistike u prijavi i cenu akcije dogovorenu pri prijavi.
 type ParticipantChoices struct {
 	SelectedSmestajIDs []uint
 	SelectedPrevozIDs  []uint
 	SelectedRentItems  []PrijavaRentItem
+	Cena               *CenaObracun // nil = bez zapamćene cene (pregled pre prijave, prijave pre cenovnika, vodič): ComputeCenaForUser
 }
 
+// ComputeBaseCenaForUser je redovna cena akcije za korisnika (član kluba / ostali), polazna tačka za
+// ComputeCenaObracun. Ne primenjuje cenovnik — za iznos koji korisnik duguje koristi ComputeCenaForUser.
 func ComputeBaseCenaForUser(akcija models.Akcija, korisnik models.Korisnik) float64 {
 	if akcija.KlubID != nil && korisnik.KlubID != nil && *akcija.KlubID == *korisnik.KlubID {
 		return akcija.CenaClan
@@ -70,6 +74,12 @@ func ComputeBaseCenaForUser(akcija models.Akcija, korisnik models.Korisnik) floa
 	return akcija.CenaClan
 }
 
+// ComputeCenaForUser je cena akcije za korisnika po cenovniku (uzrast, early bird u trenutku vreme) kada prijava
+// nema zapamćen obračun; bez promo koda i grupnog popusta, koji postoje samo uz obračun pri prijavi.
+func ComputeCenaForUser(db *gorm.DB, akcija models.Akcija, korisnik models.Korisnik, vreme time.Time) float64 {
+	return ComputeCenaObracun(akcija, LoadAkcijaCenovnik(db, akcija.ID), korisnik, vreme).Ukupno
+}
+
 func HasLogisticsChoices(smestaj []uint, prevoz []uint, rent []PrijavaRentItem) bool {
 	if len(smestaj) > 0 || len(prevoz) > 0 {
 		return true
@@ -90,7 +100,12 @@ func ComputeSaldoForParticipant(db *gorm.DB, akcija models.Akcija, korisnik mode
 	if IsActionGuide(akcija, korisnik.ID) && !HasLogisticsChoices(choices.SelectedSmestajIDs, choices.SelectedPrevozIDs, choices.SelectedRentItems) {
 		return 0
 	}
-	saldo := ComputeBaseCenaForUser(akcija, korisnik)
+	var saldo float64
+	if choices.Cena != nil {
+		saldo = choices.Cena.Ukupno
+	} else {
+		saldo = ComputeCenaForUser(db, akcija, korisnik, time.Now())
+	}
 	if len(choices.SelectedSmestajIDs) > 0 {
 		var picked []models.AkcijaSmestaj
 		if err := db.Where("akcija_id = ? AND id IN ?", akcija.ID, choices.SelectedSmestajIDs).Find(&picked).Error; err == nil {
@@ -155,7 +170,12 @@ func participantChoicesFromIzbori(izbor *models.PrijavaIzbori) (ParticipantChoic
 	if izbor == nil {
 		return ParticipantChoices{}, nil
 	}
-	return participantChoicesFromJSON(izbor.SelectedSmestajIDs, izbor.SelectedPrevozIDs, izbor.SelectedRentItemsRaw)
+	out, err := participantChoicesFromJSON(izbor.SelectedSmestajIDs, izbor.SelectedPrevozIDs, izbor.SelectedRentItemsRaw)
+	if err != nil {
+		return ParticipantChoices{}, err
+	}
+	out.Cena = ParseCenaObracun(izbor.CenaObracun)
+	return out, nil
 }
 
 // ParticipantChoicesFromIzbori parsira izbore iz PrijavaIzbori reda.
@@ -212,7 +232,12 @@ func UnsetPlatioIfObligationChangedTx(
 }
 
 func participantChoicesFromPayload(payload PrijavaIzboriPayload) (ParticipantChoices, error) {
-	return participantChoicesFromJSON(payload.SelectedSmestajIDs, payload.SelectedPrevozIDs, payload.SelectedRentItemsRaw)
+	out, err := participantChoicesFromJSON(payload.SelectedSmestajIDs, payload.SelectedPrevozIDs, payload.SelectedRentItemsRaw)
+	if err != nil {
+		return ParticipantChoices{}, err
+	}
+	out.Cena = ParseCenaObracun(payload.CenaObracun)
+	return out, nil
 }
 
 // HasFinancialObligationChangedTx vraća true kada prijava ima Platio=true i finansijska obaveza
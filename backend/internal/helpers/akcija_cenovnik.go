@@ -0,0 +1,296 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Kategorije cene u CenaObracun.
+const (
+	CenaKategorijaClan    = "clan"
+	CenaKategorijaOstali  = "ostali"
+	CenaKategorijaDete    = "dete"
+	CenaKategorijaStudent = "student"
+	CenaKategorijaSenior  = "senior"
+)
+
+// MaxGrupaLen je najduži naziv grupe za grupni popust.
+const MaxGrupaLen = 60
+
+var (
+	// ErrPromoKodNevazeci: kod ne postoji za akciju, isključen je ili mu je istekao rok.
+	ErrPromoKodNevazeci = errors.New("Nevažeći promo kod")
+	// ErrPromoKodIskoriscen: dostignut je broj iskorišćenja koda.
+	ErrPromoKodIskoriscen = errors.New("Promo kod je već iskorišćen maksimalan broj puta")
+)
+
+// CenaObracun je cena akcije primenjena na učesnika pri prijavi (bez smeštaja, prevoza i rent opreme).
+// Čuva se na zahtevu i prijavi; ComputeSaldoForParticipant ga koristi umesto trenutnog cenovnika.
+type CenaObracun struct {
+	Kategorija   string    `json:"kategorija"` // clan, ostali, dete, student, senior
+	Redovna      float64   `json:"redovna"`    // ComputeBaseCenaForUser u trenutku prijave
+	Osnovna      float64   `json:"osnovna"`    // posle early bird cene i uzrasne kategorije
+	EarlyBird    bool      `json:"earlyBird"`
+	PromoKod     string    `json:"promoKod,omitempty"`
+	PromoPopust  float64   `json:"promoPopust"`
+	Grupa        string    `json:"grupa,omitempty"`
+	GrupniPopust float64   `json:"grupniPopust"`
+	Ukupno       float64   `json:"ukupno"`
+	ObracunatoAt time.Time `json:"obracunatoAt"`
+}
+
+// CenaOpcije su unosi člana pri prijavi koji utiču na cenu.
+type CenaOpcije struct {
+	PromoKod string
+	Grupa    string
+	Vreme    time.Time // trenutak prijave (early bird, rok promo koda)
+}
+
+// ParseCenaObracun čita zapamćenu cenu; nil za stare prijave bez obračuna.
+func ParseCenaObracun(raw string) *CenaObracun {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" || trimmed == "null" {
+		return nil
+	}
+	var out CenaObracun
+	if err := json.Unmarshal([]byte(trimmed), &out); err != nil {
+		return nil
+	}
+	return &out
+}
+
+// EncodeCenaObracun serijalizuje obračun za PrijavaIzbori / ActionSignupRequest.
+func EncodeCenaObracun(o *CenaObracun) string {
+	if o == nil {
+		return ""
+	}
+	raw, _ := json.Marshal(o)
+	return string(raw)
+}
+
+// NormalizePromoKod: kodovi se porede bez razmaka i bez obzira na velika/mala slova.
+func NormalizePromoKod(kod string) string {
+	return strings.ToUpper(strings.TrimSpace(kod))
+}
+
+// NormalizeGrupa je ključ grupe: mala slova i jedan razmak između reči (SQL LOWER ne radi za ć/š u SQLite-u).
+func NormalizeGrupa(grupa string) string {
+	grupa = strings.ToLower(strings.Join(strings.Fields(grupa), " "))
+	if r := []rune(grupa); len(r) > MaxGrupaLen {
+		grupa = string(r[:MaxGrupaLen])
+	}
+	return grupa
+}
+
+func zaokruziCenu(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return math.Round(v*100) / 100
+}
+
+// LoadAkcijaCenovnik vraća cenovnik akcije; nil kada ga nema.
+func LoadAkcijaCenovnik(db *gorm.DB, akcijaID uint) *models.AkcijaCenovnik {
+	var c models.AkcijaCenovnik
+	if err := db.Where("akcija_id = ?", akcijaID).First(&c).Error; err != nil {
+		return nil
+	}
+	return &c
+}
+
+// AkcijaCenovnikPrazan: cenovnik ne menja redovnu cenu ni za koga.
+func AkcijaCenovnikPrazan(c *models.AkcijaCenovnik) bool {
+	return c == nil || (c.CenaDete == nil && c.CenaStudent == nil && c.CenaSenior == nil &&
+		(c.EarlyBirdDo == nil || (c.EarlyBirdCenaClan == nil && c.EarlyBirdCenaOstali == nil)) &&
+		(c.GrupaMinOsoba <= 0 || c.GrupaPopustProcenat <= 0))
+}
+
+// uzrastnaKategorija vraća kategoriju i cenu po uzrastu na dan akcije; prazno kada nema odgovarajuće cene.
+func uzrastnaKategorija(akcija models.Akcija, c *models.AkcijaCenovnik, korisnik models.Korisnik) (string, float64) {
+	if c == nil || korisnik.DatumRodjenja == nil {
+		return "", 0
+	}
+	godine := uzrastNaDan(*korisnik.DatumRodjenja, akcija.Datum)
+	switch {
+	case c.CenaDete != nil && godine <= c.DeteDoGodina:
+		return CenaKategorijaDete, *c.CenaDete
+	case c.CenaStudent != nil && godine > c.DeteDoGodina && godine <= c.StudentDoGodina:
+		return CenaKategorijaStudent, *c.CenaStudent
+	case c.CenaSenior != nil && c.SeniorOdGodina > 0 && godine >= c.SeniorOdGodina:
+		return CenaKategorijaSenior, *c.CenaSenior
+	}
+	return "", 0
+}
+
+// ComputeCenaObracun računa cenu bez promo koda i grupe: redovna cena (ComputeBaseCenaForUser), early bird do roka
+// i uzrasna kategorija ako je povoljnija.
+func ComputeCenaObracun(akcija models.Akcija, c *models.AkcijaCenovnik, korisnik models.Korisnik, vreme time.Time) CenaObracun {
+	redovna := ComputeBaseCenaForUser(akcija, korisnik)
+	kategorija := CenaKategorijaOstali
+	if !akcija.Javna || (akcija.KlubID != nil && korisnik.KlubID != nil && *akcija.KlubID == *korisnik.KlubID) {
+		kategorija = CenaKategorijaClan
+	}
+	out := CenaObracun{Kategorija: kategorija, Redovna: redovna, Osnovna: redovna, ObracunatoAt: vreme}
+	if c != nil && c.EarlyBirdDo != nil && !vreme.After(*c.EarlyBirdDo) {
+		eb := c.EarlyBirdCenaOstali
+		if kategorija == CenaKategorijaClan {
+			eb = c.EarlyBirdCenaClan
+		}
+		if eb != nil && *eb < out.Osnovna {
+			out.Osnovna = *eb
+			out.EarlyBird = true
+		}
+	}
+	if k, cena := uzrastnaKategorija(akcija, c, korisnik); k != "" && cena < out.Osnovna {
+		out.Kategorija = k
+		out.Osnovna = cena
+		out.EarlyBird = false
+	}
+	out.Osnovna = zaokruziCenu(out.Osnovna)
+	out.Ukupno = out.Osnovna
+	return out
+}
+
+// PromoKodIskoriscenja broji aktivne prijave i zahteve na čekanju sa kodom (excludeRequestID se ne broji).
+func PromoKodIskoriscenja(db *gorm.DB, kodID uint, excludeRequestID uint) int64 {
+	var prijave int64
+	_ = db.Table("prijava_izbori").
+		Joins("JOIN prijave ON prijave.id = prijava_izbori.prijava_id").
+		Where("prijava_izbori.promo_kod_id = ? AND prijave.status IN ?", kodID, PrijavaActiveStatuses).
+		Count(&prijave).Error
+	var zahtevi int64
+	_ = db.Model(&models.ActionSignupRequest{}).
+		Where("promo_kod_id = ? AND status = ? AND id <> ?", kodID, models.ActionSignupRequestPending, excludeRequestID).
+		Count(&zahtevi).Error
+	return prijave + zahtevi
+}
+
+// brojClanovaGrupe broji aktivne prijave i zahteve na čekanju u grupi, bez korisnika koji se prijavljuje.
+func brojClanovaGrupe(db *gorm.DB, akcijaID uint, grupa string, korisnikID uint) int64 {
+	var prijave int64
+	_ = db.Table("prijava_izbori").
+		Joins("JOIN prijave ON prijave.id = prijava_izbori.prijava_id").
+		Where("prijave.akcija_id = ? AND prijave.status IN ? AND prijave.korisnik_id <> ? AND prijava_izbori.grupa = ?",
+			akcijaID, PrijavaActiveStatuses, korisnikID, grupa).
+		Count(&prijave).Error
+	var zahtevi int64
+	_ = db.Model(&models.ActionSignupRequest{}).
+		Where("akcija_id = ? AND status = ? AND requester_id <> ? AND grupa = ?",
+			akcijaID, models.ActionSignupRequestPending, korisnikID, grupa).
+		Count(&zahtevi).Error
+	return prijave + zahtevi
+}
+
+func primeniPromo(o *CenaObracun, kod *models.AkcijaPromoKod) {
+	popust := o.Ukupno*kod.PopustProcenat/100 + kod.PopustIznos
+	if popust > o.Ukupno {
+		popust = o.Ukupno
+	}
+	o.PromoKod = kod.Kod
+	o.PromoPopust = zaokruziCenu(popust)
+	o.Ukupno = zaokruziCenu(o.Ukupno - o.PromoPopust)
+}
+
+func primeniGrupniPopust(o *CenaObracun, procenat float64) {
+	o.GrupniPopust = zaokruziCenu(o.Ukupno * procenat / 100)
+	o.Ukupno = zaokruziCenu(o.Ukupno - o.GrupniPopust)
+}
+
+// ObracunajCenuTx računa cenu pri slanju prijave: ComputeCenaObracun, zatim promo kod (zaključan red, proverava rok i
+// broj iskorišćenja) i grupni popust kada grupa sa korisnikom dostiže GrupaMinOsoba. Vraća i kod za PromoKodID.
+func ObracunajCenuTx(tx *gorm.DB, akcija models.Akcija, korisnik models.Korisnik, opcije CenaOpcije) (CenaObracun, *models.AkcijaPromoKod, error) {
+	if opcije.Vreme.IsZero() {
+		opcije.Vreme = time.Now()
+	}
+	c := LoadAkcijaCenovnik(tx, akcija.ID)
+	out := ComputeCenaObracun(akcija, c, korisnik, opcije.Vreme)
+
+	var promo *models.AkcijaPromoKod
+	if kod := NormalizePromoKod(opcije.PromoKod); kod != "" {
+		var row models.AkcijaPromoKod
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("akcija_id = ? AND kod = ?", akcija.ID, kod).First(&row).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return CenaObracun{}, nil, ErrPromoKodNevazeci
+			}
+			return CenaObracun{}, nil, err
+		}
+		if !row.Aktivan || (row.VaziDo != nil && opcije.Vreme.After(*row.VaziDo)) {
+			return CenaObracun{}, nil, ErrPromoKodNevazeci
+		}
+		if row.MaxKoriscenja > 0 && PromoKodIskoriscenja(tx, row.ID, 0) >= int64(row.MaxKoriscenja) {
+			return CenaObracun{}, nil, ErrPromoKodIskoriscen
+		}
+		primeniPromo(&out, &row)
+		promo = &row
+	}
+
+	if grupa := NormalizeGrupa(opcije.Grupa); grupa != "" {
+		out.Grupa = grupa
+		if c != nil && c.GrupaMinOsoba > 0 && c.GrupaPopustProcenat > 0 &&
+			brojClanovaGrupe(tx, akcija.ID, grupa, korisnik.ID)+1 >= int64(c.GrupaMinOsoba) {
+			primeniGrupniPopust(&out, c.GrupaPopustProcenat)
+		}
+	}
+	return out, promo, nil
+}
+
+// PrimeniGrupniPopustTx daje grupni popust ranijim članovima grupe kada grupa dostigne GrupaMinOsoba: zahtevima na
+// čekanju i neplaćenim aktivnim prijavama čiji obračun još nema popust. Plaćene prijave ostaju kako su dogovorene.
+func PrimeniGrupniPopustTx(tx *gorm.DB, akcijaID uint, grupa string) error {
+	grupa = NormalizeGrupa(grupa)
+	c := LoadAkcijaCenovnik(tx, akcijaID)
+	if grupa == "" || c == nil || c.GrupaMinOsoba <= 0 || c.GrupaPopustProcenat <= 0 {
+		return nil
+	}
+	if brojClanovaGrupe(tx, akcijaID, grupa, 0) < int64(c.GrupaMinOsoba) {
+		return nil
+	}
+
+	var zahtevi []models.ActionSignupRequest
+	if err := tx.Where("akcija_id = ? AND status = ? AND grupa = ?", akcijaID, models.ActionSignupRequestPending, grupa).
+		Find(&zahtevi).Error; err != nil {
+		return err
+	}
+	for _, z := range zahtevi {
+		o := ParseCenaObracun(z.CenaObracun)
+		if o == nil || o.GrupniPopust > 0 {
+			continue
+		}
+		primeniGrupniPopust(o, c.GrupaPopustProcenat)
+		if err := tx.Model(&models.ActionSignupRequest{}).Where("id = ?", z.ID).
+			Update("cena_obracun", EncodeCenaObracun(o)).Error; err != nil {
+			return err
+		}
+	}
+
+	var izbori []models.PrijavaIzbori
+	if err := tx.Table("prijava_izbori").
+		Select("prijava_izbori.*").
+		Joins("JOIN prijave ON prijave.id = prijava_izbori.prijava_id").
+		Where("prijave.akcija_id = ? AND prijave.status IN ? AND prijave.platio = ? AND prijava_izbori.grupa = ?",
+			akcijaID, PrijavaActiveStatuses, false, grupa).
+		Find(&izbori).Error; err != nil {
+		return err
+	}
+	for _, iz := range izbori {
+		o := ParseCenaObracun(iz.CenaObracun)
+		if o == nil || o.GrupniPopust > 0 {
+			continue
+		}
+		primeniGrupniPopust(o, c.GrupaPopustProcenat)
+		if err := tx.Model(&models.PrijavaIzbori{}).Where("id = ?", iz.ID).
+			Update("cena_obracun", EncodeCenaObracun(o)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func testCenovnikDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := testPrijavaDB(t)
+	if err := db.AutoMigrate(&models.AkcijaCenovnik{}, &models.AkcijaPromoKod{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func cenaPtr(v float64) *float64 { return &v }
+
+func TestComputeCenaObracun_TiersAndEarlyBird(t *testing.T) {
+	klubID := uint(1)
+	datum := time.Date(2027, 8, 1, 6, 0, 0, 0, time.UTC)
+	akcija := models.Akcija{Datum: datum, CenaClan: 3000, CenaOstali: 4000, Javna: true, KlubID: &klubID}
+	rok := time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+	c := &models.AkcijaCenovnik{
+		CenaDete: cenaPtr(0), DeteDoGodina: 14, CenaStudent: cenaPtr(3500), StudentDoGodina: 26, CenaSenior: cenaPtr(2000), SeniorOdGodina: 65,
+		EarlyBirdDo: &rok, EarlyBirdCenaClan: cenaPtr(2500), EarlyBirdCenaOstali: cenaPtr(3200),
+	}
+	rodjen := func(godina int) *time.Time { d := time.Date(godina, 1, 1, 0, 0, 0, 0, time.UTC); return &d }
+	rano := time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC)
+	kasno := time.Date(2027, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name       string
+		korisnik   models.Korisnik
+		vreme      time.Time
+		kategorija string
+		ukupno     float64
+		earlyBird  bool
+	}{
+		{"member early bird", models.Korisnik{KlubID: &klubID, DatumRodjenja: rodjen(1990)}, rano, CenaKategorijaClan, 2500, true},
+		{"member regular", models.Korisnik{KlubID: &klubID, DatumRodjenja: rodjen(1990)}, kasno, CenaKategorijaClan, 3000, false},
+		{"guest early bird", models.Korisnik{}, rano, CenaKategorijaOstali, 3200, true},
+		{"child", models.Korisnik{DatumRodjenja: rodjen(2015)}, kasno, CenaKategorijaDete, 0, false},
+		{"student cheaper for guest", models.Korisnik{DatumRodjenja: rodjen(2005)}, kasno, CenaKategorijaStudent, 3500, false},
+		{"student not cheaper for member", models.Korisnik{KlubID: &klubID, DatumRodjenja: rodjen(2005)}, kasno, CenaKategorijaClan, 3000, false},
+		{"senior beats early bird", models.Korisnik{KlubID: &klubID, DatumRodjenja: rodjen(1950)}, rano, CenaKategorijaSenior, 2000, false},
+	}
+	for _, tc := range cases {
+		got := ComputeCenaObracun(akcija, c, tc.korisnik, tc.vreme)
+		if got.Kategorija != tc.kategorija || !SaldoAmountsEqual(got.Ukupno, tc.ukupno) || got.EarlyBird != tc.earlyBird {
+			t.Errorf("%s: got %+v", tc.name, got)
+		}
+	}
+}
+
+func TestObracunajCenuTx_PromoLimitAndGroupDiscount(t *testing.T) {
+	db := testCenovnikDB(t)
+	akcija := models.Akcija{Naziv: "Cene", Datum: time.Now().AddDate(0, 1, 0), CenaClan: 2000, CenaOstali: 2000, Javna: true}
+	db.Create(&akcija)
+	db.Create(&models.AkcijaCenovnik{AkcijaID: akcija.ID, DeteDoGodina: 14, StudentDoGodina: 26, SeniorOdGodina: 65, GrupaMinOsoba: 3, GrupaPopustProcenat: 10})
+	db.Create(&models.AkcijaPromoKod{AkcijaID: akcija.ID, Kod: "LETO", PopustProcenat: 25, MaxKoriscenja: 1, Aktivan: true})
+	users := make([]models.Korisnik, 3)
+	for i := range users {
+		users[i] = models.Korisnik{Username: "cena_" + string(rune('a'+i)), Password: "x"}
+		db.Create(&users[i])
+	}
+
+	if _, _, err := ObracunajCenuTx(db, akcija, users[0], CenaOpcije{PromoKod: "zima"}); !errors.Is(err, ErrPromoKodNevazeci) {
+		t.Fatalf("unknown code: %v", err)
+	}
+	first, promo, err := ObracunajCenuTx(db, akcija, users[0], CenaOpcije{PromoKod: " leto ", Grupa: "Porodica  Jović"})
+	if err != nil || promo == nil || !SaldoAmountsEqual(first.Ukupno, 1500) || first.Grupa != "porodica jović" || first.GrupniPopust != 0 {
+		t.Fatalf("promo applied: %+v %v", first, err)
+	}
+	promoID := promo.ID
+	db.Create(&models.ActionSignupRequest{AkcijaID: akcija.ID, RequesterID: users[0].ID, Status: models.ActionSignupRequestPending,
+		CenaObracun: EncodeCenaObracun(&first), PromoKodID: &promoID, Grupa: first.Grupa})
+	if _, _, err := ObracunajCenuTx(db, akcija, users[1], CenaOpcije{PromoKod: "LETO"}); !errors.Is(err, ErrPromoKodIskoriscen) {
+		t.Fatalf("limit reached: %v", err)
+	}
+
+	second, _, _ := ObracunajCenuTx(db, akcija, users[1], CenaOpcije{Grupa: "porodica jović"})
+	db.Create(&models.ActionSignupRequest{AkcijaID: akcija.ID, RequesterID: users[1].ID, Status: models.ActionSignupRequestPending,
+		CenaObracun: EncodeCenaObracun(&second), Grupa: second.Grupa})
+	third, _, _ := ObracunajCenuTx(db, akcija, users[2], CenaOpcije{Grupa: "PORODICA JOVIĆ"})
+	if !SaldoAmountsEqual(third.Ukupno, 1800) {
+		t.Fatalf("third member gets group discount: %+v", third)
+	}
+	db.Create(&models.ActionSignupRequest{AkcijaID: akcija.ID, RequesterID: users[2].ID, Status: models.ActionSignupRequestPending,
+		CenaObracun: EncodeCenaObracun(&third), Grupa: third.Grupa})
+	if err := PrimeniGrupniPopustTx(db, akcija.ID, "Porodica Jović"); err != nil {
+		t.Fatal(err)
+	}
+	var reqs []models.ActionSignupRequest
+	db.Where("akcija_id = ?", akcija.ID).Order("id").Find(&reqs)
+	want := []float64{1350, 1800, 1800}
+	for i, r := range reqs {
+		if o := ParseCenaObracun(r.CenaObracun); o == nil || !SaldoAmountsEqual(o.Ukupno, want[i]) {
+			t.Fatalf("request %d after group reached: %+v", i, o)
+		}
+	}
+
+	saldo := ComputeSaldoForParticipant(db, akcija, users[0], ParticipantChoices{Cena: ParseCenaObracun(reqs[0].CenaObracun)})
+	akcija.CenaOstali = 5000
+	if later := ComputeSaldoForParticipant(db, akcija, users[0], ParticipantChoices{Cena: ParseCenaObracun(reqs[0].CenaObracun)}); !SaldoAmountsEqual(saldo, later) {
+		t.Fatalf("agreed price must survive edits: %v → %v", saldo, later)
+	}
+}
+
+func TestComputeSaldoForParticipant_PriceListWithoutStoredCena(t *testing.T) {
+	db := testCenovnikDB(t)
+	klubID := uint(1)
+	akcija := models.Akcija{Naziv: "Suva planina", Datum: time.Now().AddDate(0, 1, 0), CenaClan: 3000, CenaOstali: 4000, KlubID: &klubID}
+	db.Create(&akcija)
+	db.Create(&models.AkcijaCenovnik{AkcijaID: akcija.ID, CenaDete: cenaPtr(1000), DeteDoGodina: 14, StudentDoGodina: 26, SeniorOdGodina: 65})
+	rodjen := time.Now().AddDate(-10, 0, 0)
+	dete := models.Korisnik{KlubID: &klubID, DatumRodjenja: &rodjen}
+	odrasli := models.Korisnik{KlubID: &klubID}
+
+	if got := ComputeSaldoForParticipant(db, akcija, dete, ParticipantChoices{}); !SaldoAmountsEqual(got, 1000) {
+		t.Fatalf("child without stored price must get the price list tier, got %v", got)
+	}
+	if got := ComputeSaldoForParticipant(db, akcija, odrasli, ParticipantChoices{}); !SaldoAmountsEqual(got, 3000) {
+		t.Fatalf("adult member pays the regular price, got %v", got)
+	}
+	dogovoreno := &CenaObracun{Ukupno: 2200}
+	if got := ComputeSaldoForParticipant(db, akcija, dete, ParticipantChoices{Cena: dogovoreno}); !SaldoAmountsEqual(got, 2200) {
+		t.Fatalf("stored price wins over the price list, got %v", got)
+	}
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/sun"
+)
+
+// AkcijaDaylight su podaci o dnevnom svetlu za dan i tačku akcije (PlaninaLat/Lng).
+type AkcijaDaylight struct {
+	Datum string `json:"datum"` // YYYY-MM-DD
+	sun.Day
+	KrajTure       *time.Time `json:"krajTure,omitempty"` // StartAt + TrajanjeSati
+	UpozorenjeMrak bool       `json:"upozorenjeMrak"`
+	Upozorenje     string     `json:"upozorenje,omitempty"`
+}
+
+// AkcijaDay vraća kalendarski dan akcije: dan polaska po beogradskom vremenu, inače Datum (čuva se kao UTC datum).
+func AkcijaDay(akcija *models.Akcija) time.Time {
+	if akcija.StartAt != nil {
+		return akcija.StartAt.In(belgradeLocation())
+	}
+	return akcija.Datum.UTC()
+}
+
+// AkcijaDaylightInfo računa izlazak/zalazak i građanski sumrak za akciju; nil kada akcija nema tačku na mapi.
+// Upozorenje se postavlja kada polazak + trajanje ture pada posle građanskog sumraka.
+func AkcijaDaylightInfo(akcija *models.Akcija) *AkcijaDaylight {
+	if akcija == nil || akcija.PlaninaLat == nil || akcija.PlaninaLng == nil {
+		return nil
+	}
+	day := AkcijaDay(akcija)
+	info := &AkcijaDaylight{Datum: day.Format("2006-01-02"), Day: sun.Times(day, *akcija.PlaninaLat, *akcija.PlaninaLng)}
+	if akcija.StartAt == nil || akcija.TrajanjeSati <= 0 {
+		if info.PolarNight {
+			info.UpozorenjeMrak, info.Upozorenje = true, "Sunce tog dana ne izlazi."
+		}
+		return info
+	}
+	end := akcija.StartAt.Add(time.Duration(akcija.TrajanjeSati * float64(time.Hour))).UTC()
+	info.KrajTure = &end
+	switch {
+	case info.PolarNight && info.CivilDusk == nil:
+		info.UpozorenjeMrak, info.Upozorenje = true, "Sunce tog dana ne izlazi."
+	case info.CivilDusk != nil && end.After(*info.CivilDusk):
+		info.UpozorenjeMrak = true
+		info.Upozorenje = fmt.Sprintf("Planirani kraj ture (%s) je posle građanskog sumraka (%s) — ponesite čeonu lampu ili ranije krenite.",
+			formatBelgradeClock(end), formatBelgradeClock(*info.CivilDusk))
+	}
+	return info
+}
+
+// Summary je kratak tekst za podsetnike i opis u kalendaru (beogradsko vreme).
+func (d *AkcijaDaylight) Summary() string {
+	if d == nil {
+		return ""
+	}
+	var parts []string
+	switch {
+	case d.PolarNight:
+		parts = append(parts, "Sunce ne izlazi")
+	case d.PolarDay:
+		parts = append(parts, "Sunce ne zalazi")
+	default:
+		parts = append(parts, fmt.Sprintf("Izlazak Sunca %s, zalazak %s", formatBelgradeClock(*d.Sunrise), formatBelgradeClock(*d.Sunset)))
+	}
+	if d.CivilDusk != nil {
+		parts = append(parts, "građanski sumrak "+formatBelgradeClock(*d.CivilDusk))
+	}
+	if !d.PolarNight && !d.PolarDay {
+		parts = append(parts, fmt.Sprintf("dnevnog svetla %d h %d min", d.DaylightMin/60, d.DaylightMin%60))
+	}
+	s := strings.Join(parts, ", ") + "."
+	if d.Upozorenje != "" {
+		s += " " + d.Upozorenje
+	}
+	return s
+}
+
+func formatBelgradeClock(t time.Time) string {
+	return t.In(belgradeLocation()).Format("15:04")
+}
+
+// BelgradeLocation je vremenska zona u kojoj se akcije prikazuju (podsetnici, kalendar).
+func BelgradeLocation() *time.Location {
+	return belgradeLocation()
+}
@@ -0,0 +1,276 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MaxFormaPolja       = 30
+	MaxFormaOpcija      = 30
+	MaxFormaLabelLen    = 200
+	MaxFormaOdgovorLen  = 2000
+	formaPoljeIDPrefiks = "p"
+)
+
+var (
+	// ErrFormaNevazeca: šema forme nije ispravna (labela, tip, opcije, nepoznat id polja).
+	ErrFormaNevazeca = errors.New("Nevažeća forma prijave")
+	// ErrFormaTipPromenjen: tip polja na koje već postoje odgovori ne može da se menja.
+	ErrFormaTipPromenjen = errors.New("Tip pitanja na koje već postoje odgovori ne može da se menja; dodajte novo pitanje")
+	// ErrFormaOdgovor: odgovori na formu nisu ispravni (obavezno polje, opcija, broj).
+	ErrFormaOdgovor = errors.New("Nevažeći odgovori na formu prijave")
+)
+
+// FormaPolje je jedno pitanje forme prijave. ID je stabilan (dodeljuje ga server) — odgovori se vezuju za njega,
+// pa preimenovanje pitanja ne gubi odgovore. Arhivirano polje je uklonjeno iz forme, ali ima odgovore (export ih zadržava).
+type FormaPolje struct {
+	ID         string   `json:"id"`
+	Label      string   `json:"label"`
+	Tip        string   `json:"tip"`
+	Obavezno   bool     `json:"obavezno"`
+	Opcije     []string `json:"opcije,omitempty"`
+	Arhivirano bool     `json:"arhivirano,omitempty"`
+}
+
+// ParseFormaPolja čita Polja forme; neispravan JSON je prazna forma.
+func ParseFormaPolja(raw string) []FormaPolje {
+	var polja []FormaPolje
+	if strings.TrimSpace(raw) == "" {
+		return []FormaPolje{}
+	}
+	if err := json.Unmarshal([]byte(raw), &polja); err != nil || polja == nil {
+		return []FormaPolje{}
+	}
+	return polja
+}
+
+// AktivnaFormaPolja vraća polja koja učesnici vide (bez arhiviranih).
+func AktivnaFormaPolja(polja []FormaPolje) []FormaPolje {
+	out := make([]FormaPolje, 0, len(polja))
+	for _, p := range polja {
+		if !p.Arhivirano {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// LoadAkcijaForma vraća formu akcije i njena polja; nil kada akcija nema formu.
+func LoadAkcijaForma(db *gorm.DB, akcijaID uint) (*models.AkcijaForma, []FormaPolje) {
+	var forma models.AkcijaForma
+	if err := db.Where("akcija_id = ?", akcijaID).First(&forma).Error; err != nil {
+		return nil, nil
+	}
+	return &forma, ParseFormaPolja(forma.Polja)
+}
+
+// ParseFormaOdgovori čita sačuvane odgovore (JSON mapa id polja → odgovor).
+func ParseFormaOdgovori(raw string) map[string]string {
+	out := map[string]string{}
+	if strings.TrimSpace(raw) != "" {
+		_ = json.Unmarshal([]byte(raw), &out)
+	}
+	if out == nil {
+		out = map[string]string{}
+	}
+	return out
+}
+
+// EncodeFormaOdgovori serijalizuje odgovore za PrijavaIzbori / ActionSignupRequest; bez odgovora je prazan string.
+func EncodeFormaOdgovori(odgovori map[string]string) string {
+	if len(odgovori) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(odgovori)
+	return string(b)
+}
+
+// ValidateFormaOdgovori proverava odgovore prema aktivnim poljima forme i vraća očišćene odgovore
+// (trim, bez nepoznatih i arhiviranih polja, broj u kanonskom obliku).
+func ValidateFormaOdgovori(polja []FormaPolje, odgovori map[string]string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, p := range AktivnaFormaPolja(polja) {
+		v := strings.TrimSpace(odgovori[p.ID])
+		if v == "" {
+			if p.Obavezno {
+				return nil, fmt.Errorf("%w: pitanje „%s” je obavezno", ErrFormaOdgovor, p.Label)
+			}
+			continue
+		}
+		if len([]rune(v)) > MaxFormaOdgovorLen {
+			return nil, fmt.Errorf("%w: odgovor na „%s” je predugačak", ErrFormaOdgovor, p.Label)
+		}
+		switch p.Tip {
+		case models.FormaPoljeTipChoice:
+			found := false
+			for _, o := range p.Opcije {
+				if o == v {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("%w: „%s” nije ponuđen odgovor za „%s”", ErrFormaOdgovor, v, p.Label)
+			}
+		case models.FormaPoljeTipNumber:
+			f, err := strconv.ParseFloat(strings.ReplaceAll(v, ",", "."), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: odgovor na „%s” mora biti broj", ErrFormaOdgovor, p.Label)
+			}
+			v = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		out[p.ID] = v
+	}
+	return out, nil
+}
+
+// FormaOdgovoriNepotpuni: nedostaje odgovor na neko aktivno obavezno polje.
+func FormaOdgovoriNepotpuni(polja []FormaPolje, odgovori map[string]string) bool {
+	for _, p := range AktivnaFormaPolja(polja) {
+		if p.Obavezno && strings.TrimSpace(odgovori[p.ID]) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeFormaPolje(p FormaPolje) (FormaPolje, error) {
+	p.ID = strings.TrimSpace(p.ID)
+	p.Label = strings.TrimSpace(p.Label)
+	p.Tip = strings.ToLower(strings.TrimSpace(p.Tip))
+	p.Arhivirano = false
+	if p.Label == "" || len([]rune(p.Label)) > MaxFormaLabelLen {
+		return p, fmt.Errorf("%w: svako pitanje mora imati tekst (do %d znakova)", ErrFormaNevazeca, MaxFormaLabelLen)
+	}
+	switch p.Tip {
+	case models.FormaPoljeTipText, models.FormaPoljeTipNumber:
+		p.Opcije = nil
+	case models.FormaPoljeTipChoice:
+		seen := map[string]bool{}
+		opcije := make([]string, 0, len(p.Opcije))
+		for _, o := range p.Opcije {
+			o = strings.TrimSpace(o)
+			if o == "" || seen[o] {
+				continue
+			}
+			seen[o] = true
+			opcije = append(opcije, o)
+		}
+		if len(opcije) == 0 || len(opcije) > MaxFormaOpcija {
+			return p, fmt.Errorf("%w: pitanje „%s” mora imati 1–%d opcija", ErrFormaNevazeca, p.Label, MaxFormaOpcija)
+		}
+		p.Opcije = opcije
+	default:
+		return p, fmt.Errorf("%w: tip pitanja mora biti text, choice ili number", ErrFormaNevazeca)
+	}
+	return p, nil
+}
+
+func nextFormaPoljeID(polja []FormaPolje) int {
+	next := 1
+	for _, p := range polja {
+		if n, err := strconv.Atoi(strings.TrimPrefix(p.ID, formaPoljeIDPrefiks)); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return next
+}
+
+// MergeFormaPolja primenjuje novu šemu forme na postojeću. Nova polja (bez id) dobijaju id od servera;
+// postojeća se prepoznaju po id. Uklonjeno polje na koje postoje odgovori se arhivira umesto brisanja,
+// a promena tipa takvog polja je greška (stari odgovori ne bi važili).
+func MergeFormaPolja(stara, nova []FormaPolje, odgovorena map[string]bool) ([]FormaPolje, error) {
+	if len(nova) > MaxFormaPolja {
+		return nil, fmt.Errorf("%w: forma može imati najviše %d pitanja", ErrFormaNevazeca, MaxFormaPolja)
+	}
+	byID := make(map[string]FormaPolje, len(stara))
+	for _, p := range stara {
+		byID[p.ID] = p
+	}
+	next := nextFormaPoljeID(stara)
+	used := map[string]bool{}
+	out := make([]FormaPolje, 0, len(nova)+len(stara))
+	for _, in := range nova {
+		p, err := normalizeFormaPolje(in)
+		if err != nil {
+			return nil, err
+		}
+		if p.ID == "" {
+			p.ID = formaPoljeIDPrefiks + strconv.Itoa(next)
+			next++
+		} else {
+			old, ok := byID[p.ID]
+			if !ok || used[p.ID] {
+				return nil, fmt.Errorf("%w: nepoznato pitanje %q", ErrFormaNevazeca, p.ID)
+			}
+			if old.Tip != p.Tip && odgovorena[p.ID] {
+				return nil, fmt.Errorf("%w (%s)", ErrFormaTipPromenjen, p.Label)
+			}
+		}
+		used[p.ID] = true
+		out = append(out, p)
+	}
+	for _, old := range stara {
+		if !used[old.ID] && odgovorena[old.ID] {
+			old.Arhivirano = true
+			out = append(out, old)
+		}
+	}
+	return out, nil
+}
+
+// AkcijaFormaNepotpuniKorisnici vraća učesnike aktivnih prijava kojima nedostaje odgovor na obavezno pitanje forme.
+func AkcijaFormaNepotpuniKorisnici(db *gorm.DB, akcijaID uint, polja []FormaPolje) []uint {
+	type row struct {
+		KorisnikID uint
+		Odgovori   *string
+	}
+	var rows []row
+	_ = db.Table("prijave").
+		Select("prijave.korisnik_id, prijava_izbori.odgovori").
+		Joins("LEFT JOIN prijava_izbori ON prijava_izbori.prijava_id = prijave.id").
+		Where("prijave.akcija_id = ? AND prijave.status IN ?", akcijaID, PrijavaActiveStatuses).
+		Order("prijave.id").
+		Scan(&rows).Error
+	out := make([]uint, 0)
+	for _, r := range rows {
+		raw := ""
+		if r.Odgovori != nil {
+			raw = *r.Odgovori
+		}
+		if FormaOdgovoriNepotpuni(polja, ParseFormaOdgovori(raw)) {
+			out = append(out, r.KorisnikID)
+		}
+	}
+	return out
+}
+
+// AkcijaFormaOdgovorenaPolja vraća id polja na koja postoji bar jedan odgovor (prijave i zahtevi na čekanju).
+func AkcijaFormaOdgovorenaPolja(db *gorm.DB, akcijaID uint) map[string]bool {
+	out := map[string]bool{}
+	var raw []string
+	_ = db.Table("prijava_izbori").
+		Joins("JOIN prijave ON prijave.id = prijava_izbori.prijava_id").
+		Where("prijave.akcija_id = ? AND prijava_izbori.odgovori IS NOT NULL AND prijava_izbori.odgovori <> ''", akcijaID).
+		Pluck("prijava_izbori.odgovori", &raw).Error
+	var pending []string
+	_ = db.Model(&models.ActionSignupRequest{}).
+		Where("akcija_id = ? AND status = ? AND odgovori IS NOT NULL AND odgovori <> ''", akcijaID, models.ActionSignupRequestPending).
+		Pluck("odgovori", &pending).Error
+	for _, r := range append(raw, pending...) {
+		for id, v := range ParseFormaOdgovori(r) {
+			if strings.TrimSpace(v) != "" {
+				out[id] = true
+			}
+		}
+	}
+	return out
+}
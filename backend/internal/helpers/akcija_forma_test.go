@@ -0,0 +1,66 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+
+	"beleg-app/backend/internal/models"
+)
+
+func TestMergeFormaPolja_IDsArchiveAndTypeChange(t *testing.T) {
+	polja, err := MergeFormaPolja(nil, []FormaPolje{
+		{Label: " Veličina majice ", Tip: "choice", Opcije: []string{"S", "M", "M", " "}, Obavezno: true},
+		{Label: "Godine", Tip: "number"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polja[0].ID != "p1" || polja[1].ID != "p2" || polja[0].Label != "Veličina majice" || len(polja[0].Opcije) != 2 {
+		t.Fatalf("normalized fields: %+v", polja)
+	}
+
+	odgovorena := map[string]bool{"p1": true}
+	if _, err := MergeFormaPolja(polja, []FormaPolje{{ID: "p1", Label: "Majica", Tip: "text"}}, odgovorena); !errors.Is(err, ErrFormaTipPromenjen) {
+		t.Fatalf("type change of answered field, got %v", err)
+	}
+	if _, err := MergeFormaPolja(polja, []FormaPolje{{ID: "p9", Label: "X", Tip: "text"}}, odgovorena); !errors.Is(err, ErrFormaNevazeca) {
+		t.Fatalf("unknown field id, got %v", err)
+	}
+	next, err := MergeFormaPolja(polja, []FormaPolje{{ID: "p2", Label: "Godine", Tip: "text"}, {Label: "Alergije", Tip: "text"}}, odgovorena)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next) != 3 || next[1].ID != "p3" || next[2].ID != "p1" || !next[2].Arhivirano {
+		t.Fatalf("answered field must be archived, unanswered retyped: %+v", next)
+	}
+	if len(AktivnaFormaPolja(next)) != 2 {
+		t.Fatal("archived field must not be active")
+	}
+}
+
+func TestValidateFormaOdgovori(t *testing.T) {
+	polja := []FormaPolje{
+		{ID: "p1", Label: "Majica", Tip: models.FormaPoljeTipChoice, Opcije: []string{"S", "M"}, Obavezno: true},
+		{ID: "p2", Label: "Godine", Tip: models.FormaPoljeTipNumber},
+		{ID: "p3", Label: "Staro", Tip: models.FormaPoljeTipText, Obavezno: true, Arhivirano: true},
+	}
+	if _, err := ValidateFormaOdgovori(polja, map[string]string{"p2": "30"}); !errors.Is(err, ErrFormaOdgovor) {
+		t.Fatalf("missing required answer, got %v", err)
+	}
+	if _, err := ValidateFormaOdgovori(polja, map[string]string{"p1": "XL"}); !errors.Is(err, ErrFormaOdgovor) {
+		t.Fatalf("unknown option, got %v", err)
+	}
+	if _, err := ValidateFormaOdgovori(polja, map[string]string{"p1": "S", "p2": "trideset"}); !errors.Is(err, ErrFormaOdgovor) {
+		t.Fatalf("non-number, got %v", err)
+	}
+	got, err := ValidateFormaOdgovori(polja, map[string]string{"p1": " M ", "p2": "1,50", "p3": "x", "nepoznato": "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["p1"] != "M" || got["p2"] != "1.5" {
+		t.Fatalf("cleaned answers: %v", got)
+	}
+	if FormaOdgovoriNepotpuni(polja, got) || !FormaOdgovoriNepotpuni(polja, map[string]string{}) {
+		t.Fatal("required check must ignore archived fields")
+	}
+}
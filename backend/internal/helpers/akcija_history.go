@@ -0,0 +1,366 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PenaltyFreeWithdrawalWindow — posle poskupljenja učesnik može da odustane bez penala ovoliko dugo (najkasnije do polaska).
+const PenaltyFreeWithdrawalWindow = 7 * 24 * time.Hour
+
+// AkcijaSnapshot je stanje akcije i njenih ponuda u jednoj verziji istorije (bez lifecycle polja).
+type AkcijaSnapshot struct {
+	Naziv                    string                    `json:"naziv"`
+	Planina                  string                    `json:"planina"`
+	Vrh                      string                    `json:"vrh"`
+	PeakID                   *uint                     `json:"peakId"`
+	TipAkcije                string                    `json:"tipAkcije"`
+	FerrataID                *uint                     `json:"ferrataId"`
+	Datum                    time.Time                 `json:"datum"`
+	StartAt                  *time.Time                `json:"startAt"`
+	EndAt                    *time.Time                `json:"endAt"`
+	TrajanjeSati             float64                   `json:"trajanjeSati"`
+	BrojDana                 int                       `json:"brojDana"`
+	RokPrijava               *time.Time                `json:"rokPrijava"`
+	Opis                     string                    `json:"opis"`
+	Tezina                   string                    `json:"tezina"`
+	UkupnoKmAkcija           float64                   `json:"duzinaStazeKm"`
+	UkupnoMetaraUsponaAkcija int                       `json:"kumulativniUsponM"`
+	VisinaVrhM               int                       `json:"visinaVrhM"`
+	ZimskiUspon              bool                      `json:"zimskiUspon"`
+	VodicID                  uint                      `json:"vodicId"`
+	DrugiVodicIme            string                    `json:"drugiVodicIme"`
+	MestoPolaska             string                    `json:"mestoPolaska"`
+	KontaktTelefon           string                    `json:"kontaktTelefon"`
+	MaxLjudi                 int                       `json:"maxLjudi"`
+	CenaClan                 float64                   `json:"cenaClan"`
+	CenaOstali               float64                   `json:"cenaOstali"`
+	Javna                    bool                      `json:"javna"`
+	UIstorijiKluba           bool                      `json:"uIstorijiKluba"`
+	PrikaziListuPrijavljenih bool                      `json:"prikaziListuPrijavljenih"`
+	OmoguciGrupniChat        bool                      `json:"omoguciGrupniChat"`
+	SlikaURL                 string                    `json:"slikaUrl"`
+	Smestaj                  []models.AkcijaSmestaj    `json:"smestaj"`
+	Prevoz                   []models.AkcijaPrevoz     `json:"prevoz"`
+	Oprema                   []models.AkcijaOprema     `json:"oprema"`
+	Rent                     []models.AkcijaOpremaRent `json:"rent"`
+}
+
+// AkcijaPromena je jedno izmenjeno polje; ugnježdene stavke imaju ključ npr. "smestaj[12].cenaPoOsobiUkupno".
+type AkcijaPromena struct {
+	Polje string `json:"polje"`
+	Staro string `json:"staro"`
+	Novo  string `json:"novo"`
+}
+
+// AkcijaIzmena je ishod upisa verzije u istoriju.
+type AkcijaIzmena struct {
+	Verzija *models.AkcijaVerzija
+	Promene []AkcijaPromena
+	Posle   *AkcijaSnapshot
+}
+
+// LoadAkcijaSnapshotTx čita trenutno stanje akcije i ponuda (sortirano po ID radi stabilnog diff-a).
+func LoadAkcijaSnapshotTx(tx *gorm.DB, akcijaID uint) (*AkcijaSnapshot, error) {
+	var a models.Akcija
+	if err := tx.First(&a, akcijaID).Error; err != nil {
+		return nil, err
+	}
+	s := &AkcijaSnapshot{
+		Naziv: a.Naziv, Planina: a.Planina, Vrh: a.Vrh, PeakID: a.PeakID, TipAkcije: a.TipAkcije, FerrataID: a.FerrataID,
+		Datum: a.Datum.UTC(), StartAt: utcPtr(a.StartAt), EndAt: utcPtr(a.EndAt), TrajanjeSati: a.TrajanjeSati,
+		BrojDana: a.BrojDana, RokPrijava: utcPtr(a.RokPrijava), Opis: a.Opis, Tezina: a.Tezina,
+		UkupnoKmAkcija: a.UkupnoKmAkcija, UkupnoMetaraUsponaAkcija: a.UkupnoMetaraUsponaAkcija, VisinaVrhM: a.VisinaVrhM,
+		ZimskiUspon: a.ZimskiUspon, VodicID: a.VodicID, DrugiVodicIme: a.DrugiVodicIme, MestoPolaska: a.MestoPolaska,
+		KontaktTelefon: a.KontaktTelefon, MaxLjudi: a.MaxLjudi, CenaClan: a.CenaClan, CenaOstali: a.CenaOstali,
+		Javna: a.Javna, UIstorijiKluba: a.UIstorijiKluba, PrikaziListuPrijavljenih: a.PrikaziListuPrijavljenih,
+		OmoguciGrupniChat: a.OmoguciGrupniChat, SlikaURL: a.SlikaURL,
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Order("id").Find(&s.Smestaj).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Order("id").Find(&s.Prevoz).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Order("id").Find(&s.Oprema).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("akcija_id = ?", akcijaID).Order("id").Find(&s.Rent).Error; err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.UTC()
+	return &v
+}
+
+// flattenJSONValue pretvara JSON vrednost u string za poređenje i prikaz (null → "").
+func flattenJSONValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		b, _ := json.Marshal(x)
+		return string(b)
+	}
+}
+
+// flattenSnapshot vraća ravnu mapu polje → vrednost; stavke ponuda su ključevane ID-jem.
+func flattenSnapshot(s *AkcijaSnapshot) map[string]string {
+	out := map[string]string{}
+	if s == nil {
+		return out
+	}
+	raw, _ := json.Marshal(s)
+	var m map[string]any
+	_ = json.Unmarshal(raw, &m)
+	for k, v := range m {
+		items, isList := v.([]any)
+		if !isList {
+			out[k] = flattenJSONValue(v)
+			continue
+		}
+		for _, it := range items {
+			fields, _ := it.(map[string]any)
+			id := flattenJSONValue(fields["id"])
+			for fk, fv := range fields {
+				if fk == "id" || fk == "akcijaId" {
+					continue
+				}
+				out[fmt.Sprintf("%s[%s].%s", k, id, fk)] = flattenJSONValue(fv)
+			}
+		}
+	}
+	return out
+}
+
+// DiffAkcijaSnapshots vraća izmenjena polja sortirana po ključu; nil before znači da nema prethodnog stanja.
+func DiffAkcijaSnapshots(before, after *AkcijaSnapshot) []AkcijaPromena {
+	if before == nil || after == nil {
+		return nil
+	}
+	old, cur := flattenSnapshot(before), flattenSnapshot(after)
+	keys := map[string]struct{}{}
+	for k := range old {
+		keys[k] = struct{}{}
+	}
+	for k := range cur {
+		keys[k] = struct{}{}
+	}
+	var out []AkcijaPromena
+	for k := range keys {
+		if old[k] != cur[k] {
+			out = append(out, AkcijaPromena{Polje: k, Staro: old[k], Novo: cur[k]})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Polje < out[j].Polje })
+	return out
+}
+
+// materialFields su izmene o kojima se obaveštavaju potvrđeni učesnici.
+var materialFields = map[string]string{
+	"datum":        "datum",
+	"startAt":      "polazak",
+	"endAt":        "povratak",
+	"brojDana":     "broj dana",
+	"cenaClan":     "cena za članove",
+	"cenaOstali":   "cena za ostale",
+	"mestoPolaska": "mesto polaska",
+	"tezina":       "težina",
+}
+
+// nestedPriceFields — cena stavke ponude (smeštaj/prevoz/najam opreme) je takođe materijalna.
+var nestedPriceFields = map[string]string{
+	"smestaj.cenaPoOsobiUkupno": "smeštaj",
+	"prevoz.cenaPoOsobi":        "prevoz",
+	"rent.cenaPoSetu":           "najam opreme",
+}
+
+// nestedPriceKey vraća "smestaj.cenaPoOsobiUkupno" i ID stavke za ključ "smestaj[12].cenaPoOsobiUkupno".
+func nestedPriceKey(polje string) (string, string, bool) {
+	open, closeIdx := strings.Index(polje, "["), strings.Index(polje, "]")
+	if open < 0 || closeIdx < open {
+		return "", "", false
+	}
+	key := polje[:open] + polje[closeIdx+1:]
+	if _, ok := nestedPriceFields[key]; !ok {
+		return "", "", false
+	}
+	return key, polje[open+1 : closeIdx], true
+}
+
+// IsMaterialPromena: datum/termin, cena (i cene stavki koje postoje pre i posle), mesto polaska ili težina.
+func IsMaterialPromena(p AkcijaPromena) bool {
+	if _, ok := materialFields[p.Polje]; ok {
+		return true
+	}
+	_, _, ok := nestedPriceKey(p.Polje)
+	return ok && p.Staro != "" && p.Novo != ""
+}
+
+// IsPriceIncrease: cena akcije ili postojeće stavke ponude je porasla.
+func IsPriceIncrease(p AkcijaPromena) bool {
+	if p.Polje != "cenaClan" && p.Polje != "cenaOstali" {
+		if _, _, ok := nestedPriceKey(p.Polje); !ok {
+			return false
+		}
+	}
+	oldV, err1 := strconv.ParseFloat(p.Staro, 64)
+	newV, err2 := strconv.ParseFloat(p.Novo, 64)
+	return err1 == nil && err2 == nil && newV > oldV
+}
+
+// RecordAkcijaVerzijaTx upisuje novu verziju ako se stanje promenilo u odnosu na before
+// (before == nil: početna verzija bez razlike). Pozivalac drži lock akcije; nil rezultat znači da nema izmena.
+func RecordAkcijaVerzijaTx(tx *gorm.DB, akcijaID uint, before *AkcijaSnapshot, izvor string, actorID uint) (*AkcijaIzmena, error) {
+	after, err := LoadAkcijaSnapshotTx(tx, akcijaID)
+	if err != nil {
+		return nil, err
+	}
+	promene := DiffAkcijaSnapshots(before, after)
+	if before != nil && len(promene) == 0 {
+		return nil, nil
+	}
+	var last int
+	if err := tx.Model(&models.AkcijaVerzija{}).Where("akcija_id = ?", akcijaID).
+		Select("COALESCE(MAX(verzija), 0)").Scan(&last).Error; err != nil {
+		return nil, err
+	}
+	snapshotJSON, err := json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+	if promene == nil {
+		promene = []AkcijaPromena{}
+	}
+	promeneJSON, err := json.Marshal(promene)
+	if err != nil {
+		return nil, err
+	}
+	v := models.AkcijaVerzija{
+		AkcijaID:   akcijaID,
+		Verzija:    last + 1,
+		Izvor:      izvor,
+		Snapshot:   string(snapshotJSON),
+		Promene:    string(promeneJSON),
+		PromenioID: actorID,
+	}
+	for _, p := range promene {
+		v.Materijalna = v.Materijalna || IsMaterialPromena(p)
+		v.PoskupljenjeCene = v.PoskupljenjeCene || IsPriceIncrease(p)
+	}
+	if err := tx.Create(&v).Error; err != nil {
+		return nil, err
+	}
+	return &AkcijaIzmena{Verzija: &v, Promene: promene, Posle: after}, nil
+}
+
+// ParseAkcijaPromene čita Promene iz zapisa verzije (prazno pri neispravnom JSON-u).
+func ParseAkcijaPromene(v *models.AkcijaVerzija) []AkcijaPromena {
+	out := []AkcijaPromena{}
+	if v != nil {
+		_ = json.Unmarshal([]byte(v.Promene), &out)
+	}
+	return out
+}
+
+// formatPromenaValue prikazuje vrednost za obaveštenje: vreme po beogradskom, prazno kao „—”.
+func formatPromenaValue(polje, v string) string {
+	if v == "" {
+		return "—"
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		if polje == "datum" {
+			return t.UTC().Format("02.01.2006.")
+		}
+		return t.In(belgradeLocation()).Format("02.01.2006. 15:04")
+	}
+	return v
+}
+
+// SummarizeMaterialPromene gradi kratak opis materijalnih izmena za obaveštenje učesnicima.
+func SummarizeMaterialPromene(promene []AkcijaPromena, after *AkcijaSnapshot) string {
+	names := map[string]string{}
+	if after != nil {
+		for _, s := range after.Smestaj {
+			names["smestaj."+strconv.FormatUint(uint64(s.ID), 10)] = s.Naziv
+		}
+		for _, p := range after.Prevoz {
+			names["prevoz."+strconv.FormatUint(uint64(p.ID), 10)] = p.NazivGrupe
+		}
+		for _, r := range after.Rent {
+			names["rent."+strconv.FormatUint(uint64(r.ID), 10)] = r.NazivOpreme
+		}
+	}
+	var parts []string
+	for _, p := range promene {
+		if !IsMaterialPromena(p) {
+			continue
+		}
+		label, ok := materialFields[p.Polje]
+		if !ok {
+			key, id, _ := nestedPriceKey(p.Polje)
+			section := key[:strings.Index(key, ".")]
+			label = nestedPriceFields[key] + " — cena"
+			if n := strings.TrimSpace(names[section+"."+id]); n != "" {
+				label = fmt.Sprintf("%s „%s” — cena", nestedPriceFields[key], n)
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s → %s", label, formatPromenaValue(p.Polje, p.Staro), formatPromenaValue(p.Polje, p.Novo)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// PenaltyFreeWithdrawalUntil — rok za odustajanje bez penala: PenaltyFreeWithdrawalWindow, najkasnije do početka akcije.
+func PenaltyFreeWithdrawalUntil(akcija *models.Akcija, now time.Time) time.Time {
+	until := now.Add(PenaltyFreeWithdrawalWindow)
+	start := akcija.Datum
+	if akcija.StartAt != nil {
+		start = *akcija.StartAt
+	}
+	if start.Before(until) {
+		until = start
+	}
+	return until.UTC()
+}
+
+// GrantPenaltyFreeWithdrawalTx daje prijavljenim učesnicima (osim vodiča) pravo da odustanu bez penala do until.
+func GrantPenaltyFreeWithdrawalTx(tx *gorm.DB, akcija *models.Akcija, until time.Time) (int64, error) {
+	res := tx.Model(&models.Prijava{}).
+		Where("akcija_id = ? AND status = ? AND korisnik_id <> ?", akcija.ID, PrijavaStatusPrijavljen, akcija.VodicID).
+		Update("bez_penala_do", until)
+	return res.RowsAffected, res.Error
+}
+
+// HasPenaltyFreeWithdrawal: prijava je posle poskupljenja i rok za odustajanje bez penala još traje.
+func HasPenaltyFreeWithdrawal(p *models.Prijava, now time.Time) bool {
+	return p != nil && p.BezPenalaDo != nil && now.Before(*p.BezPenalaDo)
+}
+
+// ConfirmedParticipantIDs vraća korisnike sa statusom prijavljen (bez excludeID).
+func ConfirmedParticipantIDs(db *gorm.DB, akcijaID, excludeID uint) ([]uint, error) {
+	var ids []uint
+	err := db.Model(&models.Prijava{}).
+		Where("akcija_id = ? AND status = ? AND korisnik_id <> ?", akcijaID, PrijavaStatusPrijavljen, excludeID).
+		Distinct().Order("korisnik_id").Pluck("korisnik_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	"testing"
+
+	"beleg-app/backend/internal/models"
+)
+
+func TestDiffAkcijaSnapshots_MaterialAndPriceIncrease(t *testing.T) {
+	before := &AkcijaSnapshot{Naziv: "Trem", CenaClan: 30, Tezina: "lako",
+		Smestaj: []models.AkcijaSmestaj{{ID: 4, Naziv: "Dom", CenaPoOsobiUkupno: 20}}}
+	after := &AkcijaSnapshot{Naziv: "Trem", CenaClan: 25, Tezina: "srednje", Opis: "x",
+		Smestaj: []models.AkcijaSmestaj{{ID: 4, Naziv: "Dom", CenaPoOsobiUkupno: 25}}}
+
+	promene := DiffAkcijaSnapshots(before, after)
+	byField := map[string]AkcijaPromena{}
+	for _, p := range promene {
+		byField[p.Polje] = p
+	}
+	if len(promene) != 4 {
+		t.Fatalf("diff: %+v", promene)
+	}
+	nested := byField["smestaj[4].cenaPoOsobiUkupno"]
+	if !IsMaterialPromena(nested) || !IsPriceIncrease(nested) {
+		t.Fatalf("nested price increase: %+v", nested)
+	}
+	if p := byField["cenaClan"]; !IsMaterialPromena(p) || IsPriceIncrease(p) {
+		t.Fatalf("price drop is material but not an increase: %+v", p)
+	}
+	if !IsMaterialPromena(byField["tezina"]) || IsMaterialPromena(byField["opis"]) {
+		t.Fatal("tezina is material, opis is not")
+	}
+	summary := SummarizeMaterialPromene(promene, after)
+	if summary != "cena za članove: 30 → 25; smeštaj „Dom” — cena: 20 → 25; težina: lako → srednje" {
+		t.Fatalf("summary: %q", summary)
+	}
+}
+
+func TestIsMaterialPromena_NewNestedItemIsNotMaterial(t *testing.T) {
+	p := AkcijaPromena{Polje: "prevoz[9].cenaPoOsobi", Staro: "", Novo: "15"}
+	if IsMaterialPromena(p) || IsPriceIncrease(p) {
+		t.Fatal("a newly added offer does not change what confirmed participants pay")
+	}
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrAkcijaNotPublished — nacrt, akcija na pregledu ili zakazana akcija još ne prima prijave.
+var ErrAkcijaNotPublished = errors.New("Akcija još nije objavljena.")
+
+// SQLAkcijaObjavljena je uslov za liste, pretragu, mapu i sync: samo objavljene akcije.
+const SQLAkcijaObjavljena = "(status_objave IS NULL OR status_objave = '' OR status_objave = 'objavljena')"
+
+// IsAkcijaObjavljena: prazan status (stari redovi pre migracije) se tretira kao objavljen.
+func IsAkcijaObjavljena(akcija *models.Akcija) bool {
+	if akcija == nil {
+		return false
+	}
+	s := strings.TrimSpace(akcija.StatusObjave)
+	return s == "" || s == models.AkcijaStatusObjavljena
+}
+
+// ValidateAkcijaPublished odbija prijavu člana na akciju koja još nije objavljena.
+func ValidateAkcijaPublished(akcija *models.Akcija) error {
+	if !IsAkcijaObjavljena(akcija) {
+		return ErrAkcijaNotPublished
+	}
+	return nil
+}
+
+// AkcijaRequiresClubReview: klupska akcija koju objavljuje vodič (uloga vodic) čeka admina
+// kada klub ima uključen PregledAkcijaVodica. Admini i superadmin objavljuju direktno.
+func AkcijaRequiresClubReview(db *gorm.DB, akcija *models.Akcija, publisherRole string) bool {
+	if akcija == nil || akcija.KlubID == nil || *akcija.KlubID == 0 || publisherRole != "vodic" {
+		return false
+	}
+	if strings.EqualFold(strings.TrimSpace(akcija.OrganizatorTip), "vodic") {
+		return false
+	}
+	var klub models.Klubovi
+	if err := db.Select("id", "pregled_akcija_vodica").First(&klub, *akcija.KlubID).Error; err != nil {
+		return false
+	}
+	return klub.PregledAkcijaVodica
+}
+
+// ClubActionReviewerIDs vraća admine kluba koji odobravaju akcije vodiča.
+func ClubActionReviewerIDs(db *gorm.DB, klubID uint) ([]uint, error) {
+	var ids []uint
+	err := db.Model(&models.Korisnik{}).Where("klub_id = ? AND role = ?", klubID, "admin").
+		Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ResolvePublishStatus određuje status posle zahteva za objavljivanje:
+// pregled admina ima prednost, zatim zakazano vreme u budućnosti, inače odmah objavljena.
+func ResolvePublishStatus(needsReview bool, objaviAt *time.Time, now time.Time) string {
+	if needsReview {
+		return models.AkcijaStatusNaPregledu
+	}
+	if objaviAt != nil && objaviAt.After(now) {
+		return models.AkcijaStatusZakazana
+	}
+	return models.AkcijaStatusObjavljena
+}
@@ -0,0 +1,198 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TezinaNivoi su težine planinarskih akcija od najlakše (via ferrata ima sopstvenu skalu i ne ulazi u MinTezina).
+var TezinaNivoi = []string{"lako", "srednje", "tesko", "alpinizam"}
+
+// ErrUsloviNisuIspunjeni: član ne ispunjava uslove akcije i nema izuzetak organizatora.
+var ErrUsloviNisuIspunjeni = errors.New("Ne ispunjavate uslove za prijavu na ovu akciju")
+
+// UslovNeispunjen je jedan neispunjen uslov sa objašnjenjem za člana.
+type UslovNeispunjen struct {
+	Uslov  string `json:"uslov"` // minBrojAkcija, minVisinaM, clanarinaPlacena, sertifikat, minGodine
+	Poruka string `json:"poruka"`
+}
+
+// UsloviNisuIspunjeniError nosi razloge; errors.Is(err, ErrUsloviNisuIspunjeni) je true.
+type UsloviNisuIspunjeniError struct {
+	Razlozi []UslovNeispunjen
+}
+
+func (e *UsloviNisuIspunjeniError) Error() string {
+	poruke := make([]string, 0, len(e.Razlozi))
+	for _, r := range e.Razlozi {
+		poruke = append(poruke, r.Poruka)
+	}
+	return ErrUsloviNisuIspunjeni.Error() + ": " + strings.Join(poruke, "; ")
+}
+
+func (e *UsloviNisuIspunjeniError) Unwrap() error { return ErrUsloviNisuIspunjeni }
+
+// TezineOdNivoa vraća težine jednake ili veće od min; nepoznat nivo je nil.
+func TezineOdNivoa(min string) []string {
+	for i, t := range TezinaNivoi {
+		if t == min {
+			return TezinaNivoi[i:]
+		}
+	}
+	return nil
+}
+
+// ParseUsloviSertifikati čita listu potrebnih sertifikata.
+func ParseUsloviSertifikati(raw string) []string {
+	out := []string{}
+	if strings.TrimSpace(raw) != "" {
+		_ = json.Unmarshal([]byte(raw), &out)
+	}
+	if out == nil {
+		out = []string{}
+	}
+	return out
+}
+
+// AkcijaUsloviPrazni: nijedan uslov nije postavljen.
+func AkcijaUsloviPrazni(u *models.AkcijaUslovi) bool {
+	return u == nil || (u.MinBrojAkcija <= 0 && u.MinVisinaM <= 0 && !u.ClanarinaPlacena &&
+		u.MinGodine <= 0 && len(ParseUsloviSertifikati(u.Sertifikati)) == 0)
+}
+
+// LoadAkcijaUslovi vraća uslove akcije; nil kada ih nema.
+func LoadAkcijaUslovi(db *gorm.DB, akcijaID uint) *models.AkcijaUslovi {
+	var u models.AkcijaUslovi
+	if err := db.Where("akcija_id = ?", akcijaID).First(&u).Error; err != nil {
+		return nil
+	}
+	return &u
+}
+
+// HasAkcijaUslovIzuzetak: organizator je dozvolio članu prijavu bez obzira na uslove.
+func HasAkcijaUslovIzuzetak(db *gorm.DB, akcijaID, korisnikID uint) bool {
+	var n int64
+	if err := db.Model(&models.AkcijaUslovIzuzetak{}).Where("akcija_id = ? AND korisnik_id = ?", akcijaID, korisnikID).Count(&n).Error; err != nil {
+		return false
+	}
+	return n > 0
+}
+
+// uzrastNaDan vraća pune godine na dati dan.
+func uzrastNaDan(rodjen, dan time.Time) int {
+	godine := dan.Year() - rodjen.Year()
+	if dan.Month() < rodjen.Month() || (dan.Month() == rodjen.Month() && dan.Day() < rodjen.Day()) {
+		godine--
+	}
+	return godine
+}
+
+// ProveriUsloveAkcije vraća neispunjene uslove akcije za korisnika (prazno = ispunjava). Izuzetak se ne gleda.
+func ProveriUsloveAkcije(db *gorm.DB, akcija *models.Akcija, u *models.AkcijaUslovi, korisnik *models.Korisnik) []UslovNeispunjen {
+	out := []UslovNeispunjen{}
+	if AkcijaUsloviPrazni(u) || akcija == nil || korisnik == nil {
+		return out
+	}
+	if u.MinBrojAkcija > 0 {
+		q := db.Table("prijave").
+			Joins("JOIN akcije ON akcije.id = prijave.akcija_id").
+			Where("prijave.korisnik_id = ? AND prijave.status = ? AND akcije.id <> ?", korisnik.ID, "popeo se", akcija.ID)
+		opis := "završenih akcija"
+		if u.TipAkcije != "" {
+			q = q.Where("akcije.tip_akcije = ?", u.TipAkcije)
+			if u.TipAkcije == "via_ferrata" {
+				opis = "završenih via ferrata akcija"
+			} else {
+				opis = "završenih planinarskih akcija"
+			}
+		}
+		if u.MinTezina != "" {
+			q = q.Where("akcije.tezina IN ?", TezineOdNivoa(u.MinTezina))
+			opis += " težine „" + u.MinTezina + "” ili teže"
+		}
+		var n int64
+		_ = q.Count(&n).Error
+		if int(n) < u.MinBrojAkcija {
+			out = append(out, UslovNeispunjen{
+				Uslov:  "minBrojAkcija",
+				Poruka: fmt.Sprintf("Potrebno je najmanje %d %s (imate %d).", u.MinBrojAkcija, opis, n),
+			})
+		}
+	}
+	if u.MinVisinaM > 0 {
+		var maxVisina int
+		_ = db.Table("prijave").
+			Joins("JOIN akcije ON akcije.id = prijave.akcija_id").
+			Where("prijave.korisnik_id = ? AND prijave.status = ?", korisnik.ID, "popeo se").
+			Select("COALESCE(MAX(akcije.visina_vrh_m), 0)").
+			Scan(&maxVisina).Error
+		if maxVisina < u.MinVisinaM {
+			out = append(out, UslovNeispunjen{
+				Uslov:  "minVisinaM",
+				Poruka: fmt.Sprintf("Potreban je uspon na vrh od najmanje %d m (vaš najviši: %d m).", u.MinVisinaM, maxVisina),
+			})
+		}
+	}
+	if u.ClanarinaPlacena {
+		godina := akcija.Datum.Year()
+		if placena, _ := ClanarinaPlacenaZaGodinu(db, korisnik.ID, godina); !placena {
+			out = append(out, UslovNeispunjen{
+				Uslov:  "clanarinaPlacena",
+				Poruka: fmt.Sprintf("Potrebna je plaćena članarina za %d. godinu.", godina),
+			})
+		}
+	}
+	if potrebni := ParseUsloviSertifikati(u.Sertifikati); len(potrebni) > 0 {
+		var imaju []models.KorisnikSertifikat
+		_ = db.Where("korisnik_id = ? AND (vazi_do IS NULL OR vazi_do >= ?)", korisnik.ID, akcija.Datum).Find(&imaju).Error
+		vazeci := map[string]bool{}
+		for _, s := range imaju {
+			vazeci[strings.ToLower(strings.TrimSpace(s.Naziv))] = true
+		}
+		for _, naziv := range potrebni {
+			if !vazeci[strings.ToLower(strings.TrimSpace(naziv))] {
+				out = append(out, UslovNeispunjen{
+					Uslov:  "sertifikat",
+					Poruka: "Potreban je važeći sertifikat: " + naziv + ".",
+				})
+			}
+		}
+	}
+	if u.MinGodine > 0 {
+		if korisnik.DatumRodjenja == nil {
+			out = append(out, UslovNeispunjen{
+				Uslov:  "minGodine",
+				Poruka: fmt.Sprintf("Potrebno je najmanje %d godina; unesite datum rođenja u profilu.", u.MinGodine),
+			})
+		} else if uzrastNaDan(*korisnik.DatumRodjenja, akcija.Datum) < u.MinGodine {
+			out = append(out, UslovNeispunjen{
+				Uslov:  "minGodine",
+				Poruka: fmt.Sprintf("Potrebno je najmanje %d godina na dan akcije.", u.MinGodine),
+			})
+		}
+	}
+	return out
+}
+
+// CheckAkcijaEligibilityTx proverava uslove akcije pri slanju i prihvatanju zahteva za prijavu;
+// izuzetak organizatora preskače proveru. Vraća *UsloviNisuIspunjeniError.
+func CheckAkcijaEligibilityTx(tx *gorm.DB, akcija *models.Akcija, korisnik *models.Korisnik) error {
+	if akcija == nil || korisnik == nil {
+		return nil
+	}
+	u := LoadAkcijaUslovi(tx, akcija.ID)
+	if AkcijaUsloviPrazni(u) || HasAkcijaUslovIzuzetak(tx, akcija.ID, korisnik.ID) {
+		return nil
+	}
+	if razlozi := ProveriUsloveAkcije(tx, akcija, u, korisnik); len(razlozi) > 0 {
+		return &UsloviNisuIspunjeniError{Razlozi: razlozi}
+	}
+	return nil
+}
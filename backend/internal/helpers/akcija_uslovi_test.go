@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func testUsloviDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := testPrijavaDB(t)
+	if err := db.AutoMigrate(&models.AkcijaUslovi{}, &models.AkcijaUslovIzuzetak{}, &models.KorisnikSertifikat{}, &models.Transakcija{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func seedPopeoSe(t *testing.T, db *gorm.DB, korisnikID uint, tip, tezina string, visina int) {
+	t.Helper()
+	a := models.Akcija{Naziv: "Prošla", Datum: time.Now().AddDate(0, -2, 0), TipAkcije: tip, Tezina: tezina, VisinaVrhM: visina, IsCompleted: true}
+	if err := db.Create(&a).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Prijava{AkcijaID: a.ID, KorisnikID: korisnikID, Status: "popeo se"}).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckAkcijaEligibility_RulesReasonsAndOverride(t *testing.T) {
+	db := testUsloviDB(t)
+	datum := time.Date(2027, 7, 10, 6, 0, 0, 0, time.UTC)
+	akcija := models.Akcija{Naziv: "Mont Blanc", Datum: datum, TipAkcije: "planina", Tezina: "alpinizam"}
+	db.Create(&akcija)
+	rodjen := time.Date(2009, 7, 11, 0, 0, 0, 0, time.UTC) // 17 godina na dan akcije
+	clan := models.Korisnik{Username: "uslovi_clan", Password: "x", DatumRodjenja: &rodjen}
+	db.Create(&clan)
+	db.Create(&models.AkcijaUslovi{
+		AkcijaID: akcija.ID, MinBrojAkcija: 2, TipAkcije: "planina", MinTezina: "tesko", MinVisinaM: 2500,
+		ClanarinaPlacena: true, Sertifikati: `["Alpinistički tečaj"]`, MinGodine: 18,
+	})
+
+	err := CheckAkcijaEligibilityTx(db, &akcija, &clan)
+	var uslovi *UsloviNisuIspunjeniError
+	if !errors.As(err, &uslovi) || !errors.Is(err, ErrUsloviNisuIspunjeni) || len(uslovi.Razlozi) != 5 {
+		t.Fatalf("beginner must fail every rule, got %v", err)
+	}
+
+	seedPopeoSe(t, db, clan.ID, "planina", "tesko", 2656)
+	seedPopeoSe(t, db, clan.ID, "planina", "alpinizam", 2000)
+	seedPopeoSe(t, db, clan.ID, "planina", "lako", 1000)      // prelaka
+	seedPopeoSe(t, db, clan.ID, "via_ferrata", "tesko", 3000) // drugi tip
+	clanID := clan.ID
+	db.Create(&models.Transakcija{Tip: "uplata", Iznos: 3000, Datum: time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC), ClanarinaKorisnikID: &clanID})
+	istekao := datum.AddDate(0, 0, -1)
+	db.Create(&models.KorisnikSertifikat{KorisnikID: clan.ID, Naziv: "alpinistički tečaj", VaziDo: &istekao})
+
+	razlozi := ProveriUsloveAkcije(db, &akcija, LoadAkcijaUslovi(db, akcija.ID), &clan)
+	if len(razlozi) != 2 || razlozi[0].Uslov != "sertifikat" || razlozi[1].Uslov != "minGodine" {
+		t.Fatalf("expired certificate and age must remain: %+v", razlozi)
+	}
+	db.Create(&models.KorisnikSertifikat{KorisnikID: clan.ID, Naziv: "Alpinistički tečaj"})
+	rodjen = rodjen.AddDate(0, 0, -1)
+	clan.DatumRodjenja = &rodjen
+	if err := CheckAkcijaEligibilityTx(db, &akcija, &clan); err != nil {
+		t.Fatalf("qualified member: %v", err)
+	}
+
+	mlad := models.Korisnik{Username: "uslovi_mlad", Password: "x"}
+	db.Create(&mlad)
+	if err := CheckAkcijaEligibilityTx(db, &akcija, &mlad); !errors.Is(err, ErrUsloviNisuIspunjeni) {
+		t.Fatalf("expected rejection, got %v", err)
+	}
+	db.Create(&models.AkcijaUslovIzuzetak{AkcijaID: akcija.ID, KorisnikID: mlad.ID, Napomena: "iskusan iz drugog kluba"})
+	if err := CheckAkcijaEligibilityTx(db, &akcija, &mlad); err != nil {
+		t.Fatalf("override must skip the rules: %v", err)
+	}
+}
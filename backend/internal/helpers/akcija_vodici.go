@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SQLAkcijaCoGuideIDs je podupit akcija na kojima je korisnik ko-vodič (parametar: korisnik_id).
+const SQLAkcijaCoGuideIDs = "SELECT akcija_id FROM akcija_vodici WHERE korisnik_id = ?"
+
+// IsValidAkcijaVodicUloga: ko-vodič je pomoćni vodič ili zatvarač; glavni je Akcija.VodicID.
+func IsValidAkcijaVodicUloga(uloga string) bool {
+	return uloga == models.AkcijaVodicUlogaPomocni || uloga == models.AkcijaVodicUlogaZatvarac
+}
+
+// IsAkcijaCoGuide: korisnik je dodeljen akciji kao ko-vodič.
+func IsAkcijaCoGuide(db *gorm.DB, akcijaID, korisnikID uint) bool {
+	if akcijaID == 0 || korisnikID == 0 {
+		return false
+	}
+	var n int64
+	if err := db.Model(&models.AkcijaVodic{}).Where("akcija_id = ? AND korisnik_id = ?", akcijaID, korisnikID).Count(&n).Error; err != nil {
+		return false
+	}
+	return n > 0
+}
+
+// IsAkcijaGuide: glavni vodič ili ko-vodič akcije.
+func IsAkcijaGuide(db *gorm.DB, akcija *models.Akcija, korisnikID uint) bool {
+	if akcija == nil || korisnikID == 0 {
+		return false
+	}
+	if akcija.VodicID == korisnikID {
+		return true
+	}
+	return IsAkcijaCoGuide(db, akcija.ID, korisnikID)
+}
+
+// AkcijaCoGuides vraća ko-vodiče akcije redom dodavanja (sa učitanim korisnikom).
+func AkcijaCoGuides(db *gorm.DB, akcijaID uint) ([]models.AkcijaVodic, error) {
+	var rows []models.AkcijaVodic
+	err := db.Preload("Korisnik").Where("akcija_id = ?", akcijaID).Order("id").Find(&rows).Error
+	return rows, err
+}
+
+// AkcijaGuideIDs vraća glavnog vodiča pa ko-vodiče (bez duplikata i nula).
+func AkcijaGuideIDs(db *gorm.DB, akcija *models.Akcija) []uint {
+	if akcija == nil {
+		return nil
+	}
+	var ids []uint
+	if akcija.VodicID > 0 {
+		ids = append(ids, akcija.VodicID)
+	}
+	var co []uint
+	_ = db.Model(&models.AkcijaVodic{}).Where("akcija_id = ?", akcija.ID).Order("id").Pluck("korisnik_id", &co).Error
+	for _, id := range co {
+		if id != 0 && id != akcija.VodicID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// CanGuideAkcijaEx: ko upravlja akcijom (CanManageAkcijaEx) ili ko-vodič — odgovor na zahteve za prijavu,
+// statusi učesnika, završetak akcije i kontakt podaci učesnika. Izmena i brisanje akcije ostaju CanManageAkcijaEx.
+func CanGuideAkcijaEx(c *gin.Context, db *gorm.DB, ak *models.Akcija) bool {
+	if CanManageAkcijaEx(c, db, ak) {
+		return true
+	}
+	username, _ := c.Get("username")
+	var u models.Korisnik
+	if err := DBWhereUsername(db, UsernameFromContext(username)).Select("id").First(&u).Error; err != nil {
+		return false
+	}
+	return IsAkcijaCoGuide(db, ak.ID, u.ID)
+}
+
+// CreditGuidedToursTx pri završetku akcije uvećava BrojVodjenihTura svakom vodiču sa odobrenim profi profilom.
+func CreditGuidedToursTx(tx *gorm.DB, akcija *models.Akcija) error {
+	var ids []uint
+	for id := range ApprovedProfiGuideKorisnikIDs(tx, AkcijaGuideIDs(tx, akcija)) {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(&models.GuideProfile{}).
+		Where("korisnik_id IN ? AND status = ?", ids, models.GuideStatusApproved).
+		UpdateColumn("broj_vodjenih_tura", gorm.Expr("broj_vodjenih_tura + 1")).Error
+}
@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"context"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/weather"
+)
+
+// defaultTourHours — trajanje ture za prognozu kada organizator nije uneo TrajanjeSati.
+const defaultTourHours = 8
+
+// AkcijaPrognoza je prognoza za tačku akcije (na visini vrha) u toku same ture.
+type AkcijaPrognoza struct {
+	Izvor       string          `json:"izvor"`
+	VisinaM     float64         `json:"visinaM"`
+	Od          time.Time       `json:"od"`
+	Do          time.Time       `json:"do"`
+	Sati        []weather.Hour  `json:"sati"`
+	Upozorenja  []weather.Alert `json:"upozorenja"`
+	Osvezeno    time.Time       `json:"osvezeno"`
+	MaxUdariKmh float64         `json:"maxUdariKmh"`
+	PadavineMm  float64         `json:"padavineMm"`
+}
+
+// AkcijaTimeWindow vraća interval ture: polazak + trajanje, a bez satnice 07–19 h po beogradskom vremenu na dan akcije.
+func AkcijaTimeWindow(akcija *models.Akcija) (time.Time, time.Time) {
+	if akcija.StartAt != nil {
+		hours := akcija.TrajanjeSati
+		if hours <= 0 {
+			hours = defaultTourHours
+		}
+		from := akcija.StartAt.UTC()
+		to := from.Add(time.Duration(hours * float64(time.Hour)))
+		if akcija.EndAt != nil && akcija.EndAt.After(from) {
+			to = akcija.EndAt.UTC()
+		}
+		return from, to
+	}
+	y, m, d := akcija.Datum.UTC().Date()
+	loc := belgradeLocation()
+	return time.Date(y, m, d, 7, 0, 0, 0, loc).UTC(), time.Date(y, m, d, 19, 0, 0, 0, loc).UTC()
+}
+
+// AkcijaWeather vraća prognozu za akciju; nil bez greške kada akcija nema tačku ili je tura van opsega prognoze.
+func AkcijaWeather(ctx context.Context, provider weather.Provider, akcija *models.Akcija) (*AkcijaPrognoza, error) {
+	if provider == nil || akcija == nil || akcija.PlaninaLat == nil || akcija.PlaninaLng == nil {
+		return nil, nil
+	}
+	var elevation *float64
+	if akcija.VisinaVrhM > 0 {
+		v := float64(akcija.VisinaVrhM)
+		elevation = &v
+	}
+	f, err := provider.Forecast(ctx, *akcija.PlaninaLat, *akcija.PlaninaLng, elevation)
+	if err != nil {
+		return nil, err
+	}
+	from, to := AkcijaTimeWindow(akcija)
+	hours := f.Window(from, to)
+	if len(hours) == 0 {
+		return nil, nil
+	}
+	p := &AkcijaPrognoza{
+		Izvor: f.Izvor, VisinaM: f.ElevationM, Od: from, Do: to, Sati: hours,
+		Upozorenja: weather.Assess(hours, weather.DefaultThresholds), Osvezeno: time.Now().UTC().Truncate(time.Hour),
+	}
+	for _, h := range hours {
+		p.MaxUdariKmh = max(p.MaxUdariKmh, h.GustKmh)
+		p.PadavineMm += h.PrecipMm
+	}
+	return p, nil
+}
@@ -0,0 +1,203 @@
+package helpers
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"beleg-app/backend/internal/geo"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PeakSummitRadiusKm — GPS sesija „osvaja“ vrh ako je bar jedna tačka bliža od ovog radijusa.
+const PeakSummitRadiusKm = 0.15
+
+// RecordAkcijaPeakAscentTx upisuje uspon u dnevnik kada prijava na akciji vezanoj za vrh pređe u „popeo se“.
+// Idempotentno po (korisnik, akcija): drugi poziv ne pravi duplikat.
+func RecordAkcijaPeakAscentTx(tx *gorm.DB, akcija *models.Akcija, korisnikID uint) error {
+	if akcija == nil || akcija.ID == 0 || akcija.PeakID == nil || *akcija.PeakID == 0 || korisnikID == 0 {
+		return nil
+	}
+	var existing models.PeakAscent
+	err := tx.Where("korisnik_id = ? AND akcija_id = ?", korisnikID, akcija.ID).First(&existing).Error
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	akcijaID := akcija.ID
+	datum := akcija.Datum
+	if akcija.StartAt != nil {
+		datum = *akcija.StartAt
+	}
+	return tx.Create(&models.PeakAscent{
+		KorisnikID:  korisnikID,
+		PeakID:      *akcija.PeakID,
+		Datum:       datum,
+		Izvor:       models.PeakAscentIzvorAkcija,
+		AkcijaID:    &akcijaID,
+		ZimskiUspon: akcija.ZimskiUspon,
+	}).Error
+}
+
+// RemoveAkcijaPeakAscentTx briše uspon iz dnevnika kada se „popeo se“ vrati na drugi status.
+// Akcija bez vrha nema uspone (SyncAkcijaPeakAscentsTx ih briše pri uklanjanju veze).
+func RemoveAkcijaPeakAscentTx(tx *gorm.DB, akcija *models.Akcija, korisnikID uint) error {
+	if akcija == nil || akcija.ID == 0 || akcija.PeakID == nil || korisnikID == 0 {
+		return nil
+	}
+	return tx.Where("korisnik_id = ? AND akcija_id = ? AND izvor = ?", korisnikID, akcija.ID, models.PeakAscentIzvorAkcija).
+		Delete(&models.PeakAscent{}).Error
+}
+
+// SyncAkcijaPeakAscentsTx poravnava dnevnik sa trenutnim vrhom akcije (npr. organizator naknadno poveže vrh).
+// Briše postojeće uspone akcije i ponovo upisuje za sve „popeo se“ prijave koje ulaze u statistiku vrhova.
+func SyncAkcijaPeakAscentsTx(tx *gorm.DB, akcija *models.Akcija) error {
+	if akcija == nil || akcija.ID == 0 {
+		return nil
+	}
+	if err := tx.Where("akcija_id = ? AND izvor = ?", akcija.ID, models.PeakAscentIzvorAkcija).
+		Delete(&models.PeakAscent{}).Error; err != nil {
+		return err
+	}
+	if akcija.PeakID == nil || *akcija.PeakID == 0 {
+		return nil
+	}
+	var korisnikIDs []uint
+	if err := tx.Model(&models.Prijava{}).
+		Where("akcija_id = ? AND status = ?", akcija.ID, "popeo se").
+		Pluck("korisnik_id", &korisnikIDs).Error; err != nil {
+		return err
+	}
+	for _, kid := range korisnikIDs {
+		if !PrijavaCountsAsClimbedPeak(tx, akcija, kid) {
+			continue
+		}
+		if err := RecordAkcijaPeakAscentTx(tx, akcija, kid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordTrackedActivityPeakAscentsTx prepoznaje vrhove iz kataloga kroz koje je prošla završena GPS sesija.
+// Bbox prefilter u SQL-u, pa Haversine u Go-u; idempotentno po (aktivnost, vrh).
+func RecordTrackedActivityPeakAscentsTx(tx *gorm.DB, activity *models.TrackedActivity) ([]models.PeakAscent, error) {
+	if activity == nil || activity.ID == 0 || activity.Status != models.TrackedActivityStatusCompleted {
+		return nil, nil
+	}
+	var points []models.TrackedActivityPoint
+	if err := tx.Where("activity_id = ?", activity.ID).Order("seq ASC").Find(&points).Error; err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		if activity.StartLat != nil && activity.StartLng != nil {
+			points = append(points, models.TrackedActivityPoint{Lat: *activity.StartLat, Lng: *activity.StartLng})
+		}
+		if activity.EndLat != nil && activity.EndLng != nil {
+			points = append(points, models.TrackedActivityPoint{Lat: *activity.EndLat, Lng: *activity.EndLng})
+		}
+	}
+	minLat, maxLat, minLng, maxLng := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	valid := points[:0]
+	for _, p := range points {
+		if !geo.ValidLatLng(p.Lat, p.Lng) {
+			continue
+		}
+		valid = append(valid, p)
+		minLat = math.Min(minLat, p.Lat)
+		maxLat = math.Max(maxLat, p.Lat)
+		minLng = math.Min(minLng, p.Lng)
+		maxLng = math.Max(maxLng, p.Lng)
+	}
+	if len(valid) == 0 {
+		return nil, nil
+	}
+	// ~0.15 km u stepenima; lng margina raste sa geografskom širinom.
+	latMargin := PeakSummitRadiusKm / 111.0
+	lngMargin := latMargin / math.Max(math.Cos(maxAbs(minLat, maxLat)*math.Pi/180), 0.01)
+
+	var peaks []models.Peak
+	if err := tx.Where("status = ? AND lat IS NOT NULL AND lng IS NOT NULL", "active").
+		Where("lat BETWEEN ? AND ? AND lng BETWEEN ? AND ?", minLat-latMargin, maxLat+latMargin, minLng-lngMargin, maxLng+lngMargin).
+		Find(&peaks).Error; err != nil {
+		return nil, err
+	}
+
+	datum := activity.StartedAt
+	if activity.EndedAt != nil {
+		datum = *activity.EndedAt
+	}
+	activityID := activity.ID
+	var created []models.PeakAscent
+	for _, peak := range peaks {
+		reached := false
+		for _, p := range valid {
+			if geo.DistanceKmHaversine(p.Lat, p.Lng, *peak.Lat, *peak.Lng) <= PeakSummitRadiusKm {
+				reached = true
+				break
+			}
+		}
+		if !reached {
+			continue
+		}
+		var count int64
+		if err := tx.Model(&models.PeakAscent{}).
+			Where("tracked_activity_id = ? AND peak_id = ?", activity.ID, peak.ID).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			continue
+		}
+		ascent := models.PeakAscent{
+			KorisnikID:        activity.UserID,
+			PeakID:            peak.ID,
+			Datum:             datum,
+			Izvor:             models.PeakAscentIzvorAktivnost,
+			TrackedActivityID: &activityID,
+			ZimskiUspon:       IsWinterAscentDate(datum),
+		}
+		if err := tx.Create(&ascent).Error; err != nil {
+			return nil, err
+		}
+		created = append(created, ascent)
+	}
+	return created, nil
+}
+
+// IsWinterAscentDate — kalendarska zima (21. decembar – 20. mart) za uspone bez akcije.
+func IsWinterAscentDate(t time.Time) bool {
+	m, d := t.Month(), t.Day()
+	switch m {
+	case time.January, time.February:
+		return true
+	case time.December:
+		return d >= 21
+	case time.March:
+		return d <= 20
+	}
+	return false
+}
+
+// FirstPeakAscentDates vraća datum prvog uspona po vrhu za korisnika.
+func FirstPeakAscentDates(db *gorm.DB, korisnikID uint) (map[uint]time.Time, error) {
+	var ascents []models.PeakAscent
+	if err := db.Select("peak_id", "datum").Where("korisnik_id = ?", korisnikID).Find(&ascents).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[uint]time.Time, len(ascents))
+	for _, a := range ascents {
+		if prev, ok := out[a.PeakID]; !ok || a.Datum.Before(prev) {
+			out[a.PeakID] = a.Datum
+		}
+	}
+	return out, nil
+}
+
+func maxAbs(a, b float64) float64 {
+	return math.Max(math.Abs(a), math.Abs(b))
+}
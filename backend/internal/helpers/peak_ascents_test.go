@@ -0,0 +1,165 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func testPeakAscentDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "peak_ascents")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.Peak{},
+		&models.PeakAscent{},
+		&models.Akcija{},
+		&models.Prijava{},
+		&models.Korisnik{},
+		&models.GuideProfile{},
+		&models.TrackedActivity{},
+		&models.TrackedActivityPoint{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func seedTestPeak(t *testing.T, db *gorm.DB, naziv string, lat, lng float64) models.Peak {
+	t.Helper()
+	p := models.Peak{NazivVrha: naziv, Slug: naziv, Status: "active", VisinaM: 2000, Lat: &lat, Lng: &lng}
+	if err := db.Create(&p).Error; err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestRecordAkcijaPeakAscentTx_Idempotent(t *testing.T) {
+	db := testPeakAscentDB(t)
+	peak := seedTestPeak(t, db, "midzor", 43.3960, 22.6790)
+	akcija := models.Akcija{Naziv: "Midžor", Datum: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), PeakID: &peak.ID, ZimskiUspon: true}
+	if err := db.Create(&akcija).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := RecordAkcijaPeakAscentTx(db, &akcija, 7); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var ascents []models.PeakAscent
+	db.Find(&ascents)
+	if len(ascents) != 1 {
+		t.Fatalf("expected 1 ascent, got %d", len(ascents))
+	}
+	if ascents[0].Izvor != models.PeakAscentIzvorAkcija || !ascents[0].ZimskiUspon || ascents[0].PeakID != peak.ID {
+		t.Fatalf("unexpected ascent %+v", ascents[0])
+	}
+
+	if err := RemoveAkcijaPeakAscentTx(db, &akcija, 7); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	db.Model(&models.PeakAscent{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected ascent removed, got %d", count)
+	}
+}
+
+func TestRecordAkcijaPeakAscentTx_NoPeakIsNoop(t *testing.T) {
+	db := testPeakAscentDB(t)
+	akcija := models.Akcija{Naziv: "Bez vrha", Datum: time.Now()}
+	if err := db.Create(&akcija).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordAkcijaPeakAscentTx(db, &akcija, 7); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	db.Model(&models.PeakAscent{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected no ascent, got %d", count)
+	}
+}
+
+func TestSyncAkcijaPeakAscentsTx_RelinksClimbers(t *testing.T) {
+	db := testPeakAscentDB(t)
+	oldPeak := seedTestPeak(t, db, "stari", 43.0, 20.0)
+	newPeak := seedTestPeak(t, db, "novi", 43.1, 20.1)
+	akcija := models.Akcija{Naziv: "Tura", Datum: time.Now().AddDate(0, 0, -3), PeakID: &oldPeak.ID, IsCompleted: true}
+	if err := db.Create(&akcija).Error; err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: 1, Status: "popeo se"})
+	db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: 2, Status: "nije uspeo"})
+	if err := RecordAkcijaPeakAscentTx(db, &akcija, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	akcija.PeakID = &newPeak.ID
+	if err := SyncAkcijaPeakAscentsTx(db, &akcija); err != nil {
+		t.Fatal(err)
+	}
+	var ascents []models.PeakAscent
+	db.Find(&ascents)
+	if len(ascents) != 1 || ascents[0].PeakID != newPeak.ID || ascents[0].KorisnikID != 1 {
+		t.Fatalf("expected one relinked ascent for climber, got %+v", ascents)
+	}
+}
+
+func TestRecordTrackedActivityPeakAscentsTx_DetectsPeakNearTrack(t *testing.T) {
+	db := testPeakAscentDB(t)
+	reached := seedTestPeak(t, db, "rtanj", 43.7760, 21.8930)
+	seedTestPeak(t, db, "daleki", 43.9000, 22.1000)
+
+	ended := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	activity := models.TrackedActivity{UserID: 5, Status: models.TrackedActivityStatusCompleted, StartedAt: ended.Add(-3 * time.Hour), EndedAt: &ended}
+	if err := db.Create(&activity).Error; err != nil {
+		t.Fatal(err)
+	}
+	points := []models.TrackedActivityPoint{
+		{ActivityID: activity.ID, Seq: 0, Lat: 43.7700, Lng: 21.8800, RecordedAt: ended.Add(-2 * time.Hour)},
+		{ActivityID: activity.ID, Seq: 1, Lat: 43.7755, Lng: 21.8925, RecordedAt: ended.Add(-time.Hour)},
+	}
+	if err := db.Create(&points).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := RecordTrackedActivityPeakAscentsTx(db, &activity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 || created[0].PeakID != reached.ID || created[0].Izvor != models.PeakAscentIzvorAktivnost {
+		t.Fatalf("expected one ascent on reached peak, got %+v", created)
+	}
+	again, err := RecordTrackedActivityPeakAscentsTx(db, &activity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("expected idempotent detection, got %+v", again)
+	}
+}
+
+func TestIsWinterAscentDate(t *testing.T) {
+	cases := map[string]bool{
+		"2026-12-20": false,
+		"2026-12-21": true,
+		"2026-02-10": true,
+		"2026-03-20": true,
+		"2026-03-21": false,
+		"2026-07-01": false,
+	}
+	for raw, want := range cases {
+		d, _ := time.Parse("2006-01-02", raw)
+		if got := IsWinterAscentDate(d); got != want {
+			t.Fatalf("%s: got %v want %v", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/payments"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const placanjeEps = 0.005
+
+var (
+	ErrPlacanjeIznos        = errors.New("Iznos uplate se ne poklapa sa iznosom plaćanja")
+	ErrPlacanjeNijeUplaceno = errors.New("Povraćaj je moguć samo za uspešno plaćanje")
+	ErrPovracajPrevelik     = errors.New("Povraćaj ne može biti veći od uplaćenog iznosa")
+	ErrPrijavaPlacenaOnline = errors.New("Prijava je plaćena online — za povraćaj novca koristite povraćaj plaćanja")
+	ErrPovracajUToku        = errors.New("Drugi povraćaj ovog plaćanja je u toku — ponovite isti iznos ili sačekajte potvrdu provajdera")
+	PlacanjeUspesniStatusi  = []string{models.PlacanjePlaceno, models.PlacanjeDelimicnoVraceno}
+	placanjeUplaceniStatusi = []string{models.PlacanjePlaceno, models.PlacanjeDelimicnoVraceno, models.PlacanjeVraceno}
+)
+
+// SaldoPrijaveTx vraća iznos koji učesnik duguje za prijavu (sačuvani izbori i obračunata cena).
+func SaldoPrijaveTx(tx *gorm.DB, akcija models.Akcija, prijava models.Prijava) (float64, error) {
+	var korisnik models.Korisnik
+	if err := tx.First(&korisnik, prijava.KorisnikID).Error; err != nil {
+		return 0, err
+	}
+	var izbor models.PrijavaIzbori
+	var choices ParticipantChoices
+	if err := tx.Where("prijava_id = ?", prijava.ID).First(&izbor).Error; err == nil {
+		parsed, err := ParticipantChoicesFromIzbori(&izbor)
+		if err != nil {
+			return 0, err
+		}
+		choices = parsed
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+	return ComputeSaldoForParticipant(tx, akcija, korisnik, choices), nil
+}
+
+// ClanarinaPlacenaZaGodinu — uplate članarine za tu godinu (ručno evidentirane ili online), umanjene za povraćaje
+// online članarine, daju pozitivan saldo. Online uplata i povraćaj nose godinu članarine, ručna uplata datum.
+func ClanarinaPlacenaZaGodinu(db *gorm.DB, korisnikID uint, godina int) (bool, error) {
+	from := time.Date(godina, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(godina, 12, 31, 23, 59, 59, 0, time.UTC)
+	var neto float64
+	err := db.Model(&models.Transakcija{}).
+		Where("clanarina_korisnik_id = ? AND (clanarina_godina = ? OR (clanarina_godina IS NULL AND datum >= ? AND datum <= ?))",
+			korisnikID, godina, from, to).
+		Select("COALESCE(SUM(iznos), 0)").Scan(&neto).Error
+	return neto > placanjeEps, err
+}
+
+// OnlineUplatePoPrijavi vraća uplaćen online iznos po prijavi akcije. Potpuno vraćena plaćanja se ne računaju
+// (prijava je tada ponovo neplaćena), a delimičan povraćaj je već upisan kao zasebna isplata.
+func OnlineUplatePoPrijavi(tx *gorm.DB, akcijaID uint) (map[uint]float64, error) {
+	var rows []models.Placanje
+	if err := tx.Select("prijava_id", "iznos").
+		Where("akcija_id = ? AND svrha = ? AND status IN ?", akcijaID, models.PlacanjeSvrhaAkcija, PlacanjeUspesniStatusi).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := map[uint]float64{}
+	for _, p := range rows {
+		if p.PrijavaID != nil {
+			out[*p.PrijavaID] += p.Iznos
+		}
+	}
+	return out, nil
+}
+
+// PrijavaImaOnlineUplatu — prijava ima uspešno (ne potpuno vraćeno) online plaćanje.
+func PrijavaImaOnlineUplatu(tx *gorm.DB, prijavaID uint) (bool, error) {
+	var n int64
+	err := tx.Model(&models.Placanje{}).
+		Where("prijava_id = ? AND status IN ?", prijavaID, PlacanjeUspesniStatusi).
+		Count(&n).Error
+	return n > 0, err
+}
+
+// LockPlacanjeForUpdate zaključava plaćanje zajedno sa akcijom i prijavom (redosled Akcija → Prijava → Placanje).
+// Za članarinu se zaključava samo plaćanje. Vraća i akciju (nil za članarinu).
+func LockPlacanjeForUpdate(tx *gorm.DB, placanjeID uint) (*models.Placanje, *models.Akcija, error) {
+	var p models.Placanje
+	if err := tx.First(&p, placanjeID).Error; err != nil {
+		return nil, nil, err
+	}
+	var akcija *models.Akcija
+	if p.AkcijaID != nil {
+		a, err := LockAkcijaForUpdate(tx, *p.AkcijaID)
+		if err != nil {
+			return nil, nil, err
+		}
+		akcija = a
+		if p.PrijavaID != nil {
+			if _, err := LockPrijavaForUpdate(tx, *p.PrijavaID); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&p, placanjeID).Error; err != nil {
+		return nil, nil, err
+	}
+	return &p, akcija, nil
+}
+
+func placanjeOpis(tx *gorm.DB, p *models.Placanje, akcija *models.Akcija) string {
+	if p.Svrha == models.PlacanjeSvrhaClanarina {
+		var clan models.Korisnik
+		_ = tx.Select("id", "full_name").First(&clan, p.KorisnikID).Error
+		return fmt.Sprintf("Članarina – %s (online, plaćanje #%d)", clan.FullName, p.ID)
+	}
+	naziv := ""
+	if akcija != nil {
+		naziv = strings.TrimSpace(akcija.Naziv)
+	}
+	prijavaID := uint(0)
+	if p.PrijavaID != nil {
+		prijavaID = *p.PrijavaID
+	}
+	return fmt.Sprintf("Online uplata akcije: %s (prijava #%d, plaćanje #%d)", naziv, prijavaID, p.ID)
+}
+
+// PrimeniUspesnoPlacanjeTx upisuje uplatu u finansije i označava prijavu plaćenom; pozivalac drži LockPlacanjeForUpdate.
+// Ponovljen događaj za već plaćeno plaćanje ne radi ništa (vraća false). Novac koji je stigao za otkazanu akciju ili prijavu
+// se ipak knjiži, a Greska označava da je potreban povraćaj.
+func PrimeniUspesnoPlacanjeTx(tx *gorm.DB, p *models.Placanje, akcija *models.Akcija, paymentID string, iznos float64, at time.Time) (bool, error) {
+	if p.Status != models.PlacanjeNaCekanju && p.Status != models.PlacanjeNeuspelo {
+		return false, nil
+	}
+	if math.Abs(iznos-p.Iznos) > placanjeEps {
+		return false, fmt.Errorf("%w: očekivano %.2f, uplaćeno %.2f", ErrPlacanjeIznos, p.Iznos, iznos)
+	}
+	greska := ""
+	if p.Svrha == models.PlacanjeSvrhaAkcija && p.PrijavaID != nil && akcija != nil {
+		var prijava models.Prijava
+		if err := tx.First(&prijava, *p.PrijavaID).Error; err != nil {
+			return false, err
+		}
+		switch {
+		case akcija.IsCancelled:
+			greska = "Akcija je otkazana — potreban je povraćaj"
+		case !slices.Contains(PrijavaActiveStatuses, prijava.Status):
+			greska = "Prijava nije aktivna — potreban je povraćaj"
+		case prijava.Platio:
+			greska = "Prijava je već bila označena kao plaćena — proverite dvostruku uplatu"
+		default:
+			if err := tx.Model(&prijava).Update("platio", true).Error; err != nil {
+				return false, err
+			}
+		}
+	}
+
+	klubID := p.KlubID
+	if akcija != nil {
+		klubID = akcija.KlubID
+	}
+	t := models.Transakcija{
+		Tip:        "uplata",
+		Iznos:      p.Iznos,
+		Opis:       placanjeOpis(tx, p, akcija),
+		Datum:      at,
+		KorisnikID: ResolveFinanceRecorderID(tx, klubID, p.KorisnikID),
+	}
+	oznaciClanarinu(&t, p)
+	if err := tx.Create(&t).Error; err != nil {
+		return false, err
+	}
+
+	p.Status = models.PlacanjePlaceno
+	if paymentID != "" {
+		p.PaymentID = paymentID
+	}
+	p.PlacenoAt = &at
+	p.TransakcijaID = &t.ID
+	p.Greska = greska
+	return true, tx.Save(p).Error
+}
+
+// oznaciClanarinu vezuje uplatu ili povraćaj online članarine za člana i godinu plaćanja.
+func oznaciClanarinu(t *models.Transakcija, p *models.Placanje) {
+	if p.Svrha != models.PlacanjeSvrhaClanarina {
+		return
+	}
+	clanID, godina := p.KorisnikID, p.Godina
+	t.ClanarinaKorisnikID = &clanID
+	if godina > 0 {
+		t.ClanarinaGodina = &godina
+	}
+}
+
+// RezervisiPovracajTx proverava i rezerviše povraćaj iznos (nil: ceo preostali iznos) pre poziva provajdera;
+// pozivalac drži LockPlacanjeForUpdate i posle commit-a zove provajdera van transakcije. Vraća ciljni ukupni
+// povraćaj (osnova idempotency ključa). Rezervacija ostaje do knjiženja (PrimeniPovracajTx iz aplikacije ili
+// webhook-a): ponovljen zahtev za isti cilj je nastavlja sa istim ključem, a drugačiji iznos se odbija.
+func RezervisiPovracajTx(tx *gorm.DB, p *models.Placanje, iznos *float64) (float64, error) {
+	if !slices.Contains(PlacanjeUspesniStatusi, p.Status) {
+		return 0, ErrPlacanjeNijeUplaceno
+	}
+	if p.PovracajUToku > placanjeEps && (iznos == nil || math.Abs(p.Vraceno+*iznos-p.PovracajUToku) <= placanjeEps) {
+		return p.PovracajUToku, nil
+	}
+	if p.PovracajUToku > placanjeEps {
+		return 0, ErrPovracajUToku
+	}
+	preostalo := math.Round((p.Iznos-p.Vraceno)*100) / 100
+	vrati := preostalo
+	if iznos != nil {
+		vrati = math.Round(*iznos*100) / 100
+	}
+	if vrati <= 0 || vrati > preostalo+placanjeEps {
+		return 0, ErrPovracajPrevelik
+	}
+	p.PovracajUToku = math.Round((p.Vraceno+vrati)*100) / 100
+	if err := tx.Model(p).Update("povracaj_u_toku", p.PovracajUToku).Error; err != nil {
+		return 0, err
+	}
+	return p.PovracajUToku, nil
+}
+
+// PovracajKljuc je idempotency ključ povraćaja kod provajdera: po ciljnom ukupnom povraćaju, pa ponovljen zahtev
+// (ili isplata obaveze i povraćaj istog iznosa) ne vraća novac dvaput.
+func PovracajKljuc(p *models.Placanje, ukupnoVraceno float64) string {
+	return fmt.Sprintf("povracaj-%d-%d", p.ID, payments.ToMinor(ukupnoVraceno))
+}
+
+// PrimeniPovracajTx dovodi ukupno vraćen iznos plaćanja na ukupnoVraceno i razliku knjiži kao isplatu.
+// Idempotentno: povraćaj iz aplikacije i kasniji webhook provajdera sa istim ukupnim iznosom upisuju jednu isplatu.
+// Potpun povraćaj pre završetka akcije vraća prijavu u neplaćeno. Vraća knjiženu razliku.
+func PrimeniPovracajTx(tx *gorm.DB, p *models.Placanje, akcija *models.Akcija, ukupnoVraceno float64, at time.Time) (float64, error) {
+	if !slices.Contains(placanjeUplaceniStatusi, p.Status) {
+		return 0, ErrPlacanjeNijeUplaceno
+	}
+	if ukupnoVraceno > p.Iznos+placanjeEps {
+		return 0, ErrPovracajPrevelik
+	}
+	delta := math.Round((ukupnoVraceno-p.Vraceno)*100) / 100
+	if delta <= placanjeEps {
+		return 0, nil
+	}
+
+	klubID := p.KlubID
+	if akcija != nil {
+		klubID = akcija.KlubID
+	}
+	t := models.Transakcija{
+		Tip:        "isplata",
+		Iznos:      -delta,
+		Opis:       "Povraćaj: " + placanjeOpis(tx, p, akcija),
+		Datum:      at,
+		KorisnikID: ResolveFinanceRecorderID(tx, klubID, p.KorisnikID),
+	}
+	oznaciClanarinu(&t, p)
+	if err := tx.Create(&t).Error; err != nil {
+		return 0, err
+	}
+
+	p.Vraceno = math.Round((p.Vraceno+delta)*100) / 100
+	if p.Vraceno >= p.PovracajUToku-placanjeEps {
+		p.PovracajUToku = 0
+	}
+	p.Status = models.PlacanjeDelimicnoVraceno
+	if p.Vraceno >= p.Iznos-placanjeEps {
+		p.Status = models.PlacanjeVraceno
+		if p.PrijavaID != nil && akcija != nil && !akcija.IsCompleted && p.Greska == "" {
+			if err := tx.Model(&models.Prijava{}).Where("id = ?", *p.PrijavaID).Update("platio", false).Error; err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := tx.Save(p).Error; err != nil {
+		return 0, err
+	}
+	return delta, nil
+}
+
+// ObradiPlacanjeDogadjajTx obrađuje verifikovan webhook događaj tačno jednom po (provider, event_id).
+// Poslovne greške (nepoznato plaćanje, pogrešan iznos) se beleže uz događaj i plaćanje radi usklađivanja, a ne vraćaju,
+// da provajder ne bi ponavljao isti događaj. Vraća plaćanje na koje se događaj odnosi (nil ako ga nema ili je duplikat)
+// i da li je događaj promenio njegovo stanje.
+func ObradiPlacanjeDogadjajTx(tx *gorm.DB, provider string, ev payments.Event, payload string, at time.Time) (*models.Placanje, bool, error) {
+	dog := models.PlacanjeDogadjaj{Provider: provider, EventID: ev.ID, Tip: ev.Tip, Payload: payload}
+	if id, err := strconv.ParseUint(ev.Klub, 10, 64); err == nil && id > 0 {
+		klubID := uint(id)
+		dog.KlubID = &klubID
+	}
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dog)
+	if res.Error != nil {
+		return nil, false, res.Error
+	}
+	if res.RowsAffected == 0 || ev.Tip == "" {
+		return nil, false, nil
+	}
+
+	var ids []uint
+	if ev.CheckoutID != "" || ev.PaymentID != "" {
+		q := tx.Model(&models.Placanje{}).Where("provider = ?", provider)
+		if ev.CheckoutID != "" {
+			q = q.Where("checkout_id = ?", ev.CheckoutID)
+		} else {
+			q = q.Where("payment_id = ?", ev.PaymentID)
+		}
+		if err := q.Limit(1).Pluck("id", &ids).Error; err != nil {
+			return nil, false, err
+		}
+	}
+	if len(ids) == 0 {
+		return nil, false, tx.Model(&dog).Update("greska", "Plaćanje nije pronađeno").Error
+	}
+	p, akcija, err := LockPlacanjeForUpdate(tx, ids[0])
+	if err != nil {
+		return nil, false, err
+	}
+	if err := tx.Model(&dog).Updates(map[string]interface{}{"placanje_id": p.ID, "klub_id": p.KlubID}).Error; err != nil {
+		return nil, false, err
+	}
+
+	promenjeno := false
+	var bizErr error
+	switch ev.Tip {
+	case payments.DogadjajPlaceno:
+		promenjeno, bizErr = PrimeniUspesnoPlacanjeTx(tx, p, akcija, ev.PaymentID, ev.Iznos, at)
+	case payments.DogadjajNeuspelo:
+		if p.Status == models.PlacanjeNaCekanju {
+			p.Status = models.PlacanjeNeuspelo
+			promenjeno, bizErr = true, tx.Save(p).Error
+		}
+	case payments.DogadjajVraceno:
+		var delta float64
+		delta, bizErr = PrimeniPovracajTx(tx, p, akcija, ev.Vraceno, at)
+		promenjeno = delta > 0
+	}
+	if bizErr == nil {
+		return p, promenjeno, nil
+	}
+	if !errors.Is(bizErr, ErrPlacanjeIznos) && !errors.Is(bizErr, ErrPlacanjeNijeUplaceno) && !errors.Is(bizErr, ErrPovracajPrevelik) {
+		return nil, false, bizErr
+	}
+	if err := tx.Model(&dog).Update("greska", bizErr.Error()).Error; err != nil {
+		return nil, false, err
+	}
+	p.Greska = bizErr.Error()
+	return p, false, tx.Model(p).Update("greska", p.Greska).Error
+}
@@ -0,0 +1,551 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Izvor efektivne politike povraćaja.
+const (
+	PolitikaPovracajaIzvorKlub   = "klub"
+	PolitikaPovracajaIzvorAkcija = "akcija"
+)
+
+// maxSatiPrePovracaja — pravilo ne može važiti ranije od godinu dana pre akcije.
+const maxSatiPrePovracaja = 24 * 365
+
+var (
+	// ErrPolitikaPovracaja: pravila politike povraćaja nisu ispravna.
+	ErrPolitikaPovracaja = errors.New("Nevažeća politika povraćaja")
+	// ErrObavezaPovracajaZatvorena: obaveza je već isplaćena.
+	ErrObavezaPovracajaZatvorena = errors.New("Povraćaj je već isplaćen")
+)
+
+// PraviloPovracaja: otkaz najkasnije SatiPre sati pre početka akcije vraća Procenat uplaćenog (bez nepovratnih stavki).
+type PraviloPovracaja struct {
+	SatiPre  int     `json:"satiPre"`
+	Procenat float64 `json:"procenat"`
+}
+
+// PolitikaPovracaja je efektivna politika: pravila kluba ili akcije, procenat kada klub otkaže akciju
+// i nepovratne stavke akcije koje se odbijaju od svakog povraćaja. Otkaz posle poslednjeg pravila je bez povraćaja.
+type PolitikaPovracaja struct {
+	Pravila            []PraviloPovracaja `json:"pravila"` // od najranijeg roka (najviše sati) ka početku akcije
+	OtkazKlubaProcenat float64            `json:"otkazKlubaProcenat"`
+	NepovratniPrevoz   []uint             `json:"nepovratniPrevoz,omitempty"`
+	NepovratniSmestaj  []uint             `json:"nepovratniSmestaj,omitempty"`
+	NepovratnaOprema   []uint             `json:"nepovratnaOprema,omitempty"`
+	Napomena           string             `json:"napomena,omitempty"`
+	Izvor              string             `json:"izvor,omitempty"`
+}
+
+// NormalizujPravilaPovracaja proverava pravila (1 h – 365 dana, 0–100 %, bez ponovljenog roka, procenat ne raste
+// kako se akcija približava) i sortira ih od najranijeg roka.
+func NormalizujPravilaPovracaja(pravila []PraviloPovracaja) ([]PraviloPovracaja, error) {
+	out := append([]PraviloPovracaja(nil), pravila...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].SatiPre > out[j].SatiPre })
+	for i, p := range out {
+		if p.SatiPre <= 0 || p.SatiPre > maxSatiPrePovracaja {
+			return nil, fmt.Errorf("%w: rok mora biti između 1 sata i 365 dana pre akcije", ErrPolitikaPovracaja)
+		}
+		if p.Procenat < 0 || p.Procenat > 100 {
+			return nil, fmt.Errorf("%w: procenat mora biti između 0 i 100", ErrPolitikaPovracaja)
+		}
+		if i > 0 {
+			if out[i-1].SatiPre == p.SatiPre {
+				return nil, fmt.Errorf("%w: rok %d h je naveden dvaput", ErrPolitikaPovracaja, p.SatiPre)
+			}
+			if p.Procenat > out[i-1].Procenat {
+				return nil, fmt.Errorf("%w: povraćaj ne može rasti kako se akcija približava", ErrPolitikaPovracaja)
+			}
+		}
+	}
+	return out, nil
+}
+
+// ParsePolitikaPovracaja čita politiku kluba; nil kada je nema ili je neispravna.
+func ParsePolitikaPovracaja(raw string) *PolitikaPovracaja {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" || trimmed == "null" {
+		return nil
+	}
+	var out PolitikaPovracaja
+	if err := json.Unmarshal([]byte(trimmed), &out); err != nil {
+		return nil
+	}
+	out.Izvor = PolitikaPovracajaIzvorKlub
+	return &out
+}
+
+// EncodePolitikaPovracaja serijalizuje politiku kluba (Klubovi.PolitikaPovracaja); nil je prazan string.
+func EncodePolitikaPovracaja(p *PolitikaPovracaja) string {
+	if p == nil {
+		return ""
+	}
+	cp := *p
+	cp.Izvor = ""
+	raw, _ := json.Marshal(cp)
+	return string(raw)
+}
+
+// EncodePravilaPovracaja serijalizuje pravila za AkcijaPolitikaPovracaja.Pravila; prazna lista je prazan string.
+func EncodePravilaPovracaja(pravila []PraviloPovracaja) string {
+	if len(pravila) == 0 {
+		return ""
+	}
+	raw, _ := json.Marshal(pravila)
+	return string(raw)
+}
+
+// EncodeIDsPovracaja serijalizuje ID-jeve nepovratnih stavki akcije.
+func EncodeIDsPovracaja(ids []uint) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	raw, _ := json.Marshal(ids)
+	return string(raw)
+}
+
+// LoadAkcijaPolitikaPovracaja vraća politiku akcije; nil kada je nema.
+func LoadAkcijaPolitikaPovracaja(db *gorm.DB, akcijaID uint) *models.AkcijaPolitikaPovracaja {
+	var row models.AkcijaPolitikaPovracaja
+	if err := db.Where("akcija_id = ?", akcijaID).First(&row).Error; err != nil {
+		return nil
+	}
+	return &row
+}
+
+// PolitikaIzAkcije pretvara red akcije u politiku (prazna pravila ostaju prazna; spajanje sa klubom radi EfektivnaPolitikaPovracaja).
+func PolitikaIzAkcije(row *models.AkcijaPolitikaPovracaja) *PolitikaPovracaja {
+	if row == nil {
+		return nil
+	}
+	out := &PolitikaPovracaja{Napomena: row.Napomena, Izvor: PolitikaPovracajaIzvorAkcija}
+	_ = unmarshalChoiceIDs(row.NepovratniPrevoz, &out.NepovratniPrevoz)
+	_ = unmarshalChoiceIDs(row.NepovratniSmestaj, &out.NepovratniSmestaj)
+	_ = unmarshalChoiceIDs(row.NepovratnaOprema, &out.NepovratnaOprema)
+	if s := strings.TrimSpace(row.Pravila); s != "" && s != "null" {
+		_ = json.Unmarshal([]byte(s), &out.Pravila)
+	}
+	if row.OtkazKlubaProcenat != nil {
+		out.OtkazKlubaProcenat = *row.OtkazKlubaProcenat
+	}
+	return out
+}
+
+// EfektivnaPolitikaPovracaja spaja politiku kluba i akcije: akcija menja pravila, procenat za otkaz kluba i napomenu
+// kada ih ima, a nepovratne stavke su uvek sa akcije. Nil znači da povraćaj pri otkazu učesnika nije definisan;
+// akcije vodiča (bez finansija kluba) nemaju politiku.
+func EfektivnaPolitikaPovracaja(db *gorm.DB, akcija models.Akcija) *PolitikaPovracaja {
+	if AkcijaSkipsClubFinances(akcija) {
+		return nil
+	}
+	var out *PolitikaPovracaja
+	if akcija.KlubID != nil {
+		var klub models.Klubovi
+		if db.Select("id", "politika_povracaja").First(&klub, *akcija.KlubID).Error == nil {
+			out = ParsePolitikaPovracaja(klub.PolitikaPovracaja)
+		}
+	}
+	row := LoadAkcijaPolitikaPovracaja(db, akcija.ID)
+	if row == nil {
+		return out
+	}
+	own := PolitikaIzAkcije(row)
+	if out == nil {
+		out = &PolitikaPovracaja{OtkazKlubaProcenat: 100}
+	}
+	if len(own.Pravila) > 0 || out.Izvor == "" {
+		out.Pravila = own.Pravila
+	}
+	if row.OtkazKlubaProcenat != nil {
+		out.OtkazKlubaProcenat = own.OtkazKlubaProcenat
+	}
+	if own.Napomena != "" {
+		out.Napomena = own.Napomena
+	}
+	out.NepovratniPrevoz, out.NepovratniSmestaj, out.NepovratnaOprema = own.NepovratniPrevoz, own.NepovratniSmestaj, own.NepovratnaOprema
+	out.Izvor = PolitikaPovracajaIzvorAkcija
+	return out
+}
+
+// ProcenatPovracaja vraća procenat za otkaz učesnika u trenutku at: prvo pravilo čiji rok još nije prošao.
+func (p *PolitikaPovracaja) ProcenatPovracaja(pocetak, at time.Time) float64 {
+	if p == nil {
+		return 0
+	}
+	sati := pocetak.Sub(at).Hours()
+	for _, pr := range p.Pravila {
+		if sati >= float64(pr.SatiPre) {
+			return pr.Procenat
+		}
+	}
+	return 0
+}
+
+func formatProcenat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64) + "%"
+}
+
+func formatRokPovracaja(sati int) string {
+	if sati%24 == 0 {
+		dana := sati / 24
+		if dana%10 == 1 && dana%100 != 11 {
+			return fmt.Sprintf("%d dan", dana)
+		}
+		return fmt.Sprintf("%d dana", dana)
+	}
+	return fmt.Sprintf("%d h", sati)
+}
+
+// OpisPolitikePovracaja su redovi politike za prikaz učesniku pre prijave (uključujući nazive nepovratnih stavki).
+func OpisPolitikePovracaja(db *gorm.DB, akcijaID uint, p *PolitikaPovracaja) []string {
+	if p == nil {
+		return nil
+	}
+	out := make([]string, 0, len(p.Pravila)+3)
+	for _, pr := range p.Pravila {
+		out = append(out, fmt.Sprintf("Otkaz najkasnije %s pre početka: povraćaj %s", formatRokPovracaja(pr.SatiPre), formatProcenat(pr.Procenat)))
+	}
+	if len(p.Pravila) > 0 {
+		out = append(out, "Kasniji otkaz: bez povraćaja")
+	} else {
+		out = append(out, "Otkaz učesnika: bez povraćaja")
+	}
+	out = append(out, "Ako klub otkaže akciju: povraćaj "+formatProcenat(p.OtkazKlubaProcenat))
+
+	var stavke []string
+	if len(p.NepovratniPrevoz) > 0 {
+		var rows []models.AkcijaPrevoz
+		db.Where("akcija_id = ? AND id IN ?", akcijaID, p.NepovratniPrevoz).Order("id").Find(&rows)
+		for _, r := range rows {
+			stavke = append(stavke, strings.TrimSpace(r.TipPrevoza+" "+r.NazivGrupe))
+		}
+	}
+	if len(p.NepovratniSmestaj) > 0 {
+		var rows []models.AkcijaSmestaj
+		db.Where("akcija_id = ? AND id IN ?", akcijaID, p.NepovratniSmestaj).Order("id").Find(&rows)
+		for _, r := range rows {
+			stavke = append(stavke, r.Naziv)
+		}
+	}
+	if len(p.NepovratnaOprema) > 0 {
+		var rows []models.AkcijaOpremaRent
+		db.Where("akcija_id = ? AND id IN ?", akcijaID, p.NepovratnaOprema).Order("id").Find(&rows)
+		for _, r := range rows {
+			stavke = append(stavke, r.NazivOpreme)
+		}
+	}
+	if len(stavke) > 0 {
+		out = append(out, "Ne vraća se: "+strings.Join(stavke, ", "))
+	}
+	return out
+}
+
+// ObracunPovracaja je obračun povraćaja za jednu prijavu (čuva se uz ObavezaPovracaja i prikazuje učesniku).
+type ObracunPovracaja struct {
+	Razlog           string    `json:"razlog"`
+	Uplaceno         float64   `json:"uplaceno"`
+	Nepovratno       float64   `json:"nepovratno"`
+	NepovratneStavke []string  `json:"nepovratneStavke,omitempty"`
+	Procenat         float64   `json:"procenat"`
+	Iznos            float64   `json:"iznos"`
+	UplataOnline     bool      `json:"uplataOnline"`
+	SatiDoPocetka    float64   `json:"satiDoPocetka"`
+	ObracunatoAt     time.Time `json:"obracunatoAt"`
+}
+
+// onlineNetoZaPrijavu: uplaćeno online za prijavu umanjeno za već izvršene povraćaje.
+func onlineNetoZaPrijavu(tx *gorm.DB, prijavaID uint) (float64, error) {
+	var rows []models.Placanje
+	if err := tx.Select("iznos", "vraceno").
+		Where("prijava_id = ? AND svrha = ? AND status IN ?", prijavaID, models.PlacanjeSvrhaAkcija, PlacanjeUspesniStatusi).
+		Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for _, p := range rows {
+		sum += p.Iznos - p.Vraceno
+	}
+	return math.Round(sum*100) / 100, nil
+}
+
+// nepovratniDeo sabira cene izabranih stavki koje politika označava kao nepovratne.
+func nepovratniDeo(tx *gorm.DB, akcijaID uint, choices ParticipantChoices, p *PolitikaPovracaja) (float64, []string) {
+	if p == nil {
+		return 0, nil
+	}
+	sum := 0.0
+	var nazivi []string
+	var prevozIDs, smestajIDs []uint
+	for _, id := range choices.SelectedPrevozIDs {
+		if ChoiceIDsContain(p.NepovratniPrevoz, id) {
+			prevozIDs = append(prevozIDs, id)
+		}
+	}
+	for _, id := range choices.SelectedSmestajIDs {
+		if ChoiceIDsContain(p.NepovratniSmestaj, id) {
+			smestajIDs = append(smestajIDs, id)
+		}
+	}
+	if len(prevozIDs) > 0 {
+		var rows []models.AkcijaPrevoz
+		tx.Where("akcija_id = ? AND id IN ?", akcijaID, prevozIDs).Find(&rows)
+		for _, r := range rows {
+			sum += r.CenaPoOsobi
+			nazivi = append(nazivi, strings.TrimSpace(r.TipPrevoza+" "+r.NazivGrupe))
+		}
+	}
+	if len(smestajIDs) > 0 {
+		var rows []models.AkcijaSmestaj
+		tx.Where("akcija_id = ? AND id IN ?", akcijaID, smestajIDs).Find(&rows)
+		for _, r := range rows {
+			sum += r.CenaPoOsobiUkupno
+			nazivi = append(nazivi, r.Naziv)
+		}
+	}
+	for _, item := range choices.SelectedRentItems {
+		if item.Kolicina <= 0 || !ChoiceIDsContain(p.NepovratnaOprema, item.RentID) {
+			continue
+		}
+		var r models.AkcijaOpremaRent
+		if tx.Where("akcija_id = ? AND id = ?", akcijaID, item.RentID).First(&r).Error == nil {
+			sum += r.CenaPoSetu * float64(item.Kolicina)
+			nazivi = append(nazivi, r.NazivOpreme)
+		}
+	}
+	return sum, nazivi
+}
+
+// ObracunajPovracajTx računa povraćaj plaćene prijave za otkaz u trenutku at. Uplaćeno je online uplata (neto) ili
+// gotovinska uplata evidentirana pri označavanju (a ne saldo po ceni u trenutku otkaza, koja se u međuvremenu
+// mogla promeniti); nepovratne stavke se odbijaju u svakom slučaju. Procenat: otkaz kluba → OtkazKlubaProcenat
+// (100 bez politike), otkaz bez penala posle izmene akcije → 100, otkaz učesnika → pravila politike.
+func ObracunajPovracajTx(tx *gorm.DB, akcija models.Akcija, prijava models.Prijava, p *PolitikaPovracaja, razlog string, at time.Time) (ObracunPovracaja, error) {
+	pocetak, _ := AkcijaTimeWindow(&akcija)
+	out := ObracunPovracaja{Razlog: razlog, ObracunatoAt: at, SatiDoPocetka: math.Round(pocetak.Sub(at).Hours()*10) / 10}
+
+	online, err := onlineNetoZaPrijavu(tx, prijava.ID)
+	if err != nil {
+		return out, err
+	}
+	var choices ParticipantChoices
+	var izbor models.PrijavaIzbori
+	if err := tx.Where("prijava_id = ?", prijava.ID).First(&izbor).Error; err == nil {
+		if parsed, err := ParticipantChoicesFromIzbori(&izbor); err == nil {
+			choices = parsed
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return out, err
+	}
+	if online > 0 {
+		out.Uplaceno, out.UplataOnline = online, true
+	} else if prijava.UplacenoGotovinom != nil {
+		out.Uplaceno = math.Round(*prijava.UplacenoGotovinom*100) / 100
+	} else {
+		// Prijava označena plaćenom pre evidencije iznosa: jedina osnova je saldo po sadašnjim cenama.
+		saldo, err := SaldoPrijaveTx(tx, akcija, prijava)
+		if err != nil {
+			return out, err
+		}
+		out.Uplaceno = math.Round(saldo*100) / 100
+	}
+
+	nepovratno, stavke := nepovratniDeo(tx, akcija.ID, choices, p)
+	out.Nepovratno = math.Round(math.Min(nepovratno, out.Uplaceno)*100) / 100
+	out.NepovratneStavke = stavke
+
+	switch razlog {
+	case models.ObavezaPovracajaOtkazAkcije:
+		out.Procenat = 100
+		if p != nil {
+			out.Procenat = p.OtkazKlubaProcenat
+		}
+	case models.ObavezaPovracajaBezPenala:
+		out.Procenat = 100
+	default:
+		out.Procenat = p.ProcenatPovracaja(pocetak, at)
+	}
+	out.Iznos = zaokruziCenu((out.Uplaceno - out.Nepovratno) * out.Procenat / 100)
+	return out, nil
+}
+
+// EvidentirajObavezuPovracajaTx upisuje obavezu povraćaja za plaćenu prijavu koja se otkazuje; pozivalac drži lock akcije
+// i prijave. Akcije vodiča (bez finansija kluba) i neplaćene prijave nemaju obavezu; online uplata bez povraćaja takođe
+// (već je knjižena), dok gotovinska ostaje otvorena da bi blagajnik pri zatvaranju knjižio zadržanu uplatu.
+func EvidentirajObavezuPovracajaTx(tx *gorm.DB, akcija models.Akcija, prijava models.Prijava, p *PolitikaPovracaja, razlog string, at time.Time) (*models.ObavezaPovracaja, error) {
+	if !prijava.Platio || AkcijaSkipsClubFinances(akcija) {
+		return nil, nil
+	}
+	var postoji int64
+	if err := tx.Model(&models.ObavezaPovracaja{}).Where("prijava_id = ?", prijava.ID).Count(&postoji).Error; err != nil {
+		return nil, err
+	}
+	if postoji > 0 {
+		return nil, nil
+	}
+	obracun, err := ObracunajPovracajTx(tx, akcija, prijava, p, razlog, at)
+	if err != nil {
+		return nil, err
+	}
+	if obracun.Uplaceno <= 0 || (obracun.UplataOnline && obracun.Iznos <= 0) {
+		return nil, nil
+	}
+	raw, _ := json.Marshal(obracun)
+	o := models.ObavezaPovracaja{
+		KlubID:       akcija.KlubID,
+		AkcijaID:     akcija.ID,
+		PrijavaID:    prijava.ID,
+		KorisnikID:   prijava.KorisnikID,
+		Razlog:       razlog,
+		Uplaceno:     obracun.Uplaceno,
+		Nepovratno:   obracun.Nepovratno,
+		Procenat:     obracun.Procenat,
+		Iznos:        obracun.Iznos,
+		UplataOnline: obracun.UplataOnline,
+		Obracun:      string(raw),
+		Status:       models.ObavezaPovracajaOtvorena,
+		OtkazanoAt:   at,
+	}
+	if err := tx.Create(&o).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// RezervisiIsplatuObavezeTx vezuje obavezu za online plaćanje i rezerviše povraćaj njenog iznosa pre poziva provajdera;
+// pozivalac drži lock plaćanja i obaveze. Obaveza koja je već u toku vraća isti cilj, pa ponovljen zahtev koristi
+// isti ključ kod provajdera.
+func RezervisiIsplatuObavezeTx(tx *gorm.DB, o *models.ObavezaPovracaja, p *models.Placanje) (float64, error) {
+	switch o.Status {
+	case models.ObavezaPovracajaOtvorena:
+	case models.ObavezaPovracajaUToku:
+		if o.PlacanjeID == nil || *o.PlacanjeID != p.ID {
+			return 0, ErrPovracajUToku
+		}
+		return o.PovracajCilj, nil
+	default:
+		return 0, ErrObavezaPovracajaZatvorena
+	}
+	iznos := o.Iznos
+	cilj, err := RezervisiPovracajTx(tx, p, &iznos)
+	if err != nil {
+		return 0, err
+	}
+	o.Status = models.ObavezaPovracajaUToku
+	o.PlacanjeID = &p.ID
+	o.PovracajCilj = cilj
+	return cilj, tx.Save(o).Error
+}
+
+// EvidentirajObavezePovracajaZaOtkazAkcijeTx upisuje obaveze za sve plaćene aktivne prijave akcije koju klub otkazuje;
+// pozivalac drži lock akcije. Prijave i Platio se ne menjaju.
+func EvidentirajObavezePovracajaZaOtkazAkcijeTx(tx *gorm.DB, akcija models.Akcija, at time.Time) (int, error) {
+	if AkcijaSkipsClubFinances(akcija) {
+		return 0, nil
+	}
+	var prijave []models.Prijava
+	if err := tx.Where("akcija_id = ? AND status IN ? AND platio = ?", akcija.ID, PrijavaActiveStatuses, true).
+		Order("id").Find(&prijave).Error; err != nil {
+		return 0, err
+	}
+	if len(prijave) == 0 {
+		return 0, nil
+	}
+	politika := EfektivnaPolitikaPovracaja(tx, akcija)
+	n := 0
+	for _, prijava := range prijave {
+		o, err := EvidentirajObavezuPovracajaTx(tx, akcija, prijava, politika, models.ObavezaPovracajaOtkazAkcije, at)
+		if err != nil {
+			return n, err
+		}
+		if o != nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// LockObavezaPovracajaForUpdate zaključava obavezu zajedno sa akcijom i prijavom (redosled Akcija → Prijava → ObavezaPovracaja).
+func LockObavezaPovracajaForUpdate(tx *gorm.DB, id uint) (*models.ObavezaPovracaja, *models.Akcija, error) {
+	var o models.ObavezaPovracaja
+	if err := tx.First(&o, id).Error; err != nil {
+		return nil, nil, err
+	}
+	akcija, err := LockAkcijaForUpdate(tx, o.AkcijaID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := LockPrijavaForUpdate(tx, o.PrijavaID); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&o, id).Error; err != nil {
+		return nil, nil, err
+	}
+	return &o, akcija, nil
+}
+
+func obavezaOpis(akcija *models.Akcija, o *models.ObavezaPovracaja) string {
+	return fmt.Sprintf("%s (otkazana prijava #%d)", strings.TrimSpace(akcija.Naziv), o.PrijavaID)
+}
+
+// ZatvoriObavezuPovracajaTx označava obavezu isplaćenom; pozivalac drži LockObavezaPovracajaForUpdate.
+// Bez placanjeID (gotovina ili račun) knjiži se isplata Iznos, a za gotovinsku uplatu i sama uplata koja do tada
+// nije bila u finansijama. Uz placanjeID isplatu je već knjižio PrimeniPovracajTx. Obaveza čiji je online povraćaj
+// u toku zatvara se samo tim plaćanjem.
+func ZatvoriObavezuPovracajaTx(tx *gorm.DB, o *models.ObavezaPovracaja, akcija *models.Akcija, placanjeID *uint, zatvorioID uint, at time.Time) error {
+	switch o.Status {
+	case models.ObavezaPovracajaOtvorena:
+	case models.ObavezaPovracajaUToku:
+		if placanjeID == nil || o.PlacanjeID == nil || *placanjeID != *o.PlacanjeID {
+			return ErrPovracajUToku
+		}
+	default:
+		return ErrObavezaPovracajaZatvorena
+	}
+	if placanjeID == nil {
+		recorder := ResolveFinanceRecorderID(tx, akcija.KlubID, zatvorioID)
+		if !o.UplataOnline && o.Uplaceno > 0 {
+			uplata := models.Transakcija{
+				Tip:        "uplata",
+				Iznos:      o.Uplaceno,
+				Opis:       "Uplata akcije: " + obavezaOpis(akcija, o),
+				Datum:      at,
+				KorisnikID: recorder,
+			}
+			if err := tx.Create(&uplata).Error; err != nil {
+				return err
+			}
+			o.TransakcijaID = &uplata.ID
+		}
+		if o.Iznos > 0 {
+			isplata := models.Transakcija{
+				Tip:        "isplata",
+				Iznos:      -o.Iznos,
+				Opis:       "Povraćaj: " + obavezaOpis(akcija, o),
+				Datum:      at,
+				KorisnikID: recorder,
+			}
+			if err := tx.Create(&isplata).Error; err != nil {
+				return err
+			}
+			o.TransakcijaID = &isplata.ID
+		}
+	}
+	o.Status = models.ObavezaPovracajaIsplacena
+	o.IsplacenoAt = &at
+	o.PlacanjeID = placanjeID
+	o.ZatvorioID = &zatvorioID
+	return tx.Save(o).Error
+}
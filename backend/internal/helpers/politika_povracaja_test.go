@@ -0,0 +1,48 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizujPravilaPovracaja(t *testing.T) {
+	out, err := NormalizujPravilaPovracaja([]PraviloPovracaja{{SatiPre: 48, Procenat: 50}, {SatiPre: 168, Procenat: 100}})
+	if err != nil || out[0].SatiPre != 168 || out[1].SatiPre != 48 {
+		t.Fatalf("sorted rules: %+v %v", out, err)
+	}
+	for name, pravila := range map[string][]PraviloPovracaja{
+		"rastuci":  {{SatiPre: 168, Procenat: 50}, {SatiPre: 48, Procenat: 100}},
+		"duplikat": {{SatiPre: 48, Procenat: 50}, {SatiPre: 48, Procenat: 20}},
+		"procenat": {{SatiPre: 48, Procenat: 120}},
+		"rok":      {{SatiPre: 0, Procenat: 50}},
+	} {
+		if _, err := NormalizujPravilaPovracaja(pravila); !errors.Is(err, ErrPolitikaPovracaja) {
+			t.Fatalf("%s: expected ErrPolitikaPovracaja, got %v", name, err)
+		}
+	}
+}
+
+func TestProcenatPovracaja_Tiers(t *testing.T) {
+	p := &PolitikaPovracaja{Pravila: []PraviloPovracaja{{SatiPre: 168, Procenat: 100}, {SatiPre: 48, Procenat: 50}}}
+	pocetak := time.Date(2027, 5, 20, 7, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		pre  time.Duration
+		want float64
+	}{
+		{8 * 24 * time.Hour, 100},
+		{168 * time.Hour, 100},
+		{100 * time.Hour, 50},
+		{48 * time.Hour, 50},
+		{47 * time.Hour, 0},
+		{-time.Hour, 0},
+	} {
+		if got := p.ProcenatPovracaja(pocetak, pocetak.Add(-tc.pre)); got != tc.want {
+			t.Fatalf("%v before start: got %v want %v", tc.pre, got, tc.want)
+		}
+	}
+	var nema *PolitikaPovracaja
+	if nema.ProcenatPovracaja(pocetak, pocetak.Add(-30*24*time.Hour)) != 0 {
+		t.Fatal("nil policy refunds nothing")
+	}
+}
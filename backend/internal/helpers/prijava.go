@@ -116,11 +116,14 @@ func ValidateAkcijaSignupDeadline(akcija *models.Akcija, now time.Time) error {
 }
 
 // ValidateAkcijaSignupOpen proverava da li je akcija otvorena za novu prijavu člana
-// (aktivna + rok nije istekao). Kompozicija ValidateAkcijaActive + ValidateAkcijaSignupDeadline.
+// (aktivna, objavljena + rok nije istekao). Kompozicija ValidateAkcijaActive, ValidateAkcijaPublished i ValidateAkcijaSignupDeadline.
 func ValidateAkcijaSignupOpen(akcija *models.Akcija, now time.Time) error {
 	if err := ValidateAkcijaActive(akcija); err != nil {
 		return err
 	}
+	if err := ValidateAkcijaPublished(akcija); err != nil {
+		return err
+	}
 	return ValidateAkcijaSignupDeadline(akcija, now)
 }
 
@@ -157,6 +160,11 @@ type PrijavaIzboriPayload struct {
 	SelectedSmestajIDs   string
 	SelectedPrevozIDs    string
 	SelectedRentItemsRaw string
+	Odgovori             string // odgovori na formu akcije (EncodeFormaOdgovori)
+	FormaVerzija         int
+	CenaObracun          string // EncodeCenaObracun; cena dogovorena pri prijavi
+	PromoKodID           *uint
+	Grupa                string
 }
 
 // ReactivateCancelledPrijavaFromChoicesTx reaktivira postojeću prijavu sa statusom "otkazano".
@@ -208,6 +216,11 @@ func ReactivateCancelledPrijavaFromChoicesTx(tx *gorm.DB, prijavaID uint, choice
 			SelectedSmestajIDs:   choices.SelectedSmestajIDs,
 			SelectedPrevozIDs:    choices.SelectedPrevozIDs,
 			SelectedRentItemsRaw: choices.SelectedRentItemsRaw,
+			Odgovori:             choices.Odgovori,
+			FormaVerzija:         choices.FormaVerzija,
+			CenaObracun:          choices.CenaObracun,
+			PromoKodID:           choices.PromoKodID,
+			Grupa:                choices.Grupa,
 		}
 		if createErr := tx.Create(&izbor).Error; createErr != nil {
 			if IsDuplicatePrijavaIzboriDBError(createErr) {
@@ -218,6 +231,11 @@ func ReactivateCancelledPrijavaFromChoicesTx(tx *gorm.DB, prijavaID uint, choice
 				raced.SelectedSmestajIDs = choices.SelectedSmestajIDs
 				raced.SelectedPrevozIDs = choices.SelectedPrevozIDs
 				raced.SelectedRentItemsRaw = choices.SelectedRentItemsRaw
+				raced.Odgovori = choices.Odgovori
+				raced.FormaVerzija = choices.FormaVerzija
+				raced.CenaObracun = choices.CenaObracun
+				raced.PromoKodID = choices.PromoKodID
+				raced.Grupa = choices.Grupa
 				if saveErr := tx.Save(&raced).Error; saveErr != nil {
 					return models.Prijava{}, saveErr
 				}
@@ -231,6 +249,11 @@ func ReactivateCancelledPrijavaFromChoicesTx(tx *gorm.DB, prijavaID uint, choice
 		izbor.SelectedSmestajIDs = choices.SelectedSmestajIDs
 		izbor.SelectedPrevozIDs = choices.SelectedPrevozIDs
 		izbor.SelectedRentItemsRaw = choices.SelectedRentItemsRaw
+		izbor.Odgovori = choices.Odgovori
+		izbor.FormaVerzija = choices.FormaVerzija
+		izbor.CenaObracun = choices.CenaObracun
+		izbor.PromoKodID = choices.PromoKodID
+		izbor.Grupa = choices.Grupa
 		if err := tx.Save(&izbor).Error; err != nil {
 			return models.Prijava{}, err
 		}
@@ -448,6 +471,9 @@ func CreateConfirmedPrijavaTx(tx *gorm.DB, akcijaID, korisnikID uint, now time.T
 		}
 	}
 	if policy.ValidateSignupDeadline {
+		if err := ValidateAkcijaPublished(locked); err != nil {
+			return models.Prijava{}, err
+		}
 		if err := ValidateAkcijaSignupDeadline(locked, now); err != nil {
 			return models.Prijava{}, err
 		}
@@ -8,10 +8,10 @@ import (
 	"gorm.io/gorm"
 )
 
-// AkcijaProfiGuideAsLeader — vodič je dodeljen na akciju kao verifikovani profi vodič (ne klubski vodič domaćina).
+// AkcijaProfiGuideAsLeader — vodič (glavni ili ko-vodič) je dodeljen na akciju kao verifikovani profi vodič (ne klubski vodič domaćina).
 // Takve akcije ulaze u „vođene ture“, a ne u osvojene vrhove za tog vodiča.
 func AkcijaProfiGuideAsLeader(db *gorm.DB, akcija *models.Akcija, korisnikID uint) bool {
-	if akcija == nil || korisnikID == 0 || akcija.VodicID != korisnikID {
+	if akcija == nil || korisnikID == 0 || !IsAkcijaGuide(db, akcija, korisnikID) {
 		return false
 	}
 	if !KorisnikIsApprovedProfiGuide(db, korisnikID) {
@@ -30,12 +30,12 @@ func AkcijaProfiGuideAsLeader(db *gorm.DB, akcija *models.Akcija, korisnikID uin
 	return true
 }
 
-// AkcijaCountsAsGuidedTourForVodic — završena akcija koju je korisnik vodio (PER iz vođenih tura).
+// AkcijaCountsAsGuidedTourForVodic — završena akcija koju je korisnik vodio kao glavni ili ko-vodič (PER iz vođenih tura).
 func AkcijaCountsAsGuidedTourForVodic(db *gorm.DB, akcija *models.Akcija, korisnikID uint) bool {
-	if akcija == nil || !akcija.IsCompleted || korisnikID == 0 || akcija.VodicID != korisnikID {
+	if akcija == nil || !akcija.IsCompleted || korisnikID == 0 {
 		return false
 	}
-	return true
+	return IsAkcijaGuide(db, akcija, korisnikID)
 }
 
 // PrijavaCountsAsClimbedPeak — da li uspešna prijava ulazi u osvojene vrhove / statistiku uspona.
@@ -75,5 +75,8 @@ func PromoteGuidePrijavaToPopeoSeIfEligible(tx *gorm.DB, akcija *models.Akcija)
 	if err := tx.Save(&korisnik).Error; err != nil {
 		return false, err
 	}
+	if err := RecordAkcijaPeakAscentTx(tx, akcija, korisnik.ID); err != nil {
+		return false, err
+	}
 	return true, nil
 }
@@ -0,0 +1,36 @@
+package helpers
+
+import "beleg-app/backend/internal/models"
+
+// Pragovi za automatsko označavanje GPS sesija (vožnja umesto hoda, pokvaren barometar/GPS).
+const (
+	TrackedActivityMaxAvgSpeedKmh     = 25.0
+	TrackedActivityMaxClimbRateMPerH  = 2000.0
+	trackedActivityFlagMinDistanceM   = 1000.0
+	trackedActivityFlagMinElevationM  = 300.0
+	TrackedActivityFlagReasonSpeed    = "prosecna_brzina"
+	TrackedActivityFlagReasonClimb    = "brzina_uspona"
+	TrackedActivityFlagReasonDuration = "bez_trajanja"
+)
+
+// TrackedActivityFlagReason vraća razlog zbog kojeg se završena sesija isključuje iz rang lista i izazova,
+// ili "" ako je sesija verodostojna.
+func TrackedActivityFlagReason(a *models.TrackedActivity) string {
+	if a == nil {
+		return ""
+	}
+	if a.DurationSec <= 0 {
+		if a.DistanceM >= trackedActivityFlagMinDistanceM || a.ElevationGainM >= trackedActivityFlagMinElevationM {
+			return TrackedActivityFlagReasonDuration
+		}
+		return ""
+	}
+	hours := float64(a.DurationSec) / 3600
+	if a.DistanceM >= trackedActivityFlagMinDistanceM && a.DistanceM/1000/hours > TrackedActivityMaxAvgSpeedKmh {
+		return TrackedActivityFlagReasonSpeed
+	}
+	if a.ElevationGainM >= trackedActivityFlagMinElevationM && a.ElevationGainM/hours > TrackedActivityMaxClimbRateMPerH {
+		return TrackedActivityFlagReasonClimb
+	}
+	return ""
+}
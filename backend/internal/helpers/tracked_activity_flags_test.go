@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"testing"
+
+	"beleg-app/backend/internal/models"
+)
+
+func TestTrackedActivityFlagReason(t *testing.T) {
+	cases := []struct {
+		name string
+		a    models.TrackedActivity
+		want string
+	}{
+		{"hike", models.TrackedActivity{DurationSec: 4 * 3600, DistanceM: 14000, ElevationGainM: 1100}, ""},
+		{"car", models.TrackedActivity{DurationSec: 3600, DistanceM: 60000}, TrackedActivityFlagReasonSpeed},
+		{"barometer", models.TrackedActivity{DurationSec: 1800, DistanceM: 2000, ElevationGainM: 1500}, TrackedActivityFlagReasonClimb},
+		{"no duration", models.TrackedActivity{DistanceM: 5000}, TrackedActivityFlagReasonDuration},
+		{"short test session", models.TrackedActivity{DurationSec: 30, DistanceM: 400}, ""},
+	}
+	for _, tc := range cases {
+		if got := TrackedActivityFlagReason(&tc.a); got != tc.want {
+			t.Errorf("%s: got %q want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Package hiketime procenjuje vreme hoda po DIN 33466 (varijanta Naismith-ovog pravila koju koriste
+// nemački i austrijski planinarski savezi): horizontalno i vertikalno vreme se računaju posebno,
+// a ukupno je veće od njih plus polovina manjeg. Pauze nisu uključene u standardno vreme.
+package hiketime
+
+import (
+	"math"
+	"sort"
+)
+
+// Rates su brzine kretanja za jednu vrstu terena.
+type Rates struct {
+	HorizontalKmh float64 // km/h po ravnom
+	AscentMh      float64 // visinskih metara na sat u usponu
+	DescentMh     float64 // visinskih metara na sat u silasku
+}
+
+var (
+	// Hiking — DIN 33466: 4 km/h, 300 m/h uspona, 500 m/h silaska.
+	Hiking = Rates{HorizontalKmh: 4, AscentMh: 300, DescentMh: 500}
+	// ViaFerrata — deonica na sajli: sporije i horizontalno i vertikalno (čekanje na klinovima, prekopčavanje).
+	ViaFerrata = Rates{HorizontalKmh: 2, AscentMh: 200, DescentMh: 250}
+)
+
+const (
+	// MinPace i MaxPace ograničavaju faktor tempa grupe (0,6 = 40 % brže od standarda).
+	MinPace = 0.6
+	MaxPace = 1.8
+	// MinSamples — učesnik sa manje završenih aktivnosti ne utiče na tempo grupe.
+	MinSamples = 2
+	// MinSampleDistanceM — kratke šetnje ne govore mnogo o tempu na turi.
+	MinSampleDistanceM = 3000
+)
+
+// Leg je deonica rute.
+type Leg struct {
+	DistanceM float64
+	AscentM   float64
+	DescentM  float64
+	Rates     Rates
+}
+
+// Hours vraća standardno vreme hoda deonice u satima (bez pauza).
+func (l Leg) Hours() float64 {
+	if l.DistanceM <= 0 && l.AscentM <= 0 && l.DescentM <= 0 {
+		return 0
+	}
+	horizontal := math.Max(l.DistanceM, 0) / 1000 / l.Rates.HorizontalKmh
+	vertical := math.Max(l.AscentM, 0)/l.Rates.AscentMh + math.Max(l.DescentM, 0)/l.Rates.DescentMh
+	return math.Max(horizontal, vertical) + math.Min(horizontal, vertical)/2
+}
+
+// StandardMinutes sabira deonice i vraća standardno vreme u minutima.
+func StandardMinutes(legs ...Leg) int {
+	h := 0.0
+	for _, l := range legs {
+		h += l.Hours()
+	}
+	return int(math.Round(h * 60))
+}
+
+// PaceRatio je odnos stvarnog i standardnog vremena za završenu aktivnost (1,0 = tačno po DIN-u).
+// Aktivnosti beleže samo uspon, pa se pretpostavlja povratak na polaznu visinu (silazak = uspon).
+// Vraća false kada aktivnost nije upotrebljiva za procenu tempa.
+func PaceRatio(durationSec int, distanceM, gainM float64) (float64, bool) {
+	if durationSec <= 0 || distanceM < MinSampleDistanceM {
+		return 0, false
+	}
+	std := Leg{DistanceM: distanceM, AscentM: gainM, DescentM: gainM, Rates: Hiking}.Hours()
+	if std <= 0 {
+		return 0, false
+	}
+	return float64(durationSec) / 3600 / std, true
+}
+
+// GroupPace vraća faktor tempa grupe iz odnosa po učesniku: grupa ide tempom najsporijeg,
+// a tempo učesnika je medijana njegovih aktivnosti. Bez podataka vraća 1 i ok=false.
+func GroupPace(ratiosByParticipant map[uint][]float64) (pace float64, participants int, ok bool) {
+	pace = 0
+	for _, ratios := range ratiosByParticipant {
+		if len(ratios) < MinSamples {
+			continue
+		}
+		participants++
+		pace = math.Max(pace, median(ratios))
+	}
+	if participants == 0 {
+		return 1, 0, false
+	}
+	return math.Min(math.Max(pace, MinPace), MaxPace), participants, true
+}
+
+func median(v []float64) float64 {
+	s := append([]float64(nil), v...)
+	sort.Float64s(s)
+	if len(s)%2 == 1 {
+		return s[len(s)/2]
+	}
+	return (s[len(s)/2-1] + s[len(s)/2]) / 2
+}
@@ -0,0 +1,53 @@
+package hiketime
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLegHours_DIN33466(t *testing.T) {
+	cases := []struct {
+		name string
+		leg  Leg
+		want float64
+	}{
+		// 12 km → 3 h horizontalno; 900 m uspona → 3 h vertikalno: 3 + 3/2.
+		{"balanced", Leg{DistanceM: 12000, AscentM: 900, Rates: Hiking}, 4.5},
+		// 8 km → 2 h; 600 m gore (2 h) + 600 m dole (1,2 h) = 3,2 h: 3,2 + 2/2.
+		{"round trip", Leg{DistanceM: 8000, AscentM: 600, DescentM: 600, Rates: Hiking}, 4.2},
+		{"flat", Leg{DistanceM: 10000, Rates: Hiking}, 2.5},
+		{"empty", Leg{Rates: Hiking}, 0},
+	}
+	for _, tc := range cases {
+		if got := tc.leg.Hours(); math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf("%s: %v h, want %v", tc.name, got, tc.want)
+		}
+	}
+	if got := StandardMinutes(Leg{DistanceM: 12000, AscentM: 900, Rates: Hiking}, Leg{DistanceM: 10000, Rates: Hiking}); got != 420 {
+		t.Fatalf("legs must add up, got %d min", got)
+	}
+}
+
+func TestGroupPace_SlowestMedianClamped(t *testing.T) {
+	if r, ok := PaceRatio(6*3600, 12000, 450); !ok || math.Abs(r-6/4.2) > 1e-9 {
+		t.Fatalf("pace ratio = %v %v", r, ok)
+	}
+	if _, ok := PaceRatio(3600, 1500, 100); ok {
+		t.Fatal("short walks must not count")
+	}
+
+	pace, n, ok := GroupPace(map[uint][]float64{
+		1: {0.8, 0.9, 0.7},
+		2: {1.1, 1.3, 5.0}, // medijana 1,3 — jedna izuzetno spora tura ne odlučuje
+		3: {2.0},           // premalo uzoraka
+	})
+	if !ok || n != 2 || math.Abs(pace-1.3) > 1e-9 {
+		t.Fatalf("group pace = %v (%d, %v), want 1.3 from 2 participants", pace, n, ok)
+	}
+	if pace, _, _ := GroupPace(map[uint][]float64{1: {3, 3}}); pace != MaxPace {
+		t.Fatalf("pace must be clamped, got %v", pace)
+	}
+	if pace, _, ok := GroupPace(nil); ok || pace != 1 {
+		t.Fatalf("no data must fall back to standard pace, got %v %v", pace, ok)
+	}
+}
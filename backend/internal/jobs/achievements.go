@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"beleg-app/backend/internal/achievements"
+
+	"gorm.io/gorm"
+)
+
+// RunAchievementBackfillJob jednom po startu dodeljuje bedževe iz istorije (npr. posle dodavanja novog pravila).
+func RunAchievementBackfillJob(db *gorm.DB) {
+	// Sačekaj da se server podigne, kao i ostali startup job-ovi.
+	time.Sleep(1 * time.Minute)
+	RunAchievementBackfillOnce(db)
+}
+
+// RunAchievementBackfillOnce proverava sva pravila za sve korisnike; bez obaveštenja.
+func RunAchievementBackfillOnce(db *gorm.DB) {
+	awarded, err := achievements.Backfill(db)
+	if err != nil {
+		log.Println("[Achievement backfill job]", err)
+	}
+	if awarded > 0 {
+		log.Printf("[Achievement backfill job] dodeljeno %d bedževa iz istorije", awarded)
+	}
+}
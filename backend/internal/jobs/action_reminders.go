@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+
+	"gorm.io/gorm"
+)
+
+// actionReminderLead — podsetnik ide kada do polaska (ili dana akcije bez satnice) ostane manje od ovoga.
+const actionReminderLead = 24 * time.Hour
+
+// RunActionReminderJob svakog sata šalje podsetnike za akcije koje počinju u narednih 24 h.
+func RunActionReminderJob(db *gorm.DB) {
+	RunActionReminderOnce(db, time.Now())
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		RunActionReminderOnce(db, time.Now())
+	}
+}
+
+// RunActionReminderOnce šalje podsetnik jednom po akciji (podsetnik_poslat_at se postavlja pre slanja).
+func RunActionReminderOnce(db *gorm.DB, now time.Time) {
+	now = now.UTC()
+	until := now.Add(actionReminderLead)
+	var akcije []models.Akcija
+	if err := db.Where("is_completed = ? AND is_cancelled = ? AND podsetnik_poslat_at IS NULL AND "+helpers.SQLAkcijaObjavljena, false, false).
+		Where("(start_at IS NOT NULL AND start_at > ? AND start_at <= ?) OR (start_at IS NULL AND datum > ? AND datum <= ?)",
+			now, until, now, until).
+		Find(&akcije).Error; err != nil {
+		log.Println("[Action reminder job] čitanje akcija:", err)
+		return
+	}
+	loc := helpers.BelgradeLocation()
+	sent := 0
+	for i := range akcije {
+		a := &akcije[i]
+		res := db.Model(&models.Akcija{}).Where("id = ? AND podsetnik_poslat_at IS NULL", a.ID).Update("podsetnik_poslat_at", now)
+		if res.Error != nil {
+			log.Printf("[Action reminder job] akcija %d: %v", a.ID, res.Error)
+			continue
+		}
+		if res.RowsAffected == 0 {
+			continue // druga instanca je već poslala
+		}
+		recipients, err := actionRecipients(db, a)
+		if err != nil {
+			log.Printf("[Action reminder job] akcija %d: prijave: %v", a.ID, err)
+			continue
+		}
+		daylight := helpers.AkcijaDaylightInfo(a)
+		notifications.NotifyActionReminder(db, a, recipients, loc, daylight.Summary(), daylight != nil && daylight.UpozorenjeMrak)
+		sent++
+	}
+	if sent > 0 {
+		log.Printf("[Action reminder job] poslato podsetnika za %d akcija", sent)
+	}
+}
+
+// actionRecipients vraća prijavljene učesnike, vodiča i ko-vodiče akcije.
+func actionRecipients(db *gorm.DB, a *models.Akcija) ([]uint, error) {
+	var recipients []uint
+	if err := db.Model(&models.Prijava{}).Where("akcija_id = ? AND status = ?", a.ID, helpers.PrijavaStatusPrijavljen).
+		Distinct().Pluck("korisnik_id", &recipients).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range helpers.AkcijaGuideIDs(db, a) {
+		if !containsUint(recipients, id) {
+			recipients = append(recipients, id)
+		}
+	}
+	return recipients, nil
+}
+
+func containsUint(list []uint, v uint) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
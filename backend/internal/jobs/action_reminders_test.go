@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRunActionReminderOnce_SendsDaylightOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "action_reminders")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Korisnik{}, &models.Akcija{}, &models.Prijava{}, &models.Obavestenje{}); err != nil {
+		t.Fatal(err)
+	}
+	loc, err := time.LoadLocation("Europe/Belgrade")
+	if err != nil {
+		t.Skip("tzdata nije dostupan")
+	}
+	vodic := models.Korisnik{Username: "vodic", Role: "vodic"}
+	clan := models.Korisnik{Username: "clan", Role: "clan"}
+	otkazao := models.Korisnik{Username: "otkazao", Role: "clan"}
+	db.Create(&vodic)
+	db.Create(&clan)
+	db.Create(&otkazao)
+
+	now := time.Date(2026, 12, 11, 12, 0, 0, 0, loc)
+	// Zimski uspon: polazak u 10 h, 8 h hoda — kraj posle građanskog sumraka (~16:35).
+	start := time.Date(2026, 12, 12, 10, 0, 0, 0, loc)
+	lat, lng := 43.3, 22.1
+	akcija := models.Akcija{Naziv: "Trem", Datum: time.Date(2026, 12, 12, 0, 0, 0, 0, time.UTC), StartAt: &start,
+		TrajanjeSati: 8, PlaninaLat: &lat, PlaninaLng: &lng, VodicID: vodic.ID, ZimskiUspon: true}
+	later := start.AddDate(0, 0, 3)
+	kasnija := models.Akcija{Naziv: "Kasnije", Datum: later.UTC(), StartAt: &later, VodicID: vodic.ID}
+	db.Create(&akcija)
+	db.Create(&kasnija)
+	db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: clan.ID, Status: "prijavljen"})
+	db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: otkazao.ID, Status: "otkazano"})
+
+	RunActionReminderOnce(db, now)
+	RunActionReminderOnce(db, now.Add(time.Hour))
+
+	var rows []models.Obavestenje
+	db.Where("type = ?", models.ObavestenjeTipActionReminder).Order("user_id").Find(&rows)
+	if len(rows) != 2 || rows[0].UserID != vodic.ID || rows[1].UserID != clan.ID {
+		t.Fatalf("reminder must go once to the guide and the registered participant, got %+v", rows)
+	}
+	body := rows[1].Body
+	if !strings.Contains(body, "u 10:00") || !strings.Contains(body, "Izlazak Sunca") || !strings.Contains(body, "građanskog sumraka") {
+		t.Fatalf("reminder must include start time, daylight and dark warning: %q", body)
+	}
+	if !strings.Contains(rows[1].Title, "lampu") {
+		t.Fatalf("dark warning must show in the title: %q", rows[1].Title)
+	}
+	var reloaded models.Akcija
+	db.First(&reloaded, kasnija.ID)
+	if reloaded.PodsetnikPoslatAt != nil {
+		t.Fatal("action more than 24 h away must not be reminded yet")
+	}
+}
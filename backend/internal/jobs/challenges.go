@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"beleg-app/backend/internal/challenges"
+
+	"gorm.io/gorm"
+)
+
+// RunChallengeFinalizeJob periodično zamrzava rezultate završenih izazova i šalje obaveštenja.
+func RunChallengeFinalizeJob(db *gorm.DB) {
+	RunChallengeFinalizeOnce(db)
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		RunChallengeFinalizeOnce(db)
+	}
+}
+
+// RunChallengeFinalizeOnce zamrzava izazove kojima je prošao poslednji dan (boot + periodično).
+func RunChallengeFinalizeOnce(db *gorm.DB) {
+	finalized, err := challenges.FinalizeDue(db, time.Now())
+	if err != nil {
+		log.Println("[Challenge finalize job]", err)
+	}
+	if finalized > 0 {
+		log.Printf("[Challenge finalize job] zamrznuto %d izazova", finalized)
+	}
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RunIdempotencyKeyPruneJob jednom na sat briše istekle Idempotency-Key zapise.
+func RunIdempotencyKeyPruneJob(db *gorm.DB) {
+	RunIdempotencyKeyPruneOnce(db)
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		RunIdempotencyKeyPruneOnce(db)
+	}
+}
+
+// RunIdempotencyKeyPruneOnce briše ključeve kojima je istekao TTL (boot + periodično).
+func RunIdempotencyKeyPruneOnce(db *gorm.DB) {
+	res := db.Where("expires_at < ?", time.Now().UTC()).Delete(&models.IdempotencyKey{})
+	if res.Error != nil {
+		log.Println("[Idempotency job]", res.Error)
+		return
+	}
+	if res.RowsAffected > 0 {
+		log.Printf("[Idempotency job] obrisano %d isteklih ključeva", res.RowsAffected)
+	}
+}
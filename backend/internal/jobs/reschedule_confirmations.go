@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+	"beleg-app/backend/internal/services/actions"
+
+	"gorm.io/gorm"
+)
+
+// RunRescheduleConfirmationJob svakih 15 min oslobađa mesta učesnika koji nisu potvrdili pomeren termin.
+func RunRescheduleConfirmationJob(db *gorm.DB) {
+	RunRescheduleConfirmationOnce(db, time.Now())
+	ticker := time.NewTicker(15 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		RunRescheduleConfirmationOnce(db, time.Now())
+	}
+}
+
+// RunRescheduleConfirmationOnce oslobađa istekle prijave, dodeljuje mesta sledećima na čekanju i šalje obaveštenja.
+func RunRescheduleConfirmationOnce(db *gorm.DB, now time.Time) {
+	res, err := actions.ReleaseUnconfirmedPrijave(db, now.UTC())
+	if err != nil {
+		log.Println("[Reschedule confirmation job]", err)
+	}
+	if res == nil {
+		return
+	}
+	akcije := map[uint]*models.Akcija{}
+	akcijaByID := func(id uint) *models.Akcija {
+		if a, ok := akcije[id]; ok {
+			return a
+		}
+		var a models.Akcija
+		if err := db.First(&a, id).Error; err != nil {
+			log.Printf("[Reschedule confirmation job] akcija %d: %v", id, err)
+			akcije[id] = nil
+			return nil
+		}
+		akcije[id] = &a
+		return &a
+	}
+	for _, r := range res.Released {
+		notifications.NotifyActionSpotReleased(db, akcijaByID(r.AkcijaID), r.KorisnikID)
+	}
+	for i := range res.Granted {
+		req := &res.Granted[i]
+		notifications.NotifyActionSpotGranted(db, &req.Akcija, req.RequesterID, req.ID)
+	}
+	if len(res.Released) > 0 {
+		log.Printf("[Reschedule confirmation job] oslobođeno %d mesta, dodeljeno %d", len(res.Released), len(res.Granted))
+	}
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRunRescheduleConfirmationOnce_NotifiesReleasedAndGranted(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "reschedule_confirmations")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Korisnik{}, &models.Akcija{}, &models.Prijava{}, &models.PrijavaIzbori{},
+		&models.ActionSignupRequest{}, &models.Obavestenje{}); err != nil {
+		t.Fatal(err)
+	}
+	silent := models.Korisnik{Username: "tih", Role: "clan"}
+	waiting := models.Korisnik{Username: "ceka", Role: "clan"}
+	db.Create(&silent)
+	db.Create(&waiting)
+	now := time.Now()
+	akcija := models.Akcija{Naziv: "Trem", Datum: now.AddDate(0, 0, 10).UTC().Truncate(24 * time.Hour), MaxLjudi: 1}
+	db.Create(&akcija)
+	deadline := now.Add(-time.Minute)
+	db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: silent.ID, Status: "prijavljen", PotvrdaDo: &deadline})
+	db.Create(&models.ActionSignupRequest{AkcijaID: akcija.ID, RequesterID: waiting.ID, Status: models.ActionSignupRequestPending})
+
+	RunRescheduleConfirmationOnce(db, now)
+	RunRescheduleConfirmationOnce(db, now.Add(time.Hour))
+
+	var released, granted []models.Obavestenje
+	db.Where("type = ?", models.ObavestenjeTipActionSpotReleased).Find(&released)
+	db.Where("type = ?", models.ObavestenjeTipActionSpotGranted).Find(&granted)
+	if len(released) != 1 || released[0].UserID != silent.ID {
+		t.Fatalf("released notifications: %+v", released)
+	}
+	if len(granted) != 1 || granted[0].UserID != waiting.ID {
+		t.Fatalf("granted notifications: %+v", granted)
+	}
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"beleg-app/backend/internal/notifications"
+	"beleg-app/backend/internal/services/actions"
+
+	"gorm.io/gorm"
+)
+
+// RunScheduledPublishJob svakog minuta objavljuje zakazane akcije.
+func RunScheduledPublishJob(db *gorm.DB) {
+	RunScheduledPublishOnce(db, time.Now())
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		RunScheduledPublishOnce(db, time.Now())
+	}
+}
+
+// RunScheduledPublishOnce objavljuje akcije kojima je došlo ObjaviAt i tek tada šalje „nova akcija”.
+func RunScheduledPublishOnce(db *gorm.DB, now time.Time) {
+	published, err := actions.PublishDueActions(db, now.UTC())
+	if err != nil {
+		log.Println("[Scheduled publish job]", err)
+	}
+	for i := range published {
+		notifications.NotifyActionPublished(db, &published[i])
+	}
+	if len(published) > 0 {
+		log.Printf("[Scheduled publish job] objavljeno %d akcija", len(published))
+	}
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"beleg-app/backend/internal/deltasync"
+
+	"gorm.io/gorm"
+)
+
+// RunSyncTombstonePruneJob jednom dnevno briše tombstone-ove delta sync-a starije od deltasync.TombstoneRetention.
+func RunSyncTombstonePruneJob(db *gorm.DB) {
+	RunSyncTombstonePruneOnce(db)
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		RunSyncTombstonePruneOnce(db)
+	}
+}
+
+// RunSyncTombstonePruneOnce briše zastarele tombstone-ove (boot + periodično).
+func RunSyncTombstonePruneOnce(db *gorm.DB) {
+	deleted, err := deltasync.PruneTombstones(db, time.Now())
+	if err != nil {
+		log.Println("[Sync tombstone job]", err)
+	}
+	if deleted > 0 {
+		log.Printf("[Sync tombstone job] obrisano %d tombstone-ova", deleted)
+	}
+}
@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/notifications"
+	"beleg-app/backend/internal/weather"
+
+	"gorm.io/gorm"
+)
+
+// weatherAlertLead — prognoza se prati za akcije koje počinju u narednih 48 h.
+const weatherAlertLead = 48 * time.Hour
+
+// RunWeatherAlertJob svakog sata proverava prognozu za predstojeće akcije i briše isteklu keširanu prognozu.
+func RunWeatherAlertJob(db *gorm.DB) {
+	provider := weather.ProviderFromEnv()
+	if provider == nil {
+		log.Println("[Weather alert job] prognoza isključena (WEATHER_PROVIDER=off)")
+		return
+	}
+	cached := weather.CachedProvider{Inner: provider, DB: db}
+	RunWeatherAlertOnce(db, cached, time.Now())
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		RunWeatherAlertOnce(db, cached, time.Now())
+	}
+}
+
+// RunWeatherAlertOnce javlja vodiču i učesnicima samo rizike koji za akciju još nisu javljeni
+// (novi rizik, npr. grmljavina posle vetra, šalje novo upozorenje).
+func RunWeatherAlertOnce(db *gorm.DB, provider weather.Provider, now time.Time) {
+	now = now.UTC()
+	if n, err := weather.PruneCache(db, now); err != nil {
+		log.Println("[Weather alert job] brisanje keša:", err)
+	} else if n > 0 {
+		log.Printf("[Weather alert job] obrisano %d isteklih prognoza", n)
+	}
+	until := now.Add(weatherAlertLead)
+	var akcije []models.Akcija
+	if err := db.Where("is_completed = ? AND is_cancelled = ? AND planina_lat IS NOT NULL AND planina_lng IS NOT NULL AND "+helpers.SQLAkcijaObjavljena, false, false).
+		Where("(start_at IS NOT NULL AND start_at > ? AND start_at <= ?) OR (start_at IS NULL AND datum > ? AND datum <= ?)",
+			now, until, now.Add(-24*time.Hour), until).
+		Find(&akcije).Error; err != nil {
+		log.Println("[Weather alert job] čitanje akcija:", err)
+		return
+	}
+	loc := helpers.BelgradeLocation()
+	for i := range akcije {
+		a := &akcije[i]
+		if _, to := helpers.AkcijaTimeWindow(a); !to.After(now) {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		prognoza, err := helpers.AkcijaWeather(ctx, provider, a)
+		cancel()
+		if err != nil {
+			log.Printf("[Weather alert job] akcija %d: %v", a.ID, err)
+			continue
+		}
+		if prognoza == nil || len(prognoza.Upozorenja) == 0 {
+			continue
+		}
+		known := map[string]bool{}
+		for _, k := range strings.Split(a.VremeUpozorenja, ",") {
+			if k != "" {
+				known[k] = true
+			}
+		}
+		var fresh []weather.Alert
+		for _, al := range prognoza.Upozorenja {
+			if !known[al.Tip] {
+				fresh = append(fresh, al)
+				known[al.Tip] = true
+			}
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+		all := make([]weather.Alert, 0, len(known))
+		for k := range known {
+			all = append(all, weather.Alert{Tip: k})
+		}
+		res := db.Model(&models.Akcija{}).Where("id = ? AND vreme_upozorenja = ?", a.ID, a.VremeUpozorenja).
+			Update("vreme_upozorenja", weather.AlertKinds(all))
+		if res.Error != nil || res.RowsAffected == 0 {
+			continue // greška ili je druga instanca već javila
+		}
+		recipients, err := actionRecipients(db, a)
+		if err != nil {
+			log.Printf("[Weather alert job] akcija %d: prijave: %v", a.ID, err)
+			continue
+		}
+		notifications.NotifyWeatherAlert(db, a, recipients, fresh, loc)
+	}
+}
@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+	"beleg-app/backend/internal/weather"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type fakeWeather struct{ hours []weather.Hour }
+
+func (f *fakeWeather) Name() string { return "fake" }
+
+func (f *fakeWeather) Forecast(ctx context.Context, lat, lng float64, elevationM *float64) (*weather.Forecast, error) {
+	return &weather.Forecast{Lat: lat, Lng: lng, ElevationM: 1810, Izvor: "fake", Hours: f.hours}, nil
+}
+
+func TestRunWeatherAlertOnce_NotifiesNewRisksOnly(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "weather_alerts")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Korisnik{}, &models.Akcija{}, &models.Prijava{}, &models.Obavestenje{}, &models.WeatherCache{}); err != nil {
+		t.Fatal(err)
+	}
+	vodic := models.Korisnik{Username: "vodic", Role: "vodic"}
+	clan := models.Korisnik{Username: "clan", Role: "clan"}
+	db.Create(&vodic)
+	db.Create(&clan)
+	now := time.Date(2026, 12, 11, 9, 0, 0, 0, time.UTC)
+	start := now.Add(26 * time.Hour)
+	lat, lng := 43.3, 22.1
+	akcija := models.Akcija{Naziv: "Trem", Datum: start.Truncate(24 * time.Hour), StartAt: &start, TrajanjeSati: 6,
+		PlaninaLat: &lat, PlaninaLng: &lng, VisinaVrhM: 1810, VodicID: vodic.ID}
+	daleka := models.Akcija{Naziv: "Za nedelju dana", Datum: start.AddDate(0, 0, 7), PlaninaLat: &lat, PlaninaLng: &lng, VodicID: vodic.ID}
+	db.Create(&akcija)
+	db.Create(&daleka)
+	db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: clan.ID, Status: "prijavljen"})
+
+	provider := &fakeWeather{hours: []weather.Hour{
+		{Time: start, WindKmh: 30, GustKmh: 70},
+		{Time: start.Add(time.Hour), WindKmh: 20, GustKmh: 40},
+		{Time: start.Add(10 * time.Hour), PrecipMm: 9}, // posle ture — ne računa se
+	}}
+	RunWeatherAlertOnce(db, provider, now)
+	RunWeatherAlertOnce(db, provider, now.Add(time.Hour))
+
+	var rows []models.Obavestenje
+	db.Where("type = ?", models.ObavestenjeTipWeatherAlert).Order("id").Find(&rows)
+	if len(rows) != 2 || !strings.Contains(rows[0].Body, "udari vetra do 70 km/h") || !strings.Contains(rows[0].Body, "na 1810 m") {
+		t.Fatalf("wind alert must be sent once to guide and participant, got %+v", rows)
+	}
+	if strings.Contains(rows[0].Body, "padavine") {
+		t.Fatalf("rain after the tour window must not alert: %q", rows[0].Body)
+	}
+
+	// Prognoza se pogoršala: grmljavina je novi rizik, vetar se ne ponavlja.
+	provider.hours = append(provider.hours, weather.Hour{Time: start.Add(2 * time.Hour), WeatherCode: 95, Thunderstorm: true, GustKmh: 65})
+	RunWeatherAlertOnce(db, provider, now.Add(2*time.Hour))
+	rows = nil
+	db.Where("type = ?", models.ObavestenjeTipWeatherAlert).Order("id").Find(&rows)
+	if len(rows) != 4 || !strings.Contains(rows[3].Body, "grmljavine") || strings.Contains(rows[3].Body, "vetra") {
+		t.Fatalf("only the new thunderstorm risk must be sent, got %+v", rows)
+	}
+	var reloaded models.Akcija
+	db.First(&reloaded, akcija.ID)
+	if reloaded.VremeUpozorenja != "grmljavina,vetar" {
+		t.Fatalf("notified risks = %q", reloaded.VremeUpozorenja)
+	}
+}
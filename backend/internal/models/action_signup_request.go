@@ -17,6 +17,11 @@ type ActionSignupRequest struct {
 	SelectedSmestajIDs   string     `gorm:"type:text" json:"-"`
 	SelectedPrevozIDs    string     `gorm:"type:text" json:"-"`
 	SelectedRentItemsRaw string     `gorm:"type:text" json:"-"`
+	Odgovori             string     `gorm:"type:text" json:"-"`
+	FormaVerzija         int        `gorm:"not null;default:0" json:"formaVerzija"`
+	CenaObracun          string     `gorm:"type:text" json:"-"` // cena obračunata pri slanju zahteva, prenosi se na prijavu
+	PromoKodID           *uint      `gorm:"index" json:"promoKodId,omitempty"`
+	Grupa                string     `gorm:"type:varchar(60);not null;default:''" json:"grupa,omitempty"`
 	ReviewedByID         *uint      `gorm:"index" json:"reviewedById,omitempty"`
 	RespondedAt          *time.Time `json:"respondedAt,omitempty"`
 	CreatedAt            time.Time  `gorm:"autoCreateTime" json:"createdAt"`
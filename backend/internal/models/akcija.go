@@ -10,9 +10,10 @@ type Akcija struct {
 	Naziv   string `json:"naziv"`
 	Planina string `json:"planina"` // Ime planine
 	// Tačka na mapi za planinarske akcije (vezivanje hotela/vodiča po lokaciji); za via ferrata često iz kataloga ferate.
-	PlaninaLat  *float64  `gorm:"column:planina_lat" json:"planinaLat,omitempty"`
-	PlaninaLng  *float64  `gorm:"column:planina_lng" json:"planinaLng,omitempty"`
+	PlaninaLat  *float64  `gorm:"column:planina_lat;index:idx_akcije_planina_lat_lng,priority:1" json:"planinaLat,omitempty"`
+	PlaninaLng  *float64  `gorm:"column:planina_lng;index:idx_akcije_planina_lat_lng,priority:2" json:"planinaLng,omitempty"`
 	Vrh         string    `json:"vrh"`
+	PeakID      *uint     `gorm:"column:peak_id;index" json:"peakId,omitempty"` // Vrh iz kataloga (dnevnik uspona); Vrh ostaje tekst za prikaz
 	Datum       time.Time `json:"datum"`
 	Opis        string    `json:"opis,omitempty"`
 	Tezina      string    `json:"tezina,omitempty"`
@@ -64,8 +65,25 @@ type Akcija struct {
 	CenaOstali               float64         `gorm:"default:0" json:"cenaOstali"`
 	PrikaziListuPrijavljenih bool            `gorm:"default:true" json:"prikaziListuPrijavljenih"`
 	OmoguciGrupniChat        bool            `gorm:"default:false" json:"omoguciGrupniChat"`
+	PodsetnikPoslatAt        *time.Time      `gorm:"column:podsetnik_poslat_at" json:"-"`                                    // podsetnik 24 h pre polaska (jobs.RunActionReminderJob)
+	VremeUpozorenja          string          `gorm:"column:vreme_upozorenja;type:varchar(100);not null;default:''" json:"-"` // već javljeni rizici (jobs.RunWeatherAlertJob), npr. "grmljavina,vetar"
+
+	// Objavljivanje: samo objavljena akcija je u listama/pretrazi i prima prijave; ostale vidi samo ko njome upravlja.
+	StatusObjave    string     `gorm:"column:status_objave;type:varchar(20);not null;default:'objavljena';index" json:"statusObjave"`
+	ObjaviAt        *time.Time `gorm:"column:objavi_at;index" json:"objaviAt,omitempty"`                                       // zakazano objavljivanje (jobs.RunScheduledPublishJob)
+	ObjavljenaAt    *time.Time `gorm:"column:objavljena_at" json:"objavljenaAt,omitempty"`                                     // kada je akcija postala vidljiva
+	PregledaoID     *uint      `gorm:"column:pregledao_id" json:"pregledaoId,omitempty"`                                       // admin kluba koji je odobrio/vratio akciju
+	PregledNapomena string     `gorm:"column:pregled_napomena;type:text;not null;default:''" json:"pregledNapomena,omitempty"` // razlog vraćanja u nacrt
 }
 
+// Statusi objavljivanja akcije (StatusObjave).
+const (
+	AkcijaStatusNacrt      = "nacrt"       // vidi samo autor/vodič i admin kluba
+	AkcijaStatusNaPregledu = "na_pregledu" // akcija vodiča čeka odobrenje admina kluba
+	AkcijaStatusZakazana   = "zakazana"    // biće objavljena u ObjaviAt
+	AkcijaStatusObjavljena = "objavljena"
+)
+
 // TableName specifies the table name for the Akcija model
 func (Akcija) TableName() string {
 	return "akcije"
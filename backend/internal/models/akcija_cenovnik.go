@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// AkcijaCenovnik dopunjuje Akcija.CenaClan/CenaOstali: uzrasne kategorije (iz Korisnik.DatumRodjenja na dan akcije),
+// early bird cene do datuma i grupni popust. Nil cena kategorije znači da kategorija ne postoji.
+// Primenjena cena se pamti na prijavi (PrijavaIzbori.CenaObracun) pa je kasnije izmene ne menjaju.
+type AkcijaCenovnik struct {
+	ID                  uint       `gorm:"primaryKey" json:"id"`
+	AkcijaID            uint       `gorm:"not null;uniqueIndex" json:"akcijaId"`
+	CenaDete            *float64   `json:"cenaDete,omitempty"`
+	DeteDoGodina        int        `gorm:"not null;default:14" json:"deteDoGodina"` // uključivo
+	CenaStudent         *float64   `json:"cenaStudent,omitempty"`
+	StudentDoGodina     int        `gorm:"not null;default:26" json:"studentDoGodina"` // uključivo, posle uzrasta deteta
+	CenaSenior          *float64   `json:"cenaSenior,omitempty"`
+	SeniorOdGodina      int        `gorm:"not null;default:65" json:"seniorOdGodina"`
+	EarlyBirdDo         *time.Time `json:"earlyBirdDo,omitempty"`
+	EarlyBirdCenaClan   *float64   `json:"earlyBirdCenaClan,omitempty"`
+	EarlyBirdCenaOstali *float64   `json:"earlyBirdCenaOstali,omitempty"`
+	GrupaMinOsoba       int        `gorm:"not null;default:0" json:"grupaMinOsoba"` // 0 = bez grupnog popusta
+	GrupaPopustProcenat float64    `gorm:"not null;default:0" json:"grupaPopustProcenat"`
+	IzmenioID           uint       `gorm:"not null;default:0" json:"izmenioId"`
+	CreatedAt           time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt           time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (AkcijaCenovnik) TableName() string {
+	return "akcija_cenovnici"
+}
+
+// AkcijaPromoKod je popust na cenu akcije koji član unosi pri prijavi. Iskorišćenja se broje iz aktivnih
+// prijava i zahteva na čekanju (PromoKodID), pa otkazivanje oslobađa mesto.
+type AkcijaPromoKod struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	AkcijaID       uint       `gorm:"not null;uniqueIndex:idx_akcija_promo_kodovi_akcija_kod,priority:1" json:"akcijaId"`
+	Kod            string     `gorm:"type:varchar(40);not null;uniqueIndex:idx_akcija_promo_kodovi_akcija_kod,priority:2" json:"kod"` // velika slova
+	PopustProcenat float64    `gorm:"not null;default:0" json:"popustProcenat"`
+	PopustIznos    float64    `gorm:"not null;default:0" json:"popustIznos"`
+	MaxKoriscenja  int        `gorm:"not null;default:0" json:"maxKoriscenja"` // 0 = neograničeno
+	VaziDo         *time.Time `json:"vaziDo,omitempty"`
+	Aktivan        bool       `gorm:"not null;default:true" json:"aktivan"`
+	KreiraoID      uint       `gorm:"not null;default:0" json:"kreiraoId"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (AkcijaPromoKod) TableName() string {
+	return "akcija_promo_kodovi"
+}
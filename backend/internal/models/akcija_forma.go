@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+const (
+	FormaPoljeTipText   = "text"
+	FormaPoljeTipChoice = "choice"
+	FormaPoljeTipNumber = "number"
+)
+
+// AkcijaForma su dodatna pitanja pri prijavi na akciju. Polja je JSON []helpers.FormaPolje; odgovori su
+// u PrijavaIzbori.Odgovori / ActionSignupRequest.Odgovori (mapa id polja → odgovor) uz verziju forme.
+type AkcijaForma struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	AkcijaID  uint      `gorm:"not null;uniqueIndex" json:"akcijaId"`
+	Polja     string    `gorm:"type:text;not null;default:'[]'" json:"-"`
+	Verzija   int       `gorm:"not null;default:1" json:"verzija"`
+	IzmenioID uint      `gorm:"not null;default:0" json:"izmenioId"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (AkcijaForma) TableName() string {
+	return "akcija_forme"
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// AkcijaPomeranje je zapis o pomeranju termina akcije (umesto otkazivanja).
+// Prijave ostaju; prijavljeni učesnici moraju da potvrde novi termin do PotvrdaDo (Prijava.PotvrdaDo).
+type AkcijaPomeranje struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	AkcijaID        uint       `gorm:"not null;index" json:"akcijaId"`
+	StariDatum      time.Time  `gorm:"not null" json:"stariDatum"`
+	StariStartAt    *time.Time `json:"stariStartAt,omitempty"`
+	StariEndAt      *time.Time `json:"stariEndAt,omitempty"`
+	StariRokPrijava *time.Time `json:"stariRokPrijava,omitempty"`
+	NoviDatum       time.Time  `gorm:"not null" json:"noviDatum"`
+	NoviStartAt     *time.Time `json:"noviStartAt,omitempty"`
+	NoviEndAt       *time.Time `json:"noviEndAt,omitempty"`
+	NoviRokPrijava  *time.Time `json:"noviRokPrijava,omitempty"`
+	Razlog          string     `gorm:"type:text;not null;default:''" json:"razlog"`
+	PotvrdaDo       time.Time  `gorm:"not null" json:"potvrdaDo"`
+	BrojZaPotvrdu   int        `gorm:"not null;default:0" json:"brojZaPotvrdu"` // prijavljenih kojima je traženo da potvrde
+	PromenioID      uint       `gorm:"not null;default:0" json:"promenioId"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (AkcijaPomeranje) TableName() string {
+	return "akcija_pomeranja"
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// AkcijaRuta je uvezena trasa akcije (GPX ili polyline) sa visinama po tački i sažetkom profila.
+// Visine su JSON niz poravnat sa tačkama polyline-a (null kada visina nije poznata).
+type AkcijaRuta struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	AkcijaID    uint      `gorm:"not null;uniqueIndex" json:"akcijaId"`
+	Polyline    string    `gorm:"type:text;not null" json:"polyline"`
+	Visine      string    `gorm:"type:text" json:"-"`
+	Izvor       string    `gorm:"type:varchar(20);not null" json:"izvor"` // gpx | polyline | tacke
+	DuzinaM     float64   `gorm:"not null;default:0" json:"duzinaM"`
+	UsponM      float64   `gorm:"not null;default:0" json:"ukupnoUsponM"`
+	SpustM      float64   `gorm:"not null;default:0" json:"ukupnoSpustM"`
+	MaxNagibPct float64   `gorm:"not null;default:0" json:"maxNagibPct"`
+	UvezaoID    uint      `gorm:"not null;default:0" json:"uvezaoId"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (AkcijaRuta) TableName() string {
+	return "akcija_rute"
+}
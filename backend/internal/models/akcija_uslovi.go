@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// AkcijaUslovi su uslovi za prijavu na zahtevnu akciju (alpinizam, via ferrata). Nula ili prazno polje se ne proverava.
+// Provera je u helpers.CheckAkcijaEligibilityTx (slanje i prihvatanje zahteva za prijavu).
+type AkcijaUslovi struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	AkcijaID         uint      `gorm:"not null;uniqueIndex" json:"akcijaId"`
+	MinBrojAkcija    int       `gorm:"not null;default:0" json:"minBrojAkcija"`                         // završene akcije (status "popeo se")
+	TipAkcije        string    `gorm:"type:varchar(30);not null;default:''" json:"tipAkcije,omitempty"` // broje se samo akcije ovog tipa
+	MinTezina        string    `gorm:"type:varchar(20);not null;default:''" json:"minTezina,omitempty"` // broje se akcije ove ili veće težine
+	MinVisinaM       int       `gorm:"not null;default:0" json:"minVisinaM"`                            // najviši vrh na koji se član popeo
+	ClanarinaPlacena bool      `gorm:"not null;default:false" json:"clanarinaPlacena"`                  // članarina za godinu akcije
+	Sertifikati      string    `gorm:"type:text;not null;default:'[]'" json:"-"`                        // JSON []string naziva (KorisnikSertifikat.Naziv)
+	MinGodine        int       `gorm:"not null;default:0" json:"minGodine"`                             // na dan akcije, iz Korisnik.DatumRodjenja
+	IzmenioID        uint      `gorm:"not null;default:0" json:"izmenioId"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (AkcijaUslovi) TableName() string {
+	return "akcija_uslovi"
+}
+
+// AkcijaUslovIzuzetak: organizator je dozvolio prijavu članu koji ne ispunjava uslove akcije.
+type AkcijaUslovIzuzetak struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	AkcijaID   uint      `gorm:"not null;uniqueIndex:idx_akcija_uslov_izuzeci_akcija_korisnik,priority:1" json:"akcijaId"`
+	KorisnikID uint      `gorm:"not null;uniqueIndex:idx_akcija_uslov_izuzeci_akcija_korisnik,priority:2;index" json:"korisnikId"`
+	OdobrioID  uint      `gorm:"not null;default:0" json:"odobrioId"`
+	Napomena   string    `gorm:"type:text;not null;default:''" json:"napomena,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	Korisnik Korisnik `gorm:"foreignKey:KorisnikID" json:"-"`
+}
+
+func (AkcijaUslovIzuzetak) TableName() string {
+	return "akcija_uslov_izuzeci"
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	AkcijaVerzijaIzvorKreiranje = "kreiranje"
+	AkcijaVerzijaIzvorIzmena    = "izmena"
+	AkcijaVerzijaIzvorPrevoz    = "prevoz"
+	AkcijaVerzijaIzvorPomeranje = "pomeranje"
+)
+
+// AkcijaVerzija je jedna verzija akcije u istoriji izmena: snimak posle izmene i razlika u odnosu na prethodno stanje.
+// Snapshot je helpers.AkcijaSnapshot (akcija + smeštaj/prevoz/oprema), Promene je []helpers.AkcijaPromena (JSON).
+type AkcijaVerzija struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	AkcijaID         uint      `gorm:"not null;uniqueIndex:idx_akcija_verzije_akcija_verzija,priority:1" json:"akcijaId"`
+	Verzija          int       `gorm:"not null;uniqueIndex:idx_akcija_verzije_akcija_verzija,priority:2" json:"verzija"`
+	Izvor            string    `gorm:"type:varchar(20);not null" json:"izvor"`
+	Snapshot         string    `gorm:"type:text;not null" json:"-"`
+	Promene          string    `gorm:"type:text;not null;default:'[]'" json:"-"`
+	Materijalna      bool      `gorm:"not null;default:false" json:"materijalna"`      // datum, cena, mesto polaska ili težina
+	PoskupljenjeCene bool      `gorm:"not null;default:false" json:"poskupljenjeCene"` // učesnici mogu da odustanu bez penala
+	PromenioID       uint      `gorm:"not null;default:0" json:"promenioId"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (AkcijaVerzija) TableName() string {
+	return "akcija_verzije"
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	AkcijaVodicUlogaGlavni   = "glavni"   // Akcija.VodicID — nije red u akcija_vodici
+	AkcijaVodicUlogaPomocni  = "pomocni"  // pomoćni vodič
+	AkcijaVodicUlogaZatvarac = "zatvarac" // zatvara kolonu
+)
+
+// AkcijaVodic je ko-vodič akcije (pomoćni vodič ili zatvarač), i iz drugog kluba.
+// Glavni vodič ostaje Akcija.VodicID. GuideProfileID je popunjen kad vodič ima odobren profi profil.
+type AkcijaVodic struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	AkcijaID       uint      `gorm:"not null;uniqueIndex:idx_akcija_vodici_akcija_korisnik,priority:1" json:"akcijaId"`
+	KorisnikID     uint      `gorm:"not null;uniqueIndex:idx_akcija_vodici_akcija_korisnik,priority:2;index" json:"korisnikId"`
+	GuideProfileID *uint     `json:"guideProfileId,omitempty"`
+	Uloga          string    `gorm:"type:varchar(20);not null;default:'pomocni'" json:"uloga"`
+	DodaoID        uint      `gorm:"not null;default:0" json:"dodaoId"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	Korisnik Korisnik `gorm:"foreignKey:KorisnikID" json:"-"`
+}
+
+func (AkcijaVodic) TableName() string {
+	return "akcija_vodici"
+}
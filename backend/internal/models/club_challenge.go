@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+const (
+	ChallengeMetrikaKoraci = "koraci" // dnevni koraci (user_daily_steps)
+	ChallengeMetrikaKm     = "km"     // pređeni km sa završenih GPS aktivnosti
+	ChallengeMetrikaUspon  = "uspon"  // metri visinske razlike sa završenih GPS aktivnosti
+	ChallengeMetrikaVrhovi = "vrhovi" // usponi iz dnevnika + „popeo se“ na akcijama bez vrha iz kataloga
+
+	ChallengeTipIndividualni = "individualni"
+	ChallengeTipTimski       = "timski"
+
+	ChallengeObuhvatKlub    = "klub"    // samo članovi kluba organizatora
+	ChallengeObuhvatKlubovi = "klubovi" // članovi bilo kog kluba; timski = tim po klubu
+)
+
+// ClubChallenge — vremenski ograničen izazov (od–do, uključivo) sa jednom metrikom.
+// Učešće je opt-in; posle kraja rezultati se zamrzavaju u club_challenge_results (ZavrsenoAt != nil).
+type ClubChallenge struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Naziv        string     `gorm:"type:varchar(150);not null" json:"naziv"`
+	Opis         string     `gorm:"type:text" json:"opis,omitempty"`
+	Metrika      string     `gorm:"type:varchar(20);not null" json:"metrika"`
+	Tip          string     `gorm:"type:varchar(20);not null;default:'individualni'" json:"tip"`
+	Obuhvat      string     `gorm:"type:varchar(20);not null;default:'klub'" json:"obuhvat"`
+	KlubID       *uint      `gorm:"index" json:"klubId,omitempty"` // klub organizator; nil = superadmin izazov za sve klubove
+	PocetakDatum time.Time  `gorm:"type:date;not null" json:"pocetakDatum"`
+	KrajDatum    time.Time  `gorm:"type:date;not null;index" json:"krajDatum"`
+	ZavrsenoAt   *time.Time `json:"zavrsenoAt,omitempty"`
+	CreatedByID  uint       `gorm:"not null" json:"createdById"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (ClubChallenge) TableName() string {
+	return "club_challenges"
+}
+
+// ClubChallengeTeam — tim u timskom izazovu; kod međuklubskog izazova tim = klub (KlubID).
+type ClubChallengeTeam struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	ChallengeID uint   `gorm:"not null;index" json:"challengeId"`
+	Naziv       string `gorm:"type:varchar(150);not null" json:"naziv"`
+	KlubID      *uint  `gorm:"index" json:"klubId,omitempty"`
+}
+
+func (ClubChallengeTeam) TableName() string {
+	return "club_challenge_teams"
+}
+
+// ClubChallengeParticipant — korisnik koji se prijavio na izazov (i tim kod timskog).
+type ClubChallengeParticipant struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ChallengeID uint      `gorm:"not null;uniqueIndex:uidx_club_challenge_participants_challenge_korisnik" json:"challengeId"`
+	KorisnikID  uint      `gorm:"not null;uniqueIndex:uidx_club_challenge_participants_challenge_korisnik;index" json:"korisnikId"`
+	TeamID      *uint     `gorm:"index" json:"teamId,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (ClubChallengeParticipant) TableName() string {
+	return "club_challenge_participants"
+}
+
+// ClubChallengeResult — zamrznut plasman posle kraja izazova; red je ili za korisnika ili za tim.
+type ClubChallengeResult struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	ChallengeID uint    `gorm:"not null;index" json:"challengeId"`
+	KorisnikID  *uint   `gorm:"index" json:"korisnikId,omitempty"`
+	TeamID      *uint   `json:"teamId,omitempty"`
+	Vrednost    float64 `gorm:"not null;default:0" json:"vrednost"`
+	Rang        int     `gorm:"not null" json:"rang"`
+}
+
+func (ClubChallengeResult) TableName() string {
+	return "club_challenge_results"
+}
@@ -30,8 +30,8 @@ type Ferrata struct {
 	WhoBeginnersText    string          `gorm:"column:who_beginners_text;type:varchar(200)" json:"whoBeginnersText"`
 	WhoRecreationalText string          `gorm:"column:who_recreational_text;type:varchar(200)" json:"whoRecreationalText"`
 	WhoExperiencedText  string          `gorm:"column:who_experienced_text;type:varchar(200)" json:"whoExperiencedText"`
-	Lat                 *float64        `gorm:"column:lat" json:"lat"` // glavna tačka — API superadmin zahteva obe; NULL samo u starim redovima
-	Lng                 *float64        `gorm:"column:lng" json:"lng"`
+	Lat                 *float64        `gorm:"column:lat;index:idx_ferratas_lat_lng,priority:1" json:"lat"` // glavna tačka — API superadmin zahteva obe; NULL samo u starim redovima
+	Lng                 *float64        `gorm:"column:lng;index:idx_ferratas_lat_lng,priority:2" json:"lng"`
 	ParkingLat          *float64        `gorm:"column:parking_lat" json:"parkingLat"`
 	ParkingLng          *float64        `gorm:"column:parking_lng" json:"parkingLng"`
 	MapNote             string          `gorm:"column:map_note;type:varchar(800)" json:"mapNote"`
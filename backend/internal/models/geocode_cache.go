@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// GeocodeCache — trajni keš odgovora spoljnog geokodera (Nominatim/Photon); Kljuc = provajder + normalizovan upit.
+type GeocodeCache struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Kljuc       string    `gorm:"type:varchar(300);not null;uniqueIndex" json:"kljuc"`
+	ResultsJSON string    `gorm:"column:results_json;type:text;not null" json:"-"`
+	ExpiresAt   time.Time `gorm:"not null;index" json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (GeocodeCache) TableName() string {
+	return "geocode_cache"
+}
@@ -2,13 +2,13 @@ package models
 
 import "time"
 
-// GuideActionRating ocena vodiča od učesnika nakon završene akcije (jedna po učesniku i akciji).
+// GuideActionRating ocena vodiča od učesnika nakon završene akcije (jedna po učesniku, akciji i vodiču).
 type GuideActionRating struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
-	AkcijaID        uint      `gorm:"column:akcija_id;not null;uniqueIndex:idx_guide_rating_akcija_rater" json:"akcijaId"`
-	RaterKorisnikID uint      `gorm:"column:rater_korisnik_id;not null;uniqueIndex:idx_guide_rating_akcija_rater" json:"raterKorisnikId"`
+	AkcijaID        uint      `gorm:"column:akcija_id;not null;uniqueIndex:idx_guide_rating_akcija_rater_guide,priority:1" json:"akcijaId"`
+	RaterKorisnikID uint      `gorm:"column:rater_korisnik_id;not null;uniqueIndex:idx_guide_rating_akcija_rater_guide,priority:2" json:"raterKorisnikId"`
 	GuideProfileID  uint      `gorm:"column:guide_profile_id;not null;index" json:"guideProfileId"`
-	GuideKorisnikID uint      `gorm:"column:guide_korisnik_id;not null;index" json:"guideKorisnikId"`
+	GuideKorisnikID uint      `gorm:"column:guide_korisnik_id;not null;index;uniqueIndex:idx_guide_rating_akcija_rater_guide,priority:3" json:"guideKorisnikId"`
 	Ocena           *int      `gorm:"column:ocena" json:"ocena,omitempty"`
 	Komentar        string    `gorm:"column:komentar;type:text" json:"komentar,omitempty"`
 	CreatedAt       time.Time `json:"createdAt"`
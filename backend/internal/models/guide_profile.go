@@ -22,8 +22,8 @@ type GuideProfile struct {
 	Drzava           string          `gorm:"type:varchar(120)" json:"drzava"`
 	Region           string          `gorm:"type:varchar(120)" json:"region"`
 	Grad             string          `gorm:"type:varchar(120)" json:"grad"`
-	BaseLat          *float64        `gorm:"column:base_lat" json:"baseLat,omitempty"`
-	BaseLng          *float64        `gorm:"column:base_lng" json:"baseLng,omitempty"`
+	BaseLat          *float64        `gorm:"column:base_lat;index:idx_guide_profiles_base_lat_lng,priority:1" json:"baseLat,omitempty"`
+	BaseLng          *float64        `gorm:"column:base_lng;index:idx_guide_profiles_base_lat_lng,priority:2" json:"baseLng,omitempty"`
 	GodineIskustva   int             `gorm:"column:godine_iskustva;default:0" json:"godineIskustva"`
 	JeziciJSON       json.RawMessage `gorm:"column:jezici_json;type:jsonb" json:"-"`
 	SertifikatiOpis  string          `gorm:"column:sertifikati_opis;type:text" json:"sertifikatiOpis,omitempty"`
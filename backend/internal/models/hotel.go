@@ -10,8 +10,8 @@ type Hotel struct {
 	ID           uint            `gorm:"primaryKey" json:"id"`
 	Naziv        string          `gorm:"type:varchar(255);not null" json:"naziv"`
 	Slug         string          `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
-	Lat          float64         `gorm:"column:lat;not null" json:"lat"`
-	Lng          float64         `gorm:"column:lng;not null" json:"lng"`
+	Lat          float64         `gorm:"column:lat;not null;index:idx_hotels_lat_lng,priority:1" json:"lat"`
+	Lng          float64         `gorm:"column:lng;not null;index:idx_hotels_lat_lng,priority:2" json:"lng"`
 	Opis         string          `gorm:"type:text" json:"opis,omitempty"`
 	Adresa       string          `gorm:"type:varchar(400)" json:"adresa,omitempty"`
 	Telefon      string          `gorm:"type:varchar(80)" json:"telefon,omitempty"`
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// IdempotencyKey — zapamćen odgovor na POST/PATCH sa Idempotency-Key zaglavljem (po korisniku, metodu i putanji).
+// Status 0 znači da je prvi zahtev još u obradi; dok traje, obrada periodično osvežava ObradaAt.
+type IdempotencyKey struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	KorisnikID   uint      `gorm:"not null;uniqueIndex:uidx_idempotency_keys_korisnik_ruta_kljuc,priority:1" json:"korisnikId"`
+	Kljuc        string    `gorm:"type:varchar(128);not null;uniqueIndex:uidx_idempotency_keys_korisnik_ruta_kljuc,priority:4" json:"kljuc"`
+	Metod        string    `gorm:"type:varchar(10);not null;uniqueIndex:uidx_idempotency_keys_korisnik_ruta_kljuc,priority:2" json:"metod"`
+	Ruta         string    `gorm:"type:varchar(255);not null;uniqueIndex:uidx_idempotency_keys_korisnik_ruta_kljuc,priority:3" json:"ruta"` // putanja zahteva (npr. /api/akcije/3/prijava)
+	RequestHash  string    `gorm:"type:varchar(64);not null" json:"-"`
+	Status       int       `gorm:"not null;default:0" json:"status"`
+	ContentType  string    `gorm:"type:varchar(100)" json:"-"`
+	ResponseBody string    `gorm:"type:text" json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+	ObradaAt     time.Time `json:"-"` // poslednji znak života obrade (Status 0); zastareo znači napušten zahtev
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expiresAt"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
@@ -33,6 +33,15 @@ type Klubovi struct {
 
 	LogoURL string `gorm:"type:varchar(500)" json:"logoUrl,omitempty"`
 
+	// PregledAkcijaVodica: akcije koje objavljuje vodič (uloga vodic) čekaju odobrenje admina kluba
+	PregledAkcijaVodica bool `gorm:"column:pregled_akcija_vodica;not null;default:false" json:"pregled_akcija_vodica"`
+
+	// ClanarinaIznos: godišnja članarina koju član može da plati online (nil = online članarina nije ponuđena)
+	ClanarinaIznos *float64 `json:"clanarina_iznos,omitempty"`
+
+	// PolitikaPovracaja: JSON politika povraćaja pri otkazu (helpers.PolitikaPovracaja); prazno = klub je nema
+	PolitikaPovracaja string `gorm:"type:text" json:"-"`
+
 	// Invite kod za javnu samoregistraciju članova (globalno jedinstven; nil = još nije generisan)
 	InviteCode              *string    `gorm:"size:16;uniqueIndex" json:"-"`
 	InviteLastRegeneratedAt *time.Time `json:"-"`
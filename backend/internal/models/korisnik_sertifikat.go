@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// KorisnikSertifikat je sertifikat ili licenca člana (npr. "alpinistički tečaj", "prva pomoć") koju evidentira admin kluba.
+// VaziDo nil = trajno važi.
+type KorisnikSertifikat struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	KorisnikID    uint       `gorm:"not null;index" json:"korisnikId"`
+	Naziv         string     `gorm:"type:varchar(100);not null" json:"naziv"`
+	VaziDo        *time.Time `json:"vaziDo,omitempty"`
+	EvidentiraoID uint       `gorm:"not null;default:0" json:"evidentiraoId"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (KorisnikSertifikat) TableName() string {
+	return "korisnik_sertifikati"
+}
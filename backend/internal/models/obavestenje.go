@@ -17,6 +17,18 @@ const (
 	ObavestenjeTipActionSignupRequest        = "action_signup_request" // zahtev za prijavu na akciju → vodič ili admin kluba
 	ObavestenjeTipActionCancelled            = "action_cancelled"      // akcija otkazana → potvrđeni učesnici + pending requesteri
 	ObavestenjeTipUserRegistered             = "user_registered"       // novi korisnik → superadmin
+	ObavestenjeTipAchievement                = "achievement"           // osvojen bedž → korisnik
+	ObavestenjeTipChallenge                  = "challenge"             // izazov završen, rezultati zamrznuti → učesnici
+	ObavestenjeTipActionReminder             = "action_reminder"       // akcija za manje od 24 h → učesnici i vodič (sa dnevnim svetlom)
+	ObavestenjeTipWeatherAlert               = "weather_alert"         // vetar/padavine/grmljavina u prognozi 48 h pre polaska → učesnici i vodič
+	ObavestenjeTipActionRescheduled          = "action_rescheduled"    // akcija pomerena → potvrđeni učesnici + pending requesteri (kao kod otkazivanja)
+	ObavestenjeTipActionSpotReleased         = "action_spot_released"  // nepotvrđen novi termin → oslobođeni učesnik
+	ObavestenjeTipActionSpotGranted          = "action_spot_granted"   // oslobođeno mesto → sledeći zahtev na čekanju
+	ObavestenjeTipActionChanged              = "action_changed"        // materijalna izmena (datum, cena, polazak, težina) → potvrđeni učesnici
+	ObavestenjeTipActionReviewRequested      = "action_review_requested" // akcija vodiča čeka odobrenje → admini kluba
+	ObavestenjeTipActionReviewed             = "action_reviewed"         // akcija odobrena ili vraćena u nacrt → autor
+	ObavestenjeTipActionCoGuide              = "action_co_guide"         // dodeljen kao ko-vodič (pomoćni vodič/zatvarač) → taj korisnik
+	ObavestenjeTipActionFormUpdated          = "action_form_updated"     // nova obavezna pitanja u formi prijave → prijavljeni bez odgovora
 )
 
 // Obavestenje je jedno obaveštenje za jednog korisnika (recipient).
@@ -29,6 +41,7 @@ type Obavestenje struct {
 	Link      string     `gorm:"type:varchar(500)" json:"link,omitempty"` // npr. /akcije/5, /finansije, /zadaci
 	ReadAt    *time.Time `json:"readAt,omitempty"`                        // kada je pročitano; null = nepročitano
 	CreatedAt time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime;index:idx_obavestenja_updated_at" json:"updatedAt"` // delta sync (/api/sync)
 
 	// Opciono: ID entiteta (akcija_id, zadatak_id, transakcija_id) za link
 	Metadata string `gorm:"type:text" json:"metadata,omitempty"` // JSON npr. {"akcijaId":5}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// OfflineBundleVersion — manifest jednog izdatog offline paketa (tip → ID → hash stavke) za delta sinhronizaciju.
+type OfflineBundleVersion struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	RegionKey    string    `gorm:"type:varchar(120);not null;uniqueIndex:uidx_offline_bundle_versions_region_version" json:"regionKey"`
+	Version      string    `gorm:"type:varchar(64);not null;uniqueIndex:uidx_offline_bundle_versions_region_version" json:"version"`
+	ManifestJSON string    `gorm:"column:manifest_json;type:text;not null" json:"-"`
+	CreatedAt    time.Time `gorm:"index" json:"createdAt"`
+}
+
+func (OfflineBundleVersion) TableName() string {
+	return "offline_bundle_versions"
+}
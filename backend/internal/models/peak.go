@@ -10,8 +10,8 @@ type Peak struct {
 	Slug      string   `gorm:"column:slug;type:varchar(255);uniqueIndex;not null" json:"slug"`
 	Status    string   `gorm:"column:status;type:varchar(20);default:'active';index" json:"status"`
 	VisinaM   int      `gorm:"column:visina_m" json:"visinaM"`
-	Lat       *float64 `gorm:"column:lat" json:"lat"`
-	Lng       *float64 `gorm:"column:lng" json:"lng"`
+	Lat       *float64 `gorm:"column:lat;index:idx_peaks_lat_lng,priority:1" json:"lat"`
+	Lng       *float64 `gorm:"column:lng;index:idx_peaks_lat_lng,priority:2" json:"lng"`
 	Drzava    string   `gorm:"column:drzava;type:varchar(120)" json:"drzava"`
 	Grad      string   `gorm:"column:grad;type:varchar(120)" json:"grad"`
 	Opis      string   `gorm:"column:opis;type:text" json:"opis,omitempty"`
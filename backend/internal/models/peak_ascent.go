@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	PeakAscentIzvorAkcija    = "akcija"
+	PeakAscentIzvorAktivnost = "aktivnost"
+	PeakAscentIzvorRucno     = "rucno"
+)
+
+// PeakAscent — jedan uspon korisnika na vrh iz kataloga (dnevnik uspona).
+// Izvor: akcija (prijava „popeo se“ na akciji vezanoj za vrh), aktivnost (GPS sesija prošla kroz vrh) ili ručni unos.
+type PeakAscent struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	KorisnikID        uint      `gorm:"not null;index:idx_peak_ascents_korisnik_peak" json:"korisnikId"`
+	PeakID            uint      `gorm:"not null;index:idx_peak_ascents_korisnik_peak;index" json:"peakId"`
+	Datum             time.Time `gorm:"not null" json:"datum"`
+	Izvor             string    `gorm:"type:varchar(20);not null" json:"izvor"`
+	AkcijaID          *uint     `gorm:"index" json:"akcijaId,omitempty"`
+	TrackedActivityID *uint     `gorm:"index" json:"trackedActivityId,omitempty"`
+	ZimskiUspon       bool      `gorm:"not null;default:false" json:"zimskiUspon"`
+	Napomena          string    `gorm:"type:varchar(500)" json:"napomena,omitempty"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	Peak *Peak `gorm:"foreignKey:PeakID" json:"-"`
+}
+
+func (PeakAscent) TableName() string {
+	return "peak_ascents"
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// PeakList — kurirana lista vrhova (npr. „svi vrhovi Srbije preko 2000 m“ ili top 50 kluba).
+// KlubID nil = globalna lista (superadmin); inače lista kluba.
+type PeakList struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Naziv       string    `gorm:"type:varchar(255);not null" json:"naziv"`
+	Slug        string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
+	Opis        string    `gorm:"type:text" json:"opis,omitempty"`
+	KlubID      *uint     `gorm:"index" json:"klubId,omitempty"`
+	CreatedByID uint      `gorm:"not null;default:0" json:"createdById"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (PeakList) TableName() string {
+	return "peak_lists"
+}
+
+// PeakListItem — vrh na listi; Pozicija određuje redosled prikaza.
+type PeakListItem struct {
+	ID         uint `gorm:"primaryKey" json:"id"`
+	PeakListID uint `gorm:"not null;uniqueIndex:uidx_peak_list_items_list_peak" json:"peakListId"`
+	PeakID     uint `gorm:"not null;uniqueIndex:uidx_peak_list_items_list_peak;index" json:"peakId"`
+	Pozicija   int  `gorm:"not null;default:0" json:"pozicija"`
+}
+
+func (PeakListItem) TableName() string {
+	return "peak_list_items"
+}
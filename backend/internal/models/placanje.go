@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+const (
+	PlacanjeSvrhaAkcija    = "akcija"
+	PlacanjeSvrhaClanarina = "clanarina"
+
+	PlacanjeNaCekanju        = "na_cekanju"
+	PlacanjePlaceno          = "placeno"
+	PlacanjeNeuspelo         = "neuspelo"
+	PlacanjeDelimicnoVraceno = "delimicno_vraceno"
+	PlacanjeVraceno          = "vraceno"
+)
+
+// Placanje je online plaćanje kod provajdera (kotizacija za prijavu na akciju ili članarina za godinu).
+// Uspešno plaćanje označava prijavu plaćenom i upisuje Transakcija (TransakcijaID); povraćaji se sabiraju u Vraceno.
+// Povraćaj se pre poziva provajdera rezerviše (PovracajUToku), a knjiži posle njega ili webhook-om.
+type Placanje struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Svrha          string     `gorm:"type:varchar(20);not null;index" json:"svrha"`
+	KlubID         *uint      `gorm:"index" json:"klubId,omitempty"`
+	KorisnikID     uint       `gorm:"index;not null" json:"korisnikId"` // platilac
+	AkcijaID       *uint      `gorm:"index" json:"akcijaId,omitempty"`
+	PrijavaID      *uint      `gorm:"index" json:"prijavaId,omitempty"`
+	Godina         int        `gorm:"not null;default:0" json:"godina,omitempty"` // godina članarine
+	Iznos          float64    `gorm:"not null" json:"iznos"`
+	Valuta         string     `gorm:"type:varchar(8);not null;default:'RSD'" json:"valuta"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'na_cekanju';index" json:"status"`
+	Provider       string     `gorm:"type:varchar(20);not null" json:"provider"`
+	IdempotencyKey string     `gorm:"type:varchar(80);not null;uniqueIndex" json:"-"`
+	CheckoutID     string     `gorm:"type:varchar(120);index" json:"checkoutId,omitempty"`
+	CheckoutURL    string     `gorm:"type:text" json:"checkoutUrl,omitempty"`
+	PaymentID      string     `gorm:"type:varchar(120);index" json:"paymentId,omitempty"`
+	Vraceno        float64    `gorm:"not null;default:0" json:"vraceno"`
+	PovracajUToku  float64    `gorm:"not null;default:0" json:"povracajUToku"` // ukupan Vraceno ka kome je povraćaj poslat provajderu (0: nema)
+	TransakcijaID  *uint      `gorm:"index" json:"transakcijaId,omitempty"`
+	PlacenoAt      *time.Time `json:"placenoAt,omitempty"`
+	Greska         string     `gorm:"type:text" json:"greska,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (Placanje) TableName() string {
+	return "placanja"
+}
+
+// PlacanjeDogadjaj je primljen webhook događaj; jedinstven (provider, event_id) čini obradu idempotentnom,
+// a sačuvan payload i greška služe za usklađivanje sa izveštajem provajdera.
+type PlacanjeDogadjaj struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Provider   string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_placanje_dogadjaji_provider_event,priority:1" json:"provider"`
+	EventID    string    `gorm:"type:varchar(120);not null;uniqueIndex:idx_placanje_dogadjaji_provider_event,priority:2" json:"eventId"`
+	Tip        string    `gorm:"type:varchar(40);not null;default:''" json:"tip"`
+	PlacanjeID *uint     `gorm:"index" json:"placanjeId,omitempty"`
+	KlubID     *uint     `gorm:"index" json:"-"` // klub plaćanja, a bez plaćanja klub iz metapodataka provajdera
+	Payload    string    `gorm:"type:text" json:"-"`
+	Greska     string    `gorm:"type:text" json:"greska,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (PlacanjeDogadjaj) TableName() string {
+	return "placanje_dogadjaji"
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+const (
+	ObavezaPovracajaOtkazUcesnika = "otkaz_ucesnika"
+	ObavezaPovracajaBezPenala     = "bez_penala"
+	ObavezaPovracajaOtkazAkcije   = "otkaz_akcije"
+
+	ObavezaPovracajaOtvorena  = "otvorena"
+	ObavezaPovracajaUToku     = "u_toku" // online povraćaj poslat provajderu, čeka knjiženje
+	ObavezaPovracajaIsplacena = "isplacena"
+)
+
+// AkcijaPolitikaPovracaja menja politiku povraćaja kluba (Klubovi.PolitikaPovracaja) za jednu akciju.
+// Prazna Pravila i nil OtkazKlubaProcenat znače da se ta polja preuzimaju od kluba; nepovratne stavke
+// (npr. unapred plaćen autobus) postoje samo na akciji. Liste su JSON nizovi ID-jeva izbora akcije.
+type AkcijaPolitikaPovracaja struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	AkcijaID           uint      `gorm:"not null;uniqueIndex" json:"akcijaId"`
+	Pravila            string    `gorm:"type:text" json:"-"` // JSON []helpers.PraviloPovracaja
+	OtkazKlubaProcenat *float64  `json:"otkazKlubaProcenat,omitempty"`
+	NepovratniPrevoz   string    `gorm:"type:text" json:"-"`
+	NepovratniSmestaj  string    `gorm:"type:text" json:"-"`
+	NepovratnaOprema   string    `gorm:"type:text" json:"-"` // AkcijaOpremaRent ID-jevi
+	Napomena           string    `gorm:"type:text" json:"napomena,omitempty"`
+	IzmenioID          uint      `gorm:"not null;default:0" json:"izmenioId"`
+	CreatedAt          time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (AkcijaPolitikaPovracaja) TableName() string {
+	return "akcija_politike_povracaja"
+}
+
+// ObavezaPovracaja je obračunat povraćaj plaćene prijave posle otkaza učesnika ili otkazivanja akcije.
+// Iznos = (Uplaceno − Nepovratno) × Procenat / 100 prema politici u trenutku otkaza (Obracun čuva detalje).
+// Gotovinska uplata otkazane prijave nije knjižena (FinishAction broji samo aktivne prijave), pa se pri isplati
+// knjiže i uplata i isplata; online uplata je već knjižena (UplataOnline) i vraća se preko provajdera.
+type ObavezaPovracaja struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	KlubID        *uint      `gorm:"index" json:"klubId,omitempty"`
+	AkcijaID      uint       `gorm:"not null;index" json:"akcijaId"`
+	PrijavaID     uint       `gorm:"not null;index" json:"prijavaId"`
+	KorisnikID    uint       `gorm:"not null;index" json:"korisnikId"`
+	Razlog        string     `gorm:"type:varchar(20);not null" json:"razlog"`
+	Uplaceno      float64    `gorm:"not null;default:0" json:"uplaceno"`
+	Nepovratno    float64    `gorm:"not null;default:0" json:"nepovratno"`
+	Procenat      float64    `gorm:"not null;default:0" json:"procenat"`
+	Iznos         float64    `gorm:"not null;default:0" json:"iznos"`
+	UplataOnline  bool       `gorm:"not null;default:false" json:"uplataOnline"`
+	Obracun       string     `gorm:"type:text" json:"-"` // JSON helpers.ObracunPovracaja
+	Status        string     `gorm:"type:varchar(20);not null;default:'otvorena';index" json:"status"`
+	OtkazanoAt    time.Time  `json:"otkazanoAt"`
+	IsplacenoAt   *time.Time `json:"isplacenoAt,omitempty"`
+	PlacanjeID    *uint      `gorm:"index" json:"placanjeId,omitempty"`
+	PovracajCilj  float64    `gorm:"not null;default:0" json:"-"` // ukupan Vraceno plaćanja posle ovog povraćaja (ključ kod provajdera)
+	TransakcijaID *uint      `gorm:"index" json:"transakcijaId,omitempty"`
+	ZatvorioID    *uint      `json:"zatvorioId,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (ObavezaPovracaja) TableName() string {
+	return "obaveze_povracaja"
+}
@@ -28,6 +28,9 @@ type Post struct {
 	// Opciona slika (URL)
 	ImageURL string `gorm:"type:varchar(500)" json:"imageUrl,omitempty"`
 
+	// Opciono: podeljena završena aktivnost (slika je tada sličica rute, ako autor nije dodao svoju)
+	ActivityID *uint `gorm:"index" json:"activityId,omitempty"`
+
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
 }
@@ -9,6 +9,14 @@ type Prijava struct {
 	Status       string    `gorm:"default:'prijavljen'" json:"status"`
 	Platio       bool      `gorm:"default:false" json:"platio"`
 	PrijavljenAt time.Time `gorm:"autoCreateTime" json:"prijavljenAt"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime;index:idx_prijave_updated_at" json:"updatedAt"` // delta sync (/api/sync)
+	// PotvrdaDo: posle pomeranja termina prijavljeni mora da potvrdi dolazak do ovog trenutka, inače se mesto oslobađa.
+	PotvrdaDo *time.Time `gorm:"column:potvrda_do;index" json:"potvrdaDo,omitempty"`
+	// BezPenalaDo: posle poskupljenja akcije učesnik može da odustane bez penala (i kada je platio) do ovog trenutka.
+	BezPenalaDo *time.Time `gorm:"column:bez_penala_do" json:"bezPenalaDo,omitempty"`
+	// UplacenoGotovinom: iznos koji je blagajnik primio kada je prijavu označio plaćenom (osnova povraćaja pri otkazu).
+	// Nil za prijave označene plaćenim pre nego što se iznos evidentirao.
+	UplacenoGotovinom *float64 `gorm:"column:uplaceno_gotovinom" json:"-"`
 
 	// Relacije za GORM Preload
 	Akcija   Akcija   `gorm:"foreignKey:AkcijaID"`
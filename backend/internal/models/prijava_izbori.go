@@ -6,6 +6,11 @@ type PrijavaIzbori struct {
 	SelectedSmestajIDs   string `gorm:"type:text" json:"selectedSmestajIds"`
 	SelectedPrevozIDs    string `gorm:"type:text" json:"selectedPrevozIds"`
 	SelectedRentItemsRaw string `gorm:"type:text" json:"selectedRentItems"`
+	Odgovori             string `gorm:"type:text" json:"odgovori"` // odgovori na formu akcije (JSON mapa id polja → odgovor)
+	FormaVerzija         int    `gorm:"not null;default:0" json:"formaVerzija"`
+	CenaObracun          string `gorm:"type:text" json:"-"` // primenjena cena pri prijavi (helpers.CenaObracun JSON)
+	PromoKodID           *uint  `gorm:"index" json:"promoKodId,omitempty"`
+	Grupa                string `gorm:"type:varchar(60);not null;default:''" json:"grupa,omitempty"` // grupni popust
 }
 
 func (PrijavaIzbori) TableName() string {
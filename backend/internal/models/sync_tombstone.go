@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// SyncTombstone beleži obrisan zapis za delta sync (/api/sync): mobilni klijent ga briše iz lokalne kopije.
+// KorisnikID = tombstone samo za tog korisnika; KlubID = za članove kluba; oba nil = za sve.
+// Javna je postavljena samo za akcije: true = objavljena javna akcija (za sve), inače samo za KlubID.
+type SyncTombstone struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Entitet    string    `gorm:"type:varchar(40);not null" json:"entitet"`
+	EntitetID  uint      `gorm:"not null" json:"entitetId"`
+	KorisnikID *uint     `gorm:"index" json:"korisnikId,omitempty"`
+	KlubID     *uint     `gorm:"index" json:"klubId,omitempty"`
+	Javna      *bool     `json:"javna,omitempty"`
+	ObrisanoAt time.Time `gorm:"not null;index" json:"obrisanoAt"`
+}
+
+func (SyncTombstone) TableName() string {
+	return "sync_tombstones"
+}
@@ -6,26 +6,37 @@ const (
 	TrackedActivityStatusActive    = "active"
 	TrackedActivityStatusCompleted = "completed"
 	TrackedActivityStatusDiscarded = "discarded"
+	TrackedActivityStatusUploading = "uploading" // offline snimljena aktivnost se šalje u delovima (POST /activities/upload)
+
+	// Ko dodeljuje seq tačaka aktivnosti; utvrđuje se prvim batch-om i ne menja se.
+	TrackedActivitySeqClient = "client"
+	TrackedActivitySeqServer = "server"
 )
 
 type TrackedActivity struct {
-	ID              uint       `gorm:"primaryKey" json:"id"`
-	UserID          uint       `gorm:"index;not null" json:"userId"`
-	Status          string     `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
-	StartedAt       time.Time  `gorm:"not null" json:"startedAt"`
-	EndedAt         *time.Time `json:"endedAt,omitempty"`
-	DurationSec     int        `gorm:"default:0" json:"durationSec"`
-	DistanceM       float64    `gorm:"default:0" json:"distanceM"`
-	ElevationGainM  float64    `gorm:"default:0" json:"elevationGainM"`
-	Steps           int        `gorm:"default:0" json:"steps"`
-	StartLat        *float64   `json:"startLat,omitempty"`
-	StartLng        *float64   `json:"startLng,omitempty"`
-	EndLat          *float64   `json:"endLat,omitempty"`
-	EndLng          *float64   `json:"endLng,omitempty"`
-	RoutePolyline   string     `gorm:"type:text" json:"routePolyline,omitempty"`
-	KlubID          *uint      `gorm:"index" json:"klubId,omitempty"`
-	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	UserID            uint       `gorm:"index;not null;uniqueIndex:uidx_tracked_activities_user_client,priority:1" json:"userId"`
+	ClientID          *string    `gorm:"type:varchar(64);uniqueIndex:uidx_tracked_activities_user_client,priority:2" json:"clientId,omitempty"` // ID aktivnosti na uređaju (idempotentan upload)
+	Status            string     `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	StartedAt         time.Time  `gorm:"not null" json:"startedAt"`
+	EndedAt           *time.Time `json:"endedAt,omitempty"`
+	DurationSec       int        `gorm:"default:0" json:"durationSec"`
+	DistanceM         float64    `gorm:"default:0" json:"distanceM"`
+	ElevationGainM    float64    `gorm:"default:0" json:"elevationGainM"`
+	Steps             int        `gorm:"default:0" json:"steps"`
+	StartLat          *float64   `json:"startLat,omitempty"`
+	StartLng          *float64   `json:"startLng,omitempty"`
+	EndLat            *float64   `json:"endLat,omitempty"`
+	EndLng            *float64   `json:"endLng,omitempty"`
+	RoutePolyline     string     `gorm:"type:text" json:"routePolyline,omitempty"` // server gradi iz tačaka (najdetaljniji nivo); klijentska vrednost samo kad tačaka nema
+	RoutePolylines    string     `gorm:"type:text" json:"-"`                       // JSON {"zoom": "encoded polyline"} za geo.PolylineZoomLevels
+	RouteThumbnailURL string     `gorm:"type:varchar(500)" json:"routeThumbnailUrl,omitempty"`
+	KlubID            *uint      `gorm:"index" json:"klubId,omitempty"`
+	Flagged           bool       `gorm:"not null;default:false;index" json:"flagged"` // neverovatna brzina/uspon → ne ulazi u rang liste i izazove
+	FlagReason        string     `gorm:"type:varchar(100)" json:"flagReason,omitempty"`
+	PointSeqMode      string     `gorm:"type:varchar(10);not null;default:''" json:"-"` // TrackedActivitySeqClient/Server; prazno = još nema tačaka
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
 }
 
 func (TrackedActivity) TableName() string {
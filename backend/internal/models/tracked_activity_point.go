@@ -11,8 +11,23 @@ type TrackedActivityPoint struct {
 	Altitude   *float64  `json:"altitude,omitempty"`
 	Accuracy   *float64  `json:"accuracy,omitempty"`
 	RecordedAt time.Time `gorm:"not null" json:"recordedAt"`
+	// AltitudeDEM: visina je sa terena (DEM) — GPS visina je nedostajala ili je previše odstupala.
+	AltitudeDEM bool `gorm:"column:altitude_dem;not null;default:false" json:"altitudeDem,omitempty"`
 }
 
 func (TrackedActivityPoint) TableName() string {
 	return "tracked_activity_points"
 }
+
+// TrackedActivityPointBatch pamti primljen batch tačaka (batchId klijenta), da ponovljen zahtev posle timeout-a ne doda duplikate.
+type TrackedActivityPointBatch struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ActivityID uint      `gorm:"uniqueIndex:uidx_tracked_activity_point_batches_activity_batch,priority:1;not null" json:"activityId"`
+	BatchID    string    `gorm:"type:varchar(64);uniqueIndex:uidx_tracked_activity_point_batches_activity_batch,priority:2;not null" json:"batchId"`
+	Added      int       `gorm:"not null;default:0" json:"added"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (TrackedActivityPointBatch) TableName() string {
+	return "tracked_activity_point_batches"
+}
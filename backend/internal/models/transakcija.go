@@ -14,6 +14,8 @@ type Transakcija struct {
 
 	// Za članarinu: ako je uplata tipa članarina, ovde je ID člana koji je platio
 	ClanarinaKorisnikID *uint `gorm:"index" json:"clanarinaKorisnikId,omitempty"`
+	// Godina online članarine i njenog povraćaja (nil: godina iz Datum)
+	ClanarinaGodina *int `json:"clanarinaGodina,omitempty"`
 
 	// Timestamps
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
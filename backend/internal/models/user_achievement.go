@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UserAchievement — bedž koji je korisnik osvojio; Kod odgovara pravilu iz internal/achievements.
+type UserAchievement struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	KorisnikID uint      `gorm:"not null;uniqueIndex:uidx_user_achievements_korisnik_kod" json:"korisnikId"`
+	Kod        string    `gorm:"type:varchar(60);not null;uniqueIndex:uidx_user_achievements_korisnik_kod" json:"kod"`
+	EarnedAt   time.Time `gorm:"not null" json:"earnedAt"`
+	Backfilled bool      `gorm:"not null;default:false" json:"backfilled"`
+}
+
+func (UserAchievement) TableName() string {
+	return "user_achievements"
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// WeatherCache — keš satne prognoze spoljnog provajdera; Kljuc = provajder + tačka (≈1 km) + visina + sat.
+type WeatherCache struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Kljuc        string    `gorm:"type:varchar(120);not null;uniqueIndex" json:"kljuc"`
+	ForecastJSON string    `gorm:"column:forecast_json;type:text;not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expiresAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (WeatherCache) TableName() string {
+	return "weather_cache"
+}
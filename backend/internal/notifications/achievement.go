@@ -0,0 +1,39 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotifyAchievementEarned obaveštava korisnika o osvojenom bedžu; link vodi na sopstveni profil.
+func NotifyAchievementEarned(db *gorm.DB, korisnikID uint, kod, naziv, opis string) {
+	if korisnikID == 0 || strings.TrimSpace(kod) == "" {
+		return
+	}
+	var username string
+	var korisnik models.Korisnik
+	if err := db.Select("id", "username").First(&korisnik, korisnikID).Error; err == nil {
+		username = korisnik.Username
+	}
+	title := "Novi bedž!"
+	body := fmt.Sprintf("Osvojili ste bedž „%s“", strings.TrimSpace(naziv))
+	if o := strings.TrimSpace(opis); o != "" {
+		body += " — " + o
+	}
+	meta := ProfileNotificationMetadata(korisnikID, username, map[string]any{
+		"achievementKod": kod,
+	})
+	NotifyUsers(
+		db,
+		[]uint{korisnikID},
+		models.ObavestenjeTipAchievement,
+		title,
+		body,
+		BuildProfileNotificationLink(username),
+		MarshalMetadata(meta),
+	)
+}
@@ -0,0 +1,48 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BuildActionChangedBody gradi tekst obaveštenja o izmeni: sažetak izmena (helpers.SummarizeMaterialPromene)
+// i, posle poskupljenja, rok do kog se prijava može otkazati bez penala.
+func BuildActionChangedBody(naziv, summary string, bezPenalaDo *time.Time, loc *time.Location) string {
+	name := strings.TrimSpace(naziv)
+	if name == "" {
+		name = "Akcija"
+	}
+	body := fmt.Sprintf("„%s” je izmenjena.", name)
+	if s := strings.TrimSpace(summary); s != "" {
+		body += " " + s + "."
+	}
+	if bezPenalaDo != nil {
+		body += fmt.Sprintf(" Cena je povećana — prijavu možete otkazati bez penala do %s.", bezPenalaDo.In(loc).Format("02.01.2006. u 15:04"))
+	}
+	return body
+}
+
+// NotifyActionChanged obaveštava potvrđene učesnike o materijalnoj izmeni akcije (verzija iz istorije u metapodacima).
+func NotifyActionChanged(db *gorm.DB, akcija *models.Akcija, verzija *models.AkcijaVerzija, recipientIDs []uint, summary string, bezPenalaDo *time.Time, loc *time.Location) {
+	if db == nil || akcija == nil || akcija.ID == 0 || verzija == nil || len(recipientIDs) == 0 {
+		return
+	}
+	extra := map[string]any{"verzija": verzija.Verzija}
+	if bezPenalaDo != nil {
+		extra["bezPenalaDo"] = bezPenalaDo.UTC().Format(time.RFC3339)
+	}
+	NotifyUsers(
+		db,
+		recipientIDs,
+		models.ObavestenjeTipActionChanged,
+		"Izmena akcije",
+		BuildActionChangedBody(akcija.Naziv, summary, bezPenalaDo, loc),
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, extra)),
+	)
+}
@@ -0,0 +1,29 @@
+package notifications
+
+import (
+	"strings"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotifyCoGuideAssigned javlja korisniku da je dodeljen akciji kao ko-vodič.
+func NotifyCoGuideAssigned(db *gorm.DB, akcija *models.Akcija, vodic *models.AkcijaVodic) {
+	if db == nil || akcija == nil || akcija.ID == 0 || vodic == nil || vodic.KorisnikID == 0 {
+		return
+	}
+	uloga := "pomoćni vodič"
+	if vodic.Uloga == models.AkcijaVodicUlogaZatvarac {
+		uloga = "zatvarač kolone"
+	}
+	NotifyUsers(
+		db,
+		[]uint{vodic.KorisnikID},
+		models.ObavestenjeTipActionCoGuide,
+		"Dodeljeni ste kao vodič",
+		"Na akciji „"+strings.TrimSpace(akcija.Naziv)+"” ste "+uloga+".",
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, map[string]any{"uloga": vodic.Uloga})),
+	)
+}
@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"strings"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotifyActionPublished šalje „nova akcija” pri objavljivanju klupske akcije:
+// javna → svim korisnicima sa klubom, inače članovima kluba. Privatne ture vodiča se ne najavljuju.
+func NotifyActionPublished(db *gorm.DB, akcija *models.Akcija) {
+	if db == nil || akcija == nil || akcija.ID == 0 || akcija.KlubID == nil || *akcija.KlubID == 0 {
+		return
+	}
+	if strings.EqualFold(strings.TrimSpace(akcija.OrganizatorTip), "vodic") {
+		return
+	}
+	var notifyUserIDs []uint
+	if akcija.Javna {
+		db.Model(&models.Korisnik{}).Where("klub_id IS NOT NULL").Pluck("id", &notifyUserIDs)
+	} else {
+		db.Model(&models.Korisnik{}).Where("klub_id = ?", *akcija.KlubID).Pluck("id", &notifyUserIDs)
+	}
+	NotifyUsers(
+		db,
+		notifyUserIDs,
+		models.ObavestenjeTipAkcija,
+		"Nova akcija u kalendaru",
+		akcija.Naziv,
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, nil)),
+	)
+}
+
+// NotifyActionReviewRequested javlja adminima kluba da akcija vodiča čeka odobrenje.
+func NotifyActionReviewRequested(db *gorm.DB, akcija *models.Akcija, reviewerIDs []uint) {
+	if db == nil || akcija == nil || akcija.ID == 0 || len(reviewerIDs) == 0 {
+		return
+	}
+	NotifyUsers(
+		db,
+		reviewerIDs,
+		models.ObavestenjeTipActionReviewRequested,
+		"Akcija čeka odobrenje",
+		"„"+strings.TrimSpace(akcija.Naziv)+"” čeka vaše odobrenje pre objavljivanja.",
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, nil)),
+	)
+}
+
+// NotifyActionReviewed javlja autoru ishod pregleda (odobrena ili vraćena u nacrt sa napomenom).
+func NotifyActionReviewed(db *gorm.DB, akcija *models.Akcija, authorID uint, approved bool) {
+	if db == nil || akcija == nil || akcija.ID == 0 || authorID == 0 {
+		return
+	}
+	title := "Akcija je odobrena"
+	body := "„" + strings.TrimSpace(akcija.Naziv) + "” je odobrena"
+	switch {
+	case !approved:
+		title = "Akcija je vraćena u nacrt"
+		body = "„" + strings.TrimSpace(akcija.Naziv) + "” nije odobrena"
+		if n := strings.TrimSpace(akcija.PregledNapomena); n != "" {
+			body += ": " + n
+		}
+	case akcija.StatusObjave == models.AkcijaStatusZakazana:
+		body += " i biće objavljena u zakazano vreme"
+	default:
+		body += " i objavljena"
+	}
+	NotifyUsers(
+		db,
+		[]uint{authorID},
+		models.ObavestenjeTipActionReviewed,
+		title,
+		body+".",
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, map[string]any{"approved": approved})),
+	)
+}
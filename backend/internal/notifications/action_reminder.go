@@ -0,0 +1,53 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BuildActionReminderBody gradi tekst podsetnika: vreme i mesto polaska, pa dnevno svetlo (helpers.AkcijaDaylight.Summary).
+func BuildActionReminderBody(akcija *models.Akcija, loc *time.Location, daylight string) string {
+	name := strings.TrimSpace(akcija.Naziv)
+	if name == "" {
+		name = "Akcija"
+	}
+	var body string
+	if akcija.StartAt != nil {
+		start := akcija.StartAt.In(loc)
+		body = fmt.Sprintf("„%s” počinje %s u %s.", name, start.Format("02.01."), start.Format("15:04"))
+	} else {
+		body = fmt.Sprintf("„%s” je %s.", name, akcija.Datum.UTC().Format("02.01.2006."))
+	}
+	if s := strings.TrimSpace(akcija.MestoPolaska); s != "" {
+		body += " Polazak: " + s + "."
+	}
+	if daylight = strings.TrimSpace(daylight); daylight != "" {
+		body += " " + daylight
+	}
+	return body
+}
+
+// NotifyActionReminder šalje podsetnik (in-app + push) učesnicima i vodiču; dark menja naslov kada tura ulazi u mrak.
+func NotifyActionReminder(db *gorm.DB, akcija *models.Akcija, recipientIDs []uint, loc *time.Location, daylight string, dark bool) {
+	if db == nil || akcija == nil || akcija.ID == 0 || len(recipientIDs) == 0 {
+		return
+	}
+	title := "Podsetnik: akcija uskoro"
+	if dark {
+		title = "Podsetnik: akcija uskoro — ponesite lampu"
+	}
+	NotifyUsers(
+		db,
+		recipientIDs,
+		models.ObavestenjeTipActionReminder,
+		title,
+		BuildActionReminderBody(akcija, loc, daylight),
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, nil)),
+	)
+}
@@ -0,0 +1,96 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// formatRescheduleTerm ispisuje termin: datum i vreme polaska (beogradsko), a bez satnice samo datum.
+func formatRescheduleTerm(datum time.Time, startAt *time.Time, loc *time.Location) string {
+	if startAt != nil {
+		return startAt.In(loc).Format("02.01.2006. u 15:04")
+	}
+	return datum.UTC().Format("02.01.2006.")
+}
+
+// BuildActionRescheduledBody gradi plain-text body za action_rescheduled: stari → novi termin, razlog i rok za potvrdu.
+func BuildActionRescheduledBody(naziv string, p *models.AkcijaPomeranje, loc *time.Location) string {
+	name := strings.TrimSpace(naziv)
+	if name == "" {
+		name = "Akcija"
+	}
+	body := fmt.Sprintf("„%s” je pomerena sa %s na %s.", name,
+		formatRescheduleTerm(p.StariDatum, p.StariStartAt, loc),
+		formatRescheduleTerm(p.NoviDatum, p.NoviStartAt, loc))
+	if r := strings.TrimSpace(p.Razlog); r != "" {
+		body += " Razlog: " + r + "."
+	}
+	body += fmt.Sprintf(" Prijavljeni treba da potvrde dolazak do %s, inače se mesto oslobađa.",
+		p.PotvrdaDo.In(loc).Format("02.01.2006. u 15:04"))
+	return body
+}
+
+// NotifyActionRescheduled obaveštava iste primaoce kao otkazivanje (učesnici + pending requesteri, bez actora).
+// Greške ne propagira — caller ostaje na uspješnom HTTP 200.
+func NotifyActionRescheduled(db *gorm.DB, akcija *models.Akcija, p *models.AkcijaPomeranje, recipientIDs []uint, loc *time.Location) {
+	if db == nil || akcija == nil || akcija.ID == 0 || p == nil || len(recipientIDs) == 0 {
+		return
+	}
+	NotifyUsers(
+		db,
+		recipientIDs,
+		models.ObavestenjeTipActionRescheduled,
+		"Akcija pomerena",
+		BuildActionRescheduledBody(akcija.Naziv, p, loc),
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, map[string]any{
+			"pomeranjeId": p.ID,
+			"potvrdaDo":   p.PotvrdaDo.UTC().Format(time.RFC3339),
+		})),
+	)
+}
+
+// NotifyActionSpotReleased javlja učesniku da mu je mesto oslobođeno jer nije potvrdio novi termin.
+func NotifyActionSpotReleased(db *gorm.DB, akcija *models.Akcija, userID uint) {
+	if db == nil || akcija == nil || akcija.ID == 0 || userID == 0 {
+		return
+	}
+	name := strings.TrimSpace(akcija.Naziv)
+	if name == "" {
+		name = "akciju"
+	}
+	NotifyUsers(
+		db,
+		[]uint{userID},
+		models.ObavestenjeTipActionSpotReleased,
+		"Prijava oslobođena",
+		fmt.Sprintf("Niste potvrdili novi termin za „%s” na vreme, pa je vaše mesto oslobođeno.", name),
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, nil)),
+	)
+}
+
+// NotifyActionSpotGranted javlja korisniku sa zahtevom na čekanju da je dobio oslobođeno mesto.
+func NotifyActionSpotGranted(db *gorm.DB, akcija *models.Akcija, userID, requestID uint) {
+	if db == nil || akcija == nil || akcija.ID == 0 || userID == 0 {
+		return
+	}
+	name := strings.TrimSpace(akcija.Naziv)
+	if name == "" {
+		name = "akciju"
+	}
+	NotifyUsers(
+		db,
+		[]uint{userID},
+		models.ObavestenjeTipActionSpotGranted,
+		"Dobili ste mesto na akciji",
+		fmt.Sprintf("Oslobodilo se mesto i vaša prijava na „%s” je potvrđena.", name),
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, map[string]any{"requestId": requestID})),
+	)
+}
@@ -0,0 +1,25 @@
+package notifications
+
+import (
+	"strings"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotifySignupFormIncomplete javlja prijavljenima da forma prijave ima nova obavezna pitanja bez njihovog odgovora.
+func NotifySignupFormIncomplete(db *gorm.DB, akcija *models.Akcija, korisnikIDs []uint, verzija int) {
+	if db == nil || akcija == nil || akcija.ID == 0 || len(korisnikIDs) == 0 {
+		return
+	}
+	NotifyUsers(
+		db,
+		korisnikIDs,
+		models.ObavestenjeTipActionFormUpdated,
+		"Dopunite prijavu",
+		"Forma prijave za akciju „"+strings.TrimSpace(akcija.Naziv)+"” ima nova obavezna pitanja.",
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, map[string]any{"formaVerzija": verzija})),
+	)
+}
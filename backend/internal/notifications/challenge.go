@@ -0,0 +1,69 @@
+package notifications
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ChallengeResultRow je zamrznut plasman jednog učesnika za obaveštenje.
+type ChallengeResultRow struct {
+	KorisnikID   uint
+	Rang         int
+	BrojUcesnika int
+	Vrednost     float64
+	TimNaziv     string
+	TimRang      int
+	BrojTimova   int
+}
+
+// NotifyChallengeFinished javlja svakom učesniku konačan plasman završenog izazova.
+func NotifyChallengeFinished(db *gorm.DB, ch *models.ClubChallenge, rows []ChallengeResultRow) {
+	if ch == nil || ch.ID == 0 {
+		return
+	}
+	title := fmt.Sprintf("Izazov „%s“ je završen", strings.TrimSpace(ch.Naziv))
+	link := BuildChallengeNotificationLink(ch.ID)
+	for _, row := range rows {
+		if row.KorisnikID == 0 {
+			continue
+		}
+		NotifyUsers(
+			db,
+			[]uint{row.KorisnikID},
+			models.ObavestenjeTipChallenge,
+			title,
+			BuildChallengeFinishedBody(ch.Metrika, row),
+			link,
+			MarshalMetadata(map[string]any{"challengeId": ch.ID}),
+		)
+	}
+}
+
+// BuildChallengeFinishedBody formatira plasman; timski izazov dodaje i plasman tima.
+func BuildChallengeFinishedBody(metrika string, row ChallengeResultRow) string {
+	body := fmt.Sprintf("Vaše mesto: %d. od %d (%s).", row.Rang, row.BrojUcesnika, FormatChallengeValue(metrika, row.Vrednost))
+	if row.TimNaziv != "" {
+		body += fmt.Sprintf(" Tim „%s“: %d. od %d.", row.TimNaziv, row.TimRang, row.BrojTimova)
+	}
+	return body
+}
+
+// FormatChallengeValue ispisuje vrednost metrike sa jedinicom.
+func FormatChallengeValue(metrika string, v float64) string {
+	switch metrika {
+	case models.ChallengeMetrikaKoraci:
+		return strconv.FormatInt(int64(v), 10) + " koraka"
+	case models.ChallengeMetrikaKm:
+		return strconv.FormatFloat(v, 'f', 1, 64) + " km"
+	case models.ChallengeMetrikaUspon:
+		return strconv.FormatInt(int64(v), 10) + " m uspona"
+	case models.ChallengeMetrikaVrhovi:
+		return strconv.FormatInt(int64(v), 10) + " vrhova"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -31,6 +31,14 @@ func BuildProfileNotificationLink(username string) string {
 	return "/korisnik/" + EscapePathSegment(u)
 }
 
+// BuildChallengeNotificationLink returns the challenge detail path or "" when challengeID is 0.
+func BuildChallengeNotificationLink(challengeID uint) string {
+	if challengeID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("/izazovi/%d", challengeID)
+}
+
 func BuildOwnClubNotificationLink() string  { return "/klub" }
 func BuildTasksNotificationLink() string    { return "/zadaci" }
 func BuildFinancesNotificationLink() string { return "/finansije" }
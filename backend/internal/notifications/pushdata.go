@@ -20,6 +20,7 @@ func PushDataExtra(notifType, metadata string) map[string]string {
 	copyUintField(extra, meta, "targetId")
 	copyUintField(extra, meta, "postId")
 	copyUintField(extra, meta, "requestId")
+	copyUintField(extra, meta, "challengeId")
 	if v, ok := meta["targetUsername"].(string); ok && v != "" {
 		extra["targetUsername"] = v
 	}
@@ -0,0 +1,45 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/weather"
+
+	"gorm.io/gorm"
+)
+
+// BuildWeatherAlertBody nabraja rizike iz prognoze sa satom najgoreg stanja (beogradsko vreme).
+func BuildWeatherAlertBody(akcija *models.Akcija, alerts []weather.Alert, loc *time.Location) string {
+	parts := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		parts = append(parts, fmt.Sprintf("%s (%s)", a.Poruka, a.Vreme.In(loc).Format("02.01. 15:04")))
+	}
+	name := strings.TrimSpace(akcija.Naziv)
+	if name == "" {
+		name = "akciju"
+	}
+	where := "na planini"
+	if akcija.VisinaVrhM > 0 {
+		where = fmt.Sprintf("na %d m", akcija.VisinaVrhM)
+	}
+	return fmt.Sprintf("Prognoza za „%s” %s: %s. Proverite plan sa vodičem.", name, where, strings.Join(parts, "; "))
+}
+
+// NotifyWeatherAlert šalje upozorenje na vreme (in-app + push) učesnicima i vodiču.
+func NotifyWeatherAlert(db *gorm.DB, akcija *models.Akcija, recipientIDs []uint, alerts []weather.Alert, loc *time.Location) {
+	if db == nil || akcija == nil || akcija.ID == 0 || len(recipientIDs) == 0 || len(alerts) == 0 {
+		return
+	}
+	NotifyUsers(
+		db,
+		recipientIDs,
+		models.ObavestenjeTipWeatherAlert,
+		"Upozorenje: loše vreme na akciji",
+		BuildWeatherAlertBody(akcija, alerts, loc),
+		BuildActionNotificationLink(akcija.ID, false),
+		MarshalMetadata(ActionNotificationMetadata(akcija.ID, map[string]any{"rizici": weather.AlertKinds(alerts)})),
+	)
+}
@@ -0,0 +1,197 @@
+// Package offline gradi verzionisane offline pakete kataloga (vrhovi, ferate, hoteli, predstojeće akcije)
+// za region mape, za mobilnu aplikaciju bez signala.
+//
+// Verzija paketa je hash sadržaja: isti katalog daje istu verziju, pa klijent ponovo preuzima samo
+// kada se nešto promenilo. Za svaku izdatu verziju čuva se manifest (tip → ID → hash stavke), iz kog
+// se računa delta (izmenjeni i obrisani ID-jevi) od verzije koju klijent već ima.
+package offline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/geo"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// MaxSpanDeg — najveći bbox paketa (po širini i dužini); cela država iz regiona staje u njega.
+	MaxSpanDeg = 12.0
+	// VersionRetention — koliko dugo se čuvaju manifesti starih verzija (delta od starije verzije = ceo paket).
+	VersionRetention = 90 * 24 * time.Hour
+)
+
+var (
+	ErrUnknownCountry = errors.New("nepoznata država za offline paket")
+	ErrRegionTooLarge = errors.New("region offline paketa je prevelik")
+	ErrUnknownVersion = errors.New("verzija offline paketa nije poznata")
+)
+
+// Country je država za koju se paket može tražiti po kodu (približan bbox, uključuje granične planine).
+type Country struct {
+	Kod   string   `json:"kod"`
+	Naziv string   `json:"naziv"`
+	BBox  geo.BBox `json:"bbox"`
+}
+
+// Countries — ISO 3166-1 alpha-2 kodovi država regiona.
+var Countries = []Country{
+	{Kod: "RS", Naziv: "Srbija", BBox: geo.BBox{MinLng: 18.81, MinLat: 41.85, MaxLng: 23.01, MaxLat: 46.19}},
+	{Kod: "ME", Naziv: "Crna Gora", BBox: geo.BBox{MinLng: 18.43, MinLat: 41.85, MaxLng: 20.36, MaxLat: 43.56}},
+	{Kod: "BA", Naziv: "Bosna i Hercegovina", BBox: geo.BBox{MinLng: 15.72, MinLat: 42.55, MaxLng: 19.62, MaxLat: 45.28}},
+	{Kod: "MK", Naziv: "Severna Makedonija", BBox: geo.BBox{MinLng: 20.45, MinLat: 40.85, MaxLng: 23.04, MaxLat: 42.37}},
+	{Kod: "HR", Naziv: "Hrvatska", BBox: geo.BBox{MinLng: 13.49, MinLat: 42.39, MaxLng: 19.45, MaxLat: 46.56}},
+	{Kod: "SI", Naziv: "Slovenija", BBox: geo.BBox{MinLng: 13.37, MinLat: 45.42, MaxLng: 16.61, MaxLat: 46.88}},
+	{Kod: "BG", Naziv: "Bugarska", BBox: geo.BBox{MinLng: 22.36, MinLat: 41.23, MaxLng: 28.61, MaxLat: 44.22}},
+	{Kod: "AL", Naziv: "Albanija", BBox: geo.BBox{MinLng: 19.26, MinLat: 39.64, MaxLng: 21.06, MaxLat: 42.66}},
+	{Kod: "GR", Naziv: "Grčka", BBox: geo.BBox{MinLng: 19.37, MinLat: 34.80, MaxLng: 28.25, MaxLat: 41.75}},
+	{Kod: "RO", Naziv: "Rumunija", BBox: geo.BBox{MinLng: 20.26, MinLat: 43.62, MaxLng: 29.74, MaxLat: 48.27}},
+	{Kod: "HU", Naziv: "Mađarska", BBox: geo.BBox{MinLng: 16.11, MinLat: 45.74, MaxLng: 22.90, MaxLat: 48.59}},
+}
+
+// Region je oblast paketa; Key je stabilan identitet regiona za čuvanje manifesta.
+type Region struct {
+	Key     string   `json:"key"`
+	Country string   `json:"country,omitempty"`
+	BBox    geo.BBox `json:"bbox"`
+}
+
+// CountryRegion vraća region za ISO kod države.
+func CountryRegion(code string) (Region, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for _, c := range Countries {
+		if c.Kod == code {
+			return Region{Key: "country:" + c.Kod, Country: c.Kod, BBox: c.BBox}, nil
+		}
+	}
+	return Region{}, ErrUnknownCountry
+}
+
+// BBoxRegion vraća region za bbox; koordinate se zaokružuju na 2 decimale (~1 km) da bi
+// mala pomeranja mape delila isti manifest.
+func BBoxRegion(b geo.BBox) (Region, error) {
+	lngSpan := b.MaxLng - b.MinLng
+	if b.CrossesAntimeridian() {
+		lngSpan += 360
+	}
+	if b.MaxLat-b.MinLat > MaxSpanDeg || lngSpan > MaxSpanDeg {
+		return Region{}, ErrRegionTooLarge
+	}
+	r := geo.BBox{
+		MinLng: roundDown(b.MinLng), MinLat: roundDown(b.MinLat),
+		MaxLng: roundUp(b.MaxLng), MaxLat: roundUp(b.MaxLat),
+	}
+	return Region{Key: fmt.Sprintf("bbox:%.2f,%.2f,%.2f,%.2f", r.MinLng, r.MinLat, r.MaxLng, r.MaxLat), BBox: r}, nil
+}
+
+// Item je jedna stavka paketa (kompaktan JSON koji klijent čuva lokalno).
+type Item struct {
+	ID   uint
+	Data any
+}
+
+// Manifest: tip → ID → hash stavke.
+type Manifest map[string]map[uint]string
+
+// ItemHash je skraćeni sha256 JSON-a stavke (mape se serijalizuju sa sortiranim ključevima).
+func ItemHash(data any) string {
+	raw, _ := json.Marshal(data)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:8])
+}
+
+// BuildManifest vraća manifest i verziju (hash celog sadržaja, nezavisan od redosleda stavki).
+func BuildManifest(items map[string][]Item) (Manifest, string) {
+	m := make(Manifest, len(items))
+	lines := make([]string, 0)
+	for tip, list := range items {
+		m[tip] = make(map[uint]string, len(list))
+		for _, it := range list {
+			h := ItemHash(it.Data)
+			m[tip][it.ID] = h
+			lines = append(lines, fmt.Sprintf("%s:%d:%s", tip, it.ID, h))
+		}
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return m, hex.EncodeToString(sum[:16])
+}
+
+// Diff vraća ID-jeve koji su novi ili izmenjeni u cur, i one kojih više nema (po tipu, rastuće).
+func Diff(old, cur Manifest) (changed, deleted map[string][]uint) {
+	changed = make(map[string][]uint)
+	deleted = make(map[string][]uint)
+	for tip, items := range cur {
+		ids := make([]uint, 0)
+		for id, h := range items {
+			if old[tip][id] != h {
+				ids = append(ids, id)
+			}
+		}
+		sortIDs(ids)
+		changed[tip] = ids
+	}
+	for tip, items := range old {
+		ids := make([]uint, 0)
+		for id := range items {
+			if _, ok := cur[tip][id]; !ok {
+				ids = append(ids, id)
+			}
+		}
+		sortIDs(ids)
+		deleted[tip] = ids
+	}
+	return changed, deleted
+}
+
+// SaveVersion pamti manifest izdate verzije (idempotentno) i briše manifeste starije od VersionRetention.
+func SaveVersion(db *gorm.DB, region Region, version string, m Manifest) error {
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	row := models.OfflineBundleVersion{RegionKey: region.Key, Version: version, ManifestJSON: string(raw), CreatedAt: time.Now().UTC()}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+		return err
+	}
+	return db.Where("region_key = ? AND version <> ? AND created_at < ?", region.Key, version, time.Now().UTC().Add(-VersionRetention)).
+		Delete(&models.OfflineBundleVersion{}).Error
+}
+
+// LoadManifest vraća manifest ranije izdate verzije regiona.
+func LoadManifest(db *gorm.DB, region Region, version string) (Manifest, error) {
+	var rows []models.OfflineBundleVersion
+	if err := db.Where("region_key = ? AND version = ?", region.Key, version).Limit(1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrUnknownVersion
+	}
+	var m Manifest
+	if err := json.Unmarshal([]byte(rows[0].ManifestJSON), &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func sortIDs(ids []uint) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
+
+func roundDown(v float64) float64 {
+	return math.Floor(v*100) / 100
+}
+
+func roundUp(v float64) float64 {
+	return math.Ceil(v*100) / 100
+}
@@ -0,0 +1,52 @@
+package offline
+
+import (
+	"reflect"
+	"testing"
+
+	"beleg-app/backend/internal/geo"
+)
+
+func TestBuildManifest_VersionIgnoresOrderAndTracksContent(t *testing.T) {
+	a := map[string][]Item{"vrh": {{ID: 1, Data: map[string]any{"naziv": "Midžor"}}, {ID: 2, Data: map[string]any{"naziv": "Rtanj"}}}}
+	b := map[string][]Item{"vrh": {{ID: 2, Data: map[string]any{"naziv": "Rtanj"}}, {ID: 1, Data: map[string]any{"naziv": "Midžor"}}}}
+	_, va := BuildManifest(a)
+	_, vb := BuildManifest(b)
+	if va != vb {
+		t.Fatalf("order must not change the version: %s vs %s", va, vb)
+	}
+	b["vrh"][0].Data = map[string]any{"naziv": "Rtanj (Šiljak)"}
+	if _, vc := BuildManifest(b); vc == va {
+		t.Fatal("changed content must change the version")
+	}
+}
+
+func TestDiff_ChangedAndDeleted(t *testing.T) {
+	old := Manifest{"vrh": {1: "a", 2: "b", 3: "c"}, "hotel": {9: "x"}}
+	cur := Manifest{"vrh": {1: "a", 2: "B", 4: "d"}, "hotel": {}}
+	changed, deleted := Diff(old, cur)
+	if !reflect.DeepEqual(changed["vrh"], []uint{2, 4}) || len(changed["hotel"]) != 0 {
+		t.Fatalf("unexpected changed %+v", changed)
+	}
+	if !reflect.DeepEqual(deleted["vrh"], []uint{3}) || !reflect.DeepEqual(deleted["hotel"], []uint{9}) {
+		t.Fatalf("unexpected deleted %+v", deleted)
+	}
+}
+
+func TestRegions(t *testing.T) {
+	r, err := CountryRegion("rs")
+	if err != nil || r.Key != "country:RS" {
+		t.Fatalf("unexpected %+v err=%v", r, err)
+	}
+	if _, err := CountryRegion("XX"); err != ErrUnknownCountry {
+		t.Fatalf("expected unknown country, got %v", err)
+	}
+	r1, _ := BBoxRegion(geo.BBox{MinLng: 20.701, MinLat: 43.201, MaxLng: 20.899, MaxLat: 43.399})
+	r2, _ := BBoxRegion(geo.BBox{MinLng: 20.705, MinLat: 43.204, MaxLng: 20.891, MaxLat: 43.392})
+	if r1.Key != r2.Key || r1.Key != "bbox:20.70,43.20,20.90,43.40" {
+		t.Fatalf("small map moves must share a region: %s vs %s", r1.Key, r2.Key)
+	}
+	if _, err := BBoxRegion(geo.BBox{MinLng: 0, MinLat: 30, MaxLng: 20, MaxLat: 40}); err != ErrRegionTooLarge {
+		t.Fatalf("expected region too large, got %v", err)
+	}
+}
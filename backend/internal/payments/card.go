@@ -0,0 +1,163 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultCardURL = "https://api.stripe.com"
+
+// Card — HTTP klijent za Stripe-kompatibilan checkout API (/v1/checkout/sessions, /v1/refunds)
+// sa webhook potpisom u zaglavlju Stripe-Signature.
+type Card struct {
+	BaseURL       string
+	SecretKey     string
+	WebhookSecret string
+	Client        *http.Client
+	Now           func() time.Time
+}
+
+func NewCard(baseURL, secretKey, webhookSecret string) *Card {
+	if baseURL == "" {
+		baseURL = defaultCardURL
+	}
+	return &Card{
+		BaseURL:       strings.TrimRight(baseURL, "/"),
+		SecretKey:     secretKey,
+		WebhookSecret: webhookSecret,
+		Client:        &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *Card) Name() string { return ProviderCard }
+
+func (p *Card) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.SecretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("%w: card status %d %s", ErrUnavailable, resp.StatusCode, e.Error.Message)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: card odgovor: %v", ErrUnavailable, err)
+	}
+	return nil
+}
+
+func (p *Card) CreateCheckout(ctx context.Context, in CheckoutRequest) (*Checkout, error) {
+	form := url.Values{
+		"mode":                                   {"payment"},
+		"client_reference_id":                    {in.Referenca},
+		"success_url":                            {in.SuccessURL},
+		"cancel_url":                             {in.CancelURL},
+		"metadata[placanje]":                     {in.Referenca},
+		"metadata[klub]":                         {in.Klub},
+		"line_items[0][quantity]":                {"1"},
+		"line_items[0][price_data][currency]":    {strings.ToLower(in.Valuta)},
+		"line_items[0][price_data][unit_amount]": {strconv.FormatInt(ToMinor(in.Iznos), 10)},
+		"line_items[0][price_data][product_data][name]": {in.Opis},
+		"payment_intent_data[metadata][placanje]":       {in.Referenca},
+		"payment_intent_data[metadata][klub]":           {in.Klub},
+	}
+	var out struct {
+		ID            string `json:"id"`
+		URL           string `json:"url"`
+		PaymentIntent string `json:"payment_intent"`
+	}
+	if err := p.post(ctx, "/v1/checkout/sessions", form, in.IdempotencyKey, &out); err != nil {
+		return nil, err
+	}
+	if out.ID == "" || out.URL == "" {
+		return nil, fmt.Errorf("%w: card checkout bez id/url", ErrUnavailable)
+	}
+	return &Checkout{ID: out.ID, PaymentID: out.PaymentIntent, URL: out.URL}, nil
+}
+
+func (p *Card) Refund(ctx context.Context, paymentID string, iznos float64, idempotencyKey string) (*Refund, error) {
+	form := url.Values{
+		"payment_intent": {paymentID},
+		"amount":         {strconv.FormatInt(ToMinor(iznos), 10)},
+	}
+	var out struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := p.post(ctx, "/v1/refunds", form, idempotencyKey, &out); err != nil {
+		return nil, err
+	}
+	if out.Status == "failed" || out.Status == "canceled" {
+		return nil, fmt.Errorf("%w: povraćaj odbijen (%s)", ErrUnavailable, out.Status)
+	}
+	return &Refund{ID: out.ID}, nil
+}
+
+type cardEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID             string `json:"id"`
+			Object         string `json:"object"`
+			PaymentIntent  string `json:"payment_intent"`
+			PaymentStatus  string `json:"payment_status"`
+			AmountTotal    int64  `json:"amount_total"`
+			AmountRefunded int64  `json:"amount_refunded"`
+			Metadata       struct {
+				Klub string `json:"klub"`
+			} `json:"metadata"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// VerifyWebhook prevodi checkout.session.* i charge.refunded u normalizovane događaje.
+func (p *Card) VerifyWebhook(payload []byte, header func(string) string) (*Event, error) {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	if err := VerifySignature(p.WebhookSecret, payload, header("Stripe-Signature"), now()); err != nil {
+		return nil, err
+	}
+	var ev cardEvent
+	if err := json.Unmarshal(payload, &ev); err != nil || ev.ID == "" {
+		return nil, fmt.Errorf("%w: neispravan događaj", ErrInvalidSignature)
+	}
+	obj := ev.Data.Object
+	out := &Event{ID: ev.ID, Klub: obj.Metadata.Klub}
+	switch ev.Type {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded":
+		if obj.PaymentStatus != "paid" {
+			// Odloženo plaćanje (npr. bankovni transfer) — čeka se async_payment_succeeded.
+			return out, nil
+		}
+		out.Tip, out.CheckoutID, out.PaymentID, out.Iznos = DogadjajPlaceno, obj.ID, obj.PaymentIntent, FromMinor(obj.AmountTotal)
+	case "checkout.session.expired", "checkout.session.async_payment_failed":
+		out.Tip, out.CheckoutID = DogadjajNeuspelo, obj.ID
+	case "charge.refunded":
+		out.Tip, out.PaymentID, out.Vraceno = DogadjajVraceno, obj.PaymentIntent, FromMinor(obj.AmountRefunded)
+	}
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package payments
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const defaultFakeSecret = "fake-dev-secret"
+
+// Fake je lokalni provajder za razvoj i testove: ne zove mrežu, ID-jevi su izvedeni iz idempotency ključa,
+// a događaje potpisuje istom šemom kao pravi provajder (zaglavlje X-Placanje-Signature).
+type Fake struct {
+	WebhookSecret string
+	// CheckoutBaseURL — stranica koju frontend prikazuje umesto kartičnog checkout-a.
+	CheckoutBaseURL string
+	Now             func() time.Time
+}
+
+func NewFake(webhookSecret string) *Fake {
+	if webhookSecret == "" {
+		webhookSecret = defaultFakeSecret
+	}
+	return &Fake{WebhookSecret: webhookSecret, CheckoutBaseURL: "/placanje/fake"}
+}
+
+func (p *Fake) Name() string { return ProviderFake }
+
+func (p *Fake) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+func fakeID(prefix, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return prefix + hex.EncodeToString(sum[:8])
+}
+
+func (p *Fake) CreateCheckout(_ context.Context, in CheckoutRequest) (*Checkout, error) {
+	if in.IdempotencyKey == "" || in.Iznos <= 0 {
+		return nil, fmt.Errorf("%w: fake checkout bez ključa ili iznosa", ErrUnavailable)
+	}
+	id := fakeID("fake_cs_", in.IdempotencyKey)
+	return &Checkout{
+		ID:        id,
+		PaymentID: fakeID("fake_pi_", in.IdempotencyKey),
+		URL:       p.CheckoutBaseURL + "/" + url.PathEscape(id),
+	}, nil
+}
+
+func (p *Fake) Refund(_ context.Context, paymentID string, iznos float64, idempotencyKey string) (*Refund, error) {
+	if paymentID == "" || iznos <= 0 {
+		return nil, fmt.Errorf("%w: fake povraćaj bez plaćanja ili iznosa", ErrUnavailable)
+	}
+	return &Refund{ID: fakeID("fake_re_", idempotencyKey)}, nil
+}
+
+// fakeEvent je JSON oblik događaja lokalnog provajdera.
+type fakeEvent struct {
+	ID         string  `json:"id"`
+	Tip        string  `json:"tip"`
+	CheckoutID string  `json:"checkoutId,omitempty"`
+	PaymentID  string  `json:"paymentId,omitempty"`
+	Iznos      float64 `json:"iznos,omitempty"`
+	Vraceno    float64 `json:"vraceno,omitempty"`
+	Klub       string  `json:"klub,omitempty"`
+}
+
+// SignedEvent pravi potpisan webhook (payload i vrednost zaglavlja X-Placanje-Signature) kao da ga šalje provajder.
+func (p *Fake) SignedEvent(ev Event) ([]byte, string) {
+	if ev.ID == "" {
+		ev.ID = fakeID("fake_evt_", fmt.Sprintf("%s|%s|%s|%.2f", ev.Tip, ev.CheckoutID, ev.PaymentID, ev.Vraceno))
+	}
+	payload, _ := json.Marshal(fakeEvent(ev))
+	return payload, Sign(p.WebhookSecret, payload, p.now())
+}
+
+func (p *Fake) VerifyWebhook(payload []byte, header func(string) string) (*Event, error) {
+	if err := VerifySignature(p.WebhookSecret, payload, header("X-Placanje-Signature"), p.now()); err != nil {
+		return nil, err
+	}
+	var ev fakeEvent
+	if err := json.Unmarshal(payload, &ev); err != nil || ev.ID == "" {
+		return nil, fmt.Errorf("%w: neispravan događaj", ErrInvalidSignature)
+	}
+	out := Event(ev)
+	return &out, nil
+}
@@ -0,0 +1,159 @@
+// Package payments vodi online plaćanja (kotizacija akcije, članarina) kroz zamenljiv provajder.
+// Provajder otvara checkout za iznos, šalje potpisane webhook događaje o ishodu i vraća novac (povraćaj).
+// Podrazumevano je isključeno; PAYMENTS_PROVIDER=card koristi kartični checkout API, fake lokalni provajder za razvoj.
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderCard = "card"
+	ProviderFake = "fake"
+
+	// Normalizovani tipovi webhook događaja; ostali događaji provajdera se ignorišu (Tip == "").
+	DogadjajPlaceno  = "placeno"
+	DogadjajNeuspelo = "neuspelo"
+	DogadjajVraceno  = "vraceno"
+
+	// signatureTolerance — stariji potpisani događaji se odbijaju (zaštita od ponavljanja).
+	signatureTolerance = 5 * time.Minute
+)
+
+var (
+	ErrUnavailable      = errors.New("servis za online plaćanje trenutno nije dostupan")
+	ErrInvalidSignature = errors.New("nevažeći potpis webhook događaja")
+)
+
+// CheckoutRequest je zahtev za otvaranje plaćanja; IdempotencyKey provajder koristi da ponovljen zahtev ne otvori novo plaćanje.
+type CheckoutRequest struct {
+	IdempotencyKey string
+	Referenca      string // naš ID plaćanja, vraća se u događajima
+	Klub           string // ID kluba primaoca, vraća se u događajima (usklađivanje nepovezanih događaja)
+	Iznos          float64
+	Valuta         string
+	Opis           string
+	SuccessURL     string
+	CancelURL      string
+}
+
+// Checkout je otvoreno plaćanje kod provajdera; URL je stranica na koju se platilac preusmerava.
+type Checkout struct {
+	ID        string
+	PaymentID string // poznat odmah kod nekih provajdera, inače stiže uz događaj o uplati
+	URL       string
+}
+
+// Refund je potvrđen povraćaj kod provajdera.
+type Refund struct {
+	ID string
+}
+
+// Event je verifikovan webhook događaj; Vraceno je ukupno vraćen iznos za plaćanje (ne samo ovaj povraćaj).
+type Event struct {
+	ID         string
+	Tip        string
+	CheckoutID string
+	PaymentID  string
+	Iznos      float64
+	Vraceno    float64
+	Klub       string
+}
+
+// Provider je adapter za provajdera plaćanja.
+type Provider interface {
+	Name() string
+	CreateCheckout(ctx context.Context, req CheckoutRequest) (*Checkout, error)
+	Refund(ctx context.Context, paymentID string, iznos float64, idempotencyKey string) (*Refund, error)
+	VerifyWebhook(payload []byte, header func(string) string) (*Event, error)
+}
+
+// ToMinor pretvara iznos u najmanje jedinice valute (pare/centi), kako ih provajderi očekuju.
+func ToMinor(iznos float64) int64 {
+	return int64(math.Round(iznos * 100))
+}
+
+func FromMinor(v int64) float64 {
+	return float64(v) / 100
+}
+
+// Sign pravi zaglavlje potpisa "t=<unix>,v1=<hex hmac-sha256(secret, t.payload)>".
+func Sign(secret string, payload []byte, at time.Time) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + ts + ",v1=" + signature(secret, ts, payload)
+}
+
+func signature(secret, ts string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature proverava zaglavlje iz Sign; prihvata bilo koji od više v1 potpisa (rotacija tajne kod provajdera).
+func VerifySignature(secret string, payload []byte, header string, now time.Time) error {
+	if secret == "" || header == "" {
+		return ErrInvalidSignature
+	}
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > signatureTolerance || d < -signatureTolerance {
+		return fmt.Errorf("%w: događaj je prestar", ErrInvalidSignature)
+	}
+	expected := signature(secret, ts, payload)
+	for _, s := range sigs {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// ProviderFromEnv bira provajdera:
+//
+//   - PAYMENTS_PROVIDER=off (podrazumevano) | card | fake
+//   - PAYMENTS_CARD_URL — kartični checkout API (podrazumevano https://api.stripe.com), PAYMENTS_CARD_SECRET_KEY — API ključ
+//   - PAYMENTS_WEBHOOK_SECRET — tajna za potpis webhook događaja (za fake podrazumevano "fake-dev-secret")
+func ProviderFromEnv() Provider {
+	secret := strings.TrimSpace(os.Getenv("PAYMENTS_WEBHOOK_SECRET"))
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("PAYMENTS_PROVIDER"))) {
+	case ProviderCard:
+		key := strings.TrimSpace(os.Getenv("PAYMENTS_CARD_SECRET_KEY"))
+		if key == "" || secret == "" {
+			log.Printf("payments: PAYMENTS_CARD_SECRET_KEY i PAYMENTS_WEBHOOK_SECRET su obavezni za card provajdera; online plaćanje je isključeno")
+			return nil
+		}
+		return NewCard(strings.TrimSpace(os.Getenv("PAYMENTS_CARD_URL")), key, secret)
+	case ProviderFake:
+		return NewFake(secret)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id":"evt_1"}`)
+	header := Sign("tajna", payload, now)
+
+	if err := VerifySignature("tajna", payload, header, now.Add(time.Minute)); err != nil {
+		t.Fatalf("valid signature: %v", err)
+	}
+	if err := VerifySignature("tajna", []byte(`{"id":"evt_2"}`), header, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("tampered payload must fail, got %v", err)
+	}
+	if err := VerifySignature("druga", payload, header, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("wrong secret must fail, got %v", err)
+	}
+	if err := VerifySignature("tajna", payload, header, now.Add(10*time.Minute)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("stale event must fail, got %v", err)
+	}
+	// Rotacija tajne: provajder šalje više v1 potpisa, dovoljan je jedan ispravan.
+	ts := fmt.Sprint(now.Unix())
+	rotated := "t=" + ts + ",v1=" + signature("stara", ts, payload) + ",v1=" + signature("tajna", ts, payload)
+	if err := VerifySignature("tajna", payload, rotated, now); err != nil {
+		t.Fatalf("one of several v1 signatures: %v", err)
+	}
+}
+
+func TestCard_CheckoutRefundAndWebhook(t *testing.T) {
+	var gotCheckout, gotRefund url.Values
+	var gotKeys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk_test" {
+			http.Error(w, `{"error":{"message":"unauthorized"}}`, http.StatusUnauthorized)
+			return
+		}
+		gotKeys = append(gotKeys, r.Header.Get("Idempotency-Key"))
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		switch r.URL.Path {
+		case "/v1/checkout/sessions":
+			gotCheckout = form
+			fmt.Fprint(w, `{"id":"cs_1","url":"https://checkout.example/cs_1","payment_intent":null}`)
+		case "/v1/refunds":
+			gotRefund = form
+			fmt.Fprint(w, `{"id":"re_1","status":"succeeded"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := NewCard(srv.URL, "sk_test", "whsec")
+	p.Now = func() time.Time { return now }
+
+	co, err := p.CreateCheckout(context.Background(), CheckoutRequest{
+		IdempotencyKey: "prijava-7-1", Referenca: "42", Klub: "5", Iznos: 3000.5, Valuta: "RSD", Opis: "Kotizacija",
+		SuccessURL: "https://app/akcije/1?placanje=42", CancelURL: "https://app/akcije/1?placanje=42&otkazano=1",
+	})
+	if err != nil || co.ID != "cs_1" || co.URL == "" {
+		t.Fatalf("checkout: %+v %v", co, err)
+	}
+	if gotCheckout.Get("line_items[0][price_data][unit_amount]") != "300050" ||
+		gotCheckout.Get("line_items[0][price_data][currency]") != "rsd" ||
+		gotCheckout.Get("client_reference_id") != "42" || gotCheckout.Get("payment_intent_data[metadata][klub]") != "5" {
+		t.Fatalf("checkout form: %v", gotCheckout)
+	}
+	if _, err := p.Refund(context.Background(), "pi_1", 1000, "povracaj-42-100000"); err != nil {
+		t.Fatal(err)
+	}
+	if gotRefund.Get("payment_intent") != "pi_1" || gotRefund.Get("amount") != "100000" {
+		t.Fatalf("refund form: %v", gotRefund)
+	}
+	if gotKeys[0] != "prijava-7-1" || gotKeys[1] != "povracaj-42-100000" {
+		t.Fatalf("idempotency keys: %v", gotKeys)
+	}
+
+	verify := func(payload string) *Event {
+		t.Helper()
+		sig := Sign("whsec", []byte(payload), now)
+		ev, err := p.VerifyWebhook([]byte(payload), func(h string) string {
+			if h == "Stripe-Signature" {
+				return sig
+			}
+			return ""
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ev
+	}
+	ev := verify(`{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"id":"cs_1","payment_intent":"pi_1","payment_status":"paid","amount_total":300050,"metadata":{"placanje":"42","klub":"5"}}}}`)
+	if ev.Tip != DogadjajPlaceno || ev.CheckoutID != "cs_1" || ev.PaymentID != "pi_1" || ev.Iznos != 3000.5 || ev.Klub != "5" {
+		t.Fatalf("paid event: %+v", ev)
+	}
+	ev = verify(`{"id":"evt_2","type":"checkout.session.completed","data":{"object":{"id":"cs_2","payment_status":"unpaid"}}}`)
+	if ev.Tip != "" {
+		t.Fatalf("delayed payment must wait for async event: %+v", ev)
+	}
+	ev = verify(`{"id":"evt_3","type":"charge.refunded","data":{"object":{"id":"ch_1","payment_intent":"pi_1","amount_refunded":100000}}}`)
+	if ev.Tip != DogadjajVraceno || ev.PaymentID != "pi_1" || ev.Vraceno != 1000 {
+		t.Fatalf("refund event: %+v", ev)
+	}
+	if _, err := p.VerifyWebhook([]byte(`{"id":"evt_4"}`), func(string) string { return "" }); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("unsigned webhook must fail, got %v", err)
+	}
+}
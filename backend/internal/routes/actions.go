@@ -16,6 +16,19 @@ func RegisterActionRoutes(r *gin.Engine, protected *gin.RouterGroup, jwtSecret [
 	protected.GET("/akcije/:id/moja-prijava", handlers.GetMojaPrijavaZaAkciju)
 	protected.PATCH("/akcije/:id/moja-prijava", handlers.UpdateMojaPrijavaIzbori)
 	protected.GET("/akcije/:id/prijave", handlers.GetPrijaveZaAkciju)
+	protected.GET("/akcije/:id/prijave.csv", handlers.ExportPrijaveCSV)
+	protected.GET("/akcije/:id/forma", handlers.GetAkcijaForma)
+	protected.PUT("/akcije/:id/forma", handlers.SacuvajAkcijaFormu)
+	protected.GET("/akcije/:id/uslovi", handlers.GetAkcijaUslovi)
+	protected.PUT("/akcije/:id/uslovi", handlers.SacuvajAkcijaUslove)
+	protected.POST("/akcije/:id/uslovi/izuzeci", handlers.DodajIzuzetakUslova)
+	protected.DELETE("/akcije/:id/uslovi/izuzeci/:korisnikId", handlers.UkloniIzuzetakUslova)
+	protected.GET("/akcije/:id/cenovnik", handlers.GetAkcijaCenovnik)
+	protected.PUT("/akcije/:id/cenovnik", handlers.SacuvajAkcijaCenovnik)
+	protected.GET("/akcije/:id/politika-povracaja", handlers.GetAkcijaPolitikaPovracaja)
+	protected.PUT("/akcije/:id/politika-povracaja", handlers.SacuvajAkcijaPolitikuPovracaja)
+	protected.POST("/akcije/:id/promo-kodovi", handlers.DodajPromoKod)
+	protected.DELETE("/akcije/:id/promo-kodovi/:kodId", handlers.ObrisiPromoKod)
 	protected.POST("/akcije/:id/prevoz", handlers.DodajPrevozZaAkciju)
 	protected.DELETE("/akcije/:id/prevoz/:prevozId", handlers.ObrisiPrevozZaAkciju)
 	protected.GET("/akcije/:id/prevoz-prijave", handlers.GetPrevozPrijave)
@@ -23,10 +36,25 @@ func RegisterActionRoutes(r *gin.Engine, protected *gin.RouterGroup, jwtSecret [
 	protected.POST("/akcije/:id/add-club-members-completed", handlers.BulkAddClubMembersCompleted)
 	protected.POST("/akcije/:id/zavrsi", handlers.ZavrsiAkciju)
 	protected.POST("/akcije/:id/otkazi", handlers.OtkaziAkciju)
+	protected.POST("/akcije/:id/pomeri", handlers.PomeriAkciju)
+	protected.POST("/akcije/:id/potvrdi-termin", handlers.PotvrdiNoviTermin)
+	protected.GET("/akcije/:id/pomeranja", handlers.GetAkcijaPomeranja)
+	protected.GET("/akcije/:id/istorija", handlers.GetAkcijaIstorija)
+	protected.GET("/akcije/:id/istorija/:verzija", handlers.GetAkcijaVerzija)
+	protected.POST("/akcije/:id/objavi", handlers.ObjaviAkciju)
+	protected.POST("/akcije/:id/odobri", handlers.OdobriAkciju)
+	protected.POST("/akcije/:id/odbij", handlers.OdbijAkciju)
+	protected.POST("/akcije/:id/nacrt", handlers.VratiAkcijuUNacrt)
+	protected.POST("/akcije/:id/vodici", handlers.DodajVodicaAkciji)
+	protected.DELETE("/akcije/:id/vodici/:korisnikId", handlers.UkloniVodicaSaAkcije)
 	protected.GET("/akcije/:id/guide-rating/mine", handlers.GetMyGuideRatingForAkcija)
 	protected.POST("/akcije/:id/guide-rating", handlers.SubmitGuideRatingForAkcija)
 	protected.DELETE("/akcije/:id", handlers.DeleteAkcija)
 	protected.DELETE("/akcije/:id/prijavi", handlers.OtkaziPrijavuNaAkciju)
+	protected.PUT("/akcije/:id/ruta", handlers.PutAkcijaRuta)
+	protected.DELETE("/akcije/:id/ruta", handlers.DeleteAkcijaRuta)
+	protected.GET("/akcije/:id/elevation-profile", handlers.GetAkcijaElevationProfile)
+	protected.GET("/akcije/:id/kalendar.ics", handlers.GetAkcijaICS)
 	protected.GET("/akcije/:id/signup-requests", handlers.GetActionSignupRequests)
 	protected.GET("/akcije/:id/signup-requests/:requestId", handlers.GetActionSignupRequestByID)
 	protected.POST("/akcije/:id/signup-requests/:requestId/respond", handlers.RespondToActionSignupRequest)
@@ -17,9 +17,12 @@ func RegisterActivityRoutes(protected *gin.RouterGroup) {
 
 	// Tracked activities (GPS sessions)
 	protected.POST("/activities/start", handlers.StartTrackedActivity)
+	protected.POST("/activities/upload", handlers.StartTrackedActivityUpload)
 	protected.GET("/activities/active", handlers.GetActiveTrackedActivity)
 	protected.GET("/me/activities", handlers.GetMyTrackedActivities)
 	protected.GET("/activities/:id", handlers.GetTrackedActivity)
+	protected.GET("/activities/:id/route", handlers.GetTrackedActivityRoute)
+	protected.GET("/activities/:id/elevation-profile", handlers.GetTrackedActivityElevationProfile)
 	protected.POST("/activities/:id/points", handlers.AppendTrackedActivityPoints)
 	protected.POST("/activities/:id/finish", handlers.FinishTrackedActivity)
 	protected.POST("/activities/:id/discard", handlers.DiscardTrackedActivity)
@@ -27,4 +30,7 @@ func RegisterActivityRoutes(protected *gin.RouterGroup) {
 	// Leaderboards
 	protected.GET("/leaderboards/steps", handlers.GetStepsLeaderboard)
 	protected.GET("/leaderboards/clubs/steps", handlers.GetClubsStepsLeaderboard)
+	protected.GET("/leaderboards/distance", handlers.GetDistanceLeaderboard)
+	protected.GET("/leaderboards/elevation", handlers.GetElevationLeaderboard)
+	protected.GET("/leaderboards/actions", handlers.GetActionsLeaderboard)
 }
@@ -18,6 +18,10 @@ func RegisterAppRoutes(r *gin.Engine, db *gorm.DB, jwtSecret []byte) {
 	resendEmailRateLimiter := middleware.NewIPRateLimiter(8, 10*time.Minute)
 	forgotPasswordRateLimiter := middleware.NewIPRateLimiter(8, 10*time.Minute)
 	setupAdminRateLimiter := middleware.NewIPRateLimiter(5, 10*time.Minute)
+	searchRateLimiter := middleware.NewIPRateLimiter(120, time.Minute)
+	mapRateLimiter := middleware.NewIPRateLimiter(240, time.Minute)
+	geocodeRateLimiter := middleware.NewIPRateLimiter(30, time.Minute)
+	offlineRateLimiter := middleware.NewIPRateLimiter(30, time.Minute)
 
 	RegisterSetupPublicRoutes(r, db, setupAdminRateLimiter)
 	RegisterAuthPublicRoutes(r, db, jwtSecret, loginRateLimiter)
@@ -33,18 +37,27 @@ func RegisterAppRoutes(r *gin.Engine, db *gorm.DB, jwtSecret []byte) {
 
 	RegisterUsersPublicRoutes(r, jwtSecret)
 	RegisterFerrataPublicRoutes(r)
+	RegisterPeakListPublicRoutes(r, jwtSecret)
+	RegisterSearchPublicRoutes(r, jwtSecret, searchRateLimiter)
+	RegisterMapPublicRoutes(r, jwtSecret, mapRateLimiter)
+	RegisterOfflinePublicRoutes(r, offlineRateLimiter)
+	RegisterPaymentPublicRoutes(r)
 
 	// PROTECTED RUTE SVE UNUTAR JEDNOG BLOKA
 	protected := r.Group("/api")
 	protected.Use(middleware.AuthMiddleware(jwtSecret))
 	protected.Use(middleware.LoadUserMiddleware())
 	protected.Use(middleware.ClubHoldMiddleware())
+	protected.Use(middleware.IdempotencyMiddleware())
 	{
 		RegisterClubMembershipRoutes(r, protected, db)
-		protected.GET("/geocode", handlers.GetGeocodeSearch)
+		protected.GET("/geocode", geocodeRateLimiter, handlers.GetGeocodeSearch)
+		protected.GET("/geocode/reverse", geocodeRateLimiter, handlers.GetGeocodeReverse)
 		RegisterFinanceRoutes(protected)
+		RegisterPaymentRoutes(protected)
 		RegisterZadatakRoutes(protected)
 		RegisterObavestenjaRoutes(protected)
+		protected.GET("/sync", handlers.GetSync)
 		RegisterPushTokenRoutes(protected)
 		RegisterClubRoutes(protected)
 		RegisterFollowRoutes(protected)
@@ -65,6 +78,8 @@ func RegisterAppRoutes(r *gin.Engine, db *gorm.DB, jwtSecret []byte) {
 		protected.POST("/auth/social/google/link", handlers.LinkGoogleAccount(jwtSecret))
 
 		RegisterActivityRoutes(protected)
+		RegisterPeakRoutes(protected)
+		RegisterChallengeRoutes(protected)
 
 		RegisterUsersAdminRoutes(protected)
 	}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"beleg-app/backend/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterChallengeRoutes(protected *gin.RouterGroup) {
+	protected.GET("/izazovi", handlers.ListClubChallenges)
+	protected.GET("/izazovi/:id", handlers.GetClubChallenge)
+	protected.POST("/izazovi", handlers.CreateClubChallenge)
+	protected.DELETE("/izazovi/:id", handlers.DeleteClubChallenge)
+	protected.POST("/izazovi/:id/prijava", handlers.JoinClubChallenge)
+	protected.DELETE("/izazovi/:id/prijava", handlers.LeaveClubChallenge)
+}
@@ -14,4 +14,6 @@ func RegisterClubRoutes(g *gin.RouterGroup) {
 	g.GET("/klub", handlers.GetMojKlub)
 	g.PATCH("/klub", handlers.UpdateMojKlub)
 	g.PATCH("/klub/logo", handlers.UpdateMojKlubLogo)
+	g.GET("/klub/politika-povracaja", handlers.GetKlubPolitikaPovracaja)
+	g.PUT("/klub/politika-povracaja", handlers.SacuvajKlubPolitikuPovracaja)
 }
@@ -17,4 +17,6 @@ func RegisterFinanceRoutes(g *gin.RouterGroup) {
 	g.POST("/finansije", handlers.CreateTransakcija)
 	g.GET("/finansije/clanarine", handlers.GetClanarine)
 	g.POST("/finansije/clanarina", handlers.PostClanarinaPlati)
+	g.GET("/finansije/obaveze-povracaja", handlers.GetObavezePovracaja)
+	g.POST("/finansije/obaveze-povracaja/:id/isplata", handlers.IsplatiObavezuPovracaja)
 }
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"beleg-app/backend/internal/handlers"
+	"beleg-app/backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterMapPublicRoutes(r *gin.Engine, jwtSecret []byte, mapRateLimiter gin.HandlerFunc) {
+	r.GET("/api/map", mapRateLimiter, middleware.OptionalLoadUserMiddleware(jwtSecret), handlers.GetMap)
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"beleg-app/backend/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterOfflinePublicRoutes(r *gin.Engine, offlineRateLimiter gin.HandlerFunc) {
+	r.GET("/api/offline/countries", handlers.ListOfflineCountries)
+	r.GET("/api/offline/bundle", offlineRateLimiter, handlers.GetOfflineBundle)
+	r.GET("/api/offline/bundle/delta", offlineRateLimiter, handlers.GetOfflineBundleDelta)
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"beleg-app/backend/internal/handlers"
+	"beleg-app/backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPeakListPublicRoutes(r *gin.Engine, jwtSecret []byte) {
+	optionalAuth := middleware.OptionalLoadUserMiddleware(jwtSecret)
+	r.GET("/api/peak-lists", optionalAuth, handlers.ListPeakLists)
+	r.GET("/api/peak-lists/:slug", optionalAuth, handlers.GetPeakList)
+}
+
+func RegisterPeakRoutes(protected *gin.RouterGroup) {
+	protected.POST("/me/vrhovi", handlers.AddMyPeakAscent)
+	protected.DELETE("/me/vrhovi/:id", handlers.DeleteMyPeakAscent)
+
+	protected.POST("/peak-lists", handlers.CreatePeakList)
+	protected.PUT("/peak-lists/:id", handlers.UpdatePeakList)
+	protected.DELETE("/peak-lists/:id", handlers.DeletePeakList)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"beleg-app/backend/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPaymentPublicRoutes registruje webhook provajdera plaćanja (bez JWT-a; autentičnost daje potpis događaja).
+func RegisterPaymentPublicRoutes(r *gin.Engine) {
+	r.POST("/api/placanja/webhook/:provider", handlers.PlacanjeWebhook)
+}
+
+// RegisterPaymentRoutes registruje online plaćanja kotizacije i članarine, povraćaj i usklađivanje.
+func RegisterPaymentRoutes(g *gin.RouterGroup) {
+	g.POST("/akcije/:id/placanje", handlers.PokreniPlacanjeAkcije)
+	g.POST("/klub/clanarina/placanje", handlers.PokreniPlacanjeClanarine)
+	g.GET("/placanja/moja", handlers.GetMojaPlacanja)
+	g.POST("/placanja/fake/:checkoutId", handlers.PotvrdiFakePlacanje)
+	g.POST("/placanja/:id/povracaj", handlers.VratiPlacanje)
+	g.GET("/finansije/placanja", handlers.GetPlacanjaUskladjivanje)
+}
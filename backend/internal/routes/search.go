@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"beleg-app/backend/internal/handlers"
+	"beleg-app/backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSearchPublicRoutes(r *gin.Engine, jwtSecret []byte, searchRateLimiter gin.HandlerFunc) {
+	optionalAuth := middleware.OptionalLoadUserMiddleware(jwtSecret)
+	r.GET("/api/search", searchRateLimiter, optionalAuth, handlers.Search)
+	r.GET("/api/search/autocomplete", searchRateLimiter, optionalAuth, handlers.SearchAutocomplete)
+}
@@ -12,4 +12,7 @@ func RegisterUsersAdminRoutes(protected *gin.RouterGroup) {
 	protected.GET("/korisnici", handlers.GetKorisnici)
 	protected.GET("/korisnici/:id/info", handlers.GetKorisnikInfo)
 	protected.POST("/korisnici/:id/dodaj-proslu-akciju", handlers.AddProslaAkcija)
+	protected.GET("/korisnici/:id/sertifikati", handlers.GetKorisnikSertifikati)
+	protected.POST("/korisnici/:id/sertifikati", handlers.DodajKorisnikSertifikat)
+	protected.DELETE("/korisnici/:id/sertifikati/:sertifikatId", handlers.ObrisiKorisnikSertifikat)
 }
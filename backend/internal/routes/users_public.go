@@ -12,6 +12,9 @@ func RegisterUsersPublicRoutes(r *gin.Engine, jwtSecret []byte) {
 	r.GET("/api/korisnici/:id", optionalAuth, handlers.GetPublicKorisnik)
 	r.GET("/api/korisnici/:id/statistika", optionalAuth, handlers.GetPublicKorisnikStatistika)
 	r.GET("/api/korisnici/:id/popeo-se", optionalAuth, handlers.GetPublicKorisnikPopeoSe)
+	r.GET("/api/korisnici/:id/vrhovi", optionalAuth, handlers.GetPublicKorisnikVrhovi)
+	r.GET("/api/korisnici/:id/bedzevi", optionalAuth, handlers.GetPublicKorisnikBedzevi)
+	r.GET("/api/bedzevi", handlers.ListAchievementRules)
 	r.GET("/api/korisnici/:id/vodio", optionalAuth, handlers.GetPublicKorisnikVodio)
 	r.GET("/api/korisnici/:id/recenzije-vodica", optionalAuth, handlers.GetPublicKorisnikGuideRecenzije)
 }
@@ -0,0 +1,218 @@
+// Package routethumb crta statičnu PNG sličicu rute aktivnosti (trasa + traka visinskog profila)
+// bez servera pločica — samo linija na neutralnoj pozadini, za kartice aktivnosti i deljene objave.
+package routethumb
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+
+	"beleg-app/backend/internal/geo"
+
+	"golang.org/x/image/vector"
+)
+
+const (
+	Width         = 600
+	Height        = 360
+	profileHeight = 80
+	padding       = 24
+	routeWidth    = 4.0
+	markerRadius  = 7.0
+	// minProfileSpanM — ravna šetnja ne sme da izgleda kao strmi uspon.
+	minProfileSpanM = 50.0
+)
+
+var (
+	colorBackground = color.RGBA{0xF4, 0xF1, 0xEA, 0xFF}
+	colorProfileBg  = color.RGBA{0xE6, 0xE2, 0xD6, 0xFF}
+	colorProfile    = color.RGBA{0x7A, 0x9A, 0x84, 0xFF}
+	colorRoute      = color.RGBA{0xE4, 0x57, 0x2E, 0xFF}
+	colorStart      = color.RGBA{0x2E, 0x9E, 0x4F, 0xFF}
+	colorEnd        = color.RGBA{0x20, 0x20, 0x20, 0xFF}
+)
+
+var ErrTooFewPoints = errors.New("routethumb: ruta mora imati bar dve tačke")
+
+// Point je tačka rute; Altitude je opciona (bez visina se traka profila ostavlja praznom).
+type Point struct {
+	Lat      float64
+	Lng      float64
+	Altitude *float64
+}
+
+// Render crta sličicu: gore trasa u Web Mercator projekciji (očuvan odnos stranica), dole visinski profil po pređenoj distanci.
+func Render(points []Point) (*image.RGBA, error) {
+	if len(points) < 2 {
+		return nil, ErrTooFewPoints
+	}
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+	mapH := Height - profileHeight
+	draw.Draw(img, image.Rect(0, mapH, Width, Height), &image.Uniform{colorProfileBg}, image.Point{}, draw.Src)
+
+	drawRoute(img, points, float32(mapH))
+	drawProfile(img, points, float32(mapH))
+	return img, nil
+}
+
+// RenderPNG vraća PNG bajtove sličice (za upload kroz helpers.UploadImage).
+func RenderPNG(points []Point) ([]byte, error) {
+	img, err := Render(points)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func mercatorY(lat float64) float64 {
+	lat = math.Max(-85, math.Min(85, lat))
+	r := lat * math.Pi / 180
+	return math.Log(math.Tan(math.Pi/4 + r/2))
+}
+
+// project preslikava tačke u piksele okvira mape visine mapH (y raste nadole).
+func project(points []Point, mapH float32) ([]float32, []float32) {
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = p.Lng * math.Pi / 180
+		ys[i] = mercatorY(p.Lat)
+		minX, maxX = math.Min(minX, xs[i]), math.Max(maxX, xs[i])
+		minY, maxY = math.Min(minY, ys[i]), math.Max(maxY, ys[i])
+	}
+	boxW := float64(Width - 2*padding)
+	boxH := float64(mapH) - 2*padding
+	spanX, spanY := maxX-minX, maxY-minY
+	scale := math.Inf(1)
+	if spanX > 0 {
+		scale = boxW / spanX
+	}
+	if spanY > 0 {
+		scale = math.Min(scale, boxH/spanY)
+	}
+	if math.IsInf(scale, 1) {
+		scale = 1 // sve tačke na istom mestu: crta se samo marker
+	}
+	offX := padding + (boxW-spanX*scale)/2
+	offY := padding + (boxH-spanY*scale)/2
+	px := make([]float32, len(points))
+	py := make([]float32, len(points))
+	for i := range points {
+		px[i] = float32(offX + (xs[i]-minX)*scale)
+		py[i] = float32(offY + (maxY-ys[i])*scale)
+	}
+	return px, py
+}
+
+func drawRoute(img *image.RGBA, points []Point, mapH float32) {
+	px, py := project(points, mapH)
+	r := vector.NewRasterizer(Width, Height)
+	half := float32(routeWidth / 2)
+	for i := 1; i < len(points); i++ {
+		strokeSegment(r, px[i-1], py[i-1], px[i], py[i], half)
+		addCircle(r, px[i], py[i], half) // zaobljeni spojevi
+	}
+	r.Draw(img, img.Bounds(), &image.Uniform{colorRoute}, image.Point{})
+
+	last := len(points) - 1
+	fillCircle(img, px[last], py[last], markerRadius, colorEnd)
+	fillCircle(img, px[0], py[0], markerRadius, colorStart)
+}
+
+func drawProfile(img *image.RGBA, points []Point, top float32) {
+	dist := make([]float64, len(points))
+	minAlt, maxAlt := math.Inf(1), math.Inf(-1)
+	withAlt := 0
+	for i, p := range points {
+		if i > 0 {
+			prev := points[i-1]
+			dist[i] = dist[i-1] + geo.DistanceKmHaversine(prev.Lat, prev.Lng, p.Lat, p.Lng)
+		}
+		if p.Altitude != nil {
+			withAlt++
+			minAlt, maxAlt = math.Min(minAlt, *p.Altitude), math.Max(maxAlt, *p.Altitude)
+		}
+	}
+	total := dist[len(dist)-1]
+	if withAlt < 2 || total <= 0 {
+		return
+	}
+	if span := maxAlt - minAlt; span < minProfileSpanM {
+		minAlt -= (minProfileSpanM - span) / 2
+		maxAlt = minAlt + minProfileSpanM
+	}
+	const inset = 8
+	bottom := float32(Height)
+	usableH := float64(profileHeight - 2*inset)
+	left, usableW := float64(padding), float64(Width-2*padding)
+
+	r := vector.NewRasterizer(Width, Height)
+	first := true
+	var startX float32
+	var lastX float32
+	for i, p := range points {
+		if p.Altitude == nil {
+			continue
+		}
+		x := float32(left + dist[i]/total*usableW)
+		y := float32(float64(top) + inset + (maxAlt-*p.Altitude)/(maxAlt-minAlt)*usableH)
+		if first {
+			startX = x
+			r.MoveTo(x, bottom)
+			first = false
+		}
+		r.LineTo(x, y)
+		lastX = x
+	}
+	r.LineTo(lastX, bottom)
+	r.LineTo(startX, bottom)
+	r.ClosePath()
+	r.Draw(img, img.Bounds(), &image.Uniform{colorProfile}, image.Point{})
+}
+
+// strokeSegment dodaje pravougaonik debljine 2*half oko duži; svi pravougaonici imaju istu orijentaciju,
+// pa se preklapanja sabiraju (rasterizer ograničava pokrivenost na 1) umesto da se poništavaju.
+func strokeSegment(r *vector.Rasterizer, x0, y0, x1, y1, half float32) {
+	dx, dy := x1-x0, y1-y0
+	l := float32(math.Hypot(float64(dx), float64(dy)))
+	if l == 0 {
+		return
+	}
+	nx, ny := -dy/l*half, dx/l*half
+	r.MoveTo(x0+nx, y0+ny)
+	r.LineTo(x1+nx, y1+ny)
+	r.LineTo(x1-nx, y1-ny)
+	r.LineTo(x0-nx, y0-ny)
+	r.ClosePath()
+}
+
+// addCircle dodaje mnogougao oko (cx, cy) u istoj orijentaciji kao strokeSegment.
+func addCircle(r *vector.Rasterizer, cx, cy, radius float32) {
+	const steps = 12
+	r.MoveTo(cx+radius, cy)
+	for i := 1; i < steps; i++ {
+		a := -2 * math.Pi * float64(i) / steps
+		r.LineTo(cx+radius*float32(math.Cos(a)), cy+radius*float32(math.Sin(a)))
+	}
+	r.ClosePath()
+}
+
+func fillCircle(img *image.RGBA, cx, cy, radius float32, c color.Color) {
+	r := vector.NewRasterizer(Width, Height)
+	addCircle(r, cx, cy, radius+2)
+	r.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{})
+	r.Reset(Width, Height)
+	addCircle(r, cx, cy, radius)
+	r.Draw(img, img.Bounds(), &image.Uniform{c}, image.Point{})
+}
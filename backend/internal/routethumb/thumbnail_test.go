@@ -0,0 +1,71 @@
+package routethumb
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+)
+
+func alt(v float64) *float64 { return &v }
+
+func TestRender_RouteAndProfile(t *testing.T) {
+	// Vodoravna deonica pa skretanje pod pravim uglom nagore — spoj mora biti popunjen.
+	pts := []Point{
+		{Lat: 43.30, Lng: 21.90, Altitude: alt(400)},
+		{Lat: 43.30, Lng: 21.92, Altitude: alt(650)},
+		{Lat: 43.31, Lng: 21.92, Altitude: alt(900)},
+	}
+	img, err := Render(pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapH := Height - profileHeight
+	px, py := project(pts, float32(mapH))
+	mid := func(a, b float32) int { return int((a + b) / 2) }
+	if got := img.RGBAAt(mid(px[0], px[1]), int(py[0])); got != colorRoute {
+		t.Fatalf("expected route color on the first segment, got %v", got)
+	}
+	if got := img.RGBAAt(mid(px[1], px[2]), mid(py[1], py[2])); got != colorRoute {
+		t.Fatalf("expected route color on the second segment, got %v", got)
+	}
+	// Spoljni ugao spoja: pokriva ga samo zaobljenje, ne pravougaonici segmenata.
+	if got := img.RGBAAt(int(px[1]+1.2), int(py[1]+1.2)); got == colorBackground {
+		t.Fatalf("joint between segments must be filled")
+	}
+	// Preklapanje segmenta i zaobljenja ne sme da se poništi.
+	if got := img.RGBAAt(int(px[1])-1, int(py[1])); got != colorRoute {
+		t.Fatalf("overlapping stroke parts must stay filled, got %v", got)
+	}
+	if got := img.RGBAAt(Width/2, padding/2); got != colorBackground {
+		t.Fatalf("expected background above the route, got %v", got)
+	}
+	if got := img.RGBAAt(Width-padding-2, Height-2); got != colorProfile {
+		t.Fatalf("expected elevation profile at the bottom right, got %v", got)
+	}
+	if got := img.RGBAAt(padding+2, mapH+10); got != colorProfileBg {
+		t.Fatalf("low start must leave the top of the strip empty, got %v", got)
+	}
+
+	raw, err := RenderPNG(pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(raw))
+	if err != nil || decoded.Bounds().Dx() != Width || decoded.Bounds().Dy() != Height {
+		t.Fatalf("png round trip failed: %v", err)
+	}
+}
+
+func TestRender_WithoutAltitudeOrPoints(t *testing.T) {
+	img, err := Render([]Point{{Lat: 43.3, Lng: 21.9}, {Lat: 43.31, Lng: 21.91}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.RGBAAt(Width/2, Height-2); got != colorProfileBg {
+		t.Fatalf("without altitudes the strip stays empty, got %v", got)
+	}
+	if _, err := Render([]Point{{Lat: 43.3, Lng: 21.9}}); !errors.Is(err, ErrTooFewPoints) {
+		t.Fatalf("expected ErrTooFewPoints, got %v", err)
+	}
+}
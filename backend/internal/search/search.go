@@ -0,0 +1,114 @@
+// Package search gradi upite za globalnu pretragu (akcije, vrhovi, ferate, hoteli, vodiči, klubovi, korisnici).
+//
+// Na Postgres-u se koristi full-text pretraga nad beleg_fold(...) izrazom (GIN indeksi iz migracije
+// 000008 / PostAutoMigrateCreateSearchIndexes); beleg_fold i slug.FoldLatin moraju davati isti tekst.
+// Na ostalim dialektima (sqlite u testovima) ista pravila idu kroz LIKE nad beleg_fold, koju test registruje
+// kao SQLite funkciju nad slug.FoldLatin.
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"beleg-app/backend/internal/slug"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TipAkcija   = "akcija"
+	TipVrh      = "vrh"
+	TipFerata   = "ferata"
+	TipHotel    = "hotel"
+	TipVodic    = "vodic"
+	TipKlub     = "klub"
+	TipKorisnik = "korisnik"
+
+	// MinQueryLen — kraći upit bi pogodio pola baze.
+	MinQueryLen = 2
+	maxTokens   = 6
+)
+
+// Tipovi vraća sve tipove rezultata u redosledu prikaza.
+func Tipovi() []string {
+	return []string{TipAkcija, TipVrh, TipFerata, TipHotel, TipVodic, TipKlub, TipKorisnik}
+}
+
+// Document je tekst koji se pretražuje za jednu tabelu (kolone bez aliasa, kao u indeksu).
+type Document struct {
+	Table     string
+	IndexName string
+	Expr      string
+}
+
+// Documents — izrazi moraju biti identični onima u GIN indeksima da bi ih planer koristio.
+var Documents = map[string]Document{
+	TipAkcija:   {Table: "akcije", IndexName: "idx_akcije_search", Expr: "coalesce(naziv, '') || ' ' || coalesce(planina, '') || ' ' || coalesce(vrh, '')"},
+	TipVrh:      {Table: "peaks", IndexName: "idx_peaks_search", Expr: "coalesce(naziv_vrha, '') || ' ' || coalesce(planina, '') || ' ' || coalesce(grad, '') || ' ' || coalesce(drzava, '')"},
+	TipFerata:   {Table: "ferratas", IndexName: "idx_ferratas_search", Expr: "coalesce(naziv, '') || ' ' || coalesce(grad_opstina, '') || ' ' || coalesce(lokacija, '') || ' ' || coalesce(drzava, '')"},
+	TipHotel:    {Table: "hotels", IndexName: "idx_hotels_search", Expr: "coalesce(naziv, '') || ' ' || coalesce(adresa, '')"},
+	TipVodic:    {Table: "guide_profiles", IndexName: "idx_guide_profiles_search", Expr: "coalesce(naslov, '') || ' ' || coalesce(region, '') || ' ' || coalesce(grad, '') || ' ' || coalesce(drzava, '')"},
+	TipKlub:     {Table: "klubovi", IndexName: "idx_klubovi_search", Expr: "coalesce(naziv, '') || ' ' || coalesce(sediste, '')"},
+	TipKorisnik: {Table: "korisnici", IndexName: "idx_korisnici_search", Expr: "coalesce(username, '') || ' ' || coalesce(full_name, '')"},
+}
+
+// Fold normalizuje tekst za poređenje (mala slova, latinica, bez dijakritika, sažeti razmaci).
+func Fold(s string) string {
+	return strings.Join(strings.Fields(slug.FoldLatin(s)), " ")
+}
+
+// Tokens deli normalizovan upit na reči (samo slova i cifre, najviše 6).
+func Tokens(q string) []string {
+	parts := strings.FieldsFunc(Fold(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(parts) > maxTokens {
+		parts = parts[:maxTokens]
+	}
+	return parts
+}
+
+// TSQuery gradi prefiks tsquery („midz:* & vrh:*“); tokeni sadrže samo slova/cifre pa nema escape-a.
+func TSQuery(tokens []string) string {
+	parts := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		parts = append(parts, t+":*")
+	}
+	return strings.Join(parts, " & ")
+}
+
+// IsPostgres — full-text putanja postoji samo na Postgres-u.
+func IsPostgres(db *gorm.DB) bool {
+	return strings.EqualFold(db.Dialector.Name(), "postgres")
+}
+
+// Match dodaje uslov pretrage i vraća izraz za rang (kolone mogu biti kvalifikovane aliasom tabele).
+func Match(db *gorm.DB, q *gorm.DB, doc Document, tokens []string) (*gorm.DB, string) {
+	if IsPostgres(db) {
+		vector := "to_tsvector('simple', beleg_fold(" + doc.Expr + "))"
+		tsq := strings.ReplaceAll(TSQuery(tokens), "'", "''")
+		return q.Where(vector+" @@ to_tsquery('simple', ?)", TSQuery(tokens)),
+			"ts_rank(" + vector + ", to_tsquery('simple', '" + tsq + "'))"
+	}
+	for _, t := range tokens {
+		q = q.Where("beleg_fold("+doc.Expr+") LIKE ?", "%"+t+"%")
+	}
+	return q, "1.0"
+}
+
+// TitleBoost daje prednost rezultatima čiji naslov počinje upitom (autocomplete).
+func TitleBoost(title string, tokens []string) float64 {
+	if len(tokens) == 0 {
+		return 0
+	}
+	folded := Fold(title)
+	if strings.HasPrefix(folded, strings.Join(tokens, " ")) {
+		return 1
+	}
+	for _, w := range strings.Fields(folded) {
+		if strings.HasPrefix(w, tokens[0]) {
+			return 0.5
+		}
+	}
+	return 0
+}
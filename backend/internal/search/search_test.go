@@ -0,0 +1,119 @@
+package search
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"beleg-app/backend/internal/slug"
+)
+
+func TestFold_CyrillicAndDiacriticsEquivalent(t *testing.T) {
+	want := "midzor stara planina"
+	for _, in := range []string{"Миџор  Стара планина", "Midžor Stara Planina", "midzor stara planina"} {
+		if got := Fold(in); got != want {
+			t.Errorf("Fold(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := Fold("Ђердап Đerdap"); got != "djerdap djerdap" {
+		t.Errorf("expected đ/ђ → dj, got %q", got)
+	}
+	if got := Fold("Љубовиђа Њива"); got != "ljubovidja njiva" {
+		t.Errorf("expected digraphs, got %q", got)
+	}
+}
+
+func TestTokens_StripOperatorsAndBuildPrefixQuery(t *testing.T) {
+	tokens := Tokens("  Kopaonik & Pančićev | vrh:* !")
+	if got := TSQuery(tokens); got != "kopaonik:* & pancicev:* & vrh:*" {
+		t.Fatalf("unexpected tsquery %q", got)
+	}
+}
+
+// Izrazi u migraciji moraju biti isti kao u Documents, inače Postgres ne koristi GIN indekse.
+func TestMigrationIndexesMatchDocuments(t *testing.T) {
+	raw, err := os.ReadFile("../../migrations/000008_search_fulltext.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql := string(raw)
+	for tip, doc := range Documents {
+		if !strings.Contains(sql, doc.IndexName+" ON "+doc.Table) {
+			t.Errorf("%s: index %s missing in migration", tip, doc.IndexName)
+		}
+		if !strings.Contains(sql, "beleg_fold("+doc.Expr+")") {
+			t.Errorf("%s: migration expression differs from Documents", tip)
+		}
+	}
+}
+
+// foldSQL izvršava telo beleg_fold nad Go stringom: translate velikih slova, lower() pod C lokalom (samo ASCII),
+// replace digrafa, pa translate u latinicu. Literali se čitaju iz SQL-a redom kojim ih funkcija koristi.
+func foldSQL(t *testing.T, body, in string) string {
+	t.Helper()
+	lits := regexp.MustCompile(`'([^']*)'`).FindAllStringSubmatch(body, -1)
+	if len(lits) != 15 {
+		t.Fatalf("unexpected beleg_fold shape: %d literals", len(lits))
+	}
+	translate := func(s, from, to string) string {
+		f, tt := []rune(from), []rune(to)
+		if len(f) != len(tt) {
+			t.Fatalf("translate %q → %q: lengths differ", from, to)
+		}
+		m := make(map[rune]rune, len(f))
+		for i, r := range f {
+			m[r] = tt[i]
+		}
+		return strings.Map(func(r rune) rune {
+			if v, ok := m[r]; ok {
+				return v
+			}
+			return r
+		}, s)
+	}
+	s := translate(in, lits[1][1], lits[2][1])
+	s = strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+	for i := 3; i < 13; i += 2 {
+		s = strings.ReplaceAll(s, lits[i][1], lits[i+1][1])
+	}
+	return translate(s, lits[13][1], lits[14][1])
+}
+
+// beleg_fold (migracija i PostAutoMigrateCreateSearchIndexes) mora davati isto što i slug.FoldLatin,
+// i za velika slova, nezavisno od lokala baze.
+func TestBelegFoldMatchesFoldLatin(t *testing.T) {
+	fnBody := regexp.MustCompile(`(?s)AS \$\$(.*?)\$\$`)
+	var bodies []string
+	for _, path := range []string{"../../migrations/000008_search_fulltext.up.sql", "../database/search_indexes.go"} {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := fnBody.FindStringSubmatch(string(raw))
+		if m == nil {
+			t.Fatalf("%s: beleg_fold not found", path)
+		}
+		bodies = append(bodies, m[1])
+	}
+	if bodies[0] != bodies[1] {
+		t.Fatal("beleg_fold in migration 000008 differs from PostAutoMigrateCreateSearchIndexes")
+	}
+	lits := regexp.MustCompile(`'([^']*)'`).FindAllStringSubmatch(bodies[0], -1)
+	inputs := []string{"Миџор Стара планина", "ЉУБОВИЂА Њива ЏЕП", "ĐERDAP Čačak Šabac Žabljak Ćuprija", "Kopaonik 2026"}
+	for _, lit := range lits {
+		for _, r := range lit[1] {
+			inputs = append(inputs, string(r), strings.ToUpper(string(r)))
+		}
+	}
+	for _, in := range inputs {
+		if got, want := foldSQL(t, bodies[0], in), slug.FoldLatin(in); got != want {
+			t.Errorf("beleg_fold(%q) = %q, slug.FoldLatin = %q", in, got, want)
+		}
+	}
+}
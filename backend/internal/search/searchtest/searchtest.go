@@ -0,0 +1,18 @@
+// Package searchtest registruje beleg_fold kao SQLite funkciju, da bi search.Match u testovima išao kroz isti
+// izraz kao na Postgres-u. Testovi ga uvoze samo radi efekta: import _ ".../internal/search/searchtest".
+package searchtest
+
+import (
+	"database/sql/driver"
+
+	"beleg-app/backend/internal/slug"
+
+	sqlite "github.com/glebarez/go-sqlite"
+)
+
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("beleg_fold", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		s, _ := args[0].(string)
+		return slug.FoldLatin(s), nil
+	})
+}
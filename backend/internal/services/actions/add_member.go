@@ -149,6 +149,9 @@ func applyCompletedActionMemberTx(
 		if err := tx.Save(&korisnik).Error; err != nil {
 			return completedMemberApplyResult{}, err
 		}
+		if err := helpers.RecordAkcijaPeakAscentTx(tx, akcija, korisnikID); err != nil {
+			return completedMemberApplyResult{}, err
+		}
 		shouldNotify = true
 	}
 
@@ -85,8 +85,9 @@ func CollectCancelRecipientIDs(tx *gorm.DB, actionID, actorID uint) ([]uint, err
 
 // CancelAction otkazuje aktivnu akciju.
 // Redoslijed: lock Akcija → authorize → already-cancelled → already-completed
-// → recipient snapshot → cancel pending signups → revoke invites → update cancellation polja.
-// Ne mijenja Prijava, Platio, Transakcija, statistike ni ActionParticipationRequest.
+// → recipient snapshot → obaveze povraćaja → cancel pending signups → revoke invites → update cancellation polja.
+// Ne mijenja Prijava, Platio, Transakcija, statistike ni ActionParticipationRequest; plaćenim prijavama
+// upisuje ObavezaPovracaja po politici povraćaja (isplata se knjiži kada je blagajnik izvrši).
 // Notifikacije se šalju van TX nakon uspješnog commita (handler).
 //
 // authorize se poziva unutar TX nad zaključanom akcijom; vrati ErrCancelUnauthorized ako nema prava.
@@ -132,6 +133,9 @@ func CancelAction(
 
 		cancelledAt := time.Now()
 
+		if _, err := helpers.EvidentirajObavezePovracajaZaOtkazAkcijeTx(tx, *locked, cancelledAt); err != nil {
+			return err
+		}
 		if _, err := helpers.CancelPendingSignupRequestsForActionTx(tx, locked.ID, cancelledAt); err != nil {
 			return err
 		}
@@ -3,6 +3,7 @@ package actions
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -411,6 +412,46 @@ func TestCancelAction_PrijaveAndFinanceUntouched(t *testing.T) {
 	}
 }
 
+func TestCancelAction_RecordsRefundObligations(t *testing.T) {
+	db := testFinishDB(t)
+	if err := db.AutoMigrate(&models.AkcijaPrevoz{}); err != nil {
+		t.Fatal(err)
+	}
+	actor := seedFinishActor(t, db, "cancel_refund")
+	akcija := seedFinishAkcija(t, db, actor, func(a *models.Akcija) {
+		a.VodicID = 0
+		a.Javna = true
+		a.CenaOstali = 100
+	})
+	bus := models.AkcijaPrevoz{AkcijaID: akcija.ID, TipPrevoza: "Autobus", NazivGrupe: "Prevoznik", CenaPoOsobi: 40}
+	db.Create(&bus)
+	pola := 50.0
+	db.Create(&models.AkcijaPolitikaPovracaja{AkcijaID: akcija.ID, OtkazKlubaProcenat: &pola, NepovratniPrevoz: "[" + strconv.Itoa(int(bus.ID)) + "]"})
+
+	placena := seedFinishPrijava(t, db, akcija.ID, "ref_placena", "prijavljen", true)
+	db.Create(&models.PrijavaIzbori{PrijavaID: placena.ID, SelectedPrevozIDs: "[" + strconv.Itoa(int(bus.ID)) + "]"})
+	seedFinishPrijava(t, db, akcija.ID, "ref_neplacena", "prijavljen", false)
+	beforeTx := countTransakcije(t, db)
+
+	if _, err := CancelAction(db, akcija.ID, "Loši uslovi na planini", actor.ID, allowCancel); err != nil {
+		t.Fatal(err)
+	}
+
+	var obaveze []models.ObavezaPovracaja
+	db.Find(&obaveze)
+	if len(obaveze) != 1 {
+		t.Fatalf("obligations: %+v", obaveze)
+	}
+	o := obaveze[0]
+	if o.PrijavaID != placena.ID || o.Razlog != models.ObavezaPovracajaOtkazAkcije || o.Uplaceno != 140 ||
+		o.Nepovratno != 40 || o.Procenat != 50 || o.Iznos != 50 || o.Status != models.ObavezaPovracajaOtvorena {
+		t.Fatalf("obligation: %+v", o)
+	}
+	if countTransakcije(t, db) != beforeTx {
+		t.Fatal("obligation must not be booked before payout")
+	}
+}
+
 func TestCancelAction_SignupCleanupError_Rollback(t *testing.T) {
 	db := testFinishDB(t)
 	actor := seedFinishActor(t, db, "cancel_rb_s")
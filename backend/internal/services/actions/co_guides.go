@@ -0,0 +1,163 @@
+package actions
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCoGuideUnauthorized = errors.New("Nemate pravo da menjate vodiče ove akcije")
+	ErrCoGuideRoleInvalid  = errors.New("Uloga vodiča mora biti pomocni ili zatvarac.")
+	ErrCoGuideNotFound     = errors.New("Vodič nije pronađen (korisnik ili odobren profil vodiča).")
+	ErrCoGuideIsLead       = errors.New("Korisnik je već glavni vodič ove akcije.")
+	ErrCoGuideNotAssigned  = errors.New("Korisnik nije ko-vodič ove akcije.")
+)
+
+// CoGuideInput dodeljuje ko-vodiča: registrovan korisnik (KorisnikID) ili odobren profil vodiča (GuideProfileID).
+type CoGuideInput struct {
+	KorisnikID     uint
+	GuideProfileID uint
+	Uloga          string
+}
+
+// CoGuideResult je sačuvan ko-vodič; Added je false kada je samo promenjena uloga.
+type CoGuideResult struct {
+	Vodic *models.AkcijaVodic
+	Added bool
+}
+
+// resolveCoGuide vraća korisnika i (opciono) odobren profil vodiča za unos.
+func resolveCoGuide(tx *gorm.DB, in CoGuideInput) (uint, *uint, error) {
+	var gp models.GuideProfile
+	if in.GuideProfileID > 0 {
+		if err := tx.Where("id = ? AND status = ?", in.GuideProfileID, models.GuideStatusApproved).First(&gp).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, nil, ErrCoGuideNotFound
+			}
+			return 0, nil, err
+		}
+		if in.KorisnikID > 0 && in.KorisnikID != gp.KorisnikID {
+			return 0, nil, ErrCoGuideNotFound
+		}
+		return gp.KorisnikID, &gp.ID, nil
+	}
+	if in.KorisnikID == 0 {
+		return 0, nil, ErrCoGuideNotFound
+	}
+	var k models.Korisnik
+	if err := tx.Select("id").First(&k, in.KorisnikID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil, ErrCoGuideNotFound
+		}
+		return 0, nil, err
+	}
+	if err := tx.Where("korisnik_id = ? AND status = ?", k.ID, models.GuideStatusApproved).First(&gp).Error; err == nil {
+		return k.ID, &gp.ID, nil
+	}
+	return k.ID, nil, nil
+}
+
+// touchAkcijaTx pomera updated_at akcije da delta sync (i ko-vodičima) isporuči promenu vodiča.
+func touchAkcijaTx(tx *gorm.DB, akcijaID uint) error {
+	return tx.Model(&models.Akcija{}).Where("id = ?", akcijaID).UpdateColumn("updated_at", time.Now()).Error
+}
+
+// lockCoGuideAkcijaTx zaključava akciju za izmenu vodiča (i forme prijave): authorize, pa otkazana/završena akcija
+// se ne menja (ocene i vođene ture završene akcije vezane su za vodiče u trenutku završetka).
+func lockCoGuideAkcijaTx(tx *gorm.DB, actionID uint, authorize func(tx *gorm.DB, akcija *models.Akcija) error) (*models.Akcija, error) {
+	locked, err := helpers.LockAkcijaForUpdate(tx, actionID)
+	if err != nil {
+		return nil, err
+	}
+	if authorize != nil {
+		if err := authorize(tx, locked); err != nil {
+			return nil, err
+		}
+	}
+	if locked.IsCancelled {
+		return nil, helpers.ErrAkcijaAlreadyCancelled
+	}
+	if locked.IsCompleted {
+		return nil, helpers.ErrAkcijaAlreadyComplete
+	}
+	return locked, nil
+}
+
+// AssignCoGuide dodaje ko-vodiča akciji ili menja ulogu postojećem. Obaveštenje šalje handler posle commita.
+func AssignCoGuide(
+	db *gorm.DB,
+	actionID uint,
+	in CoGuideInput,
+	actorID uint,
+	authorize func(tx *gorm.DB, akcija *models.Akcija) error,
+) (*CoGuideResult, error) {
+	uloga := strings.ToLower(strings.TrimSpace(in.Uloga))
+	if uloga == "" {
+		uloga = models.AkcijaVodicUlogaPomocni
+	}
+	if !helpers.IsValidAkcijaVodicUloga(uloga) {
+		return nil, ErrCoGuideRoleInvalid
+	}
+	var out models.AkcijaVodic
+	added := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockCoGuideAkcijaTx(tx, actionID, authorize)
+		if err != nil {
+			return err
+		}
+		korisnikID, profileID, err := resolveCoGuide(tx, in)
+		if err != nil {
+			return err
+		}
+		if korisnikID == locked.VodicID {
+			return ErrCoGuideIsLead
+		}
+		err = tx.Where("akcija_id = ? AND korisnik_id = ?", locked.ID, korisnikID).First(&out).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			out = models.AkcijaVodic{AkcijaID: locked.ID, KorisnikID: korisnikID, GuideProfileID: profileID, Uloga: uloga, DodaoID: actorID}
+			added = true
+			if err := tx.Create(&out).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			out.Uloga = uloga
+			out.GuideProfileID = profileID
+			if err := tx.Model(&models.AkcijaVodic{}).Where("id = ?", out.ID).
+				Updates(map[string]any{"uloga": uloga, "guide_profile_id": profileID}).Error; err != nil {
+				return err
+			}
+		}
+		return touchAkcijaTx(tx, locked.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CoGuideResult{Vodic: &out, Added: added}, nil
+}
+
+// RemoveCoGuide uklanja ko-vodiča sa akcije.
+func RemoveCoGuide(db *gorm.DB, actionID, korisnikID uint, authorize func(tx *gorm.DB, akcija *models.Akcija) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockCoGuideAkcijaTx(tx, actionID, authorize)
+		if err != nil {
+			return err
+		}
+		res := tx.Where("akcija_id = ? AND korisnik_id = ?", locked.ID, korisnikID).Delete(&models.AkcijaVodic{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrCoGuideNotAssigned
+		}
+		return touchAkcijaTx(tx, locked.ID)
+	})
+}
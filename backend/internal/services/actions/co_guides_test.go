@@ -0,0 +1,116 @@
+package actions
+
+import (
+	"errors"
+	"testing"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func testCoGuideDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := testFinishDB(t)
+	if err := db.AutoMigrate(&models.AkcijaVodic{}, &models.GuideProfile{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func seedApprovedGuideProfile(t *testing.T, db *gorm.DB, korisnikID uint) models.GuideProfile {
+	t.Helper()
+	gp := models.GuideProfile{KorisnikID: korisnikID, Status: models.GuideStatusApproved, Naslov: "Vodič", Opis: "Opis"}
+	if err := db.Create(&gp).Error; err != nil {
+		t.Fatal(err)
+	}
+	return gp
+}
+
+func TestAssignCoGuide_UserAndGuideProfileWithRoles(t *testing.T) {
+	db := testCoGuideDB(t)
+	lead := seedFinishActor(t, db, "co_lead")
+	akcija := seedFinishAkcija(t, db, lead)
+	assistant := models.Korisnik{Username: "co_assistant", Password: "x", Role: "clan"}
+	profi := models.Korisnik{Username: "co_profi", Password: "x", Role: "clan"}
+	db.Create(&assistant)
+	db.Create(&profi)
+	gp := seedApprovedGuideProfile(t, db, profi.ID)
+
+	res, err := AssignCoGuide(db, akcija.ID, CoGuideInput{KorisnikID: assistant.ID}, lead.ID, allowCancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Added || res.Vodic.Uloga != models.AkcijaVodicUlogaPomocni || res.Vodic.GuideProfileID != nil {
+		t.Fatalf("registered user must be added as assistant: %+v", res.Vodic)
+	}
+	res, err = AssignCoGuide(db, akcija.ID, CoGuideInput{GuideProfileID: gp.ID, Uloga: "zatvarac"}, lead.ID, allowCancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Vodic.KorisnikID != profi.ID || res.Vodic.GuideProfileID == nil || *res.Vodic.GuideProfileID != gp.ID {
+		t.Fatalf("guide profile must resolve to its user: %+v", res.Vodic)
+	}
+	res, err = AssignCoGuide(db, akcija.ID, CoGuideInput{KorisnikID: assistant.ID, Uloga: "zatvarac"}, lead.ID, allowCancel)
+	if err != nil || res.Added || res.Vodic.Uloga != models.AkcijaVodicUlogaZatvarac {
+		t.Fatalf("second assign must only change the role: %+v %v", res, err)
+	}
+
+	if _, err := AssignCoGuide(db, akcija.ID, CoGuideInput{KorisnikID: lead.ID}, lead.ID, allowCancel); !errors.Is(err, ErrCoGuideIsLead) {
+		t.Fatalf("lead guide as co-guide, got %v", err)
+	}
+	if _, err := AssignCoGuide(db, akcija.ID, CoGuideInput{KorisnikID: assistant.ID, Uloga: "glavni"}, lead.ID, allowCancel); !errors.Is(err, ErrCoGuideRoleInvalid) {
+		t.Fatalf("invalid role, got %v", err)
+	}
+	if _, err := AssignCoGuide(db, akcija.ID, CoGuideInput{KorisnikID: 9999}, lead.ID, allowCancel); !errors.Is(err, ErrCoGuideNotFound) {
+		t.Fatalf("unknown user, got %v", err)
+	}
+
+	if ids := helpers.AkcijaGuideIDs(db, &akcija); len(ids) != 3 || ids[0] != lead.ID || ids[1] != assistant.ID || ids[2] != profi.ID {
+		t.Fatalf("guide ids: %v", ids)
+	}
+	if err := RemoveCoGuide(db, akcija.ID, assistant.ID, allowCancel); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveCoGuide(db, akcija.ID, assistant.ID, allowCancel); !errors.Is(err, ErrCoGuideNotAssigned) {
+		t.Fatalf("removing twice, got %v", err)
+	}
+	if helpers.IsAkcijaGuide(db, &akcija, assistant.ID) || !helpers.IsAkcijaGuide(db, &akcija, profi.ID) {
+		t.Fatal("guide membership after removal")
+	}
+}
+
+func TestFinishAction_CreditsEveryApprovedGuide(t *testing.T) {
+	db := testCoGuideDB(t)
+	lead := seedFinishActor(t, db, "credit_lead")
+	akcija := seedFinishAkcija(t, db, lead)
+	co := models.Korisnik{Username: "credit_co", Password: "x", Role: "clan"}
+	plain := models.Korisnik{Username: "credit_plain", Password: "x", Role: "clan"}
+	db.Create(&co)
+	db.Create(&plain)
+	leadProfile := seedApprovedGuideProfile(t, db, lead.ID)
+	coProfile := seedApprovedGuideProfile(t, db, co.ID)
+	for _, u := range []models.Korisnik{co, plain} {
+		if _, err := AssignCoGuide(db, akcija.ID, CoGuideInput{KorisnikID: u.ID}, lead.ID, allowCancel); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := FinishAction(db, &akcija, lead, FinishActionInput{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []uint{leadProfile.ID, coProfile.ID} {
+		var gp models.GuideProfile
+		db.First(&gp, id)
+		if gp.BrojVodjenihTura != 1 {
+			t.Fatalf("guide profile %d must be credited once, got %d", id, gp.BrojVodjenihTura)
+		}
+	}
+	if _, err := AssignCoGuide(db, akcija.ID, CoGuideInput{KorisnikID: plain.ID}, lead.ID, allowCancel); !errors.Is(err, helpers.ErrAkcijaAlreadyComplete) {
+		t.Fatalf("guides of a completed action are fixed, got %v", err)
+	}
+	if !helpers.AkcijaCountsAsGuidedTourForVodic(db, &akcija, co.ID) {
+		t.Fatal("co-guide must get the guided tour")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"beleg-app/backend/internal/achievements"
 	"beleg-app/backend/internal/helpers"
 	"beleg-app/backend/internal/models"
 	"beleg-app/backend/internal/notifications"
@@ -37,6 +38,11 @@ type FinishActionResult struct {
 	SummitRewardNotifications []SummitRewardNotification
 }
 
+// achievementsEvaluate je post-commit helper za bedževe; testovi mogu override-ovati.
+var achievementsEvaluate = func(db *gorm.DB, korisnikIDs []uint) {
+	achievements.EvaluateBestEffort(db, korisnikIDs, achievements.EventActionFinished)
+}
+
 // summitRewardNotify je post-commit helper; testovi mogu override-ovati.
 var summitRewardNotify = func(db *gorm.DB, userID uint, akcija models.Akcija) {
 	notifications.NotifySummitReward(db, userID, akcija)
@@ -87,8 +93,8 @@ func notifySummitRewardsBestEffort(db *gorm.DB, akcija models.Akcija, items []Su
 
 // FinishAction završava akciju i upisuje finansijski efekat u klub.
 // Redoslijed: lock Akcija → cancel pending signup → revoke invites → lock Prijava
-// → guide promote → unresolved guard → IsCompleted → finansije → commit
-// → summit reward notification (best-effort).
+// → guide promote → unresolved guard → IsCompleted → vođene ture vodičima → finansije → commit
+// → summit reward notification i bedževi (best-effort).
 func FinishAction(db *gorm.DB, akcija *models.Akcija, actor models.Korisnik, in FinishActionInput) (*FinishActionResult, error) {
 	const finEps = 1e-6
 	importedCount := 0
@@ -97,6 +103,7 @@ func FinishAction(db *gorm.DB, akcija *models.Akcija, actor models.Korisnik, in
 	netoFinansije := 0.0
 	rashodNaAkciji := in.RashodNaAkciji
 	var summitNotifs []SummitRewardNotification
+	var summitedIDs []uint
 
 	err := db.Transaction(func(tx *gorm.DB) error {
 		locked, err := helpers.LockAkcijaForUpdate(tx, akcija.ID)
@@ -138,11 +145,20 @@ func FinishAction(db *gorm.DB, akcija *models.Akcija, actor models.Korisnik, in
 		if err := tx.Save(akcija).Error; err != nil {
 			return err
 		}
+		if err := helpers.CreditGuidedToursTx(tx, akcija); err != nil {
+			return err
+		}
 
 		// Snapshot samo kada je reward domain efekat stvarno izvršen u ovoj TX.
 		if guidePromoted && akcija.VodicID != 0 {
 			summitNotifs = append(summitNotifs, SummitRewardNotification{RecipientUserID: akcija.VodicID})
 		}
+		if err := tx.Model(&models.Prijava{}).
+			Where("akcija_id = ? AND status = ?", akcija.ID, "popeo se").
+			Order("korisnik_id").
+			Pluck("korisnik_id", &summitedIDs).Error; err != nil {
+			return err
+		}
 
 		var prijave []models.Prijava
 		if err := tx.Preload("Korisnik").
@@ -152,6 +168,12 @@ func FinishAction(db *gorm.DB, akcija *models.Akcija, actor models.Korisnik, in
 			return err
 		}
 
+		// Online uplate su već knjižene pri webhook-u; ovde ulazi samo ostatak salda (gotovina/ručno označeno).
+		onlineUplate, err := helpers.OnlineUplatePoPrijavi(tx, akcija.ID)
+		if err != nil {
+			return err
+		}
+
 		if len(prijave) > 0 {
 			for _, p := range prijave {
 				var izbor models.PrijavaIzbori
@@ -167,8 +189,10 @@ func FinishAction(db *gorm.DB, akcija *models.Akcija, actor models.Korisnik, in
 					SelectedSmestajIDs: selSmestaj,
 					SelectedPrevozIDs:  selPrevoz,
 					SelectedRentItems:  selRent,
+					Cena:               helpers.ParseCenaObracun(izbor.CenaObracun),
 				})
-				if saldo <= 0 {
+				saldo -= onlineUplate[p.ID]
+				if saldo <= finEps {
 					continue
 				}
 				prihodUkupan += saldo
@@ -208,6 +232,7 @@ func FinishAction(db *gorm.DB, akcija *models.Akcija, actor models.Korisnik, in
 	}
 
 	notifySummitRewardsBestEffort(db, *akcija, summitNotifs)
+	achievementsEvaluate(db, summitedIDs)
 
 	return &FinishActionResult{
 		Akcija:                    *akcija,
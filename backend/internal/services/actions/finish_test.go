@@ -30,6 +30,8 @@ func testFinishDB(t *testing.T) *gorm.DB {
 		&models.ActionSignupRequest{},
 		&models.ActionInviteLink{},
 		&models.Transakcija{},
+		&models.Placanje{},
+		&models.AkcijaPolitikaPovracaja{}, &models.ObavezaPovracaja{},
 		&models.Obavestenje{},
 	); err != nil {
 		t.Fatalf("migrate: %v", err)
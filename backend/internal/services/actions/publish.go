@@ -0,0 +1,260 @@
+package actions
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"beleg-app/backend/internal/deltasync"
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPublishUnauthorized     = errors.New("Nemate pravo da objavite ovu akciju")
+	ErrReviewUnauthorized      = errors.New("Samo admin kluba može da odobri ili vrati akciju")
+	ErrPublishAlreadyPublished = errors.New("Akcija je već objavljena.")
+	ErrPublishAtInvalid        = errors.New("Vreme objavljivanja mora biti pre početka akcije.")
+	ErrPublishNotInReview      = errors.New("Akcija ne čeka odobrenje.")
+	ErrReviewNoteInvalid       = errors.New("Napomena mora imati između 3 i 500 karaktera.")
+)
+
+// PublishActionInput je zahtev za objavljivanje; PublisherRole odlučuje o pregledu admina kluba.
+type PublishActionInput struct {
+	ObjaviAt      *time.Time
+	PublisherRole string
+}
+
+// PublishActionResult je novo stanje akcije; ReviewerIDs su admini kluba kad akcija ide na pregled.
+type PublishActionResult struct {
+	Akcija      *models.Akcija
+	ReviewerIDs []uint
+}
+
+// ReviewActionResult je ishod pregleda; AuthorID dobija obaveštenje.
+type ReviewActionResult struct {
+	Akcija   *models.Akcija
+	Approved bool
+	AuthorID uint
+}
+
+// akcijaStart je trenutak početka akcije (polazak, a bez satnice početak dana).
+func akcijaStart(a *models.Akcija) time.Time {
+	if a.StartAt != nil {
+		return *a.StartAt
+	}
+	return a.Datum
+}
+
+// publishedUpdates vraća kolone za prelaz u dati status objave (ObjaviAt važi samo dok akcija čeka).
+func publishedUpdates(status string, objaviAt *time.Time, now time.Time) map[string]any {
+	updates := map[string]any{"status_objave": status, "objavi_at": objaviAt}
+	if status == models.AkcijaStatusObjavljena {
+		updates["objavi_at"] = nil
+		updates["objavljena_at"] = now
+	}
+	return updates
+}
+
+// lockUnpublishedAkcijaTx zaključava akciju i primenjuje authorize i lifecycle guardove.
+func lockUnpublishedAkcijaTx(tx *gorm.DB, actionID uint, authorize func(tx *gorm.DB, akcija *models.Akcija) error) (*models.Akcija, error) {
+	locked, err := helpers.LockAkcijaForUpdate(tx, actionID)
+	if err != nil {
+		return nil, err
+	}
+	if authorize != nil {
+		if err := authorize(tx, locked); err != nil {
+			return nil, err
+		}
+	}
+	if locked.IsCancelled {
+		return nil, helpers.ErrAkcijaAlreadyCancelled
+	}
+	if locked.IsCompleted {
+		return nil, helpers.ErrAkcijaAlreadyComplete
+	}
+	if helpers.IsAkcijaObjavljena(locked) {
+		return nil, ErrPublishAlreadyPublished
+	}
+	return locked, nil
+}
+
+// PublishAction objavljuje nacrt (ili menja zakazano vreme): akcija vodiča ide na pregled ako ga klub traži,
+// ObjaviAt u budućnosti zakazuje objavljivanje, inače je akcija odmah objavljena.
+// Admin koji objavi akciju na pregledu time je i odobrava. Obaveštenja šalje handler posle commita.
+func PublishAction(
+	db *gorm.DB,
+	actionID uint,
+	in PublishActionInput,
+	actorID uint,
+	now time.Time,
+	authorize func(tx *gorm.DB, akcija *models.Akcija) error,
+) (*PublishActionResult, error) {
+	var out models.Akcija
+	var reviewers []uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockUnpublishedAkcijaTx(tx, actionID, authorize)
+		if err != nil {
+			return err
+		}
+		if in.ObjaviAt != nil && !in.ObjaviAt.Before(akcijaStart(locked)) {
+			return ErrPublishAtInvalid
+		}
+		needsReview := helpers.AkcijaRequiresClubReview(tx, locked, in.PublisherRole)
+		status := helpers.ResolvePublishStatus(needsReview, in.ObjaviAt, now)
+		updates := publishedUpdates(status, in.ObjaviAt, now)
+		updates["pregled_napomena"] = ""
+		if locked.StatusObjave == models.AkcijaStatusNaPregledu && !needsReview {
+			updates["pregledao_id"] = actorID
+		}
+		if err := tx.Model(&models.Akcija{}).Where("id = ?", locked.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if status == models.AkcijaStatusNaPregledu && locked.KlubID != nil {
+			ids, err := helpers.ClubActionReviewerIDs(tx, *locked.KlubID)
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if id != actorID {
+					reviewers = append(reviewers, id)
+				}
+			}
+		}
+		return tx.First(&out, locked.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PublishActionResult{Akcija: &out, ReviewerIDs: reviewers}, nil
+}
+
+// ReviewAction: admin kluba odobrava akciju na pregledu (objava odmah ili u ObjaviAt)
+// ili je vraća u nacrt sa obaveznom napomenom.
+func ReviewAction(
+	db *gorm.DB,
+	actionID uint,
+	approve bool,
+	napomena string,
+	reviewerID uint,
+	now time.Time,
+	authorize func(tx *gorm.DB, akcija *models.Akcija) error,
+) (*ReviewActionResult, error) {
+	note := strings.TrimSpace(napomena)
+	if !approve {
+		if n := utf8.RuneCountInString(note); n < 3 || n > 500 {
+			return nil, ErrReviewNoteInvalid
+		}
+	}
+	var out models.Akcija
+	var authorID uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockUnpublishedAkcijaTx(tx, actionID, authorize)
+		if err != nil {
+			return err
+		}
+		if locked.StatusObjave != models.AkcijaStatusNaPregledu {
+			return ErrPublishNotInReview
+		}
+		authorID = locked.AddedByID
+		if authorID == 0 {
+			authorID = locked.VodicID
+		}
+		var updates map[string]any
+		if approve {
+			updates = publishedUpdates(helpers.ResolvePublishStatus(false, locked.ObjaviAt, now), locked.ObjaviAt, now)
+			updates["pregled_napomena"] = ""
+		} else {
+			updates = map[string]any{"status_objave": models.AkcijaStatusNacrt, "pregled_napomena": note}
+			if err := recordDraftHiddenTx(tx, locked); err != nil {
+				return err
+			}
+		}
+		updates["pregledao_id"] = reviewerID
+		if err := tx.Model(&models.Akcija{}).Where("id = ?", locked.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&out, locked.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ReviewActionResult{Akcija: &out, Approved: approve, AuthorID: authorID}, nil
+}
+
+// ReturnActionToDraft povlači akciju sa pregleda ili otkazuje zakazano objavljivanje.
+// Objavljena akcija ostaje objavljena (možda već ima prijave); za nju postoji otkazivanje.
+func ReturnActionToDraft(db *gorm.DB, actionID uint, authorize func(tx *gorm.DB, akcija *models.Akcija) error) (*models.Akcija, error) {
+	var out models.Akcija
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockUnpublishedAkcijaTx(tx, actionID, authorize)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Akcija{}).Where("id = ?", locked.ID).Updates(map[string]any{
+			"status_objave": models.AkcijaStatusNacrt,
+			"objavi_at":     nil,
+		}).Error; err != nil {
+			return err
+		}
+		if err := recordDraftHiddenTx(tx, locked); err != nil {
+			return err
+		}
+		return tx.First(&out, locked.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// recordDraftHiddenTx: nacrt /api/sync šalje samo autoru i vodiču, pa prijavljenima i ko-vodičima
+// akcija stiže kao obrisana (posle objave ponovo dolazi kao izmenjena).
+func recordDraftHiddenTx(tx *gorm.DB, locked *models.Akcija) error {
+	var userIDs []uint
+	if err := tx.Model(&models.Prijava{}).Where("akcija_id = ?", locked.ID).Pluck("korisnik_id", &userIDs).Error; err != nil {
+		return err
+	}
+	var coGuideIDs []uint
+	if err := tx.Model(&models.AkcijaVodic{}).Where("akcija_id = ?", locked.ID).Pluck("korisnik_id", &coGuideIDs).Error; err != nil {
+		return err
+	}
+	seen := map[uint]bool{locked.AddedByID: true, locked.VodicID: true}
+	hidden := make([]uint, 0, len(userIDs)+len(coGuideIDs))
+	for _, id := range append(userIDs, coGuideIDs...) {
+		if !seen[id] {
+			seen[id] = true
+			hidden = append(hidden, id)
+		}
+	}
+	return deltasync.RecordHidden(tx, deltasync.EntitetAkcija, []uint{locked.ID}, hidden)
+}
+
+// PublishDueActions objavljuje zakazane akcije kojima je došlo vreme (uslovni update — bezbedno za više instanci).
+// Vraća samo akcije koje je ovaj poziv objavio, radi obaveštenja „nova akcija”.
+func PublishDueActions(db *gorm.DB, now time.Time) ([]models.Akcija, error) {
+	var due []models.Akcija
+	if err := db.Where("status_objave = ? AND objavi_at IS NOT NULL AND objavi_at <= ? AND is_cancelled = ? AND is_completed = ?",
+		models.AkcijaStatusZakazana, now, false, false).Order("objavi_at").Find(&due).Error; err != nil {
+		return nil, err
+	}
+	var published []models.Akcija
+	for _, a := range due {
+		res := db.Model(&models.Akcija{}).Where("id = ? AND status_objave = ?", a.ID, models.AkcijaStatusZakazana).
+			Updates(publishedUpdates(models.AkcijaStatusObjavljena, nil, now))
+		if res.Error != nil {
+			return published, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+		a.StatusObjave = models.AkcijaStatusObjavljena
+		a.ObjaviAt = nil
+		a.ObjavljenaAt = &now
+		published = append(published, a)
+	}
+	return published, nil
+}
@@ -0,0 +1,167 @@
+package actions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func testPublishDB(t *testing.T) (*gorm.DB, models.Klubovi) {
+	t.Helper()
+	db := testFinishDB(t)
+	if err := db.AutoMigrate(&models.Klubovi{}, &models.AkcijaVodic{}, &models.SyncTombstone{}); err != nil {
+		t.Fatal(err)
+	}
+	klub := models.Klubovi{Naziv: "Pregled", PregledAkcijaVodica: true}
+	if err := db.Create(&klub).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db, klub
+}
+
+func seedDraft(t *testing.T, db *gorm.DB, klubID uint, author models.Korisnik) models.Akcija {
+	t.Helper()
+	return seedFinishAkcija(t, db, author, func(a *models.Akcija) {
+		a.KlubID = &klubID
+		a.StatusObjave = models.AkcijaStatusNacrt
+	})
+}
+
+func TestPublishAction_GuideGoesToReviewAndAdminApproves(t *testing.T) {
+	db, klub := testPublishDB(t)
+	admin := models.Korisnik{Username: "pub_admin", Password: "x", Role: "admin", KlubID: &klub.ID}
+	guide := models.Korisnik{Username: "pub_guide", Password: "x", Role: "vodic", KlubID: &klub.ID}
+	db.Create(&admin)
+	db.Create(&guide)
+	akcija := seedDraft(t, db, klub.ID, guide)
+
+	now := time.Now()
+	res, err := PublishAction(db, akcija.ID, PublishActionInput{PublisherRole: "vodic"}, guide.ID, now, allowCancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Akcija.StatusObjave != models.AkcijaStatusNaPregledu || len(res.ReviewerIDs) != 1 || res.ReviewerIDs[0] != admin.ID {
+		t.Fatalf("guide action must wait for club admin: %+v %v", res.Akcija.StatusObjave, res.ReviewerIDs)
+	}
+	if err := helpers.ValidateAkcijaPublished(res.Akcija); !errors.Is(err, helpers.ErrAkcijaNotPublished) {
+		t.Fatalf("signup on action in review must be rejected, got %v", err)
+	}
+
+	if _, err := ReviewAction(db, akcija.ID, false, " x ", admin.ID, now, allowCancel); !errors.Is(err, ErrReviewNoteInvalid) {
+		t.Fatalf("rejection without a note, got %v", err)
+	}
+	rejected, err := ReviewAction(db, akcija.ID, false, "Dopuni opremu", admin.ID, now, allowCancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Akcija.StatusObjave != models.AkcijaStatusNacrt || rejected.Akcija.PregledNapomena != "Dopuni opremu" || rejected.AuthorID != guide.ID {
+		t.Fatalf("rejected action must return to draft with note: %+v", rejected.Akcija)
+	}
+
+	if _, err := PublishAction(db, akcija.ID, PublishActionInput{PublisherRole: "vodic"}, guide.ID, now, allowCancel); err != nil {
+		t.Fatal(err)
+	}
+	approved, err := ReviewAction(db, akcija.ID, true, "", admin.ID, now, allowCancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := approved.Akcija
+	if a.StatusObjave != models.AkcijaStatusObjavljena || a.ObjavljenaAt == nil || a.PregledaoID == nil || *a.PregledaoID != admin.ID || a.PregledNapomena != "" {
+		t.Fatalf("approved action must be published: %+v", a)
+	}
+	if _, err := PublishAction(db, akcija.ID, PublishActionInput{PublisherRole: "admin"}, admin.ID, now, allowCancel); !errors.Is(err, ErrPublishAlreadyPublished) {
+		t.Fatalf("publishing twice, got %v", err)
+	}
+}
+
+func TestPublishAction_ScheduledThenPublishedOnce(t *testing.T) {
+	db, klub := testPublishDB(t)
+	admin := models.Korisnik{Username: "sched_admin", Password: "x", Role: "admin", KlubID: &klub.ID}
+	db.Create(&admin)
+	akcija := seedDraft(t, db, klub.ID, admin)
+
+	now := time.Now()
+	late := akcija.Datum.Add(time.Hour)
+	if _, err := PublishAction(db, akcija.ID, PublishActionInput{ObjaviAt: &late, PublisherRole: "admin"}, admin.ID, now, allowCancel); !errors.Is(err, ErrPublishAtInvalid) {
+		t.Fatalf("publish time after start must be rejected, got %v", err)
+	}
+	at := now.Add(time.Hour)
+	res, err := PublishAction(db, akcija.ID, PublishActionInput{ObjaviAt: &at, PublisherRole: "admin"}, admin.ID, now, allowCancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Akcija.StatusObjave != models.AkcijaStatusZakazana || res.Akcija.ObjaviAt == nil || len(res.ReviewerIDs) != 0 {
+		t.Fatalf("admin with future time must schedule: %+v", res.Akcija)
+	}
+
+	if due, err := PublishDueActions(db, now); err != nil || len(due) != 0 {
+		t.Fatalf("nothing due yet: %v %v", due, err)
+	}
+	due, err := PublishDueActions(db, at.Add(time.Minute))
+	if err != nil || len(due) != 1 || due[0].ID != akcija.ID {
+		t.Fatalf("scheduled action must be published: %v %v", due, err)
+	}
+	if again, _ := PublishDueActions(db, at.Add(2*time.Minute)); len(again) != 0 {
+		t.Fatalf("action must be published only once: %v", again)
+	}
+	var stored models.Akcija
+	db.First(&stored, akcija.ID)
+	if stored.StatusObjave != models.AkcijaStatusObjavljena || stored.ObjaviAt != nil || stored.ObjavljenaAt == nil {
+		t.Fatalf("stored state: %+v", stored)
+	}
+}
+
+func TestReturnActionToDraft_CancelsSchedule(t *testing.T) {
+	db, klub := testPublishDB(t)
+	admin := models.Korisnik{Username: "draft_admin", Password: "x", Role: "admin", KlubID: &klub.ID}
+	db.Create(&admin)
+	akcija := seedDraft(t, db, klub.ID, admin)
+	at := time.Now().Add(time.Hour)
+	if _, err := PublishAction(db, akcija.ID, PublishActionInput{ObjaviAt: &at, PublisherRole: "admin"}, admin.ID, time.Now(), allowCancel); err != nil {
+		t.Fatal(err)
+	}
+	out, err := ReturnActionToDraft(db, akcija.ID, allowCancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.StatusObjave != models.AkcijaStatusNacrt || out.ObjaviAt != nil {
+		t.Fatalf("schedule must be cleared: %+v", out)
+	}
+	if due, _ := PublishDueActions(db, at.Add(time.Minute)); len(due) != 0 {
+		t.Fatalf("draft must not be auto-published: %v", due)
+	}
+}
+
+func TestReturnActionToDraft_HidesActionFromSignedUpAndCoGuides(t *testing.T) {
+	db, klub := testPublishDB(t)
+	admin := models.Korisnik{Username: "hide_admin", Password: "x", Role: "admin", KlubID: &klub.ID}
+	member := models.Korisnik{Username: "hide_member", Password: "x", Role: "clan", KlubID: &klub.ID}
+	coGuide := models.Korisnik{Username: "hide_coguide", Password: "x", Role: "vodic", KlubID: &klub.ID}
+	db.Create(&admin)
+	db.Create(&member)
+	db.Create(&coGuide)
+	akcija := seedDraft(t, db, klub.ID, admin)
+	at := time.Now().Add(time.Hour)
+	if _, err := PublishAction(db, akcija.ID, PublishActionInput{ObjaviAt: &at, PublisherRole: "admin"}, admin.ID, time.Now(), allowCancel); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: member.ID, Status: "prijavljen"})
+	db.Create(&models.Prijava{AkcijaID: akcija.ID, KorisnikID: admin.ID, Status: "prijavljen"})
+	db.Create(&models.AkcijaVodic{AkcijaID: akcija.ID, KorisnikID: coGuide.ID, Uloga: models.AkcijaVodicUlogaPomocni})
+
+	if _, err := ReturnActionToDraft(db, akcija.ID, allowCancel); err != nil {
+		t.Fatal(err)
+	}
+	var tombstones []models.SyncTombstone
+	if err := db.Where("entitet = ? AND entitet_id = ?", "akcije", akcija.ID).Order("korisnik_id ASC").Find(&tombstones).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(tombstones) != 2 || *tombstones[0].KorisnikID != member.ID || *tombstones[1].KorisnikID != coGuide.ID {
+		t.Fatalf("draft must reach signed-up members and co-guides as deletion (not the author), got %+v", tombstones)
+	}
+}
@@ -0,0 +1,418 @@
+package actions
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRescheduleUnauthorized   = errors.New("Nemate pravo da pomerite ovu akciju")
+	ErrRescheduleReasonInvalid  = errors.New("Razlog pomeranja mora imati između 3 i 500 karaktera.")
+	ErrRescheduleInPast         = errors.New("Novi termin akcije mora biti u budućnosti.")
+	ErrRescheduleUnchanged      = errors.New("Novi termin je isti kao postojeći.")
+	ErrRescheduleEndBeforeStart = errors.New("Kraj akcije mora biti posle polaska.")
+	ErrRescheduleRokAfterDatum  = errors.New("Rok prijava ne može biti nakon datuma akcije")
+	ErrRescheduleDeadline       = errors.New("Rok za potvrdu mora biti u budućnosti i pre novog termina akcije.")
+	ErrNoReconfirmPending       = errors.New("Nemate prijavu koja čeka potvrdu novog termina.")
+	ErrReconfirmExpired         = errors.New("Rok za potvrdu novog termina je istekao.")
+)
+
+// RescheduleActionInput je novi termin akcije. Datum je kalendarski dan (UTC ponoć, kao Akcija.Datum).
+type RescheduleActionInput struct {
+	Datum      time.Time
+	StartAt    *time.Time
+	EndAt      *time.Time
+	RokPrijava *time.Time
+	PotvrdaDo  time.Time
+	Reason     string
+}
+
+// RescheduleActionResult je ishod pomeranja; RecipientUserIDs su snimljeni u TX po pravilima otkazivanja.
+type RescheduleActionResult struct {
+	Akcija           *models.Akcija
+	Pomeranje        *models.AkcijaPomeranje
+	RecipientUserIDs []uint
+}
+
+// normalizeRescheduleReason trimuje razlog i validira Unicode dužinu (3–500 runa).
+func normalizeRescheduleReason(reason string) (string, error) {
+	trimmed := strings.TrimSpace(reason)
+	n := utf8.RuneCountInString(trimmed)
+	if n < 3 || n > 500 {
+		return "", ErrRescheduleReasonInvalid
+	}
+	return trimmed, nil
+}
+
+// rescheduleStart je trenutak od kog se računa novi termin: polazak, a bez satnice početak dana akcije.
+func rescheduleStart(in RescheduleActionInput) time.Time {
+	if in.StartAt != nil {
+		return *in.StartAt
+	}
+	return in.Datum
+}
+
+// ValidateRescheduleInput proverava novi termin i rok za potvrdu u odnosu na now.
+func ValidateRescheduleInput(in RescheduleActionInput, now time.Time) error {
+	if _, err := normalizeRescheduleReason(in.Reason); err != nil {
+		return err
+	}
+	if in.Datum.IsZero() {
+		return ErrRescheduleInPast
+	}
+	if in.StartAt != nil {
+		if !in.StartAt.After(now) {
+			return ErrRescheduleInPast
+		}
+	} else if helpers.ValidateAkcijaSignupDeadline(&models.Akcija{Datum: in.Datum}, now) != nil {
+		return ErrRescheduleInPast
+	}
+	if in.EndAt != nil && (in.StartAt == nil || !in.EndAt.After(*in.StartAt)) {
+		return ErrRescheduleEndBeforeStart
+	}
+	if in.RokPrijava != nil && in.RokPrijava.After(in.Datum) {
+		return ErrRescheduleRokAfterDatum
+	}
+	if !in.PotvrdaDo.After(now) || !in.PotvrdaDo.Before(rescheduleStart(in)) {
+		return ErrRescheduleDeadline
+	}
+	return nil
+}
+
+func sameTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// RescheduleAction pomera termin aktivne akcije umesto otkazivanja.
+// Redoslijed: lock Akcija → authorize → already-cancelled → already-completed → recipient snapshot
+// → zapis istorije → novi termin → prijavljeni (osim vodiča i actora) dobijaju PotvrdaDo.
+// Prijave, pending signup zahtevi i invite linkovi ostaju; podsetnik i vremenska upozorenja se resetuju za novi termin.
+// Notifikacije se šalju van TX nakon uspješnog commita (handler).
+func RescheduleAction(
+	db *gorm.DB,
+	actionID uint,
+	in RescheduleActionInput,
+	actorID uint,
+	now time.Time,
+	authorize func(tx *gorm.DB, locked *models.Akcija) error,
+) (*RescheduleActionResult, error) {
+	if err := ValidateRescheduleInput(in, now); err != nil {
+		return nil, err
+	}
+	reason, _ := normalizeRescheduleReason(in.Reason)
+
+	var out models.Akcija
+	var pomeranje models.AkcijaPomeranje
+	var recipients []uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := helpers.LockAkcijaForUpdate(tx, actionID)
+		if err != nil {
+			return err
+		}
+		if authorize != nil {
+			if err := authorize(tx, locked); err != nil {
+				return err
+			}
+		}
+		if locked.IsCancelled {
+			return helpers.ErrAkcijaAlreadyCancelled
+		}
+		if locked.IsCompleted {
+			return helpers.ErrAkcijaAlreadyComplete
+		}
+		if locked.Datum.Equal(in.Datum) && sameTimePtr(locked.StartAt, in.StartAt) &&
+			sameTimePtr(locked.EndAt, in.EndAt) && sameTimePtr(locked.RokPrijava, in.RokPrijava) {
+			return ErrRescheduleUnchanged
+		}
+
+		before, err := helpers.LoadAkcijaSnapshotTx(tx, locked.ID)
+		if err != nil {
+			return err
+		}
+		ids, err := CollectCancelRecipientIDs(tx, locked.ID, actorID)
+		if err != nil {
+			return err
+		}
+		recipients = ids
+
+		// Vodič, ko-vodiči i onaj ko pomera akciju ne potvrđuju novi termin.
+		bezPotvrde := append(helpers.AkcijaGuideIDs(tx, locked), actorID)
+		res := tx.Model(&models.Prijava{}).
+			Where("akcija_id = ? AND status = ? AND korisnik_id NOT IN ?", locked.ID, helpers.PrijavaStatusPrijavljen, bezPotvrde).
+			Update("potvrda_do", in.PotvrdaDo)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		pomeranje = models.AkcijaPomeranje{
+			AkcijaID:        locked.ID,
+			StariDatum:      locked.Datum,
+			StariStartAt:    locked.StartAt,
+			StariEndAt:      locked.EndAt,
+			StariRokPrijava: locked.RokPrijava,
+			NoviDatum:       in.Datum,
+			NoviStartAt:     in.StartAt,
+			NoviEndAt:       in.EndAt,
+			NoviRokPrijava:  in.RokPrijava,
+			Razlog:          reason,
+			PotvrdaDo:       in.PotvrdaDo,
+			BrojZaPotvrdu:   int(res.RowsAffected),
+			PromenioID:      actorID,
+		}
+		if err := tx.Create(&pomeranje).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Akcija{}).Where("id = ?", locked.ID).Updates(map[string]any{
+			"datum":               in.Datum,
+			"start_at":            in.StartAt,
+			"end_at":              in.EndAt,
+			"rok_prijava":         in.RokPrijava,
+			"podsetnik_poslat_at": nil,
+			"vreme_upozorenja":    "",
+		}).Error; err != nil {
+			return err
+		}
+		// Istorija verzija; učesnici su već obavešteni kroz action_rescheduled, bez dodatnog action_changed.
+		if _, err := helpers.RecordAkcijaVerzijaTx(tx, locked.ID, before, models.AkcijaVerzijaIzvorPomeranje, actorID); err != nil {
+			return err
+		}
+		return tx.First(&out, locked.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RescheduleActionResult{
+		Akcija:           &out,
+		Pomeranje:        &pomeranje,
+		RecipientUserIDs: recipients,
+	}, nil
+}
+
+// ReconfirmPrijava potvrđuje dolazak na novi termin (briše PotvrdaDo) pre isteka roka.
+func ReconfirmPrijava(db *gorm.DB, actionID, korisnikID uint, now time.Time) (*models.Prijava, error) {
+	var out *models.Prijava
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := helpers.LockAkcijaForUpdate(tx, actionID)
+		if err != nil {
+			return err
+		}
+		if err := helpers.ValidateAkcijaActive(locked); err != nil {
+			return err
+		}
+		var probe models.Prijava
+		if err := tx.Select("id").Where("akcija_id = ? AND korisnik_id = ?", actionID, korisnikID).First(&probe).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoReconfirmPending
+			}
+			return err
+		}
+		prijava, err := helpers.LockPrijavaForUpdate(tx, probe.ID)
+		if err != nil {
+			return err
+		}
+		if prijava.Status != helpers.PrijavaStatusPrijavljen || prijava.PotvrdaDo == nil {
+			return ErrNoReconfirmPending
+		}
+		if !now.Before(*prijava.PotvrdaDo) {
+			return ErrReconfirmExpired
+		}
+		if err := tx.Model(prijava).Update("potvrda_do", nil).Error; err != nil {
+			return err
+		}
+		prijava.PotvrdaDo = nil
+		out = prijava
+		return nil
+	})
+	return out, err
+}
+
+// ReleasedPrijava je mesto oslobođeno zbog nepotvrđenog termina.
+type ReleasedPrijava struct {
+	AkcijaID   uint
+	KorisnikID uint
+}
+
+// ReleaseUnconfirmedResult — oslobođene prijave i zahtevi na čekanju koji su dobili mesto.
+type ReleaseUnconfirmedResult struct {
+	Released []ReleasedPrijava
+	Granted  []models.ActionSignupRequest
+}
+
+// ReleaseUnconfirmedPrijave oslobađa prijave kojima je istekao rok za potvrdu novog termina
+// (status → otkazano; Platio i izbori ostaju radi finansija) i daje mesta sledećima na čekanju.
+// Termin je promenio klub, pa plaćene prijave dobijaju obavezu povraćaja kao pri otkazivanju akcije.
+// Na otkazanoj ili završenoj akciji rok se samo briše. Notifikacije šalje pozivalac.
+func ReleaseUnconfirmedPrijave(db *gorm.DB, now time.Time) (*ReleaseUnconfirmedResult, error) {
+	var akcijaIDs []uint
+	if err := db.Model(&models.Prijava{}).
+		Where("potvrda_do IS NOT NULL AND potvrda_do <= ?", now).
+		Distinct().Pluck("akcija_id", &akcijaIDs).Error; err != nil {
+		return nil, err
+	}
+	out := &ReleaseUnconfirmedResult{}
+	for _, akcijaID := range akcijaIDs {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			locked, err := helpers.LockAkcijaForUpdate(tx, akcijaID)
+			if err != nil {
+				return err
+			}
+			expired := tx.Model(&models.Prijava{}).
+				Where("akcija_id = ? AND potvrda_do IS NOT NULL AND potvrda_do <= ?", akcijaID, now).
+				Session(&gorm.Session{})
+			var userIDs []uint
+			if !helpers.IsAkcijaTerminal(locked) {
+				var released []models.Prijava
+				if err := expired.Where("status = ?", helpers.PrijavaStatusPrijavljen).Order("id").Find(&released).Error; err != nil {
+					return err
+				}
+				if len(released) > 0 {
+					if err := expired.Where("status = ?", helpers.PrijavaStatusPrijavljen).Update("status", "otkazano").Error; err != nil {
+						return err
+					}
+				}
+				politika := helpers.EfektivnaPolitikaPovracaja(tx, *locked)
+				for _, p := range released {
+					if _, err := helpers.EvidentirajObavezuPovracajaTx(tx, *locked, p, politika, models.ObavezaPovracajaOtkazAkcije, now); err != nil {
+						return err
+					}
+					userIDs = append(userIDs, p.KorisnikID)
+				}
+			}
+			if err := expired.Update("potvrda_do", nil).Error; err != nil {
+				return err
+			}
+			if len(userIDs) == 0 {
+				return nil
+			}
+			granted, err := grantFreedSpotsTx(tx, locked, now)
+			if err != nil {
+				return err
+			}
+			for _, uid := range userIDs {
+				out.Released = append(out.Released, ReleasedPrijava{AkcijaID: akcijaID, KorisnikID: uid})
+			}
+			out.Granted = append(out.Granted, granted...)
+			return nil
+		}); err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// rescheduleGrantPolicy — mesto ide sledećem na čekanju samo dok je prijava još otvorena.
+var rescheduleGrantPolicy = helpers.ConfirmedPrijavaPolicy{
+	RequireActionActive:    true,
+	ValidateSignupDeadline: true,
+	CheckCapacity:          true,
+	RequireActiveUser:      true,
+	ReactivateCancelled:    true,
+}
+
+// grantFreedSpotsTx prihvata najstarije pending signup zahteve dok ima slobodnih mesta (samo akcije sa MaxLjudi).
+// Bez ograničenja kapaciteta niko ne čeka na mesto — zahtevi ostaju vodiču na odobravanje.
+// Izbori (smeštaj/prevoz/oprema, odgovori na formu, cena) se prenose iz zahteva kakvi su validirani pri slanju.
+func grantFreedSpotsTx(tx *gorm.DB, locked *models.Akcija, now time.Time) ([]models.ActionSignupRequest, error) {
+	if locked.MaxLjudi <= 0 {
+		return nil, nil
+	}
+	var waiting []models.ActionSignupRequest
+	if err := tx.Where("akcija_id = ? AND status = ?", locked.ID, models.ActionSignupRequestPending).
+		Order("created_at ASC, id ASC").Find(&waiting).Error; err != nil {
+		return nil, err
+	}
+	var granted []models.ActionSignupRequest
+	for i := range waiting {
+		req := waiting[i]
+		prijava, err := helpers.CreateConfirmedPrijavaTx(tx, locked.ID, req.RequesterID, now, rescheduleGrantPolicy)
+		if errors.Is(err, helpers.ErrKorisnikNotEligible) {
+			continue
+		}
+		if errors.Is(err, helpers.ErrAkcijaCapacityFull) || errors.Is(err, helpers.ErrSignupClosed) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		payload, err := signupRequestIzboriTx(tx, locked, req, now)
+		if err != nil {
+			return nil, err
+		}
+		izbor, err := helpers.EnsurePrijavaIzboriTx(tx, prijava.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Model(&izbor).Updates(map[string]any{
+			"selected_smestaj_ids":    payload.SelectedSmestajIDs,
+			"selected_prevoz_ids":     payload.SelectedPrevozIDs,
+			"selected_rent_items_raw": payload.SelectedRentItemsRaw,
+			"odgovori":                payload.Odgovori,
+			"forma_verzija":           payload.FormaVerzija,
+			"cena_obracun":            payload.CenaObracun,
+			"promo_kod_id":            payload.PromoKodID,
+			"grupa":                   payload.Grupa,
+		}).Error; err != nil {
+			return nil, err
+		}
+		respondedAt := now
+		if err := tx.Model(&models.ActionSignupRequest{}).Where("id = ?", req.ID).Updates(map[string]any{
+			"status":         models.ActionSignupRequestAccepted,
+			"responded_at":   respondedAt,
+			"reviewed_by_id": nil,
+		}).Error; err != nil {
+			return nil, err
+		}
+		req.Status = models.ActionSignupRequestAccepted
+		req.RespondedAt = &respondedAt
+		req.Akcija = *locked
+		granted = append(granted, req)
+	}
+	return granted, nil
+}
+
+// signupRequestIzboriTx prenosi sve izbore zahteva na prijavu: logistiku, odgovore na formu i cenu
+// dogovorenu pri slanju (promo kod, grupa). Zahtevi poslati pre cenovnika nemaju obračun — računa se sada, bez promo koda.
+func signupRequestIzboriTx(tx *gorm.DB, locked *models.Akcija, req models.ActionSignupRequest, now time.Time) (helpers.PrijavaIzboriPayload, error) {
+	payload := helpers.PrijavaIzboriPayload{
+		SelectedSmestajIDs:   nonEmptyJSON(req.SelectedSmestajIDs),
+		SelectedPrevozIDs:    nonEmptyJSON(req.SelectedPrevozIDs),
+		SelectedRentItemsRaw: nonEmptyJSON(req.SelectedRentItemsRaw),
+		Odgovori:             req.Odgovori,
+		FormaVerzija:         req.FormaVerzija,
+		CenaObracun:          req.CenaObracun,
+		PromoKodID:           req.PromoKodID,
+		Grupa:                req.Grupa,
+	}
+	if payload.CenaObracun != "" {
+		return payload, nil
+	}
+	var korisnik models.Korisnik
+	if err := tx.First(&korisnik, req.RequesterID).Error; err != nil {
+		return payload, err
+	}
+	obracun, _, err := helpers.ObracunajCenuTx(tx, *locked, korisnik, helpers.CenaOpcije{Grupa: req.Grupa, Vreme: now})
+	if err != nil {
+		return payload, err
+	}
+	payload.CenaObracun = helpers.EncodeCenaObracun(&obracun)
+	payload.Grupa = obracun.Grupa
+	return payload, nil
+}
+
+func nonEmptyJSON(raw string) string {
+	if strings.TrimSpace(raw) == "" {
+		return "[]"
+	}
+	return raw
+}
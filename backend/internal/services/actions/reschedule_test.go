@@ -0,0 +1,222 @@
+package actions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func testRescheduleDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := testFinishDB(t)
+	if err := db.AutoMigrate(&models.AkcijaPomeranje{}, &models.AkcijaVerzija{}, &models.AkcijaVodic{},
+		&models.AkcijaSmestaj{}, &models.AkcijaPrevoz{}, &models.AkcijaOprema{}, &models.AkcijaOpremaRent{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func rescheduleInput(now time.Time) RescheduleActionInput {
+	datum := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 14)
+	return RescheduleActionInput{Datum: datum, PotvrdaDo: now.Add(48 * time.Hour), Reason: "Loša prognoza"}
+}
+
+func reloadPrijava(t *testing.T, db *gorm.DB, id uint) models.Prijava {
+	t.Helper()
+	var p models.Prijava
+	if err := db.First(&p, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestRescheduleAction_KeepsPrijaveAndAsksForReconfirmation(t *testing.T) {
+	db := testRescheduleDB(t)
+	actor := seedFinishActor(t, db, "resched_actor")
+	rok := time.Now().Add(24 * time.Hour).UTC().Truncate(24 * time.Hour)
+	akcija := seedFinishAkcija(t, db, actor, func(a *models.Akcija) {
+		a.RokPrijava = &rok
+		a.VremeUpozorenja = "vetar"
+	})
+	guide := models.Prijava{AkcijaID: akcija.ID, KorisnikID: actor.ID, Status: "prijavljen"}
+	db.Create(&guide)
+	member := seedFinishPrijava(t, db, akcija.ID, "resched_member", "prijavljen", true)
+	coGuide := seedFinishPrijava(t, db, akcija.ID, "resched_coguide", "prijavljen", false)
+	db.Create(&models.AkcijaVodic{AkcijaID: akcija.ID, KorisnikID: coGuide.KorisnikID, Uloga: models.AkcijaVodicUlogaPomocni})
+	requester := models.Korisnik{Username: "resched_waiting", Password: "x", Role: "clan"}
+	db.Create(&requester)
+	seedFinishSignup(t, db, akcija.ID, requester.ID, models.ActionSignupRequestPending)
+
+	now := time.Now()
+	in := rescheduleInput(now)
+	in.RokPrijava = &in.Datum
+	res, err := RescheduleAction(db, akcija.ID, in, actor.ID, now, allowCancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Akcija.Datum.Equal(in.Datum) || res.Akcija.RokPrijava == nil || !res.Akcija.RokPrijava.Equal(in.Datum) ||
+		res.Akcija.VremeUpozorenja != "" || res.Akcija.IsCancelled {
+		t.Fatalf("akcija not moved: %+v", res.Akcija)
+	}
+	if len(res.RecipientUserIDs) != 3 || res.RecipientUserIDs[0] != member.KorisnikID || res.RecipientUserIDs[1] != coGuide.KorisnikID ||
+		res.RecipientUserIDs[2] != requester.ID {
+		t.Fatalf("recipients must follow cancel rules: %v", res.RecipientUserIDs)
+	}
+	if res.Pomeranje.BrojZaPotvrdu != 1 || !res.Pomeranje.StariDatum.Equal(akcija.Datum) || res.Pomeranje.Razlog != "Loša prognoza" {
+		t.Fatalf("history: %+v", res.Pomeranje)
+	}
+	if p := reloadPrijava(t, db, member.ID); p.Status != "prijavljen" || !p.Platio || p.PotvrdaDo == nil {
+		t.Fatalf("member prijava must stay and wait for reconfirmation: %+v", p)
+	}
+	if p := reloadPrijava(t, db, guide.ID); p.PotvrdaDo != nil {
+		t.Fatal("guide must not be asked to reconfirm")
+	}
+	if p := reloadPrijava(t, db, coGuide.ID); p.PotvrdaDo != nil {
+		t.Fatal("co-guide must not be asked to reconfirm")
+	}
+	var verzija models.AkcijaVerzija
+	if err := db.Where("akcija_id = ?", akcija.ID).First(&verzija).Error; err != nil ||
+		verzija.Izvor != models.AkcijaVerzijaIzvorPomeranje || !verzija.Materijalna {
+		t.Fatalf("reschedule must be recorded in version history: %+v %v", verzija, err)
+	}
+
+	if _, err := RescheduleAction(db, akcija.ID, in, actor.ID, now, allowCancel); !errors.Is(err, ErrRescheduleUnchanged) {
+		t.Fatalf("same term must be rejected, got %v", err)
+	}
+}
+
+func TestRescheduleAction_ValidatesInput(t *testing.T) {
+	now := time.Now()
+	past := rescheduleInput(now)
+	past.Datum = past.Datum.AddDate(0, 0, -30)
+	lateDeadline := rescheduleInput(now)
+	lateDeadline.PotvrdaDo = lateDeadline.Datum.Add(time.Hour)
+	shortReason := rescheduleInput(now)
+	shortReason.Reason = "ab"
+	cases := map[string]struct {
+		in   RescheduleActionInput
+		want error
+	}{
+		"past":         {past, ErrRescheduleInPast},
+		"deadline":     {lateDeadline, ErrRescheduleDeadline},
+		"reason":       {shortReason, ErrRescheduleReasonInvalid},
+		"endNoStartAt": {func() RescheduleActionInput { in := rescheduleInput(now); in.EndAt = &in.Datum; return in }(), ErrRescheduleEndBeforeStart},
+	}
+	for name, tc := range cases {
+		if err := ValidateRescheduleInput(tc.in, now); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v want %v", name, err, tc.want)
+		}
+	}
+}
+
+func TestReleaseUnconfirmedPrijave_GrantsSpotToNextWaiting(t *testing.T) {
+	db := testRescheduleDB(t)
+	actor := seedFinishActor(t, db, "release_actor")
+	akcija := seedFinishAkcija(t, db, actor, func(a *models.Akcija) { a.VodicID = 0; a.MaxLjudi = 2 })
+	confirmed := seedFinishPrijava(t, db, akcija.ID, "release_ok", "prijavljen", false)
+	silent := seedFinishPrijava(t, db, akcija.ID, "release_silent", "prijavljen", true)
+	first := models.Korisnik{Username: "release_first", Password: "x", Role: "clan"}
+	second := models.Korisnik{Username: "release_second", Password: "x", Role: "clan"}
+	db.Create(&first)
+	db.Create(&second)
+	firstReq := seedFinishSignup(t, db, akcija.ID, first.ID, models.ActionSignupRequestPending)
+	seedFinishSignup(t, db, akcija.ID, second.ID, models.ActionSignupRequestPending)
+
+	now := time.Now()
+	if _, err := RescheduleAction(db, akcija.ID, rescheduleInput(now), actor.ID, now, allowCancel); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReconfirmPrijava(db, akcija.ID, confirmed.KorisnikID, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReconfirmPrijava(db, akcija.ID, silent.KorisnikID, now.Add(49*time.Hour)); !errors.Is(err, ErrReconfirmExpired) {
+		t.Fatalf("late reconfirmation must fail, got %v", err)
+	}
+
+	if res, err := ReleaseUnconfirmedPrijave(db, now.Add(time.Hour)); err != nil || len(res.Released) != 0 {
+		t.Fatalf("nothing expires before the deadline: %+v %v", res, err)
+	}
+	res, err := ReleaseUnconfirmedPrijave(db, now.Add(49*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Released) != 1 || res.Released[0].KorisnikID != silent.KorisnikID {
+		t.Fatalf("released: %+v", res.Released)
+	}
+	if len(res.Granted) != 1 || res.Granted[0].ID != firstReq.ID {
+		t.Fatalf("only the oldest waiting request gets the single freed spot: %+v", res.Granted)
+	}
+	if p := reloadPrijava(t, db, silent.ID); p.Status != "otkazano" || !p.Platio || p.PotvrdaDo != nil {
+		t.Fatalf("silent prijava: %+v", p)
+	}
+	// Termin je promenio klub: plaćena oslobođena prijava dobija pun povraćaj kao pri otkazivanju akcije.
+	var obaveze []models.ObavezaPovracaja
+	db.Where("akcija_id = ?", akcija.ID).Find(&obaveze)
+	if len(obaveze) != 1 || obaveze[0].PrijavaID != silent.ID || obaveze[0].Razlog != models.ObavezaPovracajaOtkazAkcije ||
+		obaveze[0].Procenat != 100 || obaveze[0].Iznos != obaveze[0].Uplaceno {
+		t.Fatalf("refund obligation for the released paid prijava: %+v", obaveze)
+	}
+	if p := reloadPrijava(t, db, confirmed.ID); p.Status != "prijavljen" || p.PotvrdaDo != nil {
+		t.Fatalf("confirmed prijava: %+v", p)
+	}
+	var granted models.Prijava
+	if err := db.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, first.ID).First(&granted).Error; err != nil || granted.Status != "prijavljen" {
+		t.Fatalf("first waiting must get a prijava: %+v %v", granted, err)
+	}
+	var pending int64
+	db.Model(&models.ActionSignupRequest{}).Where("akcija_id = ? AND status = ?", akcija.ID, models.ActionSignupRequestPending).Count(&pending)
+	if pending != 1 {
+		t.Fatalf("second request must keep waiting, pending=%d", pending)
+	}
+
+	if res, err := ReleaseUnconfirmedPrijave(db, now.Add(50*time.Hour)); err != nil || len(res.Released) != 0 || len(res.Granted) != 0 {
+		t.Fatalf("second run must be a no-op: %+v %v", res, err)
+	}
+}
+
+func TestReleaseUnconfirmedPrijave_GrantCarriesAnswersAndPrice(t *testing.T) {
+	db := testRescheduleDB(t)
+	actor := seedFinishActor(t, db, "grant_actor")
+	akcija := seedFinishAkcija(t, db, actor, func(a *models.Akcija) { a.VodicID = 0; a.MaxLjudi = 1 })
+	silent := seedFinishPrijava(t, db, akcija.ID, "grant_silent", "prijavljen", false)
+	waiting := models.Korisnik{Username: "grant_waiting", Password: "x", Role: "clan"}
+	db.Create(&waiting)
+	req := seedFinishSignup(t, db, akcija.ID, waiting.ID, models.ActionSignupRequestPending)
+	promoID := uint(7)
+	cena := helpers.CenaObracun{Kategorija: "clan", Redovna: 3000, Osnovna: 3000, PromoKod: "PROLECE", PromoPopust: 500, Grupa: "drustvo", GrupniPopust: 250, Ukupno: 2250}
+	db.Model(&req).Updates(map[string]any{
+		"odgovori":      `{"1":"L"}`,
+		"forma_verzija": 3,
+		"cena_obracun":  helpers.EncodeCenaObracun(&cena),
+		"promo_kod_id":  promoID,
+		"grupa":         "drustvo",
+	})
+
+	now := time.Now()
+	if _, err := RescheduleAction(db, akcija.ID, rescheduleInput(now), actor.ID, now, allowCancel); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ReleaseUnconfirmedPrijave(db, now.Add(49*time.Hour))
+	if err != nil || len(res.Released) != 1 || res.Released[0].KorisnikID != silent.KorisnikID || len(res.Granted) != 1 {
+		t.Fatalf("release: %+v %v", res, err)
+	}
+	var granted models.Prijava
+	if err := db.Where("akcija_id = ? AND korisnik_id = ?", akcija.ID, waiting.ID).First(&granted).Error; err != nil {
+		t.Fatal(err)
+	}
+	var izbor models.PrijavaIzbori
+	if err := db.Where("prijava_id = ?", granted.ID).First(&izbor).Error; err != nil {
+		t.Fatal(err)
+	}
+	if izbor.Odgovori != `{"1":"L"}` || izbor.FormaVerzija != 3 || izbor.Grupa != "drustvo" || izbor.PromoKodID == nil || *izbor.PromoKodID != promoID {
+		t.Fatalf("granted izbori lost request data: %+v", izbor)
+	}
+	if o := helpers.ParseCenaObracun(izbor.CenaObracun); o == nil || o.Ukupno != 2250 || o.PromoPopust != 500 {
+		t.Fatalf("granted prijava must keep the discounted price: %+v", o)
+	}
+}
@@ -0,0 +1,96 @@
+package actions
+
+import (
+	"encoding/json"
+	"errors"
+
+	"beleg-app/backend/internal/helpers"
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrSignupFormUnauthorized = errors.New("Nemate pravo da menjate formu prijave ove akcije")
+
+// SignupFormResult je forma posle čuvanja. Forma je nil kada akcija više nema pitanja;
+// NepotpuniKorisnici su prijavljeni bez odgovora na neko obavezno pitanje, a NoviNepotpuni oni kojima je
+// nedostajući odgovor nastao ovom izmenom (njih handler obaveštava posle commita).
+type SignupFormResult struct {
+	Forma              *models.AkcijaForma
+	Polja              []helpers.FormaPolje
+	Promenjena         bool
+	NepotpuniKorisnici []uint
+	NoviNepotpuni      []uint
+}
+
+// SaveSignupForm zamenjuje pitanja forme prijave (helpers.MergeFormaPolja). Svaka promena podiže verziju forme;
+// odgovori ostaju vezani za id pitanja, a pitanja sa odgovorima se pri uklanjanju arhiviraju.
+func SaveSignupForm(
+	db *gorm.DB,
+	actionID uint,
+	polja []helpers.FormaPolje,
+	actorID uint,
+	authorize func(tx *gorm.DB, akcija *models.Akcija) error,
+) (*SignupFormResult, error) {
+	out := &SignupFormResult{Polja: []helpers.FormaPolje{}}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockCoGuideAkcijaTx(tx, actionID, authorize)
+		if err != nil {
+			return err
+		}
+		forma, stara := helpers.LoadAkcijaForma(tx, locked.ID)
+		merged, err := helpers.MergeFormaPolja(stara, polja, helpers.AkcijaFormaOdgovorenaPolja(tx, locked.ID))
+		if err != nil {
+			return err
+		}
+		if len(merged) == 0 {
+			if forma == nil {
+				return nil
+			}
+			if err := tx.Delete(forma).Error; err != nil {
+				return err
+			}
+			out.Promenjena = true
+			return touchAkcijaTx(tx, locked.ID)
+		}
+		raw, err := json.Marshal(merged)
+		if err != nil {
+			return err
+		}
+		out.Polja = merged
+		if forma != nil && forma.Polja == string(raw) {
+			out.Forma = forma
+			return nil
+		}
+		if forma == nil {
+			forma = &models.AkcijaForma{AkcijaID: locked.ID, Polja: string(raw), Verzija: 1, IzmenioID: actorID}
+			if err := tx.Create(forma).Error; err != nil {
+				return err
+			}
+		} else {
+			forma.Polja = string(raw)
+			forma.Verzija++
+			forma.IzmenioID = actorID
+			if err := tx.Save(forma).Error; err != nil {
+				return err
+			}
+		}
+		out.Forma = forma
+		out.Promenjena = true
+		pre := map[uint]bool{}
+		for _, id := range helpers.AkcijaFormaNepotpuniKorisnici(tx, locked.ID, stara) {
+			pre[id] = true
+		}
+		out.NepotpuniKorisnici = helpers.AkcijaFormaNepotpuniKorisnici(tx, locked.ID, merged)
+		for _, id := range out.NepotpuniKorisnici {
+			if !pre[id] {
+				out.NoviNepotpuni = append(out.NoviNepotpuni, id)
+			}
+		}
+		return touchAkcijaTx(tx, locked.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -10,25 +10,32 @@ import (
 	"gorm.io/gorm"
 )
 
+// serbianLatin radi nad malim slovima: ćirilica → latinica, pa latinica bez dijakritika.
+// Isto preslikavanje ima SQL funkcija beleg_fold (pretraga); menjati ih zajedno.
 var serbianLatin = strings.NewReplacer(
-	"Č", "c", "č", "c",
-	"Ć", "c", "ć", "c",
-	"Š", "s", "š", "s",
-	"Ž", "z", "ž", "z",
-	"Đ", "dj", "đ", "dj",
-	"Dž", "dz", "dž", "dz",
+	"č", "c", "ć", "c", "š", "s", "ž", "z", "đ", "dj",
+	"а", "a", "б", "b", "в", "v", "г", "g", "д", "d", "ђ", "dj", "е", "e", "ж", "z",
+	"з", "z", "и", "i", "ј", "j", "к", "k", "л", "l", "љ", "lj", "м", "m", "н", "n",
+	"њ", "nj", "о", "o", "п", "p", "р", "r", "с", "s", "т", "t", "ћ", "c", "у", "u",
+	"ф", "f", "х", "h", "ц", "c", "ч", "c", "џ", "dz", "ш", "s",
 )
 
+// FoldLatin vraća tekst malim slovima, ćirilicu preslovljenu u latinicu i bez srpskih dijakritika
+// („Миџор“, „Midžor“ i „midzor“ daju isto).
+func FoldLatin(s string) string {
+	return serbianLatin.Replace(strings.ToLower(s))
+}
+
 // FromName pravi URL-deo iz naslova (mala slova, latinica, crtice).
 func FromName(name string) string {
 	s := strings.TrimSpace(name)
 	if s == "" {
 		return ""
 	}
-	s = serbianLatin.Replace(s)
+	s = FoldLatin(s)
 	var b strings.Builder
 	prevHyphen := false
-	for _, r := range strings.ToLower(s) {
+	for _, r := range s {
 		if unicode.IsLetter(r) && r < unicode.MaxASCII {
 			if r >= 'a' && r <= 'z' {
 				b.WriteRune(r)
@@ -130,3 +137,29 @@ func UniqueFerrataSlug(db *gorm.DB, naziv string, excludeID uint) (string, error
 	}
 	return "", fmt.Errorf("slug: previše kolizija za bazu %q", base)
 }
+
+// UniquePeakListSlug vraća jedinstven slug za peak_lists tabelu; excludeID 0 = novi zapis.
+func UniquePeakListSlug(db *gorm.DB, naziv string, excludeID uint) (string, error) {
+	base := FromName(naziv)
+	if base == "" {
+		base = "lista-vrhova"
+	}
+	for n := 0; n < 1000; n++ {
+		candidate := base
+		if n > 0 {
+			candidate = fmt.Sprintf("%s-%d", base, n+1)
+		}
+		var count int64
+		q := db.Model(&models.PeakList{}).Where("slug = ?", candidate)
+		if excludeID > 0 {
+			q = q.Where("id <> ?", excludeID)
+		}
+		if err := q.Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("slug: previše kolizija za bazu %q", base)
+}
@@ -0,0 +1,91 @@
+// Package sun računa izlazak i zalazak Sunca i građanski sumrak za tačku i datum, potpuno offline
+// (NOAA / jednačina izlaska Sunca).
+// Tačnost je oko minut za umerene geografske širine, dovoljno za planiranje tura.
+package sun
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// sunriseAltitudeDeg — gornja ivica Sunca na horizontu, sa atmosferskom refrakcijom.
+	sunriseAltitudeDeg = -0.833
+	// civilAltitudeDeg — građanski sumrak: centar Sunca 6° ispod horizonta; posle toga je bez lampe mrak.
+	civilAltitudeDeg = -6.0
+	obliquityDeg     = 23.4397
+	j2000            = 2451545.0
+	unixEpochJD      = 2440587.5
+)
+
+// Day su vremena za jedan kalendarski dan (UTC). Nil vrednost znači da događaja tog dana nema
+// (polarni dan ili polarna noć).
+type Day struct {
+	CivilDawn   *time.Time `json:"gradjanskaZora,omitempty"`
+	Sunrise     *time.Time `json:"izlazak,omitempty"`
+	Sunset      *time.Time `json:"zalazak,omitempty"`
+	CivilDusk   *time.Time `json:"gradjanskiSumrak,omitempty"`
+	DaylightMin int        `json:"dnevnoSvetloMin"` // od izlaska do zalaska
+	PolarDay    bool       `json:"polarniDan,omitempty"`
+	PolarNight  bool       `json:"polarnaNoc,omitempty"`
+}
+
+// Times vraća izlazak, zalazak i građanski sumrak za kalendarski datum date (godina/mesec/dan u date.Location()).
+func Times(date time.Time, lat, lng float64) Day {
+	rise, set := eventPair(date, lat, lng, sunriseAltitudeDeg)
+	dawn, dusk := eventPair(date, lat, lng, civilAltitudeDeg)
+	day := Day{CivilDawn: dawn, Sunrise: rise, Sunset: set, CivilDusk: dusk}
+	switch {
+	case rise != nil:
+		day.DaylightMin = int(math.Round(set.Sub(*rise).Minutes()))
+	case noonAltitudeAbove(date, lat, lng, sunriseAltitudeDeg):
+		day.PolarDay, day.DaylightMin = true, 24*60
+	default:
+		day.PolarNight = true
+	}
+	return day
+}
+
+// eventPair vraća trenutke kada je centar Sunca na visini altitudeDeg ujutru i uveče.
+func eventPair(date time.Time, lat, lng, altitudeDeg float64) (*time.Time, *time.Time) {
+	transit, cosHour := solarDay(date, lat, lng, altitudeDeg)
+	if cosHour < -1 || cosHour > 1 {
+		return nil, nil
+	}
+	hourFrac := math.Acos(cosHour) / (2 * math.Pi)
+	rise := fromJulian(transit - hourFrac)
+	set := fromJulian(transit + hourFrac)
+	return &rise, &set
+}
+
+// noonAltitudeAbove: bez izlaska/zalaska Sunce je ceo dan iznad (cos < -1) ili ispod (cos > 1) date visine.
+func noonAltitudeAbove(date time.Time, lat, lng, altitudeDeg float64) bool {
+	_, cosHour := solarDay(date, lat, lng, altitudeDeg)
+	return cosHour < -1
+}
+
+// solarDay vraća julijanski trenutak prolaska Sunca kroz meridijan i kosinus satnog ugla za visinu altitudeDeg.
+func solarDay(date time.Time, lat, lng, altitudeDeg float64) (float64, float64) {
+	y, m, d := date.Date()
+	noonUTC := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+	n := math.Round(float64(noonUTC.Unix())/86400 + unixEpochJD - j2000)
+
+	meanSolarDay := n - lng/360
+	anomaly := math.Mod(357.5291+0.98560028*meanSolarDay, 360)
+	mRad := rad(anomaly)
+	center := 1.9148*math.Sin(mRad) + 0.02*math.Sin(2*mRad) + 0.0003*math.Sin(3*mRad)
+	eclipticLng := math.Mod(anomaly+center+180+102.9372, 360)
+	transit := j2000 + meanSolarDay + 0.0053*math.Sin(mRad) - 0.0069*math.Sin(2*rad(eclipticLng))
+
+	sinDecl := math.Sin(rad(eclipticLng)) * math.Sin(rad(obliquityDeg))
+	cosDecl := math.Cos(math.Asin(sinDecl))
+	cosHour := (math.Sin(rad(altitudeDeg)) - math.Sin(rad(lat))*sinDecl) / (math.Cos(rad(lat)) * cosDecl)
+	return transit, cosHour
+}
+
+func rad(deg float64) float64 { return deg * math.Pi / 180 }
+
+func fromJulian(jd float64) time.Time {
+	sec := (jd - unixEpochJD) * 86400
+	return time.Unix(0, int64(sec*1e9)).UTC().Truncate(time.Second)
+}
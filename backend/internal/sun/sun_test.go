@@ -0,0 +1,51 @@
+package sun
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimes_Belgrade(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Belgrade")
+	if err != nil {
+		t.Skip("tzdata nije dostupan")
+	}
+	// Beograd, 21. jun 2026: izlazak ~04:51, zalazak ~20:28 (CEST).
+	day := Times(time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), 44.8176, 20.4633)
+	if day.Sunrise == nil || day.Sunset == nil {
+		t.Fatal("expected sunrise and sunset")
+	}
+	wantRise := time.Date(2026, 6, 21, 4, 51, 0, 0, loc)
+	wantSet := time.Date(2026, 6, 21, 20, 28, 0, 0, loc)
+	if d := day.Sunrise.Sub(wantRise); d < -3*time.Minute || d > 3*time.Minute {
+		t.Fatalf("sunrise %v, want ~%v", day.Sunrise.In(loc), wantRise)
+	}
+	if d := day.Sunset.Sub(wantSet); d < -3*time.Minute || d > 3*time.Minute {
+		t.Fatalf("sunset %v, want ~%v", day.Sunset.In(loc), wantSet)
+	}
+	// Građanski sumrak leti traje ~35 min; dan ~15 h 37 min.
+	if day.CivilDusk == nil || day.CivilDawn == nil {
+		t.Fatal("expected civil twilight")
+	}
+	if d := day.CivilDusk.Sub(*day.Sunset); d < 30*time.Minute || d > 40*time.Minute {
+		t.Fatalf("civil dusk %v after sunset, want ~35 min", d)
+	}
+	if day.CivilDawn.After(*day.Sunrise) || day.DaylightMin < 930 || day.DaylightMin > 945 {
+		t.Fatalf("unexpected dawn/daylight: %+v", day)
+	}
+}
+
+func TestTimes_PolarNight(t *testing.T) {
+	// Tromse, 21. decembar: Sunce ne izlazi.
+	day := Times(time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC), 69.65, 18.96)
+	if day.Sunrise != nil || day.Sunset != nil || !day.PolarNight || day.DaylightMin != 0 {
+		t.Fatalf("polar night must have no sunrise/sunset, got %+v", day)
+	}
+	// Iako Sunce ne izlazi, građanski sumrak u podne postoji.
+	if day.CivilDawn == nil {
+		t.Fatal("Tromsø still has civil twilight around noon in December")
+	}
+	if summer := Times(time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), 69.65, 18.96); !summer.PolarDay || summer.DaylightMin != 24*60 {
+		t.Fatalf("midnight sun expected, got %+v", summer)
+	}
+}
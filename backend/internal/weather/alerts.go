@@ -0,0 +1,90 @@
+package weather
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	RizikVetar      = "vetar"
+	RizikPadavine   = "padavine"
+	RizikGrmljavina = "grmljavina"
+)
+
+// Thresholds su pragovi za upozorenje na grebenu/vrhu.
+type Thresholds struct {
+	GustKmh        float64 // udari vetra
+	WindKmh        float64 // srednji vetar
+	PrecipMmH      float64 // padavine po satu
+	PrecipProbPct  float64 // verovatnoća padavina …
+	PrecipProbMinM float64 // … uz bar ovoliko mm u tom satu
+	CapeJkg        float64 // nestabilnost koja uz padavine znači rizik od grmljavine
+}
+
+// DefaultThresholds — udari ≥ 60 km/h otežavaju kretanje po grebenu, ≥ 3 mm/h je jaka kiša na stazi.
+var DefaultThresholds = Thresholds{
+	GustKmh:        60,
+	WindKmh:        40,
+	PrecipMmH:      3,
+	PrecipProbPct:  70,
+	PrecipProbMinM: 1,
+	CapeJkg:        1000,
+}
+
+// Alert je najgori sat za jednu vrstu rizika u posmatranom intervalu.
+type Alert struct {
+	Tip      string    `json:"tip"`
+	Vreme    time.Time `json:"vreme"`
+	Vrednost float64   `json:"vrednost"`
+	Poruka   string    `json:"poruka"`
+}
+
+// isThunderstorm: WMO 95–99 (grmljavina, sa gradom) ili visoka nestabilnost uz verovatne padavine.
+func isThunderstorm(h Hour) bool {
+	return (h.WeatherCode >= 95 && h.WeatherCode <= 99) ||
+		(h.CapeJkg >= DefaultThresholds.CapeJkg && h.PrecipProb >= 40)
+}
+
+// Assess vraća upozorenja (najviše jedno po vrsti rizika, sortirana po tipu) za date sate.
+func Assess(hours []Hour, th Thresholds) []Alert {
+	worst := map[string]Alert{}
+	consider := func(tip string, h Hour, v float64, msg string) {
+		if cur, ok := worst[tip]; !ok || v > cur.Vrednost {
+			worst[tip] = Alert{Tip: tip, Vreme: h.Time, Vrednost: v, Poruka: msg}
+		}
+	}
+	for _, h := range hours {
+		if h.GustKmh >= th.GustKmh || h.WindKmh >= th.WindKmh {
+			consider(RizikVetar, h, h.GustKmh, fmt.Sprintf("udari vetra do %.0f km/h", h.GustKmh))
+		}
+		if heavyPrecip(h, th) {
+			consider(RizikPadavine, h, h.PrecipMm, fmt.Sprintf("padavine %.1f mm/h (verovatnoća %.0f %%)", h.PrecipMm, h.PrecipProb))
+		}
+		if h.Thunderstorm || (h.CapeJkg >= th.CapeJkg && h.PrecipProb >= 40) {
+			consider(RizikGrmljavina, h, h.CapeJkg, "rizik od grmljavine")
+		}
+	}
+	out := make([]Alert, 0, len(worst))
+	for _, a := range worst {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Tip < out[j].Tip })
+	return out
+}
+
+// heavyPrecip: jaka kiša u satu ili vrlo verovatne padavine.
+func heavyPrecip(h Hour, th Thresholds) bool {
+	return h.PrecipMm >= th.PrecipMmH || (h.PrecipProb >= th.PrecipProbPct && h.PrecipMm >= th.PrecipProbMinM)
+}
+
+// AlertKinds vraća sortirane tipove upozorenja spojene zarezom (za pamćenje već poslatih).
+func AlertKinds(alerts []Alert) string {
+	kinds := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		kinds = append(kinds, a.Tip)
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, ",")
+}
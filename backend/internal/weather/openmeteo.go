@@ -0,0 +1,105 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenMeteoURL = "https://api.open-meteo.com"
+	openMeteoHourly     = "temperature_2m,precipitation,precipitation_probability,wind_speed_10m,wind_gusts_10m,weather_code,cape"
+	// openMeteoForecastDays — akcije se objavljuju nedeljama unapred; Open-Meteo daje najviše 16 dana.
+	openMeteoForecastDays = 10
+)
+
+// OpenMeteo — HTTP klijent za Open-Meteo /v1/forecast (ili kompatibilan stub na BaseURL).
+type OpenMeteo struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewOpenMeteo(baseURL string) *OpenMeteo {
+	if baseURL == "" {
+		baseURL = defaultOpenMeteoURL
+	}
+	return &OpenMeteo{BaseURL: strings.TrimRight(baseURL, "/"), Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (o *OpenMeteo) Name() string { return IzvorOpenMeteo }
+
+type openMeteoResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Elevation float64 `json:"elevation"`
+	Hourly    struct {
+		Time        []int64    `json:"time"`
+		Temperature []*float64 `json:"temperature_2m"`
+		Precip      []*float64 `json:"precipitation"`
+		PrecipProb  []*float64 `json:"precipitation_probability"`
+		Wind        []*float64 `json:"wind_speed_10m"`
+		Gusts       []*float64 `json:"wind_gusts_10m"`
+		Code        []*float64 `json:"weather_code"`
+		Cape        []*float64 `json:"cape"`
+	} `json:"hourly"`
+}
+
+func (o *OpenMeteo) Forecast(ctx context.Context, lat, lng float64, elevationM *float64) (*Forecast, error) {
+	params := url.Values{
+		"latitude":      {strconv.FormatFloat(lat, 'f', 4, 64)},
+		"longitude":     {strconv.FormatFloat(lng, 'f', 4, 64)},
+		"hourly":        {openMeteoHourly},
+		"timeformat":    {"unixtime"},
+		"timezone":      {"UTC"},
+		"forecast_days": {strconv.Itoa(openMeteoForecastDays)},
+	}
+	if elevationM != nil {
+		// Open-Meteo preračunava temperaturu na zadatu visinu (vrh umesto visine ćelije modela).
+		params.Set("elevation", strconv.FormatFloat(*elevationM, 'f', 0, 64))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.BaseURL+"/v1/forecast?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: open-meteo status %d", ErrUnavailable, resp.StatusCode)
+	}
+	var body openMeteoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: open-meteo odgovor: %v", ErrUnavailable, err)
+	}
+	h := body.Hourly
+	at := func(v []*float64, i int) float64 {
+		if i < len(v) && v[i] != nil {
+			return *v[i]
+		}
+		return 0
+	}
+	f := &Forecast{Lat: body.Latitude, Lng: body.Longitude, ElevationM: body.Elevation, Izvor: IzvorOpenMeteo,
+		Hours: make([]Hour, 0, len(h.Time))}
+	for i, ts := range h.Time {
+		hour := Hour{
+			Time:        time.Unix(ts, 0).UTC(),
+			TempC:       at(h.Temperature, i),
+			WindKmh:     at(h.Wind, i),
+			GustKmh:     at(h.Gusts, i),
+			PrecipMm:    at(h.Precip, i),
+			PrecipProb:  at(h.PrecipProb, i),
+			CapeJkg:     at(h.Cape, i),
+			WeatherCode: int(at(h.Code, i)),
+		}
+		hour.Thunderstorm = isThunderstorm(hour)
+		f.Hours = append(f.Hours, hour)
+	}
+	return f, nil
+}
@@ -0,0 +1,132 @@
+// Package weather daje satnu vremensku prognozu za tačku (planina/vrh) kroz zamenljiv provajder.
+// Podrazumevani provajder je Open-Meteo kompatibilan HTTP API (WEATHER_URL može da pokazuje na lokalni stub).
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	IzvorOpenMeteo = "open-meteo"
+
+	// cacheTTL — prognoza se osvežava najviše jednom na sat po tački (ključ sadrži i sat).
+	cacheTTL = time.Hour
+)
+
+var ErrUnavailable = errors.New("servis vremenske prognoze trenutno nije dostupan")
+
+// Hour je prognoza za jedan sat (UTC).
+type Hour struct {
+	Time         time.Time `json:"vreme"`
+	TempC        float64   `json:"temperaturaC"`
+	WindKmh      float64   `json:"vetarKmh"`
+	GustKmh      float64   `json:"udariVetraKmh"`
+	PrecipMm     float64   `json:"padavineMm"`
+	PrecipProb   float64   `json:"verovatnocaPadavina"` // %
+	CapeJkg      float64   `json:"cape"`                // konvektivna energija — pokazatelj nestabilnosti (grmljavine)
+	WeatherCode  int       `json:"kod"`                 // WMO kod vremena
+	Thunderstorm bool      `json:"grmljavina"`
+}
+
+// Forecast je satna prognoza za tačku; ElevationM je visina za koju je prognoza preračunata.
+type Forecast struct {
+	Lat        float64 `json:"lat"`
+	Lng        float64 `json:"lng"`
+	ElevationM float64 `json:"visinaM"`
+	Izvor      string  `json:"izvor"`
+	Hours      []Hour  `json:"sati"`
+}
+
+// Provider je izvor prognoze; elevationM (nil = visina terena provajdera) služi za prognozu na vrhu.
+type Provider interface {
+	Name() string
+	Forecast(ctx context.Context, lat, lng float64, elevationM *float64) (*Forecast, error)
+}
+
+// Window vraća sate prognoze u intervalu [from, to).
+func (f *Forecast) Window(from, to time.Time) []Hour {
+	if f == nil {
+		return nil
+	}
+	var out []Hour
+	for _, h := range f.Hours {
+		if !h.Time.Before(from.Add(-59*time.Minute)) && h.Time.Before(to) {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// CachedProvider čuva prognoze u tabeli weather_cache po tački (≈1 km), visini i satu.
+type CachedProvider struct {
+	Inner Provider
+	DB    *gorm.DB
+	Now   func() time.Time
+}
+
+func (p CachedProvider) Name() string { return p.Inner.Name() }
+
+func (p CachedProvider) Forecast(ctx context.Context, lat, lng float64, elevationM *float64) (*Forecast, error) {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	hour := now().UTC().Truncate(time.Hour)
+	elev := "-"
+	if elevationM != nil {
+		elev = fmt.Sprintf("%.0f", math.Round(*elevationM/50)*50)
+	}
+	// 2 decimale ≈ 1 km: susedne akcije na istoj planini dele prognozu.
+	key := fmt.Sprintf("%s|%.2f,%.2f|%s|%s", p.Inner.Name(), lat, lng, elev, hour.Format("2006010215"))
+	var rows []models.WeatherCache
+	if err := p.DB.Where("kljuc = ? AND expires_at > ?", key, now().UTC()).Limit(1).Find(&rows).Error; err == nil && len(rows) == 1 {
+		var f Forecast
+		if json.Unmarshal([]byte(rows[0].ForecastJSON), &f) == nil {
+			return &f, nil
+		}
+	}
+	f, err := p.Inner.Forecast(ctx, lat, lng, elevationM)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := json.Marshal(f)
+	entry := models.WeatherCache{Kljuc: key, ForecastJSON: string(raw), ExpiresAt: hour.Add(cacheTTL), CreatedAt: now().UTC()}
+	if err := p.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kljuc"}},
+		DoUpdates: clause.AssignmentColumns([]string{"forecast_json", "expires_at", "created_at"}),
+	}).Create(&entry).Error; err != nil {
+		log.Printf("weather: cache write %q: %v", key, err)
+	}
+	return f, nil
+}
+
+// PruneCache briše istekle prognoze.
+func PruneCache(db *gorm.DB, now time.Time) (int64, error) {
+	res := db.Where("expires_at <= ?", now.UTC()).Delete(&models.WeatherCache{})
+	return res.RowsAffected, res.Error
+}
+
+// ProviderFromEnv bira provajdera:
+//
+//   - WEATHER_PROVIDER=open-meteo (podrazumevano) | off (bez prognoze)
+//   - WEATHER_URL — Open-Meteo kompatibilan server (podrazumevano https://api.open-meteo.com; lokalni stub u testu/stagingu)
+func ProviderFromEnv() Provider {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("WEATHER_PROVIDER"))) {
+	case "off", "none":
+		return nil
+	}
+	return NewOpenMeteo(strings.TrimSpace(os.Getenv("WEATHER_URL")))
+}
@@ -0,0 +1,84 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// stubOpenMeteo odgovara kao Open-Meteo /v1/forecast sa tri sata: mirno, jak vetar, grmljavina.
+func stubOpenMeteo(t *testing.T, start time.Time, hits *int, gotElevation *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*hits++
+		if r.URL.Path != "/v1/forecast" || r.URL.Query().Get("timeformat") != "unixtime" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		*gotElevation = r.URL.Query().Get("elevation")
+		ts := start.Unix()
+		fmt.Fprintf(w, `{"latitude":43.3,"longitude":22.1,"elevation":1810,"hourly":{
+			"time":[%d,%d,%d],
+			"temperature_2m":[2.5,1.0,null],
+			"precipitation":[0,0.2,6.5],
+			"precipitation_probability":[10,30,90],
+			"wind_speed_10m":[12,45,20],
+			"wind_gusts_10m":[20,75,40],
+			"weather_code":[1,3,95],
+			"cape":[0,100,1400]}}`, ts, ts+3600, ts+7200)
+	}))
+}
+
+func TestOpenMeteo_CachedPerHourAndAssessed(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "weather_cache")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.WeatherCache{}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 12, 12, 9, 0, 0, 0, time.UTC)
+	hits := 0
+	var elevation string
+	srv := stubOpenMeteo(t, start, &hits, &elevation)
+	defer srv.Close()
+
+	now := start.Add(-20 * time.Hour)
+	p := CachedProvider{Inner: NewOpenMeteo(srv.URL), DB: db, Now: func() time.Time { return now }}
+	summit := 1810.0
+	f, err := p.Forecast(context.Background(), 43.3012, 22.1004, &summit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Hours) != 3 || f.Hours[0].Time != start || f.Hours[2].TempC != 0 || !f.Hours[2].Thunderstorm || elevation != "1810" {
+		t.Fatalf("unexpected forecast: %+v (elevation=%q)", f, elevation)
+	}
+	// Ista tačka (≈1 km) u istom satu dolazi iz keša; sledeći sat ponovo pita provajdera.
+	if _, err := p.Forecast(context.Background(), 43.3049, 22.0951, &summit); err != nil || hits != 1 {
+		t.Fatalf("same hour must be cached, hits=%d err=%v", hits, err)
+	}
+	now = now.Add(time.Hour)
+	if _, err := p.Forecast(context.Background(), 43.3012, 22.1004, &summit); err != nil || hits != 2 {
+		t.Fatalf("next hour must refresh, hits=%d err=%v", hits, err)
+	}
+	if n, err := PruneCache(db, now); err != nil || n != 1 {
+		t.Fatalf("expired entry must be pruned, got %d %v", n, err)
+	}
+
+	alerts := Assess(f.Window(start, start.Add(3*time.Hour)), DefaultThresholds)
+	if AlertKinds(alerts) != "grmljavina,padavine,vetar" {
+		t.Fatalf("unexpected alerts: %+v", alerts)
+	}
+	if calm := Assess(f.Window(start, start.Add(time.Hour)), DefaultThresholds); len(calm) != 0 {
+		t.Fatalf("calm hour must not alert: %+v", calm)
+	}
+}
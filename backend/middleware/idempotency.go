@@ -0,0 +1,288 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"beleg-app/backend/internal/apperror"
+	"beleg-app/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyMaxLen     = 128
+	idempotencyMaxStoredBody = 1 << 20
+	// idempotencyMaxMultipart — najveći upload koji handleri primaju (ParseMultipartForm u objavama).
+	idempotencyMaxMultipart = 20 << 20
+)
+
+var (
+	// idempotencyHeartbeat — koliko često obrada u toku osvežava rezervaciju ključa.
+	idempotencyHeartbeat = 30 * time.Second
+	// idempotencyStaleInProcess — rezervaciju bez znaka života ovoliko dugo (pad servera) preuzima ponovljen zahtev.
+	// Mora biti nekoliko puta duže od idempotencyHeartbeat, pa spor handler koji još radi nikad ne izvršava dvaput.
+	idempotencyStaleInProcess = 2 * time.Minute
+)
+
+// IdempotencyTTL — koliko dugo se ponovljen zahtev sa istim ključem odgovara zapamćenim odgovorom.
+var IdempotencyTTL = 24 * time.Hour
+
+// idempotencyRecorder prosleđuje odgovor klijentu i pamti telo za kasniji replay.
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyRecorder) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *idempotencyRecorder) capture(b []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(b) > idempotencyMaxStoredBody {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(b)
+}
+
+// idempotencyRequestHash vezuje ključ za metod, putanju i telo: isti ključ za drugi zahtev je greška klijenta.
+// Multipart (upload slika) se ne drži u memoriji — telo, najviše idempotencyMaxMultipart, se pri heširanju prepisuje
+// u privremeni fajl iz kog ga handler čita; cleanup briše fajl posle obrade zahteva.
+func idempotencyRequestHash(c *gin.Context) (hash string, cleanup func(), err error) {
+	cleanup = func() {}
+	h := sha256.New()
+	io.WriteString(h, c.Request.Method+" "+c.Request.URL.RequestURI()+"\n")
+	if c.Request.Body == nil {
+		return hex.EncodeToString(h.Sum(nil)), cleanup, nil
+	}
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		f, err := os.CreateTemp("", "idempotency-*")
+		if err != nil {
+			return "", cleanup, err
+		}
+		cleanup = func() {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+		body := http.MaxBytesReader(c.Writer, c.Request.Body, idempotencyMaxMultipart)
+		if _, err := io.Copy(io.MultiWriter(h, f), body); err != nil {
+			cleanup()
+			return "", func() {}, err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			cleanup()
+			return "", func() {}, err
+		}
+		_ = c.Request.Body.Close()
+		c.Request.Body = io.NopCloser(f)
+		return hex.EncodeToString(h.Sum(nil)), cleanup, nil
+	}
+	raw, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return "", cleanup, err
+	}
+	_ = c.Request.Body.Close()
+	c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+	h.Write(raw)
+	return hex.EncodeToString(h.Sum(nil)), cleanup, nil
+}
+
+// IdempotencyMiddleware poštuje Idempotency-Key na POST/PATCH (posle LoadUserMiddleware).
+//
+// Ključ se rezerviše (jedinstven po korisniku, metodu i putanji) pre handlera, u zasebnom upisu — ne unutar transakcije
+// handlera, pa ponovljen zahtev ne čeka na lock-ove iz helpers (LockAkcijaForUpdate → LockPrijavaForUpdate…)
+// niti ih preuzima: dobija 409 dok prvi traje, a posle toga zapamćen status i telo (Idempotent-Replayed: true).
+// Odgovor 5xx (ili panic) oslobađa ključ, jer je handler-ova transakcija vraćena i ponovni pokušaj je bezbedan.
+func IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPatch {
+			c.Next()
+			return
+		}
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > idempotencyKeyMaxLen {
+			apperror.Abort(c, apperror.New("VALIDATION", "Idempotency-Key može imati najviše 128 znakova", http.StatusBadRequest))
+			return
+		}
+		userIDVal, _ := c.Get(ContextKeyUserID)
+		userID, _ := userIDVal.(uint)
+		dbAny, ok := c.Get("db")
+		if !ok || userID == 0 {
+			c.Next()
+			return
+		}
+		db := dbAny.(*gorm.DB)
+
+		hash, cleanup, err := idempotencyRequestHash(c)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				apperror.Abort(c, apperror.New("PAYLOAD_TOO_LARGE", "Zahtev je prevelik", http.StatusRequestEntityTooLarge))
+				return
+			}
+			apperror.Abort(c, apperror.ErrValidation)
+			return
+		}
+		defer cleanup()
+		now := time.Now().UTC()
+		entry := models.IdempotencyKey{
+			KorisnikID:  userID,
+			Kljuc:       key,
+			Metod:       c.Request.Method,
+			Ruta:        c.Request.URL.Path,
+			RequestHash: hash,
+			CreatedAt:   now,
+			ObradaAt:    now,
+			ExpiresAt:   now.Add(IdempotencyTTL),
+		}
+		owned, existing, err := reserveIdempotencyKey(db, &entry, now)
+		if err != nil {
+			log.Printf("idempotency: rezervacija ključa: %v", err)
+			apperror.Abort(c, apperror.New("INTERNAL", "Greška pri obradi zahteva", http.StatusInternalServerError))
+			return
+		}
+		if !owned {
+			switch {
+			case existing.RequestHash != hash:
+				apperror.Abort(c, apperror.New("IDEMPOTENCY_MISMATCH", "Idempotency-Key je već iskorišćen za drugi zahtev", http.StatusUnprocessableEntity))
+			case existing.Status == 0:
+				apperror.Abort(c, apperror.New("IDEMPOTENCY_IN_PROGRESS", "Zahtev sa istim Idempotency-Key se još obrađuje", http.StatusConflict))
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				contentType := existing.ContentType
+				if contentType == "" {
+					contentType = "application/json; charset=utf-8"
+				}
+				c.Data(existing.Status, contentType, []byte(existing.ResponseBody))
+				c.Abort()
+			}
+			return
+		}
+
+		rec := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = rec
+		stored := false
+		defer func() {
+			if !stored {
+				_ = db.Where("id = ? AND status = ?", entry.ID, 0).Delete(&models.IdempotencyKey{}).Error
+			}
+		}()
+		stop := idempotencyKeepAlive(db, entry.ID)
+		c.Next()
+		stop()
+
+		status := rec.Status()
+		if status >= http.StatusInternalServerError || rec.overflow {
+			return
+		}
+		if err := db.Model(&models.IdempotencyKey{}).Where("id = ?", entry.ID).Updates(map[string]any{
+			"status":        status,
+			"content_type":  rec.Header().Get("Content-Type"),
+			"response_body": rec.body.String(),
+		}).Error; err != nil {
+			log.Printf("idempotency: čuvanje odgovora za ključ %d: %v", entry.ID, err)
+			return
+		}
+		stored = true
+	}
+}
+
+// idempotencyKeepAlive osvežava ObradaAt rezervacije dok handler radi, da je ponovljen zahtev ne bi preuzeo
+// kao napuštenu; stop čeka da se poslednje osvežavanje završi.
+func idempotencyKeepAlive(db *gorm.DB, id uint) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(idempotencyHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := db.Model(&models.IdempotencyKey{}).Where("id = ? AND status = ?", id, 0).
+					Update("obrada_at", time.Now().UTC()).Error; err != nil {
+					log.Printf("idempotency: osvežavanje ključa %d: %v", id, err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// reserveIdempotencyKey upisuje ključ u obradi; kada ključ već postoji, vraća zapamćen red.
+// Istekao ključ ili ključ napušten usred obrade (pad servera: ObradaAt se ne osvežava) preuzima novi zahtev.
+func reserveIdempotencyKey(db *gorm.DB, entry *models.IdempotencyKey, now time.Time) (bool, models.IdempotencyKey, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+		if res.Error != nil {
+			return false, models.IdempotencyKey{}, res.Error
+		}
+		if res.RowsAffected == 1 {
+			return true, models.IdempotencyKey{}, nil
+		}
+		var rows []models.IdempotencyKey
+		if err := db.Where("korisnik_id = ? AND metod = ? AND ruta = ? AND kljuc = ?", entry.KorisnikID, entry.Metod, entry.Ruta, entry.Kljuc).
+			Limit(1).Find(&rows).Error; err != nil {
+			return false, models.IdempotencyKey{}, err
+		}
+		if len(rows) == 0 {
+			entry.ID = 0
+			continue
+		}
+		existing := rows[0]
+		if existing.ExpiresAt.Before(now) {
+			if err := db.Where("id = ? AND expires_at < ?", existing.ID, now).Delete(&models.IdempotencyKey{}).Error; err != nil {
+				return false, models.IdempotencyKey{}, err
+			}
+			entry.ID = 0
+			continue
+		}
+		if existing.Status == 0 && existing.RequestHash == entry.RequestHash && existing.ObradaAt.Before(now.Add(-idempotencyStaleInProcess)) {
+			takeover := db.Model(&models.IdempotencyKey{}).
+				Where("id = ? AND status = ? AND obrada_at = ?", existing.ID, 0, existing.ObradaAt).
+				Updates(map[string]any{"obrada_at": now, "expires_at": entry.ExpiresAt})
+			if takeover.Error != nil {
+				return false, models.IdempotencyKey{}, takeover.Error
+			}
+			if takeover.RowsAffected == 1 {
+				entry.ID = existing.ID
+				return true, models.IdempotencyKey{}, nil
+			}
+		}
+		return false, existing, nil
+	}
+	return false, models.IdempotencyKey{}, gorm.ErrInvalidTransaction
+}
@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"beleg-app/backend/internal/models"
+	"beleg-app/backend/internal/testdb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func idempotencyRouter(t *testing.T) (*gin.Engine, *gorm.DB, *int) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(testdb.MemoryDSN(t, "idempotency")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.IdempotencyKey{}); err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("db", db)
+		c.Set(ContextKeyUserID, uint(7))
+		c.Next()
+	})
+	r.Use(IdempotencyMiddleware())
+	r.POST("/api/akcije/:id/prijava", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"prijavaId": calls})
+	})
+	r.POST("/api/pad", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Greška"})
+	})
+	return r, db, &calls
+}
+
+func idempotentPost(r *gin.Engine, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	r, _, calls := idempotencyRouter(t)
+
+	first := idempotentPost(r, "/api/akcije/3/prijava", "k-1", `{"sobaId":1}`)
+	if first.Code != http.StatusCreated || *calls != 1 {
+		t.Fatalf("first request: %d calls=%d", first.Code, *calls)
+	}
+	retry := idempotentPost(r, "/api/akcije/3/prijava", "k-1", `{"sobaId":1}`)
+	if retry.Code != http.StatusCreated || *calls != 1 || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retry must replay without running the handler: %d calls=%d body=%s", retry.Code, *calls, retry.Body.String())
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" || !strings.HasPrefix(retry.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("replay headers missing: %v", retry.Header())
+	}
+
+	if w := idempotentPost(r, "/api/akcije/3/prijava", "k-1", `{"sobaId":2}`); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("same key with another body must be rejected, got %d", w.Code)
+	}
+	if w := idempotentPost(r, "/api/akcije/4/prijava", "k-1", `{"sobaId":1}`); w.Code != http.StatusCreated || *calls != 2 {
+		t.Fatalf("same key on another path is an independent request: %d calls=%d", w.Code, *calls)
+	}
+	if w := idempotentPost(r, "/api/akcije/3/prijava", "", `{"sobaId":1}`); w.Code != http.StatusCreated || *calls != 3 {
+		t.Fatalf("requests without the header are not deduplicated: %d calls=%d", w.Code, *calls)
+	}
+	if w := idempotentPost(r, "/api/akcije/3/prijava", strings.Repeat("x", 129), `{}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("overlong key must be rejected, got %d", w.Code)
+	}
+}
+
+func TestIdempotencyMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	r, db, calls := idempotencyRouter(t)
+
+	if w := idempotentPost(r, "/api/pad", "k-2", `{}`); w.Code != http.StatusInternalServerError {
+		t.Fatalf("unexpected %d", w.Code)
+	}
+	var n int64
+	db.Model(&models.IdempotencyKey{}).Count(&n)
+	if n != 0 {
+		t.Fatalf("5xx response must not be stored, got %d rows", n)
+	}
+	if w := idempotentPost(r, "/api/pad", "k-2", `{}`); w.Code != http.StatusInternalServerError || *calls != 2 {
+		t.Fatalf("retry after 5xx must run the handler again: %d calls=%d", w.Code, *calls)
+	}
+}
+
+func TestIdempotencyMiddleware_InProgressConflict(t *testing.T) {
+	r, db, calls := idempotencyRouter(t)
+	req := httptest.NewRequest(http.MethodPost, "/api/akcije/3/prijava", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = req
+	hash, cleanup, _ := idempotencyRequestHash(c)
+	cleanup()
+	now := time.Now().UTC()
+	pending := models.IdempotencyKey{KorisnikID: 7, Kljuc: "k-3", Metod: http.MethodPost, Ruta: "/api/akcije/3/prijava",
+		RequestHash: hash, CreatedAt: now, ObradaAt: now, ExpiresAt: now.Add(IdempotencyTTL)}
+	if err := db.Create(&pending).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if w := idempotentPost(r, "/api/akcije/3/prijava", "k-3", `{}`); w.Code != http.StatusConflict || *calls != 0 {
+		t.Fatalf("concurrent retry must get 409 without running the handler: %d calls=%d", w.Code, *calls)
+	}
+}
+
+func TestIdempotencyMiddleware_SlowRequestKeepsReservation(t *testing.T) {
+	heartbeat, stale := idempotencyHeartbeat, idempotencyStaleInProcess
+	idempotencyHeartbeat, idempotencyStaleInProcess = 20*time.Millisecond, 100*time.Millisecond
+	t.Cleanup(func() { idempotencyHeartbeat, idempotencyStaleInProcess = heartbeat, stale })
+
+	r, _, _ := idempotencyRouter(t)
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	r.POST("/api/spor", func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- idempotentPost(r, "/api/spor", "k-5", `{}`) }()
+	<-started
+
+	// Handler radi duže od prozora zastarelosti; osvežavanje rezervacije ne sme pustiti ponovljen zahtev.
+	time.Sleep(5 * idempotencyStaleInProcess)
+	if w := idempotentPost(r, "/api/spor", "k-5", `{}`); w.Code != http.StatusConflict || calls.Load() != 1 {
+		t.Fatalf("retry of a running request must get 409: %d calls=%d", w.Code, calls.Load())
+	}
+	close(release)
+	if w := <-first; w.Code != http.StatusCreated {
+		t.Fatalf("unexpected %d", w.Code)
+	}
+	if w := idempotentPost(r, "/api/spor", "k-5", `{}`); w.Header().Get(IdempotentReplayedHeader) != "true" || calls.Load() != 1 {
+		t.Fatalf("finished request must replay: %d calls=%d", w.Code, calls.Load())
+	}
+}
+
+func TestIdempotencyMiddleware_MultipartTooLarge(t *testing.T) {
+	r, db, _ := idempotencyRouter(t)
+	var calls int
+	r.POST("/api/posts", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, _ := mw.CreateFormFile("slika", "veliko.jpg")
+	fw.Write(bytes.Repeat([]byte{'x'}, idempotencyMaxMultipart+1))
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/posts", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set(IdempotencyKeyHeader, "k-6")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var n int64
+	db.Model(&models.IdempotencyKey{}).Count(&n)
+	if w.Code != http.StatusRequestEntityTooLarge || calls != 0 || n != 0 {
+		t.Fatalf("oversized upload must be rejected before reserving the key: %d calls=%d rows=%d", w.Code, calls, n)
+	}
+}
+
+func TestIdempotencyMiddleware_MultipartHashesBody(t *testing.T) {
+	r, _, _ := idempotencyRouter(t)
+	var seen []string
+	r.POST("/api/posts", func(c *gin.Context) {
+		fh, err := c.FormFile("slika")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		f, _ := fh.Open()
+		defer f.Close()
+		raw, _ := io.ReadAll(f)
+		seen = append(seen, string(raw))
+		c.JSON(http.StatusCreated, gin.H{"postId": len(seen)})
+	})
+	upload := func(key, content string) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		mw.SetBoundary("beleg-retry") // ponovljen zahtev klijenta šalje iste bajtove
+		fw, _ := mw.CreateFormFile("slika", "vrh.jpg")
+		fw.Write([]byte(content))
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/posts", &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set(IdempotencyKeyHeader, key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := upload("k-4", "aaaa"); w.Code != http.StatusCreated || len(seen) != 1 || seen[0] != "aaaa" {
+		t.Fatalf("handler must read the spooled upload: %d %v", w.Code, seen)
+	}
+	if w := upload("k-4", "aaaa"); w.Code != http.StatusCreated || w.Header().Get(IdempotentReplayedHeader) != "true" || len(seen) != 1 {
+		t.Fatalf("identical upload must replay: %d %v", w.Code, seen)
+	}
+	// Ista dužina tela, drugi sadržaj: ranije bi prošlo kao isti zahtev.
+	if w := upload("k-4", "bbbb"); w.Code != http.StatusUnprocessableEntity || len(seen) != 1 {
+		t.Fatalf("different upload with the same key must be rejected: %d %v", w.Code, seen)
+	}
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS peak_list_items;
+DROP TABLE IF EXISTS peak_lists;
+DROP TABLE IF EXISTS peak_ascents;
+DROP INDEX IF EXISTS idx_akcije_peak_id;
+ALTER TABLE akcije DROP COLUMN IF EXISTS peak_id;
@@ -0,0 +1,47 @@
+-- Dnevnik uspona po vrhu iz kataloga + kurirane liste vrhova.
+-- akcije.peak_id je opcioni link akcije na peaks (Vrh ostaje slobodan tekst).
+
+ALTER TABLE akcije ADD COLUMN IF NOT EXISTS peak_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_akcije_peak_id ON akcije (peak_id);
+
+CREATE TABLE IF NOT EXISTS peak_ascents (
+    id BIGSERIAL PRIMARY KEY,
+    korisnik_id BIGINT NOT NULL,
+    peak_id BIGINT NOT NULL,
+    datum TIMESTAMPTZ NOT NULL,
+    izvor VARCHAR(20) NOT NULL,
+    akcija_id BIGINT,
+    tracked_activity_id BIGINT,
+    zimski_uspon BOOLEAN NOT NULL DEFAULT FALSE,
+    napomena VARCHAR(500),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_peak_ascents_korisnik_peak ON peak_ascents (korisnik_id, peak_id);
+CREATE INDEX IF NOT EXISTS idx_peak_ascents_peak_id ON peak_ascents (peak_id);
+CREATE INDEX IF NOT EXISTS idx_peak_ascents_akcija_id ON peak_ascents (akcija_id);
+CREATE INDEX IF NOT EXISTS idx_peak_ascents_tracked_activity_id ON peak_ascents (tracked_activity_id);
+
+CREATE TABLE IF NOT EXISTS peak_lists (
+    id BIGSERIAL PRIMARY KEY,
+    naziv VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    opis TEXT,
+    klub_id BIGINT,
+    created_by_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_peak_lists_slug ON peak_lists (slug);
+CREATE INDEX IF NOT EXISTS idx_peak_lists_klub_id ON peak_lists (klub_id);
+
+CREATE TABLE IF NOT EXISTS peak_list_items (
+    id BIGSERIAL PRIMARY KEY,
+    peak_list_id BIGINT NOT NULL,
+    peak_id BIGINT NOT NULL,
+    pozicija INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_peak_list_items_list_peak ON peak_list_items (peak_list_id, peak_id);
+CREATE INDEX IF NOT EXISTS idx_peak_list_items_peak_id ON peak_list_items (peak_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uidx_user_achievements_korisnik_kod;
+DROP TABLE IF EXISTS user_achievements;
@@ -0,0 +1,12 @@
+-- Osvojeni bedževi; kod odgovara pravilu u internal/achievements.
+
+CREATE TABLE IF NOT EXISTS user_achievements (
+    id BIGSERIAL PRIMARY KEY,
+    korisnik_id BIGINT NOT NULL,
+    kod VARCHAR(60) NOT NULL,
+    earned_at TIMESTAMPTZ NOT NULL,
+    backfilled BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_user_achievements_korisnik_kod
+    ON user_achievements (korisnik_id, kod);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS club_challenge_results;
+DROP TABLE IF EXISTS club_challenge_participants;
+DROP TABLE IF EXISTS club_challenge_teams;
+DROP TABLE IF EXISTS club_challenges;
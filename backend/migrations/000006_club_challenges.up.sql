@@ -0,0 +1,55 @@
+-- Vremenski ograničeni izazovi (koraci, km, uspon, vrhovi), opt-in učešće i zamrznuti rezultati.
+
+CREATE TABLE IF NOT EXISTS club_challenges (
+    id BIGSERIAL PRIMARY KEY,
+    naziv VARCHAR(150) NOT NULL,
+    opis TEXT,
+    metrika VARCHAR(20) NOT NULL,
+    tip VARCHAR(20) NOT NULL DEFAULT 'individualni',
+    obuhvat VARCHAR(20) NOT NULL DEFAULT 'klub',
+    klub_id BIGINT,
+    pocetak_datum DATE NOT NULL,
+    kraj_datum DATE NOT NULL,
+    zavrseno_at TIMESTAMPTZ,
+    created_by_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_club_challenges_klub_id ON club_challenges (klub_id);
+CREATE INDEX IF NOT EXISTS idx_club_challenges_kraj_datum ON club_challenges (kraj_datum);
+
+CREATE TABLE IF NOT EXISTS club_challenge_teams (
+    id BIGSERIAL PRIMARY KEY,
+    challenge_id BIGINT NOT NULL,
+    naziv VARCHAR(150) NOT NULL,
+    klub_id BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_club_challenge_teams_challenge_id ON club_challenge_teams (challenge_id);
+CREATE INDEX IF NOT EXISTS idx_club_challenge_teams_klub_id ON club_challenge_teams (klub_id);
+
+CREATE TABLE IF NOT EXISTS club_challenge_participants (
+    id BIGSERIAL PRIMARY KEY,
+    challenge_id BIGINT NOT NULL,
+    korisnik_id BIGINT NOT NULL,
+    team_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_club_challenge_participants_challenge_korisnik
+    ON club_challenge_participants (challenge_id, korisnik_id);
+CREATE INDEX IF NOT EXISTS idx_club_challenge_participants_korisnik_id ON club_challenge_participants (korisnik_id);
+CREATE INDEX IF NOT EXISTS idx_club_challenge_participants_team_id ON club_challenge_participants (team_id);
+
+CREATE TABLE IF NOT EXISTS club_challenge_results (
+    id BIGSERIAL PRIMARY KEY,
+    challenge_id BIGINT NOT NULL,
+    korisnik_id BIGINT,
+    team_id BIGINT,
+    vrednost DOUBLE PRECISION NOT NULL DEFAULT 0,
+    rang INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_club_challenge_results_challenge_id ON club_challenge_results (challenge_id);
+CREATE INDEX IF NOT EXISTS idx_club_challenge_results_korisnik_id ON club_challenge_results (korisnik_id);
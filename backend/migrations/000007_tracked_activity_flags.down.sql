@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tracked_activities_flagged;
+ALTER TABLE tracked_activities DROP COLUMN IF EXISTS flag_reason;
+ALTER TABLE tracked_activities DROP COLUMN IF EXISTS flagged;
@@ -0,0 +1,5 @@
+-- Automatski označene GPS sesije (neverovatna brzina/uspon) se isključuju iz rang lista i izazova.
+
+ALTER TABLE tracked_activities ADD COLUMN IF NOT EXISTS flagged BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tracked_activities ADD COLUMN IF NOT EXISTS flag_reason VARCHAR(100);
+CREATE INDEX IF NOT EXISTS idx_tracked_activities_flagged ON tracked_activities (flagged);
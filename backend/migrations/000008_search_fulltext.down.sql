@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_korisnici_search;
+DROP INDEX IF EXISTS idx_klubovi_search;
+DROP INDEX IF EXISTS idx_guide_profiles_search;
+DROP INDEX IF EXISTS idx_hotels_search;
+DROP INDEX IF EXISTS idx_ferratas_search;
+DROP INDEX IF EXISTS idx_peaks_search;
+DROP INDEX IF EXISTS idx_akcije_search;
+DROP FUNCTION IF EXISTS beleg_fold(text);
@@ -0,0 +1,29 @@
+-- Globalna pretraga: beleg_fold (ćirilica → latinica, bez dijakritika; isto kao slug.FoldLatin)
+-- i GIN full-text indeksi; izrazi su identični search.Documents.
+-- lower() zavisi od lokala baze (pod C lokalom ne menja ćirilicu), pa se velika slova prvo preslikavaju eksplicitno.
+
+CREATE OR REPLACE FUNCTION beleg_fold(input text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+	SELECT translate(
+		replace(replace(replace(replace(replace(lower(translate(coalesce(input, ''),
+			'ČĆŠŽĐАБВГДЂЕЖЗИЈКЛЉМНЊОПРСТЋУФХЦЧЏШ',
+			'čćšžđабвгдђежзијклљмнњопрстћуфхцчџш')), 'đ', 'dj'), 'ђ', 'dj'), 'љ', 'lj'), 'њ', 'nj'), 'џ', 'dz'),
+		'čćšžабвгдежзијклмнопрстћуфхцчш',
+		'ccszabvgdezzijklmnoprstcufhccs'
+	)
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_akcije_search ON akcije
+    USING GIN (to_tsvector('simple', beleg_fold(coalesce(naziv, '') || ' ' || coalesce(planina, '') || ' ' || coalesce(vrh, ''))));
+CREATE INDEX IF NOT EXISTS idx_peaks_search ON peaks
+    USING GIN (to_tsvector('simple', beleg_fold(coalesce(naziv_vrha, '') || ' ' || coalesce(planina, '') || ' ' || coalesce(grad, '') || ' ' || coalesce(drzava, ''))));
+CREATE INDEX IF NOT EXISTS idx_ferratas_search ON ferratas
+    USING GIN (to_tsvector('simple', beleg_fold(coalesce(naziv, '') || ' ' || coalesce(grad_opstina, '') || ' ' || coalesce(lokacija, '') || ' ' || coalesce(drzava, ''))));
+CREATE INDEX IF NOT EXISTS idx_hotels_search ON hotels
+    USING GIN (to_tsvector('simple', beleg_fold(coalesce(naziv, '') || ' ' || coalesce(adresa, ''))));
+CREATE INDEX IF NOT EXISTS idx_guide_profiles_search ON guide_profiles
+    USING GIN (to_tsvector('simple', beleg_fold(coalesce(naslov, '') || ' ' || coalesce(region, '') || ' ' || coalesce(grad, '') || ' ' || coalesce(drzava, ''))));
+CREATE INDEX IF NOT EXISTS idx_klubovi_search ON klubovi
+    USING GIN (to_tsvector('simple', beleg_fold(coalesce(naziv, '') || ' ' || coalesce(sediste, ''))));
+CREATE INDEX IF NOT EXISTS idx_korisnici_search ON korisnici
+    USING GIN (to_tsvector('simple', beleg_fold(coalesce(username, '') || ' ' || coalesce(full_name, ''))));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_akcije_planina_lat_lng;
+DROP INDEX IF EXISTS idx_guide_profiles_base_lat_lng;
+DROP INDEX IF EXISTS idx_hotels_lat_lng;
+DROP INDEX IF EXISTS idx_ferratas_lat_lng;
+DROP INDEX IF EXISTS idx_peaks_lat_lng;
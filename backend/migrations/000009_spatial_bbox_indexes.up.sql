@@ -0,0 +1,7 @@
+-- Btree (lat, lng) indeksi za bbox upite mape (/api/map) i prefilter „u okolini“; bez PostGIS-a.
+
+CREATE INDEX IF NOT EXISTS idx_peaks_lat_lng ON peaks (lat, lng);
+CREATE INDEX IF NOT EXISTS idx_ferratas_lat_lng ON ferratas (lat, lng);
+CREATE INDEX IF NOT EXISTS idx_hotels_lat_lng ON hotels (lat, lng);
+CREATE INDEX IF NOT EXISTS idx_guide_profiles_base_lat_lng ON guide_profiles (base_lat, base_lng);
+CREATE INDEX IF NOT EXISTS idx_akcije_planina_lat_lng ON akcije (planina_lat, planina_lng);
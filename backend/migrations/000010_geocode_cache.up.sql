@@ -0,0 +1,11 @@
+-- Trajni keš spoljnog geokodera (Nominatim/Photon) za /api/geocode i /api/geocode/reverse.
+
+CREATE TABLE IF NOT EXISTS geocode_cache (
+    id BIGSERIAL PRIMARY KEY,
+    kljuc VARCHAR(300) NOT NULL,
+    results_json TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_geocode_cache_kljuc ON geocode_cache (kljuc);
+CREATE INDEX IF NOT EXISTS idx_geocode_cache_expires_at ON geocode_cache (expires_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS offline_bundle_versions;
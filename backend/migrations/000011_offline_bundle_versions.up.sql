@@ -0,0 +1,11 @@
+-- Manifesti izdatih offline paketa (tip → ID → hash stavke) za /api/offline/bundle/delta.
+
+CREATE TABLE IF NOT EXISTS offline_bundle_versions (
+    id BIGSERIAL PRIMARY KEY,
+    region_key VARCHAR(120) NOT NULL,
+    version VARCHAR(64) NOT NULL,
+    manifest_json TEXT NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_offline_bundle_versions_region_version ON offline_bundle_versions (region_key, version);
+CREATE INDEX IF NOT EXISTS idx_offline_bundle_versions_created_at ON offline_bundle_versions (created_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS sync_tombstones;
+DROP INDEX IF EXISTS idx_obavestenja_updated_at;
+ALTER TABLE obavestenja DROP COLUMN IF EXISTS updated_at;
+DROP INDEX IF EXISTS idx_prijave_updated_at;
+ALTER TABLE prijave DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,22 @@
+-- Delta sync (/api/sync): updated_at na prijavama i obaveštenjima + tombstone-ovi obrisanih zapisa.
+
+ALTER TABLE prijave ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+UPDATE prijave SET updated_at = COALESCE(prijavljen_at, CURRENT_TIMESTAMP) WHERE updated_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_prijave_updated_at ON prijave (updated_at);
+
+ALTER TABLE obavestenja ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+UPDATE obavestenja SET updated_at = COALESCE(read_at, created_at, CURRENT_TIMESTAMP) WHERE updated_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_obavestenja_updated_at ON obavestenja (updated_at);
+
+CREATE TABLE IF NOT EXISTS sync_tombstones (
+    id BIGSERIAL PRIMARY KEY,
+    entitet VARCHAR(40) NOT NULL,
+    entitet_id BIGINT NOT NULL,
+    korisnik_id BIGINT,
+    klub_id BIGINT,
+    javna BOOLEAN,
+    obrisano_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_korisnik_id ON sync_tombstones (korisnik_id);
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_klub_id ON sync_tombstones (klub_id);
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_obrisano_at ON sync_tombstones (obrisano_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tracked_activity_point_batches;
+DROP INDEX IF EXISTS uidx_tracked_activities_user_client;
+ALTER TABLE tracked_activities DROP COLUMN IF EXISTS point_seq_mode;
+ALTER TABLE tracked_activities DROP COLUMN IF EXISTS client_id;
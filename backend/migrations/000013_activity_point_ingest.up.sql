@@ -0,0 +1,15 @@
+-- Idempotentan prijem GPS tačaka: ID aktivnosti sa uređaja (offline upload) i primljeni batch-evi tačaka.
+
+ALTER TABLE tracked_activities ADD COLUMN IF NOT EXISTS client_id VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_tracked_activities_user_client ON tracked_activities (user_id, client_id);
+-- Način numerisanja tačaka (seq od klijenta ili servera) se utvrđuje prvim batch-om aktivnosti.
+ALTER TABLE tracked_activities ADD COLUMN IF NOT EXISTS point_seq_mode VARCHAR(10) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS tracked_activity_point_batches (
+    id BIGSERIAL PRIMARY KEY,
+    activity_id BIGINT NOT NULL,
+    batch_id VARCHAR(64) NOT NULL,
+    added BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_tracked_activity_point_batches_activity_batch ON tracked_activity_point_batches (activity_id, batch_id);
@@ -0,0 +1,18 @@
+-- Idempotency-Key za POST/PATCH: zapamćen status i telo odgovora po (korisnik, metod, putanja, ključ), čuvaju se 24 h.
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id BIGSERIAL PRIMARY KEY,
+    korisnik_id BIGINT NOT NULL,
+    kljuc VARCHAR(128) NOT NULL,
+    metod VARCHAR(10) NOT NULL,
+    ruta VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status BIGINT NOT NULL DEFAULT 0,
+    content_type VARCHAR(100),
+    response_body TEXT,
+    created_at TIMESTAMPTZ,
+    obrada_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_idempotency_keys_korisnik_ruta_kljuc ON idempotency_keys (korisnik_id, metod, ruta, kljuc);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
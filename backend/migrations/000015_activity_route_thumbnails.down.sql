@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_activity_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS activity_id;
+ALTER TABLE tracked_activities DROP COLUMN IF EXISTS route_thumbnail_url;
+ALTER TABLE tracked_activities DROP COLUMN IF EXISTS route_polylines;
@@ -0,0 +1,7 @@
+-- Rute aktivnosti sa servera: pojednostavljeni polyline-i po nivou zuma, PNG sličica rute i deljenje aktivnosti u objavama.
+
+ALTER TABLE tracked_activities ADD COLUMN IF NOT EXISTS route_polylines TEXT;
+ALTER TABLE tracked_activities ADD COLUMN IF NOT EXISTS route_thumbnail_url VARCHAR(500);
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS activity_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_posts_activity_id ON posts (activity_id);
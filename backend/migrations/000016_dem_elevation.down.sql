@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS akcija_rute;
+ALTER TABLE tracked_activity_points DROP COLUMN IF EXISTS altitude_dem;
@@ -0,0 +1,19 @@
+-- Visine terena iz DEM-a: oznaka ispravljenih GPS visina i uvezene trase akcija sa visinskim profilom.
+
+ALTER TABLE tracked_activity_points ADD COLUMN IF NOT EXISTS altitude_dem BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS akcija_rute (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    polyline TEXT NOT NULL,
+    visine TEXT,
+    izvor VARCHAR(20) NOT NULL,
+    duzina_m DOUBLE PRECISION NOT NULL DEFAULT 0,
+    uspon_m DOUBLE PRECISION NOT NULL DEFAULT 0,
+    spust_m DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_nagib_pct DOUBLE PRECISION NOT NULL DEFAULT 0,
+    uvezao_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_akcija_rute_akcija_id ON akcija_rute (akcija_id);
@@ -0,0 +1 @@
+ALTER TABLE akcije DROP COLUMN IF EXISTS podsetnik_poslat_at;
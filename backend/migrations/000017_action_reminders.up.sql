@@ -0,0 +1,3 @@
+-- Podsetnik pre akcije (sa dnevnim svetlom) šalje se jednom; job označava akciju pre slanja.
+
+ALTER TABLE akcije ADD COLUMN IF NOT EXISTS podsetnik_poslat_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+ALTER TABLE akcije DROP COLUMN IF EXISTS vreme_upozorenja;
+DROP TABLE IF EXISTS weather_cache;
@@ -0,0 +1,13 @@
+-- Vremenska prognoza: keš po tački/visini/satu i već javljeni rizici po akciji (upozorenje se ne ponavlja).
+
+CREATE TABLE IF NOT EXISTS weather_cache (
+    id BIGSERIAL PRIMARY KEY,
+    kljuc VARCHAR(120) NOT NULL,
+    forecast_json TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_weather_cache_kljuc ON weather_cache (kljuc);
+CREATE INDEX IF NOT EXISTS idx_weather_cache_expires_at ON weather_cache (expires_at);
+
+ALTER TABLE akcije ADD COLUMN IF NOT EXISTS vreme_upozorenja VARCHAR(100) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_prijave_potvrda_do;
+ALTER TABLE prijave DROP COLUMN IF EXISTS potvrda_do;
+DROP TABLE IF EXISTS akcija_pomeranja;
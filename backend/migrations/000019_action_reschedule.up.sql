@@ -0,0 +1,23 @@
+-- Pomeranje termina akcije: istorija pomeranja i rok do kog prijavljeni potvrđuju novi termin.
+
+CREATE TABLE IF NOT EXISTS akcija_pomeranja (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    stari_datum TIMESTAMPTZ NOT NULL,
+    stari_start_at TIMESTAMPTZ,
+    stari_end_at TIMESTAMPTZ,
+    stari_rok_prijava TIMESTAMPTZ,
+    novi_datum TIMESTAMPTZ NOT NULL,
+    novi_start_at TIMESTAMPTZ,
+    novi_end_at TIMESTAMPTZ,
+    novi_rok_prijava TIMESTAMPTZ,
+    razlog TEXT NOT NULL DEFAULT '',
+    potvrda_do TIMESTAMPTZ NOT NULL,
+    broj_za_potvrdu BIGINT NOT NULL DEFAULT 0,
+    promenio_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_akcija_pomeranja_akcija_id ON akcija_pomeranja (akcija_id);
+
+ALTER TABLE prijave ADD COLUMN IF NOT EXISTS potvrda_do TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_prijave_potvrda_do ON prijave (potvrda_do);
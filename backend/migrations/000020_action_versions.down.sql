@@ -0,0 +1,2 @@
+ALTER TABLE prijave DROP COLUMN IF EXISTS bez_penala_do;
+DROP TABLE IF EXISTS akcija_verzije;
@@ -0,0 +1,17 @@
+-- Istorija izmena akcije: verzije sa snimkom stanja i razlikom; odustajanje bez penala posle poskupljenja.
+
+CREATE TABLE IF NOT EXISTS akcija_verzije (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    verzija BIGINT NOT NULL,
+    izvor VARCHAR(20) NOT NULL,
+    snapshot TEXT NOT NULL,
+    promene TEXT NOT NULL DEFAULT '[]',
+    materijalna BOOLEAN NOT NULL DEFAULT FALSE,
+    poskupljenje_cene BOOLEAN NOT NULL DEFAULT FALSE,
+    promenio_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_akcija_verzije_akcija_verzija ON akcija_verzije (akcija_id, verzija);
+
+ALTER TABLE prijave ADD COLUMN IF NOT EXISTS bez_penala_do TIMESTAMPTZ;
@@ -0,0 +1,8 @@
+ALTER TABLE klubovi DROP COLUMN IF EXISTS pregled_akcija_vodica;
+DROP INDEX IF EXISTS idx_akcije_objavi_at;
+DROP INDEX IF EXISTS idx_akcije_status_objave;
+ALTER TABLE akcije DROP COLUMN IF EXISTS pregled_napomena;
+ALTER TABLE akcije DROP COLUMN IF EXISTS pregledao_id;
+ALTER TABLE akcije DROP COLUMN IF EXISTS objavljena_at;
+ALTER TABLE akcije DROP COLUMN IF EXISTS objavi_at;
+ALTER TABLE akcije DROP COLUMN IF EXISTS status_objave;
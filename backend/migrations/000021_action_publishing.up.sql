@@ -0,0 +1,13 @@
+-- Nacrt, pregled admina kluba i zakazano objavljivanje akcija. Postojeće akcije ostaju objavljene.
+
+ALTER TABLE akcije ADD COLUMN IF NOT EXISTS status_objave VARCHAR(20) NOT NULL DEFAULT 'objavljena';
+ALTER TABLE akcije ADD COLUMN IF NOT EXISTS objavi_at TIMESTAMPTZ;
+ALTER TABLE akcije ADD COLUMN IF NOT EXISTS objavljena_at TIMESTAMPTZ;
+ALTER TABLE akcije ADD COLUMN IF NOT EXISTS pregledao_id BIGINT;
+ALTER TABLE akcije ADD COLUMN IF NOT EXISTS pregled_napomena TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_akcije_status_objave ON akcije (status_objave);
+CREATE INDEX IF NOT EXISTS idx_akcije_objavi_at ON akcije (objavi_at);
+
+UPDATE akcije SET objavljena_at = created_at WHERE objavljena_at IS NULL;
+
+ALTER TABLE klubovi ADD COLUMN IF NOT EXISTS pregled_akcija_vodica BOOLEAN NOT NULL DEFAULT FALSE;
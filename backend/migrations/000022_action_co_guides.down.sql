@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_guide_rating_akcija_rater_guide;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_guide_rating_akcija_rater ON guide_action_ratings (akcija_id, rater_korisnik_id);
+DROP TABLE IF EXISTS akcija_vodici;
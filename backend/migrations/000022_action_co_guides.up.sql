@@ -0,0 +1,16 @@
+-- Ko-vodiči akcije sa ulogama; ocena vodiča je po učesniku i vodiču (više vodiča na istoj akciji).
+
+CREATE TABLE IF NOT EXISTS akcija_vodici (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    korisnik_id BIGINT NOT NULL,
+    guide_profile_id BIGINT,
+    uloga VARCHAR(20) NOT NULL DEFAULT 'pomocni',
+    dodao_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_akcija_vodici_akcija_korisnik ON akcija_vodici (akcija_id, korisnik_id);
+CREATE INDEX IF NOT EXISTS idx_akcija_vodici_korisnik_id ON akcija_vodici (korisnik_id);
+
+DROP INDEX IF EXISTS idx_guide_rating_akcija_rater;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_guide_rating_akcija_rater_guide ON guide_action_ratings (akcija_id, rater_korisnik_id, guide_korisnik_id);
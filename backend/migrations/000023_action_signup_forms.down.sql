@@ -0,0 +1,5 @@
+ALTER TABLE action_signup_requests DROP COLUMN IF EXISTS forma_verzija;
+ALTER TABLE action_signup_requests DROP COLUMN IF EXISTS odgovori;
+ALTER TABLE prijava_izbori DROP COLUMN IF EXISTS forma_verzija;
+ALTER TABLE prijava_izbori DROP COLUMN IF EXISTS odgovori;
+DROP TABLE IF EXISTS akcija_forme;
@@ -0,0 +1,17 @@
+-- Forma sa dodatnim pitanjima pri prijavi na akciju; odgovori uz izbore prijave i zahteve za prijavu.
+
+CREATE TABLE IF NOT EXISTS akcija_forme (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    polja TEXT NOT NULL DEFAULT '[]',
+    verzija BIGINT NOT NULL DEFAULT 1,
+    izmenio_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_akcija_forme_akcija_id ON akcija_forme (akcija_id);
+
+ALTER TABLE prijava_izbori ADD COLUMN IF NOT EXISTS odgovori TEXT;
+ALTER TABLE prijava_izbori ADD COLUMN IF NOT EXISTS forma_verzija BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE action_signup_requests ADD COLUMN IF NOT EXISTS odgovori TEXT;
+ALTER TABLE action_signup_requests ADD COLUMN IF NOT EXISTS forma_verzija BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS korisnik_sertifikati;
+DROP TABLE IF EXISTS akcija_uslov_izuzeci;
+DROP TABLE IF EXISTS akcija_uslovi;
@@ -0,0 +1,38 @@
+-- Uslovi za prijavu na zahtevne akcije, izuzeci po članu i sertifikati članova.
+
+CREATE TABLE IF NOT EXISTS akcija_uslovi (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    min_broj_akcija BIGINT NOT NULL DEFAULT 0,
+    tip_akcije VARCHAR(30) NOT NULL DEFAULT '',
+    min_tezina VARCHAR(20) NOT NULL DEFAULT '',
+    min_visina_m BIGINT NOT NULL DEFAULT 0,
+    clanarina_placena BOOLEAN NOT NULL DEFAULT FALSE,
+    sertifikati TEXT NOT NULL DEFAULT '[]',
+    min_godine BIGINT NOT NULL DEFAULT 0,
+    izmenio_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_akcija_uslovi_akcija_id ON akcija_uslovi (akcija_id);
+
+CREATE TABLE IF NOT EXISTS akcija_uslov_izuzeci (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    korisnik_id BIGINT NOT NULL,
+    odobrio_id BIGINT NOT NULL DEFAULT 0,
+    napomena TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_akcija_uslov_izuzeci_akcija_korisnik ON akcija_uslov_izuzeci (akcija_id, korisnik_id);
+CREATE INDEX IF NOT EXISTS idx_akcija_uslov_izuzeci_korisnik_id ON akcija_uslov_izuzeci (korisnik_id);
+
+CREATE TABLE IF NOT EXISTS korisnik_sertifikati (
+    id BIGSERIAL PRIMARY KEY,
+    korisnik_id BIGINT NOT NULL,
+    naziv VARCHAR(100) NOT NULL,
+    vazi_do TIMESTAMPTZ,
+    evidentirao_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_korisnik_sertifikati_korisnik_id ON korisnik_sertifikati (korisnik_id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_action_signup_requests_promo_kod_id;
+ALTER TABLE action_signup_requests DROP COLUMN IF EXISTS grupa;
+ALTER TABLE action_signup_requests DROP COLUMN IF EXISTS promo_kod_id;
+ALTER TABLE action_signup_requests DROP COLUMN IF EXISTS cena_obracun;
+DROP INDEX IF EXISTS idx_prijava_izbori_promo_kod_id;
+ALTER TABLE prijava_izbori DROP COLUMN IF EXISTS grupa;
+ALTER TABLE prijava_izbori DROP COLUMN IF EXISTS promo_kod_id;
+ALTER TABLE prijava_izbori DROP COLUMN IF EXISTS cena_obracun;
+DROP TABLE IF EXISTS akcija_promo_kodovi;
+DROP TABLE IF EXISTS akcija_cenovnici;
@@ -0,0 +1,44 @@
+-- Cenovnik akcije (uzrasne kategorije, early bird, grupni popust), promo kodovi i primenjena cena na prijavi.
+
+CREATE TABLE IF NOT EXISTS akcija_cenovnici (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    cena_dete NUMERIC,
+    dete_do_godina BIGINT NOT NULL DEFAULT 14,
+    cena_student NUMERIC,
+    student_do_godina BIGINT NOT NULL DEFAULT 26,
+    cena_senior NUMERIC,
+    senior_od_godina BIGINT NOT NULL DEFAULT 65,
+    early_bird_do TIMESTAMPTZ,
+    early_bird_cena_clan NUMERIC,
+    early_bird_cena_ostali NUMERIC,
+    grupa_min_osoba BIGINT NOT NULL DEFAULT 0,
+    grupa_popust_procenat NUMERIC NOT NULL DEFAULT 0,
+    izmenio_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_akcija_cenovnici_akcija_id ON akcija_cenovnici (akcija_id);
+
+CREATE TABLE IF NOT EXISTS akcija_promo_kodovi (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    kod VARCHAR(40) NOT NULL,
+    popust_procenat NUMERIC NOT NULL DEFAULT 0,
+    popust_iznos NUMERIC NOT NULL DEFAULT 0,
+    max_koriscenja BIGINT NOT NULL DEFAULT 0,
+    vazi_do TIMESTAMPTZ,
+    aktivan BOOLEAN NOT NULL DEFAULT TRUE,
+    kreirao_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_akcija_promo_kodovi_akcija_kod ON akcija_promo_kodovi (akcija_id, kod);
+
+ALTER TABLE prijava_izbori ADD COLUMN IF NOT EXISTS cena_obracun TEXT;
+ALTER TABLE prijava_izbori ADD COLUMN IF NOT EXISTS promo_kod_id BIGINT;
+ALTER TABLE prijava_izbori ADD COLUMN IF NOT EXISTS grupa VARCHAR(60) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_prijava_izbori_promo_kod_id ON prijava_izbori (promo_kod_id);
+ALTER TABLE action_signup_requests ADD COLUMN IF NOT EXISTS cena_obracun TEXT;
+ALTER TABLE action_signup_requests ADD COLUMN IF NOT EXISTS promo_kod_id BIGINT;
+ALTER TABLE action_signup_requests ADD COLUMN IF NOT EXISTS grupa VARCHAR(60) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_action_signup_requests_promo_kod_id ON action_signup_requests (promo_kod_id);
@@ -0,0 +1,4 @@
+ALTER TABLE transakcije DROP COLUMN IF EXISTS clanarina_godina;
+ALTER TABLE klubovi DROP COLUMN IF EXISTS clanarina_iznos;
+DROP TABLE IF EXISTS placanje_dogadjaji;
+DROP TABLE IF EXISTS placanja;
@@ -0,0 +1,54 @@
+-- Online plaćanja kotizacije i članarine preko provajdera, primljeni webhook događaji i iznos članarine kluba.
+
+CREATE TABLE IF NOT EXISTS placanja (
+    id BIGSERIAL PRIMARY KEY,
+    svrha VARCHAR(20) NOT NULL,
+    klub_id BIGINT,
+    korisnik_id BIGINT NOT NULL,
+    akcija_id BIGINT,
+    prijava_id BIGINT,
+    godina BIGINT NOT NULL DEFAULT 0,
+    iznos NUMERIC NOT NULL,
+    valuta VARCHAR(8) NOT NULL DEFAULT 'RSD',
+    status VARCHAR(20) NOT NULL DEFAULT 'na_cekanju',
+    provider VARCHAR(20) NOT NULL,
+    idempotency_key VARCHAR(80) NOT NULL,
+    checkout_id VARCHAR(120),
+    checkout_url TEXT,
+    payment_id VARCHAR(120),
+    vraceno NUMERIC NOT NULL DEFAULT 0,
+    povracaj_u_toku NUMERIC NOT NULL DEFAULT 0,
+    transakcija_id BIGINT,
+    placeno_at TIMESTAMPTZ,
+    greska TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_placanja_idempotency_key ON placanja (idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_placanja_svrha ON placanja (svrha);
+CREATE INDEX IF NOT EXISTS idx_placanja_klub_id ON placanja (klub_id);
+CREATE INDEX IF NOT EXISTS idx_placanja_korisnik_id ON placanja (korisnik_id);
+CREATE INDEX IF NOT EXISTS idx_placanja_akcija_id ON placanja (akcija_id);
+CREATE INDEX IF NOT EXISTS idx_placanja_prijava_id ON placanja (prijava_id);
+CREATE INDEX IF NOT EXISTS idx_placanja_status ON placanja (status);
+CREATE INDEX IF NOT EXISTS idx_placanja_checkout_id ON placanja (checkout_id);
+CREATE INDEX IF NOT EXISTS idx_placanja_payment_id ON placanja (payment_id);
+CREATE INDEX IF NOT EXISTS idx_placanja_transakcija_id ON placanja (transakcija_id);
+
+CREATE TABLE IF NOT EXISTS placanje_dogadjaji (
+    id BIGSERIAL PRIMARY KEY,
+    provider VARCHAR(20) NOT NULL,
+    event_id VARCHAR(120) NOT NULL,
+    tip VARCHAR(40) NOT NULL DEFAULT '',
+    placanje_id BIGINT,
+    klub_id BIGINT,
+    payload TEXT,
+    greska TEXT,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_placanje_dogadjaji_provider_event ON placanje_dogadjaji (provider, event_id);
+CREATE INDEX IF NOT EXISTS idx_placanje_dogadjaji_placanje_id ON placanje_dogadjaji (placanje_id);
+CREATE INDEX IF NOT EXISTS idx_placanje_dogadjaji_klub_id ON placanje_dogadjaji (klub_id);
+
+ALTER TABLE klubovi ADD COLUMN IF NOT EXISTS clanarina_iznos NUMERIC;
+ALTER TABLE transakcije ADD COLUMN IF NOT EXISTS clanarina_godina INTEGER;
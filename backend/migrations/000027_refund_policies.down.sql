@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS obaveze_povracaja;
+DROP TABLE IF EXISTS akcija_politike_povracaja;
+ALTER TABLE prijave DROP COLUMN IF EXISTS uplaceno_gotovinom;
+ALTER TABLE klubovi DROP COLUMN IF EXISTS politika_povracaja;
@@ -0,0 +1,53 @@
+-- Politike povraćaja pri otkazu (klub i akcija) i obaveze povraćaja za otkazane plaćene prijave.
+
+ALTER TABLE klubovi ADD COLUMN IF NOT EXISTS politika_povracaja TEXT;
+
+-- Iznos gotovinske uplate evidentiran kada blagajnik označi prijavu plaćenom; osnova povraćaja pri otkazu.
+-- Postojeće plaćene prijave ostaju NULL (povraćaj za njih se i dalje računa od salda).
+ALTER TABLE prijave ADD COLUMN IF NOT EXISTS uplaceno_gotovinom NUMERIC;
+
+CREATE TABLE IF NOT EXISTS akcija_politike_povracaja (
+    id BIGSERIAL PRIMARY KEY,
+    akcija_id BIGINT NOT NULL,
+    pravila TEXT,
+    otkaz_kluba_procenat NUMERIC,
+    nepovratni_prevoz TEXT,
+    nepovratni_smestaj TEXT,
+    nepovratna_oprema TEXT,
+    napomena TEXT,
+    izmenio_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_akcija_politike_povracaja_akcija_id ON akcija_politike_povracaja (akcija_id);
+
+CREATE TABLE IF NOT EXISTS obaveze_povracaja (
+    id BIGSERIAL PRIMARY KEY,
+    klub_id BIGINT,
+    akcija_id BIGINT NOT NULL,
+    prijava_id BIGINT NOT NULL,
+    korisnik_id BIGINT NOT NULL,
+    razlog VARCHAR(20) NOT NULL,
+    uplaceno NUMERIC NOT NULL DEFAULT 0,
+    nepovratno NUMERIC NOT NULL DEFAULT 0,
+    procenat NUMERIC NOT NULL DEFAULT 0,
+    iznos NUMERIC NOT NULL DEFAULT 0,
+    uplata_online BOOLEAN NOT NULL DEFAULT FALSE,
+    obracun TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'otvorena',
+    otkazano_at TIMESTAMPTZ,
+    isplaceno_at TIMESTAMPTZ,
+    placanje_id BIGINT,
+    povracaj_cilj NUMERIC NOT NULL DEFAULT 0,
+    transakcija_id BIGINT,
+    zatvorio_id BIGINT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_obaveze_povracaja_klub_id ON obaveze_povracaja (klub_id);
+CREATE INDEX IF NOT EXISTS idx_obaveze_povracaja_akcija_id ON obaveze_povracaja (akcija_id);
+CREATE INDEX IF NOT EXISTS idx_obaveze_povracaja_prijava_id ON obaveze_povracaja (prijava_id);
+CREATE INDEX IF NOT EXISTS idx_obaveze_povracaja_korisnik_id ON obaveze_povracaja (korisnik_id);
+CREATE INDEX IF NOT EXISTS idx_obaveze_povracaja_status ON obaveze_povracaja (status);
+CREATE INDEX IF NOT EXISTS idx_obaveze_povracaja_placanje_id ON obaveze_povracaja (placanje_id);
+CREATE INDEX IF NOT EXISTS idx_obaveze_povracaja_transakcija_id ON obaveze_povracaja (transakcija_id);
@@ -106,8 +106,14 @@ export async function regenerateAkcijaInviteLink(
   return res.data
 }
 
-export async function otkaziPrijavu(client: AxiosInstance, id: number | string): Promise<void> {
-  await client.delete(`/api/akcije/${id}/prijavi`)
+export async function otkaziPrijavu(
+  client: AxiosInstance,
+  id: number | string,
+  options?: { potvrdaBezPovracaja?: boolean },
+): Promise<void> {
+  await client.delete(`/api/akcije/${id}/prijavi`, {
+    params: options?.potvrdaBezPovracaja ? { potvrdaBezPovracaja: true } : undefined,
+  })
 }
 
 export async function cancelSignupRequest(client: AxiosInstance, akcijaId: number | string): Promise<void> {